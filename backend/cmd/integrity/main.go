@@ -0,0 +1,71 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/joho/godotenv"
+	"web-crawler-backend/internal/config"
+	"web-crawler-backend/internal/database"
+	"web-crawler-backend/internal/services"
+)
+
+func main() {
+	// Load environment variables
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using system environment variables")
+	}
+
+	// Parse command line flags
+	var (
+		repair     = flag.Bool("repair", false, "Repair the inconsistencies found instead of only reporting them")
+		stuckAfter = flag.Duration("stuck-after", time.Hour, "How long a crawl may run before it is considered stuck")
+	)
+	flag.Parse()
+
+	// Initialize configuration
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Invalid configuration:\n%v", err)
+	}
+
+	db, err := database.Initialize(cfg.DBDriver, cfg.DatabaseURL)
+	if err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+
+	report, err := services.NewIntegrityService(db).Run(services.IntegrityOptions{
+		Repair:     *repair,
+		StuckAfter: *stuckAfter,
+	})
+	if err != nil {
+		log.Fatal("Integrity check failed:", err)
+	}
+
+	for _, check := range report.Checks {
+		if report.Repair {
+			fmt.Printf("%-22s found %d, repaired %d\n", check.Name+":", check.Found, check.Repaired)
+		} else {
+			fmt.Printf("%-22s found %d\n", check.Name+":", check.Found)
+		}
+		for _, detail := range check.Details {
+			fmt.Printf("  - %s\n", detail)
+		}
+		if check.Found > len(check.Details) {
+			fmt.Printf("  ... and %d more\n", check.Found-len(check.Details))
+		}
+	}
+
+	switch {
+	case report.Issues() == 0:
+		fmt.Println("No inconsistencies found")
+	case report.Repair:
+		fmt.Printf("Repaired %d inconsistencies\n", report.Issues())
+	default:
+		fmt.Printf("Found %d inconsistencies, run with -repair to fix them\n", report.Issues())
+		os.Exit(1)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"time"
 
 	"github.com/joho/godotenv"
 	"web-crawler-backend/internal/config"
@@ -19,31 +20,48 @@ func main() {
 
 	// Parse command line flags
 	var (
-		action = flag.String("action", "up", "Migration action: up, down, version")
-		steps  = flag.Int("steps", 1, "Number of steps for down migration")
+		action  = flag.String("action", "up", "Migration action: up, down, version, status, goto, force, create")
+		steps   = flag.Int("steps", 1, "Number of steps for down migration")
+		version = flag.Int("version", -1, "Target version for goto and force")
+		name    = flag.String("name", "", "Name of the migration to create")
 	)
 	flag.Parse()
 
 	// Initialize configuration
-	cfg := config.Load()
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Invalid configuration:\n%v", err)
+	}
+
+	// Creating migration files doesn't need a database
+	if *action == "create" {
+		paths, err := database.CreateMigration(*name, time.Now())
+		if err != nil {
+			log.Fatal("Failed to create migration:", err)
+		}
+		for _, path := range paths {
+			fmt.Println("Created", path)
+		}
+		return
+	}
 
 	switch *action {
 	case "up":
-		if err := database.RunMigrationsWithFiles(cfg.DatabaseURL); err != nil {
+		if err := database.RunMigrationsWithFiles(cfg.DBDriver, cfg.DatabaseURL); err != nil {
 			log.Fatal("Failed to run migrations up:", err)
 		}
 		fmt.Println("Migrations applied successfully")
 
 	case "down":
 		for i := 0; i < *steps; i++ {
-			if err := database.RollbackMigration(cfg.DatabaseURL); err != nil {
+			if err := database.RollbackMigration(cfg.DBDriver, cfg.DatabaseURL); err != nil {
 				log.Fatal("Failed to rollback migration:", err)
 			}
 		}
 		fmt.Printf("Rolled back %d migration(s) successfully\n", *steps)
 
 	case "version":
-		version, dirty, err := database.GetMigrationVersion(cfg.DatabaseURL)
+		version, dirty, err := database.GetMigrationVersion(cfg.DBDriver, cfg.DatabaseURL)
 		if err != nil {
 			log.Fatal("Failed to get migration version:", err)
 		}
@@ -52,9 +70,54 @@ func main() {
 			fmt.Println("Warning: Migration state is dirty")
 		}
 
+	case "status":
+		migrations, err := database.GetMigrationStatus(cfg.DBDriver, cfg.DatabaseURL)
+		if err != nil {
+			log.Fatal("Failed to get migration status:", err)
+		}
+		for _, migration := range migrations {
+			state := "pending"
+			if migration.Dirty {
+				state = "dirty"
+			} else if migration.Applied {
+				state = "applied"
+			}
+			fmt.Printf("%-8s %06d_%s\n", state, migration.Version, migration.Name)
+		}
+
+	case "goto":
+		if *version < 0 {
+			log.Fatal("goto requires -version")
+		}
+		if err := database.MigrateTo(cfg.DBDriver, cfg.DatabaseURL, uint(*version)); err != nil {
+			log.Fatal("Failed to migrate:", err)
+		}
+		fmt.Printf("Migrated to version %d\n", *version)
+
+	case "force":
+		// -1 is a valid target: it marks the database as having no migrations
+		if !flagSet("version") {
+			log.Fatal("force requires -version")
+		}
+		if err := database.ForceMigrationVersion(cfg.DBDriver, cfg.DatabaseURL, *version); err != nil {
+			log.Fatal("Failed to force migration version:", err)
+		}
+		fmt.Printf("Forced migration version to %d\n", *version)
+
 	default:
 		fmt.Printf("Unknown action: %s\n", *action)
-		fmt.Println("Available actions: up, down, version")
+		fmt.Println("Available actions: up, down, version, status, goto, force, create")
 		os.Exit(1)
 	}
-} 
\ No newline at end of file
+}
+
+// flagSet reports whether a flag was passed on the command line
+func flagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/joho/godotenv"
+	"web-crawler-backend/internal/config"
+	"web-crawler-backend/internal/database"
+	"web-crawler-backend/internal/handlers"
+	"web-crawler-backend/internal/models"
+	"web-crawler-backend/internal/services"
+	"web-crawler-backend/internal/storage"
+)
+
+// The worker runs queued crawls without serving the API, so crawl capacity
+// can be scaled separately from the API replicas. The API enqueues crawls
+// when started with CRAWL_QUEUE=true; with CRAWL_QUEUE_WORKERS=0 it leaves
+// all of them to workers.
+func main() {
+	// Load environment variables
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using system environment variables")
+	}
+
+	// Initialize configuration
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Invalid configuration:\n%v", err)
+	}
+
+	// Parse command line flags
+	workers := flag.Int("workers", cfg.CrawlQueueWorkers, "Number of crawls run at once")
+	flag.Parse()
+
+	if cfg.RedisURL == "" {
+		log.Fatal("The crawl queue requires Redis, set REDIS_URL")
+	}
+	redisClient, err := services.NewRedisClient(cfg.RedisURL)
+	if err != nil {
+		log.Fatal("Failed to connect to Redis:", err)
+	}
+	defer redisClient.Close()
+
+	db, err := database.Initialize(cfg.DBDriver, cfg.DatabaseURL)
+	if err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+
+	// Crawl results must invalidate what the API replicas cached
+	if err := db.Use(services.NewCacheService(redisClient, cfg.CacheTTL).Plugin()); err != nil {
+		log.Fatal("Failed to set up cache invalidation:", err)
+	}
+
+	// Redirects and links of the pages crawled must not reach internal services
+	urlValidator, err := services.NewURLValidator(services.URLValidatorOptions{
+		AllowedHosts:    cfg.CrawlAllowedHosts,
+		AllowedNetworks: cfg.CrawlAllowedNetworks,
+		AllowedPorts:    cfg.CrawlAllowedPorts,
+	})
+	if err != nil {
+		log.Fatal("Invalid crawl destination settings:", err)
+	}
+	services.RestrictCrawlerDestinations(urlValidator)
+
+	crawlerService := services.NewCrawlerServiceWithOptions(db, services.CrawlerOptions{
+		MaxConcurrency:   cfg.CrawlConcurrency,
+		MaxHostQPS:       cfg.CrawlHostQPS,
+		MaxPages:         cfg.CrawlMaxPages,
+		MaxResponseBytes: int64(cfg.CrawlMaxResponseBytes),
+		MaxRetries:       cfg.CrawlMaxRetries,
+		RetryBaseDelay:   cfg.CrawlRetryBaseDelay,
+		RetryMaxDelay:    cfg.CrawlRetryMaxDelay,
+		InsertBatchSize:  cfg.CrawlInsertBatchSize,
+	}).WithQuota(services.NewQuotaService(db, models.QuotaLimits{
+		MaxURLs:         cfg.QuotaMaxURLs,
+		MaxCrawlsPerDay: cfg.QuotaMaxCrawlsPerDay,
+		MaxPages:        cfg.QuotaMaxPages,
+	})).WithLinkCheckCache(services.NewLinkCheckCache(redisClient, cfg.LinkCheckCacheTTL)).
+		WithFeatureFlags(services.NewFeatureFlagService(db, cfg.FeatureFlags))
+	if cfg.CrawlSnapshotDir != "" {
+		snapshots, err := storage.Open(cfg.Storage(), cfg.CrawlSnapshotDir)
+		if err != nil {
+			log.Fatal("Invalid storage settings:", err)
+		}
+		crawlerService = crawlerService.WithSnapshots(snapshots)
+	}
+	crawlQueue := services.NewCrawlQueue(redisClient, services.CrawlQueueOptions{MaxRetries: cfg.CrawlQueueMaxRetries})
+
+	stop := crawlQueue.Start(crawlerService, *workers)
+	log.Printf("Crawl worker started with %d workers", *workers)
+
+	// Serve liveness and readiness checks for the orchestrator
+	var healthServer *http.Server
+	if cfg.WorkerHealthPort != "" {
+		healthService := services.NewHealthService(db, database.MigrationsDir(cfg.DBDriver))
+		healthService.AddWorker("crawl_queue", crawlQueue.Heartbeat())
+		healthServer = newHealthServer(cfg.WorkerHealthPort, handlers.NewHealthHandler(healthService))
+		go func() {
+			if err := healthServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Fatal("Failed to serve health checks:", err)
+			}
+		}()
+	}
+
+	// Finish running crawls on shutdown
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	<-signals
+	log.Println("Shutting down, waiting for running crawls")
+	stop()
+
+	if healthServer != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		healthServer.Shutdown(ctx)
+	}
+}
+
+// newHealthServer serves /healthz and /readyz like the API does
+func newHealthServer(port string, healthHandler *handlers.HealthHandler) *http.Server {
+	gin.SetMode(gin.ReleaseMode)
+	router := gin.New()
+	router.Use(gin.Recovery())
+	router.GET("/healthz", healthHandler.Liveness)
+	router.GET("/readyz", healthHandler.Readiness)
+	return &http.Server{Addr: ":" + port, Handler: router}
+}
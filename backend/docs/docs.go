@@ -0,0 +1,6115 @@
+// Package docs Code generated by swaggo/swag. DO NOT EDIT
+package docs
+
+import "github.com/swaggo/swag"
+
+const docTemplate = `{
+    "schemes": {{ marshal .Schemes }},
+    "swagger": "2.0",
+    "info": {
+        "description": "{{escape .Description}}",
+        "title": "{{.Title}}",
+        "contact": {},
+        "version": "{{.Version}}"
+    },
+    "host": "{{.Host}}",
+    "basePath": "{{.BasePath}}",
+    "paths": {
+        "/admin/auth-incidents": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists the clients that sent many invalid tokens to token refresh or validation, newest first, and until when they were blocked. Admins only.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Security audit log",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size, at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Number of incidents to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/crawls/{id}/cancel": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Marks a running crawl and its URL as failed, e.g. when the crawl is stuck. A crawl that finishes after all overwrites this with its result. Admins only.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Cancel a running crawl",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Crawl ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/crawls/{id}/requeue": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Starts a new crawl of the URL of a crawl, e.g. one that failed. Admins only.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Crawl the URL of a crawl again",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Crawl ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/flags": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists every feature flag with its default, stored rollout and environment override. Admins only.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "features"
+                ],
+                "summary": "List the feature flags",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.FeatureFlagState"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/flags/{name}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Turns a feature on for everyone or for the listed users. An override in FEATURE_FLAGS still wins. Admins only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "features"
+                ],
+                "summary": "Roll a feature out",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Flag name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Rollout",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.FeatureFlagRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.FeatureFlagState"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "features"
+                ],
+                "summary": "Reset a feature flag to its default",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Flag name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/metrics": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Counts the calls, client and server errors and latency of every route and user since this server process started. Each replica reports its own requests. Admins only.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "API usage per route and user",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.RequestMetricsReport"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/status": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Reports the crawl queue with its worker utilization, the running crawls with their durations and the newest failed crawls. The queue is left out when crawls run in the API process. Admins only.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Inspect the crawling system",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SystemStatus"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/csrf": {
+            "get": {
+                "description": "Returns a CSRF token and sets it in the csrf_token cookie. Requests that change something and authenticate with the auth cookie must echo it in the X-CSRF-Token header.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Get a CSRF token",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/login": {
+            "post": {
+                "description": "Authenticate user and return JWT token. With cookie auth enabled the token is also set in an HttpOnly cookie.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Login user",
+                "parameters": [
+                    {
+                        "description": "Login credentials",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.AuthResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/logout": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Logout user by revoking the session of the token, so it can't be used again, and clear the auth cookie",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Logout user",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/profile": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get current authenticated user's profile",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Get user profile",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/refresh": {
+            "post": {
+                "description": "Generate a new JWT token using existing valid token, taken from the body or else the Authorization header or auth cookie",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Refresh JWT token",
+                "parameters": [
+                    {
+                        "description": "Current token",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.RefreshTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.AuthResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/register": {
+            "post": {
+                "description": "Create a new user account",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Register a new user",
+                "parameters": [
+                    {
+                        "description": "Registration data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RegisterRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List the devices the current user is logged in on, most recently used first. The session of the request is marked current.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "List sessions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/sessions/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Log the current user out on one device; tokens of the session are rejected from then on",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Revoke a session",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Session ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/validate": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Check if the current JWT token is valid",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Validate token",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/broken-link-threshold": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the threshold applying to the URLs of the organization of the request, or outside organizations to the URLs the user added, unless a URL has its own",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "alerts"
+                ],
+                "summary": "Get the default broken link threshold",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.BrokenLinkThreshold"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Sets the threshold of the URLs of the organization, which needs its admin role, or outside organizations of the URLs the user added. URLs with their own threshold keep it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "alerts"
+                ],
+                "summary": "Set the default broken link threshold",
+                "parameters": [
+                    {
+                        "description": "Limits",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.BrokenLinkThresholdRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.BrokenLinkThreshold"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "alerts"
+                ],
+                "summary": "Remove the default broken link threshold",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/compare": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Crawls both URLs of the pair and compares the results like GET /environments/{id}/comparison, e.g. as a check before a release. Both crawls count against the daily quota. If they don't finish before the request times out, the latest completed crawls are compared, the answer is 202 with complete set to false, and the comparison can be fetched again once the crawls finish.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "environments"
+                ],
+                "summary": "Crawl and compare an environment pair",
+                "parameters": [
+                    {
+                        "description": "Environment pair",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CompareRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.EnvironmentComparison"
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/models.EnvironmentComparison"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/crawl/bulk-rerun": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Reruns the crawls with low priority unless the request asks for high priority.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "crawl"
+                ],
+                "summary": "Rerun the crawls of several URLs",
+                "parameters": [
+                    {
+                        "description": "URL IDs and priority",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.BulkRerunRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the response of an earlier request with the same key",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/crawl/status/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "crawl"
+                ],
+                "summary": "Get the crawl status of a URL",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "URL ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CrawlStatusResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/crawl/{id}": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Starts a crawl with high priority unless the optional body asks for low priority.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "crawl"
+                ],
+                "summary": "Start a crawl",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "URL ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Crawl priority",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.StartCrawlRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/crawls/{id}/events": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the steps of a crawl in order, e.g. requests, retries, parsing, queued and slow link checks, to explain its results.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "crawl"
+                ],
+                "summary": "List the events of a crawl",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Crawl ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.CrawlEvent"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/crawls/{id}/reprocess": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Extracts the links, images, issues and scores of a completed crawl again from its stored HTML, without fetching the page. Link and image checks are taken from the crawl's previous results.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "crawl"
+                ],
+                "summary": "Reprocess a crawl from its snapshot",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Crawl ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/environments": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists the URL pairs of deployments to compare, e.g. production and staging, with their URLs",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "environments"
+                ],
+                "summary": "List environment pairs",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.EnvironmentPair"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Links the URL of a page on a reference deployment, e.g. production, with its URL on another deployment, e.g. staging, so POST /compare can compare them",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "environments"
+                ],
+                "summary": "Link two URLs as an environment pair",
+                "parameters": [
+                    {
+                        "description": "Pair name and URLs",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateEnvironmentPairRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.EnvironmentPair"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/environments/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Deletes the pair; its URLs and their crawls are kept",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "environments"
+                ],
+                "summary": "Unlink an environment pair",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Environment pair ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/environments/{id}/comparison": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Compares the latest completed crawls of both URLs without crawling them again: titles, heading counts, links only one deployment has, and links whose checks got different HTTP statuses. Links to a URL's own host are compared by path.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "environments"
+                ],
+                "summary": "Compare the latest crawls of an environment pair",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Environment pair ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.EnvironmentComparison"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/features": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "features"
+                ],
+                "summary": "List the features available to the current user",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/integrations/google": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Tells whether Google Sheets exports are enabled on the server and whether the user connected a Google account",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sheet-exports"
+                ],
+                "summary": "Get the Google account connection",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.GoogleConnectionStatus"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Forgets the user's Google authorization. Their exports fail until they connect again.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sheet-exports"
+                ],
+                "summary": "Disconnect the Google account",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/integrations/google/authorize": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the URL of Google's consent page. After the user grants access to their spreadsheets, Google redirects to the callback, which stores the authorization. The URL is valid for 10 minutes, and only in the browser that requested it: the response sets a cookie the callback checks.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sheet-exports"
+                ],
+                "summary": "Start connecting a Google account",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "501": {
+                        "description": "Not Implemented",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/integrations/google/callback": {
+            "get": {
+                "description": "Google redirects here from its consent page. The state identifies the user who started the authorization, and must match the cookie set when it started.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sheet-exports"
+                ],
+                "summary": "Complete connecting a Google account",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "State from the authorization URL",
+                        "name": "state",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authorization code",
+                        "name": "code",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Error, when the user denied access",
+                        "name": "error",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/orgs": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists the organizations of the current user with their role in each",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "List organizations",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.OrganizationWithRole"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates an organization with the current user as its owner. Send its ID in the X-Organization-ID header to work on its URLs.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "Create an organization",
+                "parameters": [
+                    {
+                        "description": "Organization name",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateOrganizationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.OrganizationWithRole"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/orgs/{org_id}/members": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "List the members of an organization",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Organization ID",
+                        "name": "org_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Membership"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Adds a user by username. Admins may add members; only owners may add owners.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "Add a member to an organization",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Organization ID",
+                        "name": "org_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "User and role",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.AddMemberRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Membership"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/orgs/{org_id}/members/{user_id}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Admins may change the roles of non-owners; only owners may change owners or make others owner. The last owner can't be demoted.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "Change the role of a member",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Organization ID",
+                        "name": "org_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New role",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateMemberRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Membership"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Members may leave on their own; admins may remove non-owners and owners anyone. The last owner can't leave.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "Remove a member from an organization",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Organization ID",
+                        "name": "org_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/public/reports/{token}": {
+            "get": {
+                "description": "Shows the crawl results a share link points to. Needs no authentication.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "public"
+                ],
+                "summary": "Show a shared report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Share token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SharedReport"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "410": {
+                        "description": "Gone",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/queue": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Counts pending, scheduled, running and dead crawl tasks and the workers of all worker processes, and lists the oldest pending, the next scheduled and the newest dead tasks. Admins only.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "queue"
+                ],
+                "summary": "Inspect the crawl queue",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CrawlQueueStats"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/queue/dead": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "queue"
+                ],
+                "summary": "Delete all dead crawl tasks",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/queue/dead/{task_id}/retry": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Moves a task out of the dead letter list and back into the queue with its retries reset. Admins only.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "queue"
+                ],
+                "summary": "Retry a dead crawl task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "task_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/queue/scheduled/{task_id}/requeue": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Moves a task waiting for its retry back into the queue right away. Admins only.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "queue"
+                ],
+                "summary": "Requeue a scheduled crawl task now",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "task_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/queue/tasks/{task_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Removes a pending, scheduled or dead task from the queue. Tasks a worker is running can't be cancelled. Admins only.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "queue"
+                ],
+                "summary": "Cancel a crawl task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "task_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/quota": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the plan and limits of the current user with how much of them is used. Zero limits are unlimited.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "quota"
+                ],
+                "summary": "Show quota usage",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.QuotaUsage"
+                        }
+                    }
+                }
+            }
+        },
+        "/quota/users/{user_id}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Sets the plan of a user with limits overriding the default ones; omitted limits use the default. Admins only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "quota"
+                ],
+                "summary": "Put a user on a plan",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Plan and limits",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UserQuotaRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserQuota"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "quota"
+                ],
+                "summary": "Put a user back on the default plan",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sheet-exports": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists the exports, newest first, with the time, row count and error of their last run",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sheet-exports"
+                ],
+                "summary": "List Google Sheets exports",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.SheetExport"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Writes the URL list (kind urls) or the broken link report (kind broken_links) of the organization's URLs into a spreadsheet, replacing its first sheet. Without a spreadsheet_id a spreadsheet is created in the user's Google Drive. With interval_minutes, at least 60, the export is written again periodically with the user's Google account.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sheet-exports"
+                ],
+                "summary": "Export to Google Sheets",
+                "parameters": [
+                    {
+                        "description": "Export",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateSheetExportRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.SheetExport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sheet-exports/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Stops the export; the spreadsheet is kept",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sheet-exports"
+                ],
+                "summary": "Delete a Google Sheets export",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Export ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sheet-exports/{id}/run": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Writes the export again without waiting for its interval",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sheet-exports"
+                ],
+                "summary": "Run a Google Sheets export now",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Export ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SheetExport"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sitemaps": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists the imported sitemaps, newest first, with the time and error of their last sync",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sitemaps"
+                ],
+                "summary": "List imported sitemaps",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Sitemap"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Fetches an XML sitemap, or the sitemaps of a sitemap index, and adds and crawls the pages it lists as URLs. Pages over the URL or crawl quota are skipped. With a sync interval the sitemap is fetched again periodically: new pages are added, pages no longer listed are flagged, and both are reported in the activity feed as sitemap.drift.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sitemaps"
+                ],
+                "summary": "Import a sitemap",
+                "parameters": [
+                    {
+                        "description": "Sitemap URL and sync interval",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ImportSitemapRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sitemaps/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Stops syncing the sitemap; the URLs it added are kept",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sitemaps"
+                ],
+                "summary": "Delete a sitemap",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Sitemap ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sitemaps/{id}/entries": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists the pages imported from a sitemap with their URL IDs. Pages a sync found no longer listed have removed_at set.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sitemaps"
+                ],
+                "summary": "List the pages of a sitemap",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Sitemap ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only pages no longer listed",
+                        "name": "removed",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to return (default: 20, max: 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to skip (default: 0)",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.SitemapEntry"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sitemaps/{id}/sync": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Fetches the sitemap again without waiting for its sync interval, adding new pages and flagging removed ones",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sitemaps"
+                ],
+                "summary": "Sync a sitemap now",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Sitemap ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SitemapSyncResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/tokens": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists the active tokens the current user created, newest first, or the tokens of an organization for its admins",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tokens"
+                ],
+                "summary": "List API tokens",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Organization ID",
+                        "name": "organization_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.APIToken"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates a token for scripts and integrations, sent as a Bearer token like the JWT of a login. The read scope only reads, crawl also starts and reprocesses crawls, and admin may do everything the user may. Tokens of an organization only work on its URLs and need an admin of it to create them. The token is only returned here.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tokens"
+                ],
+                "summary": "Create an API token",
+                "parameters": [
+                    {
+                        "description": "Token name, scope and organization",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateAPITokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.CreatedAPIToken"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/tokens/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Revokes a token the current user created, or a token of an organization they administer. Requests with it are rejected from then on.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tokens"
+                ],
+                "summary": "Revoke an API token",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Token ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/urls": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List URLs with filters and offset or cursor pagination",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "urls"
+                ],
+                "summary": "List URLs",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size (max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Rows to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from next_cursor; pass it empty to start cursor pagination",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search in URL and title",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Crawl status, or several separated by commas, e.g. error,running",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated conditions, e.g. status:error,broken_links\u003e10,created_after:2024-01-01",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Minimum broken links of the latest crawl",
+                        "name": "broken_links_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Whether the latest crawl found a login form",
+                        "name": "has_login_form",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Whether the latest completed crawl crossed the broken link threshold",
+                        "name": "needs_attention",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "HTML version, e.g. HTML5",
+                        "name": "html_version",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Minimum duration of the latest crawl in milliseconds",
+                        "name": "duration_min_ms",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum duration of the latest crawl in milliseconds",
+                        "name": "duration_max_ms",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum words on the root page of the latest crawl, to find thin content",
+                        "name": "word_count_max",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Minimum Flesch reading ease of the latest crawl",
+                        "name": "readability_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "url, title, status, html_version, created_at or updated_at, or several separated by commas, each optionally followed by asc or desc, e.g. status asc,updated_at desc",
+                        "name": "sortBy",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc or desc, for sort columns without a direction",
+                        "name": "sortOrder",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated fields to return, e.g. url,status,broken_links; id is always included",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Add a URL and start crawling it",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "urls"
+                ],
+                "summary": "Add a URL",
+                "parameters": [
+                    {
+                        "description": "URL to crawl",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CrawlRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the response of an earlier request with the same key",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/urls/bulk-delete": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "urls"
+                ],
+                "summary": "Delete several URLs",
+                "parameters": [
+                    {
+                        "description": "URL IDs",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.BulkRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the response of an earlier request with the same key",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/urls/trash": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "urls"
+                ],
+                "summary": "List deleted URLs",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size, at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Number of URLs to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/urls/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get a URL with its crawls and broken links",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "urls"
+                ],
+                "summary": "Get a URL",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "URL ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated fields to return, e.g. url,status,crawls; id is always included",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "urls"
+                ],
+                "summary": "Delete a URL",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "URL ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/urls/{id}/broken-link-threshold": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the URL's own threshold; without one, the default of its project applies",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "alerts"
+                ],
+                "summary": "Get the broken link threshold of a URL",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "URL ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.BrokenLinkThreshold"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "When a crawl of the URL finds more broken links than max_broken_links, or a larger percentage of its links broken than max_broken_percent, the URL is flagged with needs_attention and its owner is notified in the activity feed as links.threshold_exceeded. The flag clears when a later crawl is within the threshold.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "alerts"
+                ],
+                "summary": "Set the broken link threshold of a URL",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "URL ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Limits",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.BrokenLinkThresholdRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.BrokenLinkThreshold"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "The default of the URL's project applies again from its next crawl",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "alerts"
+                ],
+                "summary": "Remove the broken link threshold of a URL",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "URL ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/urls/{id}/issues": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists the GitHub and Jira issues filed for the findings of the URL, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "issues"
+                ],
+                "summary": "List the issues filed for a URL",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "URL ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ExternalIssue"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Opens a GitHub or Jira issue describing a broken link (keyed by the link URL) or failed SEO check (keyed by the check key) of the latest crawl of the URL. The link to the issue is shown as external_issue on the finding, in later crawls too. A finding gets one issue: filing it again answers 200 with the existing one.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "issues"
+                ],
+                "summary": "File an issue for a finding",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "URL ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Tracker and finding",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateExternalIssueRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ExternalIssue"
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.ExternalIssue"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/urls/{id}/links": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "urls"
+                ],
+                "summary": "List the links of a URL",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "URL ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "all, internal, external, mailto, tel, anchor, other, broken, accessible, nofollow, sponsored, ugc, new_tab, nav, footer or content",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Rows to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from next_cursor; pass it empty to start cursor pagination",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated fields to return, e.g. link_url,status_code; id is always included",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/urls/{id}/purge": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "urls"
+                ],
+                "summary": "Permanently delete a URL from the trash",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "URL ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/urls/{id}/recheck-links": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Requests the broken links of the URL's latest completed crawl again, without crawling the page, and updates their status and the crawl's broken link count. Useful to verify fixes; scores are updated by the next crawl or reprocess.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "crawl"
+                ],
+                "summary": "Recheck the broken links of a URL",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "URL ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.LinkRecheckResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/urls/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "urls"
+                ],
+                "summary": "Restore a deleted URL",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "URL ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/urls/{id}/share": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates a signed link that shows the crawl results of the URL without authentication until it expires. Links can't be revoked; deleting the URL disables them.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "urls"
+                ],
+                "summary": "Share the crawl results of a URL",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "URL ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Link lifetime",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.ShareRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.ShareLink"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/urls/{id}/trends": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the broken, internal and external link counts, pages and duration of the latest completed crawls, oldest first, for plotting over time",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "urls"
+                ],
+                "summary": "Show quality trends of a URL",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "URL ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 30,
+                        "description": "Number of crawls, at most 200",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CrawlTrends"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "models.APIScope": {
+            "type": "string",
+            "enum": [
+                "read",
+                "crawl",
+                "admin"
+            ],
+            "x-enum-varnames": [
+                "APIScopeRead",
+                "APIScopeCrawl",
+                "APIScopeAdmin"
+            ]
+        },
+        "models.APIToken": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "organization_id": {
+                    "description": "Organization the token is limited to, 0 for none",
+                    "type": "integer"
+                },
+                "prefix": {
+                    "description": "Prefix is the start of the token, to recognize it by",
+                    "type": "string"
+                },
+                "scope": {
+                    "$ref": "#/definitions/models.APIScope"
+                },
+                "user_id": {
+                    "description": "User the token acts as",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.AddMemberRequest": {
+            "type": "object",
+            "required": [
+                "role",
+                "username"
+            ],
+            "properties": {
+                "role": {
+                    "enum": [
+                        "owner",
+                        "admin",
+                        "member",
+                        "viewer"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.OrgRole"
+                        }
+                    ]
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AuthResponse": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/models.User"
+                }
+            }
+        },
+        "models.BrokenLinkThreshold": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "max_broken_links": {
+                    "description": "A crawl crosses the threshold with more broken links than MaxBrokenLinks,\nor a larger percentage of broken links among the internal and external\nlinks it found than MaxBrokenPercent; nil limits are not checked",
+                    "type": "integer"
+                },
+                "max_broken_percent": {
+                    "type": "number"
+                },
+                "organization_id": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url_id": {
+                    "description": "0 for project defaults",
+                    "type": "integer"
+                },
+                "user_id": {
+                    "description": "Owner of a personal project default, 0 otherwise",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.BrokenLinkThresholdRequest": {
+            "type": "object",
+            "properties": {
+                "max_broken_links": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 5
+                },
+                "max_broken_percent": {
+                    "type": "number",
+                    "maximum": 100,
+                    "example": 10
+                }
+            }
+        },
+        "models.BulkRequest": {
+            "type": "object",
+            "required": [
+                "ids"
+            ],
+            "properties": {
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "models.BulkRerunRequest": {
+            "type": "object",
+            "required": [
+                "ids"
+            ],
+            "properties": {
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "priority": {
+                    "description": "Defaults to low",
+                    "enum": [
+                        "high",
+                        "low"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.CrawlPriority"
+                        }
+                    ]
+                }
+            }
+        },
+        "models.CompareRequest": {
+            "type": "object",
+            "required": [
+                "pair_id"
+            ],
+            "properties": {
+                "pair_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Crawl": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "description": "Requests made for the seed page, including retries of transient failures",
+                    "type": "integer"
+                },
+                "broken_links": {
+                    "type": "integer"
+                },
+                "completed_at": {
+                    "type": "string"
+                },
+                "connection_reused": {
+                    "description": "The request went over a connection opened for an earlier one",
+                    "type": "boolean"
+                },
+                "content_encoding": {
+                    "description": "e.g. gzip, br, empty if uncompressed",
+                    "type": "string"
+                },
+                "content_hash": {
+                    "description": "SHA-256 of the normalized page text",
+                    "type": "string"
+                },
+                "crawl_log": {
+                    "description": "Newline separated notes, e.g. applied rate limits",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "download_ms": {
+                    "description": "Time until the whole body was read",
+                    "type": "integer"
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "etag": {
+                    "description": "Validators of the seed page, sent on the next crawl to skip an unchanged page",
+                    "type": "string"
+                },
+                "external_links": {
+                    "type": "integer"
+                },
+                "extracted_data": {
+                    "description": "JSON object of custom extractor results by extractor name",
+                    "type": "string"
+                },
+                "heading_counts": {
+                    "description": "JSON string: {\"h1\":1,\"h2\":3,...}",
+                    "type": "string"
+                },
+                "http3_advertised": {
+                    "description": "HTTP/3 runs over QUIC, which the crawler doesn't speak; servers offer it through Alt-Svc",
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "internal_links": {
+                    "type": "integer"
+                },
+                "keep_alive": {
+                    "description": "The server left the connection open for further requests",
+                    "type": "boolean"
+                },
+                "keyword_checks": {
+                    "description": "JSON array of KeywordCheck",
+                    "type": "string"
+                },
+                "last_modified": {
+                    "type": "string"
+                },
+                "links": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Link"
+                    }
+                },
+                "login_form_confidence": {
+                    "description": "0-1, how sure the detection is that the page has a login form",
+                    "type": "number"
+                },
+                "login_form_detected": {
+                    "type": "boolean"
+                },
+                "login_form_evidence": {
+                    "description": "JSON array: [\"password input\",\"submit text \\\"Sign in\\\"\"]",
+                    "type": "string"
+                },
+                "page_meta": {
+                    "$ref": "#/definitions/models.PageMeta"
+                },
+                "pages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Page"
+                    }
+                },
+                "pages_crawled": {
+                    "type": "integer"
+                },
+                "protocol": {
+                    "description": "e.g. HTTP/1.1, HTTP/2.0",
+                    "type": "string"
+                },
+                "readability_score": {
+                    "description": "Flesch reading ease of the root page, nil without text",
+                    "type": "number"
+                },
+                "reprocessed_at": {
+                    "description": "When the results were last extracted again from the snapshot",
+                    "type": "string"
+                },
+                "response_bytes": {
+                    "description": "Size of the decoded body",
+                    "type": "integer"
+                },
+                "security_checks": {
+                    "description": "JSON array of SecurityCheck",
+                    "type": "string"
+                },
+                "security_headers": {
+                    "description": "JSON object of the security headers the page was served with",
+                    "type": "string"
+                },
+                "security_score": {
+                    "type": "integer"
+                },
+                "seo_checks": {
+                    "description": "JSON array of SEOCheck",
+                    "type": "string"
+                },
+                "seo_score": {
+                    "type": "integer"
+                },
+                "skip_reason": {
+                    "description": "Why the page was not parsed, e.g. a PDF or an oversized response",
+                    "type": "string"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "queued, running, completed, unchanged, skipped, error; unchanged crawls keep the results of the latest completed one",
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "ttfb_ms": {
+                    "description": "Time to first byte of the response",
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "description": "Relationships",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.URL"
+                        }
+                    ]
+                },
+                "url_id": {
+                    "type": "integer"
+                },
+                "word_count": {
+                    "description": "Words of the root page's visible text",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CrawlEvent": {
+            "type": "object",
+            "properties": {
+                "crawl_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "elapsed_ms": {
+                    "description": "Time since the crawl started",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CrawlPriority": {
+            "type": "string",
+            "enum": [
+                "high",
+                "low"
+            ],
+            "x-enum-varnames": [
+                "CrawlPriorityHigh",
+                "CrawlPriorityLow"
+            ]
+        },
+        "models.CrawlQueueStats": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "description": "Running on a worker",
+                    "type": "integer"
+                },
+                "dead": {
+                    "description": "Out of retries",
+                    "type": "integer"
+                },
+                "dead_tasks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CrawlTask"
+                    }
+                },
+                "pending": {
+                    "description": "Waiting for a worker",
+                    "type": "integer"
+                },
+                "pending_high": {
+                    "description": "Pending with high priority",
+                    "type": "integer"
+                },
+                "pending_low": {
+                    "description": "Pending with low priority",
+                    "type": "integer"
+                },
+                "pending_tasks": {
+                    "description": "Oldest first, high priority first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CrawlTask"
+                    }
+                },
+                "scheduled": {
+                    "description": "Waiting to be retried",
+                    "type": "integer"
+                },
+                "scheduled_tasks": {
+                    "description": "Next retry first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CrawlTask"
+                    }
+                },
+                "utilization": {
+                    "description": "Share of workers running a crawl, from 0 to 1",
+                    "type": "number"
+                },
+                "workers": {
+                    "description": "Workers of all running worker processes",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CrawlRequest": {
+            "type": "object",
+            "required": [
+                "url"
+            ],
+            "properties": {
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CrawlStatusResponse": {
+            "type": "object",
+            "properties": {
+                "broken_links": {
+                    "type": "integer"
+                },
+                "completed_at": {
+                    "type": "string"
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "external_links": {
+                    "type": "integer"
+                },
+                "fields": {
+                    "description": "Values of the URL's extraction rules",
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "heading_counts": {
+                    "$ref": "#/definitions/models.HeadingCounts"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "internal_links": {
+                    "type": "integer"
+                },
+                "keywords": {
+                    "description": "Presence of the URL's keywords",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.KeywordCheck"
+                    }
+                },
+                "login_form": {
+                    "$ref": "#/definitions/models.LoginFormResult"
+                },
+                "performance": {
+                    "$ref": "#/definitions/models.ResponseMetrics"
+                },
+                "performance_trend": {
+                    "description": "Completed crawls, oldest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PerformanceSample"
+                    }
+                },
+                "readability_score": {
+                    "description": "Flesch reading ease, higher is easier to read",
+                    "type": "number"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "word_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CrawlTask": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "description": "Failed runs so far",
+                    "type": "integer"
+                },
+                "enqueued_at": {
+                    "type": "string"
+                },
+                "failed_at": {
+                    "description": "Time of the last failed run",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "priority": {
+                    "$ref": "#/definitions/models.CrawlPriority"
+                },
+                "retry_at": {
+                    "description": "When a scheduled task is queued again",
+                    "type": "string"
+                },
+                "url_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CrawlTrendPoint": {
+            "type": "object",
+            "properties": {
+                "broken_links": {
+                    "type": "integer"
+                },
+                "crawl_id": {
+                    "type": "integer"
+                },
+                "crawled_at": {
+                    "type": "string"
+                },
+                "duration_ms": {
+                    "description": "From start to completion, 0 if either is unknown",
+                    "type": "integer"
+                },
+                "external_links": {
+                    "type": "integer"
+                },
+                "internal_links": {
+                    "type": "integer"
+                },
+                "pages_crawled": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CrawlTrends": {
+            "type": "object",
+            "properties": {
+                "points": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CrawlTrendPoint"
+                    }
+                },
+                "url_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CreateAPITokenRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "scope"
+            ],
+            "properties": {
+                "expires_in_days": {
+                    "description": "ExpiresInDays is how long the token works; 0 never expires it",
+                    "type": "integer",
+                    "maximum": 3650,
+                    "minimum": 0
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "organization_id": {
+                    "type": "integer"
+                },
+                "scope": {
+                    "enum": [
+                        "read",
+                        "crawl",
+                        "admin"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.APIScope"
+                        }
+                    ]
+                }
+            }
+        },
+        "models.CreateEnvironmentPairRequest": {
+            "type": "object",
+            "required": [
+                "baseline_url_id",
+                "candidate_url_id",
+                "name"
+            ],
+            "properties": {
+                "baseline_url_id": {
+                    "type": "integer"
+                },
+                "candidate_url_id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "models.CreateExternalIssueRequest": {
+            "type": "object",
+            "required": [
+                "finding_key",
+                "finding_type",
+                "tracker"
+            ],
+            "properties": {
+                "finding_key": {
+                    "type": "string"
+                },
+                "finding_type": {
+                    "description": "broken_link or seo_check",
+                    "type": "string"
+                },
+                "tracker": {
+                    "description": "github or jira",
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateOrganizationRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 191
+                }
+            }
+        },
+        "models.CreateSheetExportRequest": {
+            "type": "object",
+            "required": [
+                "kind"
+            ],
+            "properties": {
+                "interval_minutes": {
+                    "description": "0 only exports on demand",
+                    "type": "integer",
+                    "example": 1440
+                },
+                "kind": {
+                    "description": "urls or broken_links",
+                    "type": "string",
+                    "example": "broken_links"
+                },
+                "spreadsheet_id": {
+                    "description": "SpreadsheetID is the ID in the spreadsheet's address; empty creates a new spreadsheet",
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreatedAPIToken": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "organization_id": {
+                    "description": "Organization the token is limited to, 0 for none",
+                    "type": "integer"
+                },
+                "prefix": {
+                    "description": "Prefix is the start of the token, to recognize it by",
+                    "type": "string"
+                },
+                "scope": {
+                    "$ref": "#/definitions/models.APIScope"
+                },
+                "token": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "description": "User the token acts as",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.EnvironmentComparison": {
+            "type": "object",
+            "properties": {
+                "baseline": {
+                    "$ref": "#/definitions/models.EnvironmentCrawl"
+                },
+                "candidate": {
+                    "$ref": "#/definitions/models.EnvironmentCrawl"
+                },
+                "complete": {
+                    "description": "Complete reports whether both crawls finished before the comparison was made",
+                    "type": "boolean"
+                },
+                "heading_changes": {
+                    "description": "Count delta per heading level, from baseline to candidate",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "identical": {
+                    "description": "Identical reports whether no difference was found",
+                    "type": "boolean"
+                },
+                "links_only_in_baseline": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "links_only_in_candidate": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "pair_id": {
+                    "type": "integer"
+                },
+                "status_changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.LinkStatusDifference"
+                    }
+                },
+                "title_changed": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.EnvironmentCrawl": {
+            "type": "object",
+            "properties": {
+                "broken_links": {
+                    "type": "integer"
+                },
+                "crawl_id": {
+                    "description": "CrawlID is the latest completed crawl, which is compared; 0 if there is none",
+                    "type": "integer"
+                },
+                "heading_counts": {
+                    "$ref": "#/definitions/models.HeadingCounts"
+                },
+                "status": {
+                    "description": "Status is the status of the latest crawl, e.g. running while a new\ncrawl hasn't finished, or error if it failed",
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "url_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.EnvironmentPair": {
+            "type": "object",
+            "properties": {
+                "baseline_url": {
+                    "$ref": "#/definitions/models.URL"
+                },
+                "baseline_url_id": {
+                    "description": "BaselineURLID is the reference deployment, e.g. production, and\nCandidateURLID the one checked against it, e.g. staging",
+                    "type": "integer"
+                },
+                "candidate_url": {
+                    "$ref": "#/definitions/models.URL"
+                },
+                "candidate_url_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "organization_id": {
+                    "description": "Organization sharing the pair, 0 for none",
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ExternalIssue": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "integer"
+                },
+                "finding_key": {
+                    "type": "string"
+                },
+                "finding_type": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "issue_key": {
+                    "type": "string"
+                },
+                "issue_url": {
+                    "type": "string"
+                },
+                "tracker": {
+                    "description": "github, jira",
+                    "type": "string"
+                },
+                "url_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ExtractionRule": {
+            "type": "object",
+            "properties": {
+                "attribute": {
+                    "description": "Read this attribute of the matches instead of their text",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "multiple": {
+                    "description": "Keep every match as a list instead of the first one",
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "selector": {
+                    "type": "string"
+                },
+                "type": {
+                    "description": "css, xpath",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.FailedCrawl": {
+            "type": "object",
+            "properties": {
+                "completed_at": {
+                    "type": "string"
+                },
+                "crawl_id": {
+                    "type": "integer"
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "url_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.FeatureFlagRequest": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "user_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "models.FeatureFlagState": {
+            "type": "object",
+            "properties": {
+                "default": {
+                    "description": "Used while the flag has no stored setting",
+                    "type": "boolean"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "enabled": {
+                    "description": "On for every user",
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "override": {
+                    "description": "Override is set by the FEATURE_FLAGS environment variable and wins over\nthe stored setting and the default",
+                    "type": "boolean"
+                },
+                "user_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "models.FindingAnnotation": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "integer"
+                },
+                "finding_key": {
+                    "type": "string"
+                },
+                "finding_type": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "acknowledged, false_positive, accepted, ignored",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.GoogleConnectionStatus": {
+            "type": "object",
+            "properties": {
+                "configured": {
+                    "description": "The deployment has a Google OAuth client",
+                    "type": "boolean"
+                },
+                "connected": {
+                    "description": "The user authorized their Google account",
+                    "type": "boolean"
+                },
+                "connected_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.HeadingCounts": {
+            "type": "object",
+            "properties": {
+                "h1": {
+                    "type": "integer"
+                },
+                "h2": {
+                    "type": "integer"
+                },
+                "h3": {
+                    "type": "integer"
+                },
+                "h4": {
+                    "type": "integer"
+                },
+                "h5": {
+                    "type": "integer"
+                },
+                "h6": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ImportSitemapRequest": {
+            "type": "object",
+            "required": [
+                "url"
+            ],
+            "properties": {
+                "sync_interval_minutes": {
+                    "description": "0 imports the sitemap once",
+                    "type": "integer",
+                    "example": 1440
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://example.com/sitemap.xml"
+                }
+            }
+        },
+        "models.KeywordCheck": {
+            "type": "object",
+            "properties": {
+                "changed": {
+                    "description": "Changed is set when the status or the number of occurrences differs\nfrom the previous crawl",
+                    "type": "boolean"
+                },
+                "keyword": {
+                    "type": "string"
+                },
+                "occurrences": {
+                    "type": "integer"
+                },
+                "previous_occurrences": {
+                    "description": "nil when the keyword wasn't checked before",
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "present, missing",
+                    "type": "string"
+                }
+            }
+        },
+        "models.Link": {
+            "type": "object",
+            "properties": {
+                "annotation": {
+                    "description": "Set when a broken link has been acknowledged, marked a false positive, accepted or ignored",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.FindingAnnotation"
+                        }
+                    ]
+                },
+                "context": {
+                    "description": "nav, footer, or content",
+                    "type": "string"
+                },
+                "crawl": {
+                    "$ref": "#/definitions/models.Crawl"
+                },
+                "crawl_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "external_issue": {
+                    "description": "Set when an issue has been filed for the broken link",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ExternalIssue"
+                        }
+                    ]
+                },
+                "found_on_url": {
+                    "description": "Page the link was found on: the seed page, or a deeper page for broken links found during deep crawls",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_accessible": {
+                    "description": "No gorm default, it would turn false into true on insert",
+                    "type": "boolean"
+                },
+                "link_text": {
+                    "type": "string"
+                },
+                "link_type": {
+                    "description": "internal, external, mailto, tel, anchor, or other; only internal and external links are checked",
+                    "type": "string"
+                },
+                "link_url": {
+                    "type": "string"
+                },
+                "nofollow": {
+                    "type": "boolean"
+                },
+                "occurrences": {
+                    "description": "Times the page links to the URL; repeated links are stored once",
+                    "type": "integer"
+                },
+                "rel": {
+                    "description": "Lowercased rel attribute, e.g. \"nofollow noopener\"",
+                    "type": "string"
+                },
+                "sponsored": {
+                    "type": "boolean"
+                },
+                "status_code": {
+                    "type": "integer"
+                },
+                "target": {
+                    "description": "target attribute, e.g. _blank",
+                    "type": "string"
+                },
+                "ugc": {
+                    "type": "boolean"
+                },
+                "url": {
+                    "description": "Relationships",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.URL"
+                        }
+                    ]
+                },
+                "url_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.LinkRecheckResult": {
+            "type": "object",
+            "properties": {
+                "broken_links": {
+                    "description": "Broken links of the crawl after the recheck",
+                    "type": "integer"
+                },
+                "checked": {
+                    "description": "Broken links requested again",
+                    "type": "integer"
+                },
+                "crawl_id": {
+                    "type": "integer"
+                },
+                "fixed": {
+                    "description": "Links that are accessible now",
+                    "type": "integer"
+                },
+                "links": {
+                    "description": "The rechecked links with their new status",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Link"
+                    }
+                },
+                "still_broken": {
+                    "description": "Links that are still broken",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.LinkStatusDifference": {
+            "type": "object",
+            "properties": {
+                "baseline_status": {
+                    "type": "integer"
+                },
+                "candidate_status": {
+                    "type": "integer"
+                },
+                "link": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.LoginFormResult": {
+            "type": "object",
+            "properties": {
+                "confidence": {
+                    "description": "0-1, from the strength of the evidence",
+                    "type": "number"
+                },
+                "detected": {
+                    "description": "Result of the crawler's heuristic",
+                    "type": "boolean"
+                },
+                "effective": {
+                    "description": "Value reported for the URL",
+                    "type": "boolean"
+                },
+                "evidence": {
+                    "description": "Signals that contributed to the detection",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "override": {
+                    "description": "Manual correction, if any",
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.LoginRequest": {
+            "type": "object",
+            "required": [
+                "password",
+                "username"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Membership": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "organization_id": {
+                    "type": "integer"
+                },
+                "role": {
+                    "$ref": "#/definitions/models.OrgRole"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/models.User"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.OrgRole": {
+            "type": "string",
+            "enum": [
+                "owner",
+                "admin",
+                "member",
+                "viewer"
+            ],
+            "x-enum-varnames": [
+                "OrgRoleOwner",
+                "OrgRoleAdmin",
+                "OrgRoleMember",
+                "OrgRoleViewer"
+            ]
+        },
+        "models.OrganizationWithRole": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "role": {
+                    "$ref": "#/definitions/models.OrgRole"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Page": {
+            "type": "object",
+            "properties": {
+                "canonical": {
+                    "type": "string"
+                },
+                "connection_reused": {
+                    "description": "The request went over a connection opened for an earlier one",
+                    "type": "boolean"
+                },
+                "content_encoding": {
+                    "description": "e.g. gzip, br, empty if uncompressed",
+                    "type": "string"
+                },
+                "content_hash": {
+                    "description": "SHA-256 of the normalized page text",
+                    "type": "string"
+                },
+                "crawl_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "depth": {
+                    "type": "integer"
+                },
+                "download_ms": {
+                    "description": "Time until the whole body was read",
+                    "type": "integer"
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "heading_counts": {
+                    "description": "JSON string: {\"h1\":1,\"h2\":3,...}",
+                    "type": "string"
+                },
+                "heading_depth": {
+                    "description": "Deepest heading level used on the page, 0 if none",
+                    "type": "integer"
+                },
+                "http3_advertised": {
+                    "description": "HTTP/3 runs over QUIC, which the crawler doesn't speak; servers offer it through Alt-Svc",
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "keep_alive": {
+                    "description": "The server left the connection open for further requests",
+                    "type": "boolean"
+                },
+                "page_url": {
+                    "type": "string"
+                },
+                "protocol": {
+                    "description": "e.g. HTTP/1.1, HTTP/2.0",
+                    "type": "string"
+                },
+                "response_bytes": {
+                    "description": "Size of the decoded body",
+                    "type": "integer"
+                },
+                "status_code": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "ttfb_ms": {
+                    "description": "Time to first byte of the response",
+                    "type": "integer"
+                },
+                "url_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PageIcon": {
+            "type": "object",
+            "properties": {
+                "implicit": {
+                    "description": "/favicon.ico, checked because the page links no favicon",
+                    "type": "boolean"
+                },
+                "is_accessible": {
+                    "type": "boolean"
+                },
+                "kind": {
+                    "description": "favicon, apple_touch_icon, manifest",
+                    "type": "string"
+                },
+                "sizes": {
+                    "type": "string"
+                },
+                "status_code": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PageMeta": {
+            "type": "object",
+            "properties": {
+                "amp_url": {
+                    "description": "AMP variant from \u003clink rel=\"amphtml\"\u003e",
+                    "type": "string"
+                },
+                "canonical": {
+                    "type": "string"
+                },
+                "crawl_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "icons": {
+                    "description": "Favicons, apple-touch icons and web app manifests linked from the page",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PageIcon"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "open_graph": {
+                    "description": "Open Graph (og:*) and Twitter Card (twitter:*) tags keyed by property name",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "robots": {
+                    "type": "string"
+                },
+                "twitter_card": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "url_id": {
+                    "type": "integer"
+                },
+                "viewport": {
+                    "description": "Content of the viewport meta tag",
+                    "type": "string"
+                }
+            }
+        },
+        "models.PerformanceSample": {
+            "type": "object",
+            "properties": {
+                "connection_reused": {
+                    "description": "The request went over a connection opened for an earlier one",
+                    "type": "boolean"
+                },
+                "content_encoding": {
+                    "description": "e.g. gzip, br, empty if uncompressed",
+                    "type": "string"
+                },
+                "crawl_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "download_ms": {
+                    "description": "Time until the whole body was read",
+                    "type": "integer"
+                },
+                "http3_advertised": {
+                    "description": "HTTP/3 runs over QUIC, which the crawler doesn't speak; servers offer it through Alt-Svc",
+                    "type": "boolean"
+                },
+                "keep_alive": {
+                    "description": "The server left the connection open for further requests",
+                    "type": "boolean"
+                },
+                "protocol": {
+                    "description": "e.g. HTTP/1.1, HTTP/2.0",
+                    "type": "string"
+                },
+                "response_bytes": {
+                    "description": "Size of the decoded body",
+                    "type": "integer"
+                },
+                "ttfb_ms": {
+                    "description": "Time to first byte of the response",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.QuotaLimits": {
+            "type": "object",
+            "properties": {
+                "max_crawls_per_day": {
+                    "description": "Crawls started by the user per UTC day",
+                    "type": "integer"
+                },
+                "max_pages": {
+                    "description": "Pages per deep crawl of the user's URLs",
+                    "type": "integer"
+                },
+                "max_urls": {
+                    "description": "URLs added by the user",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.QuotaUsage": {
+            "type": "object",
+            "properties": {
+                "crawls_reset_at": {
+                    "type": "string"
+                },
+                "crawls_today": {
+                    "type": "integer"
+                },
+                "limits": {
+                    "$ref": "#/definitions/models.QuotaLimits"
+                },
+                "plan": {
+                    "type": "string"
+                },
+                "unlimited": {
+                    "description": "Admins have no limits",
+                    "type": "boolean"
+                },
+                "urls": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.RefreshTokenRequest": {
+            "type": "object",
+            "properties": {
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.RegisterRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "first_name",
+                "last_name",
+                "password",
+                "username"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "first_name": {
+                    "type": "string"
+                },
+                "last_name": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 6
+                },
+                "username": {
+                    "type": "string",
+                    "maxLength": 20,
+                    "minLength": 3
+                }
+            }
+        },
+        "models.RequestMetricsReport": {
+            "type": "object",
+            "properties": {
+                "routes": {
+                    "description": "Most called first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RouteRequestStats"
+                    }
+                },
+                "since": {
+                    "type": "string"
+                },
+                "users": {
+                    "description": "Most active first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.UserRequestStats"
+                    }
+                }
+            }
+        },
+        "models.ResponseMetrics": {
+            "type": "object",
+            "properties": {
+                "connection_reused": {
+                    "description": "The request went over a connection opened for an earlier one",
+                    "type": "boolean"
+                },
+                "content_encoding": {
+                    "description": "e.g. gzip, br, empty if uncompressed",
+                    "type": "string"
+                },
+                "download_ms": {
+                    "description": "Time until the whole body was read",
+                    "type": "integer"
+                },
+                "http3_advertised": {
+                    "description": "HTTP/3 runs over QUIC, which the crawler doesn't speak; servers offer it through Alt-Svc",
+                    "type": "boolean"
+                },
+                "keep_alive": {
+                    "description": "The server left the connection open for further requests",
+                    "type": "boolean"
+                },
+                "protocol": {
+                    "description": "e.g. HTTP/1.1, HTTP/2.0",
+                    "type": "string"
+                },
+                "response_bytes": {
+                    "description": "Size of the decoded body",
+                    "type": "integer"
+                },
+                "ttfb_ms": {
+                    "description": "Time to first byte of the response",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.RouteRequestStats": {
+            "type": "object",
+            "properties": {
+                "avg_latency_ms": {
+                    "type": "number"
+                },
+                "calls": {
+                    "type": "integer"
+                },
+                "client_errors": {
+                    "description": "4xx responses, including rate limited requests",
+                    "type": "integer"
+                },
+                "max_latency_ms": {
+                    "type": "number"
+                },
+                "method": {
+                    "type": "string"
+                },
+                "route": {
+                    "description": "Route pattern; empty for requests matching no route",
+                    "type": "string"
+                },
+                "server_errors": {
+                    "description": "5xx responses",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.RunningCrawl": {
+            "type": "object",
+            "properties": {
+                "crawl_id": {
+                    "type": "integer"
+                },
+                "duration_ms": {
+                    "description": "How long the crawl has been running",
+                    "type": "integer"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "url_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SEOCheck": {
+            "type": "object",
+            "properties": {
+                "external_issue": {
+                    "description": "Set when an issue has been filed for the failed check",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ExternalIssue"
+                        }
+                    ]
+                },
+                "key": {
+                    "type": "string"
+                },
+                "max_score": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "passed": {
+                    "type": "boolean"
+                },
+                "score": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SEOReport": {
+            "type": "object",
+            "properties": {
+                "checks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SEOCheck"
+                    }
+                },
+                "crawl_id": {
+                    "type": "integer"
+                },
+                "score": {
+                    "description": "0-100",
+                    "type": "integer"
+                },
+                "url_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SecurityCheck": {
+            "type": "object",
+            "properties": {
+                "header": {
+                    "type": "string"
+                },
+                "max_score": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "passed": {
+                    "type": "boolean"
+                },
+                "present": {
+                    "type": "boolean"
+                },
+                "score": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SecurityReport": {
+            "type": "object",
+            "properties": {
+                "checks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SecurityCheck"
+                    }
+                },
+                "crawl_id": {
+                    "type": "integer"
+                },
+                "headers": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "score": {
+                    "description": "0-100",
+                    "type": "integer"
+                },
+                "url_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ShareLink": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "path": {
+                    "description": "API path serving the report",
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ShareRequest": {
+            "type": "object",
+            "properties": {
+                "expires_in_hours": {
+                    "type": "integer",
+                    "minimum": 1
+                }
+            }
+        },
+        "models.SharedReport": {
+            "type": "object",
+            "properties": {
+                "crawl": {
+                    "$ref": "#/definitions/models.CrawlStatusResponse"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "html_version": {
+                    "type": "string"
+                },
+                "security": {
+                    "$ref": "#/definitions/models.SecurityReport"
+                },
+                "seo": {
+                    "$ref": "#/definitions/models.SEOReport"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SheetExport": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "interval_minutes": {
+                    "description": "0 only exports on demand",
+                    "type": "integer"
+                },
+                "kind": {
+                    "description": "urls, broken_links",
+                    "type": "string"
+                },
+                "last_error": {
+                    "description": "Why the last run failed, empty if it succeeded",
+                    "type": "string"
+                },
+                "last_rows": {
+                    "description": "Rows written by the last run, without the header",
+                    "type": "integer"
+                },
+                "last_run_at": {
+                    "type": "string"
+                },
+                "next_run_at": {
+                    "type": "string"
+                },
+                "organization_id": {
+                    "type": "integer"
+                },
+                "spreadsheet_id": {
+                    "type": "string"
+                },
+                "spreadsheet_url": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "description": "User whose Google account writes the spreadsheet",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Sitemap": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_error": {
+                    "description": "Why the last sync failed, empty if it succeeded",
+                    "type": "string"
+                },
+                "last_synced_at": {
+                    "type": "string"
+                },
+                "next_sync_at": {
+                    "type": "string"
+                },
+                "organization_id": {
+                    "type": "integer"
+                },
+                "sync_interval_minutes": {
+                    "description": "0 never syncs the sitemap again",
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "description": "User who imported the sitemap and owns the URLs it adds",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SitemapEntry": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "loc": {
+                    "description": "Normalized address the sitemap lists",
+                    "type": "string"
+                },
+                "removed_at": {
+                    "description": "When a sync found the page gone from the sitemap, nil while listed",
+                    "type": "string"
+                },
+                "sitemap_id": {
+                    "type": "integer"
+                },
+                "url_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SitemapSyncResult": {
+            "type": "object",
+            "properties": {
+                "added": {
+                    "description": "Pages added as URLs or listed again",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "listed": {
+                    "description": "Pages the sitemap lists",
+                    "type": "integer"
+                },
+                "removed": {
+                    "description": "Pages no longer listed",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "sitemap_id": {
+                    "type": "integer"
+                },
+                "skipped": {
+                    "description": "Pages not added, because they are invalid or over the quota; the next sync tries again",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.StartCrawlRequest": {
+            "type": "object",
+            "properties": {
+                "priority": {
+                    "description": "Defaults to high",
+                    "enum": [
+                        "high",
+                        "low"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.CrawlPriority"
+                        }
+                    ]
+                }
+            }
+        },
+        "models.SystemStatus": {
+            "type": "object",
+            "properties": {
+                "checked_at": {
+                    "type": "string"
+                },
+                "queue": {
+                    "description": "Nil when crawls run in the API process",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.CrawlQueueStats"
+                        }
+                    ]
+                },
+                "recent_failures": {
+                    "description": "Newest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FailedCrawl"
+                    }
+                },
+                "running_crawls": {
+                    "description": "Longest running first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RunningCrawl"
+                    }
+                }
+            }
+        },
+        "models.URL": {
+            "type": "object",
+            "properties": {
+                "allow_subdomains": {
+                    "description": "Deep crawls also follow subdomains of the host",
+                    "type": "boolean"
+                },
+                "check_internal_links": {
+                    "description": "Request internal links of the root page like external ones instead of assuming they work",
+                    "type": "boolean"
+                },
+                "crawls": {
+                    "description": "Relationships",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Crawl"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "disabled_extractors": {
+                    "description": "Extractors skipped on this URL's pages, e.g. \"images\"",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "exclude_patterns": {
+                    "description": "Deep crawls skip pages matching any of these",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "extraction_rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ExtractionRule"
+                    }
+                },
+                "has_login_form": {
+                    "type": "boolean"
+                },
+                "html_version": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "include_patterns": {
+                    "description": "Deep crawls only follow pages matching one of these, empty follows all",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "keywords": {
+                    "description": "Phrases every crawl checks the page for",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "links": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Link"
+                    }
+                },
+                "login_form_override": {
+                    "description": "Manual correction of the login form detection, nil to use the crawler's result",
+                    "type": "boolean"
+                },
+                "max_depth": {
+                    "description": "Link depth followed from the root page, 0 crawls the root page only",
+                    "type": "integer"
+                },
+                "max_pages": {
+                    "description": "Page limit for deep crawls, 0 uses the crawler default",
+                    "type": "integer"
+                },
+                "max_query_params": {
+                    "description": "Skip links with more query parameters, 0 for no limit",
+                    "type": "integer"
+                },
+                "needs_attention": {
+                    "description": "The latest completed crawl crossed the broken link threshold",
+                    "type": "boolean"
+                },
+                "organization_id": {
+                    "description": "Organization sharing the URL, 0 for none",
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "pending, running, completed, skipped, error",
+                    "type": "string"
+                },
+                "strip_query": {
+                    "description": "Drop query strings from discovered links",
+                    "type": "boolean"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "description": "User who first added the URL",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.UpdateMemberRequest": {
+            "type": "object",
+            "required": [
+                "role"
+            ],
+            "properties": {
+                "role": {
+                    "enum": [
+                        "owner",
+                        "admin",
+                        "member",
+                        "viewer"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.OrgRole"
+                        }
+                    ]
+                }
+            }
+        },
+        "models.User": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "first_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "is_admin": {
+                    "type": "boolean"
+                },
+                "last_name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.UserQuota": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "max_crawls_per_day": {
+                    "type": "integer"
+                },
+                "max_pages": {
+                    "type": "integer"
+                },
+                "max_urls": {
+                    "type": "integer"
+                },
+                "plan": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.UserQuotaRequest": {
+            "type": "object",
+            "required": [
+                "plan"
+            ],
+            "properties": {
+                "max_crawls_per_day": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "max_pages": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "max_urls": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "plan": {
+                    "type": "string",
+                    "maxLength": 64
+                }
+            }
+        },
+        "models.UserRequestStats": {
+            "type": "object",
+            "properties": {
+                "avg_latency_ms": {
+                    "type": "number"
+                },
+                "calls": {
+                    "type": "integer"
+                },
+                "client_errors": {
+                    "description": "4xx responses, including rate limited requests",
+                    "type": "integer"
+                },
+                "max_latency_ms": {
+                    "type": "number"
+                },
+                "server_errors": {
+                    "description": "5xx responses",
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "integer"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        }
+    },
+    "securityDefinitions": {
+        "ApiKeyAuth": {
+            "description": "Bearer followed by a JWT from /auth/login, or by an API token from /tokens",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
+        }
+    }
+}`
+
+// SwaggerInfo holds exported Swagger Info so clients can modify it
+var SwaggerInfo = &swag.Spec{
+	Version:          "1.0",
+	Host:             "",
+	BasePath:         "/api/v1",
+	Schemes:          []string{},
+	Title:            "Web Crawler API",
+	Description:      "Crawls websites and reports on their links, SEO, accessibility and security.",
+	InfoInstanceName: "swagger",
+	SwaggerTemplate:  docTemplate,
+	LeftDelim:        "{{",
+	RightDelim:       "}}",
+}
+
+func init() {
+	swag.Register(SwaggerInfo.InstanceName(), SwaggerInfo)
+}
@@ -0,0 +1,213 @@
+// Package apperror defines the errors the API responds with. Services return
+// them for failures a client can act on, handlers wrap everything else, and
+// every error response has the same envelope:
+//
+//	{"error": "URL not found", "code": "url_not_found",
+//	 "message": "The requested URL does not exist", "request_id": "..."}
+//
+// "error" is a short human readable title and "code" a stable machine
+// readable one. Errors with more context add a "details" object.
+package apperror
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDKey is the context key the request ID is stored under
+const RequestIDKey = "request_id"
+
+// Codes used when an error doesn't have a more specific one
+const (
+	CodeInvalidRequest     = "invalid_request"
+	CodeUnauthorized       = "unauthorized"
+	CodeForbidden          = "forbidden"
+	CodeNotFound           = "not_found"
+	CodeConflict           = "conflict"
+	CodeGone               = "gone"
+	CodeTooLarge           = "request_too_large"
+	CodeUnprocessable      = "unprocessable"
+	CodeRateLimited        = "rate_limited"
+	CodeInternal           = "internal_error"
+	CodeNotImplemented     = "not_implemented"
+	CodeUnavailable        = "unavailable"
+	CodeValidation         = "validation_error"
+	CodePreconditionFailed = "precondition_failed"
+)
+
+// Error is an error with everything needed to respond with it
+type Error struct {
+	Status  int
+	Code    string
+	Title   string
+	Message string
+	Details map[string]interface{}
+	// Err is the underlying error, logged but not sent to clients
+	Err error
+}
+
+// New creates an error with the default code of its status
+func New(status int, title, message string) *Error {
+	return &Error{Status: status, Code: statusCode(status), Title: title, Message: message}
+}
+
+// Wrap turns err into an error response with the given status and title and
+// err's text as the message. Errors that already are an *Error, such as the
+// ones returned by services, are returned as they are.
+func Wrap(status int, title string, err error) *Error {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	return &Error{Status: status, Code: statusCode(status), Title: title, Message: err.Error(), Err: err}
+}
+
+func (e *Error) Error() string {
+	if e.Message != "" {
+		return e.Message
+	}
+	return e.Title
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is matches errors with the same code, so copies made by the With methods
+// still match the error they were made from
+func (e *Error) Is(target error) bool {
+	appErr, ok := target.(*Error)
+	return ok && appErr.Code == e.Code
+}
+
+// WithCode returns a copy of the error with another code
+func (e *Error) WithCode(code string) *Error {
+	copied := *e
+	copied.Code = code
+	return &copied
+}
+
+// WithMessage returns a copy of the error with another message
+func (e *Error) WithMessage(message string) *Error {
+	copied := *e
+	copied.Message = message
+	return &copied
+}
+
+// WithDetails returns a copy of the error with details added to the response
+func (e *Error) WithDetails(details map[string]interface{}) *Error {
+	copied := *e
+	copied.Details = details
+	return &copied
+}
+
+// From returns err as an *Error. Other errors become internal errors, with
+// their text kept out of the response.
+func From(err error) *Error {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	return &Error{
+		Status:  http.StatusInternalServerError,
+		Code:    CodeInternal,
+		Title:   "Internal server error",
+		Message: "Something went wrong",
+		Err:     err,
+	}
+}
+
+// FieldError describes one invalid field of a request body
+type FieldError struct {
+	Field   string `json:"field" example:"email"`
+	Rule    string `json:"rule" example:"email"`
+	Message string `json:"message" example:"email must be a valid email address"`
+}
+
+// Validation creates a 400 error listing the invalid fields of a request
+// body under "fields" in its details
+func Validation(title string, fields []FieldError) *Error {
+	messages := make([]string, len(fields))
+	for i, field := range fields {
+		messages[i] = field.Message
+	}
+	return &Error{
+		Status:  http.StatusBadRequest,
+		Code:    CodeValidation,
+		Title:   title,
+		Message: strings.Join(messages, "; "),
+		Details: map[string]interface{}{"fields": fields},
+	}
+}
+
+// Response is the JSON envelope of every error response
+type Response struct {
+	Error     string                 `json:"error" example:"URL not found"`
+	Code      string                 `json:"code" example:"url_not_found"`
+	Message   string                 `json:"message" example:"The requested URL does not exist"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+	RequestID string                 `json:"request_id,omitempty" example:"4f1c9a0e2b7d6c35"`
+}
+
+// Render writes err as the response of the request
+func Render(c *gin.Context, err error) {
+	appErr := From(err)
+	requestID := c.GetString(RequestIDKey)
+	if appErr.Status >= http.StatusInternalServerError && appErr.Err != nil {
+		log.Printf("Error in request %s: %s: %v", requestID, appErr.Title, appErr.Err)
+	}
+	c.JSON(appErr.Status, Response{
+		Error:     appErr.Title,
+		Code:      appErr.Code,
+		Message:   appErr.Message,
+		Details:   appErr.Details,
+		RequestID: requestID,
+	})
+}
+
+// Abort records err on the request, responds with it and stops the handler
+// chain. The recorded error shows up in the request log, and ErrorHandler
+// leaves the written response alone.
+func Abort(c *gin.Context, err error) {
+	_ = c.Error(err)
+	Render(c, err)
+	c.Abort()
+}
+
+// statusCode returns the default code of an HTTP status
+func statusCode(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return CodeInvalidRequest
+	case http.StatusUnauthorized:
+		return CodeUnauthorized
+	case http.StatusForbidden:
+		return CodeForbidden
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusConflict:
+		return CodeConflict
+	case http.StatusGone:
+		return CodeGone
+	case http.StatusPreconditionFailed:
+		return CodePreconditionFailed
+	case http.StatusRequestEntityTooLarge:
+		return CodeTooLarge
+	case http.StatusUnprocessableEntity:
+		return CodeUnprocessable
+	case http.StatusTooManyRequests:
+		return CodeRateLimited
+	case http.StatusNotImplemented:
+		return CodeNotImplemented
+	case http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return CodeUnavailable
+	}
+	if status >= http.StatusInternalServerError {
+		return CodeInternal
+	}
+	return CodeInvalidRequest
+}
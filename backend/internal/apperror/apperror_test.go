@@ -0,0 +1,74 @@
+package apperror
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestError(t *testing.T) {
+	notFound := New(http.StatusNotFound, "URL not found", "The requested URL does not exist").WithCode("url_not_found")
+
+	t.Run("codes default to the status", func(t *testing.T) {
+		assert.Equal(t, CodeInvalidRequest, New(http.StatusBadRequest, "Invalid", "").Code)
+		assert.Equal(t, CodeRateLimited, New(http.StatusTooManyRequests, "Slow down", "").Code)
+		assert.Equal(t, CodeInternal, New(http.StatusBadGateway, "Upstream failed", "").Code)
+	})
+
+	t.Run("copies match the error they were made from", func(t *testing.T) {
+		wrapped := fmt.Errorf("loading: %w", notFound.WithMessage("Not in the trash"))
+		assert.ErrorIs(t, wrapped, notFound)
+		assert.NotErrorIs(t, New(http.StatusNotFound, "Crawl not found", ""), notFound)
+		assert.Equal(t, "The requested URL does not exist", notFound.Error())
+	})
+
+	t.Run("wrapping keeps app errors", func(t *testing.T) {
+		assert.Same(t, notFound, Wrap(http.StatusInternalServerError, "Failed", fmt.Errorf("loading: %w", notFound)))
+
+		cause := errors.New("connection refused")
+		wrapped := Wrap(http.StatusInternalServerError, "Failed to fetch URL", cause)
+		assert.Equal(t, CodeInternal, wrapped.Code)
+		assert.Equal(t, "connection refused", wrapped.Message)
+		assert.ErrorIs(t, wrapped, cause)
+	})
+
+	t.Run("other errors are internal without their text", func(t *testing.T) {
+		internal := From(errors.New("password=secret"))
+		assert.Equal(t, http.StatusInternalServerError, internal.Status)
+		assert.Equal(t, "Something went wrong", internal.Message)
+	})
+}
+
+func TestAbort(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/test", func(c *gin.Context) {
+		c.Set(RequestIDKey, "req-1")
+		Abort(c, New(http.StatusForbidden, "Quota exceeded", "URL limit reached").
+			WithCode("quota_exceeded").
+			WithDetails(map[string]interface{}{"limit": 1}))
+		assert.True(t, c.IsAborted())
+		assert.Len(t, c.Errors, 1)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/test", nil))
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	var response Response
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, Response{
+		Error:     "Quota exceeded",
+		Code:      "quota_exceeded",
+		Message:   "URL limit reached",
+		Details:   map[string]interface{}{"limit": float64(1)},
+		RequestID: "req-1",
+	}, response)
+}
@@ -0,0 +1,84 @@
+package authcookie
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testContext(cookies ...*http.Cookie) (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("POST", "/", nil)
+	for _, cookie := range cookies {
+		c.Request.AddCookie(cookie)
+	}
+	return c, w
+}
+
+func TestOptions_SetToken(t *testing.T) {
+	options := &Options{Domain: "crawler.example.com", Secure: true, SameSite: http.SameSiteStrictMode}
+	c, w := testContext()
+	options.SetToken(c, "token", time.Now().Add(time.Hour))
+
+	cookies := w.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, DefaultName, cookies[0].Name)
+	assert.Equal(t, "token", cookies[0].Value)
+	assert.True(t, cookies[0].HttpOnly)
+	assert.True(t, cookies[0].Secure)
+	assert.Equal(t, http.SameSiteStrictMode, cookies[0].SameSite)
+	assert.Equal(t, "crawler.example.com", cookies[0].Domain)
+	assert.InDelta(t, 3600, cookies[0].MaxAge, 1)
+
+	c, _ = testContext(cookies[0])
+	token, ok := options.Token(c)
+	assert.True(t, ok)
+	assert.Equal(t, "token", token)
+
+	var disabled *Options
+	c, w = testContext(cookies[0])
+	disabled.SetToken(c, "token", time.Now().Add(time.Hour))
+	assert.Empty(t, w.Result().Cookies())
+	_, ok = disabled.Token(c)
+	assert.False(t, ok, "nil options read no cookies")
+}
+
+func TestOptions_CSRF(t *testing.T) {
+	options := &Options{Secret: []byte("secret")}
+	c, w := testContext()
+	token, err := options.IssueCSRF(c)
+	require.NoError(t, err)
+	cookies := w.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.False(t, cookies[0].HttpOnly, "scripts read the CSRF cookie")
+
+	tests := []struct {
+		name   string
+		cookie string
+		header string
+		valid  bool
+	}{
+		{"echoed token", token, token, true},
+		{"missing header", token, "", false},
+		{"other token", token, token + "x", false},
+		{"forged token", "nonce.signature", "nonce.signature", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := testContext(&http.Cookie{Name: CSRFCookie, Value: tt.cookie})
+			c.Request.Header.Set(CSRFHeader, tt.header)
+			assert.Equal(t, tt.valid, options.ValidCSRF(c))
+		})
+	}
+
+	c, _ = testContext(&http.Cookie{Name: CSRFCookie, Value: token})
+	c.Request.Header.Set(CSRFHeader, token)
+	assert.False(t, (&Options{Secret: []byte("other")}).ValidCSRF(c), "tokens are signed with the secret")
+}
@@ -1,28 +1,434 @@
-package config
-
-import (
-	"os"
-)
-
-type Config struct {
-	Environment string
-	DatabaseURL string
-	Port        string
-	JWTSecret   string
-}
-
-func Load() *Config {
-	return &Config{
-		Environment: getEnv("ENVIRONMENT", "development"),
-		DatabaseURL: getEnv("DATABASE_URL", "root:password@tcp(localhost:3306)/webcrawler?charset=utf8mb4&parseTime=True&loc=Local"),
-		Port:        getEnv("PORT", "8080"),
-		JWTSecret:   getEnv("JWT_SECRET", "your-secret-key-here"),
-	}
-}
-
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
-	}
-	return defaultValue
-} 
\ No newline at end of file
+package config
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"web-crawler-backend/internal/authcookie"
+	"web-crawler-backend/internal/googlesheets"
+	"web-crawler-backend/internal/issuetracker"
+	"web-crawler-backend/internal/password"
+	"web-crawler-backend/internal/server"
+	"web-crawler-backend/internal/storage"
+)
+
+// Development defaults that must be replaced in production
+const (
+	defaultDatabaseURL = "root:password@tcp(localhost:3306)/webcrawler?charset=utf8mb4&parseTime=True&loc=Local"
+	defaultJWTSecret   = "your-secret-key-here"
+	defaultShareSecret = "your-share-secret-here"
+)
+
+type Config struct {
+	Environment string
+	DatabaseURL string
+	DBDriver    string
+	Port        string
+	ReportsDir  string
+
+	// JWTSecret signs access tokens, CSRF cookies and the state of Google
+	// authorizations. Production refuses to start with the default or a
+	// short one.
+	JWTSecret string
+
+	// JWTTokenLifetime is how long access tokens stay valid. JWTIssuer is set
+	// as their issuer and, when not empty, required of tokens; JWTClockSkew is
+	// the leeway given to their expiry and issue times.
+	JWTTokenLifetime time.Duration
+	JWTIssuer        string
+	JWTClockSkew     time.Duration
+
+	// AuthCookies also hands tokens to browsers in an HttpOnly cookie, and
+	// accepts the cookie with a CSRF token instead of the Authorization
+	// header. The cookie is scoped to AuthCookieDomain, limited to HTTPS by
+	// AuthCookieSecure, and AuthCookieSameSite is lax, strict or none.
+	// AuthCookieSecure also limits the cookie of Google authorizations.
+	AuthCookies        bool
+	AuthCookieDomain   string
+	AuthCookieSecure   bool
+	AuthCookieSameSite string
+
+	// OnboardingSampleURL is crawled as a demo for new accounts; empty disables it
+	OnboardingSampleURL string
+
+	// Crawler settings
+	CrawlConcurrency int
+	CrawlHostQPS     float64
+	CrawlMaxPages    int
+	// CrawlMaxResponseBytes is the largest page the crawler downloads
+	CrawlMaxResponseBytes int
+	// Retries of transient seed request failures, with exponential backoff
+	CrawlMaxRetries     int
+	CrawlRetryBaseDelay time.Duration
+	CrawlRetryMaxDelay  time.Duration
+	// CrawlInsertBatchSize is how many discovered links are inserted per statement
+	CrawlInsertBatchSize int
+	// CrawlMaxDuration is how long a crawl may run before the watchdog fails it
+	CrawlMaxDuration time.Duration
+	// CrawlRetentionKeep is how many crawls are kept per URL, 0 keeps all;
+	// older crawls are archived to CrawlArchiveDir first if it is set
+	CrawlRetentionKeep int
+	CrawlArchiveDir    string
+	// CrawlSnapshotDir keeps the HTML of every crawled seed page so crawls
+	// can be reprocessed; empty keeps none
+	CrawlSnapshotDir string
+
+	// Destinations exempt from the internal address check, and the ports URLs may use
+	CrawlAllowedHosts    []string
+	CrawlAllowedNetworks []string
+	CrawlAllowedPorts    []int
+
+	// API rate limits: requests per window per IP on public endpoints, per
+	// user on protected ones, per user on the crawl endpoints, and per IP on
+	// token refresh and validation
+	RateLimitWindow        time.Duration
+	RateLimitPublic        int
+	RateLimitUser          int
+	RateLimitCrawl         int
+	RateLimitToken         int
+
+	// TrustedProxies are the addresses or CIDR ranges of the reverse proxies
+	// in front of the API. Only their X-Forwarded-For headers are believed
+	// for the client IP that rate limits and the token guard key on; empty
+	// trusts none and uses the connection's address.
+	TrustedProxies []string
+
+	// TokenGuardMaxFailures invalid tokens from one IP within
+	// TokenGuardWindow on token refresh and validation are logged as an
+	// incident; zero disables this. The IP is then blocked from them for
+	// TokenGuardBlock; zero only logs.
+	TokenGuardMaxFailures int
+	TokenGuardWindow      time.Duration
+	TokenGuardBlock       time.Duration
+
+	// RequestTimeout is the deadline of every API request, and
+	// MaxRequestBodyBytes the largest request body accepted
+	RequestTimeout      time.Duration
+	MaxRequestBodyBytes int
+	// CompressMinBytes is the smallest response body that gets compressed
+	CompressMinBytes int
+
+	// SwaggerUI serves the interactive API docs at /swagger/index.html
+	SwaggerUI bool
+
+	// IdempotencyKeyTTL is how long responses to requests with an
+	// Idempotency-Key header are replayed to retries
+	IdempotencyKeyTTL time.Duration
+	// TrashRetention is how long deleted URLs stay restorable before they are
+	// purged; zero keeps them until purged by hand
+	TrashRetention time.Duration
+	// MonitorHistory is how long uptime checks are kept; zero keeps them forever
+	MonitorHistory time.Duration
+
+	// TLS termination: certificates from TLSCertFile and TLSKeyFile, or from
+	// Let's Encrypt for TLSAutocertDomains, kept in TLSAutocertCacheDir.
+	// TLSRedirectPort runs a plain HTTP listener redirecting to HTTPS, which
+	// also answers the challenges of Let's Encrypt; empty runs none.
+	TLSCertFile         string
+	TLSKeyFile          string
+	TLSAutocertDomains  []string
+	TLSAutocertCacheDir string
+	TLSAutocertEmail    string
+	TLSRedirectPort     string
+
+	// GRPCPort serves the crawler over gRPC for internal services; empty disables it
+	GRPCPort string
+
+	// RedisURL enables caching of URL lists and crawl statuses in Redis,
+	// e.g. redis://localhost:6379/0; empty disables caching and the crawl
+	// queue. CacheTTL bounds how long a cache entry is served.
+	RedisURL string
+	CacheTTL time.Duration
+	// LinkCheckCacheTTL is how long the status of an external link is reused
+	// by later crawls when Redis is set; 0 checks links on every crawl
+	LinkCheckCacheTTL time.Duration
+
+	// CrawlQueue runs crawls through a queue in Redis, so several replicas
+	// or worker processes can share them. CrawlQueueWorkers is how many
+	// crawls this process runs at once; 0 only enqueues crawls for others.
+	CrawlQueue           bool
+	CrawlQueueWorkers    int
+	CrawlQueueMaxRetries int
+	// WorkerHealthPort serves the health checks of cmd/worker; empty disables them
+	WorkerHealthPort string
+
+	// FeatureFlags overrides feature flags on this deployment, read from e.g.
+	// FEATURE_FLAGS=deep_crawl=false; it wins over the settings admins store
+	FeatureFlags map[string]bool
+
+	// Limits of the default plan per user: URLs added, crawls started per
+	// UTC day and pages per deep crawl. Zero means no limit; admins have none.
+	QuotaMaxURLs         int
+	QuotaMaxCrawlsPerDay int
+	QuotaMaxPages        int
+
+	// ShareSecret signs public report links. ShareTTL is how long a link
+	// stays valid unless it asks for less; ShareMaxTTL bounds what it may ask for.
+	ShareSecret string
+	ShareTTL    time.Duration
+	ShareMaxTTL time.Duration
+
+	// PasswordHashAlgorithm hashes new passwords with "argon2id" or "bcrypt".
+	// Hashes of the other algorithm or with other costs than the ones below
+	// keep working and are replaced when their user logs in.
+	PasswordHashAlgorithm string
+	PasswordBcryptCost    int
+	// Argon2id cost: memory in KiB, iterations and threads
+	PasswordArgon2Memory      int
+	PasswordArgon2Iterations  int
+	PasswordArgon2Parallelism int
+
+	// StorageBackend keeps report bundles, crawl archives and snapshots on
+	// the local disk ("local") or in an S3-compatible bucket ("s3"). With S3,
+	// ReportsDir, CrawlArchiveDir and CrawlSnapshotDir are key prefixes in the bucket.
+	StorageBackend    string
+	S3Endpoint        string
+	S3Region          string
+	S3Bucket          string
+	S3AccessKeyID     string
+	S3SecretAccessKey string
+	// S3PathStyle addresses the bucket as endpoint/bucket, as MinIO needs
+	S3PathStyle bool
+
+	// Issue trackers findings can be filed in. GitHub issues are opened in
+	// IssueGitHubRepository (owner/name) when IssueGitHubToken is set; Jira
+	// issues of IssueJiraIssueType in IssueJiraProject when IssueJiraAPIToken
+	// is set. IssueLabels are added to the issues of both.
+	IssueGitHubToken      string
+	IssueGitHubRepository string
+	IssueGitHubAPIURL     string
+	IssueJiraURL          string
+	IssueJiraEmail        string
+	IssueJiraAPIToken     string
+	IssueJiraProject      string
+	IssueJiraIssueType    string
+	IssueLabels           []string
+
+	// Google Sheets exports authorize users with the OAuth client
+	// GoogleClientID; empty disables them. GoogleRedirectURL is the
+	// /api/v1/integrations/google/callback URL registered with the client.
+	GoogleClientID     string
+	GoogleClientSecret string
+	GoogleRedirectURL  string
+}
+
+// Load reads the configuration from the environment. Malformed values,
+// values out of range and settings that are unsafe in production are all
+// reported in the returned error, instead of silently falling back to defaults.
+func Load() (*Config, error) {
+	env := &envReader{}
+	cfg := &Config{
+		Environment: env.string("ENVIRONMENT", "development"),
+		DatabaseURL: env.string("DATABASE_URL", defaultDatabaseURL),
+		DBDriver:    env.string("DB_DRIVER", "mysql"), // mysql, postgres or sqlite
+		Port:        env.string("PORT", "8080"),
+		JWTSecret:   env.string("JWT_SECRET", defaultJWTSecret),
+		ReportsDir:  env.string("REPORTS_DIR", "./reports"),
+
+		JWTTokenLifetime: env.duration("JWT_TOKEN_LIFETIME", 24*time.Hour),
+		JWTIssuer:        env.stringAllowEmpty("JWT_ISSUER", ""),
+		JWTClockSkew:     env.duration("JWT_CLOCK_SKEW", 30*time.Second),
+
+		AuthCookies:        env.bool("AUTH_COOKIES", false),
+		AuthCookieDomain:   env.stringAllowEmpty("AUTH_COOKIE_DOMAIN", ""),
+		AuthCookieSecure:   env.bool("AUTH_COOKIE_SECURE", true),
+		AuthCookieSameSite: env.string("AUTH_COOKIE_SAMESITE", "lax"),
+
+		OnboardingSampleURL: env.stringAllowEmpty("ONBOARDING_SAMPLE_URL", "https://books.toscrape.com/"),
+
+		CrawlConcurrency: env.int("CRAWL_CONCURRENCY", 5),
+		CrawlHostQPS:     env.float("CRAWL_HOST_QPS", 10),
+		CrawlMaxPages:    env.int("CRAWL_MAX_PAGES", 100),
+
+		CrawlMaxResponseBytes: env.int("CRAWL_MAX_RESPONSE_BYTES", 10<<20),
+		CrawlMaxRetries:       env.int("CRAWL_MAX_RETRIES", 2),
+		CrawlRetryBaseDelay:   env.duration("CRAWL_RETRY_BASE_DELAY", time.Second),
+		CrawlRetryMaxDelay:    env.duration("CRAWL_RETRY_MAX_DELAY", 30*time.Second),
+		CrawlInsertBatchSize:  env.int("CRAWL_INSERT_BATCH_SIZE", 200),
+		CrawlMaxDuration:      env.duration("CRAWL_MAX_DURATION", 30*time.Minute),
+		CrawlRetentionKeep:    env.int("CRAWL_RETENTION_KEEP", 20),
+		CrawlArchiveDir:       env.stringAllowEmpty("CRAWL_ARCHIVE_DIR", ""),
+		CrawlSnapshotDir:      env.stringAllowEmpty("CRAWL_SNAPSHOT_DIR", ""),
+
+		CrawlAllowedHosts:    env.list("CRAWL_ALLOWED_HOSTS"),
+		CrawlAllowedNetworks: env.list("CRAWL_ALLOWED_NETWORKS"),
+		CrawlAllowedPorts:    env.intList("CRAWL_ALLOWED_PORTS", []int{80, 443, 8080, 8443}),
+
+		RateLimitWindow:        env.duration("RATE_LIMIT_WINDOW", time.Minute),
+		RateLimitPublic:        env.int("RATE_LIMIT_PUBLIC", 60),
+		RateLimitUser:          env.int("RATE_LIMIT_USER", 600),
+		RateLimitCrawl:         env.int("RATE_LIMIT_CRAWL", 30),
+		RateLimitToken:         env.int("RATE_LIMIT_TOKEN", 30),
+
+		TrustedProxies: env.list("TRUSTED_PROXIES"),
+
+		TokenGuardMaxFailures: env.int("TOKEN_GUARD_MAX_FAILURES", 20),
+		TokenGuardWindow:      env.duration("TOKEN_GUARD_WINDOW", 10*time.Minute),
+		TokenGuardBlock:       env.duration("TOKEN_GUARD_BLOCK", 0),
+
+		RequestTimeout:      env.duration("REQUEST_TIMEOUT", 30*time.Second),
+		MaxRequestBodyBytes: env.int("MAX_REQUEST_BODY_BYTES", 1<<20),
+		CompressMinBytes:    env.int("COMPRESS_MIN_BYTES", 1024),
+
+		IdempotencyKeyTTL: env.duration("IDEMPOTENCY_KEY_TTL", 24*time.Hour),
+		TrashRetention:    env.duration("TRASH_RETENTION", 30*24*time.Hour),
+		MonitorHistory:    env.duration("MONITOR_HISTORY", 90*24*time.Hour),
+
+		TLSCertFile:         env.stringAllowEmpty("TLS_CERT_FILE", ""),
+		TLSKeyFile:          env.stringAllowEmpty("TLS_KEY_FILE", ""),
+		TLSAutocertDomains:  env.list("TLS_AUTOCERT_DOMAINS"),
+		TLSAutocertCacheDir: env.string("TLS_AUTOCERT_CACHE_DIR", "./certs"),
+		TLSAutocertEmail:    env.stringAllowEmpty("TLS_AUTOCERT_EMAIL", ""),
+		TLSRedirectPort:     env.stringAllowEmpty("TLS_REDIRECT_PORT", ""),
+
+		SwaggerUI: env.bool("SWAGGER_UI", false),
+		GRPCPort:  env.stringAllowEmpty("GRPC_PORT", "9090"),
+
+		RedisURL: env.stringAllowEmpty("REDIS_URL", ""),
+		CacheTTL: env.duration("CACHE_TTL", 30*time.Second),
+
+		LinkCheckCacheTTL: env.duration("LINK_CHECK_CACHE_TTL", 15*time.Minute),
+
+		CrawlQueue:           env.bool("CRAWL_QUEUE", false),
+		CrawlQueueWorkers:    env.int("CRAWL_QUEUE_WORKERS", 4),
+		CrawlQueueMaxRetries: env.int("CRAWL_QUEUE_MAX_RETRIES", 3),
+		WorkerHealthPort:     env.stringAllowEmpty("WORKER_HEALTH_PORT", "8081"),
+
+		FeatureFlags: env.flags("FEATURE_FLAGS"),
+
+		QuotaMaxURLs:         env.int("QUOTA_MAX_URLS", 0),
+		QuotaMaxCrawlsPerDay: env.int("QUOTA_MAX_CRAWLS_PER_DAY", 0),
+		QuotaMaxPages:        env.int("QUOTA_MAX_PAGES", 0),
+
+		ShareSecret: env.string("SHARE_SECRET", defaultShareSecret),
+		ShareTTL:    env.duration("SHARE_TTL", 7*24*time.Hour),
+		ShareMaxTTL: env.duration("SHARE_MAX_TTL", 30*24*time.Hour),
+
+		PasswordHashAlgorithm:     env.string("PASSWORD_HASH_ALGORITHM", password.AlgorithmArgon2id),
+		PasswordBcryptCost:        env.int("PASSWORD_BCRYPT_COST", 10),
+		PasswordArgon2Memory:      env.int("PASSWORD_ARGON2_MEMORY", int(password.DefaultArgon2Params.Memory)),
+		PasswordArgon2Iterations:  env.int("PASSWORD_ARGON2_ITERATIONS", int(password.DefaultArgon2Params.Iterations)),
+		PasswordArgon2Parallelism: env.int("PASSWORD_ARGON2_PARALLELISM", int(password.DefaultArgon2Params.Parallelism)),
+
+		StorageBackend:    env.string("STORAGE_BACKEND", "local"),
+		S3Endpoint:        env.stringAllowEmpty("S3_ENDPOINT", ""),
+		S3Region:          env.string("S3_REGION", "us-east-1"),
+		S3Bucket:          env.stringAllowEmpty("S3_BUCKET", ""),
+		S3AccessKeyID:     env.stringAllowEmpty("S3_ACCESS_KEY_ID", ""),
+		S3SecretAccessKey: env.stringAllowEmpty("S3_SECRET_ACCESS_KEY", ""),
+		S3PathStyle:       env.bool("S3_PATH_STYLE", false),
+
+		IssueGitHubToken:      env.stringAllowEmpty("ISSUE_GITHUB_TOKEN", ""),
+		IssueGitHubRepository: env.stringAllowEmpty("ISSUE_GITHUB_REPOSITORY", ""),
+		IssueGitHubAPIURL:     env.string("ISSUE_GITHUB_API_URL", "https://api.github.com"),
+		IssueJiraURL:          env.stringAllowEmpty("ISSUE_JIRA_URL", ""),
+		IssueJiraEmail:        env.stringAllowEmpty("ISSUE_JIRA_EMAIL", ""),
+		IssueJiraAPIToken:     env.stringAllowEmpty("ISSUE_JIRA_API_TOKEN", ""),
+		IssueJiraProject:      env.stringAllowEmpty("ISSUE_JIRA_PROJECT", ""),
+		IssueJiraIssueType:    env.string("ISSUE_JIRA_ISSUE_TYPE", "Bug"),
+		IssueLabels:           env.list("ISSUE_LABELS"),
+
+		GoogleClientID:     env.stringAllowEmpty("GOOGLE_CLIENT_ID", ""),
+		GoogleClientSecret: env.stringAllowEmpty("GOOGLE_CLIENT_SECRET", ""),
+		GoogleRedirectURL:  env.stringAllowEmpty("GOOGLE_REDIRECT_URL", ""),
+	}
+
+	problems := append(env.problems, cfg.validate()...)
+	if len(problems) > 0 {
+		return nil, errors.Join(problems...)
+	}
+	return cfg, nil
+}
+
+// TLS returns the TLS termination settings of the API server
+func (c *Config) TLS() server.TLSOptions {
+	options := server.TLSOptions{
+		CertFile:         c.TLSCertFile,
+		KeyFile:          c.TLSKeyFile,
+		AutocertDomains:  c.TLSAutocertDomains,
+		AutocertCacheDir: c.TLSAutocertCacheDir,
+		AutocertEmail:    c.TLSAutocertEmail,
+	}
+	if c.TLSRedirectPort != "" {
+		options.RedirectAddr = ":" + c.TLSRedirectPort
+	}
+	return options
+}
+
+// Cookies returns the settings of cookie auth, nil if it is disabled. CSRF
+// tokens are signed with the JWT secret.
+func (c *Config) Cookies() *authcookie.Options {
+	if !c.AuthCookies {
+		return nil
+	}
+	sameSite := map[string]http.SameSite{
+		"lax":    http.SameSiteLaxMode,
+		"strict": http.SameSiteStrictMode,
+		"none":   http.SameSiteNoneMode,
+	}[c.AuthCookieSameSite]
+	return &authcookie.Options{
+		Domain:   c.AuthCookieDomain,
+		Secure:   c.AuthCookieSecure,
+		SameSite: sameSite,
+		Secret:   []byte(c.JWTSecret),
+	}
+}
+
+// Passwords returns the settings of password hashing
+func (c *Config) Passwords() password.Config {
+	return password.Config{
+		Algorithm:  c.PasswordHashAlgorithm,
+		BcryptCost: c.PasswordBcryptCost,
+		Argon2: password.Argon2Params{
+			Memory:      uint32(c.PasswordArgon2Memory),
+			Iterations:  uint32(c.PasswordArgon2Iterations),
+			Parallelism: uint8(c.PasswordArgon2Parallelism),
+		},
+	}
+}
+
+// Storage returns the settings of the storage backend
+func (c *Config) Storage() storage.Config {
+	return storage.Config{
+		Backend: c.StorageBackend,
+		S3: storage.S3Options{
+			Endpoint:        c.S3Endpoint,
+			Region:          c.S3Region,
+			Bucket:          c.S3Bucket,
+			AccessKeyID:     c.S3AccessKeyID,
+			SecretAccessKey: c.S3SecretAccessKey,
+			PathStyle:       c.S3PathStyle,
+		},
+	}
+}
+
+
+// IssueTrackers returns the settings of the issue trackers
+func (c *Config) IssueTrackers() issuetracker.Config {
+	return issuetracker.Config{
+		GitHub: issuetracker.GitHubOptions{
+			APIURL:     c.IssueGitHubAPIURL,
+			Token:      c.IssueGitHubToken,
+			Repository: c.IssueGitHubRepository,
+			Labels:     c.IssueLabels,
+		},
+		Jira: issuetracker.JiraOptions{
+			BaseURL:   c.IssueJiraURL,
+			Email:     c.IssueJiraEmail,
+			APIToken:  c.IssueJiraAPIToken,
+			Project:   c.IssueJiraProject,
+			IssueType: c.IssueJiraIssueType,
+			Labels:    c.IssueLabels,
+		},
+	}
+}
+
+// GoogleSheets returns the OAuth client of Google Sheets exports
+func (c *Config) GoogleSheets() googlesheets.Options {
+	return googlesheets.Options{
+		ClientID:     c.GoogleClientID,
+		ClientSecret: c.GoogleClientSecret,
+		RedirectURL:  c.GoogleRedirectURL,
+	}
+}
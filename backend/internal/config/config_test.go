@@ -0,0 +1,156 @@
+package config
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_Defaults(t *testing.T) {
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, "development", cfg.Environment)
+	assert.Equal(t, 5, cfg.CrawlConcurrency)
+	assert.Equal(t, 30*time.Minute, cfg.CrawlMaxDuration)
+	assert.Equal(t, 24*time.Hour, cfg.JWTTokenLifetime)
+	assert.Equal(t, []int{80, 443, 8080, 8443}, cfg.CrawlAllowedPorts)
+}
+
+func TestLoad_TypedValues(t *testing.T) {
+	t.Setenv("CRAWL_CONCURRENCY", "8")
+	t.Setenv("CRAWL_HOST_QPS", "2.5")
+	t.Setenv("CRAWL_MAX_DURATION", "1h")
+	t.Setenv("CRAWL_QUEUE", "true")
+	t.Setenv("REDIS_URL", "redis://localhost:6379/0")
+	t.Setenv("CRAWL_ALLOWED_PORTS", "80, 8000")
+	t.Setenv("GRPC_PORT", "")
+	t.Setenv("FEATURE_FLAGS", "deep_crawl=false, beta=1")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, 8, cfg.CrawlConcurrency)
+	assert.Equal(t, 2.5, cfg.CrawlHostQPS)
+	assert.Equal(t, time.Hour, cfg.CrawlMaxDuration)
+	assert.True(t, cfg.CrawlQueue)
+	assert.Equal(t, []int{80, 8000}, cfg.CrawlAllowedPorts)
+	assert.Empty(t, cfg.GRPCPort, "an empty value disables the gRPC server")
+	assert.Equal(t, map[string]bool{"deep_crawl": false, "beta": true}, cfg.FeatureFlags)
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		problem string
+	}{
+		{"malformed number", map[string]string{"CRAWL_CONCURRENCY": "five"}, `CRAWL_CONCURRENCY: "five" is not a whole number`},
+		{"malformed duration", map[string]string{"CRAWL_MAX_DURATION": "30"}, `CRAWL_MAX_DURATION: "30" is not a duration`},
+		{"malformed boolean", map[string]string{"SWAGGER_UI": "yes"}, `SWAGGER_UI: "yes" is not true or false`},
+		{"malformed port list", map[string]string{"CRAWL_ALLOWED_PORTS": "80,https"}, `CRAWL_ALLOWED_PORTS: "https" is not a whole number`},
+		{"malformed feature flag", map[string]string{"FEATURE_FLAGS": "deep_crawl"}, `FEATURE_FLAGS: "deep_crawl" is not a name=true or name=false entry`},
+		{"out of range", map[string]string{"CRAWL_CONCURRENCY": "0"}, "CRAWL_CONCURRENCY: must be at least 1, got 0"},
+		{"invalid port", map[string]string{"PORT": "http"}, `PORT: "http" is not a port number`},
+		{"unknown driver", map[string]string{"DB_DRIVER": "oracle"}, "DB_DRIVER: must be mysql, postgres or sqlite"},
+		{"queue without Redis", map[string]string{"CRAWL_QUEUE": "true"}, "CRAWL_QUEUE: needs REDIS_URL to be set"},
+		{"S3 without bucket", map[string]string{"STORAGE_BACKEND": "s3"}, "S3_BUCKET: must be set when STORAGE_BACKEND is s3"},
+		{"GitHub token without repository", map[string]string{"ISSUE_GITHUB_TOKEN": "secret"}, "ISSUE_GITHUB_REPOSITORY: must be set when ISSUE_GITHUB_TOKEN is"},
+		{"Google client without secret", map[string]string{"GOOGLE_CLIENT_ID": "client"}, "GOOGLE_CLIENT_ID: needs GOOGLE_CLIENT_SECRET and GOOGLE_REDIRECT_URL"},
+		{"Jira token without project", map[string]string{"ISSUE_JIRA_API_TOKEN": "secret", "ISSUE_JIRA_URL": "https://acme.atlassian.net"}, "ISSUE_JIRA_API_TOKEN: needs ISSUE_JIRA_URL, ISSUE_JIRA_EMAIL and ISSUE_JIRA_PROJECT"},
+		{"certificate without key", map[string]string{"TLS_CERT_FILE": "cert.pem"}, "TLS_CERT_FILE: must be set together with TLS_KEY_FILE"},
+		{"redirect without TLS", map[string]string{"TLS_REDIRECT_PORT": "80"}, "TLS_REDIRECT_PORT: needs TLS_CERT_FILE or TLS_AUTOCERT_DOMAINS to be set"},
+		{"unknown password hash", map[string]string{"PASSWORD_HASH_ALGORITHM": "md5"}, "PASSWORD_HASH_ALGORITHM: must be argon2id or bcrypt"},
+		{"bcrypt cost out of range", map[string]string{"PASSWORD_BCRYPT_COST": "40"}, "PASSWORD_BCRYPT_COST: must be between 4 and 31, got 40"},
+		{"unknown SameSite mode", map[string]string{"AUTH_COOKIE_SAMESITE": "loose"}, `AUTH_COOKIE_SAMESITE: must be lax, strict or none, got "loose"`},
+		{"negative block", map[string]string{"TOKEN_GUARD_BLOCK": "-1m"}, "TOKEN_GUARD_BLOCK: must not be negative, got -1m0s"},
+		{"invalid trusted proxy", map[string]string{"TRUSTED_PROXIES": "10.0.0.0/8,proxy.local"}, `TRUSTED_PROXIES: "proxy.local" is not an IP address or CIDR range`},
+		{"token without lifetime", map[string]string{"JWT_TOKEN_LIFETIME": "0s"}, "JWT_TOKEN_LIFETIME: must be a positive duration, got 0s"},
+		{"inverted share lifetimes", map[string]string{"SHARE_TTL": "48h", "SHARE_MAX_TTL": "24h"}, "SHARE_MAX_TTL: must not be shorter than SHARE_TTL"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			cfg, err := Load()
+			assert.Nil(t, cfg)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.problem)
+		})
+	}
+}
+
+func TestLoad_TLS(t *testing.T) {
+	t.Setenv("TLS_AUTOCERT_DOMAINS", "crawler.example.com, www.crawler.example.com")
+	t.Setenv("TLS_REDIRECT_PORT", "80")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	options := cfg.TLS()
+	assert.True(t, options.Enabled())
+	assert.Equal(t, []string{"crawler.example.com", "www.crawler.example.com"}, options.AutocertDomains)
+	assert.Equal(t, "./certs", options.AutocertCacheDir)
+	assert.Equal(t, ":80", options.RedirectAddr)
+}
+
+func TestLoad_Passwords(t *testing.T) {
+	t.Setenv("PASSWORD_HASH_ALGORITHM", "bcrypt")
+	t.Setenv("PASSWORD_BCRYPT_COST", "12")
+	t.Setenv("PASSWORD_ARGON2_MEMORY", "65536")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	passwords := cfg.Passwords()
+	assert.Equal(t, "bcrypt", passwords.Algorithm)
+	assert.Equal(t, 12, passwords.BcryptCost)
+	assert.Equal(t, uint32(65536), passwords.Argon2.Memory)
+	assert.Equal(t, uint32(2), passwords.Argon2.Iterations)
+}
+
+func TestLoad_Cookies(t *testing.T) {
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Nil(t, cfg.Cookies(), "cookie auth is off by default")
+
+	t.Setenv("AUTH_COOKIES", "true")
+	t.Setenv("AUTH_COOKIE_SAMESITE", "strict")
+	cfg, err = Load()
+	require.NoError(t, err)
+	cookies := cfg.Cookies()
+	require.NotNil(t, cookies)
+	assert.True(t, cookies.Secure)
+	assert.Equal(t, http.SameSiteStrictMode, cookies.SameSite)
+	assert.Equal(t, []byte(cfg.JWTSecret), cookies.Secret)
+}
+
+func TestLoad_Production(t *testing.T) {
+	t.Setenv("ENVIRONMENT", "production")
+
+	t.Run("development defaults are rejected", func(t *testing.T) {
+		_, err := Load()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "DATABASE_URL: must be set in production")
+		assert.Contains(t, err.Error(), "JWT_SECRET: must be set in production")
+		assert.Contains(t, err.Error(), "SHARE_SECRET: must be set in production")
+	})
+
+	t.Run("short secrets are rejected", func(t *testing.T) {
+		t.Setenv("DATABASE_URL", "crawler:password@tcp(mysql:3306)/webcrawler")
+		t.Setenv("JWT_SECRET", "short")
+		t.Setenv("SHARE_SECRET", "a-long-enough-share-secret")
+		_, err := Load()
+		require.Error(t, err)
+		assert.Equal(t, "JWT_SECRET: must be at least 16 characters in production", err.Error())
+	})
+
+	t.Run("configured secrets pass", func(t *testing.T) {
+		t.Setenv("DATABASE_URL", "crawler:password@tcp(mysql:3306)/webcrawler")
+		t.Setenv("JWT_SECRET", "a-long-enough-jwt-secret")
+		t.Setenv("SHARE_SECRET", "a-long-enough-share-secret")
+		cfg, err := Load()
+		require.NoError(t, err)
+		assert.Equal(t, "production", cfg.Environment)
+	})
+}
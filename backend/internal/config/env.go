@@ -0,0 +1,135 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// envReader reads typed settings from environment variables. Unset variables
+// take their default; variables that are set but can't be parsed are recorded
+// as problems, so a typo doesn't silently turn into the default.
+type envReader struct {
+	problems []error
+}
+
+func (r *envReader) invalid(key, value, expected string) {
+	r.problems = append(r.problems, fmt.Errorf("%s: %q is not %s", key, value, expected))
+}
+
+// string reads a string; an empty value takes the default
+func (r *envReader) string(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// stringAllowEmpty is like string but lets an explicitly empty value override the default
+func (r *envReader) stringAllowEmpty(key, defaultValue string) string {
+	if value, ok := os.LookupEnv(key); ok {
+		return value
+	}
+	return defaultValue
+}
+
+func (r *envReader) int(key string, defaultValue int) int {
+	raw := strings.TrimSpace(os.Getenv(key))
+	if raw == "" {
+		return defaultValue
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		r.invalid(key, raw, "a whole number")
+		return defaultValue
+	}
+	return value
+}
+
+func (r *envReader) float(key string, defaultValue float64) float64 {
+	raw := strings.TrimSpace(os.Getenv(key))
+	if raw == "" {
+		return defaultValue
+	}
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		r.invalid(key, raw, "a number")
+		return defaultValue
+	}
+	return value
+}
+
+// bool reads a boolean such as "true", "1" or "false"
+func (r *envReader) bool(key string, defaultValue bool) bool {
+	raw := strings.TrimSpace(os.Getenv(key))
+	if raw == "" {
+		return defaultValue
+	}
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		r.invalid(key, raw, "true or false")
+		return defaultValue
+	}
+	return value
+}
+
+// duration reads a duration such as "500ms" or "2s"
+func (r *envReader) duration(key string, defaultValue time.Duration) time.Duration {
+	raw := strings.TrimSpace(os.Getenv(key))
+	if raw == "" {
+		return defaultValue
+	}
+	value, err := time.ParseDuration(raw)
+	if err != nil {
+		r.invalid(key, raw, `a duration such as "30s" or "2h"`)
+		return defaultValue
+	}
+	return value
+}
+
+// list reads a comma separated list, skipping empty entries
+func (r *envReader) list(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// flags reads a comma separated list of name=true or name=false entries
+func (r *envReader) flags(key string) map[string]bool {
+	flags := map[string]bool{}
+	for _, entry := range r.list(key) {
+		name, raw, found := strings.Cut(entry, "=")
+		value, err := strconv.ParseBool(strings.TrimSpace(raw))
+		if !found || strings.TrimSpace(name) == "" || err != nil {
+			r.invalid(key, entry, "a name=true or name=false entry")
+			continue
+		}
+		flags[strings.TrimSpace(name)] = value
+	}
+	return flags
+}
+
+// intList reads a comma separated list of numbers; an unset variable takes the default
+func (r *envReader) intList(key string, defaultValue []int) []int {
+	entries := r.list(key)
+	if len(entries) == 0 {
+		return defaultValue
+	}
+
+	values := make([]int, 0, len(entries))
+	for _, entry := range entries {
+		value, err := strconv.Atoi(entry)
+		if err != nil {
+			r.invalid(key, entry, "a whole number")
+			return defaultValue
+		}
+		values = append(values, value)
+	}
+	return values
+}
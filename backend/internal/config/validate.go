@@ -0,0 +1,195 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+)
+
+// minSecretLength is the shortest JWT or share secret accepted in production
+const minSecretLength = 16
+
+// validate checks that the settings are in range and that production doesn't
+// run with the development defaults, returning every problem found
+func (c *Config) validate() []error {
+	var problems []error
+	problem := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Errorf(format, args...))
+	}
+	atLeast := func(key string, value, min int) {
+		if value < min {
+			problem("%s: must be at least %d, got %d", key, min, value)
+		}
+	}
+	positive := func(key string, value time.Duration) {
+		if value <= 0 {
+			problem("%s: must be a positive duration, got %s", key, value)
+		}
+	}
+	notNegative := func(key string, value time.Duration) {
+		if value < 0 {
+			problem("%s: must not be negative, got %s", key, value)
+		}
+	}
+	port := func(key, value string, optional bool) {
+		if value == "" && optional {
+			return
+		}
+		if number, err := strconv.Atoi(value); err != nil || number < 1 || number > 65535 {
+			problem("%s: %q is not a port number", key, value)
+		}
+	}
+
+	switch c.Environment {
+	case "development", "test", "staging", "production":
+	default:
+		problem("ENVIRONMENT: must be development, test, staging or production, got %q", c.Environment)
+	}
+	switch c.DBDriver {
+	case "mysql", "postgres", "sqlite":
+	default:
+		problem("DB_DRIVER: must be mysql, postgres or sqlite, got %q", c.DBDriver)
+	}
+	port("PORT", c.Port, false)
+	port("GRPC_PORT", c.GRPCPort, true)
+	port("WORKER_HEALTH_PORT", c.WorkerHealthPort, true)
+	port("TLS_REDIRECT_PORT", c.TLSRedirectPort, true)
+
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		problem("TLS_CERT_FILE: must be set together with TLS_KEY_FILE")
+	}
+	tlsEnabled := c.TLSCertFile != "" || len(c.TLSAutocertDomains) > 0
+	if c.TLSCertFile != "" && len(c.TLSAutocertDomains) > 0 {
+		problem("TLS_AUTOCERT_DOMAINS: can't be used together with TLS_CERT_FILE")
+	}
+	if c.TLSRedirectPort != "" && !tlsEnabled {
+		problem("TLS_REDIRECT_PORT: needs TLS_CERT_FILE or TLS_AUTOCERT_DOMAINS to be set")
+	}
+	if c.TLSRedirectPort != "" && c.TLSRedirectPort == c.Port {
+		problem("TLS_REDIRECT_PORT: must differ from PORT")
+	}
+
+	positive("JWT_TOKEN_LIFETIME", c.JWTTokenLifetime)
+	notNegative("JWT_CLOCK_SKEW", c.JWTClockSkew)
+	switch c.AuthCookieSameSite {
+	case "lax", "strict":
+	case "none":
+		if !c.AuthCookieSecure {
+			problem("AUTH_COOKIE_SAMESITE: none needs AUTH_COOKIE_SECURE, browsers reject it otherwise")
+		}
+	default:
+		problem("AUTH_COOKIE_SAMESITE: must be lax, strict or none, got %q", c.AuthCookieSameSite)
+	}
+
+	atLeast("CRAWL_CONCURRENCY", c.CrawlConcurrency, 1)
+	if c.CrawlHostQPS <= 0 {
+		problem("CRAWL_HOST_QPS: must be positive, got %g", c.CrawlHostQPS)
+	}
+	atLeast("CRAWL_MAX_PAGES", c.CrawlMaxPages, 1)
+	atLeast("CRAWL_MAX_RESPONSE_BYTES", c.CrawlMaxResponseBytes, 1)
+	atLeast("CRAWL_MAX_RETRIES", c.CrawlMaxRetries, 0)
+	positive("CRAWL_RETRY_BASE_DELAY", c.CrawlRetryBaseDelay)
+	if c.CrawlRetryMaxDelay < c.CrawlRetryBaseDelay {
+		problem("CRAWL_RETRY_MAX_DELAY: must not be shorter than CRAWL_RETRY_BASE_DELAY (%s), got %s", c.CrawlRetryBaseDelay, c.CrawlRetryMaxDelay)
+	}
+	atLeast("CRAWL_INSERT_BATCH_SIZE", c.CrawlInsertBatchSize, 1)
+	positive("CRAWL_MAX_DURATION", c.CrawlMaxDuration)
+	atLeast("CRAWL_RETENTION_KEEP", c.CrawlRetentionKeep, 0)
+	for _, allowed := range c.CrawlAllowedPorts {
+		if allowed < 1 || allowed > 65535 {
+			problem("CRAWL_ALLOWED_PORTS: %d is not a port number", allowed)
+		}
+	}
+
+	positive("RATE_LIMIT_WINDOW", c.RateLimitWindow)
+	atLeast("RATE_LIMIT_PUBLIC", c.RateLimitPublic, 0)
+	atLeast("RATE_LIMIT_USER", c.RateLimitUser, 0)
+	atLeast("RATE_LIMIT_CRAWL", c.RateLimitCrawl, 0)
+	atLeast("RATE_LIMIT_TOKEN", c.RateLimitToken, 0)
+	for _, proxy := range c.TrustedProxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			problem("TRUSTED_PROXIES: %q is not an IP address or CIDR range", proxy)
+		}
+	}
+	atLeast("TOKEN_GUARD_MAX_FAILURES", c.TokenGuardMaxFailures, 0)
+	positive("TOKEN_GUARD_WINDOW", c.TokenGuardWindow)
+	notNegative("TOKEN_GUARD_BLOCK", c.TokenGuardBlock)
+	notNegative("REQUEST_TIMEOUT", c.RequestTimeout)
+	atLeast("MAX_REQUEST_BODY_BYTES", c.MaxRequestBodyBytes, 0)
+	atLeast("COMPRESS_MIN_BYTES", c.CompressMinBytes, 0)
+
+	notNegative("IDEMPOTENCY_KEY_TTL", c.IdempotencyKeyTTL)
+	notNegative("TRASH_RETENTION", c.TrashRetention)
+	notNegative("MONITOR_HISTORY", c.MonitorHistory)
+	notNegative("CACHE_TTL", c.CacheTTL)
+	notNegative("LINK_CHECK_CACHE_TTL", c.LinkCheckCacheTTL)
+
+	if c.CrawlQueue && c.RedisURL == "" {
+		problem("CRAWL_QUEUE: needs REDIS_URL to be set")
+	}
+	atLeast("CRAWL_QUEUE_WORKERS", c.CrawlQueueWorkers, 0)
+	atLeast("CRAWL_QUEUE_MAX_RETRIES", c.CrawlQueueMaxRetries, 0)
+
+	atLeast("QUOTA_MAX_URLS", c.QuotaMaxURLs, 0)
+	atLeast("QUOTA_MAX_CRAWLS_PER_DAY", c.QuotaMaxCrawlsPerDay, 0)
+	atLeast("QUOTA_MAX_PAGES", c.QuotaMaxPages, 0)
+
+	positive("SHARE_TTL", c.ShareTTL)
+	if c.ShareMaxTTL < c.ShareTTL {
+		problem("SHARE_MAX_TTL: must not be shorter than SHARE_TTL (%s), got %s", c.ShareTTL, c.ShareMaxTTL)
+	}
+
+	switch c.PasswordHashAlgorithm {
+	case "argon2id", "bcrypt":
+	default:
+		problem("PASSWORD_HASH_ALGORITHM: must be argon2id or bcrypt, got %q", c.PasswordHashAlgorithm)
+	}
+	if c.PasswordBcryptCost < 4 || c.PasswordBcryptCost > 31 {
+		problem("PASSWORD_BCRYPT_COST: must be between 4 and 31, got %d", c.PasswordBcryptCost)
+	}
+	if c.PasswordArgon2Parallelism < 1 || c.PasswordArgon2Parallelism > 255 {
+		problem("PASSWORD_ARGON2_PARALLELISM: must be between 1 and 255, got %d", c.PasswordArgon2Parallelism)
+	}
+	atLeast("PASSWORD_ARGON2_ITERATIONS", c.PasswordArgon2Iterations, 1)
+	// Argon2 needs 8 KiB per thread
+	atLeast("PASSWORD_ARGON2_MEMORY", c.PasswordArgon2Memory, 8*c.PasswordArgon2Parallelism)
+
+	switch c.StorageBackend {
+	case "local":
+	case "s3":
+		if c.S3Bucket == "" {
+			problem("S3_BUCKET: must be set when STORAGE_BACKEND is s3")
+		}
+	default:
+		problem("STORAGE_BACKEND: must be local or s3, got %q", c.StorageBackend)
+	}
+
+	if c.IssueGitHubToken != "" && c.IssueGitHubRepository == "" {
+		problem("ISSUE_GITHUB_REPOSITORY: must be set when ISSUE_GITHUB_TOKEN is")
+	}
+	if c.IssueJiraAPIToken != "" && (c.IssueJiraURL == "" || c.IssueJiraEmail == "" || c.IssueJiraProject == "") {
+		problem("ISSUE_JIRA_API_TOKEN: needs ISSUE_JIRA_URL, ISSUE_JIRA_EMAIL and ISSUE_JIRA_PROJECT to be set")
+	}
+
+	if c.GoogleClientID != "" && (c.GoogleClientSecret == "" || c.GoogleRedirectURL == "") {
+		problem("GOOGLE_CLIENT_ID: needs GOOGLE_CLIENT_SECRET and GOOGLE_REDIRECT_URL to be set")
+	}
+
+	if c.Environment == "production" {
+		if c.DatabaseURL == defaultDatabaseURL {
+			problem("DATABASE_URL: must be set in production")
+		}
+		secret := func(key, value, defaultValue string) {
+			switch {
+			case value == defaultValue:
+				problem("%s: must be set in production", key)
+			case len(value) < minSecretLength:
+				problem("%s: must be at least %d characters in production", key, minSecretLength)
+			}
+		}
+		secret("JWT_SECRET", c.JWTSecret, defaultJWTSecret)
+		secret("SHARE_SECRET", c.ShareSecret, defaultShareSecret)
+	}
+	return problems
+}
@@ -5,15 +5,42 @@ import (
 	"log"
 
 	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 
 	"web-crawler-backend/internal/models"
 )
 
+// Supported database drivers, selected with DB_DRIVER
+const (
+	DriverMySQL    = "mysql"
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+// dialector returns the GORM dialector for a database driver
+func dialector(driver, databaseURL string) (gorm.Dialector, error) {
+	switch driver {
+	case DriverMySQL:
+		return mysql.Open(databaseURL), nil
+	case DriverPostgres:
+		return postgres.Open(databaseURL), nil
+	case DriverSQLite:
+		return sqlite.Open(databaseURL), nil
+	}
+	return nil, fmt.Errorf("unsupported database driver %q", driver)
+}
+
 // Initialize creates a new database connection
-func Initialize(databaseURL string) (*gorm.DB, error) {
-	db, err := gorm.Open(mysql.Open(databaseURL), &gorm.Config{
+func Initialize(driver, databaseURL string) (*gorm.DB, error) {
+	dialect, err := dialector(driver, databaseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := gorm.Open(dialect, &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Info),
 	})
 	if err != nil {
@@ -29,13 +56,18 @@ func Initialize(databaseURL string) (*gorm.DB, error) {
 	// Set connection pool settings
 	sqlDB.SetMaxIdleConns(10)
 	sqlDB.SetMaxOpenConns(100)
+	if driver == DriverSQLite {
+		// SQLite allows a single writer, and every connection to :memory:
+		// opens a separate database
+		sqlDB.SetMaxOpenConns(1)
+	}
 
 	return db, nil
 }
 
 // RunMigrations runs all database migrations
-func RunMigrations(databaseURL string) error {
-	db, err := Initialize(databaseURL)
+func RunMigrations(driver, databaseURL string) error {
+	db, err := Initialize(driver, databaseURL)
 	if err != nil {
 		return err
 	}
@@ -46,6 +78,38 @@ func RunMigrations(databaseURL string) error {
 		&models.URL{},
 		&models.Crawl{},
 		&models.Link{},
+		&models.PageMeta{},
+		&models.Page{},
+		&models.PageLink{},
+		&models.Image{},
+		&models.Form{},
+		&models.CrawlEvent{},
+		&models.AccessibilityIssue{},
+		&models.MixedContentIssue{},
+		&models.CrawlSchedule{},
+		&models.ActivityEvent{},
+		&models.FindingAnnotation{},
+		&models.ExtractionRule{},
+		&models.Monitor{},
+		&models.MonitorCheck{},
+		&models.ReportBundle{},
+		&models.OnboardingState{},
+		&models.IdempotencyKey{},
+		&models.UserQuota{},
+		&models.CrawlUsage{},
+		&models.Organization{},
+		&models.Membership{},
+		&models.FeatureFlag{},
+		&models.Session{},
+		&models.AuthIncident{},
+		&models.APIToken{},
+		&models.EnvironmentPair{},
+		&models.Sitemap{},
+		&models.SitemapEntry{},
+		&models.BrokenLinkThreshold{},
+		&models.ExternalIssue{},
+		&models.GoogleConnection{},
+		&models.SheetExport{},
 	)
 	if err != nil {
 		return fmt.Errorf("failed to run migrations: %w", err)
@@ -0,0 +1,155 @@
+package database
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"web-crawler-backend/internal/models"
+)
+
+func TestInitializeSQLite(t *testing.T) {
+	require.NoError(t, RunMigrations(DriverSQLite, "file::memory:"))
+
+	db, err := Initialize(DriverSQLite, ":memory:")
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.User{}, &models.URL{}, &models.IdempotencyKey{}))
+
+	user := models.User{Username: "alice", Email: "alice@example.com", Password: "secret"}
+	require.NoError(t, db.Create(&user).Error)
+	key := models.IdempotencyKey{UserID: user.ID, Key: "k", Method: "POST", Path: "/urls", RequestHash: "h", ResponseBody: []byte(`{}`)}
+	require.NoError(t, db.Create(&key).Error)
+
+	var stored models.IdempotencyKey
+	require.NoError(t, db.First(&stored, key.ID).Error)
+	assert.Equal(t, []byte(`{}`), stored.ResponseBody)
+}
+
+func TestRunMigrationsWithFilesSQLite(t *testing.T) {
+	// Migration directories are relative to the backend root
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir("../.."))
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	path := filepath.Join(t.TempDir(), "webcrawler.db")
+	require.NoError(t, RunMigrationsWithFiles(DriverSQLite, path))
+
+	version, dirty, err := GetMigrationVersion(DriverSQLite, path)
+	require.NoError(t, err)
+	assert.False(t, dirty)
+	assert.Equal(t, uint(54), version)
+
+	db, err := Initialize(DriverSQLite, path)
+	require.NoError(t, err)
+	// The migrated schema must have a column for every model field
+	for _, model := range []interface{}{
+		&models.User{}, &models.URL{}, &models.Crawl{}, &models.Link{}, &models.PageMeta{},
+		&models.Page{}, &models.PageLink{}, &models.Image{}, &models.Form{}, &models.CrawlEvent{}, &models.AccessibilityIssue{},
+		&models.MixedContentIssue{}, &models.CrawlSchedule{}, &models.ActivityEvent{},
+		&models.FindingAnnotation{}, &models.ReportBundle{}, &models.OnboardingState{},
+		&models.IdempotencyKey{}, &models.UserQuota{}, &models.CrawlUsage{}, &models.Organization{}, &models.Membership{}, &models.FeatureFlag{}, &models.Session{}, &models.AuthIncident{}, &models.APIToken{}, &models.EnvironmentPair{}, &models.Sitemap{}, &models.SitemapEntry{}, &models.BrokenLinkThreshold{}, &models.ExternalIssue{}, &models.GoogleConnection{}, &models.SheetExport{},
+		&models.ExtractionRule{}, &models.Monitor{}, &models.MonitorCheck{},
+	} {
+		stmt := &gorm.Statement{DB: db}
+		require.NoError(t, stmt.Parse(model))
+		require.True(t, db.Migrator().HasTable(model), stmt.Schema.Table)
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName != "" {
+				assert.True(t, db.Migrator().HasColumn(model, field.DBName), "%s.%s", stmt.Schema.Table, field.DBName)
+			}
+		}
+	}
+}
+
+func TestMigrationCommandsSQLite(t *testing.T) {
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir("../.."))
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	path := filepath.Join(t.TempDir(), "webcrawler.db")
+	migrations, err := GetMigrationStatus(DriverSQLite, path)
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+	assert.Equal(t, MigrationState{Version: 27, Name: "initial_schema"}, migrations[0])
+
+	require.NoError(t, RunMigrationsWithFiles(DriverSQLite, path))
+	migrations, err = GetMigrationStatus(DriverSQLite, path)
+	require.NoError(t, err)
+	assert.True(t, migrations[len(migrations)-1].Applied)
+
+	// Forcing a version marks later migrations pending without touching the schema
+	require.NoError(t, ForceMigrationVersion(DriverSQLite, path, -1))
+	migrations, err = GetMigrationStatus(DriverSQLite, path)
+	require.NoError(t, err)
+	assert.False(t, migrations[0].Applied)
+
+	require.NoError(t, ForceMigrationVersion(DriverSQLite, path, 27))
+	require.NoError(t, MigrateTo(DriverSQLite, path, 27))
+	version, _, err := GetMigrationVersion(DriverSQLite, path)
+	require.NoError(t, err)
+	assert.Equal(t, uint(27), version)
+}
+
+func TestCreateMigration(t *testing.T) {
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	dir := t.TempDir()
+	for _, driver := range []string{DriverMySQL, DriverPostgres, DriverSQLite} {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, MigrationsDir(driver)), 0o755))
+	}
+	require.NoError(t, os.Chdir(dir))
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	now := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+	paths, err := CreateMigration("Add URL tags!", now)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"migrations/20240501123000_add_url_tags.up.sql",
+		"migrations/20240501123000_add_url_tags.down.sql",
+		"migrations/postgres/20240501123000_add_url_tags.up.sql",
+		"migrations/postgres/20240501123000_add_url_tags.down.sql",
+		"migrations/sqlite/20240501123000_add_url_tags.up.sql",
+		"migrations/sqlite/20240501123000_add_url_tags.down.sql",
+	}, paths)
+
+	migrations, err := listMigrations(MigrationsDir(DriverSQLite))
+	require.NoError(t, err)
+	assert.Equal(t, []MigrationState{{Version: 20240501123000, Name: "add_url_tags"}}, migrations)
+
+	_, err = CreateMigration("Add URL tags", now)
+	assert.Error(t, err, "existing migrations are not overwritten")
+	_, err = CreateMigration("  ", now)
+	assert.Error(t, err)
+}
+
+func TestInitializeUnsupportedDriver(t *testing.T) {
+	_, err := Initialize("oracle", "")
+	assert.ErrorContains(t, err, `unsupported database driver "oracle"`)
+}
+
+func TestMigrationsDir(t *testing.T) {
+	assert.Equal(t, "./migrations", MigrationsDir(DriverMySQL))
+	assert.Equal(t, "./migrations/postgres", MigrationsDir(DriverPostgres))
+	assert.Equal(t, "./migrations/sqlite", MigrationsDir(DriverSQLite))
+}
+
+func TestMigrationDSN(t *testing.T) {
+	dsn, err := migrationDSN(DriverMySQL, "user:pass@tcp(db:3306)/crawler?parseTime=true")
+	require.NoError(t, err)
+	assert.Contains(t, dsn, "multiStatements=true")
+	assert.Contains(t, dsn, "parseTime=true")
+
+	_, err = migrationDSN(DriverMySQL, "not a dsn")
+	assert.Error(t, err)
+
+	dsn, err = migrationDSN(DriverSQLite, "file::memory:")
+	require.NoError(t, err)
+	assert.Equal(t, "file::memory:", dsn)
+}
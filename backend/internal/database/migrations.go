@@ -2,110 +2,277 @@ package database
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
+	gomysql "github.com/go-sql-driver/mysql"
 	"github.com/golang-migrate/migrate/v4"
+	migratedb "github.com/golang-migrate/migrate/v4/database"
 	"github.com/golang-migrate/migrate/v4/database/mysql"
+	"github.com/golang-migrate/migrate/v4/database/pgx/v5"
+	"github.com/golang-migrate/migrate/v4/database/sqlite3"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
-	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/jackc/pgx/v5/stdlib"
 )
 
+// MigrationsDir returns the directory holding the migration files for a
+// database driver. PostgreSQL and SQLite have their own sets, numbered like
+// the MySQL one.
+func MigrationsDir(driver string) string {
+	switch driver {
+	case DriverPostgres:
+		return "./migrations/postgres"
+	case DriverSQLite:
+		return "./migrations/sqlite"
+	}
+	return "./migrations"
+}
+
 // RunMigrationsWithFiles runs migrations from migration files
-func RunMigrationsWithFiles(databaseURL string) error {
-	// Parse database URL to extract connection details
-	db, err := sql.Open("mysql", databaseURL)
+func RunMigrationsWithFiles(driver, databaseURL string) error {
+	db, m, err := newMigrate(driver, databaseURL)
 	if err != nil {
-		return fmt.Errorf("failed to open database connection: %w", err)
+		return err
 	}
 	defer db.Close()
 
-	// Test the connection
-	if err := db.Ping(); err != nil {
-		return fmt.Errorf("failed to ping database: %w", err)
+	// Run migrations
+	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
+		return fmt.Errorf("failed to run migrations: %w", err)
 	}
 
-	// Create MySQL driver instance
-	driver, err := mysql.WithInstance(db, &mysql.Config{})
+	log.Println("Database migrations completed successfully")
+	return nil
+}
+
+// GetMigrationVersion returns the current migration version
+func GetMigrationVersion(driver, databaseURL string) (uint, bool, error) {
+	db, m, err := newMigrate(driver, databaseURL)
 	if err != nil {
-		return fmt.Errorf("failed to create migration driver: %w", err)
+		return 0, false, err
 	}
+	defer db.Close()
 
-	// Create migrate instance
-	m, err := migrate.NewWithDatabaseInstance(
-		"file://./migrations",
-		"mysql",
-		driver,
-	)
+	version, dirty, err := m.Version()
 	if err != nil {
-		return fmt.Errorf("failed to create migrate instance: %w", err)
+		return 0, false, fmt.Errorf("failed to get migration version: %w", err)
 	}
 
-	// Run migrations
-	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
-		return fmt.Errorf("failed to run migrations: %w", err)
+	return version, dirty, nil
+}
+
+// RollbackMigration rolls back one migration step
+func RollbackMigration(driver, databaseURL string) error {
+	db, m, err := newMigrate(driver, databaseURL)
+	if err != nil {
+		return err
 	}
+	defer db.Close()
 
-	log.Println("Database migrations completed successfully")
+	if err := m.Steps(-1); err != nil {
+		return fmt.Errorf("failed to rollback migration: %w", err)
+	}
+
+	log.Println("Migration rollback completed successfully")
 	return nil
 }
 
-// GetMigrationVersion returns the current migration version
-func GetMigrationVersion(databaseURL string) (uint, bool, error) {
-	db, err := sql.Open("mysql", databaseURL)
+// MigrateTo migrates up or down to a specific version
+func MigrateTo(driver, databaseURL string, version uint) error {
+	db, m, err := newMigrate(driver, databaseURL)
 	if err != nil {
-		return 0, false, fmt.Errorf("failed to open database connection: %w", err)
+		return err
 	}
 	defer db.Close()
 
-	driver, err := mysql.WithInstance(db, &mysql.Config{})
+	if err := m.Migrate(version); err != nil && err != migrate.ErrNoChange {
+		return fmt.Errorf("failed to migrate to version %d: %w", version, err)
+	}
+
+	log.Printf("Migrated to version %d", version)
+	return nil
+}
+
+// ForceMigrationVersion records version as the current one and clears the
+// dirty flag without running any migration. It is used to recover after a
+// failed migration has been fixed by hand; -1 means no migration applied.
+func ForceMigrationVersion(driver, databaseURL string, version int) error {
+	db, m, err := newMigrate(driver, databaseURL)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if err := m.Force(version); err != nil {
+		return fmt.Errorf("failed to force migration version %d: %w", version, err)
+	}
+
+	log.Printf("Forced migration version to %d", version)
+	return nil
+}
+
+// MigrationState is a migration file and whether the database has applied it
+type MigrationState struct {
+	Version uint
+	Name    string
+	Applied bool
+	Dirty   bool // the migration failed partway and needs fixing
+}
+
+// GetMigrationStatus lists the migrations of a driver, oldest first, marking
+// the ones the database has applied
+func GetMigrationStatus(driver, databaseURL string) ([]MigrationState, error) {
+	migrations, err := listMigrations(MigrationsDir(driver))
 	if err != nil {
-		return 0, false, fmt.Errorf("failed to create migration driver: %w", err)
+		return nil, err
 	}
 
-	m, err := migrate.NewWithDatabaseInstance(
-		"file://./migrations",
-		"mysql",
-		driver,
-	)
+	db, m, err := newMigrate(driver, databaseURL)
 	if err != nil {
-		return 0, false, fmt.Errorf("failed to create migrate instance: %w", err)
+		return nil, err
 	}
+	defer db.Close()
 
 	version, dirty, err := m.Version()
-	if err != nil {
-		return 0, false, fmt.Errorf("failed to get migration version: %w", err)
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return nil, fmt.Errorf("failed to get migration version: %w", err)
 	}
 
-	return version, dirty, nil
+	for i := range migrations {
+		// Migrations are applied in order, so everything up to the current
+		// version has been applied
+		migrations[i].Applied = err == nil && migrations[i].Version <= version
+		migrations[i].Dirty = err == nil && dirty && migrations[i].Version == version
+	}
+	return migrations, nil
 }
 
-// RollbackMigration rolls back one migration step
-func RollbackMigration(databaseURL string) error {
-	db, err := sql.Open("mysql", databaseURL)
+// migrationFilePattern matches up files, e.g. 000001_create_urls_table.up.sql
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_(.+)\.up\.sql$`)
+
+// listMigrations reads the migrations in dir, oldest first
+func listMigrations(dir string) ([]MigrationState, error) {
+	entries, err := os.ReadDir(dir)
 	if err != nil {
-		return fmt.Errorf("failed to open database connection: %w", err)
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
 	}
-	defer db.Close()
 
-	driver, err := mysql.WithInstance(db, &mysql.Config{})
+	var migrations []MigrationState
+	for _, entry := range entries {
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+		version, err := strconv.ParseUint(match[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		migrations = append(migrations, MigrationState{Version: uint(version), Name: match[2]})
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// nonWordPattern matches the characters replaced in migration names
+var nonWordPattern = regexp.MustCompile(`[^a-z0-9]+`)
+
+// CreateMigration scaffolds empty up and down files for a new migration,
+// versioned with the UTC timestamp of now. Every driver gets its own pair with
+// the same version so the migration sets stay in step. It returns the
+// created paths.
+func CreateMigration(name string, now time.Time) ([]string, error) {
+	name = strings.Trim(nonWordPattern.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if name == "" {
+		return nil, fmt.Errorf("migration name is required")
+	}
+	version := now.UTC().Format("20060102150405")
+
+	var paths []string
+	for _, driver := range []string{DriverMySQL, DriverPostgres, DriverSQLite} {
+		for _, direction := range []string{"up", "down"} {
+			path := filepath.Join(MigrationsDir(driver), fmt.Sprintf("%s_%s.%s.sql", version, name, direction))
+			file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+			if err != nil {
+				return paths, fmt.Errorf("failed to create migration: %w", err)
+			}
+			file.Close()
+			paths = append(paths, path)
+		}
+	}
+	return paths, nil
+}
+
+// migrationDSN returns the data source name migrations connect with. MySQL
+// only runs migration files holding several statements with multiStatements
+// set, which the application's own connections don't need.
+func migrationDSN(driver, databaseURL string) (string, error) {
+	if driver != DriverMySQL {
+		return databaseURL, nil
+	}
+	cfg, err := gomysql.ParseDSN(databaseURL)
 	if err != nil {
-		return fmt.Errorf("failed to create migration driver: %w", err)
+		return "", fmt.Errorf("invalid MySQL data source name: %w", err)
+	}
+	cfg.MultiStatements = true
+	return cfg.FormatDSN(), nil
+}
+
+// newMigrate connects to the database and creates a migrate instance reading
+// the migration files of its driver. The caller closes the connection.
+func newMigrate(driver, databaseURL string) (*sql.DB, *migrate.Migrate, error) {
+	var sqlDriver string
+	switch driver {
+	case DriverMySQL:
+		sqlDriver = "mysql"
+	case DriverPostgres:
+		sqlDriver = "pgx"
+	case DriverSQLite:
+		sqlDriver = "sqlite3"
+	default:
+		return nil, nil, fmt.Errorf("unsupported database driver %q", driver)
 	}
 
-	m, err := migrate.NewWithDatabaseInstance(
-		"file://./migrations",
-		"mysql",
-		driver,
-	)
+	dsn, err := migrationDSN(driver, databaseURL)
 	if err != nil {
-		return fmt.Errorf("failed to create migrate instance: %w", err)
+		return nil, nil, err
+	}
+	db, err := sql.Open(sqlDriver, dsn)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open database connection: %w", err)
 	}
 
-	if err := m.Steps(-1); err != nil {
-		return fmt.Errorf("failed to rollback migration: %w", err)
+	// Test the connection
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	log.Println("Migration rollback completed successfully")
-	return nil
-} 
\ No newline at end of file
+	var instance migratedb.Driver
+	switch driver {
+	case DriverPostgres:
+		instance, err = pgx.WithInstance(db, &pgx.Config{})
+	case DriverSQLite:
+		instance, err = sqlite3.WithInstance(db, &sqlite3.Config{})
+	default:
+		instance, err = mysql.WithInstance(db, &mysql.Config{})
+	}
+	if err != nil {
+		db.Close()
+		return nil, nil, fmt.Errorf("failed to create migration driver: %w", err)
+	}
+
+	m, err := migrate.NewWithDatabaseInstance("file://"+MigrationsDir(driver), driver, instance)
+	if err != nil {
+		db.Close()
+		return nil, nil, fmt.Errorf("failed to create migrate instance: %w", err)
+	}
+	return db, m, nil
+}
@@ -0,0 +1,215 @@
+// Package googlesheets connects Google accounts with OAuth 2.0 and writes
+// rows to their spreadsheets through the Google Sheets API.
+package googlesheets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Scope lets the application create spreadsheets and edit the ones the user
+// names; it can't read the user's other Drive files
+const Scope = "https://www.googleapis.com/auth/spreadsheets"
+
+var (
+	// ErrRejected is returned when Google answers a request with an error
+	ErrRejected = errors.New("Google rejected the request")
+	// ErrRevoked is returned when the refresh token was revoked or expired;
+	// the user has to connect their account again
+	ErrRevoked = errors.New("Google authorization revoked")
+)
+
+// Options configures the OAuth client of the application
+type Options struct {
+	ClientID     string
+	ClientSecret string
+	// RedirectURL is the callback Google sends users back to with the
+	// authorization code; it must be registered with the client
+	RedirectURL string
+	// AuthURL, TokenURL and APIURL default to Google's endpoints
+	AuthURL  string
+	TokenURL string
+	APIURL   string
+	// Client sends the requests; defaults to a client with a 30 second timeout
+	Client *http.Client
+}
+
+// Token is an OAuth token of a user. Google only returns a refresh token on
+// the first exchange of a consent; refreshes leave it empty.
+type Token struct {
+	AccessToken  string
+	RefreshToken string
+	Expiry       time.Time
+}
+
+// Spreadsheet identifies a spreadsheet
+type Spreadsheet struct {
+	ID  string
+	URL string
+}
+
+// Client authorizes users and writes to their spreadsheets
+type Client struct {
+	opts Options
+	now  func() time.Time
+}
+
+// New validates the options and returns the client
+func New(opts Options) (*Client, error) {
+	if opts.ClientID == "" || opts.ClientSecret == "" {
+		return nil, errors.New("Google Sheets needs an OAuth client ID and secret")
+	}
+	if redirect, err := url.Parse(opts.RedirectURL); err != nil || redirect.Host == "" {
+		return nil, fmt.Errorf("invalid Google OAuth redirect URL %q", opts.RedirectURL)
+	}
+	if opts.AuthURL == "" {
+		opts.AuthURL = "https://accounts.google.com/o/oauth2/v2/auth"
+	}
+	if opts.TokenURL == "" {
+		opts.TokenURL = "https://oauth2.googleapis.com/token"
+	}
+	if opts.APIURL == "" {
+		opts.APIURL = "https://sheets.googleapis.com"
+	}
+	opts.APIURL = strings.TrimRight(opts.APIURL, "/")
+	if opts.Client == nil {
+		opts.Client = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &Client{opts: opts, now: time.Now}, nil
+}
+
+// AuthCodeURL returns the consent page users are sent to. It asks for
+// offline access so a refresh token lets scheduled exports run without them.
+func (c *Client) AuthCodeURL(state string) string {
+	query := url.Values{
+		"client_id":     {c.opts.ClientID},
+		"redirect_uri":  {c.opts.RedirectURL},
+		"response_type": {"code"},
+		"scope":         {Scope},
+		"access_type":   {"offline"},
+		"prompt":        {"consent"},
+		"state":         {state},
+	}
+	return c.opts.AuthURL + "?" + query.Encode()
+}
+
+// Exchange trades the authorization code of the callback for a token
+func (c *Client) Exchange(ctx context.Context, code string) (*Token, error) {
+	return c.token(ctx, url.Values{
+		"grant_type":   {"authorization_code"},
+		"code":         {code},
+		"redirect_uri": {c.opts.RedirectURL},
+	})
+}
+
+// Refresh returns a new access token for a refresh token
+func (c *Client) Refresh(ctx context.Context, refreshToken string) (*Token, error) {
+	return c.token(ctx, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+	})
+}
+
+func (c *Client) token(ctx context.Context, form url.Values) (*Token, error) {
+	form.Set("client_id", c.opts.ClientID)
+	form.Set("client_secret", c.opts.ClientSecret)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.opts.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.opts.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach Google: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresIn    int    `json:"expires_in"`
+		Error        string `json:"error"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body); err != nil {
+		return nil, fmt.Errorf("invalid Google token response: %w", err)
+	}
+	if body.Error == "invalid_grant" {
+		return nil, ErrRevoked
+	}
+	if resp.StatusCode != http.StatusOK || body.AccessToken == "" {
+		return nil, fmt.Errorf("%w: %s: %s", ErrRejected, resp.Status, body.Error)
+	}
+	return &Token{
+		AccessToken:  body.AccessToken,
+		RefreshToken: body.RefreshToken,
+		Expiry:       c.now().Add(time.Duration(body.ExpiresIn) * time.Second),
+	}, nil
+}
+
+// CreateSpreadsheet creates a spreadsheet owned by the user
+func (c *Client) CreateSpreadsheet(ctx context.Context, accessToken, title string) (*Spreadsheet, error) {
+	var created struct {
+		SpreadsheetID  string `json:"spreadsheetId"`
+		SpreadsheetURL string `json:"spreadsheetUrl"`
+	}
+	if err := c.call(ctx, accessToken, http.MethodPost, "/v4/spreadsheets", map[string]interface{}{
+		"properties": map[string]string{"title": title},
+	}, &created); err != nil {
+		return nil, err
+	}
+	return &Spreadsheet{ID: created.SpreadsheetID, URL: created.SpreadsheetURL}, nil
+}
+
+// WriteRows replaces the content of the first sheet of the spreadsheet with rows
+func (c *Client) WriteRows(ctx context.Context, accessToken, spreadsheetID string, rows [][]string) error {
+	// Without a sheet name, ranges refer to the first sheet
+	base := "/v4/spreadsheets/" + url.PathEscape(spreadsheetID) + "/values/"
+	if err := c.call(ctx, accessToken, http.MethodPost, base+"A:ZZ:clear", map[string]interface{}{}, nil); err != nil {
+		return err
+	}
+	return c.call(ctx, accessToken, http.MethodPut, base+"A1?valueInputOption=RAW", map[string]interface{}{
+		"range":          "A1",
+		"majorDimension": "ROWS",
+		"values":         rows,
+	}, nil)
+}
+
+// call sends a JSON request to the Sheets API and decodes the response into out
+func (c *Client) call(ctx context.Context, accessToken, method, path string, in, out interface{}) error {
+	payload, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.opts.APIURL+path, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.opts.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach Google Sheets: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%w: %s: %s", ErrRejected, resp.Status, strings.TrimSpace(string(body)))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("invalid Google Sheets response: %w", err)
+	}
+	return nil
+}
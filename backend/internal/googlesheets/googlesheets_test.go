@@ -0,0 +1,121 @@
+package googlesheets
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	client, err := New(Options{
+		ClientID:     "client",
+		ClientSecret: "secret",
+		RedirectURL:  "https://crawler.example.com/api/v1/integrations/google/callback",
+		TokenURL:     server.URL + "/token",
+		APIURL:       server.URL,
+	})
+	require.NoError(t, err)
+	return client
+}
+
+func TestAuthCodeURL(t *testing.T) {
+	client, err := New(Options{ClientID: "client", ClientSecret: "secret", RedirectURL: "https://crawler.example.com/callback"})
+	require.NoError(t, err)
+
+	consent, err := url.Parse(client.AuthCodeURL("state-1"))
+	require.NoError(t, err)
+	assert.Equal(t, "accounts.google.com", consent.Host)
+	assert.Equal(t, "state-1", consent.Query().Get("state"))
+	assert.Equal(t, "offline", consent.Query().Get("access_type"))
+	assert.Equal(t, Scope, consent.Query().Get("scope"))
+
+	_, err = New(Options{ClientID: "client", ClientSecret: "secret"})
+	assert.Error(t, err)
+}
+
+func TestExchangeAndRefresh(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "secret", r.PostForm.Get("client_secret"))
+		switch r.PostForm.Get("grant_type") {
+		case "authorization_code":
+			assert.Equal(t, "code-1", r.PostForm.Get("code"))
+			w.Write([]byte(`{"access_token": "access-1", "refresh_token": "refresh-1", "expires_in": 3600}`))
+		case "refresh_token":
+			if r.PostForm.Get("refresh_token") != "refresh-1" {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"error": "invalid_grant"}`))
+				return
+			}
+			w.Write([]byte(`{"access_token": "access-2", "expires_in": 3600}`))
+		}
+	})
+	ctx := context.Background()
+
+	token, err := client.Exchange(ctx, "code-1")
+	require.NoError(t, err)
+	assert.Equal(t, "access-1", token.AccessToken)
+	assert.Equal(t, "refresh-1", token.RefreshToken)
+	assert.False(t, token.Expiry.IsZero())
+
+	token, err = client.Refresh(ctx, "refresh-1")
+	require.NoError(t, err)
+	assert.Equal(t, "access-2", token.AccessToken)
+	assert.Empty(t, token.RefreshToken)
+
+	_, err = client.Refresh(ctx, "revoked")
+	assert.ErrorIs(t, err, ErrRevoked)
+}
+
+func TestSpreadsheets(t *testing.T) {
+	var requests []string
+	var written struct {
+		Values [][]string `json:"values"`
+	}
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer access-1", r.Header.Get("Authorization"))
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch {
+		case r.URL.Path == "/v4/spreadsheets":
+			w.Write([]byte(`{"spreadsheetId": "sheet-1", "spreadsheetUrl": "https://docs.google.com/spreadsheets/d/sheet-1/edit"}`))
+		case r.Method == http.MethodPut:
+			assert.Equal(t, "RAW", r.URL.Query().Get("valueInputOption"))
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&written))
+			w.Write([]byte(`{}`))
+		default:
+			w.Write([]byte(`{}`))
+		}
+	})
+	ctx := context.Background()
+
+	spreadsheet, err := client.CreateSpreadsheet(ctx, "access-1", "Broken links")
+	require.NoError(t, err)
+	assert.Equal(t, "sheet-1", spreadsheet.ID)
+
+	rows := [][]string{{"url", "status"}, {"https://example.com", "completed"}}
+	require.NoError(t, client.WriteRows(ctx, "access-1", "sheet-1", rows))
+	assert.Equal(t, []string{
+		"POST /v4/spreadsheets",
+		"POST /v4/spreadsheets/sheet-1/values/A:ZZ:clear",
+		"PUT /v4/spreadsheets/sheet-1/values/A1",
+	}, requests)
+	assert.Equal(t, rows, written.Values)
+}
+
+func TestWriteRows_Rejected(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error": {"status": "PERMISSION_DENIED"}}`, http.StatusForbidden)
+	})
+
+	err := client.WriteRows(context.Background(), "access-1", "someone-elses-sheet", [][]string{{"url"}})
+	assert.ErrorIs(t, err, ErrRejected)
+	assert.Contains(t, err.Error(), "PERMISSION_DENIED")
+}
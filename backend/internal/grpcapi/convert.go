@@ -0,0 +1,97 @@
+package grpcapi
+
+import (
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"web-crawler-backend/internal/grpcapi/crawlerpb"
+	"web-crawler-backend/internal/models"
+)
+
+func urlToProto(url *models.URL) *crawlerpb.URL {
+	msg := &crawlerpb.URL{
+		Id:           uint32(url.ID),
+		Url:          url.URL,
+		Title:        url.Title,
+		HtmlVersion:  url.HTMLVersion,
+		Status:       url.Status,
+		HasLoginForm: url.HasLoginForm,
+		CreatedAt:    timestamp(&url.CreatedAt),
+		UpdatedAt:    timestamp(&url.UpdatedAt),
+	}
+	for i := range url.Crawls {
+		msg.Crawls = append(msg.Crawls, crawlToProto(&url.Crawls[i]))
+	}
+	return msg
+}
+
+func crawlToProto(crawl *models.Crawl) *crawlerpb.Crawl {
+	return &crawlerpb.Crawl{
+		Id:            uint32(crawl.ID),
+		UrlId:         uint32(crawl.URLID),
+		Status:        crawl.Status,
+		StartedAt:     timestamp(crawl.StartedAt),
+		CompletedAt:   timestamp(crawl.CompletedAt),
+		ErrorMessage:  crawl.ErrorMessage,
+		Title:         crawl.Title,
+		InternalLinks: int32(crawl.InternalLinks),
+		ExternalLinks: int32(crawl.ExternalLinks),
+		BrokenLinks:   int32(crawl.BrokenLinks),
+		PagesCrawled:  int32(crawl.PagesCrawled),
+		CreatedAt:     timestamp(&crawl.CreatedAt),
+	}
+}
+
+func linkToProto(link *models.Link) *crawlerpb.Link {
+	return &crawlerpb.Link{
+		Id:           uint32(link.ID),
+		UrlId:        uint32(link.URLID),
+		CrawlId:      uint32(link.CrawlID),
+		LinkUrl:      link.LinkURL,
+		LinkText:     link.LinkText,
+		LinkType:     link.LinkType,
+		StatusCode:   int32(link.StatusCode),
+		IsAccessible: link.IsAccessible,
+		Context:      link.Context,
+		CreatedAt:    timestamp(&link.CreatedAt),
+	}
+}
+
+// crawlStatusToProto converts a crawl status. The status response carries the
+// crawl ID, or the URL ID before the first crawl, so the URL ID is passed in.
+func crawlStatusToProto(urlID uint32, crawlStatus *models.CrawlStatusResponse) *crawlerpb.CrawlStatus {
+	msg := &crawlerpb.CrawlStatus{
+		UrlId:         urlID,
+		Url:           crawlStatus.URL,
+		Status:        crawlStatus.Status,
+		InternalLinks: int32(crawlStatus.InternalLinks),
+		ExternalLinks: int32(crawlStatus.ExternalLinks),
+		BrokenLinks:   int32(crawlStatus.BrokenLinks),
+		StartedAt:     timestamp(crawlStatus.StartedAt),
+		CompletedAt:   timestamp(crawlStatus.CompletedAt),
+		ErrorMessage:  crawlStatus.ErrorMessage,
+	}
+	if crawlStatus.Status != "pending" {
+		msg.CrawlId = uint32(crawlStatus.ID)
+	}
+	if counts := crawlStatus.HeadingCounts; counts != nil {
+		msg.HeadingCounts = &crawlerpb.HeadingCounts{
+			H1: int32(counts.H1),
+			H2: int32(counts.H2),
+			H3: int32(counts.H3),
+			H4: int32(counts.H4),
+			H5: int32(counts.H5),
+			H6: int32(counts.H6),
+		}
+	}
+	return msg
+}
+
+// timestamp converts optional and zero times to an unset timestamp
+func timestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil || t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
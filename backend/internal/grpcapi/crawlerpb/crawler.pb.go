@@ -0,0 +1,1173 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.29.3
+// source: crawler/v1/crawler.proto
+
+package crawlerpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type URL struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Url         string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	Title       string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	HtmlVersion string                 `protobuf:"bytes,4,opt,name=html_version,json=htmlVersion,proto3" json:"html_version,omitempty"`
+	// pending, running, completed, skipped or error
+	Status        string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	HasLoginForm  bool                   `protobuf:"varint,6,opt,name=has_login_form,json=hasLoginForm,proto3" json:"has_login_form,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Crawls        []*Crawl               `protobuf:"bytes,9,rep,name=crawls,proto3" json:"crawls,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *URL) Reset() {
+	*x = URL{}
+	mi := &file_crawler_v1_crawler_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *URL) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*URL) ProtoMessage() {}
+
+func (x *URL) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_v1_crawler_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use URL.ProtoReflect.Descriptor instead.
+func (*URL) Descriptor() ([]byte, []int) {
+	return file_crawler_v1_crawler_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *URL) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *URL) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *URL) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *URL) GetHtmlVersion() string {
+	if x != nil {
+		return x.HtmlVersion
+	}
+	return ""
+}
+
+func (x *URL) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *URL) GetHasLoginForm() bool {
+	if x != nil {
+		return x.HasLoginForm
+	}
+	return false
+}
+
+func (x *URL) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *URL) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *URL) GetCrawls() []*Crawl {
+	if x != nil {
+		return x.Crawls
+	}
+	return nil
+}
+
+type Crawl struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	UrlId uint32                 `protobuf:"varint,2,opt,name=url_id,json=urlId,proto3" json:"url_id,omitempty"`
+	// queued, running, completed, skipped or error
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	StartedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	CompletedAt   *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+	ErrorMessage  string                 `protobuf:"bytes,6,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	Title         string                 `protobuf:"bytes,7,opt,name=title,proto3" json:"title,omitempty"`
+	InternalLinks int32                  `protobuf:"varint,8,opt,name=internal_links,json=internalLinks,proto3" json:"internal_links,omitempty"`
+	ExternalLinks int32                  `protobuf:"varint,9,opt,name=external_links,json=externalLinks,proto3" json:"external_links,omitempty"`
+	BrokenLinks   int32                  `protobuf:"varint,10,opt,name=broken_links,json=brokenLinks,proto3" json:"broken_links,omitempty"`
+	PagesCrawled  int32                  `protobuf:"varint,11,opt,name=pages_crawled,json=pagesCrawled,proto3" json:"pages_crawled,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Crawl) Reset() {
+	*x = Crawl{}
+	mi := &file_crawler_v1_crawler_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Crawl) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Crawl) ProtoMessage() {}
+
+func (x *Crawl) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_v1_crawler_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Crawl.ProtoReflect.Descriptor instead.
+func (*Crawl) Descriptor() ([]byte, []int) {
+	return file_crawler_v1_crawler_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Crawl) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Crawl) GetUrlId() uint32 {
+	if x != nil {
+		return x.UrlId
+	}
+	return 0
+}
+
+func (x *Crawl) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Crawl) GetStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartedAt
+	}
+	return nil
+}
+
+func (x *Crawl) GetCompletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CompletedAt
+	}
+	return nil
+}
+
+func (x *Crawl) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+func (x *Crawl) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Crawl) GetInternalLinks() int32 {
+	if x != nil {
+		return x.InternalLinks
+	}
+	return 0
+}
+
+func (x *Crawl) GetExternalLinks() int32 {
+	if x != nil {
+		return x.ExternalLinks
+	}
+	return 0
+}
+
+func (x *Crawl) GetBrokenLinks() int32 {
+	if x != nil {
+		return x.BrokenLinks
+	}
+	return 0
+}
+
+func (x *Crawl) GetPagesCrawled() int32 {
+	if x != nil {
+		return x.PagesCrawled
+	}
+	return 0
+}
+
+func (x *Crawl) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type Link struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Id       uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	UrlId    uint32                 `protobuf:"varint,2,opt,name=url_id,json=urlId,proto3" json:"url_id,omitempty"`
+	CrawlId  uint32                 `protobuf:"varint,3,opt,name=crawl_id,json=crawlId,proto3" json:"crawl_id,omitempty"`
+	LinkUrl  string                 `protobuf:"bytes,4,opt,name=link_url,json=linkUrl,proto3" json:"link_url,omitempty"`
+	LinkText string                 `protobuf:"bytes,5,opt,name=link_text,json=linkText,proto3" json:"link_text,omitempty"`
+	// internal or external
+	LinkType     string `protobuf:"bytes,6,opt,name=link_type,json=linkType,proto3" json:"link_type,omitempty"`
+	StatusCode   int32  `protobuf:"varint,7,opt,name=status_code,json=statusCode,proto3" json:"status_code,omitempty"`
+	IsAccessible bool   `protobuf:"varint,8,opt,name=is_accessible,json=isAccessible,proto3" json:"is_accessible,omitempty"`
+	// nav, footer or content
+	Context       string                 `protobuf:"bytes,9,opt,name=context,proto3" json:"context,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Link) Reset() {
+	*x = Link{}
+	mi := &file_crawler_v1_crawler_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Link) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Link) ProtoMessage() {}
+
+func (x *Link) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_v1_crawler_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Link.ProtoReflect.Descriptor instead.
+func (*Link) Descriptor() ([]byte, []int) {
+	return file_crawler_v1_crawler_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Link) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Link) GetUrlId() uint32 {
+	if x != nil {
+		return x.UrlId
+	}
+	return 0
+}
+
+func (x *Link) GetCrawlId() uint32 {
+	if x != nil {
+		return x.CrawlId
+	}
+	return 0
+}
+
+func (x *Link) GetLinkUrl() string {
+	if x != nil {
+		return x.LinkUrl
+	}
+	return ""
+}
+
+func (x *Link) GetLinkText() string {
+	if x != nil {
+		return x.LinkText
+	}
+	return ""
+}
+
+func (x *Link) GetLinkType() string {
+	if x != nil {
+		return x.LinkType
+	}
+	return ""
+}
+
+func (x *Link) GetStatusCode() int32 {
+	if x != nil {
+		return x.StatusCode
+	}
+	return 0
+}
+
+func (x *Link) GetIsAccessible() bool {
+	if x != nil {
+		return x.IsAccessible
+	}
+	return false
+}
+
+func (x *Link) GetContext() string {
+	if x != nil {
+		return x.Context
+	}
+	return ""
+}
+
+func (x *Link) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type HeadingCounts struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	H1            int32                  `protobuf:"varint,1,opt,name=h1,proto3" json:"h1,omitempty"`
+	H2            int32                  `protobuf:"varint,2,opt,name=h2,proto3" json:"h2,omitempty"`
+	H3            int32                  `protobuf:"varint,3,opt,name=h3,proto3" json:"h3,omitempty"`
+	H4            int32                  `protobuf:"varint,4,opt,name=h4,proto3" json:"h4,omitempty"`
+	H5            int32                  `protobuf:"varint,5,opt,name=h5,proto3" json:"h5,omitempty"`
+	H6            int32                  `protobuf:"varint,6,opt,name=h6,proto3" json:"h6,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HeadingCounts) Reset() {
+	*x = HeadingCounts{}
+	mi := &file_crawler_v1_crawler_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HeadingCounts) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HeadingCounts) ProtoMessage() {}
+
+func (x *HeadingCounts) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_v1_crawler_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HeadingCounts.ProtoReflect.Descriptor instead.
+func (*HeadingCounts) Descriptor() ([]byte, []int) {
+	return file_crawler_v1_crawler_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *HeadingCounts) GetH1() int32 {
+	if x != nil {
+		return x.H1
+	}
+	return 0
+}
+
+func (x *HeadingCounts) GetH2() int32 {
+	if x != nil {
+		return x.H2
+	}
+	return 0
+}
+
+func (x *HeadingCounts) GetH3() int32 {
+	if x != nil {
+		return x.H3
+	}
+	return 0
+}
+
+func (x *HeadingCounts) GetH4() int32 {
+	if x != nil {
+		return x.H4
+	}
+	return 0
+}
+
+func (x *HeadingCounts) GetH5() int32 {
+	if x != nil {
+		return x.H5
+	}
+	return 0
+}
+
+func (x *HeadingCounts) GetH6() int32 {
+	if x != nil {
+		return x.H6
+	}
+	return 0
+}
+
+type CrawlStatus struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	UrlId uint32                 `protobuf:"varint,1,opt,name=url_id,json=urlId,proto3" json:"url_id,omitempty"`
+	// Zero until the URL has been crawled
+	CrawlId       uint32                 `protobuf:"varint,2,opt,name=crawl_id,json=crawlId,proto3" json:"crawl_id,omitempty"`
+	Url           string                 `protobuf:"bytes,3,opt,name=url,proto3" json:"url,omitempty"`
+	Status        string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	InternalLinks int32                  `protobuf:"varint,5,opt,name=internal_links,json=internalLinks,proto3" json:"internal_links,omitempty"`
+	ExternalLinks int32                  `protobuf:"varint,6,opt,name=external_links,json=externalLinks,proto3" json:"external_links,omitempty"`
+	BrokenLinks   int32                  `protobuf:"varint,7,opt,name=broken_links,json=brokenLinks,proto3" json:"broken_links,omitempty"`
+	HeadingCounts *HeadingCounts         `protobuf:"bytes,8,opt,name=heading_counts,json=headingCounts,proto3" json:"heading_counts,omitempty"`
+	StartedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	CompletedAt   *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+	ErrorMessage  string                 `protobuf:"bytes,11,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CrawlStatus) Reset() {
+	*x = CrawlStatus{}
+	mi := &file_crawler_v1_crawler_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CrawlStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CrawlStatus) ProtoMessage() {}
+
+func (x *CrawlStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_v1_crawler_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CrawlStatus.ProtoReflect.Descriptor instead.
+func (*CrawlStatus) Descriptor() ([]byte, []int) {
+	return file_crawler_v1_crawler_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CrawlStatus) GetUrlId() uint32 {
+	if x != nil {
+		return x.UrlId
+	}
+	return 0
+}
+
+func (x *CrawlStatus) GetCrawlId() uint32 {
+	if x != nil {
+		return x.CrawlId
+	}
+	return 0
+}
+
+func (x *CrawlStatus) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *CrawlStatus) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *CrawlStatus) GetInternalLinks() int32 {
+	if x != nil {
+		return x.InternalLinks
+	}
+	return 0
+}
+
+func (x *CrawlStatus) GetExternalLinks() int32 {
+	if x != nil {
+		return x.ExternalLinks
+	}
+	return 0
+}
+
+func (x *CrawlStatus) GetBrokenLinks() int32 {
+	if x != nil {
+		return x.BrokenLinks
+	}
+	return 0
+}
+
+func (x *CrawlStatus) GetHeadingCounts() *HeadingCounts {
+	if x != nil {
+		return x.HeadingCounts
+	}
+	return nil
+}
+
+func (x *CrawlStatus) GetStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartedAt
+	}
+	return nil
+}
+
+func (x *CrawlStatus) GetCompletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CompletedAt
+	}
+	return nil
+}
+
+func (x *CrawlStatus) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+type SubmitURLRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitURLRequest) Reset() {
+	*x = SubmitURLRequest{}
+	mi := &file_crawler_v1_crawler_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitURLRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitURLRequest) ProtoMessage() {}
+
+func (x *SubmitURLRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_v1_crawler_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitURLRequest.ProtoReflect.Descriptor instead.
+func (*SubmitURLRequest) Descriptor() ([]byte, []int) {
+	return file_crawler_v1_crawler_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SubmitURLRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+type GetURLRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetURLRequest) Reset() {
+	*x = GetURLRequest{}
+	mi := &file_crawler_v1_crawler_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetURLRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetURLRequest) ProtoMessage() {}
+
+func (x *GetURLRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_v1_crawler_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetURLRequest.ProtoReflect.Descriptor instead.
+func (*GetURLRequest) Descriptor() ([]byte, []int) {
+	return file_crawler_v1_crawler_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetURLRequest) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type StartCrawlRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UrlId         uint32                 `protobuf:"varint,1,opt,name=url_id,json=urlId,proto3" json:"url_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartCrawlRequest) Reset() {
+	*x = StartCrawlRequest{}
+	mi := &file_crawler_v1_crawler_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartCrawlRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartCrawlRequest) ProtoMessage() {}
+
+func (x *StartCrawlRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_v1_crawler_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartCrawlRequest.ProtoReflect.Descriptor instead.
+func (*StartCrawlRequest) Descriptor() ([]byte, []int) {
+	return file_crawler_v1_crawler_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *StartCrawlRequest) GetUrlId() uint32 {
+	if x != nil {
+		return x.UrlId
+	}
+	return 0
+}
+
+type StartCrawlResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UrlId         uint32                 `protobuf:"varint,1,opt,name=url_id,json=urlId,proto3" json:"url_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartCrawlResponse) Reset() {
+	*x = StartCrawlResponse{}
+	mi := &file_crawler_v1_crawler_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartCrawlResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartCrawlResponse) ProtoMessage() {}
+
+func (x *StartCrawlResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_v1_crawler_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartCrawlResponse.ProtoReflect.Descriptor instead.
+func (*StartCrawlResponse) Descriptor() ([]byte, []int) {
+	return file_crawler_v1_crawler_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *StartCrawlResponse) GetUrlId() uint32 {
+	if x != nil {
+		return x.UrlId
+	}
+	return 0
+}
+
+type GetCrawlStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UrlId         uint32                 `protobuf:"varint,1,opt,name=url_id,json=urlId,proto3" json:"url_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCrawlStatusRequest) Reset() {
+	*x = GetCrawlStatusRequest{}
+	mi := &file_crawler_v1_crawler_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCrawlStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCrawlStatusRequest) ProtoMessage() {}
+
+func (x *GetCrawlStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_v1_crawler_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCrawlStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetCrawlStatusRequest) Descriptor() ([]byte, []int) {
+	return file_crawler_v1_crawler_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetCrawlStatusRequest) GetUrlId() uint32 {
+	if x != nil {
+		return x.UrlId
+	}
+	return 0
+}
+
+type WatchCrawlStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UrlId         uint32                 `protobuf:"varint,1,opt,name=url_id,json=urlId,proto3" json:"url_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchCrawlStatusRequest) Reset() {
+	*x = WatchCrawlStatusRequest{}
+	mi := &file_crawler_v1_crawler_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchCrawlStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchCrawlStatusRequest) ProtoMessage() {}
+
+func (x *WatchCrawlStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_v1_crawler_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchCrawlStatusRequest.ProtoReflect.Descriptor instead.
+func (*WatchCrawlStatusRequest) Descriptor() ([]byte, []int) {
+	return file_crawler_v1_crawler_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *WatchCrawlStatusRequest) GetUrlId() uint32 {
+	if x != nil {
+		return x.UrlId
+	}
+	return 0
+}
+
+type ListLinksRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	UrlId uint32                 `protobuf:"varint,1,opt,name=url_id,json=urlId,proto3" json:"url_id,omitempty"`
+	// Same filters as GET /urls/{id}/links, e.g. internal, external or broken;
+	// empty lists all links
+	LinkType string `protobuf:"bytes,2,opt,name=link_type,json=linkType,proto3" json:"link_type,omitempty"`
+	// At most 100, defaults to 10
+	PageSize int32 `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// next_page_token of the previous page
+	PageToken     string `protobuf:"bytes,4,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLinksRequest) Reset() {
+	*x = ListLinksRequest{}
+	mi := &file_crawler_v1_crawler_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLinksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLinksRequest) ProtoMessage() {}
+
+func (x *ListLinksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_v1_crawler_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLinksRequest.ProtoReflect.Descriptor instead.
+func (*ListLinksRequest) Descriptor() ([]byte, []int) {
+	return file_crawler_v1_crawler_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListLinksRequest) GetUrlId() uint32 {
+	if x != nil {
+		return x.UrlId
+	}
+	return 0
+}
+
+func (x *ListLinksRequest) GetLinkType() string {
+	if x != nil {
+		return x.LinkType
+	}
+	return ""
+}
+
+func (x *ListLinksRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListLinksRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListLinksResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Links []*Link                `protobuf:"bytes,1,rep,name=links,proto3" json:"links,omitempty"`
+	Total int64                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	// Empty on the last page
+	NextPageToken string `protobuf:"bytes,3,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLinksResponse) Reset() {
+	*x = ListLinksResponse{}
+	mi := &file_crawler_v1_crawler_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLinksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLinksResponse) ProtoMessage() {}
+
+func (x *ListLinksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_v1_crawler_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLinksResponse.ProtoReflect.Descriptor instead.
+func (*ListLinksResponse) Descriptor() ([]byte, []int) {
+	return file_crawler_v1_crawler_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListLinksResponse) GetLinks() []*Link {
+	if x != nil {
+		return x.Links
+	}
+	return nil
+}
+
+func (x *ListLinksResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *ListLinksResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+var File_crawler_v1_crawler_proto protoreflect.FileDescriptor
+
+const file_crawler_v1_crawler_proto_rawDesc = "" +
+	"\n" +
+	"\x18crawler/v1/crawler.proto\x12\n" +
+	"crawler.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xbf\x02\n" +
+	"\x03URL\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\rR\x02id\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12\x14\n" +
+	"\x05title\x18\x03 \x01(\tR\x05title\x12!\n" +
+	"\fhtml_version\x18\x04 \x01(\tR\vhtmlVersion\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12$\n" +
+	"\x0ehas_login_form\x18\x06 \x01(\bR\fhasLoginForm\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12)\n" +
+	"\x06crawls\x18\t \x03(\v2\x11.crawler.v1.CrawlR\x06crawls\"\xcc\x03\n" +
+	"\x05Crawl\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\rR\x02id\x12\x15\n" +
+	"\x06url_id\x18\x02 \x01(\rR\x05urlId\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x129\n" +
+	"\n" +
+	"started_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tstartedAt\x12=\n" +
+	"\fcompleted_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\vcompletedAt\x12#\n" +
+	"\rerror_message\x18\x06 \x01(\tR\ferrorMessage\x12\x14\n" +
+	"\x05title\x18\a \x01(\tR\x05title\x12%\n" +
+	"\x0einternal_links\x18\b \x01(\x05R\rinternalLinks\x12%\n" +
+	"\x0eexternal_links\x18\t \x01(\x05R\rexternalLinks\x12!\n" +
+	"\fbroken_links\x18\n" +
+	" \x01(\x05R\vbrokenLinks\x12#\n" +
+	"\rpages_crawled\x18\v \x01(\x05R\fpagesCrawled\x129\n" +
+	"\n" +
+	"created_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\xb8\x02\n" +
+	"\x04Link\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\rR\x02id\x12\x15\n" +
+	"\x06url_id\x18\x02 \x01(\rR\x05urlId\x12\x19\n" +
+	"\bcrawl_id\x18\x03 \x01(\rR\acrawlId\x12\x19\n" +
+	"\blink_url\x18\x04 \x01(\tR\alinkUrl\x12\x1b\n" +
+	"\tlink_text\x18\x05 \x01(\tR\blinkText\x12\x1b\n" +
+	"\tlink_type\x18\x06 \x01(\tR\blinkType\x12\x1f\n" +
+	"\vstatus_code\x18\a \x01(\x05R\n" +
+	"statusCode\x12#\n" +
+	"\ris_accessible\x18\b \x01(\bR\fisAccessible\x12\x18\n" +
+	"\acontext\x18\t \x01(\tR\acontext\x129\n" +
+	"\n" +
+	"created_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"o\n" +
+	"\rHeadingCounts\x12\x0e\n" +
+	"\x02h1\x18\x01 \x01(\x05R\x02h1\x12\x0e\n" +
+	"\x02h2\x18\x02 \x01(\x05R\x02h2\x12\x0e\n" +
+	"\x02h3\x18\x03 \x01(\x05R\x02h3\x12\x0e\n" +
+	"\x02h4\x18\x04 \x01(\x05R\x02h4\x12\x0e\n" +
+	"\x02h5\x18\x05 \x01(\x05R\x02h5\x12\x0e\n" +
+	"\x02h6\x18\x06 \x01(\x05R\x02h6\"\xbb\x03\n" +
+	"\vCrawlStatus\x12\x15\n" +
+	"\x06url_id\x18\x01 \x01(\rR\x05urlId\x12\x19\n" +
+	"\bcrawl_id\x18\x02 \x01(\rR\acrawlId\x12\x10\n" +
+	"\x03url\x18\x03 \x01(\tR\x03url\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12%\n" +
+	"\x0einternal_links\x18\x05 \x01(\x05R\rinternalLinks\x12%\n" +
+	"\x0eexternal_links\x18\x06 \x01(\x05R\rexternalLinks\x12!\n" +
+	"\fbroken_links\x18\a \x01(\x05R\vbrokenLinks\x12@\n" +
+	"\x0eheading_counts\x18\b \x01(\v2\x19.crawler.v1.HeadingCountsR\rheadingCounts\x129\n" +
+	"\n" +
+	"started_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tstartedAt\x12=\n" +
+	"\fcompleted_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\vcompletedAt\x12#\n" +
+	"\rerror_message\x18\v \x01(\tR\ferrorMessage\"$\n" +
+	"\x10SubmitURLRequest\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\"\x1f\n" +
+	"\rGetURLRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\rR\x02id\"*\n" +
+	"\x11StartCrawlRequest\x12\x15\n" +
+	"\x06url_id\x18\x01 \x01(\rR\x05urlId\"+\n" +
+	"\x12StartCrawlResponse\x12\x15\n" +
+	"\x06url_id\x18\x01 \x01(\rR\x05urlId\".\n" +
+	"\x15GetCrawlStatusRequest\x12\x15\n" +
+	"\x06url_id\x18\x01 \x01(\rR\x05urlId\"0\n" +
+	"\x17WatchCrawlStatusRequest\x12\x15\n" +
+	"\x06url_id\x18\x01 \x01(\rR\x05urlId\"\x82\x01\n" +
+	"\x10ListLinksRequest\x12\x15\n" +
+	"\x06url_id\x18\x01 \x01(\rR\x05urlId\x12\x1b\n" +
+	"\tlink_type\x18\x02 \x01(\tR\blinkType\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x04 \x01(\tR\tpageToken\"y\n" +
+	"\x11ListLinksResponse\x12&\n" +
+	"\x05links\x18\x01 \x03(\v2\x10.crawler.v1.LinkR\x05links\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x03R\x05total\x12&\n" +
+	"\x0fnext_page_token\x18\x03 \x01(\tR\rnextPageToken2\xbb\x03\n" +
+	"\x0eCrawlerService\x12:\n" +
+	"\tSubmitURL\x12\x1c.crawler.v1.SubmitURLRequest\x1a\x0f.crawler.v1.URL\x124\n" +
+	"\x06GetURL\x12\x19.crawler.v1.GetURLRequest\x1a\x0f.crawler.v1.URL\x12K\n" +
+	"\n" +
+	"StartCrawl\x12\x1d.crawler.v1.StartCrawlRequest\x1a\x1e.crawler.v1.StartCrawlResponse\x12L\n" +
+	"\x0eGetCrawlStatus\x12!.crawler.v1.GetCrawlStatusRequest\x1a\x17.crawler.v1.CrawlStatus\x12R\n" +
+	"\x10WatchCrawlStatus\x12#.crawler.v1.WatchCrawlStatusRequest\x1a\x17.crawler.v1.CrawlStatus0\x01\x12H\n" +
+	"\tListLinks\x12\x1c.crawler.v1.ListLinksRequest\x1a\x1d.crawler.v1.ListLinksResponseB0Z.web-crawler-backend/internal/grpcapi/crawlerpbb\x06proto3"
+
+var (
+	file_crawler_v1_crawler_proto_rawDescOnce sync.Once
+	file_crawler_v1_crawler_proto_rawDescData []byte
+)
+
+func file_crawler_v1_crawler_proto_rawDescGZIP() []byte {
+	file_crawler_v1_crawler_proto_rawDescOnce.Do(func() {
+		file_crawler_v1_crawler_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_crawler_v1_crawler_proto_rawDesc), len(file_crawler_v1_crawler_proto_rawDesc)))
+	})
+	return file_crawler_v1_crawler_proto_rawDescData
+}
+
+var file_crawler_v1_crawler_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_crawler_v1_crawler_proto_goTypes = []any{
+	(*URL)(nil),                     // 0: crawler.v1.URL
+	(*Crawl)(nil),                   // 1: crawler.v1.Crawl
+	(*Link)(nil),                    // 2: crawler.v1.Link
+	(*HeadingCounts)(nil),           // 3: crawler.v1.HeadingCounts
+	(*CrawlStatus)(nil),             // 4: crawler.v1.CrawlStatus
+	(*SubmitURLRequest)(nil),        // 5: crawler.v1.SubmitURLRequest
+	(*GetURLRequest)(nil),           // 6: crawler.v1.GetURLRequest
+	(*StartCrawlRequest)(nil),       // 7: crawler.v1.StartCrawlRequest
+	(*StartCrawlResponse)(nil),      // 8: crawler.v1.StartCrawlResponse
+	(*GetCrawlStatusRequest)(nil),   // 9: crawler.v1.GetCrawlStatusRequest
+	(*WatchCrawlStatusRequest)(nil), // 10: crawler.v1.WatchCrawlStatusRequest
+	(*ListLinksRequest)(nil),        // 11: crawler.v1.ListLinksRequest
+	(*ListLinksResponse)(nil),       // 12: crawler.v1.ListLinksResponse
+	(*timestamppb.Timestamp)(nil),   // 13: google.protobuf.Timestamp
+}
+var file_crawler_v1_crawler_proto_depIdxs = []int32{
+	13, // 0: crawler.v1.URL.created_at:type_name -> google.protobuf.Timestamp
+	13, // 1: crawler.v1.URL.updated_at:type_name -> google.protobuf.Timestamp
+	1,  // 2: crawler.v1.URL.crawls:type_name -> crawler.v1.Crawl
+	13, // 3: crawler.v1.Crawl.started_at:type_name -> google.protobuf.Timestamp
+	13, // 4: crawler.v1.Crawl.completed_at:type_name -> google.protobuf.Timestamp
+	13, // 5: crawler.v1.Crawl.created_at:type_name -> google.protobuf.Timestamp
+	13, // 6: crawler.v1.Link.created_at:type_name -> google.protobuf.Timestamp
+	3,  // 7: crawler.v1.CrawlStatus.heading_counts:type_name -> crawler.v1.HeadingCounts
+	13, // 8: crawler.v1.CrawlStatus.started_at:type_name -> google.protobuf.Timestamp
+	13, // 9: crawler.v1.CrawlStatus.completed_at:type_name -> google.protobuf.Timestamp
+	2,  // 10: crawler.v1.ListLinksResponse.links:type_name -> crawler.v1.Link
+	5,  // 11: crawler.v1.CrawlerService.SubmitURL:input_type -> crawler.v1.SubmitURLRequest
+	6,  // 12: crawler.v1.CrawlerService.GetURL:input_type -> crawler.v1.GetURLRequest
+	7,  // 13: crawler.v1.CrawlerService.StartCrawl:input_type -> crawler.v1.StartCrawlRequest
+	9,  // 14: crawler.v1.CrawlerService.GetCrawlStatus:input_type -> crawler.v1.GetCrawlStatusRequest
+	10, // 15: crawler.v1.CrawlerService.WatchCrawlStatus:input_type -> crawler.v1.WatchCrawlStatusRequest
+	11, // 16: crawler.v1.CrawlerService.ListLinks:input_type -> crawler.v1.ListLinksRequest
+	0,  // 17: crawler.v1.CrawlerService.SubmitURL:output_type -> crawler.v1.URL
+	0,  // 18: crawler.v1.CrawlerService.GetURL:output_type -> crawler.v1.URL
+	8,  // 19: crawler.v1.CrawlerService.StartCrawl:output_type -> crawler.v1.StartCrawlResponse
+	4,  // 20: crawler.v1.CrawlerService.GetCrawlStatus:output_type -> crawler.v1.CrawlStatus
+	4,  // 21: crawler.v1.CrawlerService.WatchCrawlStatus:output_type -> crawler.v1.CrawlStatus
+	12, // 22: crawler.v1.CrawlerService.ListLinks:output_type -> crawler.v1.ListLinksResponse
+	17, // [17:23] is the sub-list for method output_type
+	11, // [11:17] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
+}
+
+func init() { file_crawler_v1_crawler_proto_init() }
+func file_crawler_v1_crawler_proto_init() {
+	if File_crawler_v1_crawler_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_crawler_v1_crawler_proto_rawDesc), len(file_crawler_v1_crawler_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   13,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_crawler_v1_crawler_proto_goTypes,
+		DependencyIndexes: file_crawler_v1_crawler_proto_depIdxs,
+		MessageInfos:      file_crawler_v1_crawler_proto_msgTypes,
+	}.Build()
+	File_crawler_v1_crawler_proto = out.File
+	file_crawler_v1_crawler_proto_goTypes = nil
+	file_crawler_v1_crawler_proto_depIdxs = nil
+}
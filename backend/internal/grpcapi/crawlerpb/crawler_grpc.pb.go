@@ -0,0 +1,337 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: crawler/v1/crawler.proto
+
+package crawlerpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	CrawlerService_SubmitURL_FullMethodName        = "/crawler.v1.CrawlerService/SubmitURL"
+	CrawlerService_GetURL_FullMethodName           = "/crawler.v1.CrawlerService/GetURL"
+	CrawlerService_StartCrawl_FullMethodName       = "/crawler.v1.CrawlerService/StartCrawl"
+	CrawlerService_GetCrawlStatus_FullMethodName   = "/crawler.v1.CrawlerService/GetCrawlStatus"
+	CrawlerService_WatchCrawlStatus_FullMethodName = "/crawler.v1.CrawlerService/WatchCrawlStatus"
+	CrawlerService_ListLinks_FullMethodName        = "/crawler.v1.CrawlerService/ListLinks"
+)
+
+// CrawlerServiceClient is the client API for CrawlerService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// CrawlerService lets internal services submit URLs, start crawls and follow
+// their progress without going through the JSON API. Every call needs an
+// "authorization: Bearer <token>" metadata entry with a token from /auth/login.
+type CrawlerServiceClient interface {
+	// SubmitURL adds a URL, or restores a deleted one, and starts crawling it
+	SubmitURL(ctx context.Context, in *SubmitURLRequest, opts ...grpc.CallOption) (*URL, error)
+	// GetURL returns a URL with its crawls
+	GetURL(ctx context.Context, in *GetURLRequest, opts ...grpc.CallOption) (*URL, error)
+	// StartCrawl crawls a URL again
+	StartCrawl(ctx context.Context, in *StartCrawlRequest, opts ...grpc.CallOption) (*StartCrawlResponse, error)
+	// GetCrawlStatus returns the status of the latest crawl of a URL
+	GetCrawlStatus(ctx context.Context, in *GetCrawlStatusRequest, opts ...grpc.CallOption) (*CrawlStatus, error)
+	// WatchCrawlStatus streams the crawl status of a URL whenever it changes,
+	// and ends once the crawl has finished
+	WatchCrawlStatus(ctx context.Context, in *WatchCrawlStatusRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[CrawlStatus], error)
+	// ListLinks pages through the links found on a URL, newest first
+	ListLinks(ctx context.Context, in *ListLinksRequest, opts ...grpc.CallOption) (*ListLinksResponse, error)
+}
+
+type crawlerServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCrawlerServiceClient(cc grpc.ClientConnInterface) CrawlerServiceClient {
+	return &crawlerServiceClient{cc}
+}
+
+func (c *crawlerServiceClient) SubmitURL(ctx context.Context, in *SubmitURLRequest, opts ...grpc.CallOption) (*URL, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(URL)
+	err := c.cc.Invoke(ctx, CrawlerService_SubmitURL_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *crawlerServiceClient) GetURL(ctx context.Context, in *GetURLRequest, opts ...grpc.CallOption) (*URL, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(URL)
+	err := c.cc.Invoke(ctx, CrawlerService_GetURL_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *crawlerServiceClient) StartCrawl(ctx context.Context, in *StartCrawlRequest, opts ...grpc.CallOption) (*StartCrawlResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StartCrawlResponse)
+	err := c.cc.Invoke(ctx, CrawlerService_StartCrawl_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *crawlerServiceClient) GetCrawlStatus(ctx context.Context, in *GetCrawlStatusRequest, opts ...grpc.CallOption) (*CrawlStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CrawlStatus)
+	err := c.cc.Invoke(ctx, CrawlerService_GetCrawlStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *crawlerServiceClient) WatchCrawlStatus(ctx context.Context, in *WatchCrawlStatusRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[CrawlStatus], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &CrawlerService_ServiceDesc.Streams[0], CrawlerService_WatchCrawlStatus_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchCrawlStatusRequest, CrawlStatus]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CrawlerService_WatchCrawlStatusClient = grpc.ServerStreamingClient[CrawlStatus]
+
+func (c *crawlerServiceClient) ListLinks(ctx context.Context, in *ListLinksRequest, opts ...grpc.CallOption) (*ListLinksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListLinksResponse)
+	err := c.cc.Invoke(ctx, CrawlerService_ListLinks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CrawlerServiceServer is the server API for CrawlerService service.
+// All implementations must embed UnimplementedCrawlerServiceServer
+// for forward compatibility.
+//
+// CrawlerService lets internal services submit URLs, start crawls and follow
+// their progress without going through the JSON API. Every call needs an
+// "authorization: Bearer <token>" metadata entry with a token from /auth/login.
+type CrawlerServiceServer interface {
+	// SubmitURL adds a URL, or restores a deleted one, and starts crawling it
+	SubmitURL(context.Context, *SubmitURLRequest) (*URL, error)
+	// GetURL returns a URL with its crawls
+	GetURL(context.Context, *GetURLRequest) (*URL, error)
+	// StartCrawl crawls a URL again
+	StartCrawl(context.Context, *StartCrawlRequest) (*StartCrawlResponse, error)
+	// GetCrawlStatus returns the status of the latest crawl of a URL
+	GetCrawlStatus(context.Context, *GetCrawlStatusRequest) (*CrawlStatus, error)
+	// WatchCrawlStatus streams the crawl status of a URL whenever it changes,
+	// and ends once the crawl has finished
+	WatchCrawlStatus(*WatchCrawlStatusRequest, grpc.ServerStreamingServer[CrawlStatus]) error
+	// ListLinks pages through the links found on a URL, newest first
+	ListLinks(context.Context, *ListLinksRequest) (*ListLinksResponse, error)
+	mustEmbedUnimplementedCrawlerServiceServer()
+}
+
+// UnimplementedCrawlerServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedCrawlerServiceServer struct{}
+
+func (UnimplementedCrawlerServiceServer) SubmitURL(context.Context, *SubmitURLRequest) (*URL, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitURL not implemented")
+}
+func (UnimplementedCrawlerServiceServer) GetURL(context.Context, *GetURLRequest) (*URL, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetURL not implemented")
+}
+func (UnimplementedCrawlerServiceServer) StartCrawl(context.Context, *StartCrawlRequest) (*StartCrawlResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartCrawl not implemented")
+}
+func (UnimplementedCrawlerServiceServer) GetCrawlStatus(context.Context, *GetCrawlStatusRequest) (*CrawlStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCrawlStatus not implemented")
+}
+func (UnimplementedCrawlerServiceServer) WatchCrawlStatus(*WatchCrawlStatusRequest, grpc.ServerStreamingServer[CrawlStatus]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchCrawlStatus not implemented")
+}
+func (UnimplementedCrawlerServiceServer) ListLinks(context.Context, *ListLinksRequest) (*ListLinksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListLinks not implemented")
+}
+func (UnimplementedCrawlerServiceServer) mustEmbedUnimplementedCrawlerServiceServer() {}
+func (UnimplementedCrawlerServiceServer) testEmbeddedByValue()                        {}
+
+// UnsafeCrawlerServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CrawlerServiceServer will
+// result in compilation errors.
+type UnsafeCrawlerServiceServer interface {
+	mustEmbedUnimplementedCrawlerServiceServer()
+}
+
+func RegisterCrawlerServiceServer(s grpc.ServiceRegistrar, srv CrawlerServiceServer) {
+	// If the following call pancis, it indicates UnimplementedCrawlerServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&CrawlerService_ServiceDesc, srv)
+}
+
+func _CrawlerService_SubmitURL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitURLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CrawlerServiceServer).SubmitURL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CrawlerService_SubmitURL_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CrawlerServiceServer).SubmitURL(ctx, req.(*SubmitURLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CrawlerService_GetURL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetURLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CrawlerServiceServer).GetURL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CrawlerService_GetURL_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CrawlerServiceServer).GetURL(ctx, req.(*GetURLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CrawlerService_StartCrawl_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartCrawlRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CrawlerServiceServer).StartCrawl(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CrawlerService_StartCrawl_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CrawlerServiceServer).StartCrawl(ctx, req.(*StartCrawlRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CrawlerService_GetCrawlStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCrawlStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CrawlerServiceServer).GetCrawlStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CrawlerService_GetCrawlStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CrawlerServiceServer).GetCrawlStatus(ctx, req.(*GetCrawlStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CrawlerService_WatchCrawlStatus_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchCrawlStatusRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CrawlerServiceServer).WatchCrawlStatus(m, &grpc.GenericServerStream[WatchCrawlStatusRequest, CrawlStatus]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CrawlerService_WatchCrawlStatusServer = grpc.ServerStreamingServer[CrawlStatus]
+
+func _CrawlerService_ListLinks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListLinksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CrawlerServiceServer).ListLinks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CrawlerService_ListLinks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CrawlerServiceServer).ListLinks(ctx, req.(*ListLinksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// CrawlerService_ServiceDesc is the grpc.ServiceDesc for CrawlerService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var CrawlerService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "crawler.v1.CrawlerService",
+	HandlerType: (*CrawlerServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SubmitURL",
+			Handler:    _CrawlerService_SubmitURL_Handler,
+		},
+		{
+			MethodName: "GetURL",
+			Handler:    _CrawlerService_GetURL_Handler,
+		},
+		{
+			MethodName: "StartCrawl",
+			Handler:    _CrawlerService_StartCrawl_Handler,
+		},
+		{
+			MethodName: "GetCrawlStatus",
+			Handler:    _CrawlerService_GetCrawlStatus_Handler,
+		},
+		{
+			MethodName: "ListLinks",
+			Handler:    _CrawlerService_ListLinks_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchCrawlStatus",
+			Handler:       _CrawlerService_WatchCrawlStatus_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "crawler/v1/crawler.proto",
+}
@@ -0,0 +1,187 @@
+// Package grpcapi serves the crawler over gRPC for other internal services.
+// The messages and service are defined in proto/crawler/v1/crawler.proto;
+// run make proto after changing it.
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"web-crawler-backend/internal/grpcapi/crawlerpb"
+	"web-crawler-backend/internal/services"
+)
+
+const (
+	// defaultPollInterval is how often WatchCrawlStatus checks for changes
+	defaultPollInterval = time.Second
+
+	defaultPageSize = 10
+	maxPageSize     = 100
+)
+
+// contextCrawlStarter is implemented by crawlers that link their crawl traces
+// to the request that started them
+type contextCrawlStarter interface {
+	StartCrawlContext(ctx context.Context, urlID uint)
+}
+
+// Server implements crawlerpb.CrawlerServiceServer on top of the URL and crawler services
+type Server struct {
+	crawlerpb.UnimplementedCrawlerServiceServer
+
+	urlService     *services.URLService
+	crawlerService services.CrawlerServiceInterface
+	pollInterval   time.Duration
+}
+
+func NewServer(urlService *services.URLService, crawlerService services.CrawlerServiceInterface) *Server {
+	return &Server{
+		urlService:     urlService,
+		crawlerService: crawlerService,
+		pollInterval:   defaultPollInterval,
+	}
+}
+
+// NewGRPCServer returns a gRPC server with the crawler service registered.
+// Every call is authenticated with a bearer token from the auth service.
+func NewGRPCServer(server *Server, authService *services.AuthService) *grpc.Server {
+	auth := authenticator{authService: authService}
+	grpcServer := grpc.NewServer(
+		grpc.UnaryInterceptor(auth.unary),
+		grpc.StreamInterceptor(auth.stream),
+	)
+	crawlerpb.RegisterCrawlerServiceServer(grpcServer, server)
+	return grpcServer
+}
+
+// SubmitURL handles CrawlerService.SubmitURL
+func (s *Server) SubmitURL(ctx context.Context, req *crawlerpb.SubmitURLRequest) (*crawlerpb.URL, error) {
+	if req.GetUrl() == "" {
+		return nil, status.Error(codes.InvalidArgument, "url is required")
+	}
+
+	userID, _ := userIDFromContext(ctx)
+	url, err := s.urlService.WithContext(ctx).CreateURLForUser(req.GetUrl(), userID)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return urlToProto(url), nil
+}
+
+// GetURL handles CrawlerService.GetURL
+func (s *Server) GetURL(ctx context.Context, req *crawlerpb.GetURLRequest) (*crawlerpb.URL, error) {
+	url, err := s.urlService.WithContext(ctx).GetURL(uint(req.GetId()))
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return urlToProto(url), nil
+}
+
+// StartCrawl handles CrawlerService.StartCrawl
+func (s *Server) StartCrawl(ctx context.Context, req *crawlerpb.StartCrawlRequest) (*crawlerpb.StartCrawlResponse, error) {
+	urlID := uint(req.GetUrlId())
+	if _, err := s.crawlerService.GetCrawlStatus(urlID); err != nil {
+		return nil, statusError(err)
+	}
+
+	// Start crawling in background, linked to this call's trace
+	if starter, ok := s.crawlerService.(contextCrawlStarter); ok {
+		go starter.StartCrawlContext(ctx, urlID)
+	} else {
+		go s.crawlerService.StartCrawl(urlID)
+	}
+
+	return &crawlerpb.StartCrawlResponse{UrlId: req.GetUrlId()}, nil
+}
+
+// GetCrawlStatus handles CrawlerService.GetCrawlStatus
+func (s *Server) GetCrawlStatus(ctx context.Context, req *crawlerpb.GetCrawlStatusRequest) (*crawlerpb.CrawlStatus, error) {
+	crawlStatus, err := s.crawlerService.GetCrawlStatus(uint(req.GetUrlId()))
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return crawlStatusToProto(req.GetUrlId(), crawlStatus), nil
+}
+
+// WatchCrawlStatus handles CrawlerService.WatchCrawlStatus. It polls the
+// crawl status, sends it whenever it changes and returns once the crawl has
+// finished or the client goes away.
+func (s *Server) WatchCrawlStatus(req *crawlerpb.WatchCrawlStatusRequest, stream crawlerpb.CrawlerService_WatchCrawlStatusServer) error {
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	var last *crawlerpb.CrawlStatus
+	for {
+		crawlStatus, err := s.crawlerService.GetCrawlStatus(uint(req.GetUrlId()))
+		if err != nil {
+			return statusError(err)
+		}
+
+		current := crawlStatusToProto(req.GetUrlId(), crawlStatus)
+		if !proto.Equal(current, last) {
+			if err := stream.Send(current); err != nil {
+				return err
+			}
+			last = current
+		}
+		if crawlFinished(current.GetStatus()) {
+			return nil
+		}
+
+		select {
+		case <-stream.Context().Done():
+			return status.FromContextError(stream.Context().Err()).Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// ListLinks handles CrawlerService.ListLinks
+func (s *Server) ListLinks(ctx context.Context, req *crawlerpb.ListLinksRequest) (*crawlerpb.ListLinksResponse, error) {
+	pageSize := int(req.GetPageSize())
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+
+	links, total, next, err := s.urlService.WithContext(ctx).GetURLLinksAfter(uint(req.GetUrlId()), req.GetLinkType(), pageSize, req.GetPageToken())
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	resp := &crawlerpb.ListLinksResponse{Total: total, NextPageToken: next}
+	for _, link := range links {
+		resp.Links = append(resp.Links, linkToProto(link))
+	}
+	return resp, nil
+}
+
+// crawlFinished reports whether a crawl status is final
+func crawlFinished(crawlStatus string) bool {
+	switch crawlStatus {
+	case "completed", "unchanged", "skipped", "error":
+		return true
+	}
+	return false
+}
+
+// statusError maps service errors to gRPC status codes
+func statusError(err error) error {
+	switch {
+	case errors.Is(err, services.ErrURLNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, services.ErrInvalidURL), errors.Is(err, services.ErrURLNotAllowed), errors.Is(err, services.ErrInvalidCursor):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+		return status.FromContextError(err).Err()
+	}
+	return status.Error(codes.Internal, err.Error())
+}
@@ -0,0 +1,173 @@
+package grpcapi
+
+import (
+	"context"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"web-crawler-backend/internal/grpcapi/crawlerpb"
+	"web-crawler-backend/internal/models"
+	"web-crawler-backend/internal/services"
+)
+
+// fakeCrawler plays back a sequence of crawl statuses, one per call
+type fakeCrawler struct {
+	mu       sync.Mutex
+	statuses []string
+	started  chan uint
+}
+
+func (f *fakeCrawler) StartCrawl(urlID uint) {
+	f.started <- urlID
+}
+
+func (f *fakeCrawler) GetCrawlStatus(urlID uint) (*models.CrawlStatusResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if urlID != 1 {
+		return nil, services.ErrURLNotFound
+	}
+	current := f.statuses[0]
+	if len(f.statuses) > 1 {
+		f.statuses = f.statuses[1:]
+	}
+	return &models.CrawlStatusResponse{ID: 7, URL: "https://example.com", Status: current, InternalLinks: 3}, nil
+}
+
+func (f *fakeCrawler) BulkRerunCrawls(urlIDs []uint) error {
+	return nil
+}
+
+func setupGRPCTest(t *testing.T, crawler *fakeCrawler) (crawlerpb.CrawlerServiceClient, context.Context, *gorm.DB) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.User{}, &models.Session{}, &models.URL{}, &models.Crawl{}, &models.Link{}, &models.PageMeta{}, &models.ActivityEvent{}, &models.FindingAnnotation{}, &models.ExternalIssue{}))
+
+	authService := services.NewAuthService(db)
+	_, err = authService.Register(&models.RegisterRequest{Username: "grpc", Email: "grpc@example.com", Password: "password123"})
+	require.NoError(t, err)
+	auth, err := authService.Login(&models.LoginRequest{Username: "grpc", Password: "password123"})
+	require.NoError(t, err)
+
+	server := NewServer(services.NewURLService(db, crawler), crawler)
+	server.pollInterval = time.Millisecond
+	grpcServer := NewGRPCServer(server, authService)
+
+	listener := bufconn.Listen(1 << 20)
+	go grpcServer.Serve(listener)
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+auth.Token)
+	return crawlerpb.NewCrawlerServiceClient(conn), ctx, db
+}
+
+func TestServer_RequiresToken(t *testing.T) {
+	client, _, _ := setupGRPCTest(t, &fakeCrawler{statuses: []string{"completed"}})
+
+	_, err := client.GetURL(context.Background(), &crawlerpb.GetURLRequest{Id: 1})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer invalid")
+	stream, err := client.WatchCrawlStatus(ctx, &crawlerpb.WatchCrawlStatusRequest{UrlId: 1})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestServer_URLsAndLinks(t *testing.T) {
+	crawler := &fakeCrawler{statuses: []string{"completed"}, started: make(chan uint, 1)}
+	client, ctx, db := setupGRPCTest(t, crawler)
+
+	url, err := client.SubmitURL(ctx, &crawlerpb.SubmitURLRequest{Url: "https://example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1), url.GetId())
+	assert.Equal(t, "pending", url.GetStatus())
+	assert.Equal(t, uint(1), <-crawler.started)
+
+	var owner models.URL
+	require.NoError(t, db.First(&owner, url.GetId()).Error)
+	require.NotNil(t, owner.UserID)
+
+	_, err = client.SubmitURL(ctx, &crawlerpb.SubmitURLRequest{Url: "not a url"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	crawl := models.Crawl{URLID: 1, Status: "completed", InternalLinks: 2}
+	require.NoError(t, db.Create(&crawl).Error)
+	for _, link := range []models.Link{
+		{URLID: 1, CrawlID: crawl.ID, LinkURL: "https://example.com/a", LinkType: "internal", IsAccessible: true},
+		{URLID: 1, CrawlID: crawl.ID, LinkURL: "https://example.com/b", LinkType: "internal", IsAccessible: true},
+		{URLID: 1, CrawlID: crawl.ID, LinkURL: "https://other.com", LinkType: "external", IsAccessible: false},
+	} {
+		require.NoError(t, db.Create(&link).Error)
+	}
+
+	got, err := client.GetURL(ctx, &crawlerpb.GetURLRequest{Id: 1})
+	require.NoError(t, err)
+	require.Len(t, got.GetCrawls(), 1)
+	assert.Equal(t, int32(2), got.GetCrawls()[0].GetInternalLinks())
+	assert.Nil(t, got.GetCrawls()[0].GetStartedAt())
+
+	_, err = client.GetURL(ctx, &crawlerpb.GetURLRequest{Id: 99})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	page, err := client.ListLinks(ctx, &crawlerpb.ListLinksRequest{UrlId: 1, LinkType: "internal", PageSize: 1})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), page.GetTotal())
+	require.Len(t, page.GetLinks(), 1)
+	require.NotEmpty(t, page.GetNextPageToken())
+
+	page, err = client.ListLinks(ctx, &crawlerpb.ListLinksRequest{UrlId: 1, LinkType: "internal", PageSize: 1, PageToken: page.GetNextPageToken()})
+	require.NoError(t, err)
+	require.Len(t, page.GetLinks(), 1)
+	assert.Empty(t, page.GetNextPageToken())
+
+	_, err = client.ListLinks(ctx, &crawlerpb.ListLinksRequest{UrlId: 1, PageToken: "garbage"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestServer_WatchCrawlStatus(t *testing.T) {
+	crawler := &fakeCrawler{statuses: []string{"queued", "queued", "running", "running", "completed"}}
+	client, ctx, _ := setupGRPCTest(t, crawler)
+
+	stream, err := client.WatchCrawlStatus(ctx, &crawlerpb.WatchCrawlStatusRequest{UrlId: 1})
+	require.NoError(t, err)
+
+	var seen []string
+	for {
+		update, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		assert.Equal(t, uint32(7), update.GetCrawlId())
+		seen = append(seen, update.GetStatus())
+	}
+	assert.Equal(t, []string{"queued", "running", "completed"}, seen)
+}
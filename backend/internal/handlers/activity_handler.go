@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"web-crawler-backend/internal/apperror"
+	"web-crawler-backend/internal/services"
+)
+
+type ActivityHandler struct {
+	activityService *services.ActivityService
+}
+
+func NewActivityHandler(activityService *services.ActivityService) *ActivityHandler {
+	return &ActivityHandler{activityService: activityService}
+}
+
+// service returns the activity service bound to the request context
+func (h *ActivityHandler) service(c *gin.Context) *services.ActivityService {
+	return h.activityService.WithContext(c.Request.Context())
+}
+
+// GetActivity handles GET /api/v1/activity
+func (h *ActivityHandler) GetActivity(c *gin.Context) {
+	// Parse query parameters
+	var types []string
+	for _, eventType := range strings.Split(c.Query("type"), ",") {
+		if eventType = strings.TrimSpace(eventType); eventType != "" {
+			types = append(types, eventType)
+		}
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	events, total, err := h.service(c).GetFeed(c.GetUint("user_id"), types, limit, offset)
+	if err != nil {
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to fetch activity", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": events,
+		"pagination": gin.H{
+			"total":  total,
+			"limit":  limit,
+			"offset": offset,
+		},
+	})
+}
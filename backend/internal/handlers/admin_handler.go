@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"web-crawler-backend/internal/apperror"
+	"web-crawler-backend/internal/services"
+)
+
+type AdminHandler struct {
+	systemStatusService *services.SystemStatusService
+	requestMetrics      *services.RequestMetrics
+	tokenGuard          *services.TokenGuard
+}
+
+func NewAdminHandler(systemStatusService *services.SystemStatusService, requestMetrics *services.RequestMetrics, tokenGuard *services.TokenGuard) *AdminHandler {
+	return &AdminHandler{systemStatusService: systemStatusService, requestMetrics: requestMetrics, tokenGuard: tokenGuard}
+}
+
+// GetSystemStatus handles GET /api/v1/admin/status
+// @Summary Inspect the crawling system
+// @Description Reports the crawl queue with its worker utilization, the running crawls with their durations and the newest failed crawls. The queue is left out when crawls run in the API process. Admins only.
+// @Tags admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} models.SystemStatus
+// @Router /admin/status [get]
+func (h *AdminHandler) GetSystemStatus(c *gin.Context) {
+	status, err := h.systemStatusService.Status(c.Request.Context(), time.Now())
+	if err != nil {
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to read system status", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": status,
+	})
+}
+
+// GetRequestMetrics handles GET /api/v1/admin/metrics
+// @Summary API usage per route and user
+// @Description Counts the calls, client and server errors and latency of every route and user since this server process started. Each replica reports its own requests. Admins only.
+// @Tags admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} models.RequestMetricsReport
+// @Router /admin/metrics [get]
+func (h *AdminHandler) GetRequestMetrics(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"data": h.requestMetrics.Report(),
+	})
+}
+
+// GetAuthIncidents handles GET /api/v1/admin/auth-incidents
+// @Summary Security audit log
+// @Description Lists the clients that sent many invalid tokens to token refresh or validation, newest first, and until when they were blocked. Admins only.
+// @Tags admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Param limit query int false "Page size, at most 100" default(20)
+// @Param offset query int false "Number of incidents to skip" default(0)
+// @Success 200 {object} map[string]interface{}
+// @Router /admin/auth-incidents [get]
+func (h *AdminHandler) GetAuthIncidents(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	incidents, total, err := h.tokenGuard.Incidents(c.Request.Context(), limit, offset)
+	if err != nil {
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to fetch auth incidents", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": incidents,
+		"pagination": gin.H{
+			"total":  total,
+			"limit":  limit,
+			"offset": offset,
+		},
+	})
+}
+
+// parseCrawlID reads the crawl ID path parameter, answering 400 if it isn't a number
+func parseCrawlID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apperror.Abort(c, apperror.New(http.StatusBadRequest, "Invalid crawl ID", "ID must be a valid number"))
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// CancelCrawl handles POST /api/v1/admin/crawls/:id/cancel
+// @Summary Cancel a running crawl
+// @Description Marks a running crawl and its URL as failed, e.g. when the crawl is stuck. A crawl that finishes after all overwrites this with its result. Admins only.
+// @Tags admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Crawl ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /admin/crawls/{id}/cancel [post]
+func (h *AdminHandler) CancelCrawl(c *gin.Context) {
+	id, ok := parseCrawlID(c)
+	if !ok {
+		return
+	}
+
+	crawl, err := h.systemStatusService.CancelCrawl(id, time.Now())
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrCrawlNotFound):
+			apperror.Abort(c, apperror.New(http.StatusNotFound, "Crawl not found", "The requested crawl does not exist"))
+		case errors.Is(err, services.ErrCrawlNotRunning):
+			apperror.Abort(c, apperror.New(http.StatusConflict, "Crawl not running", "Only running crawls can be cancelled"))
+		default:
+			apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to cancel crawl", err))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":    crawl,
+		"message": "Crawl cancelled",
+	})
+}
+
+// RequeueCrawl handles POST /api/v1/admin/crawls/:id/requeue
+// @Summary Crawl the URL of a crawl again
+// @Description Starts a new crawl of the URL of a crawl, e.g. one that failed. Admins only.
+// @Tags admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Crawl ID"
+// @Success 202 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /admin/crawls/{id}/requeue [post]
+func (h *AdminHandler) RequeueCrawl(c *gin.Context) {
+	id, ok := parseCrawlID(c)
+	if !ok {
+		return
+	}
+
+	urlID, err := h.systemStatusService.RequeueCrawl(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrCrawlNotFound):
+			apperror.Abort(c, apperror.New(http.StatusNotFound, "Crawl not found", "The requested crawl does not exist"))
+		case errors.Is(err, services.ErrCrawlRunning):
+			apperror.Abort(c, apperror.New(http.StatusConflict, "Crawl running", "The URL is being crawled; cancel the running crawl first"))
+		default:
+			apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to requeue crawl", err))
+		}
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"data":    gin.H{"url_id": urlID},
+		"message": "Crawl queued",
+	})
+}
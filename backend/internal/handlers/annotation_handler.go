@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"web-crawler-backend/internal/apperror"
+	"web-crawler-backend/internal/models"
+	"web-crawler-backend/internal/services"
+)
+
+type AnnotationHandler struct {
+	annotationService *services.AnnotationService
+}
+
+func NewAnnotationHandler(annotationService *services.AnnotationService) *AnnotationHandler {
+	return &AnnotationHandler{annotationService: annotationService}
+}
+
+// service returns the annotation service bound to the request context
+func (h *AnnotationHandler) service(c *gin.Context) *services.AnnotationService {
+	return h.annotationService.WithContext(c.Request.Context())
+}
+
+// ListAnnotations handles GET /api/v1/urls/:id/annotations
+func (h *AnnotationHandler) ListAnnotations(c *gin.Context) {
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	annotations, err := h.service(c).ListAnnotations(id)
+	if err != nil {
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to fetch annotations", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": annotations,
+	})
+}
+
+// Annotate handles POST /api/v1/urls/:id/annotations
+func (h *AnnotationHandler) Annotate(c *gin.Context) {
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req models.FindingAnnotationRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	annotation, err := h.service(c).Annotate(id, c.GetUint("user_id"), req)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidAnnotation) {
+			apperror.Abort(c, apperror.Wrap(http.StatusBadRequest, "Invalid annotation", err))
+			return
+		}
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to save annotation", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": annotation,
+	})
+}
+
+// DeleteAnnotation handles DELETE /api/v1/urls/:id/annotations/:annotation_id
+func (h *AnnotationHandler) DeleteAnnotation(c *gin.Context) {
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	annotationID, err := strconv.ParseUint(c.Param("annotation_id"), 10, 32)
+	if err != nil {
+		apperror.Abort(c, apperror.New(http.StatusBadRequest, "Invalid annotation ID", "ID must be a valid number"))
+		return
+	}
+
+	if err := h.service(c).DeleteAnnotation(id, uint(annotationID), c.GetUint("user_id")); err != nil {
+		if errors.Is(err, services.ErrAnnotationNotFound) {
+			apperror.Abort(c, apperror.New(http.StatusNotFound, "Annotation not found", "The requested annotation does not exist for this URL"))
+			return
+		}
+
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to delete annotation", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Annotation deleted successfully",
+	})
+}
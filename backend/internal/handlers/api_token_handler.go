@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"web-crawler-backend/internal/apperror"
+	"web-crawler-backend/internal/models"
+	"web-crawler-backend/internal/services"
+)
+
+type APITokenHandler struct {
+	apiTokenService *services.APITokenService
+}
+
+func NewAPITokenHandler(apiTokenService *services.APITokenService) *APITokenHandler {
+	return &APITokenHandler{apiTokenService: apiTokenService}
+}
+
+// service returns the API token service bound to the request context
+func (h *APITokenHandler) service(c *gin.Context) *services.APITokenService {
+	return h.apiTokenService.WithContext(c.Request.Context())
+}
+
+// respondAPITokenError answers with the status matching an API token service error
+func respondAPITokenError(c *gin.Context, err error, failure string) {
+	if errors.Is(err, services.ErrAPITokenNotFound) {
+		apperror.Abort(c, apperror.New(http.StatusNotFound, "API token not found", "The API token does not exist or has been revoked"))
+		return
+	}
+	respondOrganizationError(c, err, failure)
+}
+
+// CreateToken handles POST /api/v1/tokens
+// @Summary Create an API token
+// @Description Creates a token for scripts and integrations, sent as a Bearer token like the JWT of a login. The read scope only reads, crawl also starts and reprocesses crawls, and admin may do everything the user may. Tokens of an organization only work on its URLs and need an admin of it to create them. The token is only returned here.
+// @Tags tokens
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body models.CreateAPITokenRequest true "Token name, scope and organization"
+// @Success 201 {object} models.CreatedAPIToken
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /tokens [post]
+func (h *APITokenHandler) CreateToken(c *gin.Context) {
+	var req models.CreateAPITokenRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	token, err := h.service(c).CreateToken(c.GetUint("user_id"), &req)
+	if err != nil {
+		respondAPITokenError(c, err, "Failed to create API token")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"data": token,
+	})
+}
+
+// ListTokens handles GET /api/v1/tokens
+// @Summary List API tokens
+// @Description Lists the active tokens the current user created, newest first, or the tokens of an organization for its admins
+// @Tags tokens
+// @Produce json
+// @Security ApiKeyAuth
+// @Param organization_id query int false "Organization ID"
+// @Success 200 {array} models.APIToken
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /tokens [get]
+func (h *APITokenHandler) ListTokens(c *gin.Context) {
+	orgID, err := strconv.ParseUint(c.DefaultQuery("organization_id", "0"), 10, 32)
+	if err != nil {
+		apperror.Abort(c, apperror.New(http.StatusBadRequest, "Invalid organization ID", "organization_id must be a valid number"))
+		return
+	}
+
+	tokens, err := h.service(c).ListTokens(c.GetUint("user_id"), uint(orgID))
+	if err != nil {
+		respondAPITokenError(c, err, "Failed to fetch API tokens")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": tokens,
+	})
+}
+
+// RevokeToken handles DELETE /api/v1/tokens/:id
+// @Summary Revoke an API token
+// @Description Revokes a token the current user created, or a token of an organization they administer. Requests with it are rejected from then on.
+// @Tags tokens
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Token ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /tokens/{id} [delete]
+func (h *APITokenHandler) RevokeToken(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apperror.Abort(c, apperror.New(http.StatusBadRequest, "Invalid token ID", "ID must be a valid number"))
+		return
+	}
+
+	if err := h.service(c).RevokeToken(c.GetUint("user_id"), uint(id)); err != nil {
+		respondAPITokenError(c, err, "Failed to revoke API token")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "API token revoked",
+	})
+}
@@ -1,20 +1,33 @@
 package handlers
 
 import (
+	"errors"
+	"io"
+	"log"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"web-crawler-backend/internal/apperror"
+	"web-crawler-backend/internal/authcookie"
 	"web-crawler-backend/internal/models"
 	"web-crawler-backend/internal/services"
 )
 
 type AuthHandler struct {
-	authService *services.AuthService
+	authService       *services.AuthService
+	onboardingService *services.OnboardingService
+	cookies           *authcookie.Options
 }
 
-func NewAuthHandler(authService *services.AuthService) *AuthHandler {
+// NewAuthHandler creates the handler; nil cookies disable cookie auth, so
+// tokens are only returned in response bodies
+func NewAuthHandler(authService *services.AuthService, onboardingService *services.OnboardingService, cookies *authcookie.Options) *AuthHandler {
 	return &AuthHandler{
-		authService: authService,
+		authService:       authService,
+		onboardingService: onboardingService,
+		cookies:           cookies,
 	}
 }
 
@@ -31,28 +44,23 @@ func NewAuthHandler(authService *services.AuthService) *AuthHandler {
 // @Router /auth/register [post]
 func (h *AuthHandler) Register(c *gin.Context) {
 	var req models.RegisterRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request",
-			"message": err.Error(),
-		})
+	if !bindJSON(c, &req) {
 		return
 	}
 
-	user, err := h.authService.Register(&req)
+	user, err := h.authService.WithContext(c.Request.Context()).Register(&req)
 	if err != nil {
-		statusCode := http.StatusInternalServerError
-		if err.Error() == "username or email already exists" {
-			statusCode = http.StatusConflict
-		}
-		
-		c.JSON(statusCode, gin.H{
-			"error":   "Registration failed",
-			"message": err.Error(),
-		})
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Registration failed", err))
 		return
 	}
 
+	// Give new accounts a sample crawl so the dashboard isn't empty
+	if h.onboardingService != nil {
+		if _, err := h.onboardingService.CreateDemoCrawl(user.ID); err != nil && !errors.Is(err, services.ErrDemoDisabled) {
+			log.Printf("Failed to create demo crawl for user %d: %v", user.ID, err)
+		}
+	}
+
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "User created successfully",
 		"user":    user,
@@ -61,7 +69,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 
 // Login handles user authentication
 // @Summary Login user
-// @Description Authenticate user and return JWT token
+// @Description Authenticate user and return JWT token. With cookie auth enabled the token is also set in an HttpOnly cookie.
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -72,61 +80,56 @@ func (h *AuthHandler) Register(c *gin.Context) {
 // @Router /auth/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req models.LoginRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request",
-			"message": err.Error(),
-		})
+	if !bindJSON(c, &req) {
 		return
 	}
 
-	authResponse, err := h.authService.Login(&req)
+	authResponse, err := h.authService.WithContext(c.Request.Context()).WithClient(c.Request.UserAgent(), c.ClientIP()).Login(&req)
 	if err != nil {
-		statusCode := http.StatusInternalServerError
-		if err.Error() == "invalid credentials" {
-			statusCode = http.StatusUnauthorized
-		}
-		
-		c.JSON(statusCode, gin.H{
-			"error":   "Login failed",
-			"message": err.Error(),
-		})
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Login failed", err))
 		return
 	}
 
+	h.cookies.SetToken(c, authResponse.Token, authResponse.ExpiresAt)
 	c.JSON(http.StatusOK, authResponse)
 }
 
 // RefreshToken handles token refresh
 // @Summary Refresh JWT token
-// @Description Generate a new JWT token using existing valid token
+// @Description Generate a new JWT token using existing valid token, taken from the body or else the Authorization header or auth cookie
 // @Tags auth
 // @Accept json
 // @Produce json
-// @Param request body models.RefreshTokenRequest true "Current token"
+// @Param request body models.RefreshTokenRequest false "Current token"
 // @Success 200 {object} models.AuthResponse
 // @Failure 400 {object} map[string]interface{}
 // @Failure 401 {object} map[string]interface{}
 // @Router /auth/refresh [post]
 func (h *AuthHandler) RefreshToken(c *gin.Context) {
+	// The body is optional for clients authenticated by cookie, whose token
+	// CookieAuth puts in the Authorization header
 	var req models.RefreshTokenRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request",
-			"message": err.Error(),
-		})
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		apperror.Abort(c, bindError(&req, err))
+		return
+	}
+	if req.Token == "" {
+		req.Token, _ = strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	}
+	if req.Token == "" {
+		apperror.Abort(c, apperror.Validation("Invalid request body", []apperror.FieldError{
+			{Field: "token", Rule: "required", Message: "token is required"},
+		}))
 		return
 	}
 
-	authResponse, err := h.authService.RefreshToken(req.Token)
+	authResponse, err := h.authService.WithContext(c.Request.Context()).WithClient(c.Request.UserAgent(), c.ClientIP()).RefreshToken(req.Token)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error":   "Token refresh failed",
-			"message": err.Error(),
-		})
+		apperror.Abort(c, apperror.Wrap(http.StatusUnauthorized, "Token refresh failed", err))
 		return
 	}
 
+	h.cookies.SetToken(c, authResponse.Token, authResponse.ExpiresAt)
 	c.JSON(http.StatusOK, authResponse)
 }
 
@@ -144,24 +147,13 @@ func (h *AuthHandler) GetProfile(c *gin.Context) {
 	// Get user ID from middleware context
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error":   "Unauthorized",
-			"message": "User context not found",
-		})
+		apperror.Abort(c, apperror.New(http.StatusUnauthorized, "Unauthorized", "User context not found"))
 		return
 	}
 
-	user, err := h.authService.GetUserByID(userID.(uint))
+	user, err := h.authService.WithContext(c.Request.Context()).GetUserByID(userID.(uint))
 	if err != nil {
-		statusCode := http.StatusInternalServerError
-		if err.Error() == "user not found" {
-			statusCode = http.StatusNotFound
-		}
-		
-		c.JSON(statusCode, gin.H{
-			"error":   "Failed to get profile",
-			"message": err.Error(),
-		})
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to get profile", err))
 		return
 	}
 
@@ -170,24 +162,105 @@ func (h *AuthHandler) GetProfile(c *gin.Context) {
 	})
 }
 
-// Logout handles user logout (client-side token invalidation)
+// Logout handles user logout
 // @Summary Logout user
-// @Description Logout user (client should discard token)
+// @Description Logout user by revoking the session of the token, so it can't be used again, and clear the auth cookie
 // @Tags auth
 // @Produce json
 // @Security ApiKeyAuth
 // @Success 200 {object} map[string]interface{}
 // @Router /auth/logout [post]
 func (h *AuthHandler) Logout(c *gin.Context) {
-	// In a stateless JWT implementation, logout is typically handled client-side
-	// by discarding the token. For enhanced security, you could implement a
-	// token blacklist on the server side.
-	
+	// API tokens have no session to revoke; they are revoked on their own
+	if sessionID := c.GetUint("session_id"); sessionID != 0 {
+		err := h.authService.WithContext(c.Request.Context()).RevokeSession(c.GetUint("user_id"), sessionID)
+		if err != nil && !errors.Is(err, services.ErrSessionNotFound) {
+			apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Logout failed", err))
+			return
+		}
+	}
+	h.cookies.Clear(c)
+
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Logged out successfully",
 	})
 }
 
+// GetCSRFToken hands out a CSRF token for cookie auth
+// @Summary Get a CSRF token
+// @Description Returns a CSRF token and sets it in the csrf_token cookie. Requests that change something and authenticate with the auth cookie must echo it in the X-CSRF-Token header.
+// @Tags auth
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /auth/csrf [get]
+func (h *AuthHandler) GetCSRFToken(c *gin.Context) {
+	if h.cookies == nil {
+		apperror.Abort(c, apperror.New(http.StatusNotFound, "Cookie auth disabled", "Cookie authentication is not enabled on this server"))
+		return
+	}
+
+	token, err := h.cookies.IssueCSRF(c)
+	if err != nil {
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to issue CSRF token", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"csrf_token": token,
+	})
+}
+
+// GetSessions lists the active sessions of the current user
+// @Summary List sessions
+// @Description List the devices the current user is logged in on, most recently used first. The session of the request is marked current.
+// @Tags auth
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /auth/sessions [get]
+func (h *AuthHandler) GetSessions(c *gin.Context) {
+	sessions, err := h.authService.WithContext(c.Request.Context()).ListSessions(c.GetUint("user_id"), c.GetUint("session_id"))
+	if err != nil {
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to list sessions", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": sessions,
+	})
+}
+
+// RevokeSession ends a session of the current user
+// @Summary Revoke a session
+// @Description Log the current user out on one device; tokens of the session are rejected from then on
+// @Tags auth
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Session ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /auth/sessions/{id} [delete]
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apperror.Abort(c, apperror.New(http.StatusBadRequest, "Invalid session ID", "ID must be a valid number"))
+		return
+	}
+
+	if err := h.authService.WithContext(c.Request.Context()).RevokeSession(c.GetUint("user_id"), uint(id)); err != nil {
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to revoke session", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Session revoked",
+	})
+}
+
 // ValidateToken validates if the current token is valid
 // @Summary Validate token
 // @Description Check if the current JWT token is valid
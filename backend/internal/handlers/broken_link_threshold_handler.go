@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"web-crawler-backend/internal/apperror"
+	"web-crawler-backend/internal/models"
+	"web-crawler-backend/internal/services"
+)
+
+type BrokenLinkThresholdHandler struct {
+	alertService *services.BrokenLinkAlertService
+}
+
+func NewBrokenLinkThresholdHandler(alertService *services.BrokenLinkAlertService) *BrokenLinkThresholdHandler {
+	return &BrokenLinkThresholdHandler{alertService: alertService}
+}
+
+// service returns the alert service of the organization of the request,
+// bound to the request context
+func (h *BrokenLinkThresholdHandler) service(c *gin.Context) *services.BrokenLinkAlertService {
+	return h.alertService.WithContext(c.Request.Context()).WithOrganization(c.GetUint("organization_id"))
+}
+
+// respondThresholdError answers with the status matching an alert service error
+func respondThresholdError(c *gin.Context, err error, failure string) {
+	switch {
+	case errors.Is(err, services.ErrThresholdNotFound):
+		apperror.Abort(c, apperror.New(http.StatusNotFound, "Threshold not found", "No broken link threshold is set"))
+	case errors.Is(err, services.ErrInvalidThreshold):
+		apperror.Abort(c, apperror.Wrap(http.StatusBadRequest, "Invalid threshold", err))
+	default:
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, failure, err))
+	}
+}
+
+// GetURLThreshold handles GET /api/v1/urls/:id/broken-link-threshold
+// @Summary Get the broken link threshold of a URL
+// @Description Returns the URL's own threshold; without one, the default of its project applies
+// @Tags alerts
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "URL ID"
+// @Success 200 {object} models.BrokenLinkThreshold
+// @Failure 404 {object} map[string]interface{}
+// @Router /urls/{id}/broken-link-threshold [get]
+func (h *BrokenLinkThresholdHandler) GetURLThreshold(c *gin.Context) {
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	threshold, err := h.service(c).GetURLThreshold(id)
+	if err != nil {
+		respondThresholdError(c, err, "Failed to fetch threshold")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": threshold,
+	})
+}
+
+// SetURLThreshold handles PUT /api/v1/urls/:id/broken-link-threshold
+// @Summary Set the broken link threshold of a URL
+// @Description When a crawl of the URL finds more broken links than max_broken_links, or a larger percentage of its links broken than max_broken_percent, the URL is flagged with needs_attention and its owner is notified in the activity feed as links.threshold_exceeded. The flag clears when a later crawl is within the threshold.
+// @Tags alerts
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "URL ID"
+// @Param request body models.BrokenLinkThresholdRequest true "Limits"
+// @Success 200 {object} models.BrokenLinkThreshold
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /urls/{id}/broken-link-threshold [put]
+func (h *BrokenLinkThresholdHandler) SetURLThreshold(c *gin.Context) {
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req models.BrokenLinkThresholdRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	threshold, err := h.service(c).SetURLThreshold(id, req)
+	if err != nil {
+		respondThresholdError(c, err, "Failed to save threshold")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": threshold,
+	})
+}
+
+// DeleteURLThreshold handles DELETE /api/v1/urls/:id/broken-link-threshold
+// @Summary Remove the broken link threshold of a URL
+// @Description The default of the URL's project applies again from its next crawl
+// @Tags alerts
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "URL ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /urls/{id}/broken-link-threshold [delete]
+func (h *BrokenLinkThresholdHandler) DeleteURLThreshold(c *gin.Context) {
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service(c).DeleteURLThreshold(id); err != nil {
+		respondThresholdError(c, err, "Failed to delete threshold")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Threshold deleted",
+	})
+}
+
+// GetProjectThreshold handles GET /api/v1/broken-link-threshold
+// @Summary Get the default broken link threshold
+// @Description Returns the threshold applying to the URLs of the organization of the request, or outside organizations to the URLs the user added, unless a URL has its own
+// @Tags alerts
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} models.BrokenLinkThreshold
+// @Failure 404 {object} map[string]interface{}
+// @Router /broken-link-threshold [get]
+func (h *BrokenLinkThresholdHandler) GetProjectThreshold(c *gin.Context) {
+	threshold, err := h.service(c).GetProjectThreshold(c.GetUint("user_id"))
+	if err != nil {
+		respondThresholdError(c, err, "Failed to fetch threshold")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": threshold,
+	})
+}
+
+// SetProjectThreshold handles PUT /api/v1/broken-link-threshold
+// @Summary Set the default broken link threshold
+// @Description Sets the threshold of the URLs of the organization, which needs its admin role, or outside organizations of the URLs the user added. URLs with their own threshold keep it.
+// @Tags alerts
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body models.BrokenLinkThresholdRequest true "Limits"
+// @Success 200 {object} models.BrokenLinkThreshold
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /broken-link-threshold [put]
+func (h *BrokenLinkThresholdHandler) SetProjectThreshold(c *gin.Context) {
+	var req models.BrokenLinkThresholdRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	threshold, err := h.service(c).SetProjectThreshold(c.GetUint("user_id"), req)
+	if err != nil {
+		respondThresholdError(c, err, "Failed to save threshold")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": threshold,
+	})
+}
+
+// DeleteProjectThreshold handles DELETE /api/v1/broken-link-threshold
+// @Summary Remove the default broken link threshold
+// @Tags alerts
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /broken-link-threshold [delete]
+func (h *BrokenLinkThresholdHandler) DeleteProjectThreshold(c *gin.Context) {
+	if err := h.service(c).DeleteProjectThreshold(c.GetUint("user_id")); err != nil {
+		respondThresholdError(c, err, "Failed to delete threshold")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Threshold deleted",
+	})
+}
@@ -1,101 +1,299 @@
 package handlers
 
 import (
+	"errors"
+	"io"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"web-crawler-backend/internal/apperror"
 	"web-crawler-backend/internal/models"
 	"web-crawler-backend/internal/services"
 )
 
 type CrawlHandler struct {
-	crawlerService *services.CrawlerService
+	crawlerService      services.CrawlServiceInterface
+	quotaService        *services.QuotaService
+	organizationService *services.OrganizationService
 }
 
-func NewCrawlHandler(crawlerService *services.CrawlerService) *CrawlHandler {
-	return &CrawlHandler{crawlerService: crawlerService}
+// NewCrawlHandler creates the handler; a nil quota service enforces no quotas
+// and a nil organization service lets bulk reruns include URLs of any organization
+func NewCrawlHandler(crawlerService services.CrawlServiceInterface, quotaService *services.QuotaService, organizationService *services.OrganizationService) *CrawlHandler {
+	return &CrawlHandler{crawlerService: crawlerService, quotaService: quotaService, organizationService: organizationService}
+}
+
+// reserveCrawls counts crawls against the daily quota of the user, answering
+// the request and returning false if they don't fit
+func (h *CrawlHandler) reserveCrawls(c *gin.Context, n int) bool {
+	err := h.quotaService.ReserveCrawls(c.GetUint("user_id"), n)
+	if err == nil {
+		return true
+	}
+	if !respondQuotaExceeded(c, err) {
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to check quota", err))
+	}
+	return false
 }
 
 // StartCrawl handles POST /api/v1/crawl/:id
+// @Summary Start a crawl
+// @Description Starts a crawl with high priority unless the optional body asks for low priority.
+// @Tags crawl
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "URL ID"
+// @Param request body models.StartCrawlRequest false "Crawl priority"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 429 {object} map[string]interface{}
+// @Router /crawl/{id} [post]
 func (h *CrawlHandler) StartCrawl(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid URL ID",
-			"message": "ID must be a valid number",
-		})
+		apperror.Abort(c, apperror.New(http.StatusBadRequest, "Invalid URL ID", "ID must be a valid number"))
+		return
+	}
+
+	// The body is optional
+	var req models.StartCrawlRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		apperror.Abort(c, apperror.Wrap(http.StatusBadRequest, "Invalid request body", err))
 		return
 	}
+	if req.Priority == "" {
+		req.Priority = models.CrawlPriorityHigh
+	}
 
-	// Start crawling in background
-	go h.crawlerService.StartCrawl(uint(id))
+	if !h.reserveCrawls(c, 1) {
+		return
+	}
+
+	// Start crawling in background, linked to this request's trace
+	go h.crawlerService.StartCrawlWithPriority(c.Request.Context(), uint(id), req.Priority)
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Crawling started",
-		"url_id":  id,
+		"message":  "Crawling started",
+		"url_id":   id,
+		"priority": req.Priority,
 	})
 }
 
 // GetCrawlStatus handles GET /api/v1/crawl/status/:id
+// @Summary Get the crawl status of a URL
+// @Tags crawl
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "URL ID"
+// @Success 200 {object} models.CrawlStatusResponse
+// @Failure 404 {object} map[string]interface{}
+// @Router /crawl/status/{id} [get]
 func (h *CrawlHandler) GetCrawlStatus(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid URL ID",
-			"message": "ID must be a valid number",
-		})
+		apperror.Abort(c, apperror.New(http.StatusBadRequest, "Invalid URL ID", "ID must be a valid number"))
 		return
 	}
 
-	status, err := h.crawlerService.GetCrawlStatus(uint(id))
+	status, err := h.crawlerService.ForRequest(c.Request.Context()).GetCrawlStatus(uint(id))
 	if err != nil {
-		if err.Error() == "URL not found" {
-			c.JSON(http.StatusNotFound, gin.H{
-				"error":   "URL not found",
-				"message": "The requested URL does not exist",
-			})
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to get crawl status", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": status,
+	})
+}
+
+// ReprocessCrawl handles POST /api/v1/crawls/:id/reprocess
+// @Summary Reprocess a crawl from its snapshot
+// @Description Extracts the links, images, issues and scores of a completed crawl again from its stored HTML, without fetching the page. Link and image checks are taken from the crawl's previous results.
+// @Tags crawl
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Crawl ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /crawls/{id}/reprocess [post]
+func (h *CrawlHandler) ReprocessCrawl(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apperror.Abort(c, apperror.New(http.StatusBadRequest, "Invalid crawl ID", "ID must be a valid number"))
+		return
+	}
+
+	if h.organizationService != nil {
+		found, err := h.organizationService.WithContext(c.Request.Context()).CrawlInOrganization(uint(id), c.GetUint("organization_id"))
+		if err != nil {
+			apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to reprocess crawl", err))
 			return
 		}
+		if !found {
+			apperror.Abort(c, apperror.New(http.StatusNotFound, "Crawl not found", "The requested crawl does not exist"))
+			return
+		}
+	}
 
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to get crawl status",
-			"message": err.Error(),
-		})
+	crawl, err := h.crawlerService.ForRequest(c.Request.Context()).ReprocessCrawl(uint(id))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrCrawlNotFound):
+			apperror.Abort(c, apperror.New(http.StatusNotFound, "Crawl not found", "The requested crawl does not exist"))
+		case errors.Is(err, services.ErrNoSnapshot):
+			apperror.Abort(c, apperror.New(http.StatusConflict, "Snapshot not available", "The page of this crawl was not kept, rerun the crawl instead"))
+		case errors.Is(err, services.ErrCrawlNotCompleted):
+			apperror.Abort(c, apperror.Wrap(http.StatusConflict, "Crawl not completed", err))
+		default:
+			apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to reprocess crawl", err))
+		}
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"data": status,
+		"message": "Crawl reprocessed",
+		"data":    crawl,
+	})
+}
+
+// GetCrawlEvents handles GET /api/v1/crawls/:id/events
+// @Summary List the events of a crawl
+// @Description Returns the steps of a crawl in order, e.g. requests, retries, parsing, queued and slow link checks, to explain its results.
+// @Tags crawl
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Crawl ID"
+// @Success 200 {array} models.CrawlEvent
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /crawls/{id}/events [get]
+func (h *CrawlHandler) GetCrawlEvents(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apperror.Abort(c, apperror.New(http.StatusBadRequest, "Invalid crawl ID", "ID must be a valid number"))
+		return
+	}
+
+	if h.organizationService != nil {
+		found, err := h.organizationService.WithContext(c.Request.Context()).CrawlInOrganization(uint(id), c.GetUint("organization_id"))
+		if err != nil {
+			apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to fetch crawl events", err))
+			return
+		}
+		if !found {
+			apperror.Abort(c, apperror.New(http.StatusNotFound, "Crawl not found", "The requested crawl does not exist"))
+			return
+		}
+	}
+
+	events, err := h.crawlerService.ForRequest(c.Request.Context()).GetCrawlEvents(uint(id))
+	if err != nil {
+		if errors.Is(err, services.ErrCrawlNotFound) {
+			apperror.Abort(c, apperror.New(http.StatusNotFound, "Crawl not found", "The requested crawl does not exist"))
+			return
+		}
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to fetch crawl events", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": events,
+	})
+}
+
+// RecheckBrokenLinks handles POST /api/v1/urls/:id/recheck-links
+// @Summary Recheck the broken links of a URL
+// @Description Requests the broken links of the URL's latest completed crawl again, without crawling the page, and updates their status and the crawl's broken link count. Useful to verify fixes; scores are updated by the next crawl or reprocess.
+// @Tags crawl
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "URL ID"
+// @Success 200 {object} models.LinkRecheckResult
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /urls/{id}/recheck-links [post]
+func (h *CrawlHandler) RecheckBrokenLinks(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apperror.Abort(c, apperror.New(http.StatusBadRequest, "Invalid URL ID", "ID must be a valid number"))
+		return
+	}
+
+	result, err := h.crawlerService.ForRequest(c.Request.Context()).RecheckBrokenLinks(uint(id))
+	if err != nil {
+		if errors.Is(err, services.ErrNoCompletedCrawl) {
+			apperror.Abort(c, apperror.New(http.StatusNotFound, "No completed crawl", "The URL has no completed crawl whose links could be rechecked"))
+			return
+		}
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to recheck links", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Broken links rechecked",
+		"data":    result,
 	})
 }
 
 // BulkRerunCrawls handles POST /api/v1/crawl/bulk-rerun
+// @Summary Rerun the crawls of several URLs
+// @Description Reruns the crawls with low priority unless the request asks for high priority.
+// @Tags crawl
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body models.BulkRerunRequest true "URL IDs and priority"
+// @Param Idempotency-Key header string false "Replays the response of an earlier request with the same key"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 429 {object} map[string]interface{}
+// @Router /crawl/bulk-rerun [post]
 func (h *CrawlHandler) BulkRerunCrawls(c *gin.Context) {
-	var req models.BulkRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request body",
-			"message": err.Error(),
-		})
+	var req models.BulkRerunRequest
+	if !bindJSON(c, &req) {
 		return
 	}
 
 	if len(req.IDs) == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "No IDs provided",
-			"message": "At least one URL ID must be provided",
-		})
+		apperror.Abort(c, apperror.New(http.StatusBadRequest, "No IDs provided", "At least one URL ID must be provided"))
+		return
+	}
+
+	if req.Priority == "" {
+		req.Priority = models.CrawlPriorityLow
+	}
+
+	if h.organizationService != nil {
+		found, err := h.organizationService.WithContext(c.Request.Context()).FilterURLIDs(c.GetUint("organization_id"), req.IDs)
+		if err != nil {
+			apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to rerun crawls", err))
+			return
+		}
+		inOrganization := make(map[uint]bool, len(found))
+		for _, id := range found {
+			inOrganization[id] = true
+		}
+		for _, id := range req.IDs {
+			if !inOrganization[id] {
+				apperror.Abort(c, services.ErrURLNotFound.WithMessage("One or more URLs do not exist in this organization"))
+				return
+			}
+		}
+	}
+
+	if !h.reserveCrawls(c, len(req.IDs)) {
 		return
 	}
 
-	if err := h.crawlerService.BulkRerunCrawls(req.IDs); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to rerun crawls",
-			"message": err.Error(),
-		})
+	if err := h.crawlerService.ForRequest(c.Request.Context()).BulkRerunCrawlsWithPriority(req.IDs, req.Priority); err != nil {
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to rerun crawls", err))
 		return
 	}
 
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"web-crawler-backend/internal/models"
+	"web-crawler-backend/internal/services"
+)
+
+// fakeCrawlService answers the crawl handler without a database. Methods
+// the tests don't stub panic through the nil embedded interface.
+type fakeCrawlService struct {
+	services.CrawlServiceInterface
+	ctx      context.Context
+	statuses map[uint]*models.CrawlStatusResponse
+	reruns   []uint
+	priority models.CrawlPriority
+}
+
+func (f *fakeCrawlService) ForRequest(ctx context.Context) services.CrawlServiceInterface {
+	f.ctx = ctx
+	return f
+}
+
+func (f *fakeCrawlService) GetCrawlStatus(urlID uint) (*models.CrawlStatusResponse, error) {
+	status, ok := f.statuses[urlID]
+	if !ok {
+		return nil, services.ErrURLNotFound
+	}
+	return status, nil
+}
+
+func (f *fakeCrawlService) BulkRerunCrawlsWithPriority(urlIDs []uint, priority models.CrawlPriority) error {
+	f.reruns = append(f.reruns, urlIDs...)
+	f.priority = priority
+	return nil
+}
+
+func setupCrawlHandlerTest(service services.CrawlServiceInterface) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	handler := NewCrawlHandler(service, nil, nil)
+	router.GET("/crawl/status/:id", handler.GetCrawlStatus)
+	router.POST("/crawl/bulk-rerun", handler.BulkRerunCrawls)
+	return router
+}
+
+func TestCrawlHandler_GetCrawlStatus(t *testing.T) {
+	service := &fakeCrawlService{statuses: map[uint]*models.CrawlStatusResponse{
+		1: {ID: 1, Status: "running"},
+	}}
+	router := setupCrawlHandlerTest(service)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/crawl/status/1", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Data models.CrawlStatusResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "running", response.Data.Status)
+	assert.NotNil(t, service.ctx, "the service is bound to the request")
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/crawl/status/2", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"url_not_found"`)
+}
+
+func TestCrawlHandler_BulkRerunCrawls(t *testing.T) {
+	service := &fakeCrawlService{}
+	router := setupCrawlHandlerTest(service)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/crawl/bulk-rerun", strings.NewReader(`{"ids":[1,2]}`)))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []uint{1, 2}, service.reruns)
+	assert.Equal(t, models.CrawlPriorityLow, service.priority, "reruns default to low priority")
+}
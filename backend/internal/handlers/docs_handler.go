@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+
+	"web-crawler-backend/docs"
+)
+
+// openAPIPath is where the generated spec is served, relative to the API root
+const openAPIPath = "/openapi.json"
+
+// OpenAPISpec handles GET /api/v1/openapi.json with the spec generated by
+// swag from the handler annotations (make docs)
+func OpenAPISpec(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json; charset=utf-8", []byte(docs.SwaggerInfo.ReadDoc()))
+}
+
+// SwaggerUI serves the embedded Swagger UI for the spec at apiRoot + /openapi.json
+func SwaggerUI(apiRoot string) gin.HandlerFunc {
+	return ginSwagger.WrapHandler(swaggerFiles.Handler, ginSwagger.URL(apiRoot+openAPIPath))
+}
@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"web-crawler-backend/internal/apperror"
+	"web-crawler-backend/internal/models"
+	"web-crawler-backend/internal/services"
+)
+
+type EnvironmentHandler struct {
+	environmentService *services.EnvironmentService
+	quotaService       *services.QuotaService
+}
+
+// NewEnvironmentHandler creates the handler; a nil quota service enforces no quotas
+func NewEnvironmentHandler(environmentService *services.EnvironmentService, quotaService *services.QuotaService) *EnvironmentHandler {
+	return &EnvironmentHandler{environmentService: environmentService, quotaService: quotaService}
+}
+
+// service returns the environment service of the organization of the
+// request, bound to the request context
+func (h *EnvironmentHandler) service(c *gin.Context) *services.EnvironmentService {
+	return h.environmentService.WithContext(c.Request.Context()).WithOrganization(c.GetUint("organization_id"))
+}
+
+// parsePairID reads the pair ID path parameter, answering 400 if it isn't a number
+func parsePairID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apperror.Abort(c, apperror.New(http.StatusBadRequest, "Invalid environment pair ID", "ID must be a valid number"))
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// ListPairs handles GET /api/v1/environments
+// @Summary List environment pairs
+// @Description Lists the URL pairs of deployments to compare, e.g. production and staging, with their URLs
+// @Tags environments
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} models.EnvironmentPair
+// @Router /environments [get]
+func (h *EnvironmentHandler) ListPairs(c *gin.Context) {
+	pairs, err := h.service(c).ListPairs()
+	if err != nil {
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to fetch environment pairs", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": pairs,
+	})
+}
+
+// CreatePair handles POST /api/v1/environments
+// @Summary Link two URLs as an environment pair
+// @Description Links the URL of a page on a reference deployment, e.g. production, with its URL on another deployment, e.g. staging, so POST /compare can compare them
+// @Tags environments
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body models.CreateEnvironmentPairRequest true "Pair name and URLs"
+// @Success 201 {object} models.EnvironmentPair
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /environments [post]
+func (h *EnvironmentHandler) CreatePair(c *gin.Context) {
+	var req models.CreateEnvironmentPairRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	pair, err := h.service(c).CreatePair(c.GetUint("user_id"), &req)
+	if err != nil {
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to create environment pair", err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"data": pair,
+	})
+}
+
+// DeletePair handles DELETE /api/v1/environments/:id
+// @Summary Unlink an environment pair
+// @Description Deletes the pair; its URLs and their crawls are kept
+// @Tags environments
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Environment pair ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /environments/{id} [delete]
+func (h *EnvironmentHandler) DeletePair(c *gin.Context) {
+	id, ok := parsePairID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service(c).DeletePair(id); err != nil {
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to delete environment pair", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Environment pair deleted",
+	})
+}
+
+// GetComparison handles GET /api/v1/environments/:id/comparison
+// @Summary Compare the latest crawls of an environment pair
+// @Description Compares the latest completed crawls of both URLs without crawling them again: titles, heading counts, links only one deployment has, and links whose checks got different HTTP statuses. Links to a URL's own host are compared by path.
+// @Tags environments
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Environment pair ID"
+// @Success 200 {object} models.EnvironmentComparison
+// @Failure 404 {object} map[string]interface{}
+// @Router /environments/{id}/comparison [get]
+func (h *EnvironmentHandler) GetComparison(c *gin.Context) {
+	id, ok := parsePairID(c)
+	if !ok {
+		return
+	}
+
+	comparison, err := h.service(c).Compare(id)
+	if err != nil {
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to compare environments", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": comparison,
+	})
+}
+
+// Compare handles POST /api/v1/compare
+// @Summary Crawl and compare an environment pair
+// @Description Crawls both URLs of the pair and compares the results like GET /environments/{id}/comparison, e.g. as a check before a release. Both crawls count against the daily quota. If they don't finish before the request times out, the latest completed crawls are compared, the answer is 202 with complete set to false, and the comparison can be fetched again once the crawls finish.
+// @Tags environments
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body models.CompareRequest true "Environment pair"
+// @Success 200 {object} models.EnvironmentComparison
+// @Success 202 {object} models.EnvironmentComparison
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 429 {object} map[string]interface{}
+// @Router /compare [post]
+func (h *EnvironmentHandler) Compare(c *gin.Context) {
+	var req models.CompareRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	service := h.service(c)
+	if _, err := service.GetPair(req.PairID); err != nil {
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to fetch environment pair", err))
+		return
+	}
+	if err := h.quotaService.ReserveCrawls(c.GetUint("user_id"), 2); err != nil {
+		if !respondQuotaExceeded(c, err) {
+			apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to check quota", err))
+		}
+		return
+	}
+
+	comparison, err := service.CrawlAndCompare(c.Request.Context(), req.PairID)
+	if err != nil {
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to compare environments", err))
+		return
+	}
+
+	status := http.StatusOK
+	if !comparison.Complete {
+		status = http.StatusAccepted
+	}
+	c.JSON(status, gin.H{
+		"data": comparison,
+	})
+}
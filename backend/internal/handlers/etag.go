@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// weakETag builds a weak ETag from a resource version, the request URL and
+// the organization of the request, so every filter, sort order and page of a
+// list in every organization gets its own tag
+func weakETag(c *gin.Context, version string) string {
+	c.Header("Vary", "X-Organization-ID")
+	sum := sha256.Sum256([]byte(c.Request.URL.RequestURI() + "\n" + c.GetHeader("X-Organization-ID") + "\n" + version))
+	return `W/"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// notModified sets the ETag of the response and answers 304 Not Modified if
+// the client already has this version. Handlers return when it reports true.
+func notModified(c *gin.Context, etag string) bool {
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "private, no-cache")
+
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return true
+	}
+	return false
+}
+
+// etagMatches compares an If-None-Match header with an ETag using the weak
+// comparison of RFC 9110, which ignores the W/ prefix
+func etagMatches(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"web-crawler-backend/internal/apperror"
+	"web-crawler-backend/internal/models"
+	"web-crawler-backend/internal/services"
+)
+
+type ExtractionRuleHandler struct {
+	ruleService *services.ExtractionRuleService
+}
+
+func NewExtractionRuleHandler(ruleService *services.ExtractionRuleService) *ExtractionRuleHandler {
+	return &ExtractionRuleHandler{ruleService: ruleService}
+}
+
+// service returns the extraction rule service bound to the request context
+func (h *ExtractionRuleHandler) service(c *gin.Context) *services.ExtractionRuleService {
+	return h.ruleService.WithContext(c.Request.Context())
+}
+
+// ListRules handles GET /api/v1/urls/:id/extraction-rules
+func (h *ExtractionRuleHandler) ListRules(c *gin.Context) {
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	rules, err := h.service(c).ListRules(id)
+	if err != nil {
+		h.respondError(c, err, "Failed to fetch extraction rules")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": rules,
+	})
+}
+
+// CreateRule handles POST /api/v1/urls/:id/extraction-rules
+func (h *ExtractionRuleHandler) CreateRule(c *gin.Context) {
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req models.ExtractionRuleRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	rule, err := h.service(c).CreateRule(id, req)
+	if err != nil {
+		h.respondError(c, err, "Failed to create extraction rule")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"data": rule,
+	})
+}
+
+// UpdateRule handles PUT /api/v1/urls/:id/extraction-rules/:rule_id
+func (h *ExtractionRuleHandler) UpdateRule(c *gin.Context) {
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+	ruleID, ok := parseRuleID(c)
+	if !ok {
+		return
+	}
+
+	var req models.ExtractionRuleRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	rule, err := h.service(c).UpdateRule(id, ruleID, req)
+	if err != nil {
+		h.respondError(c, err, "Failed to update extraction rule")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": rule,
+	})
+}
+
+// DeleteRule handles DELETE /api/v1/urls/:id/extraction-rules/:rule_id
+func (h *ExtractionRuleHandler) DeleteRule(c *gin.Context) {
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+	ruleID, ok := parseRuleID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service(c).DeleteRule(id, ruleID); err != nil {
+		h.respondError(c, err, "Failed to delete extraction rule")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Extraction rule deleted successfully",
+	})
+}
+
+func parseRuleID(c *gin.Context) (uint, bool) {
+	ruleID, err := strconv.ParseUint(c.Param("rule_id"), 10, 32)
+	if err != nil {
+		apperror.Abort(c, apperror.New(http.StatusBadRequest, "Invalid rule ID", "ID must be a valid number"))
+		return 0, false
+	}
+	return uint(ruleID), true
+}
+
+func (h *ExtractionRuleHandler) respondError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrInvalidExtractionRule):
+		apperror.Abort(c, apperror.Wrap(http.StatusBadRequest, "Invalid extraction rule", err))
+	case errors.Is(err, services.ErrExtractionRuleNotFound):
+		apperror.Abort(c, apperror.New(http.StatusNotFound, "Extraction rule not found", "The requested extraction rule does not exist for this URL"))
+	default:
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, fallback, err))
+	}
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"web-crawler-backend/internal/apperror"
+	"web-crawler-backend/internal/models"
+	"web-crawler-backend/internal/services"
+)
+
+type FeatureFlagHandler struct {
+	featureFlagService *services.FeatureFlagService
+}
+
+func NewFeatureFlagHandler(featureFlagService *services.FeatureFlagService) *FeatureFlagHandler {
+	return &FeatureFlagHandler{featureFlagService: featureFlagService}
+}
+
+// GetMyFeatures handles GET /api/v1/features
+// @Summary List the features available to the current user
+// @Tags features
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]interface{}
+// @Router /features [get]
+func (h *FeatureFlagHandler) GetMyFeatures(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"data": h.featureFlagService.EnabledFor(c.GetUint("user_id")),
+	})
+}
+
+// ListFeatureFlags handles GET /api/v1/admin/flags
+// @Summary List the feature flags
+// @Description Lists every feature flag with its default, stored rollout and environment override. Admins only.
+// @Tags features
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} models.FeatureFlagState
+// @Router /admin/flags [get]
+func (h *FeatureFlagHandler) ListFeatureFlags(c *gin.Context) {
+	flags, err := h.featureFlagService.List()
+	if err != nil {
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to fetch feature flags", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": flags,
+	})
+}
+
+// SetFeatureFlag handles PUT /api/v1/admin/flags/:name
+// @Summary Roll a feature out
+// @Description Turns a feature on for everyone or for the listed users. An override in FEATURE_FLAGS still wins. Admins only.
+// @Tags features
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param name path string true "Flag name"
+// @Param request body models.FeatureFlagRequest true "Rollout"
+// @Success 200 {object} models.FeatureFlagState
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /admin/flags/{name} [put]
+func (h *FeatureFlagHandler) SetFeatureFlag(c *gin.Context) {
+	var req models.FeatureFlagRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	flag, err := h.featureFlagService.Set(c.Param("name"), req)
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownFeatureFlag) {
+			respondUnknownFeatureFlag(c)
+			return
+		}
+
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to set feature flag", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": flag,
+	})
+}
+
+// ResetFeatureFlag handles DELETE /api/v1/admin/flags/:name
+// @Summary Reset a feature flag to its default
+// @Tags features
+// @Produce json
+// @Security ApiKeyAuth
+// @Param name path string true "Flag name"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /admin/flags/{name} [delete]
+func (h *FeatureFlagHandler) ResetFeatureFlag(c *gin.Context) {
+	if err := h.featureFlagService.Reset(c.Param("name")); err != nil {
+		if errors.Is(err, services.ErrUnknownFeatureFlag) {
+			respondUnknownFeatureFlag(c)
+			return
+		}
+
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to reset feature flag", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Feature flag reset to its default",
+	})
+}
+
+func respondUnknownFeatureFlag(c *gin.Context) {
+	apperror.Abort(c, apperror.New(http.StatusNotFound, "Feature flag not found", "No feature uses a flag with this name"))
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fieldSet is a sparse fieldset requested with ?fields=; a nil set keeps
+// every field
+type fieldSet map[string]bool
+
+// parseFields reads the comma separated fields parameter, which may name
+// the JSON fields of model. The id is always included so clients can tell
+// items apart.
+func parseFields(c *gin.Context, model interface{}) (fieldSet, error) {
+	param := strings.TrimSpace(c.Query("fields"))
+	if param == "" {
+		return nil, nil
+	}
+
+	allowed := jsonFields(reflect.TypeOf(model))
+	fields := fieldSet{"id": true}
+	for _, name := range strings.Split(param, ",") {
+		name = strings.TrimSpace(name)
+		if _, ok := allowed[name]; !ok {
+			names := make([]string, 0, len(allowed))
+			for allowedName := range allowed {
+				names = append(names, allowedName)
+			}
+			sort.Strings(names)
+			return nil, fmt.Errorf("unknown field %q; use %s", name, strings.Join(names, ", "))
+		}
+		fields[name] = true
+	}
+	return fields, nil
+}
+
+// serialize returns v, a struct or a slice of structs, with only the fields
+// of the set. Requested fields are kept even if they are empty.
+func (f fieldSet) serialize(v interface{}) interface{} {
+	if f == nil {
+		return v
+	}
+
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Slice {
+		return f.pick(value)
+	}
+	items := make([]map[string]interface{}, value.Len())
+	for i := range items {
+		items[i] = f.pick(value.Index(i))
+	}
+	return items
+}
+
+// pick copies the fields of the set from a struct or a pointer to one
+func (f fieldSet) pick(value reflect.Value) map[string]interface{} {
+	value = reflect.Indirect(value)
+	item := make(map[string]interface{}, len(f))
+	for name, index := range jsonFields(value.Type()) {
+		if f[name] {
+			item[name] = value.FieldByIndex(index).Interface()
+		}
+	}
+	return item
+}
+
+// jsonFields maps the JSON names of the fields of a struct type, including
+// those of embedded structs, to their field indexes
+func jsonFields(t reflect.Type) map[string][]int {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+
+	fields := make(map[string][]int)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if !field.IsExported() || tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			for embedded, index := range jsonFields(field.Type) {
+				fields[embedded] = append([]int{i}, index...)
+			}
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = []int{i}
+	}
+	return fields
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"web-crawler-backend/internal/apperror"
+	"web-crawler-backend/internal/models"
+	"web-crawler-backend/internal/services"
+)
+
+type IssueHandler struct {
+	issueService *services.IssueService
+}
+
+func NewIssueHandler(issueService *services.IssueService) *IssueHandler {
+	return &IssueHandler{issueService: issueService}
+}
+
+// service returns the issue service bound to the request context
+func (h *IssueHandler) service(c *gin.Context) *services.IssueService {
+	return h.issueService.WithContext(c.Request.Context())
+}
+
+// ListIssues handles GET /api/v1/urls/:id/issues
+// @Summary List the issues filed for a URL
+// @Description Lists the GitHub and Jira issues filed for the findings of the URL, newest first
+// @Tags issues
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "URL ID"
+// @Success 200 {array} models.ExternalIssue
+// @Failure 404 {object} map[string]interface{}
+// @Router /urls/{id}/issues [get]
+func (h *IssueHandler) ListIssues(c *gin.Context) {
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	issues, err := h.service(c).ListIssues(id)
+	if err != nil {
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to fetch issues", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": issues,
+	})
+}
+
+// CreateIssue handles POST /api/v1/urls/:id/issues
+// @Summary File an issue for a finding
+// @Description Opens a GitHub or Jira issue describing a broken link (keyed by the link URL) or failed SEO check (keyed by the check key) of the latest crawl of the URL. The link to the issue is shown as external_issue on the finding, in later crawls too. A finding gets one issue: filing it again answers 200 with the existing one.
+// @Tags issues
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "URL ID"
+// @Param request body models.CreateExternalIssueRequest true "Tracker and finding"
+// @Success 201 {object} models.ExternalIssue
+// @Success 200 {object} models.ExternalIssue
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 502 {object} map[string]interface{}
+// @Router /urls/{id}/issues [post]
+func (h *IssueHandler) CreateIssue(c *gin.Context) {
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req models.CreateExternalIssueRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	issue, created, err := h.service(c).CreateIssue(id, c.GetUint("user_id"), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrTrackerNotConfigured):
+			apperror.Abort(c, apperror.Wrap(http.StatusBadRequest, "Issue tracker not configured", err))
+		case errors.Is(err, services.ErrInvalidFinding):
+			apperror.Abort(c, apperror.Wrap(http.StatusBadRequest, "Invalid finding", err))
+		case errors.Is(err, services.ErrFindingNotFound):
+			apperror.Abort(c, apperror.Wrap(http.StatusNotFound, "Finding not found", err))
+		case errors.Is(err, services.ErrTrackerFailed):
+			apperror.Abort(c, apperror.Wrap(http.StatusBadGateway, "Failed to file issue", err))
+		default:
+			apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to file issue", err))
+		}
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	c.JSON(status, gin.H{
+		"data": issue,
+	})
+}
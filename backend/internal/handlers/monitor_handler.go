@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"web-crawler-backend/internal/apperror"
+	"web-crawler-backend/internal/models"
+	"web-crawler-backend/internal/services"
+)
+
+type MonitorHandler struct {
+	monitorService *services.MonitorService
+}
+
+func NewMonitorHandler(monitorService *services.MonitorService) *MonitorHandler {
+	return &MonitorHandler{monitorService: monitorService}
+}
+
+// GetMonitor handles GET /api/v1/urls/:id/monitor
+func (h *MonitorHandler) GetMonitor(c *gin.Context) {
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	monitor, err := h.monitorService.GetMonitor(id)
+	if err != nil {
+		if errors.Is(err, services.ErrMonitorNotFound) {
+			apperror.Abort(c, apperror.New(http.StatusNotFound, "Monitor not found", "This URL is not monitored"))
+			return
+		}
+
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to fetch monitor", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": monitor,
+	})
+}
+
+// SetMonitor handles PUT /api/v1/urls/:id/monitor
+func (h *MonitorHandler) SetMonitor(c *gin.Context) {
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req models.MonitorRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	monitor, err := h.monitorService.SetMonitor(id, req)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidMonitor) {
+			apperror.Abort(c, apperror.Wrap(http.StatusBadRequest, "Invalid monitor", err))
+			return
+		}
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to save monitor", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": monitor,
+	})
+}
+
+// DeleteMonitor handles DELETE /api/v1/urls/:id/monitor
+func (h *MonitorHandler) DeleteMonitor(c *gin.Context) {
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.monitorService.DeleteMonitor(id); err != nil {
+		if errors.Is(err, services.ErrMonitorNotFound) {
+			apperror.Abort(c, apperror.New(http.StatusNotFound, "Monitor not found", "This URL is not monitored"))
+			return
+		}
+
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to delete monitor", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Monitor deleted successfully",
+	})
+}
+
+// ListChecks handles GET /api/v1/urls/:id/monitor/checks
+func (h *MonitorHandler) ListChecks(c *gin.Context) {
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit <= 0 || limit > 500 {
+		limit = 50
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	checks, total, err := h.monitorService.ListChecks(id, limit, offset)
+	if err != nil {
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to fetch monitor checks", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": checks,
+		"pagination": gin.H{
+			"total":  total,
+			"limit":  limit,
+			"offset": offset,
+		},
+	})
+}
+
+// GetAvailability handles GET /api/v1/urls/:id/availability
+func (h *MonitorHandler) GetAvailability(c *gin.Context) {
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	availability, err := h.monitorService.GetAvailability(id, c.DefaultQuery("period", "24h"), time.Now())
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidPeriod) {
+			apperror.Abort(c, apperror.Wrap(http.StatusBadRequest, "Invalid period", err))
+			return
+		}
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to compute availability", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": availability,
+	})
+}
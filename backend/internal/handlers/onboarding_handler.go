@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"web-crawler-backend/internal/apperror"
+	"web-crawler-backend/internal/services"
+)
+
+type OnboardingHandler struct {
+	onboardingService *services.OnboardingService
+}
+
+func NewOnboardingHandler(onboardingService *services.OnboardingService) *OnboardingHandler {
+	return &OnboardingHandler{onboardingService: onboardingService}
+}
+
+// GetOnboarding handles GET /api/v1/onboarding
+func (h *OnboardingHandler) GetOnboarding(c *gin.Context) {
+	status, err := h.onboardingService.GetStatus(c.GetUint("user_id"))
+	if err != nil {
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to fetch onboarding state", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": status,
+	})
+}
+
+// CompleteStep handles POST /api/v1/onboarding/steps/:step
+func (h *OnboardingHandler) CompleteStep(c *gin.Context) {
+	status, err := h.onboardingService.CompleteStep(c.GetUint("user_id"), c.Param("step"))
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownOnboardingStep) {
+			apperror.Abort(c, apperror.New(http.StatusBadRequest, "Invalid onboarding step", "The step does not exist or is completed automatically"))
+			return
+		}
+
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to update onboarding state", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": status,
+	})
+}
+
+// CreateDemo handles POST /api/v1/onboarding/demo
+func (h *OnboardingHandler) CreateDemo(c *gin.Context) {
+	demo, err := h.onboardingService.CreateDemoCrawl(c.GetUint("user_id"))
+	if err != nil {
+		if errors.Is(err, services.ErrDemoDisabled) {
+			apperror.Abort(c, apperror.Wrap(http.StatusNotFound, "Demo crawl unavailable", err))
+			return
+		}
+
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to create demo crawl", err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"data":    demo,
+		"message": "Demo crawl started",
+	})
+}
+
+// RemoveDemo handles DELETE /api/v1/onboarding/demo
+func (h *OnboardingHandler) RemoveDemo(c *gin.Context) {
+	if err := h.onboardingService.RemoveDemo(c.GetUint("user_id")); err != nil {
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to remove demo crawl", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Demo crawl removed",
+	})
+}
@@ -0,0 +1,254 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"web-crawler-backend/internal/apperror"
+	"web-crawler-backend/internal/models"
+	"web-crawler-backend/internal/services"
+)
+
+type OrganizationHandler struct {
+	organizationService *services.OrganizationService
+}
+
+func NewOrganizationHandler(organizationService *services.OrganizationService) *OrganizationHandler {
+	return &OrganizationHandler{organizationService: organizationService}
+}
+
+// service returns the organization service bound to the request context
+func (h *OrganizationHandler) service(c *gin.Context) *services.OrganizationService {
+	return h.organizationService.WithContext(c.Request.Context())
+}
+
+// membership returns the membership of the current user in the organization
+// of the :org_id parameter, answering the request and returning nil if the
+// user is not a member of it
+func (h *OrganizationHandler) membership(c *gin.Context) *models.Membership {
+	orgID, err := strconv.ParseUint(c.Param("org_id"), 10, 32)
+	if err != nil {
+		apperror.Abort(c, apperror.New(http.StatusBadRequest, "Invalid organization ID", "ID must be a valid number"))
+		return nil
+	}
+
+	membership, err := h.service(c).GetMembership(uint(orgID), c.GetUint("user_id"))
+	if err != nil {
+		respondOrganizationError(c, err, "Failed to fetch organization")
+		return nil
+	}
+	return membership
+}
+
+// respondOrganizationError answers with the status matching an organization service error
+func respondOrganizationError(c *gin.Context, err error, failure string) {
+	switch {
+	case errors.Is(err, services.ErrOrganizationNotFound):
+		apperror.Abort(c, apperror.New(http.StatusNotFound, "Organization not found", "The organization does not exist or you are not a member of it"))
+	case errors.Is(err, services.ErrMemberNotFound):
+		apperror.Abort(c, apperror.New(http.StatusNotFound, "Member not found", "The user is not a member of the organization"))
+	case errors.Is(err, services.ErrUserNotFound):
+		apperror.Abort(c, apperror.New(http.StatusNotFound, "User not found", "The requested user does not exist"))
+	case errors.Is(err, services.ErrAlreadyMember):
+		apperror.Abort(c, apperror.Wrap(http.StatusConflict, "Already a member", err))
+	case errors.Is(err, services.ErrOrgPermission):
+		apperror.Abort(c, apperror.New(http.StatusForbidden, "Forbidden", "Your role in the organization doesn't allow this change"))
+	case errors.Is(err, services.ErrLastOwner):
+		apperror.Abort(c, apperror.Wrap(http.StatusConflict, "Last owner", err))
+	default:
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, failure, err))
+	}
+}
+
+// CreateOrganization handles POST /api/v1/orgs
+// @Summary Create an organization
+// @Description Creates an organization with the current user as its owner. Send its ID in the X-Organization-ID header to work on its URLs.
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body models.CreateOrganizationRequest true "Organization name"
+// @Success 201 {object} models.OrganizationWithRole
+// @Failure 400 {object} map[string]interface{}
+// @Router /orgs [post]
+func (h *OrganizationHandler) CreateOrganization(c *gin.Context) {
+	var req models.CreateOrganizationRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	org, err := h.service(c).CreateOrganization(c.GetUint("user_id"), req.Name)
+	if err != nil {
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to create organization", err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"data": org,
+	})
+}
+
+// ListOrganizations handles GET /api/v1/orgs
+// @Summary List organizations
+// @Description Lists the organizations of the current user with their role in each
+// @Tags organizations
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} models.OrganizationWithRole
+// @Router /orgs [get]
+func (h *OrganizationHandler) ListOrganizations(c *gin.Context) {
+	orgs, err := h.service(c).ListOrganizations(c.GetUint("user_id"))
+	if err != nil {
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to fetch organizations", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": orgs,
+	})
+}
+
+// ListMembers handles GET /api/v1/orgs/:org_id/members
+// @Summary List the members of an organization
+// @Tags organizations
+// @Produce json
+// @Security ApiKeyAuth
+// @Param org_id path int true "Organization ID"
+// @Success 200 {array} models.Membership
+// @Failure 404 {object} map[string]interface{}
+// @Router /orgs/{org_id}/members [get]
+func (h *OrganizationHandler) ListMembers(c *gin.Context) {
+	membership := h.membership(c)
+	if membership == nil {
+		return
+	}
+
+	members, err := h.service(c).ListMembers(membership.OrganizationID)
+	if err != nil {
+		respondOrganizationError(c, err, "Failed to fetch members")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": members,
+	})
+}
+
+// AddMember handles POST /api/v1/orgs/:org_id/members
+// @Summary Add a member to an organization
+// @Description Adds a user by username. Admins may add members; only owners may add owners.
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param org_id path int true "Organization ID"
+// @Param request body models.AddMemberRequest true "User and role"
+// @Success 201 {object} models.Membership
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /orgs/{org_id}/members [post]
+func (h *OrganizationHandler) AddMember(c *gin.Context) {
+	membership := h.membership(c)
+	if membership == nil {
+		return
+	}
+
+	var req models.AddMemberRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	member, err := h.service(c).AddMember(membership.OrganizationID, membership, req.Username, req.Role)
+	if err != nil {
+		respondOrganizationError(c, err, "Failed to add member")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"data": member,
+	})
+}
+
+// UpdateMember handles PUT /api/v1/orgs/:org_id/members/:user_id
+// @Summary Change the role of a member
+// @Description Admins may change the roles of non-owners; only owners may change owners or make others owner. The last owner can't be demoted.
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param org_id path int true "Organization ID"
+// @Param user_id path int true "User ID"
+// @Param request body models.UpdateMemberRequest true "New role"
+// @Success 200 {object} models.Membership
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /orgs/{org_id}/members/{user_id} [put]
+func (h *OrganizationHandler) UpdateMember(c *gin.Context) {
+	membership := h.membership(c)
+	if membership == nil {
+		return
+	}
+
+	userID, err := strconv.ParseUint(c.Param("user_id"), 10, 32)
+	if err != nil {
+		apperror.Abort(c, apperror.New(http.StatusBadRequest, "Invalid user ID", "ID must be a valid number"))
+		return
+	}
+
+	var req models.UpdateMemberRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	member, err := h.service(c).UpdateMemberRole(membership.OrganizationID, membership, uint(userID), req.Role)
+	if err != nil {
+		respondOrganizationError(c, err, "Failed to update member")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": member,
+	})
+}
+
+// RemoveMember handles DELETE /api/v1/orgs/:org_id/members/:user_id
+// @Summary Remove a member from an organization
+// @Description Members may leave on their own; admins may remove non-owners and owners anyone. The last owner can't leave.
+// @Tags organizations
+// @Produce json
+// @Security ApiKeyAuth
+// @Param org_id path int true "Organization ID"
+// @Param user_id path int true "User ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /orgs/{org_id}/members/{user_id} [delete]
+func (h *OrganizationHandler) RemoveMember(c *gin.Context) {
+	membership := h.membership(c)
+	if membership == nil {
+		return
+	}
+
+	userID, err := strconv.ParseUint(c.Param("user_id"), 10, 32)
+	if err != nil {
+		apperror.Abort(c, apperror.New(http.StatusBadRequest, "Invalid user ID", "ID must be a valid number"))
+		return
+	}
+
+	if err := h.service(c).RemoveMember(membership.OrganizationID, membership, uint(userID)); err != nil {
+		respondOrganizationError(c, err, "Failed to remove member")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Member removed successfully",
+	})
+}
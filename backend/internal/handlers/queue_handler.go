@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"web-crawler-backend/internal/apperror"
+	"web-crawler-backend/internal/services"
+)
+
+type QueueHandler struct {
+	crawlQueue *services.CrawlQueue
+}
+
+// NewQueueHandler creates the handler; a nil queue answers every request with 404
+func NewQueueHandler(crawlQueue *services.CrawlQueue) *QueueHandler {
+	return &QueueHandler{crawlQueue: crawlQueue}
+}
+
+// enabled reports whether crawls run through the queue, answering 404 if not
+func (h *QueueHandler) enabled(c *gin.Context) bool {
+	if h.crawlQueue == nil {
+		apperror.Abort(c, apperror.New(http.StatusNotFound, "Crawl queue disabled", "Crawls run in the API process; set CRAWL_QUEUE to use the queue"))
+		return false
+	}
+	return true
+}
+
+// GetQueue handles GET /api/v1/queue
+// @Summary Inspect the crawl queue
+// @Description Counts pending, scheduled, running and dead crawl tasks and the workers of all worker processes, and lists the oldest pending, the next scheduled and the newest dead tasks. Admins only.
+// @Tags queue
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} models.CrawlQueueStats
+// @Failure 404 {object} map[string]interface{}
+// @Router /queue [get]
+func (h *QueueHandler) GetQueue(c *gin.Context) {
+	if !h.enabled(c) {
+		return
+	}
+
+	stats, err := h.crawlQueue.Stats(c.Request.Context())
+	if err != nil {
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to read crawl queue", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// RetryDeadTask handles POST /api/v1/queue/dead/:task_id/retry
+// @Summary Retry a dead crawl task
+// @Description Moves a task out of the dead letter list and back into the queue with its retries reset. Admins only.
+// @Tags queue
+// @Produce json
+// @Security ApiKeyAuth
+// @Param task_id path string true "Task ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /queue/dead/{task_id}/retry [post]
+func (h *QueueHandler) RetryDeadTask(c *gin.Context) {
+	if !h.enabled(c) {
+		return
+	}
+
+	task, err := h.crawlQueue.RetryDead(c.Request.Context(), c.Param("task_id"))
+	if err != nil {
+		if errors.Is(err, services.ErrCrawlTaskNotFound) {
+			apperror.Abort(c, apperror.New(http.StatusNotFound, "Task not found", "The task is not in the dead letter list"))
+			return
+		}
+
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to retry task", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":    task,
+		"message": "Task queued again",
+	})
+}
+
+// RequeueTask handles POST /api/v1/queue/scheduled/:task_id/requeue
+// @Summary Requeue a scheduled crawl task now
+// @Description Moves a task waiting for its retry back into the queue right away. Admins only.
+// @Tags queue
+// @Produce json
+// @Security ApiKeyAuth
+// @Param task_id path string true "Task ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /queue/scheduled/{task_id}/requeue [post]
+func (h *QueueHandler) RequeueTask(c *gin.Context) {
+	if !h.enabled(c) {
+		return
+	}
+
+	task, err := h.crawlQueue.RequeueTask(c.Request.Context(), c.Param("task_id"))
+	if err != nil {
+		if errors.Is(err, services.ErrCrawlTaskNotFound) {
+			apperror.Abort(c, apperror.New(http.StatusNotFound, "Task not found", "The task is not waiting for a retry"))
+			return
+		}
+
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to requeue task", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":    task,
+		"message": "Task queued again",
+	})
+}
+
+// CancelTask handles DELETE /api/v1/queue/tasks/:task_id
+// @Summary Cancel a crawl task
+// @Description Removes a pending, scheduled or dead task from the queue. Tasks a worker is running can't be cancelled. Admins only.
+// @Tags queue
+// @Produce json
+// @Security ApiKeyAuth
+// @Param task_id path string true "Task ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /queue/tasks/{task_id} [delete]
+func (h *QueueHandler) CancelTask(c *gin.Context) {
+	if !h.enabled(c) {
+		return
+	}
+
+	if err := h.crawlQueue.CancelTask(c.Request.Context(), c.Param("task_id")); err != nil {
+		switch {
+		case errors.Is(err, services.ErrCrawlTaskNotFound):
+			apperror.Abort(c, apperror.New(http.StatusNotFound, "Task not found", "The task is not in the queue"))
+		case errors.Is(err, services.ErrCrawlTaskRunning):
+			apperror.Abort(c, apperror.New(http.StatusConflict, "Task running", "A worker is running the task; cancel its crawl instead"))
+		default:
+			apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to cancel task", err))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Task cancelled",
+	})
+}
+
+// PurgeDeadTasks handles DELETE /api/v1/queue/dead
+// @Summary Delete all dead crawl tasks
+// @Tags queue
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /queue/dead [delete]
+func (h *QueueHandler) PurgeDeadTasks(c *gin.Context) {
+	if !h.enabled(c) {
+		return
+	}
+
+	purged, err := h.crawlQueue.PurgeDead(c.Request.Context())
+	if err != nil {
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to purge dead tasks", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"purged":  purged,
+		"message": "Dead tasks purged",
+	})
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"web-crawler-backend/internal/models"
+	"web-crawler-backend/internal/services"
+)
+
+func setupQueueHandlerTest(crawlQueue *services.CrawlQueue) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	handler := NewQueueHandler(crawlQueue)
+	router.GET("/queue", handler.GetQueue)
+	router.POST("/queue/dead/:task_id/retry", handler.RetryDeadTask)
+	router.DELETE("/queue/dead", handler.PurgeDeadTasks)
+	router.POST("/queue/scheduled/:task_id/requeue", handler.RequeueTask)
+	router.DELETE("/queue/tasks/:task_id", handler.CancelTask)
+	return router
+}
+
+func TestQueueHandler(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+	crawlQueue := services.NewCrawlQueue(client, services.CrawlQueueOptions{})
+	router := setupQueueHandlerTest(crawlQueue)
+
+	task, err := crawlQueue.Enqueue(context.Background(), 1, models.CrawlPriorityHigh)
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/queue", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var stats models.CrawlQueueStats
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &stats))
+	assert.Equal(t, int64(1), stats.Pending)
+	assert.Equal(t, int64(1), stats.PendingHigh)
+	require.Len(t, stats.PendingTasks, 1)
+	assert.Equal(t, task.ID, stats.PendingTasks[0].ID)
+	assert.Empty(t, stats.DeadTasks)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/queue/scheduled/"+task.ID+"/requeue", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code, "pending tasks are not waiting for a retry")
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("DELETE", "/queue/tasks/"+task.ID, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("DELETE", "/queue/tasks/"+task.ID, nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/queue/dead/unknown/retry", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("DELETE", "/queue/dead", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"purged":0,"message":"Dead tasks purged"}`, w.Body.String())
+}
+
+func TestQueueHandler_Disabled(t *testing.T) {
+	router := setupQueueHandlerTest(nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/queue", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "Crawl queue disabled")
+}
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"web-crawler-backend/internal/apperror"
+	"web-crawler-backend/internal/models"
+	"web-crawler-backend/internal/services"
+)
+
+type QuotaHandler struct {
+	quotaService *services.QuotaService
+}
+
+func NewQuotaHandler(quotaService *services.QuotaService) *QuotaHandler {
+	return &QuotaHandler{quotaService: quotaService}
+}
+
+// respondQuotaExceeded answers with the details of an exceeded quota and
+// reports whether err was one. Exhausted daily crawls are answered with 429
+// and a Retry-After header, other quotas with 403.
+func respondQuotaExceeded(c *gin.Context, err error) bool {
+	var quotaErr *services.QuotaExceededError
+	if !errors.As(err, &quotaErr) {
+		return false
+	}
+
+	details := map[string]interface{}{
+		"quota": quotaErr.Quota,
+		"limit": quotaErr.Limit,
+		"used":  quotaErr.Used,
+	}
+	status := http.StatusForbidden
+	if quotaErr.ResetsAt != nil {
+		status = http.StatusTooManyRequests
+		details["resets_at"] = quotaErr.ResetsAt
+		retryAfter := int(math.Ceil(time.Until(*quotaErr.ResetsAt).Seconds()))
+		if retryAfter < 1 {
+			retryAfter = 1
+		}
+		c.Header("Retry-After", strconv.Itoa(retryAfter))
+	}
+	apperror.Abort(c, apperror.New(status, "Quota exceeded", quotaErr.Error()).WithCode("quota_exceeded").WithDetails(details))
+	return true
+}
+
+// GetQuota handles GET /api/v1/quota
+// @Summary Show quota usage
+// @Description Returns the plan and limits of the current user with how much of them is used. Zero limits are unlimited.
+// @Tags quota
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} models.QuotaUsage
+// @Router /quota [get]
+func (h *QuotaHandler) GetQuota(c *gin.Context) {
+	usage, err := h.quotaService.GetUsage(c.GetUint("user_id"))
+	if err != nil {
+		if errors.Is(err, services.ErrUserNotFound) {
+			apperror.Abort(c, apperror.New(http.StatusNotFound, "User not found", "The user of this token no longer exists"))
+			return
+		}
+
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to get quota", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": usage,
+	})
+}
+
+// SetUserQuota handles PUT /api/v1/quota/users/:user_id
+// @Summary Put a user on a plan
+// @Description Sets the plan of a user with limits overriding the default ones; omitted limits use the default. Admins only.
+// @Tags quota
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param user_id path int true "User ID"
+// @Param request body models.UserQuotaRequest true "Plan and limits"
+// @Success 200 {object} models.UserQuota
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /quota/users/{user_id} [put]
+func (h *QuotaHandler) SetUserQuota(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("user_id"), 10, 32)
+	if err != nil {
+		apperror.Abort(c, apperror.New(http.StatusBadRequest, "Invalid user ID", "ID must be a valid number"))
+		return
+	}
+
+	var req models.UserQuotaRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	quota, err := h.quotaService.SetUserQuota(uint(userID), req)
+	if err != nil {
+		if errors.Is(err, services.ErrUserNotFound) {
+			apperror.Abort(c, apperror.New(http.StatusNotFound, "User not found", "The requested user does not exist"))
+			return
+		}
+
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to set quota", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": quota,
+	})
+}
+
+// DeleteUserQuota handles DELETE /api/v1/quota/users/:user_id
+// @Summary Put a user back on the default plan
+// @Tags quota
+// @Produce json
+// @Security ApiKeyAuth
+// @Param user_id path int true "User ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /quota/users/{user_id} [delete]
+func (h *QuotaHandler) DeleteUserQuota(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("user_id"), 10, 32)
+	if err != nil {
+		apperror.Abort(c, apperror.New(http.StatusBadRequest, "Invalid user ID", "ID must be a valid number"))
+		return
+	}
+
+	if err := h.quotaService.DeleteUserQuota(uint(userID)); err != nil {
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to delete quota", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "User is back on the default plan",
+	})
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"web-crawler-backend/internal/models"
+	"web-crawler-backend/internal/services"
+)
+
+func TestQuotaHandler(t *testing.T) {
+	router, _, db := setupURLHandlerTest()
+	require.NoError(t, db.AutoMigrate(&models.User{}, &models.UserQuota{}, &models.CrawlUsage{}))
+	user := &models.User{Username: "alice", Email: "alice@example.com", Password: "secret"}
+	require.NoError(t, db.Create(user).Error)
+
+	quotaService := services.NewQuotaService(db, models.QuotaLimits{MaxURLs: 1, MaxCrawlsPerDay: 2})
+	urlHandler := NewURLHandler(services.NewURLService(db, &mockCrawlerServiceHandler{}), quotaService, nil)
+	handler := NewQuotaHandler(quotaService)
+	router.Use(func(c *gin.Context) { c.Set("user_id", user.ID) })
+	router.POST("/urls", urlHandler.CreateURL)
+	router.GET("/quota", handler.GetQuota)
+	router.PUT("/quota/users/:user_id", handler.SetUserQuota)
+
+	createURL := func(url string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		body, _ := json.Marshal(models.CrawlRequest{URL: url})
+		req := httptest.NewRequest("POST", "/urls", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	require.Equal(t, http.StatusCreated, createURL("https://example.com").Code)
+
+	// The second URL is over the URL quota
+	w := createURL("https://example.org")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	var quotaErr struct {
+		Code    string                 `json:"code"`
+		Details map[string]interface{} `json:"details"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &quotaErr))
+	assert.Equal(t, "quota_exceeded", quotaErr.Code)
+	assert.Equal(t, services.QuotaURLs, quotaErr.Details["quota"])
+	assert.Equal(t, float64(1), quotaErr.Details["limit"])
+
+	// A bigger plan allows more URLs, until the crawls of the day run out
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("PUT", "/quota/users/1", bytes.NewBufferString(`{"plan":"pro","max_urls":10}`)))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, http.StatusCreated, createURL("https://example.org").Code)
+	w = createURL("https://example.net")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/quota", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var usage struct {
+		Data models.QuotaUsage `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &usage))
+	assert.Equal(t, "pro", usage.Data.Plan)
+	assert.Equal(t, models.QuotaLimits{MaxURLs: 10, MaxCrawlsPerDay: 2}, usage.Data.Limits)
+	assert.Equal(t, int64(2), usage.Data.URLs)
+	assert.Equal(t, 2, usage.Data.CrawlsToday)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("PUT", "/quota/users/99", bytes.NewBufferString(`{"plan":"pro"}`)))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"web-crawler-backend/internal/apperror"
+	"web-crawler-backend/internal/models"
+	"web-crawler-backend/internal/services"
+)
+
+type ReportHandler struct {
+	reportService *services.ReportService
+}
+
+func NewReportHandler(reportService *services.ReportService) *ReportHandler {
+	return &ReportHandler{reportService: reportService}
+}
+
+// CreateBundle handles POST /api/v1/reports/bundle
+func (h *ReportHandler) CreateBundle(c *gin.Context) {
+	var req models.ReportBundleRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	if len(req.URLIDs) == 0 {
+		apperror.Abort(c, apperror.New(http.StatusBadRequest, "No IDs provided", "At least one URL ID must be provided"))
+		return
+	}
+
+	bundle, err := h.reportService.WithOrganization(c.GetUint("organization_id")).CreateBundle(c.GetUint("user_id"), req.URLIDs)
+	if err != nil {
+		apperror.Abort(c, apperror.Wrap(http.StatusBadRequest, "Failed to create report bundle", err))
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"data":    bundle,
+		"message": "Report bundle generation started",
+	})
+}
+
+// GetBundle handles GET /api/v1/reports/bundle/:id
+func (h *ReportHandler) GetBundle(c *gin.Context) {
+	bundle, ok := h.findBundle(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": bundle,
+	})
+}
+
+// DownloadBundle handles GET /api/v1/reports/bundle/:id/download
+func (h *ReportHandler) DownloadBundle(c *gin.Context) {
+	bundle, ok := h.findBundle(c)
+	if !ok {
+		return
+	}
+
+	if bundle.Status != "completed" {
+		apperror.Abort(c, apperror.New(http.StatusConflict, "Report bundle not ready", fmt.Sprintf("Report bundle is %s", bundle.Status)))
+		return
+	}
+
+	file, size, err := h.reportService.WithContext(c.Request.Context()).OpenBundle(bundle)
+	if err != nil {
+		if errors.Is(err, services.ErrBundleNotFound) {
+			apperror.Abort(c, apperror.New(http.StatusNotFound, "Report bundle not found", "The report bundle archive no longer exists"))
+			return
+		}
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to open report bundle", err))
+		return
+	}
+	defer file.Close()
+
+	c.DataFromReader(http.StatusOK, size, "application/zip", file, map[string]string{
+		"Content-Disposition": fmt.Sprintf(`attachment; filename="report-bundle-%d.zip"`, bundle.ID),
+	})
+}
+
+// findBundle loads the bundle named in the path, writing an error response if it can't
+func (h *ReportHandler) findBundle(c *gin.Context) (*models.ReportBundle, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apperror.Abort(c, apperror.New(http.StatusBadRequest, "Invalid bundle ID", "ID must be a valid number"))
+		return nil, false
+	}
+
+	bundle, err := h.reportService.GetBundle(c.GetUint("user_id"), uint(id))
+	if err != nil {
+		if errors.Is(err, services.ErrBundleNotFound) {
+			apperror.Abort(c, apperror.New(http.StatusNotFound, "Report bundle not found", "The requested report bundle does not exist"))
+			return nil, false
+		}
+
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to fetch report bundle", err))
+		return nil, false
+	}
+
+	return bundle, true
+}
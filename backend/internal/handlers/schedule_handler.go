@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"web-crawler-backend/internal/apperror"
+	"web-crawler-backend/internal/models"
+	"web-crawler-backend/internal/services"
+)
+
+type ScheduleHandler struct {
+	schedulerService *services.SchedulerService
+}
+
+func NewScheduleHandler(schedulerService *services.SchedulerService) *ScheduleHandler {
+	return &ScheduleHandler{schedulerService: schedulerService}
+}
+
+// GetSchedule handles GET /api/v1/urls/:id/schedule
+func (h *ScheduleHandler) GetSchedule(c *gin.Context) {
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	schedule, err := h.schedulerService.GetSchedule(id)
+	if err != nil {
+		if errors.Is(err, services.ErrScheduleNotFound) {
+			apperror.Abort(c, apperror.New(http.StatusNotFound, "Schedule not found", "This URL has no crawl schedule"))
+			return
+		}
+
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to fetch schedule", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": schedule,
+	})
+}
+
+// SetSchedule handles PUT /api/v1/urls/:id/schedule
+func (h *ScheduleHandler) SetSchedule(c *gin.Context) {
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req models.CrawlScheduleRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	schedule, err := h.schedulerService.SetSchedule(id, req)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidSchedule) {
+			apperror.Abort(c, apperror.Wrap(http.StatusBadRequest, "Invalid schedule", err))
+			return
+		}
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to save schedule", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": schedule,
+	})
+}
+
+// DeleteSchedule handles DELETE /api/v1/urls/:id/schedule
+func (h *ScheduleHandler) DeleteSchedule(c *gin.Context) {
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.schedulerService.DeleteSchedule(id); err != nil {
+		if errors.Is(err, services.ErrScheduleNotFound) {
+			apperror.Abort(c, apperror.New(http.StatusNotFound, "Schedule not found", "This URL has no crawl schedule"))
+			return
+		}
+
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to delete schedule", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Schedule deleted successfully",
+	})
+}
+
+// parseIDParam reads the :id path parameter, writing a 400 response if it is invalid
+func parseIDParam(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apperror.Abort(c, apperror.New(http.StatusBadRequest, "Invalid URL ID", "ID must be a valid number"))
+		return 0, false
+	}
+	return uint(id), true
+}
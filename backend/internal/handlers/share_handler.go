@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"web-crawler-backend/internal/apperror"
+	"web-crawler-backend/internal/models"
+	"web-crawler-backend/internal/services"
+)
+
+type ShareHandler struct {
+	shareService *services.ShareService
+}
+
+func NewShareHandler(shareService *services.ShareService) *ShareHandler {
+	return &ShareHandler{shareService: shareService}
+}
+
+// service returns the share service bound to the request context
+func (h *ShareHandler) service(c *gin.Context) *services.ShareService {
+	return h.shareService.WithContext(c.Request.Context())
+}
+
+// CreateShare handles POST /api/v1/urls/:id/share
+// @Summary Share the crawl results of a URL
+// @Description Creates a signed link that shows the crawl results of the URL without authentication until it expires. Links can't be revoked; deleting the URL disables them.
+// @Tags urls
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "URL ID"
+// @Param request body models.ShareRequest false "Link lifetime"
+// @Success 201 {object} models.ShareLink
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /urls/{id}/share [post]
+func (h *ShareHandler) CreateShare(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apperror.Abort(c, apperror.New(http.StatusBadRequest, "Invalid URL ID", "ID must be a valid number"))
+		return
+	}
+
+	var req models.ShareRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		apperror.Abort(c, apperror.Wrap(http.StatusBadRequest, "Invalid request body", err))
+		return
+	}
+
+	ttl := time.Duration(req.ExpiresInHours) * time.Hour
+	link, err := h.service(c).CreateShare(uint(id), c.GetUint("user_id"), ttl)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidShareTTL) {
+			apperror.Abort(c, apperror.Wrap(http.StatusBadRequest, "Invalid expiry", err))
+			return
+		}
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to share URL", err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"data": link,
+	})
+}
+
+// GetSharedReport handles GET /api/v1/public/reports/:token
+// @Summary Show a shared report
+// @Description Shows the crawl results a share link points to. Needs no authentication.
+// @Tags public
+// @Produce json
+// @Param token path string true "Share token"
+// @Success 200 {object} models.SharedReport
+// @Failure 404 {object} map[string]interface{}
+// @Failure 410 {object} map[string]interface{}
+// @Router /public/reports/{token} [get]
+func (h *ShareHandler) GetSharedReport(c *gin.Context) {
+	report, err := h.service(c).GetSharedReport(c.Param("token"))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrShareExpired):
+			apperror.Abort(c, apperror.New(http.StatusGone, "Link expired", "This report link has expired"))
+		case errors.Is(err, services.ErrShareNotFound):
+			apperror.Abort(c, apperror.New(http.StatusNotFound, "Report not found", "The report link is invalid or the URL no longer exists"))
+		default:
+			apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to get report", err))
+		}
+		return
+	}
+
+	// Caches would keep serving the report after the link expires
+	c.Header("Cache-Control", "private, no-store")
+	c.JSON(http.StatusOK, gin.H{
+		"data": report,
+	})
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"web-crawler-backend/internal/models"
+	"web-crawler-backend/internal/services"
+)
+
+func TestShareHandler(t *testing.T) {
+	router, _, db := setupURLHandlerTest()
+	crawler := &mockCrawlerServiceHandler{}
+	shareService := services.NewShareService(db, services.NewURLService(db, crawler), crawler, services.ShareOptions{
+		Secret: "secret",
+		TTL:    time.Hour,
+		MaxTTL: 24 * time.Hour,
+	})
+	handler := NewShareHandler(shareService)
+	router.POST("/urls/:id/share", handler.CreateShare)
+	router.GET("/public/reports/:token", handler.GetSharedReport)
+
+	url := &models.URL{URL: "https://example.com", Title: "Example", Status: "completed"}
+	require.NoError(t, db.Create(url).Error)
+
+	// The body is optional
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/urls/1/share", nil))
+	require.Equal(t, http.StatusCreated, w.Code)
+	var created struct {
+		Data models.ShareLink `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.NotEmpty(t, created.Data.Token)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/public/reports/"+created.Data.Token, nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "private, no-store", w.Header().Get("Cache-Control"))
+	var shared struct {
+		Data models.SharedReport `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &shared))
+	assert.Equal(t, "https://example.com", shared.Data.URL)
+	assert.Equal(t, "Example", shared.Data.Title)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/urls/1/share", bytes.NewBufferString(`{"expires_in_hours":48}`)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/urls/99/share", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/public/reports/not-a-token", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
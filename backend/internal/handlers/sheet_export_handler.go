@@ -0,0 +1,281 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"path"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"web-crawler-backend/internal/apperror"
+	"web-crawler-backend/internal/models"
+	"web-crawler-backend/internal/services"
+)
+
+// googleNonceCookie keeps the nonce of a Google authorization in the browser
+// that started it, for the callback to check
+const googleNonceCookie = "google_oauth_nonce"
+
+type SheetExportHandler struct {
+	sheetExportService *services.SheetExportService
+	// secureCookies limits the nonce cookie to HTTPS
+	secureCookies bool
+}
+
+func NewSheetExportHandler(sheetExportService *services.SheetExportService, secureCookies bool) *SheetExportHandler {
+	return &SheetExportHandler{sheetExportService: sheetExportService, secureCookies: secureCookies}
+}
+
+// setNonceCookie stores the authorization nonce in an HttpOnly cookie sent
+// only to the Google integration routes; a negative maxAge deletes it. It is
+// lax so Google's redirect to the callback carries it.
+func (h *SheetExportHandler) setNonceCookie(c *gin.Context, nonce string, maxAge int) {
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     googleNonceCookie,
+		Value:    nonce,
+		Path:     path.Dir(c.Request.URL.Path),
+		MaxAge:   maxAge,
+		Secure:   h.secureCookies,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// service returns the sheet export service of the organization of the
+// request, bound to the request context
+func (h *SheetExportHandler) service(c *gin.Context) *services.SheetExportService {
+	return h.sheetExportService.WithContext(c.Request.Context()).WithOrganization(c.GetUint("organization_id"))
+}
+
+// parseSheetExportID reads the export ID path parameter, answering 400 if it isn't a number
+func parseSheetExportID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apperror.Abort(c, apperror.New(http.StatusBadRequest, "Invalid export ID", "ID must be a valid number"))
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// respondSheetExportError answers with the status matching a sheet export service error
+func respondSheetExportError(c *gin.Context, err error, failure string) {
+	switch {
+	case errors.Is(err, services.ErrInvalidSheetExport):
+		apperror.Abort(c, apperror.Wrap(http.StatusBadRequest, "Invalid export", err))
+	case errors.Is(err, services.ErrInvalidGoogleState):
+		apperror.Abort(c, apperror.Wrap(http.StatusBadRequest, "Invalid authorization", err))
+	case errors.Is(err, services.ErrGoogleNotConnected):
+		apperror.Abort(c, apperror.New(http.StatusConflict, "Google account not connected",
+			"Connect a Google account before exporting to Google Sheets"))
+	case errors.Is(err, services.ErrSheetsFailed):
+		apperror.Abort(c, apperror.Wrap(http.StatusBadGateway, "Google Sheets request failed", err))
+	default:
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, failure, err))
+	}
+}
+
+// GetGoogleConnection handles GET /api/v1/integrations/google
+// @Summary Get the Google account connection
+// @Description Tells whether Google Sheets exports are enabled on the server and whether the user connected a Google account
+// @Tags sheet-exports
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} models.GoogleConnectionStatus
+// @Router /integrations/google [get]
+func (h *SheetExportHandler) GetGoogleConnection(c *gin.Context) {
+	status, err := h.service(c).GetConnection(c.GetUint("user_id"))
+	if err != nil {
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to fetch Google connection", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": status,
+	})
+}
+
+// AuthorizeGoogle handles GET /api/v1/integrations/google/authorize
+// @Summary Start connecting a Google account
+// @Description Returns the URL of Google's consent page. After the user grants access to their spreadsheets, Google redirects to the callback, which stores the authorization. The URL is valid for 10 minutes, and only in the browser that requested it: the response sets a cookie the callback checks.
+// @Tags sheet-exports
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 501 {object} map[string]interface{}
+// @Router /integrations/google/authorize [get]
+func (h *SheetExportHandler) AuthorizeGoogle(c *gin.Context) {
+	url, nonce, err := h.service(c).AuthorizeURL(c.GetUint("user_id"))
+	if err != nil {
+		respondSheetExportError(c, err, "Failed to start Google authorization")
+		return
+	}
+	h.setNonceCookie(c, nonce, int(services.GoogleStateTTL.Seconds()))
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": gin.H{"url": url},
+	})
+}
+
+// GoogleCallback handles GET /api/v1/integrations/google/callback
+// @Summary Complete connecting a Google account
+// @Description Google redirects here from its consent page. The state identifies the user who started the authorization, and must match the cookie set when it started.
+// @Tags sheet-exports
+// @Produce json
+// @Param state query string true "State from the authorization URL"
+// @Param code query string false "Authorization code"
+// @Param error query string false "Error, when the user denied access"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 502 {object} map[string]interface{}
+// @Router /integrations/google/callback [get]
+func (h *SheetExportHandler) GoogleCallback(c *gin.Context) {
+	// The nonce is good for one attempt
+	nonce, _ := c.Cookie(googleNonceCookie)
+	h.setNonceCookie(c, "", -1)
+
+	if denied := c.Query("error"); denied != "" {
+		apperror.Abort(c, apperror.New(http.StatusBadRequest, "Authorization denied", "Google reported: "+denied))
+		return
+	}
+	code := c.Query("code")
+	if code == "" {
+		apperror.Abort(c, apperror.New(http.StatusBadRequest, "Invalid authorization", "Missing authorization code"))
+		return
+	}
+
+	if err := h.service(c).Connect(c.Query("state"), nonce, code); err != nil {
+		respondSheetExportError(c, err, "Failed to connect Google account")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Google account connected",
+	})
+}
+
+// DisconnectGoogle handles DELETE /api/v1/integrations/google
+// @Summary Disconnect the Google account
+// @Description Forgets the user's Google authorization. Their exports fail until they connect again.
+// @Tags sheet-exports
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /integrations/google [delete]
+func (h *SheetExportHandler) DisconnectGoogle(c *gin.Context) {
+	if err := h.service(c).Disconnect(c.GetUint("user_id")); err != nil {
+		respondSheetExportError(c, err, "Failed to disconnect Google account")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Google account disconnected",
+	})
+}
+
+// CreateExport handles POST /api/v1/sheet-exports
+// @Summary Export to Google Sheets
+// @Description Writes the URL list (kind urls) or the broken link report (kind broken_links) of the organization's URLs into a spreadsheet, replacing its first sheet. Without a spreadsheet_id a spreadsheet is created in the user's Google Drive. With interval_minutes, at least 60, the export is written again periodically with the user's Google account.
+// @Tags sheet-exports
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body models.CreateSheetExportRequest true "Export"
+// @Success 201 {object} models.SheetExport
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 502 {object} map[string]interface{}
+// @Router /sheet-exports [post]
+func (h *SheetExportHandler) CreateExport(c *gin.Context) {
+	var req models.CreateSheetExportRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	export, err := h.service(c).CreateExport(c.GetUint("user_id"), &req)
+	if err != nil && export == nil {
+		respondSheetExportError(c, err, "Failed to create export")
+		return
+	}
+
+	// An export whose first run failed is kept with the error, for the
+	// schedule to retry
+	c.JSON(http.StatusCreated, gin.H{
+		"data": export,
+	})
+}
+
+// ListExports handles GET /api/v1/sheet-exports
+// @Summary List Google Sheets exports
+// @Description Lists the exports, newest first, with the time, row count and error of their last run
+// @Tags sheet-exports
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} models.SheetExport
+// @Router /sheet-exports [get]
+func (h *SheetExportHandler) ListExports(c *gin.Context) {
+	exports, err := h.service(c).ListExports()
+	if err != nil {
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to fetch exports", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": exports,
+	})
+}
+
+// RunExport handles POST /api/v1/sheet-exports/:id/run
+// @Summary Run a Google Sheets export now
+// @Description Writes the export again without waiting for its interval
+// @Tags sheet-exports
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Export ID"
+// @Success 200 {object} models.SheetExport
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 502 {object} map[string]interface{}
+// @Router /sheet-exports/{id}/run [post]
+func (h *SheetExportHandler) RunExport(c *gin.Context) {
+	id, ok := parseSheetExportID(c)
+	if !ok {
+		return
+	}
+
+	export, err := h.service(c).RunExport(id)
+	if err != nil {
+		respondSheetExportError(c, err, "Failed to run export")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": export,
+	})
+}
+
+// DeleteExport handles DELETE /api/v1/sheet-exports/:id
+// @Summary Delete a Google Sheets export
+// @Description Stops the export; the spreadsheet is kept
+// @Tags sheet-exports
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Export ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /sheet-exports/{id} [delete]
+func (h *SheetExportHandler) DeleteExport(c *gin.Context) {
+	id, ok := parseSheetExportID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service(c).DeleteExport(id); err != nil {
+		respondSheetExportError(c, err, "Failed to delete export")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Export deleted",
+	})
+}
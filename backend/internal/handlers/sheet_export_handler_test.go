@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"web-crawler-backend/internal/googlesheets"
+	"web-crawler-backend/internal/services"
+)
+
+func TestSheetExportHandler_GoogleAuthorizationCookie(t *testing.T) {
+	router, _, db := setupURLHandlerTest()
+	client, err := googlesheets.New(googlesheets.Options{
+		ClientID:     "client",
+		ClientSecret: "secret",
+		RedirectURL:  "https://crawler.example.com/integrations/google/callback",
+	})
+	require.NoError(t, err)
+	handler := NewSheetExportHandler(services.NewSheetExportService(db, client, "state-secret"), true)
+	router.Use(func(c *gin.Context) { c.Set("user_id", uint(1)) })
+	router.GET("/integrations/google/authorize", handler.AuthorizeGoogle)
+	router.GET("/integrations/google/callback", handler.GoogleCallback)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/integrations/google/authorize", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	cookies := w.Result().Cookies()
+	require.Len(t, cookies, 1)
+	nonce := cookies[0]
+	assert.Equal(t, googleNonceCookie, nonce.Name)
+	assert.NotEmpty(t, nonce.Value)
+	assert.Equal(t, "/integrations/google", nonce.Path)
+	assert.True(t, nonce.HttpOnly)
+	assert.True(t, nonce.Secure)
+
+	var authorization struct {
+		Data struct {
+			URL string `json:"url"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &authorization))
+	consent, err := url.Parse(authorization.Data.URL)
+	require.NoError(t, err)
+	callback := "/integrations/google/callback?code=code-1&state=" + url.QueryEscape(consent.Query().Get("state"))
+
+	// A state sent from a browser without the cookie, or with another
+	// authorization's, doesn't connect anything
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", callback, nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	req := httptest.NewRequest("GET", callback, nil)
+	req.AddCookie(&http.Cookie{Name: googleNonceCookie, Value: "other"})
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	cleared := w.Result().Cookies()
+	require.Len(t, cleared, 1)
+	assert.Negative(t, cleared[0].MaxAge)
+}
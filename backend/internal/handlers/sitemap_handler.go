@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"web-crawler-backend/internal/apperror"
+	"web-crawler-backend/internal/models"
+	"web-crawler-backend/internal/services"
+)
+
+type SitemapHandler struct {
+	sitemapService *services.SitemapService
+}
+
+func NewSitemapHandler(sitemapService *services.SitemapService) *SitemapHandler {
+	return &SitemapHandler{sitemapService: sitemapService}
+}
+
+// service returns the sitemap service of the organization of the request,
+// bound to the request context
+func (h *SitemapHandler) service(c *gin.Context) *services.SitemapService {
+	return h.sitemapService.WithContext(c.Request.Context()).WithOrganization(c.GetUint("organization_id"))
+}
+
+// parseSitemapID reads the sitemap ID path parameter, answering 400 if it isn't a number
+func parseSitemapID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apperror.Abort(c, apperror.New(http.StatusBadRequest, "Invalid sitemap ID", "ID must be a valid number"))
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// respondSitemapError answers with the status matching a sitemap service error
+func respondSitemapError(c *gin.Context, err error, failure string) {
+	switch {
+	case errors.Is(err, services.ErrInvalidSitemap), errors.Is(err, services.ErrInvalidURL):
+		apperror.Abort(c, apperror.Wrap(http.StatusBadRequest, "Invalid sitemap", err))
+	case errors.Is(err, services.ErrURLNotAllowed):
+		apperror.Abort(c, apperror.Wrap(http.StatusBadRequest, "URL not allowed", err))
+	default:
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, failure, err))
+	}
+}
+
+// ImportSitemap handles POST /api/v1/sitemaps
+// @Summary Import a sitemap
+// @Description Fetches an XML sitemap, or the sitemaps of a sitemap index, and adds and crawls the pages it lists as URLs. Pages over the URL or crawl quota are skipped. With a sync interval the sitemap is fetched again periodically: new pages are added, pages no longer listed are flagged, and both are reported in the activity feed as sitemap.drift.
+// @Tags sitemaps
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body models.ImportSitemapRequest true "Sitemap URL and sync interval"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /sitemaps [post]
+func (h *SitemapHandler) ImportSitemap(c *gin.Context) {
+	var req models.ImportSitemapRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	sitemap, result, err := h.service(c).Import(c.GetUint("user_id"), &req)
+	if err != nil {
+		respondSitemapError(c, err, "Failed to import sitemap")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"data":   sitemap,
+		"result": result,
+	})
+}
+
+// ListSitemaps handles GET /api/v1/sitemaps
+// @Summary List imported sitemaps
+// @Description Lists the imported sitemaps, newest first, with the time and error of their last sync
+// @Tags sitemaps
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} models.Sitemap
+// @Router /sitemaps [get]
+func (h *SitemapHandler) ListSitemaps(c *gin.Context) {
+	sitemaps, err := h.service(c).ListSitemaps()
+	if err != nil {
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to fetch sitemaps", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": sitemaps,
+	})
+}
+
+// GetEntries handles GET /api/v1/sitemaps/:id/entries
+// @Summary List the pages of a sitemap
+// @Description Lists the pages imported from a sitemap with their URL IDs. Pages a sync found no longer listed have removed_at set.
+// @Tags sitemaps
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Sitemap ID"
+// @Param removed query bool false "Only pages no longer listed"
+// @Param limit query int false "Number of items to return (default: 20, max: 100)"
+// @Param offset query int false "Number of items to skip (default: 0)"
+// @Success 200 {array} models.SitemapEntry
+// @Failure 404 {object} map[string]interface{}
+// @Router /sitemaps/{id}/entries [get]
+func (h *SitemapHandler) GetEntries(c *gin.Context) {
+	id, ok := parseSitemapID(c)
+	if !ok {
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	entries, total, err := h.service(c).GetEntries(id, c.Query("removed") == "true", limit, offset)
+	if err != nil {
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to fetch sitemap entries", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": entries,
+		"pagination": gin.H{
+			"total":  total,
+			"limit":  limit,
+			"offset": offset,
+		},
+	})
+}
+
+// SyncSitemap handles POST /api/v1/sitemaps/:id/sync
+// @Summary Sync a sitemap now
+// @Description Fetches the sitemap again without waiting for its sync interval, adding new pages and flagging removed ones
+// @Tags sitemaps
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Sitemap ID"
+// @Success 200 {object} models.SitemapSyncResult
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /sitemaps/{id}/sync [post]
+func (h *SitemapHandler) SyncSitemap(c *gin.Context) {
+	id, ok := parseSitemapID(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.service(c).Sync(id)
+	if err != nil {
+		respondSitemapError(c, err, "Failed to sync sitemap")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": result,
+	})
+}
+
+// DeleteSitemap handles DELETE /api/v1/sitemaps/:id
+// @Summary Delete a sitemap
+// @Description Stops syncing the sitemap; the URLs it added are kept
+// @Tags sitemaps
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Sitemap ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /sitemaps/{id} [delete]
+func (h *SitemapHandler) DeleteSitemap(c *gin.Context) {
+	id, ok := parseSitemapID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service(c).DeleteSitemap(id); err != nil {
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to delete sitemap", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Sitemap deleted",
+	})
+}
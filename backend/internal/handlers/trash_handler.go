@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"web-crawler-backend/internal/apperror"
+	"web-crawler-backend/internal/services"
+)
+
+type TrashHandler struct {
+	trashService *services.TrashService
+}
+
+func NewTrashHandler(trashService *services.TrashService) *TrashHandler {
+	return &TrashHandler{trashService: trashService}
+}
+
+// service returns the trash service of the organization of the request, bound
+// to the request context
+func (h *TrashHandler) service(c *gin.Context) *services.TrashService {
+	return h.trashService.WithContext(c.Request.Context()).WithOrganization(c.GetUint("organization_id"))
+}
+
+// ListTrash handles GET /api/v1/urls/trash
+// @Summary List deleted URLs
+// @Tags urls
+// @Produce json
+// @Security ApiKeyAuth
+// @Param limit query int false "Page size, at most 100" default(20)
+// @Param offset query int false "Number of URLs to skip" default(0)
+// @Success 200 {object} map[string]interface{}
+// @Router /urls/trash [get]
+func (h *TrashHandler) ListTrash(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	urls, total, err := h.service(c).ListTrash(limit, offset)
+	if err != nil {
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to fetch deleted URLs", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": urls,
+		"pagination": gin.H{
+			"total":  total,
+			"limit":  limit,
+			"offset": offset,
+		},
+	})
+}
+
+// RestoreURL handles POST /api/v1/urls/:id/restore
+// @Summary Restore a deleted URL
+// @Tags urls
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "URL ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /urls/{id}/restore [post]
+func (h *TrashHandler) RestoreURL(c *gin.Context) {
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	url, err := h.service(c).RestoreURL(id)
+	if err != nil {
+		if errors.Is(err, services.ErrURLNotFound) {
+			apperror.Abort(c, services.ErrURLNotFound.WithMessage("The requested URL is not in the trash"))
+			return
+		}
+
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to restore URL", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":    url,
+		"message": "URL restored successfully",
+	})
+}
+
+// PurgeURL handles DELETE /api/v1/urls/:id/purge
+// @Summary Permanently delete a URL from the trash
+// @Tags urls
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "URL ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /urls/{id}/purge [delete]
+func (h *TrashHandler) PurgeURL(c *gin.Context) {
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service(c).PurgeURL(id); err != nil {
+		if errors.Is(err, services.ErrURLNotFound) {
+			apperror.Abort(c, services.ErrURLNotFound.WithMessage("The requested URL is not in the trash"))
+			return
+		}
+		if errors.Is(err, services.ErrCrawlRunning) {
+			apperror.Abort(c, apperror.New(http.StatusConflict, "Crawl running", "The URL is still being crawled; purge it once the crawl finishes"))
+			return
+		}
+
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to purge URL", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "URL purged successfully",
+	})
+}
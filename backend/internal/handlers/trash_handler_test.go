@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"web-crawler-backend/internal/models"
+	"web-crawler-backend/internal/services"
+)
+
+func TestTrashHandler(t *testing.T) {
+	router, urlHandler, db := setupURLHandlerTest()
+	require.NoError(t, db.AutoMigrate(&models.CrawlSchedule{}))
+	handler := NewTrashHandler(services.NewTrashService(db, time.Hour))
+
+	router.GET("/urls/trash", handler.ListTrash)
+	router.GET("/urls/:id", urlHandler.GetURL)
+	router.POST("/urls/:id/restore", handler.RestoreURL)
+	router.DELETE("/urls/:id/purge", handler.PurgeURL)
+
+	url := &models.URL{URL: "https://example.com", Status: "completed"}
+	require.NoError(t, db.Create(url).Error)
+	require.NoError(t, db.Delete(url).Error)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/urls/trash", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var list struct {
+		Data []struct {
+			ID        uint       `json:"id"`
+			DeletedAt time.Time  `json:"deleted_at"`
+			PurgeAt   *time.Time `json:"purge_at"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Len(t, list.Data, 1)
+	assert.Equal(t, url.ID, list.Data[0].ID)
+	assert.NotNil(t, list.Data[0].PurgeAt)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/urls/1/restore", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("DELETE", "/urls/1/purge", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code, "restored URLs are not in the trash")
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/urls/1", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -1,23 +1,122 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"web-crawler-backend/internal/apperror"
 	"web-crawler-backend/internal/models"
 	"web-crawler-backend/internal/services"
 )
 
 type URLHandler struct {
-	urlService *services.URLService
+	urlService   services.URLServiceInterface
+	quotaService *services.QuotaService
+	featureFlags *services.FeatureFlagService
 }
 
-func NewURLHandler(urlService *services.URLService) *URLHandler {
-	return &URLHandler{urlService: urlService}
+// NewURLHandler creates the handler; a nil quota service enforces no quotas
+// and nil feature flags use their defaults
+func NewURLHandler(urlService services.URLServiceInterface, quotaService *services.QuotaService, featureFlags *services.FeatureFlagService) *URLHandler {
+	return &URLHandler{urlService: urlService, quotaService: quotaService, featureFlags: featureFlags}
+}
+
+// service returns the URL service limited to the organization of the request,
+// with its queries traced as part of the request
+func (h *URLHandler) service(c *gin.Context) services.URLServiceInterface {
+	return h.urlService.ForRequest(c.Request.Context(), c.GetUint("organization_id"))
+}
+
+// urlStatuses are the statuses URL lists can be filtered by
+var urlStatuses = map[string]bool{
+	"pending":   true,
+	"running":   true,
+	"completed": true,
+	"skipped":   true,
+	"error":     true,
+}
+
+// normalizeURLStatuses validates a comma separated list of statuses
+func normalizeURLStatuses(status string) (string, error) {
+	if status == "" {
+		return "", nil
+	}
+
+	statuses := strings.Split(status, ",")
+	for i, s := range statuses {
+		statuses[i] = strings.TrimSpace(s)
+		if !urlStatuses[statuses[i]] {
+			return "", fmt.Errorf("unknown status %q; use pending, running, completed, skipped or error", statuses[i])
+		}
+	}
+	return strings.Join(statuses, ","), nil
+}
+
+// urlMetricParams maps query parameters on the latest crawl of a URL to
+// conditions of the filter language
+var urlMetricParams = []struct{ param, field, op string }{
+	{"broken_links_min", "broken_links", ">="},
+	{"has_login_form", "has_login_form", "="},
+	{"needs_attention", "needs_attention", "="},
+	{"html_version", "html_version", "="},
+	{"duration_min_ms", "duration_ms", ">="},
+	{"duration_max_ms", "duration_ms", "<="},
+	{"word_count_max", "word_count", "<="},
+	{"readability_min", "readability", ">="},
+}
+
+// urlListFilter combines the filter expression of a URL list request with
+// its latest-crawl metric parameters
+func urlListFilter(c *gin.Context) (services.URLFilter, error) {
+	var terms []string
+	if expr := strings.TrimSpace(c.Query("filter")); expr != "" {
+		terms = append(terms, expr)
+	}
+	for _, p := range urlMetricParams {
+		value, ok := c.GetQuery(p.param)
+		if !ok {
+			continue
+		}
+		// Separators would smuggle extra conditions into the expression
+		if strings.ContainsAny(value, ",|") {
+			return nil, fmt.Errorf("%w: %s takes a single value", services.ErrInvalidFilter, p.param)
+		}
+		terms = append(terms, p.field+p.op+value)
+	}
+	return services.ParseURLFilter(strings.Join(terms, ","))
 }
 
 // GetURLs handles GET /api/v1/urls
+// @Summary List URLs
+// @Description List URLs with filters and offset or cursor pagination
+// @Tags urls
+// @Produce json
+// @Security ApiKeyAuth
+// @Param limit query int false "Page size (max 100)"
+// @Param offset query int false "Rows to skip"
+// @Param cursor query string false "Cursor from next_cursor; pass it empty to start cursor pagination"
+// @Param search query string false "Search in URL and title"
+// @Param status query string false "Crawl status, or several separated by commas, e.g. error,running"
+// @Param filter query string false "Comma separated conditions, e.g. status:error,broken_links>10,created_after:2024-01-01"
+// @Param broken_links_min query int false "Minimum broken links of the latest crawl"
+// @Param has_login_form query bool false "Whether the latest crawl found a login form"
+// @Param needs_attention query bool false "Whether the latest completed crawl crossed the broken link threshold"
+// @Param html_version query string false "HTML version, e.g. HTML5"
+// @Param duration_min_ms query int false "Minimum duration of the latest crawl in milliseconds"
+// @Param duration_max_ms query int false "Maximum duration of the latest crawl in milliseconds"
+// @Param word_count_max query int false "Maximum words on the root page of the latest crawl, to find thin content"
+// @Param readability_min query int false "Minimum Flesch reading ease of the latest crawl"
+// @Param sortBy query string false "url, title, status, html_version, created_at or updated_at, or several separated by commas, each optionally followed by asc or desc, e.g. status asc,updated_at desc"
+// @Param sortOrder query string false "asc or desc, for sort columns without a direction"
+// @Param fields query string false "Comma separated fields to return, e.g. url,status,broken_links; id is always included"
+// @Success 200 {object} map[string]interface{}
+// @Success 304
+// @Failure 400 {object} map[string]interface{}
+// @Router /urls [get]
 func (h *URLHandler) GetURLs(c *gin.Context) {
 	// Parse query parameters
 	limitStr := c.DefaultQuery("limit", "20")
@@ -37,36 +136,80 @@ func (h *URLHandler) GetURLs(c *gin.Context) {
 		offset = 0
 	}
 
+	if sortOrder != "asc" && sortOrder != "desc" {
+		sortOrder = "desc"
+	}
+
 	// Validate sort parameters
-	validSortColumns := map[string]bool{
-		"url":          true,
-		"title":        true,
-		"status":       true,
-		"html_version": true,
-		"created_at":   true,
-		"updated_at":   true,
+	sortBy, err = services.NormalizeURLSort(sortBy, sortOrder)
+	if err != nil {
+		apperror.Abort(c, apperror.Wrap(http.StatusBadRequest, "Invalid sort", err))
+		return
+	}
+	status, err = normalizeURLStatuses(status)
+	if err != nil {
+		apperror.Abort(c, apperror.Wrap(http.StatusBadRequest, "Invalid status", err))
+		return
 	}
 
-	if !validSortColumns[sortBy] {
-		sortBy = "updated_at"
+	filter, err := urlListFilter(c)
+	if err != nil {
+		apperror.Abort(c, apperror.Wrap(http.StatusBadRequest, "Invalid filter", err))
+		return
 	}
+	fields, err := parseFields(c, models.URLListItem{})
+	if err != nil {
+		apperror.Abort(c, apperror.Wrap(http.StatusBadRequest, "Invalid fields", err))
+		return
+	}
+	service := h.service(c).Filtered(filter)
 
-	if sortOrder != "asc" && sortOrder != "desc" {
-		sortOrder = "desc"
+	// Answer polling clients from a cheap fingerprint before loading the list
+	if version, err := service.ListVersion(search, status); err == nil && notModified(c, weakETag(c, version)) {
+		return
+	}
+
+	// A cursor parameter, even an empty one, opts into keyset pagination
+	if cursor, ok := c.GetQuery("cursor"); ok {
+		urls, total, next, err := service.GetURLsAfter(limit, cursor, search, status, sortBy, sortOrder)
+		if err != nil {
+			if errors.Is(err, services.ErrInvalidCursor) {
+				apperror.Abort(c, apperror.Wrap(http.StatusBadRequest, "Invalid cursor", err))
+				return
+			}
+			if errors.Is(err, services.ErrInvalidFilter) {
+				apperror.Abort(c, apperror.Wrap(http.StatusBadRequest, "Invalid filter", err))
+				return
+			}
+
+			apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to fetch URLs", err))
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data": fields.serialize(urls),
+			"pagination": gin.H{
+				"total":       total,
+				"limit":       limit,
+				"next_cursor": next,
+			},
+		})
+		return
 	}
 
 	// Get URLs from service
-	urls, total, err := h.urlService.GetURLs(limit, offset, search, status, sortBy, sortOrder)
+	urls, total, err := service.GetURLs(limit, offset, search, status, sortBy, sortOrder)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to fetch URLs",
-			"message": err.Error(),
-		})
+		if errors.Is(err, services.ErrInvalidFilter) {
+			apperror.Abort(c, apperror.Wrap(http.StatusBadRequest, "Invalid filter", err))
+			return
+		}
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to fetch URLs", err))
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"data": urls,
+		"data": fields.serialize(urls),
 		"pagination": gin.H{
 			"total":  total,
 			"limit":  limit,
@@ -76,23 +219,51 @@ func (h *URLHandler) GetURLs(c *gin.Context) {
 }
 
 // CreateURL handles POST /api/v1/urls
+// @Summary Add a URL
+// @Description Add a URL and start crawling it
+// @Tags urls
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body models.CrawlRequest true "URL to crawl"
+// @Param Idempotency-Key header string false "Replays the response of an earlier request with the same key"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 429 {object} map[string]interface{}
+// @Router /urls [post]
 func (h *URLHandler) CreateURL(c *gin.Context) {
 	var req models.CrawlRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request body",
-			"message": err.Error(),
-		})
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	// Adding a URL crawls it, so it counts against both quotas
+	userID := c.GetUint("user_id")
+	err := h.quotaService.CheckURLs(userID)
+	if err == nil {
+		err = h.quotaService.ReserveCrawls(userID, 1)
+	}
+	if err != nil {
+		if respondQuotaExceeded(c, err) {
+			return
+		}
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to check quota", err))
 		return
 	}
 
 	// Create URL and start crawling
-	url, err := h.urlService.CreateURL(req.URL)
+	url, err := h.service(c).CreateURLForUser(req.URL, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to create URL",
-			"message": err.Error(),
-		})
+		if errors.Is(err, services.ErrInvalidURL) {
+			apperror.Abort(c, apperror.Wrap(http.StatusBadRequest, "Invalid URL", err))
+			return
+		}
+		if errors.Is(err, services.ErrURLNotAllowed) {
+			apperror.Abort(c, apperror.Wrap(http.StatusBadRequest, "URL not allowed", err))
+			return
+		}
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to create URL", err))
 		return
 	}
 
@@ -103,31 +274,463 @@ func (h *URLHandler) CreateURL(c *gin.Context) {
 }
 
 // GetURL handles GET /api/v1/urls/:id
+// @Summary Get a URL
+// @Description Get a URL with its crawls and broken links
+// @Tags urls
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "URL ID"
+// @Param fields query string false "Comma separated fields to return, e.g. url,status,crawls; id is always included"
+// @Success 200 {object} map[string]interface{}
+// @Success 304
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /urls/{id} [get]
 func (h *URLHandler) GetURL(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid URL ID",
-			"message": "ID must be a valid number",
-		})
+		apperror.Abort(c, apperror.New(http.StatusBadRequest, "Invalid URL ID", "ID must be a valid number"))
+		return
+	}
+	fields, err := parseFields(c, models.URL{})
+	if err != nil {
+		apperror.Abort(c, apperror.Wrap(http.StatusBadRequest, "Invalid fields", err))
+		return
+	}
+
+	if version, err := h.service(c).URLVersion(uint(id)); err == nil && notModified(c, weakETag(c, version)) {
+		return
+	}
+
+	url, err := h.service(c).GetURL(uint(id))
+	if err != nil {
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to fetch URL", err))
 		return
 	}
 
-	url, err := h.urlService.GetURL(uint(id))
+	c.JSON(http.StatusOK, gin.H{
+		"data": fields.serialize(url),
+	})
+}
+
+// DeleteURL handles DELETE /api/v1/urls/:id
+// @Summary Delete a URL
+// @Tags urls
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "URL ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /urls/{id} [delete]
+func (h *URLHandler) DeleteURL(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		apperror.Abort(c, apperror.New(http.StatusBadRequest, "Invalid URL ID", "ID must be a valid number"))
+		return
+	}
+
+	if err := h.service(c).DeleteURL(uint(id)); err != nil {
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to delete URL", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "URL deleted successfully",
+	})
+}
+
+// GetURLImages handles GET /api/v1/urls/:id/images
+func (h *URLHandler) GetURLImages(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		if err.Error() == "URL not found" {
-			c.JSON(http.StatusNotFound, gin.H{
-				"error":   "URL not found",
-				"message": "The requested URL does not exist",
-			})
+		apperror.Abort(c, apperror.New(http.StatusBadRequest, "Invalid URL ID", "ID must be a valid number"))
+		return
+	}
+
+	// Parse query parameters
+	filter := c.Query("filter") // all, missing_alt, decorative, broken, missing_dimensions
+	limitStr := c.DefaultQuery("limit", "50")
+	offsetStr := c.DefaultQuery("offset", "0")
+
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || limit <= 0 || limit > 200 {
+		limit = 50
+	}
+
+	offset, err := strconv.Atoi(offsetStr)
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	images, total, err := h.service(c).GetURLImages(uint(id), filter, limit, offset)
+	if err != nil {
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to fetch images", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": images,
+		"pagination": gin.H{
+			"total":  total,
+			"limit":  limit,
+			"offset": offset,
+		},
+	})
+}
+
+// GetURLForms handles GET /api/v1/urls/:id/forms
+func (h *URLHandler) GetURLForms(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		apperror.Abort(c, apperror.New(http.StatusBadRequest, "Invalid URL ID", "ID must be a valid number"))
+		return
+	}
+
+	// Parse query parameters
+	formType := c.Query("type") // all, login, search, newsletter, contact, checkout, other
+	limitStr := c.DefaultQuery("limit", "50")
+	offsetStr := c.DefaultQuery("offset", "0")
+
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || limit <= 0 || limit > 200 {
+		limit = 50
+	}
+
+	offset, err := strconv.Atoi(offsetStr)
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	forms, total, err := h.service(c).GetURLForms(uint(id), formType, limit, offset)
+	if err != nil {
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to fetch forms", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": forms,
+		"pagination": gin.H{
+			"total":  total,
+			"limit":  limit,
+			"offset": offset,
+		},
+	})
+}
+
+// GetSEOReport handles GET /api/v1/urls/:id/seo
+func (h *URLHandler) GetSEOReport(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		apperror.Abort(c, apperror.New(http.StatusBadRequest, "Invalid URL ID", "ID must be a valid number"))
+		return
+	}
+
+	report, err := h.service(c).GetSEOReport(uint(id))
+	if err != nil {
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to fetch SEO report", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": report,
+	})
+}
+
+// GetSecurityReport handles GET /api/v1/urls/:id/security
+func (h *URLHandler) GetSecurityReport(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		apperror.Abort(c, apperror.New(http.StatusBadRequest, "Invalid URL ID", "ID must be a valid number"))
+		return
+	}
+
+	report, err := h.service(c).GetSecurityReport(uint(id))
+	if err != nil {
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to fetch security report", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": report,
+	})
+}
+
+// GetAccessibilityReport handles GET /api/v1/urls/:id/accessibility
+func (h *URLHandler) GetAccessibilityReport(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		apperror.Abort(c, apperror.New(http.StatusBadRequest, "Invalid URL ID", "ID must be a valid number"))
+		return
+	}
+
+	// Optional crawl_id selects an older crawl, rule filters the issue list
+	var crawlID uint64
+	if crawlIDStr := c.Query("crawl_id"); crawlIDStr != "" {
+		crawlID, err = strconv.ParseUint(crawlIDStr, 10, 32)
+		if err != nil {
+			apperror.Abort(c, apperror.New(http.StatusBadRequest, "Invalid crawl ID", "crawl_id must be a valid number"))
 			return
 		}
+	}
 
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to fetch URL",
-			"message": err.Error(),
-		})
+	report, err := h.service(c).GetAccessibilityReport(uint(id), uint(crawlID), c.Query("rule"))
+	if err != nil {
+		if errors.Is(err, services.ErrCrawlNotFound) {
+			apperror.Abort(c, apperror.New(http.StatusNotFound, "Crawl not found", "The requested crawl does not exist for this URL"))
+			return
+		}
+
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to fetch accessibility issues", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": report,
+	})
+}
+
+// GetMixedContentReport handles GET /api/v1/urls/:id/mixed-content
+func (h *URLHandler) GetMixedContentReport(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		apperror.Abort(c, apperror.New(http.StatusBadRequest, "Invalid URL ID", "ID must be a valid number"))
+		return
+	}
+
+	// Optional crawl_id selects an older crawl, type filters the issue list
+	var crawlID uint64
+	if crawlIDStr := c.Query("crawl_id"); crawlIDStr != "" {
+		crawlID, err = strconv.ParseUint(crawlIDStr, 10, 32)
+		if err != nil {
+			apperror.Abort(c, apperror.New(http.StatusBadRequest, "Invalid crawl ID", "crawl_id must be a valid number"))
+			return
+		}
+	}
+
+	report, err := h.service(c).GetMixedContentReport(uint(id), uint(crawlID), c.Query("type"))
+	if err != nil {
+		if errors.Is(err, services.ErrCrawlNotFound) {
+			apperror.Abort(c, apperror.New(http.StatusNotFound, "Crawl not found", "The requested crawl does not exist for this URL"))
+			return
+		}
+
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to fetch mixed content issues", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": report,
+	})
+}
+
+// GetContentChanges handles GET /api/v1/urls/:id/changes
+func (h *URLHandler) GetContentChanges(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		apperror.Abort(c, apperror.New(http.StatusBadRequest, "Invalid URL ID", "ID must be a valid number"))
+		return
+	}
+
+	// Number of consecutive crawl pairs to compare
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit <= 0 || limit > 50 {
+		limit = 10
+	}
+
+	changes, err := h.service(c).GetContentChanges(uint(id), limit)
+	if err != nil {
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to fetch content changes", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": changes,
+	})
+}
+
+// GetCrawlTrends handles GET /api/v1/urls/:id/trends
+// @Summary Show quality trends of a URL
+// @Description Returns the broken, internal and external link counts, pages and duration of the latest completed crawls, oldest first, for plotting over time
+// @Tags urls
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "URL ID"
+// @Param limit query int false "Number of crawls, at most 200" default(30)
+// @Success 200 {object} models.CrawlTrends
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /urls/{id}/trends [get]
+func (h *URLHandler) GetCrawlTrends(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apperror.Abort(c, apperror.New(http.StatusBadRequest, "Invalid URL ID", "ID must be a valid number"))
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "30"))
+	if err != nil || limit <= 0 || limit > 200 {
+		limit = 30
+	}
+
+	trends, err := h.service(c).GetCrawlTrends(uint(id), limit)
+	if err != nil {
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to fetch crawl trends", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": trends,
+	})
+}
+
+// GetCrawlDiff handles GET /api/v1/urls/:id/crawls/:crawl_a/diff/:crawl_b
+func (h *URLHandler) GetCrawlDiff(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		apperror.Abort(c, apperror.New(http.StatusBadRequest, "Invalid URL ID", "ID must be a valid number"))
+		return
+	}
+
+	crawlA, errA := strconv.ParseUint(c.Param("crawl_a"), 10, 32)
+	crawlB, errB := strconv.ParseUint(c.Param("crawl_b"), 10, 32)
+	if errA != nil || errB != nil {
+		apperror.Abort(c, apperror.New(http.StatusBadRequest, "Invalid crawl ID", "Crawl IDs must be valid numbers"))
+		return
+	}
+
+	diff, err := h.service(c).GetCrawlDiff(uint(id), uint(crawlA), uint(crawlB))
+	if err != nil {
+		if errors.Is(err, services.ErrCrawlNotFound) {
+			apperror.Abort(c, apperror.New(http.StatusNotFound, "Crawl not found", "The requested crawl does not exist for this URL"))
+			return
+		}
+
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to compare crawls", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": diff,
+	})
+}
+
+// GetLinkGraph handles GET /api/v1/urls/:id/graph
+func (h *URLHandler) GetLinkGraph(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		apperror.Abort(c, apperror.New(http.StatusBadRequest, "Invalid URL ID", "ID must be a valid number"))
+		return
+	}
+
+	format := c.DefaultQuery("format", "json") // json, dot
+	if format != "json" && format != "dot" {
+		apperror.Abort(c, apperror.New(http.StatusBadRequest, "Invalid format", "format must be json or dot"))
+		return
+	}
+
+	graph, err := h.service(c).GetLinkGraph(uint(id))
+	if err != nil {
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to build link graph", err))
+		return
+	}
+
+	if format == "dot" {
+		c.Data(http.StatusOK, "text/vnd.graphviz; charset=utf-8", []byte(services.RenderLinkGraphDOT(graph)))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": graph,
+	})
+}
+
+// GetDuplicates handles GET /api/v1/urls/:id/duplicates
+func (h *URLHandler) GetDuplicates(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		apperror.Abort(c, apperror.New(http.StatusBadRequest, "Invalid URL ID", "ID must be a valid number"))
+		return
+	}
+
+	report, err := h.service(c).GetDuplicates(uint(id))
+	if err != nil {
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to find duplicates", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": report,
+	})
+}
+
+// GetStructureReport handles GET /api/v1/urls/:id/structure
+func (h *URLHandler) GetStructureReport(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		apperror.Abort(c, apperror.New(http.StatusBadRequest, "Invalid URL ID", "ID must be a valid number"))
+		return
+	}
+
+	report, err := h.service(c).GetStructureReport(uint(id))
+	if err != nil {
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to build structure report", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": report,
+	})
+}
+
+// UpdateCrawlSettings handles PUT /api/v1/urls/:id/crawl-settings
+func (h *URLHandler) UpdateCrawlSettings(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		apperror.Abort(c, apperror.New(http.StatusBadRequest, "Invalid URL ID", "ID must be a valid number"))
+		return
+	}
+
+	var req models.CrawlSettingsRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	if req.MaxDepth != nil && *req.MaxDepth > 0 && !h.featureFlags.Enabled(models.FeatureDeepCrawl, c.GetUint("user_id")) {
+		apperror.Abort(c, apperror.New(http.StatusForbidden, "Feature not available", "Deep crawls are not available for your account"))
+		return
+	}
+
+	if req.MaxPages != nil {
+		if err := h.quotaService.CheckPages(c.GetUint("user_id"), *req.MaxPages); err != nil {
+			if respondQuotaExceeded(c, err) {
+				return
+			}
+			apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to check quota", err))
+			return
+		}
+	}
+
+	url, err := h.service(c).UpdateCrawlSettings(uint(id), req)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidCrawlSettings) {
+			apperror.Abort(c, apperror.Wrap(http.StatusBadRequest, "Invalid crawl settings", err))
+			return
+		}
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to update crawl settings", err))
 		return
 	}
 
@@ -136,55 +739,55 @@ func (h *URLHandler) GetURL(c *gin.Context) {
 	})
 }
 
-// DeleteURL handles DELETE /api/v1/urls/:id
-func (h *URLHandler) DeleteURL(c *gin.Context) {
+// SetLoginFormOverride handles PUT /api/v1/urls/:id/login-form
+func (h *URLHandler) SetLoginFormOverride(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid URL ID",
-			"message": "ID must be a valid number",
-		})
+		apperror.Abort(c, apperror.New(http.StatusBadRequest, "Invalid URL ID", "ID must be a valid number"))
 		return
 	}
 
-	if err := h.urlService.DeleteURL(uint(id)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to delete URL",
-			"message": err.Error(),
-		})
+	var req models.LoginFormOverrideRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	url, err := h.service(c).SetLoginFormOverride(uint(id), req.HasLoginForm)
+	if err != nil {
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to update login form classification", err))
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "URL deleted successfully",
+		"data": url,
 	})
 }
 
 // BulkDeleteURLs handles POST /api/v1/urls/bulk-delete
+// @Summary Delete several URLs
+// @Tags urls
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body models.BulkRequest true "URL IDs"
+// @Param Idempotency-Key header string false "Replays the response of an earlier request with the same key"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /urls/bulk-delete [post]
 func (h *URLHandler) BulkDeleteURLs(c *gin.Context) {
 	var req models.BulkRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request body",
-			"message": err.Error(),
-		})
+	if !bindJSON(c, &req) {
 		return
 	}
 
 	if len(req.IDs) == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "No IDs provided",
-			"message": "At least one URL ID must be provided",
-		})
+		apperror.Abort(c, apperror.New(http.StatusBadRequest, "No IDs provided", "At least one URL ID must be provided"))
 		return
 	}
 
-	if err := h.urlService.BulkDeleteURLs(req.IDs); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to delete URLs",
-			"message": err.Error(),
-		})
+	if err := h.service(c).BulkDeleteURLs(req.IDs); err != nil {
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to delete URLs", err))
 		return
 	}
 
@@ -194,19 +797,30 @@ func (h *URLHandler) BulkDeleteURLs(c *gin.Context) {
 }
 
 // GetURLLinks handles GET /api/v1/urls/:id/links
+// @Summary List the links of a URL
+// @Tags urls
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "URL ID"
+// @Param type query string false "all, internal, external, mailto, tel, anchor, other, broken, accessible, nofollow, sponsored, ugc, new_tab, nav, footer or content"
+// @Param limit query int false "Page size (max 200)"
+// @Param offset query int false "Rows to skip"
+// @Param cursor query string false "Cursor from next_cursor; pass it empty to start cursor pagination"
+// @Param fields query string false "Comma separated fields to return, e.g. link_url,status_code; id is always included"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /urls/{id}/links [get]
 func (h *URLHandler) GetURLLinks(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid URL ID",
-			"message": "ID must be a valid number",
-		})
+		apperror.Abort(c, apperror.New(http.StatusBadRequest, "Invalid URL ID", "ID must be a valid number"))
 		return
 	}
 
 	// Parse query parameters
-	linkType := c.Query("type")     // all, internal, external, broken
+	linkType := c.Query("type")     // all, internal, external, mailto, tel, anchor, other, broken, accessible, nofollow, sponsored, ugc, new_tab, nav, footer, content
 	limitStr := c.DefaultQuery("limit", "50")
 	offsetStr := c.DefaultQuery("offset", "0")
 
@@ -220,25 +834,43 @@ func (h *URLHandler) GetURLLinks(c *gin.Context) {
 		offset = 0
 	}
 
-	links, total, err := h.urlService.GetURLLinks(uint(id), linkType, limit, offset)
+	fields, err := parseFields(c, models.Link{})
 	if err != nil {
-		if err.Error() == "URL not found" {
-			c.JSON(http.StatusNotFound, gin.H{
-				"error":   "URL not found",
-				"message": "The requested URL does not exist",
-			})
+		apperror.Abort(c, apperror.Wrap(http.StatusBadRequest, "Invalid fields", err))
+		return
+	}
+
+	// A cursor parameter, even an empty one, opts into keyset pagination
+	if cursor, ok := c.GetQuery("cursor"); ok {
+		links, total, next, err := h.service(c).GetURLLinksAfter(uint(id), linkType, limit, cursor)
+		if err != nil {
+			if errors.Is(err, services.ErrInvalidCursor) {
+				apperror.Abort(c, apperror.Wrap(http.StatusBadRequest, "Invalid cursor", err))
+				return
+			}
+			apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to fetch links", err))
 			return
 		}
 
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to fetch links",
-			"message": err.Error(),
+		c.JSON(http.StatusOK, gin.H{
+			"data": fields.serialize(links),
+			"pagination": gin.H{
+				"total":       total,
+				"limit":       limit,
+				"next_cursor": next,
+			},
 		})
 		return
 	}
 
+	links, total, err := h.service(c).GetURLLinks(uint(id), linkType, limit, offset)
+	if err != nil {
+		apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to fetch links", err))
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"data": links,
+		"data": fields.serialize(links),
 		"pagination": gin.H{
 			"total":  total,
 			"limit":  limit,
@@ -6,8 +6,10 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -50,12 +52,12 @@ func setupURLHandlerTest() (*gin.Engine, *URLHandler, *gorm.DB) {
 	db, _ := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Silent),
 	})
-	db.AutoMigrate(&models.URL{}, &models.Crawl{}, &models.Link{})
+	db.AutoMigrate(&models.URL{}, &models.Crawl{}, &models.Link{}, &models.PageMeta{}, &models.Page{}, &models.PageLink{}, &models.Image{}, &models.Form{}, &models.CrawlEvent{}, &models.AccessibilityIssue{}, &models.MixedContentIssue{}, &models.ActivityEvent{}, &models.FindingAnnotation{}, &models.ExternalIssue{})
 	
 	// Setup services
 	crawlerService := &mockCrawlerServiceHandler{}
 	urlService := services.NewURLService(db, crawlerService)
-	handler := NewURLHandler(urlService)
+	handler := NewURLHandler(urlService, nil, nil)
 	
 	// Create test router
 	router := gin.New()
@@ -97,6 +99,65 @@ func TestURLHandler_GetURLs(t *testing.T) {
 		assert.Equal(t, float64(0), pagination["offset"])
 	})
 	
+	t.Run("with filter expression", func(t *testing.T) {
+		router, handler, db := setupURLHandlerTest()
+		
+		require.NoError(t, db.Create(&models.URL{URL: "https://example1.com", Status: "error"}).Error)
+		require.NoError(t, db.Create(&models.URL{URL: "https://example2.com", Status: "completed"}).Error)
+		router.GET("/urls", handler.GetURLs)
+		
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/urls?filter=status:error,created_after:2020-01-01", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		data := response["data"].([]interface{})
+		require.Len(t, data, 1)
+		assert.Equal(t, "https://example1.com", data[0].(map[string]interface{})["url"])
+		
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/urls?filter=colour:red", nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `unknown field \"colour\"`)
+	})
+	
+	t.Run("with latest crawl metrics", func(t *testing.T) {
+		router, handler, db := setupURLHandlerTest()
+		
+		start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+		for i, seconds := range []int{2, 30, 5} {
+			u := &models.URL{URL: fmt.Sprintf("https://example%d.com", i), Status: "completed", HTMLVersion: "HTML5"}
+			require.NoError(t, db.Create(u).Error)
+			completed := start.Add(time.Duration(seconds) * time.Second)
+			require.NoError(t, db.Create(&models.Crawl{URLID: u.ID, Status: "completed", BrokenLinks: i * 5, StartedAt: &start, CompletedAt: &completed}).Error)
+		}
+		require.NoError(t, db.Create(&models.URL{URL: "https://legacy.com", Status: "completed", HTMLVersion: "HTML 4.01"}).Error)
+		router.GET("/urls", handler.GetURLs)
+		
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/urls?sortBy=url&sortOrder=asc&broken_links_min=5&duration_max_ms=10000&html_version=HTML5", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		data := response["data"].([]interface{})
+		require.Len(t, data, 1)
+		assert.Equal(t, "https://example2.com", data[0].(map[string]interface{})["url"])
+		
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/urls?duration_min_ms=3000&filter=broken_links<10", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		data = response["data"].([]interface{})
+		require.Len(t, data, 1)
+		assert.Equal(t, "https://example1.com", data[0].(map[string]interface{})["url"])
+		
+		for _, query := range []string{"broken_links_min=many", "has_login_form=maybe", "duration_max_ms=-1", "html_version=HTML5,status:error"} {
+			w = httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", "/urls?"+query, nil))
+			assert.Equal(t, http.StatusBadRequest, w.Code, query)
+		}
+	})
+	
 	t.Run("with pagination parameters", func(t *testing.T) {
 		router, handler, db := setupURLHandlerTest()
 		
@@ -246,6 +307,32 @@ func TestURLHandler_GetURLs(t *testing.T) {
 		pagination := response["pagination"].(map[string]interface{})
 		assert.Equal(t, float64(20), pagination["limit"]) // Should fallback to default
 	})
+	
+	t.Run("multiple sort columns and statuses", func(t *testing.T) {
+		router, handler, db := setupURLHandlerTest()
+		
+		for i, status := range []string{"error", "running", "completed", "error"} {
+			require.NoError(t, db.Create(&models.URL{URL: fmt.Sprintf("https://example%d.com", i), Status: status}).Error)
+		}
+		router.GET("/urls", handler.GetURLs)
+		
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/urls?status=error,running&sortBy="+url.QueryEscape("status desc,url:asc"), nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		var listed []interface{}
+		for _, item := range response["data"].([]interface{}) {
+			listed = append(listed, item.(map[string]interface{})["url"])
+		}
+		assert.Equal(t, []interface{}{"https://example1.com", "https://example0.com", "https://example3.com"}, listed)
+		
+		for _, query := range []string{"sortBy=score", "sortBy=url,url", "sortBy=url+sideways", "status=error,broken"} {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", "/urls?"+query, nil))
+			assert.Equal(t, http.StatusBadRequest, w.Code, query)
+		}
+	})
 }
 
 func TestURLHandler_CreateURL(t *testing.T) {
@@ -293,6 +380,26 @@ func TestURLHandler_CreateURL(t *testing.T) {
 		assert.Equal(t, "Invalid request body", response["error"])
 	})
 	
+	t.Run("unsupported scheme", func(t *testing.T) {
+		router, handler, _ := setupURLHandlerTest()
+
+		router.POST("/urls", handler.CreateURL)
+
+		requestBody := `{"url": "ftp://example.com"}`
+		req := httptest.NewRequest("POST", "/urls", bytes.NewBufferString(requestBody))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response map[string]interface{}
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+
+		assert.Equal(t, "Invalid URL", response["error"])
+	})
+
 	t.Run("missing URL field", func(t *testing.T) {
 		router, handler, _ := setupURLHandlerTest()
 		
@@ -380,6 +487,7 @@ func TestURLHandler_GetURL(t *testing.T) {
 		require.NoError(t, err)
 		
 		assert.Equal(t, "URL not found", response["error"])
+		assert.Equal(t, "url_not_found", response["code"])
 		assert.Equal(t, "The requested URL does not exist", response["message"])
 	})
 }
@@ -620,4 +728,107 @@ func TestURLHandler_GetURLLinks(t *testing.T) {
 		
 		assert.Equal(t, "URL not found", response["error"])
 	})
-} 
\ No newline at end of file
+} 
+
+func TestURLHandler_ETags(t *testing.T) {
+	router, handler, db := setupURLHandlerTest()
+	router.GET("/urls", handler.GetURLs)
+	router.GET("/urls/:id", handler.GetURL)
+
+	url := &models.URL{URL: "https://example.com", Title: "Example", Status: "completed"}
+	require.NoError(t, db.Create(url).Error)
+
+	get := func(path, etag string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	for _, path := range []string{"/urls", fmt.Sprintf("/urls/%d", url.ID)} {
+		t.Run(path, func(t *testing.T) {
+			w := get(path, "")
+			require.Equal(t, http.StatusOK, w.Code)
+			etag := w.Header().Get("ETag")
+			assert.Regexp(t, `^W/"[0-9a-f]{32}"$`, etag)
+
+			w = get(path, etag)
+			assert.Equal(t, http.StatusNotModified, w.Code)
+			assert.Empty(t, w.Body.String())
+
+			// Strong and listed tags match too
+			assert.Equal(t, http.StatusNotModified, get(path, `"other", `+etag[2:]).Code)
+
+			// New links change the version
+			require.NoError(t, db.Create(&models.Link{URLID: url.ID, LinkURL: "https://example.com/" + path, LinkType: "internal"}).Error)
+			w = get(path, etag)
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.NotEqual(t, etag, w.Header().Get("ETag"))
+		})
+	}
+
+	t.Run("filters get their own tags", func(t *testing.T) {
+		all := get("/urls", "").Header().Get("ETag")
+		pending := get("/urls?status=pending", "").Header().Get("ETag")
+		assert.NotEqual(t, all, pending)
+		assert.Equal(t, http.StatusOK, get("/urls?status=pending", all).Code)
+	})
+
+	t.Run("annotations change the URL version", func(t *testing.T) {
+		path := fmt.Sprintf("/urls/%d", url.ID)
+		etag := get(path, "").Header().Get("ETag")
+		require.NoError(t, db.Create(&models.FindingAnnotation{URLID: url.ID, FindingType: models.FindingBrokenLink, FindingKey: "https://example.com/gone", Status: models.AnnotationIgnored}).Error)
+		assert.Equal(t, http.StatusOK, get(path, etag).Code)
+	})
+}
+
+func TestURLHandler_Fields(t *testing.T) {
+	router, handler, db := setupURLHandlerTest()
+	router.GET("/urls", handler.GetURLs)
+	router.GET("/urls/:id", handler.GetURL)
+	router.GET("/urls/:id/links", handler.GetURLLinks)
+	
+	u := &models.URL{URL: "https://example.com", Title: "Example", Status: "completed"}
+	require.NoError(t, db.Create(u).Error)
+	require.NoError(t, db.Create(&models.Link{URLID: u.ID, LinkURL: "https://other.com", LinkType: "external", StatusCode: 404}).Error)
+	
+	for path, want := range map[string][]string{
+		"/urls?fields=url,broken_links":             {"id", "url", "broken_links"},
+		"/urls/1?fields=status":                     {"id", "status"},
+		"/urls/1/links?fields=link_url,status_code": {"id", "link_url", "status_code"},
+		"/urls/1/links?fields=id&cursor=":           {"id"},
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		require.Equal(t, http.StatusOK, w.Code, path)
+		
+		var response struct {
+			Data json.RawMessage `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		var item map[string]interface{}
+		var items []map[string]interface{}
+		if json.Unmarshal(response.Data, &items) == nil {
+			require.Len(t, items, 1, path)
+			item = items[0]
+		} else {
+			require.NoError(t, json.Unmarshal(response.Data, &item))
+		}
+		keys := make([]string, 0, len(item))
+		for key := range item {
+			keys = append(keys, key)
+		}
+		assert.ElementsMatch(t, want, keys, path)
+	}
+	
+	// Unknown fields, including those of other resources, are rejected
+	for _, path := range []string{"/urls?fields=password", "/urls/1?fields=link_url", "/urls/1/links?fields=title"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code, path)
+		assert.Contains(t, w.Body.String(), "Invalid fields", path)
+	}
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+
+	"web-crawler-backend/internal/apperror"
+)
+
+// bindJSON binds the JSON body of the request into obj and reports whether it
+// was valid. Invalid bodies are answered with a validation error listing
+// every invalid field by its JSON name, the rule it broke and a message.
+func bindJSON(c *gin.Context, obj interface{}) bool {
+	if err := c.ShouldBindJSON(obj); err != nil {
+		apperror.Abort(c, bindError(obj, err))
+		return false
+	}
+	return true
+}
+
+// bindError translates a binding error into the error response. Bodies that
+// aren't JSON objects keep the decoder's message.
+func bindError(obj interface{}, err error) *apperror.Error {
+	const title = "Invalid request body"
+
+	var invalid validator.ValidationErrors
+	if errors.As(err, &invalid) {
+		fields := make([]apperror.FieldError, 0, len(invalid))
+		for _, fieldErr := range invalid {
+			field := jsonFieldPath(reflect.TypeOf(obj), fieldErr.StructNamespace())
+			fields = append(fields, apperror.FieldError{
+				Field:   field,
+				Rule:    fieldErr.Tag(),
+				Message: ruleMessage(field, fieldErr),
+			})
+		}
+		return apperror.Validation(title, fields)
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return apperror.Validation(title, []apperror.FieldError{{
+			Field:   typeErr.Field,
+			Rule:    "type",
+			Message: fmt.Sprintf("%s must be of type %s", typeErr.Field, jsonTypeName(typeErr.Type)),
+		}})
+	}
+
+	return apperror.Wrap(http.StatusBadRequest, title, err)
+}
+
+// ruleMessage describes a broken validation rule
+func ruleMessage(field string, fieldErr validator.FieldError) string {
+	param := fieldErr.Param()
+	switch fieldErr.Tag() {
+	case "required":
+		return field + " is required"
+	case "email":
+		return field + " must be a valid email address"
+	case "url", "http_url":
+		return field + " must be a valid URL"
+	case "oneof":
+		return fmt.Sprintf("%s must be one of: %s", field, strings.Join(strings.Fields(param), ", "))
+	case "min", "max":
+		bound := "at least"
+		if fieldErr.Tag() == "max" {
+			bound = "at most"
+		}
+		switch fieldErr.Kind() {
+		case reflect.String:
+			return fmt.Sprintf("%s must be %s %s characters long", field, bound, param)
+		case reflect.Slice, reflect.Array, reflect.Map:
+			return fmt.Sprintf("%s must have %s %s items", field, bound, param)
+		}
+		return fmt.Sprintf("%s must be %s %s", field, bound, param)
+	}
+	if param != "" {
+		return fmt.Sprintf("%s must satisfy %s=%s", field, fieldErr.Tag(), param)
+	}
+	return fmt.Sprintf("%s must satisfy %s", field, fieldErr.Tag())
+}
+
+// jsonFieldPath turns the struct namespace of a field, e.g.
+// "BulkRequest.IDs[0]", into its path in the JSON body, e.g. "ids[0]"
+func jsonFieldPath(t reflect.Type, namespace string) string {
+	segments := strings.Split(namespace, ".")[1:]
+	path := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		name, index, _ := strings.Cut(segment, "[")
+		if index != "" {
+			index = "[" + index
+		}
+
+		for t != nil && (t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+			t = t.Elem()
+		}
+		jsonName := name
+		if t != nil && t.Kind() == reflect.Struct {
+			if field, ok := t.FieldByName(name); ok {
+				if tag, _, _ := strings.Cut(field.Tag.Get("json"), ","); tag != "" && tag != "-" {
+					jsonName = tag
+				}
+				t = field.Type
+			} else {
+				t = nil
+			}
+		}
+		path = append(path, jsonName+index)
+	}
+	return strings.Join(path, ".")
+}
+
+// jsonTypeName names a Go type the way JSON clients know it
+func jsonTypeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.String:
+		return "string"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	}
+	return "object"
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"web-crawler-backend/internal/apperror"
+	"web-crawler-backend/internal/models"
+)
+
+func TestBindJSON(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/register", func(c *gin.Context) {
+		var req models.RegisterRequest
+		if bindJSON(c, &req) {
+			c.Status(http.StatusNoContent)
+		}
+	})
+	router.POST("/bulk-rerun", func(c *gin.Context) {
+		var req models.BulkRerunRequest
+		if bindJSON(c, &req) {
+			c.Status(http.StatusNoContent)
+		}
+	})
+	post := func(path, body string) (int, apperror.Response) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("POST", path, bytes.NewBufferString(body)))
+		var response apperror.Response
+		if w.Code != http.StatusNoContent {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		}
+		return w.Code, response
+	}
+	fields := func(response apperror.Response) []apperror.FieldError {
+		var fields []apperror.FieldError
+		encoded, err := json.Marshal(response.Details["fields"])
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(encoded, &fields))
+		return fields
+	}
+
+	t.Run("every invalid field is listed by its JSON name", func(t *testing.T) {
+		status, response := post("/register", `{"username":"al","email":"not-an-email","password":"secret1","first_name":"Al"}`)
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, "Invalid request body", response.Error)
+		assert.Equal(t, apperror.CodeValidation, response.Code)
+		assert.Equal(t, []apperror.FieldError{
+			{Field: "username", Rule: "min", Message: "username must be at least 3 characters long"},
+			{Field: "email", Rule: "email", Message: "email must be a valid email address"},
+			{Field: "last_name", Rule: "required", Message: "last_name is required"},
+		}, fields(response))
+		assert.Equal(t, "username must be at least 3 characters long; email must be a valid email address; last_name is required", response.Message)
+	})
+
+	t.Run("rules with options list them", func(t *testing.T) {
+		status, response := post("/bulk-rerun", `{"ids":[1],"priority":"urgent"}`)
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, []apperror.FieldError{
+			{Field: "priority", Rule: "oneof", Message: "priority must be one of: high, low"},
+		}, fields(response))
+	})
+
+	t.Run("values of the wrong type", func(t *testing.T) {
+		status, response := post("/bulk-rerun", `{"ids":"1"}`)
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, []apperror.FieldError{
+			{Field: "ids", Rule: "type", Message: "ids must be of type array"},
+		}, fields(response))
+	})
+
+	t.Run("malformed JSON keeps the decoder message", func(t *testing.T) {
+		status, response := post("/bulk-rerun", `{"ids":`)
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, apperror.CodeInvalidRequest, response.Code)
+		assert.Equal(t, "unexpected EOF", response.Message)
+		assert.Nil(t, response.Details)
+	})
+
+	t.Run("valid bodies are bound", func(t *testing.T) {
+		status, _ := post("/bulk-rerun", `{"ids":[1,2],"priority":"high"}`)
+		assert.Equal(t, http.StatusNoContent, status)
+	})
+}
@@ -0,0 +1,88 @@
+package issuetracker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// GitHubOptions configures filing issues in a GitHub repository
+type GitHubOptions struct {
+	// APIURL is the REST API of GitHub or GitHub Enterprise; defaults to
+	// https://api.github.com
+	APIURL string
+	// Token is a personal access or app token allowed to create issues
+	Token string
+	// Repository is owner/name
+	Repository string
+	// Labels are added to every issue, next to the ones of the issue
+	Labels []string
+	// Client sends the requests; defaults to a client with a 30 second timeout
+	Client *http.Client
+}
+
+// GitHubTracker files issues through the GitHub REST API
+type GitHubTracker struct {
+	opts GitHubOptions
+}
+
+// NewGitHub validates the options and returns the repository's tracker
+func NewGitHub(opts GitHubOptions) (*GitHubTracker, error) {
+	if opts.Token == "" {
+		return nil, errors.New("GitHub issues need a token")
+	}
+	if owner, name, ok := strings.Cut(opts.Repository, "/"); !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+		return nil, fmt.Errorf("GitHub repository %q is not owner/name", opts.Repository)
+	}
+	if opts.APIURL == "" {
+		opts.APIURL = "https://api.github.com"
+	}
+	opts.APIURL = strings.TrimRight(opts.APIURL, "/")
+	if opts.Client == nil {
+		opts.Client = defaultClient
+	}
+	return &GitHubTracker{opts: opts}, nil
+}
+
+// Create opens an issue in the repository
+func (g *GitHubTracker) Create(ctx context.Context, issue Issue) (*Created, error) {
+	payload, err := json.Marshal(map[string]interface{}{
+		"title":  issue.Title,
+		"body":   issue.Body,
+		"labels": append(append([]string{}, g.opts.Labels...), issue.Labels...),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.opts.APIURL+"/repos/"+g.opts.Repository+"/issues", bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+g.opts.Token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+
+	resp, err := g.opts.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach GitHub: %w", err)
+	}
+	defer resp.Body.Close()
+	if err := checkResponse(resp); err != nil {
+		return nil, err
+	}
+
+	var created struct {
+		Number  int    `json:"number"`
+		HTMLURL string `json:"html_url"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		return nil, fmt.Errorf("invalid GitHub response: %w", err)
+	}
+	return &Created{Key: fmt.Sprintf("%s#%d", g.opts.Repository, created.Number), URL: created.HTMLURL}, nil
+}
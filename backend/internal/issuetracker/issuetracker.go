@@ -0,0 +1,83 @@
+// Package issuetracker files issues about crawl findings in external issue
+// trackers: GitHub repositories and Jira projects.
+package issuetracker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Trackers
+const (
+	GitHub = "github"
+	Jira   = "jira"
+)
+
+// ErrRejected is returned when a tracker answers a request with an error
+var ErrRejected = errors.New("issue tracker rejected the request")
+
+// Issue is the content of an issue to file
+type Issue struct {
+	Title string
+	// Body is plain text; both trackers render line breaks
+	Body   string
+	Labels []string
+}
+
+// Created identifies an issue filed in a tracker
+type Created struct {
+	// Key is the tracker's reference, e.g. owner/repo#12 or WEB-12
+	Key string
+	// URL is the page of the issue for people
+	URL string
+}
+
+// Tracker files issues in one issue tracker
+type Tracker interface {
+	Create(ctx context.Context, issue Issue) (*Created, error)
+}
+
+// Config configures the trackers issues can be filed in
+type Config struct {
+	GitHub GitHubOptions
+	Jira   JiraOptions
+}
+
+// Open returns the configured trackers by name. A tracker is configured when
+// its token is set; without any the map is empty.
+func Open(cfg Config) (map[string]Tracker, error) {
+	trackers := map[string]Tracker{}
+	if cfg.GitHub.Token != "" {
+		tracker, err := NewGitHub(cfg.GitHub)
+		if err != nil {
+			return nil, err
+		}
+		trackers[GitHub] = tracker
+	}
+	if cfg.Jira.APIToken != "" {
+		tracker, err := NewJira(cfg.Jira)
+		if err != nil {
+			return nil, err
+		}
+		trackers[Jira] = tracker
+	}
+	return trackers, nil
+}
+
+// defaultClient sends the requests of trackers without their own client
+var defaultClient = &http.Client{Timeout: 30 * time.Second}
+
+// checkResponse returns ErrRejected with the start of the body for responses
+// that aren't a success
+func checkResponse(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return fmt.Errorf("%w: %s: %s", ErrRejected, resp.Status, strings.TrimSpace(string(body)))
+}
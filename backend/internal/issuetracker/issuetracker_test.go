@@ -0,0 +1,96 @@
+package issuetracker
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitHub(t *testing.T) {
+	var received map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/repos/acme/site/issues", r.URL.Path)
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"number": 12, "html_url": "https://github.com/acme/site/issues/12"}`))
+	}))
+	defer server.Close()
+
+	tracker, err := NewGitHub(GitHubOptions{APIURL: server.URL + "/", Token: "secret", Repository: "acme/site", Labels: []string{"crawler"}})
+	require.NoError(t, err)
+	created, err := tracker.Create(context.Background(), Issue{Title: "Broken link", Body: "404", Labels: []string{"broken link"}})
+	require.NoError(t, err)
+	assert.Equal(t, "acme/site#12", created.Key)
+	assert.Equal(t, "https://github.com/acme/site/issues/12", created.URL)
+	assert.Equal(t, "Broken link", received["title"])
+	assert.Equal(t, []interface{}{"crawler", "broken link"}, received["labels"])
+
+	_, err = NewGitHub(GitHubOptions{Token: "secret", Repository: "acme"})
+	assert.Error(t, err)
+}
+
+func TestJira(t *testing.T) {
+	var received struct {
+		Fields map[string]interface{} `json:"fields"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/rest/api/2/issue", r.URL.Path)
+		user, password, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "bot@example.com", user)
+		assert.Equal(t, "secret", password)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id": "10001", "key": "WEB-7"}`))
+	}))
+	defer server.Close()
+
+	tracker, err := NewJira(JiraOptions{BaseURL: server.URL, Email: "bot@example.com", APIToken: "secret", Project: "WEB"})
+	require.NoError(t, err)
+	created, err := tracker.Create(context.Background(), Issue{Title: "Broken link", Body: "404", Labels: []string{"broken link"}})
+	require.NoError(t, err)
+	assert.Equal(t, "WEB-7", created.Key)
+	assert.Equal(t, server.URL+"/browse/WEB-7", created.URL)
+	assert.Equal(t, map[string]interface{}{"key": "WEB"}, received.Fields["project"])
+	assert.Equal(t, map[string]interface{}{"name": "Bug"}, received.Fields["issuetype"])
+	assert.Equal(t, []interface{}{"broken-link"}, received.Fields["labels"])
+
+	_, err = NewJira(JiraOptions{BaseURL: server.URL, APIToken: "secret", Project: "WEB"})
+	assert.Error(t, err)
+}
+
+func TestCreate_Rejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message": "Bad credentials"}`, http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	tracker, err := NewGitHub(GitHubOptions{APIURL: server.URL, Token: "expired", Repository: "acme/site"})
+	require.NoError(t, err)
+	_, err = tracker.Create(context.Background(), Issue{Title: "Broken link"})
+	assert.ErrorIs(t, err, ErrRejected)
+	assert.Contains(t, err.Error(), "Bad credentials")
+}
+
+func TestOpen(t *testing.T) {
+	trackers, err := Open(Config{})
+	require.NoError(t, err)
+	assert.Empty(t, trackers)
+
+	trackers, err = Open(Config{
+		GitHub: GitHubOptions{Token: "secret", Repository: "acme/site"},
+		Jira:   JiraOptions{BaseURL: "https://acme.atlassian.net", Email: "bot@example.com", APIToken: "secret", Project: "WEB"},
+	})
+	require.NoError(t, err)
+	assert.IsType(t, &GitHubTracker{}, trackers[GitHub])
+	assert.IsType(t, &JiraTracker{}, trackers[Jira])
+
+	_, err = Open(Config{GitHub: GitHubOptions{Token: "secret"}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,106 @@
+package issuetracker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// JiraOptions configures filing issues in a Jira project
+type JiraOptions struct {
+	// BaseURL is the site, e.g. https://example.atlassian.net
+	BaseURL string
+	// Email and APIToken authenticate with basic auth
+	Email    string
+	APIToken string
+	// Project is the key of the project, e.g. WEB
+	Project string
+	// IssueType is the name of the type of new issues; defaults to Bug
+	IssueType string
+	// Labels are added to every issue, next to the ones of the issue
+	Labels []string
+	// Client sends the requests; defaults to a client with a 30 second timeout
+	Client *http.Client
+}
+
+// JiraTracker files issues through the Jira REST API
+type JiraTracker struct {
+	opts JiraOptions
+}
+
+// NewJira validates the options and returns the project's tracker
+func NewJira(opts JiraOptions) (*JiraTracker, error) {
+	base, err := url.Parse(opts.BaseURL)
+	if err != nil || base.Host == "" || (base.Scheme != "http" && base.Scheme != "https") {
+		return nil, fmt.Errorf("invalid Jira URL %q", opts.BaseURL)
+	}
+	if opts.Email == "" || opts.APIToken == "" {
+		return nil, errors.New("Jira issues need an email and API token")
+	}
+	if opts.Project == "" {
+		return nil, errors.New("Jira issues need a project key")
+	}
+	if opts.IssueType == "" {
+		opts.IssueType = "Bug"
+	}
+	opts.BaseURL = strings.TrimRight(opts.BaseURL, "/")
+	if opts.Client == nil {
+		opts.Client = defaultClient
+	}
+	return &JiraTracker{opts: opts}, nil
+}
+
+// Create opens an issue in the project
+func (j *JiraTracker) Create(ctx context.Context, issue Issue) (*Created, error) {
+	// Jira labels can't contain spaces
+	labels := []string{}
+	for _, label := range append(append([]string{}, j.opts.Labels...), issue.Labels...) {
+		labels = append(labels, strings.ReplaceAll(label, " ", "-"))
+	}
+	payload, err := json.Marshal(map[string]interface{}{
+		"fields": map[string]interface{}{
+			"project":     map[string]string{"key": j.opts.Project},
+			"issuetype":   map[string]string{"name": j.opts.IssueType},
+			"summary":     issue.Title,
+			"description": issue.Body,
+			"labels":      labels,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Version 2 of the API takes the description as plain text
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, j.opts.BaseURL+"/rest/api/2/issue", bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth(j.opts.Email, j.opts.APIToken)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := j.opts.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach Jira: %w", err)
+	}
+	defer resp.Body.Close()
+	if err := checkResponse(resp); err != nil {
+		return nil, err
+	}
+
+	var created struct {
+		Key string `json:"key"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		return nil, fmt.Errorf("invalid Jira response: %w", err)
+	}
+	if created.Key == "" {
+		return nil, errors.New("invalid Jira response: no issue key")
+	}
+	return &Created{Key: created.Key, URL: j.opts.BaseURL + "/browse/" + created.Key}, nil
+}
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"web-crawler-backend/internal/apperror"
+	"web-crawler-backend/internal/models"
+	"web-crawler-backend/internal/services"
+)
+
+// apiTokenKey holds the API token of requests authenticated by one
+const apiTokenKey = "api_token"
+
+// authenticate validates the bearer token of a request: an API token, which
+// it keeps in the context for TokenScope, or the JWT of a login
+func authenticate(c *gin.Context, authService *services.AuthService, tokenString string) (*models.JWTClaims, error) {
+	service := authService.WithContext(c.Request.Context())
+	if !strings.HasPrefix(tokenString, models.APITokenPrefix) {
+		return service.ValidateToken(tokenString)
+	}
+
+	claims, token, err := service.ValidateAPIToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	c.Set(apiTokenKey, token)
+	return claims, nil
+}
+
+// apiToken returns the API token the request was authenticated by
+func apiToken(c *gin.Context) (*models.APIToken, bool) {
+	value, ok := c.Get(apiTokenKey)
+	if !ok {
+		return nil, false
+	}
+	token, ok := value.(*models.APIToken)
+	return token, ok
+}
+
+// TokenScope limits the routes API tokens may use: requests that only read
+// need the read scope, and others the write scope. Requests of logged in
+// users pass. It must run after AuthRequired.
+func TokenScope(read, write models.APIScope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, ok := apiToken(c)
+		if !ok {
+			c.Next()
+			return
+		}
+
+		required := write
+		if isReadOnly(c.Request.Method) {
+			required = read
+		}
+		if !token.Scope.AtLeast(required) {
+			apperror.Abort(c, apperror.New(http.StatusForbidden, "Forbidden", "This API token needs the "+string(required)+" scope").WithCode("insufficient_scope"))
+			return
+		}
+		c.Next()
+	}
+}
+
+// UserTokensOnly refuses API tokens of organizations on routes outside their
+// organization, such as the management of organizations and tokens. It must
+// run after AuthRequired.
+func UserTokensOnly() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token, ok := apiToken(c); ok && token.OrganizationID != 0 {
+			apperror.Abort(c, apperror.New(http.StatusForbidden, "Forbidden", "API tokens of an organization only work on its URLs").WithCode("insufficient_scope"))
+			return
+		}
+		c.Next()
+	}
+}
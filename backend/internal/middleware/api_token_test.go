@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"web-crawler-backend/internal/models"
+	"web-crawler-backend/internal/password"
+	"web-crawler-backend/internal/services"
+)
+
+func TestAPITokens(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.User{}, &models.Session{}, &models.URL{}, &models.Organization{}, &models.Membership{}, &models.APIToken{}))
+
+	hash, err := password.Default().Hash("secret")
+	require.NoError(t, err)
+	user := &models.User{Username: "alice", Email: "alice@example.com", Password: hash, IsActive: true, IsAdmin: true}
+	require.NoError(t, db.Create(user).Error)
+	organizations := services.NewOrganizationService(db)
+	org, err := organizations.CreateOrganization(user.ID, "Acme")
+	require.NoError(t, err)
+
+	apiTokens := services.NewAPITokenService(db)
+	authService := services.NewAuthService(db).WithAPITokens(apiTokens)
+	newToken := func(scope models.APIScope, orgID uint) string {
+		token, err := apiTokens.CreateToken(user.ID, &models.CreateAPITokenRequest{Name: string(scope), Scope: scope, OrganizationID: orgID})
+		require.NoError(t, err)
+		return token.Token
+	}
+	readToken := newToken(models.APIScopeRead, 0)
+	crawlToken := newToken(models.APIScopeCrawl, 0)
+	adminToken := newToken(models.APIScopeAdmin, 0)
+	orgToken := newToken(models.APIScopeAdmin, org.ID)
+	login, err := authService.Login(&models.LoginRequest{Username: "alice", Password: "secret"})
+	require.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	handler := func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"organization_id": c.GetUint("organization_id"), "is_admin": c.GetBool("is_admin")})
+	}
+	urls := router.Group("/urls", AuthRequired(authService), TokenScope(models.APIScopeRead, models.APIScopeAdmin), OrganizationScope(organizations))
+	urls.GET("", handler)
+	urls.POST("", handler)
+	crawl := router.Group("/crawl", AuthRequired(authService), TokenScope(models.APIScopeRead, models.APIScopeCrawl), OrganizationScope(organizations))
+	crawl.POST("/:id", handler)
+	router.GET("/orgs", AuthRequired(authService), UserTokensOnly(), handler)
+
+	request := func(method, path, token, org string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		if org != "" {
+			req.Header.Set("X-Organization-ID", org)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		org    string
+		want   int
+	}{
+		{"read token reads", "GET", "/urls", readToken, "", http.StatusOK},
+		{"read token can't crawl", "POST", "/crawl/1", readToken, "", http.StatusForbidden},
+		{"crawl token crawls", "POST", "/crawl/1", crawlToken, "", http.StatusOK},
+		{"crawl token can't change URLs", "POST", "/urls", crawlToken, "", http.StatusForbidden},
+		{"admin token changes URLs", "POST", "/urls", adminToken, "", http.StatusOK},
+		{"logins aren't scoped", "POST", "/urls", login.Token, "", http.StatusOK},
+		{"unknown token", "GET", "/urls", models.APITokenPrefix + "guess", "", http.StatusUnauthorized},
+		{"organization token in its organization", "POST", "/urls", orgToken, fmt.Sprint(org.ID), http.StatusOK},
+		{"organization token in another organization", "GET", "/urls", orgToken, "999", http.StatusForbidden},
+		{"organization token outside its routes", "GET", "/orgs", orgToken, "", http.StatusForbidden},
+		{"user token outside organizations", "GET", "/orgs", readToken, "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := request(tt.method, tt.path, tt.token, tt.org)
+			assert.Equal(t, tt.want, w.Code, w.Body.String())
+			if tt.want == http.StatusForbidden {
+				assert.Contains(t, w.Body.String(), `"code":"insufficient_scope"`)
+			}
+		})
+	}
+
+	t.Run("organization tokens default to their organization", func(t *testing.T) {
+		w := request("GET", "/urls", orgToken, "")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, fmt.Sprintf(`{"organization_id":%d,"is_admin":false}`, org.ID), w.Body.String())
+	})
+
+	t.Run("admin rights need the admin scope", func(t *testing.T) {
+		w := request("GET", "/urls", readToken, "")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"organization_id":0,"is_admin":false}`, w.Body.String())
+		w = request("GET", "/urls", adminToken, "")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"organization_id":0,"is_admin":true}`, w.Body.String())
+	})
+}
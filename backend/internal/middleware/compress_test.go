@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupCompressTest() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Compress(100))
+	router.GET("/large", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"data": strings.Repeat("link ", 100)})
+	})
+	router.GET("/small", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"data": "ok"})
+	})
+	router.GET("/binary", func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/zip", make([]byte, 500))
+	})
+	router.GET("/empty", func(c *gin.Context) {
+		c.Status(http.StatusNotModified)
+	})
+	return router
+}
+
+func compressRequest(router *gin.Engine, path, acceptEncoding string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("GET", path, nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestCompress(t *testing.T) {
+	router := setupCompressTest()
+	expected := `{"data":"` + strings.Repeat("link ", 100) + `"}`
+
+	t.Run("gzip", func(t *testing.T) {
+		w := compressRequest(router, "/large", "gzip, deflate")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+		assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+
+		reader, err := gzip.NewReader(w.Body)
+		require.NoError(t, err)
+		body, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		assert.Equal(t, expected, string(body))
+	})
+
+	t.Run("brotli is preferred", func(t *testing.T) {
+		w := compressRequest(router, "/large", "gzip, br")
+		assert.Equal(t, "br", w.Header().Get("Content-Encoding"))
+		body, err := io.ReadAll(brotli.NewReader(w.Body))
+		require.NoError(t, err)
+		assert.Equal(t, expected, string(body))
+	})
+
+	t.Run("rejected encodings are skipped", func(t *testing.T) {
+		w := compressRequest(router, "/large", "br;q=0, gzip;q=0.5")
+		assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	})
+
+	t.Run("uncompressed without Accept-Encoding", func(t *testing.T) {
+		w := compressRequest(router, "/large", "")
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+		assert.Equal(t, expected, w.Body.String())
+	})
+
+	t.Run("small responses pass through", func(t *testing.T) {
+		w := compressRequest(router, "/small", "gzip")
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+		assert.Equal(t, `{"data":"ok"}`, w.Body.String())
+	})
+
+	t.Run("binary responses pass through", func(t *testing.T) {
+		w := compressRequest(router, "/binary", "gzip")
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+		assert.Len(t, w.Body.Bytes(), 500)
+	})
+
+	t.Run("bodiless responses", func(t *testing.T) {
+		w := compressRequest(router, "/empty", "gzip")
+		assert.Equal(t, http.StatusNotModified, w.Code)
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+		assert.Empty(t, w.Body.String())
+	})
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	for header, expected := range map[string]string{
+		"":                   "",
+		"identity":           "",
+		"gzip":               "gzip",
+		"GZIP":               "gzip",
+		"*":                  "gzip",
+		"deflate, br":        "br",
+		"br;q=0":             "",
+		"br;q=0, *;q=0.1":    "gzip",
+		"gzip;q=0, br;q=0.8": "br",
+	} {
+		assert.Equal(t, expected, negotiateEncoding(header), header)
+	}
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"web-crawler-backend/internal/authcookie"
+)
+
+// cookieAuthKey marks requests authenticated by the auth cookie
+const cookieAuthKey = "cookie_auth"
+
+// CookieAuth lets browser clients authenticate with the HttpOnly cookie set
+// at login instead of the Authorization header, by moving the token of the
+// cookie into the header for AuthRequired and the handlers. Use CSRF after
+// it, since browsers send the cookie with cross-site requests too. Requests
+// with an Authorization header are left alone, and nil cookies disable
+// cookie auth.
+func CookieAuth(cookies *authcookie.Options) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") != "" {
+			c.Next()
+			return
+		}
+		token, ok := cookies.Token(c)
+		if !ok {
+			c.Next()
+			return
+		}
+
+		c.Request.Header.Set("Authorization", "Bearer "+token)
+		c.Set(cookieAuthKey, true)
+		c.Next()
+	}
+}
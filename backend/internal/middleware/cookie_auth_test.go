@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"web-crawler-backend/internal/authcookie"
+)
+
+func TestCookieAuth(t *testing.T) {
+	tests := []struct {
+		name          string
+		cookies       *authcookie.Options
+		authorization string
+		want          string
+	}{
+		{"cookie", &authcookie.Options{}, "", "Bearer cookie-token"},
+		{"header wins", &authcookie.Options{}, "Bearer header-token", "Bearer header-token"},
+		{"disabled", nil, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.Use(CookieAuth(tt.cookies))
+			router.GET("/test", func(c *gin.Context) {
+				c.String(http.StatusOK, c.GetHeader("Authorization"))
+			})
+
+			req := httptest.NewRequest("GET", "/test", nil)
+			req.AddCookie(&http.Cookie{Name: authcookie.DefaultName, Value: "cookie-token"})
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.want, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"web-crawler-backend/internal/authcookie"
+)
+
+func TestCSRF(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cookies := &authcookie.Options{Secret: []byte("secret")}
+
+	// Issue a CSRF token the way the CSRF endpoint does
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	csrfToken, err := cookies.IssueCSRF(c)
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.Use(CookieAuth(cookies), CSRF(cookies, HeaderAuthenticated, ExemptRoutes("/hooks/:id")))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router.GET("/urls", ok)
+	router.POST("/urls", ok)
+	router.POST("/hooks/:id", ok)
+
+	tests := []struct {
+		name          string
+		method        string
+		path          string
+		authCookie    bool
+		csrf          string
+		authorization string
+		want          int
+	}{
+		{"read", "GET", "/urls", true, "", "", http.StatusOK},
+		{"write without token", "POST", "/urls", true, "", "", http.StatusForbidden},
+		{"write with token", "POST", "/urls", true, csrfToken, "", http.StatusOK},
+		{"write with forged token", "POST", "/urls", true, "nonce.signature", "", http.StatusForbidden},
+		{"write without auth cookie", "POST", "/urls", false, "", "", http.StatusOK},
+		{"API client", "POST", "/urls", true, "", "Bearer header-token", http.StatusOK},
+		{"exempt route", "POST", "/hooks/1", true, "", "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.authCookie {
+				req.AddCookie(&http.Cookie{Name: authcookie.DefaultName, Value: "cookie-token"})
+			}
+			if tt.csrf != "" {
+				req.AddCookie(&http.Cookie{Name: authcookie.CSRFCookie, Value: tt.csrf})
+				req.Header.Set(authcookie.CSRFHeader, tt.csrf)
+			}
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.want, w.Code)
+			if tt.want == http.StatusForbidden {
+				assert.Contains(t, w.Body.String(), `"code":"csrf_token_invalid"`)
+			}
+		})
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		router := gin.New()
+		router.Use(CSRF(nil))
+		router.POST("/urls", ok)
+		req := httptest.NewRequest("POST", "/urls", nil)
+		req.AddCookie(&http.Cookie{Name: authcookie.DefaultName, Value: "cookie-token"})
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"web-crawler-backend/internal/apperror"
+	"web-crawler-backend/internal/services"
+)
+
+// maxIdempotencyKeyLength matches the size of the key column
+const maxIdempotencyKeyLength = 255
+
+// Idempotency makes a write endpoint safe to retry. The response to a request
+// with an Idempotency-Key header is stored, and later requests from the same
+// user with the same key get that response again instead of repeating the
+// work. Requests without the header are handled as usual. It must run after
+// AuthRequired, since keys are scoped to the user.
+func Idempotency(service *services.IdempotencyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader("Idempotency-Key")
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			apperror.Abort(c, apperror.New(http.StatusBadRequest, "Invalid idempotency key", "Idempotency-Key must be at most 255 characters"))
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			apperror.Abort(c, apperror.Wrap(http.StatusBadRequest, "Invalid request body", err))
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		sum := sha256.Sum256(body)
+
+		record, err := service.Begin(services.IdempotencyRequest{
+			UserID:      c.GetUint("user_id"),
+			Key:         key,
+			Method:      c.Request.Method,
+			Path:        c.Request.URL.Path,
+			RequestHash: hex.EncodeToString(sum[:]),
+		}, time.Now())
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrIdempotencyKeyInUse):
+				apperror.Abort(c, apperror.Wrap(http.StatusConflict, "Request in progress", err))
+			case errors.Is(err, services.ErrIdempotencyKeyReused):
+				apperror.Abort(c, apperror.Wrap(http.StatusUnprocessableEntity, "Idempotency key reused", err))
+			default:
+				apperror.Abort(c, apperror.Wrap(http.StatusInternalServerError, "Failed to check idempotency key", err))
+			}
+			c.Abort()
+			return
+		}
+
+		if record.Completed() {
+			c.Header("Idempotent-Replayed", "true")
+			c.Data(record.StatusCode, record.ContentType, record.ResponseBody)
+			c.Abort()
+			return
+		}
+
+		writer := &recordingWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+		c.Writer = writer.ResponseWriter
+
+		// Server errors and unanswered requests are worth retrying, so they free the key
+		if !writer.Written() || writer.Status() >= http.StatusInternalServerError {
+			if err := service.Release(record); err != nil {
+				log.Printf("Failed to release idempotency key: %v", err)
+			}
+			return
+		}
+		if err := service.Complete(record, writer.Status(), writer.Header().Get("Content-Type"), writer.body.Bytes()); err != nil {
+			log.Printf("Failed to store idempotent response: %v", err)
+		}
+	}
+}
+
+// recordingWriter keeps a copy of the response body
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,138 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"web-crawler-backend/internal/models"
+	"web-crawler-backend/internal/services"
+)
+
+func setupIdempotencyTest(t *testing.T) (*gin.Engine, *gorm.DB, *int) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.IdempotencyKey{}))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		var userID uint = 1
+		fmt.Sscan(c.GetHeader("X-User"), &userID)
+		c.Set("user_id", userID)
+	})
+	router.Use(Idempotency(services.NewIdempotencyService(db, time.Hour)))
+
+	calls := 0
+	router.POST("/urls", func(c *gin.Context) {
+		calls++
+		c.JSON(http.StatusCreated, gin.H{"call": calls})
+	})
+	router.POST("/fail", func(c *gin.Context) {
+		calls++
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "boom"})
+	})
+	return router, db, &calls
+}
+
+func idempotentRequest(router *gin.Engine, path, key, body, user string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", path, strings.NewReader(body))
+	if key != "" {
+		req.Header.Set("Idempotency-Key", key)
+	}
+	if user != "" {
+		req.Header.Set("X-User", user)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotency(t *testing.T) {
+	t.Run("retries replay the stored response", func(t *testing.T) {
+		router, _, calls := setupIdempotencyTest(t)
+
+		first := idempotentRequest(router, "/urls", "abc", `{"url":"https://example.com"}`, "")
+		assert.Equal(t, http.StatusCreated, first.Code)
+		assert.Empty(t, first.Header().Get("Idempotent-Replayed"))
+
+		retry := idempotentRequest(router, "/urls", "abc", `{"url":"https://example.com"}`, "")
+		assert.Equal(t, http.StatusCreated, retry.Code)
+		assert.Equal(t, "true", retry.Header().Get("Idempotent-Replayed"))
+		assert.Equal(t, first.Body.String(), retry.Body.String())
+		assert.Equal(t, first.Header().Get("Content-Type"), retry.Header().Get("Content-Type"))
+		assert.Equal(t, 1, *calls)
+	})
+
+	t.Run("requests without a key are not stored", func(t *testing.T) {
+		router, _, calls := setupIdempotencyTest(t)
+
+		idempotentRequest(router, "/urls", "", `{}`, "")
+		idempotentRequest(router, "/urls", "", `{}`, "")
+		assert.Equal(t, 2, *calls)
+	})
+
+	t.Run("keys are scoped to the user", func(t *testing.T) {
+		router, _, calls := setupIdempotencyTest(t)
+
+		idempotentRequest(router, "/urls", "abc", `{}`, "1")
+		w := idempotentRequest(router, "/urls", "abc", `{}`, "2")
+		assert.Empty(t, w.Header().Get("Idempotent-Replayed"))
+		assert.Equal(t, 2, *calls)
+	})
+
+	t.Run("reusing a key for a different body is rejected", func(t *testing.T) {
+		router, _, calls := setupIdempotencyTest(t)
+
+		idempotentRequest(router, "/urls", "abc", `{"url":"https://example.com"}`, "")
+		w := idempotentRequest(router, "/urls", "abc", `{"url":"https://other.com"}`, "")
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Equal(t, 1, *calls)
+	})
+
+	t.Run("a key in progress conflicts", func(t *testing.T) {
+		router, db, calls := setupIdempotencyTest(t)
+		require.NoError(t, db.Create(&models.IdempotencyKey{
+			UserID: 1, Key: "abc", Method: "POST", Path: "/urls",
+			RequestHash: "44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a", // {}
+			ExpiresAt:   time.Now().Add(time.Hour),
+		}).Error)
+
+		w := idempotentRequest(router, "/urls", "abc", `{}`, "")
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Equal(t, 0, *calls)
+	})
+
+	t.Run("server errors free the key", func(t *testing.T) {
+		router, db, calls := setupIdempotencyTest(t)
+
+		idempotentRequest(router, "/fail", "abc", `{}`, "")
+		w := idempotentRequest(router, "/fail", "abc", `{}`, "")
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, 2, *calls)
+
+		var count int64
+		db.Model(&models.IdempotencyKey{}).Count(&count)
+		assert.Zero(t, count)
+	})
+
+	t.Run("oversized keys are rejected", func(t *testing.T) {
+		router, _, calls := setupIdempotencyTest(t)
+
+		w := idempotentRequest(router, "/urls", strings.Repeat("k", 256), `{}`, "")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, 0, *calls)
+	})
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"web-crawler-backend/internal/apperror"
+)
+
+// BodyLimit rejects request bodies larger than maxBytes. Bodies that announce
+// their size are refused up front with 413; others fail to bind once they
+// cross the limit. A limit of zero or less disables the check.
+func BodyLimit(maxBytes int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if maxBytes <= 0 || c.Request.Body == nil {
+			c.Next()
+			return
+		}
+
+		if c.Request.ContentLength > maxBytes {
+			apperror.Abort(c, apperror.New(http.StatusRequestEntityTooLarge, "Request too large", fmt.Sprintf("Request body may be at most %d bytes", maxBytes)))
+			return
+		}
+
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes)
+		c.Next()
+	}
+}
+
+// Timeout gives every request a deadline. Services get it through the
+// request context, so their queries are cancelled once it passes. Handlers
+// that return without responding after the deadline get a 503.
+// A timeout of zero or less disables the deadline.
+func Timeout(timeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if timeout <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && !c.Writer.Written() {
+			apperror.Abort(c, apperror.New(http.StatusServiceUnavailable, "Request timed out", fmt.Sprintf("The request did not complete within %s", timeout)))
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"web-crawler-backend/internal/services"
+)
+
+// RecordRequestMetrics counts every request by route and user. The user is
+// read after the handlers ran, so it is known for routes behind AuthRequired.
+func RecordRequestMetrics(metrics *services.RequestMetrics) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+		metrics.Record(c.Request.Method, c.FullPath(), c.GetUint("user_id"), c.GetString("username"), c.Writer.Status(), time.Since(start))
+	}
+}
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"web-crawler-backend/internal/services"
+)
+
+func TestRecordRequestMetrics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	metrics := services.NewRequestMetrics()
+	router := gin.New()
+	router.Use(RecordRequestMetrics(metrics))
+	authenticated := func(c *gin.Context) {
+		c.Set("user_id", uint(7))
+		c.Set("username", "alice")
+		c.Next()
+	}
+	router.GET("/urls/:id", authenticated, func(c *gin.Context) {
+		if c.Param("id") == "0" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "URL not found"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": c.Param("id")})
+	})
+	router.GET("/health", func(c *gin.Context) {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "degraded"})
+	})
+
+	for _, path := range []string{"/urls/1", "/urls/2", "/urls/0", "/health", "/missing"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+	}
+
+	report := metrics.Report()
+	require.Len(t, report.Routes, 3)
+	assert.Equal(t, "/urls/:id", report.Routes[0].Route, "requests are grouped by route pattern")
+	assert.Equal(t, int64(3), report.Routes[0].Calls)
+	assert.Equal(t, int64(1), report.Routes[0].ClientErrors)
+	assert.Equal(t, "", report.Routes[1].Route, "unknown paths share one entry")
+	assert.Equal(t, int64(1), report.Routes[1].ClientErrors)
+	assert.Equal(t, "/health", report.Routes[2].Route)
+	assert.Equal(t, int64(1), report.Routes[2].ServerErrors)
+
+	require.Len(t, report.Users, 2)
+	assert.Equal(t, uint(7), report.Users[0].UserID)
+	assert.Equal(t, "alice", report.Users[0].Username)
+	assert.Equal(t, int64(3), report.Users[0].Calls)
+	assert.Equal(t, uint(0), report.Users[1].UserID)
+	assert.Equal(t, int64(2), report.Users[1].Calls)
+	assert.GreaterOrEqual(t, report.Users[0].MaxLatencyMs, report.Users[0].AvgLatencyMs)
+}
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -8,6 +9,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"web-crawler-backend/internal/apperror"
 	"web-crawler-backend/internal/services"
 )
 
@@ -28,33 +30,32 @@ func Logger() gin.HandlerFunc {
 	})
 }
 
-// ErrorHandler provides centralized error handling
+// ErrorHandler provides centralized error handling. Errors recorded with
+// c.Error by handlers that didn't respond are rendered in the error envelope:
+// an *apperror.Error as it is, bind errors as validation errors and anything
+// else as an internal error.
 func ErrorHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Next()
 
-		if len(c.Errors) > 0 {
-			err := c.Errors.Last()
-			log.Printf("Error: %v", err.Err)
-
-			switch err.Type {
-			case gin.ErrorTypeBind:
-				c.JSON(http.StatusBadRequest, gin.H{
-					"error":   "Validation error",
-					"message": err.Error(),
-				})
-			case gin.ErrorTypePublic:
-				c.JSON(http.StatusInternalServerError, gin.H{
-					"error":   "Internal server error",
-					"message": err.Error(),
-				})
-			default:
-				c.JSON(http.StatusInternalServerError, gin.H{
-					"error":   "Internal server error",
-					"message": "Something went wrong",
-				})
-			}
+		// Handlers that already responded only record errors for logging
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		err := c.Errors.Last()
+		log.Printf("Error: %v", err.Err)
+
+		var appErr *apperror.Error
+		switch {
+		case errors.As(err.Err, &appErr):
+		case err.Type == gin.ErrorTypeBind:
+			appErr = apperror.New(http.StatusBadRequest, "Validation error", err.Error()).WithCode(apperror.CodeValidation)
+		case err.Type == gin.ErrorTypePublic:
+			appErr = apperror.New(http.StatusInternalServerError, "Internal server error", err.Error())
+		default:
+			appErr = apperror.New(http.StatusInternalServerError, "Internal server error", "Something went wrong")
 		}
+		apperror.Render(c, appErr)
 	}
 }
 
@@ -62,10 +63,7 @@ func ErrorHandler() gin.HandlerFunc {
 func Recovery() gin.HandlerFunc {
 	return gin.CustomRecovery(func(c *gin.Context, recovered interface{}) {
 		log.Printf("Panic recovered: %v", recovered)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Internal server error",
-			"message": "Server encountered an unexpected error",
-		})
+		apperror.Render(c, apperror.New(http.StatusInternalServerError, "Internal server error", "Server encountered an unexpected error"))
 	})
 }
 
@@ -75,11 +73,7 @@ func AuthRequired(authService *services.AuthService) gin.HandlerFunc {
 		// Get token from Authorization header
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error":   "Unauthorized",
-				"message": "Authorization header is required",
-			})
-			c.Abort()
+			apperror.Abort(c, apperror.New(http.StatusUnauthorized, "Unauthorized", "Authorization header is required"))
 			return
 		}
 
@@ -88,22 +82,14 @@ func AuthRequired(authService *services.AuthService) gin.HandlerFunc {
 		if strings.HasPrefix(authHeader, "Bearer ") {
 			tokenString = authHeader[7:]
 		} else {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error":   "Unauthorized",
-				"message": "Invalid authorization header format",
-			})
-			c.Abort()
+			apperror.Abort(c, apperror.New(http.StatusUnauthorized, "Unauthorized", "Invalid authorization header format"))
 			return
 		}
 
 		// Validate token
-		claims, err := authService.ValidateToken(tokenString)
+		claims, err := authenticate(c, authService, tokenString)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error":   "Unauthorized",
-				"message": err.Error(),
-			})
-			c.Abort()
+			apperror.Abort(c, apperror.Wrap(http.StatusUnauthorized, "Unauthorized", err))
 			return
 		}
 
@@ -111,6 +97,7 @@ func AuthRequired(authService *services.AuthService) gin.HandlerFunc {
 		c.Set("user_id", claims.UserID)
 		c.Set("username", claims.Username)
 		c.Set("is_admin", claims.IsAdmin)
+		c.Set("session_id", claims.SessionID)
 		c.Set("claims", claims)
 
 		c.Next()
@@ -123,20 +110,12 @@ func AdminRequired() gin.HandlerFunc {
 		// This middleware should be used after AuthRequired
 		isAdmin, exists := c.Get("is_admin")
 		if !exists {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error":   "Internal server error",
-				"message": "User authentication context not found",
-			})
-			c.Abort()
+			apperror.Abort(c, apperror.New(http.StatusInternalServerError, "Internal server error", "User authentication context not found"))
 			return
 		}
 
 		if !isAdmin.(bool) {
-			c.JSON(http.StatusForbidden, gin.H{
-				"error":   "Forbidden",
-				"message": "Admin access required",
-			})
-			c.Abort()
+			apperror.Abort(c, apperror.New(http.StatusForbidden, "Forbidden", "Admin access required"))
 			return
 		}
 
@@ -160,12 +139,13 @@ func OptionalAuth(authService *services.AuthService) gin.HandlerFunc {
 			tokenString := authHeader[7:]
 			
 			// Validate token
-			claims, err := authService.ValidateToken(tokenString)
+			claims, err := authenticate(c, authService, tokenString)
 			if err == nil {
 				// Set user info in context if token is valid
 				c.Set("user_id", claims.UserID)
 				c.Set("username", claims.Username)
 				c.Set("is_admin", claims.IsAdmin)
+				c.Set("session_id", claims.SessionID)
 				c.Set("claims", claims)
 			}
 			// Continue regardless of token validity
@@ -31,7 +31,7 @@ func setupMiddlewareTest() (*gin.Engine, *services.AuthService) {
 	}
 
 	// Auto migrate models
-	err = db.AutoMigrate(&models.User{})
+	err = db.AutoMigrate(&models.User{}, &models.Session{})
 	if err != nil {
 		panic(err)
 	}
@@ -107,6 +107,7 @@ func TestErrorHandler(t *testing.T) {
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		require.NoError(t, err)
 		assert.Equal(t, "Validation error", response["error"])
+		assert.Equal(t, "validation_error", response["code"])
 	})
 	
 	t.Run("handles public errors", func(t *testing.T) {
@@ -148,6 +149,31 @@ func TestErrorHandler(t *testing.T) {
 		require.NoError(t, err)
 		assert.Equal(t, "Internal server error", response["error"])
 		assert.Equal(t, "Something went wrong", response["message"])
+		assert.Equal(t, "internal_error", response["code"])
+	})
+
+	t.Run("renders app errors with their code", func(t *testing.T) {
+		router, _ := setupMiddlewareTest()
+		router.Use(RequestID(), ErrorHandler())
+
+		router.GET("/test", func(c *gin.Context) {
+			c.Error(services.ErrURLNotFound)
+		})
+
+		req := httptest.NewRequest("GET", "/test", nil)
+		req.Header.Set("X-Request-ID", "req-1")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		var response map[string]interface{}
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, "URL not found", response["error"])
+		assert.Equal(t, "url_not_found", response["code"])
+		assert.Equal(t, "The requested URL does not exist", response["message"])
+		assert.Equal(t, "req-1", response["request_id"])
 	})
 	
 	t.Run("no errors - passes through", func(t *testing.T) {
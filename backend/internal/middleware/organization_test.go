@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"web-crawler-backend/internal/models"
+	"web-crawler-backend/internal/services"
+)
+
+func TestOrganizationScope(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.User{}, &models.URL{}, &models.Organization{}, &models.Membership{}))
+
+	service := services.NewOrganizationService(db)
+	org, err := service.CreateOrganization(1, "Acme")
+	require.NoError(t, err)
+	require.NoError(t, db.Create(&models.Membership{OrganizationID: org.ID, UserID: 2, Role: models.OrgRoleViewer}).Error)
+	require.NoError(t, db.Create(&models.Membership{OrganizationID: org.ID, UserID: 3, Role: models.OrgRoleMember}).Error)
+	personal := &models.URL{URL: "https://example.com"}
+	shared := &models.URL{URL: "https://example.com", OrganizationID: org.ID}
+	require.NoError(t, db.Create(personal).Error)
+	require.NoError(t, db.Create(shared).Error)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		var userID uint = 1
+		fmt.Sscan(c.GetHeader("X-User"), &userID)
+		c.Set("user_id", userID)
+	})
+	urls := router.Group("/urls", OrganizationScope(service), URLInScope(service))
+	handler := func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"organization_id": c.GetUint("organization_id")})
+	}
+	urls.GET("", handler)
+	urls.GET("/:id", handler)
+	urls.PUT("/:id", handler)
+	urls.DELETE("/:id", OrgRoleRequired(models.OrgRoleAdmin), handler)
+
+	request := func(method, path, user, org string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("X-User", user)
+		if org != "" {
+			req.Header.Set("X-Organization-ID", org)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	orgID := fmt.Sprint(org.ID)
+	sharedPath := fmt.Sprintf("/urls/%d", shared.ID)
+	personalPath := fmt.Sprintf("/urls/%d", personal.ID)
+
+	// Without the header requests work on the URLs outside any organization
+	w := request("GET", "/urls", "2", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"organization_id":0}`, w.Body.String())
+	assert.Equal(t, http.StatusOK, request("DELETE", personalPath, "2", "").Code)
+	assert.Equal(t, http.StatusNotFound, request("GET", sharedPath, "2", "").Code)
+
+	// Members see the URLs of their organization only
+	w = request("GET", sharedPath, "2", orgID)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, fmt.Sprintf(`{"organization_id":%d}`, org.ID), w.Body.String())
+	assert.Equal(t, http.StatusNotFound, request("GET", personalPath, "2", orgID).Code)
+
+	// Outsiders can't tell the organization exists
+	assert.Equal(t, http.StatusNotFound, request("GET", "/urls", "4", orgID).Code)
+	assert.Equal(t, http.StatusBadRequest, request("GET", "/urls", "1", "acme").Code)
+
+	// Viewers only read, and deleting needs an admin
+	assert.Equal(t, http.StatusForbidden, request("PUT", sharedPath, "2", orgID).Code)
+	assert.Equal(t, http.StatusOK, request("PUT", sharedPath, "3", orgID).Code)
+	assert.Equal(t, http.StatusForbidden, request("DELETE", sharedPath, "3", orgID).Code)
+	assert.Equal(t, http.StatusOK, request("DELETE", sharedPath, "1", orgID).Code)
+}
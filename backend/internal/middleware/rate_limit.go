@@ -0,0 +1,124 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"web-crawler-backend/internal/apperror"
+)
+
+// rateWindow counts the requests of one client in the current window
+type rateWindow struct {
+	count int
+	reset time.Time
+}
+
+// RateLimiter allows each client a fixed number of requests per window.
+// Clients are identified by a key, e.g. their IP address or user ID.
+type RateLimiter struct {
+	mu        sync.Mutex
+	limit     int
+	window    time.Duration
+	clients   map[string]*rateWindow
+	nextSweep time.Time
+	now       func() time.Time
+}
+
+// NewRateLimiter creates a limiter allowing limit requests per window.
+// A limit of zero or less disables it.
+func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
+	if window <= 0 {
+		window = time.Minute
+	}
+	return &RateLimiter{
+		limit:   limit,
+		window:  window,
+		clients: make(map[string]*rateWindow),
+		now:     time.Now,
+	}
+}
+
+// allow counts a request of the client and reports whether it is within the
+// limit, how many requests remain and when the window resets
+func (l *RateLimiter) allow(key string) (remaining int, reset time.Time, ok bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	client, exists := l.clients[key]
+	if !exists || !now.Before(client.reset) {
+		client = &rateWindow{reset: now.Add(l.window)}
+		l.clients[key] = client
+	}
+
+	if client.count >= l.limit {
+		return 0, client.reset, false
+	}
+	client.count++
+	return l.limit - client.count, client.reset, true
+}
+
+// sweep drops expired windows once per window so idle clients don't pile up
+func (l *RateLimiter) sweep(now time.Time) {
+	if now.Before(l.nextSweep) {
+		return
+	}
+	for key, client := range l.clients {
+		if !now.Before(client.reset) {
+			delete(l.clients, key)
+		}
+	}
+	l.nextSweep = now.Add(l.window)
+}
+
+// RateLimitByIP limits requests per client IP, for public endpoints
+func RateLimitByIP(limiter *RateLimiter) gin.HandlerFunc {
+	return rateLimit(limiter, func(c *gin.Context) string {
+		return "ip:" + c.ClientIP()
+	})
+}
+
+// RateLimitByUser limits requests per authenticated user. It must run after
+// AuthRequired; requests without a user fall back to their IP address.
+func RateLimitByUser(limiter *RateLimiter) gin.HandlerFunc {
+	return rateLimit(limiter, func(c *gin.Context) string {
+		if userID, ok := c.Get("user_id"); ok {
+			return fmt.Sprintf("user:%v", userID)
+		}
+		return "ip:" + c.ClientIP()
+	})
+}
+
+func rateLimit(limiter *RateLimiter, key func(*gin.Context) string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if limiter.limit <= 0 {
+			c.Next()
+			return
+		}
+
+		remaining, reset, ok := limiter.allow(key(c))
+		c.Header("X-RateLimit-Limit", strconv.Itoa(limiter.limit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		c.Header("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+
+		if !ok {
+			retryAfter := int(math.Ceil(reset.Sub(limiter.now()).Seconds()))
+			if retryAfter < 1 {
+				retryAfter = 1
+			}
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			apperror.Abort(c, apperror.New(http.StatusTooManyRequests, "Too many requests", fmt.Sprintf("Rate limit exceeded, retry in %d seconds", retryAfter)))
+			return
+		}
+
+		c.Next()
+	}
+}
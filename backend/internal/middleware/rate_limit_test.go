@@ -0,0 +1,115 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRateLimiter(limit int, now *time.Time) *RateLimiter {
+	limiter := NewRateLimiter(limit, time.Minute)
+	limiter.now = func() time.Time { return *now }
+	return limiter
+}
+
+func TestRateLimitByIP(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	now := time.Unix(1_700_000_000, 0)
+	router := gin.New()
+	router.Use(RateLimitByIP(newTestRateLimiter(2, &now)))
+	router.GET("/test", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+
+	request := func(ip string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/test", nil)
+		req.RemoteAddr = ip + ":1234"
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := request("10.0.0.1")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "2", w.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, "1", w.Header().Get("X-RateLimit-Remaining"))
+	assert.Equal(t, "1700000060", w.Header().Get("X-RateLimit-Reset"))
+
+	assert.Equal(t, http.StatusOK, request("10.0.0.1").Code)
+
+	now = now.Add(15 * time.Second)
+	w = request("10.0.0.1")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "0", w.Header().Get("X-RateLimit-Remaining"))
+	assert.Equal(t, "45", w.Header().Get("Retry-After"))
+	assert.Contains(t, w.Body.String(), "Too many requests")
+
+	// Other clients have their own budget
+	assert.Equal(t, http.StatusOK, request("10.0.0.2").Code)
+
+	// The window resets after a minute
+	now = now.Add(45 * time.Second)
+	assert.Equal(t, http.StatusOK, request("10.0.0.1").Code)
+}
+
+func TestRateLimitByUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	now := time.Unix(1_700_000_000, 0)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		if user := c.GetHeader("X-Test-User"); user != "" {
+			c.Set("user_id", user)
+		}
+	})
+	router.Use(RateLimitByUser(newTestRateLimiter(1, &now)))
+	router.GET("/test", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	request := func(user string) int {
+		req := httptest.NewRequest("GET", "/test", nil)
+		req.Header.Set("X-Test-User", user)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// Users behind the same IP are limited separately
+	assert.Equal(t, http.StatusOK, request("1"))
+	assert.Equal(t, http.StatusTooManyRequests, request("1"))
+	assert.Equal(t, http.StatusOK, request("2"))
+	assert.Equal(t, http.StatusOK, request(""))
+	assert.Equal(t, http.StatusTooManyRequests, request(""))
+}
+
+func TestRateLimiter_Disabled(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RateLimitByIP(NewRateLimiter(0, time.Minute)))
+	router.GET("/test", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	for i := 0; i < 5; i++ {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/test", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("X-RateLimit-Limit"))
+	}
+}
+
+func TestRateLimiter_Sweep(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	limiter := newTestRateLimiter(5, &now)
+	limiter.allow("a")
+	limiter.allow("b")
+	assert.Len(t, limiter.clients, 2)
+
+	now = now.Add(2 * time.Minute)
+	limiter.allow("c")
+	assert.Len(t, limiter.clients, 1)
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"web-crawler-backend/internal/apperror"
+)
+
+func TestRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestID())
+	router.GET("/test", func(c *gin.Context) {
+		c.String(http.StatusOK, c.GetString(apperror.RequestIDKey))
+	})
+	request := func(header string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/test", nil)
+		if header != "" {
+			req.Header.Set("X-Request-ID", header)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("requests get a new ID", func(t *testing.T) {
+		first, second := request(""), request("")
+		assert.Len(t, first.Body.String(), 32)
+		assert.Equal(t, first.Body.String(), first.Header().Get("X-Request-ID"))
+		assert.NotEqual(t, first.Body.String(), second.Body.String())
+	})
+
+	t.Run("a client ID is kept", func(t *testing.T) {
+		w := request("trace-42.a_b")
+		assert.Equal(t, "trace-42.a_b", w.Body.String())
+		assert.Equal(t, "trace-42.a_b", w.Header().Get("X-Request-ID"))
+	})
+
+	t.Run("unusable client IDs are replaced", func(t *testing.T) {
+		for _, header := range []string{"bad id", "evil\tlog", strings.Repeat("a", 129)} {
+			w := request(header)
+			assert.NotEqual(t, header, w.Body.String())
+			assert.Len(t, w.Body.String(), 32)
+		}
+	})
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"web-crawler-backend/internal/apperror"
+	"web-crawler-backend/internal/services"
+)
+
+// GuardTokens counts the requests rejected with 401 on token endpoints
+// against their client IP, and turns away IPs the guard has blocked. The
+// router must only trust X-Forwarded-For from its own proxies, or clients
+// could pick a new IP for every request.
+func GuardTokens(guard *services.TokenGuard) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if until, blocked := guard.Blocked(c.ClientIP()); blocked {
+			retryAfter := int(math.Ceil(time.Until(until).Seconds()))
+			if retryAfter < 1 {
+				retryAfter = 1
+			}
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			apperror.Abort(c, apperror.New(http.StatusTooManyRequests, "Too many invalid tokens", fmt.Sprintf("Blocked after too many invalid tokens, retry in %d seconds", retryAfter)).WithCode("client_blocked"))
+			return
+		}
+
+		c.Next()
+
+		if c.Writer.Status() == http.StatusUnauthorized {
+			guard.RecordFailure(c.Request.Context(), c.ClientIP(), c.FullPath())
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"web-crawler-backend/internal/models"
+	"web-crawler-backend/internal/services"
+)
+
+func TestGuardTokens(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.AuthIncident{}))
+	guard := services.NewTokenGuard(db, services.TokenGuardOptions{MaxFailures: 2, Window: time.Minute, BlockFor: time.Minute})
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/auth/refresh", GuardTokens(guard), func(c *gin.Context) {
+		if c.GetHeader("Authorization") != "Bearer valid" {
+			c.Status(http.StatusUnauthorized)
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+
+	require.NoError(t, router.SetTrustedProxies([]string{"10.0.0.100"}))
+
+	forwarded := ""
+	request := func(ip, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/auth/refresh", nil)
+		req.RemoteAddr = ip + ":1234"
+		req.Header.Set("Authorization", "Bearer "+token)
+		if forwarded != "" {
+			req.Header.Set("X-Forwarded-For", forwarded)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusOK, request("10.0.0.1", "valid").Code, "valid tokens don't count")
+	assert.Equal(t, http.StatusUnauthorized, request("10.0.0.1", "guess-1").Code)
+	assert.Equal(t, http.StatusUnauthorized, request("10.0.0.1", "guess-2").Code)
+
+	w := request("10.0.0.1", "valid")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code, "blocked even with a valid token")
+	assert.Equal(t, "60", w.Header().Get("Retry-After"))
+	assert.Contains(t, w.Body.String(), `"code":"client_blocked"`)
+
+	assert.Equal(t, http.StatusOK, request("10.0.0.2", "valid").Code)
+
+	var incident models.AuthIncident
+	require.NoError(t, db.First(&incident).Error)
+	assert.Equal(t, "10.0.0.1", incident.IPAddress)
+	assert.Equal(t, "/auth/refresh", incident.Path)
+
+	t.Run("forwarded addresses", func(t *testing.T) {
+		forwarded = "203.0.113.7"
+		assert.Equal(t, http.StatusTooManyRequests, request("10.0.0.1", "valid").Code, "clients can't pick their own address")
+
+		assert.Equal(t, http.StatusUnauthorized, request("10.0.0.100", "guess-1").Code)
+		assert.Equal(t, http.StatusUnauthorized, request("10.0.0.100", "guess-2").Code)
+		assert.Equal(t, http.StatusTooManyRequests, request("10.0.0.100", "valid").Code, "trusted proxies forward the client's address")
+		forwarded = "203.0.113.8"
+		assert.Equal(t, http.StatusOK, request("10.0.0.100", "valid").Code, "other clients behind the proxy pass")
+	})
+}
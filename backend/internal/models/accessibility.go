@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// Accessibility rules checked during extraction
+const (
+	A11yRuleImageAlt     = "image-alt"
+	A11yRuleFormLabel    = "form-label"
+	A11yRuleHTMLLang     = "html-lang"
+	A11yRuleHeadingOrder = "heading-order"
+)
+
+// AccessibilityIssue is a WCAG problem found on a crawled page
+type AccessibilityIssue struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	URLID     uint      `json:"url_id" gorm:"not null;index"`
+	CrawlID   uint      `json:"crawl_id" gorm:"not null;index"`
+	Rule      string    `json:"rule" gorm:"type:varchar(50);not null"`
+	WCAG      string    `json:"wcag"` // Success criterion, e.g. 1.1.1
+	Element   string    `json:"element" gorm:"type:text"`
+	Message   string    `json:"message"`
+	CreatedAt time.Time `json:"created_at"`
+	// Annotation is set when the issue was acknowledged or marked a false positive
+	Annotation *FindingAnnotation `json:"annotation,omitempty" gorm:"-"`
+}
+
+// FindingKey identifies the issue across crawls for annotations
+func (i AccessibilityIssue) FindingKey() string {
+	return i.Rule + " " + i.Element
+}
+
+// AccessibilityReport lists the accessibility issues of a crawl
+type AccessibilityReport struct {
+	URLID     uint                 `json:"url_id"`
+	CrawlID   uint                 `json:"crawl_id"`
+	Counts    map[string]int       `json:"counts"`    // Issues per rule, without annotated ones
+	Annotated int                  `json:"annotated"` // Issues that were acknowledged or marked false positives
+	Issues    []AccessibilityIssue `json:"issues"`
+}
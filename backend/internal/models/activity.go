@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// Activity event types
+const (
+	ActivityURLAdded         = "url.added"
+	ActivityCrawlCompleted   = "crawl.completed"
+	ActivityCrawlFailed      = "crawl.failed"
+	ActivityFindingAnnotated = "finding.annotated"
+	ActivityFindingCleared   = "finding.cleared"
+	ActivityKeywordMissing   = "keyword.missing"
+	ActivitySitemapDrift     = "sitemap.drift"
+	ActivityBrokenLinksAlert = "links.threshold_exceeded"
+	ActivityIssueFiled       = "finding.issue_filed"
+)
+
+// ActivityEvent is an entry in an account's activity feed and audit trail
+type ActivityEvent struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    uint      `json:"user_id" gorm:"not null;index"` // Account the event belongs to
+	ActorID   *uint     `json:"actor_id,omitempty"`            // User who caused the event, nil for system events
+	Type      string    `json:"type" gorm:"type:varchar(50);not null;index"`
+	URLID     *uint     `json:"url_id,omitempty" gorm:"index"`
+	CrawlID   *uint     `json:"crawl_id,omitempty"`
+	Message   string    `json:"message"`
+	Metadata  string    `json:"metadata,omitempty" gorm:"type:text"` // JSON object with event specific details
+	CreatedAt time.Time `json:"created_at" gorm:"index"`
+}
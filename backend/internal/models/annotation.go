@@ -0,0 +1,65 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Finding types that can be annotated
+const (
+	FindingBrokenLink         = "broken_link"
+	FindingAccessibilityIssue = "accessibility_issue"
+	FindingMixedContent       = "mixed_content"
+)
+
+// Annotation statuses
+const (
+	AnnotationAccepted      = "accepted"
+	AnnotationIgnored       = "ignored"
+	AnnotationAcknowledged  = "acknowledged"
+	AnnotationFalsePositive = "false_positive"
+)
+
+// FindingAnnotation marks a crawl finding as known, with the reason as a note.
+// Findings are matched by type and key (the link URL of broken links, the
+// resource URL of mixed content, and the rule and element of accessibility
+// issues), so the annotation keeps applying to later crawls that report the
+// same finding.
+type FindingAnnotation struct {
+	ID          uint   `json:"id" gorm:"primaryKey"`
+	URLID       uint   `json:"url_id" gorm:"not null;uniqueIndex:idx_finding_annotation"`
+	FindingType string `json:"finding_type" gorm:"type:varchar(50);not null;uniqueIndex:idx_finding_annotation"`
+	FindingKey  string `json:"finding_key" gorm:"type:varchar(768);not null"`
+	// FindingKeyHash is the SHA-256 of FindingKey, set on save. Keys are
+	// indexed by their hash since MySQL can't index 768 characters.
+	FindingKeyHash string    `json:"-" gorm:"type:char(64);not null;uniqueIndex:idx_finding_annotation"`
+	Status         string    `json:"status" gorm:"type:varchar(20);not null"` // acknowledged, false_positive, accepted, ignored
+	Reason         string    `json:"reason" gorm:"type:text"`
+	CreatedBy      uint      `json:"created_by"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// BeforeSave sets the hash of the finding key
+func (a *FindingAnnotation) BeforeSave(tx *gorm.DB) error {
+	a.FindingKeyHash = HashFindingKey(a.FindingKey)
+	return nil
+}
+
+// HashFindingKey returns the hex SHA-256 of a finding key, which unique
+// indexes of findings use in place of the key
+func HashFindingKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// FindingAnnotationRequest creates or updates an annotation
+type FindingAnnotationRequest struct {
+	FindingType string `json:"finding_type" binding:"required"`
+	FindingKey  string `json:"finding_key" binding:"required"`
+	Status      string `json:"status" binding:"required"`
+	Reason      string `json:"reason" binding:"required"`
+}
@@ -0,0 +1,68 @@
+package models
+
+import "time"
+
+// APIScope is what an API token may do
+type APIScope string
+
+const (
+	// APIScopeRead only reads
+	APIScopeRead APIScope = "read"
+	// APIScopeCrawl also starts and reprocesses crawls
+	APIScopeCrawl APIScope = "crawl"
+	// APIScopeAdmin may do everything its user may
+	APIScopeAdmin APIScope = "admin"
+)
+
+var apiScopeRanks = map[APIScope]int{
+	APIScopeRead:  1,
+	APIScopeCrawl: 2,
+	APIScopeAdmin: 3,
+}
+
+// Valid reports whether s is a known scope
+func (s APIScope) Valid() bool {
+	return apiScopeRanks[s] > 0
+}
+
+// AtLeast reports whether s grants everything min does
+func (s APIScope) AtLeast(min APIScope) bool {
+	return s.Valid() && apiScopeRanks[s] >= apiScopeRanks[min]
+}
+
+// APITokenPrefix starts every API token, telling them apart from the JWTs of logins
+const APITokenPrefix = "wct_"
+
+// APIToken lets scripts and integrations call the API as a user without
+// logging in. Tokens of an organization only work on its URLs. Only a hash of
+// the token is stored; it is shown once, when created.
+type APIToken struct {
+	ID             uint     `json:"id" gorm:"primaryKey"`
+	UserID         uint     `json:"user_id" gorm:"not null;index"`                   // User the token acts as
+	OrganizationID uint     `json:"organization_id" gorm:"not null;default:0;index"` // Organization the token is limited to, 0 for none
+	Name           string   `json:"name" gorm:"type:varchar(100);not null"`
+	Scope          APIScope `json:"scope" gorm:"type:varchar(20);not null"`
+	// Prefix is the start of the token, to recognize it by
+	Prefix     string     `json:"prefix" gorm:"type:varchar(16);not null"`
+	TokenHash  string     `json:"-" gorm:"type:varchar(64);not null;uniqueIndex"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"-" gorm:"index"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreateAPITokenRequest creates an API token for the caller, or for one of
+// the organizations they administer
+type CreateAPITokenRequest struct {
+	Name           string   `json:"name" binding:"required,max=100"`
+	Scope          APIScope `json:"scope" binding:"required,oneof=read crawl admin"`
+	OrganizationID uint     `json:"organization_id"`
+	// ExpiresInDays is how long the token works; 0 never expires it
+	ExpiresInDays int `json:"expires_in_days" binding:"min=0,max=3650"`
+}
+
+// CreatedAPIToken is a new API token with its secret, which can't be read again
+type CreatedAPIToken struct {
+	APIToken
+	Token string `json:"token"`
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// Auth incident types
+const (
+	AuthIncidentInvalidTokens = "auth.invalid_tokens"
+)
+
+// AuthIncident is an entry in the security audit log: a client that sent
+// many invalid tokens to the token endpoints in a short time
+type AuthIncident struct {
+	ID        uint   `json:"id" gorm:"primaryKey"`
+	Type      string `json:"type" gorm:"type:varchar(50);not null;index"`
+	IPAddress string `json:"ip_address" gorm:"type:varchar(45);not null;index"`
+	// Path is the route of the request that raised the incident
+	Path string `json:"path" gorm:"type:varchar(255)"`
+	// Failures is the number of invalid tokens within the detection window
+	Failures int `json:"failures"`
+	// BlockedUntil is when the client may use the token endpoints again,
+	// nil if it wasn't blocked
+	BlockedUntil *time.Time `json:"blocked_until,omitempty"`
+	CreatedAt    time.Time  `json:"created_at" gorm:"index"`
+}
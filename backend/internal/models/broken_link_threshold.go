@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// BrokenLinkThreshold flags URLs whose crawls find too many broken links. It
+// applies to one URL, or as the default of a project: the URLs of an
+// organization, or the URLs a user added outside any organization. A URL's
+// own threshold replaces the default of its project.
+type BrokenLinkThreshold struct {
+	ID             uint `json:"id" gorm:"primaryKey"`
+	OrganizationID uint `json:"organization_id" gorm:"not null;default:0;uniqueIndex:idx_broken_link_thresholds_scope"`
+	UserID         uint `json:"user_id" gorm:"not null;default:0;uniqueIndex:idx_broken_link_thresholds_scope"` // Owner of a personal project default, 0 otherwise
+	URLID          uint `json:"url_id" gorm:"not null;default:0;uniqueIndex:idx_broken_link_thresholds_scope"`  // 0 for project defaults
+	// A crawl crosses the threshold with more broken links than MaxBrokenLinks,
+	// or a larger percentage of broken links among the internal and external
+	// links it found than MaxBrokenPercent; nil limits are not checked
+	MaxBrokenLinks   *int      `json:"max_broken_links"`
+	MaxBrokenPercent *float64  `json:"max_broken_percent"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// Exceeded reports whether a crawl with the given link counts crosses the threshold
+func (t *BrokenLinkThreshold) Exceeded(broken, total int) bool {
+	if t.MaxBrokenLinks != nil && broken > *t.MaxBrokenLinks {
+		return true
+	}
+	return t.MaxBrokenPercent != nil && total > 0 && float64(broken)*100/float64(total) > *t.MaxBrokenPercent
+}
+
+// BrokenLinkThresholdRequest sets a threshold; at least one limit is required
+type BrokenLinkThresholdRequest struct {
+	MaxBrokenLinks   *int     `json:"max_broken_links" binding:"omitempty,min=0" example:"5"`
+	MaxBrokenPercent *float64 `json:"max_broken_percent" binding:"omitempty,gt=0,lte=100" example:"10"`
+}
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// CrawlChange describes how a page changed between two consecutive completed crawls
+type CrawlChange struct {
+	CrawlID         uint           `json:"crawl_id"`
+	PreviousCrawlID uint           `json:"previous_crawl_id"`
+	CrawledAt       time.Time      `json:"crawled_at"`
+	Changed         bool           `json:"changed"`
+	ContentChanged  bool           `json:"content_changed"` // Normalized page text differs
+	TitleChanged    bool           `json:"title_changed"`
+	PreviousTitle   string         `json:"previous_title,omitempty"`
+	Title           string         `json:"title,omitempty"`
+	HeadingChanges  map[string]int `json:"heading_changes"` // Count delta per heading level, e.g. {"h2": -1}
+	LinksAdded      int            `json:"links_added"`
+	LinksRemoved    int            `json:"links_removed"`
+}
+
+// CrawlDiff is a structured comparison of two crawls of the same URL
+type CrawlDiff struct {
+	URLID                 uint           `json:"url_id"`
+	FromCrawlID           uint           `json:"from_crawl_id"`
+	ToCrawlID             uint           `json:"to_crawl_id"`
+	ContentChanged        bool           `json:"content_changed"`
+	TitleChanged          bool           `json:"title_changed"`
+	HeadingChanges        map[string]int `json:"heading_changes"` // Count delta per heading level
+	LinksAdded            []string       `json:"links_added"`
+	LinksRemoved          []string       `json:"links_removed"`
+	BrokenLinksFixed      []string       `json:"broken_links_fixed"`      // Broken before, reachable now
+	BrokenLinksIntroduced []string       `json:"broken_links_introduced"` // Broken now, but not before
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// Crawl event types
+const (
+	CrawlEventFeatureDisabled  = "feature_disabled"
+	CrawlEventFetchStarted     = "fetch_started"
+	CrawlEventRetryScheduled   = "retry_scheduled"
+	CrawlEventResponseReceived = "response_received"
+	CrawlEventBodyReceived     = "body_received"
+	CrawlEventParseComplete    = "parse_complete"
+	CrawlEventLinksQueued      = "links_queued"
+	CrawlEventSlowLink         = "slow_link"
+	CrawlEventChecksComplete   = "checks_complete"
+	CrawlEventPagesCrawled     = "pages_crawled"
+	CrawlEventFinished         = "finished"
+)
+
+// CrawlEvent is a step of a crawl, kept to explain its results
+type CrawlEvent struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	CrawlID   uint      `json:"crawl_id" gorm:"not null;index"`
+	Type      string    `json:"type" gorm:"type:varchar(32);not null"`
+	Message   string    `json:"message" gorm:"type:text"`
+	ElapsedMs int64     `json:"elapsed_ms"` // Time since the crawl started
+	CreatedAt time.Time `json:"created_at"`
+}
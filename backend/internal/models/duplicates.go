@@ -0,0 +1,31 @@
+package models
+
+// Ways pages of a crawl can be grouped as duplicates
+const (
+	DuplicateByContent   = "content"   // Identical normalized page text
+	DuplicateByCanonical = "canonical" // Same canonical URL declared
+)
+
+// DuplicatePage is a page belonging to a duplicate cluster
+type DuplicatePage struct {
+	PageURL   string `json:"page_url"`
+	Title     string `json:"title"`
+	Canonical string `json:"canonical"`
+}
+
+// DuplicateCluster is a group of crawled pages that share content or a canonical URL
+type DuplicateCluster struct {
+	Type  string          `json:"type"`
+	Key   string          `json:"key"` // Content hash or canonical URL
+	Pages []DuplicatePage `json:"pages"`
+	// Content clusters only: false when the pages don't all point to one
+	// canonical URL, so search engines have to guess which page to index
+	CanonicalConsistent bool `json:"canonical_consistent"`
+}
+
+// DuplicatesReport lists the duplicate clusters of a deep crawl
+type DuplicatesReport struct {
+	URLID    uint               `json:"url_id"`
+	CrawlID  uint               `json:"crawl_id"`
+	Clusters []DuplicateCluster `json:"clusters"`
+}
@@ -0,0 +1,73 @@
+package models
+
+import "time"
+
+// EnvironmentPair links the URLs of a page on two deployments of a site, such
+// as production and staging, so their crawls can be compared before a release
+type EnvironmentPair struct {
+	ID             uint   `json:"id" gorm:"primaryKey"`
+	OrganizationID uint   `json:"organization_id" gorm:"not null;default:0;index"` // Organization sharing the pair, 0 for none
+	Name           string `json:"name" gorm:"type:varchar(100);not null"`
+	// BaselineURLID is the reference deployment, e.g. production, and
+	// CandidateURLID the one checked against it, e.g. staging
+	BaselineURLID  uint      `json:"baseline_url_id" gorm:"not null;index"`
+	CandidateURLID uint      `json:"candidate_url_id" gorm:"not null;index"`
+	CreatedBy      uint      `json:"created_by" gorm:"not null"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+
+	BaselineURL  *URL `json:"baseline_url,omitempty" gorm:"foreignKey:BaselineURLID"`
+	CandidateURL *URL `json:"candidate_url,omitempty" gorm:"foreignKey:CandidateURLID"`
+}
+
+// CreateEnvironmentPairRequest links two URLs as an environment pair
+type CreateEnvironmentPairRequest struct {
+	Name           string `json:"name" binding:"required,max=100"`
+	BaselineURLID  uint   `json:"baseline_url_id" binding:"required"`
+	CandidateURLID uint   `json:"candidate_url_id" binding:"required,nefield=BaselineURLID"`
+}
+
+// CompareRequest crawls both URLs of an environment pair and compares them
+type CompareRequest struct {
+	PairID uint `json:"pair_id" binding:"required"`
+}
+
+// EnvironmentCrawl is one side of an environment comparison
+type EnvironmentCrawl struct {
+	URLID uint   `json:"url_id"`
+	URL   string `json:"url"`
+	// CrawlID is the latest completed crawl, which is compared; 0 if there is none
+	CrawlID uint `json:"crawl_id"`
+	// Status is the status of the latest crawl, e.g. running while a new
+	// crawl hasn't finished, or error if it failed
+	Status        string        `json:"status"`
+	Title         string        `json:"title"`
+	HeadingCounts HeadingCounts `json:"heading_counts"`
+	BrokenLinks   int           `json:"broken_links"`
+}
+
+// LinkStatusDifference is a link both environments have whose checks got
+// different HTTP statuses
+type LinkStatusDifference struct {
+	Link            string `json:"link"`
+	BaselineStatus  int    `json:"baseline_status"`
+	CandidateStatus int    `json:"candidate_status"`
+}
+
+// EnvironmentComparison is a structural diff of the latest completed crawls
+// of an environment pair. Links to a URL's own host are compared by path, so
+// the same page on both deployments matches; other links by their full URL.
+type EnvironmentComparison struct {
+	PairID    uint             `json:"pair_id"`
+	Baseline  EnvironmentCrawl `json:"baseline"`
+	Candidate EnvironmentCrawl `json:"candidate"`
+	// Complete reports whether both crawls finished before the comparison was made
+	Complete bool `json:"complete"`
+	// Identical reports whether no difference was found
+	Identical            bool                   `json:"identical"`
+	TitleChanged         bool                   `json:"title_changed"`
+	HeadingChanges       map[string]int         `json:"heading_changes"` // Count delta per heading level, from baseline to candidate
+	LinksOnlyInBaseline  []string               `json:"links_only_in_baseline"`
+	LinksOnlyInCandidate []string               `json:"links_only_in_candidate"`
+	StatusChanges        []LinkStatusDifference `json:"status_changes"`
+}
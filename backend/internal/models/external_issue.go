@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Finding types issues can be filed for, next to FindingBrokenLink
+const (
+	FindingSEOCheck = "seo_check"
+)
+
+// ExternalIssue links a crawl finding to the issue filed for it in GitHub or
+// Jira. Like annotations, findings are matched by type and key (the link URL
+// of broken links, the check key of SEO checks), so the link is shown on the
+// finding in later crawls too.
+type ExternalIssue struct {
+	ID          uint   `json:"id" gorm:"primaryKey"`
+	URLID       uint   `json:"url_id" gorm:"not null;uniqueIndex:idx_external_issue"`
+	FindingType string `json:"finding_type" gorm:"type:varchar(50);not null;uniqueIndex:idx_external_issue"`
+	FindingKey  string `json:"finding_key" gorm:"type:varchar(768);not null"`
+	// FindingKeyHash is the SHA-256 of FindingKey, set on save, like the
+	// one of annotations
+	FindingKeyHash string    `json:"-" gorm:"type:char(64);not null;uniqueIndex:idx_external_issue"`
+	Tracker        string    `json:"tracker" gorm:"type:varchar(20);not null"` // github, jira
+	IssueKey       string    `json:"issue_key" gorm:"type:varchar(255);not null"`
+	IssueURL       string    `json:"issue_url" gorm:"type:text;not null"`
+	CreatedBy      uint      `json:"created_by"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// BeforeSave sets the hash of the finding key
+func (i *ExternalIssue) BeforeSave(tx *gorm.DB) error {
+	i.FindingKeyHash = HashFindingKey(i.FindingKey)
+	return nil
+}
+
+// CreateExternalIssueRequest files an issue for a finding of the latest crawl
+type CreateExternalIssueRequest struct {
+	Tracker     string `json:"tracker" binding:"required"`      // github or jira
+	FindingType string `json:"finding_type" binding:"required"` // broken_link or seo_check
+	FindingKey  string `json:"finding_key" binding:"required"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// Selector types of extraction rules
+const (
+	SelectorCSS   = "css"
+	SelectorXPath = "xpath"
+)
+
+// ExtractionRule maps a CSS selector or XPath expression to a named field.
+// Crawls of the URL evaluate its rules on the root page and store the values
+// with the crawl under extracted_data.fields.
+type ExtractionRule struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	URLID     uint      `json:"url_id" gorm:"not null;uniqueIndex:idx_extraction_rule_name"`
+	Name      string    `json:"name" gorm:"type:varchar(100);not null;uniqueIndex:idx_extraction_rule_name"`
+	Type      string    `json:"type" gorm:"type:varchar(10);not null"` // css, xpath
+	Selector  string    `json:"selector" gorm:"type:text;not null"`
+	Attribute string    `json:"attribute" gorm:"type:varchar(100);default:''"` // Read this attribute of the matches instead of their text
+	Multiple  bool      `json:"multiple" gorm:"default:false"`                 // Keep every match as a list instead of the first one
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ExtractionRuleRequest creates or updates an extraction rule
+type ExtractionRuleRequest struct {
+	Name      string `json:"name" binding:"required"`
+	Type      string `json:"type"` // Defaults to css
+	Selector  string `json:"selector" binding:"required"`
+	Attribute string `json:"attribute"`
+	Multiple  bool   `json:"multiple"`
+}
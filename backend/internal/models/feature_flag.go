@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// Names of the feature flags consulted by the services
+const (
+	// FeatureDeepCrawl lets crawls follow internal links beyond the root page
+	FeatureDeepCrawl = "deep_crawl"
+)
+
+// FeatureFlag rolls a feature out to everyone or to chosen users. Flags
+// without a row use their built-in default.
+type FeatureFlag struct {
+	Name       string    `json:"name" gorm:"primaryKey;type:varchar(64)"`
+	Enabled    bool      `json:"enabled" gorm:"default:false"`       // On for every user
+	UserIDs    []uint    `json:"user_ids" gorm:"-"`                  // On for these users while not enabled for everyone
+	UserIDList string    `json:"-" gorm:"column:user_ids;type:text"` // Newline separated
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// FeatureFlagRequest sets who a feature is rolled out to
+type FeatureFlagRequest struct {
+	Enabled bool   `json:"enabled"`
+	UserIDs []uint `json:"user_ids"`
+}
+
+// FeatureFlagState is the effective setting of a feature flag
+type FeatureFlagState struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Default     bool   `json:"default"` // Used while the flag has no stored setting
+	Enabled     bool   `json:"enabled"` // On for every user
+	UserIDs     []uint `json:"user_ids"`
+	// Override is set by the FEATURE_FLAGS environment variable and wins over
+	// the stored setting and the default
+	Override *bool `json:"override,omitempty"`
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// Form types
+const (
+	FormTypeLogin      = "login"
+	FormTypeSearch     = "search"
+	FormTypeNewsletter = "newsletter"
+	FormTypeContact    = "contact"
+	FormTypeCheckout   = "checkout"
+	FormTypeOther      = "other"
+)
+
+// Form is a <form> element found on a crawled page
+type Form struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	URLID      uint      `json:"url_id" gorm:"not null;index"`
+	CrawlID    uint      `json:"crawl_id" gorm:"not null;index"`
+	Type       string    `json:"type" gorm:"type:varchar(20);not null"` // login, search, newsletter, contact, checkout, other
+	Method     string    `json:"method" gorm:"type:varchar(10)"`        // GET or POST
+	Action     string    `json:"action" gorm:"type:varchar(2048)"`      // Resolved against the page; the page itself when not set
+	InputCount int       `json:"input_count"`                           // Fields the user fills in, without hidden inputs and buttons
+	Evidence   string    `json:"evidence" gorm:"type:text"`             // Why the form got its type, e.g. `search input, input named "q"`
+	CreatedAt  time.Time `json:"created_at"`
+}
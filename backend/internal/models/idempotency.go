@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// IdempotencyKey stores the response to a write request sent with an
+// Idempotency-Key header, so a retry of the request gets the same response
+// instead of repeating the work. Keys are scoped to the user and endpoint.
+type IdempotencyKey struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	UserID       uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_idempotency_key"`
+	Key          string    `json:"key" gorm:"type:varchar(255);not null;uniqueIndex:idx_idempotency_key"`
+	Method       string    `json:"method" gorm:"type:varchar(10);not null;uniqueIndex:idx_idempotency_key"`
+	Path         string    `json:"path" gorm:"type:varchar(255);not null;uniqueIndex:idx_idempotency_key"`
+	RequestHash  string    `json:"request_hash" gorm:"type:char(64);not null"` // SHA-256 of the request body
+	StatusCode   int       `json:"status_code"`                                // 0 while the first request is in progress
+	ContentType  string    `json:"content_type" gorm:"type:varchar(255)"`
+	ResponseBody []byte    `json:"-" gorm:"size:16777215"` // mediumblob on MySQL
+	ExpiresAt    time.Time `json:"expires_at" gorm:"not null;index"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// Completed reports whether the response of the first request has been stored
+func (k *IdempotencyKey) Completed() bool {
+	return k.StatusCode != 0
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// Image is an <img> element found on a crawled page
+type Image struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	URLID      uint      `json:"url_id" gorm:"not null;index"`
+	CrawlID    uint      `json:"crawl_id" gorm:"not null;index"`
+	Src        string    `json:"src" gorm:"type:varchar(2048);not null"` // Resolved URL, or data:<media type> for inline images
+	Alt        string    `json:"alt"`
+	MissingAlt bool      `json:"missing_alt"` // No alt attribute at all; alt="" marks a decorative image
+	Width      string    `json:"width"`       // Raw width/height attributes, empty when not set
+	Height     string    `json:"height"`
+	StatusCode int       `json:"status_code"`
+	IsBroken   bool      `json:"is_broken"`
+	CreatedAt  time.Time `json:"created_at"`
+}
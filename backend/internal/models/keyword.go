@@ -0,0 +1,24 @@
+package models
+
+// Keyword statuses
+const (
+	KeywordPresent = "present"
+	KeywordMissing = "missing"
+)
+
+// KeywordCheck reports whether a watched phrase appears in the visible text of
+// a crawled page, compared with the previous crawl that checked it
+type KeywordCheck struct {
+	Keyword     string `json:"keyword"`
+	Status      string `json:"status"` // present, missing
+	Occurrences int    `json:"occurrences"`
+	// Changed is set when the status or the number of occurrences differs
+	// from the previous crawl
+	Changed             bool `json:"changed"`
+	PreviousOccurrences *int `json:"previous_occurrences,omitempty"` // nil when the keyword wasn't checked before
+}
+
+// Disappeared reports whether the keyword was found by the previous crawl but not this one
+func (k KeywordCheck) Disappeared() bool {
+	return k.Status == KeywordMissing && k.PreviousOccurrences != nil && *k.PreviousOccurrences > 0
+}
@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// PageLink is an internal link from one page of a deep crawl to another.
+// Both ends are normalized page URLs; the target may not have been crawled.
+type PageLink struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	URLID     uint      `json:"url_id" gorm:"not null;index"`
+	CrawlID   uint      `json:"crawl_id" gorm:"not null;index"`
+	SourceURL string    `json:"source_url" gorm:"type:varchar(2048);not null"`
+	TargetURL string    `json:"target_url" gorm:"type:varchar(2048);not null"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// GraphNode is a page in the link graph of a crawl
+type GraphNode struct {
+	URL        string `json:"url"`
+	Title      string `json:"title,omitempty"`
+	Depth      int    `json:"depth"`
+	StatusCode int    `json:"status_code,omitempty"`
+	Crawled    bool   `json:"crawled"` // False for pages linked to but beyond the crawl limits
+	Inbound    int    `json:"inbound"`
+	Outbound   int    `json:"outbound"`
+	Orphan     bool   `json:"orphan"` // Crawled page, other than the root, that no crawled page links to
+}
+
+// GraphEdge is a link between two nodes of the link graph
+type GraphEdge struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+}
+
+// LinkGraph is the internal linking structure of a deep crawl
+type LinkGraph struct {
+	URLID   uint        `json:"url_id"`
+	CrawlID uint        `json:"crawl_id"`
+	Nodes   []GraphNode `json:"nodes"`
+	Edges   []GraphEdge `json:"edges"`
+	Orphans []string    `json:"orphans"`
+}
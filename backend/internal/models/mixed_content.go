@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// Resource types checked for mixed content
+const (
+	MixedContentScript     = "script"
+	MixedContentStylesheet = "stylesheet"
+	MixedContentIframe     = "iframe"
+	MixedContentImage      = "image"
+)
+
+// MixedContentIssue is an http:// resource referenced by an https page
+type MixedContentIssue struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	URLID        uint      `json:"url_id" gorm:"not null;index"`
+	CrawlID      uint      `json:"crawl_id" gorm:"not null;index"`
+	ResourceType string    `json:"resource_type" gorm:"type:varchar(20);not null"`
+	ResourceURL  string    `json:"resource_url" gorm:"type:text;not null"`
+	Blocked      bool      `json:"blocked"` // Active content that browsers refuse to load; passive content only triggers a warning
+	Element      string    `json:"element" gorm:"type:text"`
+	CreatedAt    time.Time `json:"created_at"`
+	// Annotation is set when the resource was acknowledged or marked a false positive
+	Annotation *FindingAnnotation `json:"annotation,omitempty" gorm:"-"`
+}
+
+// MixedContentReport lists the mixed content issues of a crawl
+type MixedContentReport struct {
+	URLID     uint                `json:"url_id"`
+	CrawlID   uint                `json:"crawl_id"`
+	Counts    map[string]int      `json:"counts"`    // Issues per resource type, without annotated ones
+	Annotated int                 `json:"annotated"` // Issues that were acknowledged or marked false positives
+	Issues    []MixedContentIssue `json:"issues"`
+}
@@ -1,9 +1,10 @@
 package models
 
 import (
+	"strings"
 	"time"
 
-	"github.com/dgrijalva/jwt-go"
+	"github.com/golang-jwt/jwt/v5"
 	"gorm.io/gorm"
 )
 
@@ -25,11 +26,29 @@ type User struct {
 // URL represents a website URL to be crawled
 type URL struct {
 	ID          uint      `json:"id" gorm:"primaryKey"`
-	URL         string    `json:"url" gorm:"not null;unique"`
+	URL         string    `json:"url" gorm:"not null;uniqueIndex:idx_urls_org_url"`
 	Title       string    `json:"title"`
 	HTMLVersion string    `json:"html_version"`
-	Status      string    `json:"status" gorm:"default:'pending'"` // pending, running, completed, error
+	Status      string    `json:"status" gorm:"default:'pending'"` // pending, running, completed, skipped, error
 	HasLoginForm bool     `json:"has_login_form" gorm:"default:false"`
+	LoginFormOverride *bool `json:"login_form_override"` // Manual correction of the login form detection, nil to use the crawler's result
+	MaxDepth    int       `json:"max_depth" gorm:"default:0"` // Link depth followed from the root page, 0 crawls the root page only
+	MaxPages    int       `json:"max_pages" gorm:"default:0"` // Page limit for deep crawls, 0 uses the crawler default
+	IncludePatterns []string `json:"include_patterns" gorm:"-"` // Deep crawls only follow pages matching one of these, empty follows all
+	ExcludePatterns []string `json:"exclude_patterns" gorm:"-"` // Deep crawls skip pages matching any of these
+	IncludePatternList string `json:"-" gorm:"column:include_patterns;type:text"` // Newline separated
+	ExcludePatternList string `json:"-" gorm:"column:exclude_patterns;type:text"` // Newline separated
+	AllowSubdomains bool  `json:"allow_subdomains"` // Deep crawls also follow subdomains of the host
+	StripQuery  bool      `json:"strip_query"` // Drop query strings from discovered links
+	MaxQueryParams int    `json:"max_query_params"` // Skip links with more query parameters, 0 for no limit
+	CheckInternalLinks bool `json:"check_internal_links"` // Request internal links of the root page like external ones instead of assuming they work
+	DisabledExtractors []string `json:"disabled_extractors" gorm:"-"` // Extractors skipped on this URL's pages, e.g. "images"
+	DisabledExtractorList string `json:"-" gorm:"column:disabled_extractors;type:text"` // Newline separated
+	Keywords    []string  `json:"keywords" gorm:"-"` // Phrases every crawl checks the page for
+	KeywordList string    `json:"-" gorm:"column:keywords;type:text"` // Newline separated
+	NeedsAttention bool   `json:"needs_attention" gorm:"default:false;index"` // The latest completed crawl crossed the broken link threshold
+	UserID      *uint     `json:"user_id,omitempty" gorm:"index"` // User who first added the URL
+	OrganizationID uint   `json:"organization_id" gorm:"not null;default:0;uniqueIndex:idx_urls_org_url"` // Organization sharing the URL, 0 for none
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
@@ -37,45 +56,126 @@ type URL struct {
 	// Relationships
 	Crawls []Crawl `json:"crawls,omitempty" gorm:"foreignKey:URLID"`
 	Links  []Link  `json:"links,omitempty" gorm:"foreignKey:URLID"`
+	ExtractionRules []ExtractionRule `json:"extraction_rules,omitempty" gorm:"foreignKey:URLID"`
+}
+
+// BeforeSave encodes the crawl scope patterns, disabled extractors and keywords into their columns
+func (u *URL) BeforeSave(tx *gorm.DB) error {
+	u.IncludePatternList = strings.Join(u.IncludePatterns, "\n")
+	u.ExcludePatternList = strings.Join(u.ExcludePatterns, "\n")
+	u.DisabledExtractorList = strings.Join(u.DisabledExtractors, "\n")
+	u.KeywordList = strings.Join(u.Keywords, "\n")
+	return nil
+}
+
+// AfterFind decodes the crawl scope, extractor and keyword columns into lists
+func (u *URL) AfterFind(tx *gorm.DB) error {
+	u.IncludePatterns = splitPatternList(u.IncludePatternList)
+	u.ExcludePatterns = splitPatternList(u.ExcludePatternList)
+	u.DisabledExtractors = splitPatternList(u.DisabledExtractorList)
+	u.Keywords = splitPatternList(u.KeywordList)
+	return nil
+}
+
+func splitPatternList(list string) []string {
+	if list == "" {
+		return []string{}
+	}
+	return strings.Split(list, "\n")
 }
 
 // Crawl represents a crawling session for a URL
 type Crawl struct {
 	ID            uint       `json:"id" gorm:"primaryKey"`
 	URLID         uint       `json:"url_id" gorm:"not null"`
-	Status        string     `json:"status" gorm:"default:'queued'"` // queued, running, completed, error
+	Status        string     `json:"status" gorm:"default:'queued'"` // queued, running, completed, unchanged, skipped, error; unchanged crawls keep the results of the latest completed one
 	StartedAt     *time.Time `json:"started_at"`
 	CompletedAt   *time.Time `json:"completed_at"`
+	ReprocessedAt *time.Time `json:"reprocessed_at,omitempty"` // When the results were last extracted again from the snapshot
 	ErrorMessage  string     `json:"error_message"`
+	SkipReason    string     `json:"skip_reason,omitempty"` // Why the page was not parsed, e.g. a PDF or an oversized response
+	Attempts      int        `json:"attempts"` // Requests made for the seed page, including retries of transient failures
 	InternalLinks int        `json:"internal_links" gorm:"default:0"`
 	ExternalLinks int        `json:"external_links" gorm:"default:0"`
 	BrokenLinks   int        `json:"broken_links" gorm:"default:0"`
 	HeadingCounts string     `json:"heading_counts"` // JSON string: {"h1":1,"h2":3,...}
+	Title         string     `json:"title"`
+	ContentHash   string     `json:"content_hash" gorm:"type:char(64)"` // SHA-256 of the normalized page text
+	CrawlLog      string     `json:"crawl_log,omitempty" gorm:"type:text"` // Newline separated notes, e.g. applied rate limits
+	SnapshotKey   string     `json:"-" gorm:"type:varchar(255)"` // Key of the stored HTML of the seed page, empty if none was kept
+	ETag          string     `json:"etag,omitempty" gorm:"column:etag;type:varchar(255)"`         // Validators of the seed page, sent on the next crawl to skip an unchanged page
+	LastModified  string     `json:"last_modified,omitempty" gorm:"type:varchar(64)"`
+	LoginFormDetected bool   `json:"login_form_detected" gorm:"default:false"`
+	LoginFormEvidence string `json:"login_form_evidence" gorm:"type:text"` // JSON array: ["password input","submit text \"Sign in\""]
+	LoginFormConfidence float64 `json:"login_form_confidence" gorm:"default:0"` // 0-1, how sure the detection is that the page has a login form
+	PagesCrawled  int        `json:"pages_crawled" gorm:"default:0"`
+	SEOScore      int        `json:"seo_score" gorm:"default:0"`
+	SEOChecks     string     `json:"seo_checks" gorm:"type:text"` // JSON array of SEOCheck
+	SecurityScore   int      `json:"security_score" gorm:"default:0"`
+	SecurityHeaders string   `json:"security_headers" gorm:"type:text"` // JSON object of the security headers the page was served with
+	SecurityChecks  string   `json:"security_checks" gorm:"type:text"`  // JSON array of SecurityCheck
+	ExtractedData   string   `json:"extracted_data,omitempty" gorm:"type:text"` // JSON object of custom extractor results by extractor name
+	KeywordChecks   string   `json:"keyword_checks,omitempty" gorm:"type:text"` // JSON array of KeywordCheck
+	WordCount        int      `json:"word_count" gorm:"default:0"` // Words of the root page's visible text
+	ReadabilityScore *float64 `json:"readability_score"`           // Flesch reading ease of the root page, nil without text
+	ResponseMetrics          // Performance of the root page
 	CreatedAt     time.Time  `json:"created_at"`
 	UpdatedAt     time.Time  `json:"updated_at"`
 
 	// Relationships
-	URL   URL    `json:"url,omitempty" gorm:"foreignKey:URLID"`
-	Links []Link `json:"links,omitempty" gorm:"foreignKey:CrawlID"`
+	URL      URL       `json:"url,omitempty" gorm:"foreignKey:URLID"`
+	Links    []Link    `json:"links,omitempty" gorm:"foreignKey:CrawlID"`
+	PageMeta *PageMeta `json:"page_meta,omitempty" gorm:"foreignKey:CrawlID"`
+	Pages    []Page    `json:"pages,omitempty" gorm:"foreignKey:CrawlID"`
 }
 
+// Page regions a link can be found in
+const (
+	LinkContextNav     = "nav"
+	LinkContextFooter  = "footer"
+	LinkContextContent = "content"
+)
+
 // Link represents a link found during crawling
 type Link struct {
 	ID          uint   `json:"id" gorm:"primaryKey"`
 	URLID       uint   `json:"url_id" gorm:"not null"`
 	CrawlID     uint   `json:"crawl_id" gorm:"not null"`
 	LinkURL     string `json:"link_url" gorm:"not null"`
+	FoundOnURL  string `json:"found_on_url" gorm:"type:varchar(2048)"` // Page the link was found on: the seed page, or a deeper page for broken links found during deep crawls
 	LinkText    string `json:"link_text"`
-	LinkType    string `json:"link_type"` // internal, external
+	LinkType    string `json:"link_type"` // internal, external, mailto, tel, anchor, or other; only internal and external links are checked
 	StatusCode  int    `json:"status_code"`
-	IsAccessible bool  `json:"is_accessible" gorm:"default:true"`
+	IsAccessible bool  `json:"is_accessible"` // No gorm default, it would turn false into true on insert
+	Rel         string `json:"rel"`                // Lowercased rel attribute, e.g. "nofollow noopener"
+	Target      string `json:"target"`             // target attribute, e.g. _blank
+	Nofollow    bool   `json:"nofollow"`
+	Sponsored   bool   `json:"sponsored"`
+	UGC         bool   `json:"ugc"`
+	Context     string `json:"context" gorm:"type:varchar(20)"` // nav, footer, or content
+	Occurrences int    `json:"occurrences" gorm:"default:1"`     // Times the page links to the URL; repeated links are stored once
 	CreatedAt   time.Time `json:"created_at"`
 
+	// Set when a broken link has been acknowledged, marked a false positive, accepted or ignored
+	Annotation *FindingAnnotation `json:"annotation,omitempty" gorm:"-"`
+	// Set when an issue has been filed for the broken link
+	ExternalIssue *ExternalIssue `json:"external_issue,omitempty" gorm:"-"`
+
 	// Relationships
 	URL   URL   `json:"url,omitempty" gorm:"foreignKey:URLID"`
 	Crawl Crawl `json:"crawl,omitempty" gorm:"foreignKey:CrawlID"`
 }
 
+// LinkRecheckResult reports a recheck of the broken links of a crawl
+type LinkRecheckResult struct {
+	CrawlID     uint   `json:"crawl_id"`
+	Checked     int    `json:"checked"`      // Broken links requested again
+	Fixed       int    `json:"fixed"`        // Links that are accessible now
+	StillBroken int    `json:"still_broken"` // Links that are still broken
+	BrokenLinks int    `json:"broken_links"` // Broken links of the crawl after the recheck
+	Links       []Link `json:"links"`        // The rechecked links with their new status
+}
+
 // HeadingCounts represents the count of heading tags
 type HeadingCounts struct {
 	H1 int `json:"h1"`
@@ -100,16 +200,48 @@ type CrawlStatusResponse struct {
 	ExternalLinks int            `json:"external_links"`
 	BrokenLinks   int            `json:"broken_links"`
 	HeadingCounts *HeadingCounts `json:"heading_counts"`
+	LoginForm     *LoginFormResult `json:"login_form,omitempty"`
+	Performance      *ResponseMetrics    `json:"performance,omitempty"`
+	PerformanceTrend []PerformanceSample `json:"performance_trend,omitempty"` // Completed crawls, oldest first
+	Fields        map[string]interface{} `json:"fields,omitempty"` // Values of the URL's extraction rules
+	Keywords      []KeywordCheck `json:"keywords,omitempty"` // Presence of the URL's keywords
+	WordCount        int      `json:"word_count"`
+	ReadabilityScore *float64 `json:"readability_score"` // Flesch reading ease, higher is easier to read
 	StartedAt     *time.Time     `json:"started_at"`
 	CompletedAt   *time.Time     `json:"completed_at"`
 	ErrorMessage  string         `json:"error_message,omitempty"`
 }
 
+// LoginFormResult explains the login form classification of a crawled page
+type LoginFormResult struct {
+	Detected  bool     `json:"detected"`  // Result of the crawler's heuristic
+	Evidence  []string `json:"evidence"`  // Signals that contributed to the detection
+	Confidence float64 `json:"confidence"` // 0-1, from the strength of the evidence
+	Override  *bool    `json:"override"`  // Manual correction, if any
+	Effective bool     `json:"effective"` // Value reported for the URL
+}
+
+// LoginFormOverrideRequest sets or clears the manual login form classification
+type LoginFormOverrideRequest struct {
+	HasLoginForm *bool `json:"has_login_form"`
+}
+
 // BulkRequest represents bulk action requests
 type BulkRequest struct {
 	IDs []uint `json:"ids" binding:"required"`
 }
 
+// StartCrawlRequest is the optional body of a single crawl request
+type StartCrawlRequest struct {
+	Priority CrawlPriority `json:"priority" binding:"omitempty,oneof=high low"` // Defaults to high
+}
+
+// BulkRerunRequest reruns the crawls of several URLs
+type BulkRerunRequest struct {
+	IDs      []uint        `json:"ids" binding:"required"`
+	Priority CrawlPriority `json:"priority" binding:"omitempty,oneof=high low"` // Defaults to low
+}
+
 // Authentication-related structs
 type LoginRequest struct {
 	Username string `json:"username" binding:"required"`
@@ -125,12 +257,15 @@ type RegisterRequest struct {
 }
 
 type AuthResponse struct {
-	Token string `json:"token"`
-	User  *User  `json:"user"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+	User      *User     `json:"user"`
 }
 
+// RefreshTokenRequest carries the token to refresh; clients authenticated
+// by cookie send an empty body and refresh the token of their cookie
 type RefreshTokenRequest struct {
-	Token string `json:"token" binding:"required"`
+	Token string `json:"token"`
 }
 
 // JWT Claims structure
@@ -138,5 +273,8 @@ type JWTClaims struct {
 	UserID   uint   `json:"user_id"`
 	Username string `json:"username"`
 	IsAdmin  bool   `json:"is_admin"`
-	jwt.StandardClaims
+	// SessionID is the session the token belongs to; tokens without one
+	// are rejected
+	SessionID uint `json:"sid,omitempty"`
+	jwt.RegisteredClaims
 } 
\ No newline at end of file
@@ -5,7 +5,7 @@ import (
 	"testing"
 	"time"
 
-	"github.com/dgrijalva/jwt-go"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -283,8 +283,8 @@ func TestJWTClaims(t *testing.T) {
 			UserID:   1,
 			Username: "testuser",
 			IsAdmin:  false,
-			StandardClaims: jwt.StandardClaims{
-				ExpiresAt: time.Now().Add(time.Hour * 24).Unix(),
+			RegisteredClaims: jwt.RegisteredClaims{
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour * 24)),
 				Issuer:    "web-crawler",
 			},
 		}
@@ -293,7 +293,7 @@ func TestJWTClaims(t *testing.T) {
 		assert.Equal(t, "testuser", claims.Username)
 		assert.False(t, claims.IsAdmin)
 		assert.Equal(t, "web-crawler", claims.Issuer)
-		assert.True(t, claims.ExpiresAt > time.Now().Unix())
+		assert.True(t, claims.ExpiresAt.After(time.Now()))
 	})
 }
 
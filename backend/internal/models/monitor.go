@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+// Monitor checks that a URL is up every few minutes. Checks only look at the
+// status code, response time and TLS certificate; the page isn't parsed.
+type Monitor struct {
+	ID              uint       `json:"id" gorm:"primaryKey"`
+	URLID           uint       `json:"url_id" gorm:"not null;uniqueIndex"`
+	IntervalMinutes int        `json:"interval_minutes" gorm:"not null"`
+	Enabled         bool       `json:"enabled"`
+	NextCheckAt     *time.Time `json:"next_check_at" gorm:"index"`
+	LastCheckAt     *time.Time `json:"last_check_at"`
+	LastUp          *bool      `json:"last_up"` // Result of the latest check, nil before the first one
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// MonitorRequest creates or replaces a URL's monitor
+type MonitorRequest struct {
+	IntervalMinutes int   `json:"interval_minutes" binding:"required"`
+	Enabled         *bool `json:"enabled"`
+}
+
+// MonitorCheck is one entry of a monitor's check history
+type MonitorCheck struct {
+	ID           uint       `json:"id" gorm:"primaryKey"`
+	URLID        uint       `json:"url_id" gorm:"not null;index:idx_monitor_checks_url_checked"`
+	CheckedAt    time.Time  `json:"checked_at" gorm:"not null;index:idx_monitor_checks_url_checked"`
+	Up           bool       `json:"up"`
+	StatusCode   int        `json:"status_code"`    // 0 when no response was received
+	ResponseMs   int64      `json:"response_ms"`    // Time until the response headers arrived
+	TLSValid     *bool      `json:"tls_valid"`      // nil for plain HTTP
+	TLSExpiresAt *time.Time `json:"tls_expires_at"` // Expiry of the server certificate
+	Error        string     `json:"error,omitempty" gorm:"type:varchar(255)"`
+}
+
+// Availability summarizes the checks of a URL's monitor over a period
+type Availability struct {
+	URLID         uint          `json:"url_id"`
+	Period        string        `json:"period"` // e.g. 24h, 7d
+	Since         time.Time     `json:"since"`
+	Checks        int64         `json:"checks"`
+	UpChecks      int64         `json:"up_checks"`
+	UptimePercent *float64      `json:"uptime_percent"`  // nil without checks
+	AvgResponseMs *float64      `json:"avg_response_ms"` // Average of the successful checks
+	LastCheck     *MonitorCheck `json:"last_check"`
+	TLSExpiresAt  *time.Time    `json:"tls_expires_at"` // From the latest check over HTTPS
+}
@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// Onboarding step keys, in the order users are guided through them
+const (
+	OnboardingStepAccountCreated      = "account_created"
+	OnboardingStepDemoExplored        = "demo_explored"
+	OnboardingStepFirstURLAdded       = "first_url_added"
+	OnboardingStepFirstCrawlCompleted = "first_crawl_completed"
+	OnboardingStepReportViewed        = "report_viewed"
+)
+
+// OnboardingState stores a user's progress through onboarding
+type OnboardingState struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	UserID         uint      `json:"user_id" gorm:"not null;uniqueIndex"`
+	CompletedSteps string    `json:"-" gorm:"type:text"` // JSON object: {"demo_explored":"2024-01-01T00:00:00Z",...}
+	DemoURLID      *uint     `json:"demo_url_id"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// OnboardingStep is a single step in the onboarding response
+type OnboardingStep struct {
+	Key         string     `json:"key"`
+	Title       string     `json:"title"`
+	Completed   bool       `json:"completed"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// OnboardingStatus represents the onboarding state returned to clients
+type OnboardingStatus struct {
+	Steps       []OnboardingStep `json:"steps"`
+	CurrentStep string           `json:"current_step,omitempty"`
+	Completed   bool             `json:"completed"`
+	Demo        *URL             `json:"demo,omitempty"`
+}
@@ -0,0 +1,77 @@
+package models
+
+import "time"
+
+// OrgRole is what a member may do within an organization
+type OrgRole string
+
+const (
+	// OrgRoleOwner manages the organization, including its owners
+	OrgRoleOwner OrgRole = "owner"
+	// OrgRoleAdmin manages members and the URL inventory
+	OrgRoleAdmin OrgRole = "admin"
+	// OrgRoleMember adds, crawls and edits URLs
+	OrgRoleMember OrgRole = "member"
+	// OrgRoleViewer only reads
+	OrgRoleViewer OrgRole = "viewer"
+)
+
+var orgRoleRanks = map[OrgRole]int{
+	OrgRoleViewer: 1,
+	OrgRoleMember: 2,
+	OrgRoleAdmin:  3,
+	OrgRoleOwner:  4,
+}
+
+// Valid reports whether r is a known role
+func (r OrgRole) Valid() bool {
+	return orgRoleRanks[r] > 0
+}
+
+// AtLeast reports whether r grants everything min does
+func (r OrgRole) AtLeast(min OrgRole) bool {
+	return r.Valid() && orgRoleRanks[r] >= orgRoleRanks[min]
+}
+
+// Organization is a team whose members share a URL inventory
+type Organization struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Name      string    `json:"name" gorm:"type:varchar(191);not null"`
+	CreatedBy uint      `json:"created_by" gorm:"not null"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Membership puts a user in an organization with a role
+type Membership struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	OrganizationID uint      `json:"organization_id" gorm:"not null;uniqueIndex:idx_membership"`
+	UserID         uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_membership;index"`
+	Role           OrgRole   `json:"role" gorm:"type:varchar(20);not null"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+
+	User *User `json:"user,omitempty" gorm:"foreignKey:UserID"`
+}
+
+// OrganizationWithRole is an organization as seen by one of its members
+type OrganizationWithRole struct {
+	Organization
+	Role OrgRole `json:"role"`
+}
+
+// CreateOrganizationRequest creates an organization owned by the caller
+type CreateOrganizationRequest struct {
+	Name string `json:"name" binding:"required,max=191"`
+}
+
+// AddMemberRequest adds a user to an organization by username
+type AddMemberRequest struct {
+	Username string  `json:"username" binding:"required"`
+	Role     OrgRole `json:"role" binding:"required,oneof=owner admin member viewer"`
+}
+
+// UpdateMemberRequest changes the role of a member
+type UpdateMemberRequest struct {
+	Role OrgRole `json:"role" binding:"required,oneof=owner admin member viewer"`
+}
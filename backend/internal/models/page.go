@@ -0,0 +1,65 @@
+package models
+
+import "time"
+
+// Page is a single page visited during a crawl. Single-page crawls store the
+// root page only; deep crawls store every page reached within the URL's limits.
+type Page struct {
+	ID            uint      `json:"id" gorm:"primaryKey"`
+	URLID         uint      `json:"url_id" gorm:"not null;index"`
+	CrawlID       uint      `json:"crawl_id" gorm:"not null;index"`
+	PageURL       string    `json:"page_url" gorm:"type:varchar(2048);not null"`
+	Depth         int       `json:"depth"`
+	StatusCode    int       `json:"status_code"`
+	Title         string    `json:"title"`
+	HeadingCounts string    `json:"heading_counts"` // JSON string: {"h1":1,"h2":3,...}
+	HeadingDepth  int       `json:"heading_depth"`  // Deepest heading level used on the page, 0 if none
+	ErrorMessage  string    `json:"error_message,omitempty"`
+	Canonical     string    `json:"canonical" gorm:"type:varchar(2048)"`
+	ContentHash   string    `json:"content_hash" gorm:"type:char(64);index"` // SHA-256 of the normalized page text
+	CreatedAt     time.Time `json:"created_at"`
+
+	// How the page was served
+	ResponseMetrics
+}
+
+// CrawlSettingsRequest updates how far a URL is crawled, which pages deep
+// crawls may visit and what is extracted from them
+type CrawlSettingsRequest struct {
+	MaxDepth        *int      `json:"max_depth"`
+	MaxPages        *int      `json:"max_pages"`
+	IncludePatterns *[]string `json:"include_patterns"`
+	ExcludePatterns *[]string `json:"exclude_patterns"`
+	AllowSubdomains *bool     `json:"allow_subdomains"`
+	StripQuery      *bool     `json:"strip_query"`
+	MaxQueryParams  *int      `json:"max_query_params"`
+	// CheckInternalLinks requests internal links instead of assuming they work
+	CheckInternalLinks *bool `json:"check_internal_links"`
+	// DisabledExtractors replaces the extractors skipped on the URL's pages
+	DisabledExtractors *[]string `json:"disabled_extractors"`
+	// Keywords replaces the phrases crawls check the page for
+	Keywords *[]string `json:"keywords"`
+}
+
+// PageStructure summarizes the headings of one crawled page
+type PageStructure struct {
+	PageURL       string        `json:"page_url"`
+	Depth         int           `json:"depth"`
+	Title         string        `json:"title"`
+	HeadingCounts HeadingCounts `json:"heading_counts"`
+	HeadingDepth  int           `json:"heading_depth"`
+	MissingH1     bool          `json:"missing_h1"`
+	MultipleH1    bool          `json:"multiple_h1"`
+}
+
+// StructureReport aggregates heading structure across all pages of a crawl
+type StructureReport struct {
+	URLID               uint            `json:"url_id"`
+	CrawlID             uint            `json:"crawl_id"`
+	PagesCrawled        int             `json:"pages_crawled"`
+	PagesMissingH1      int             `json:"pages_missing_h1"`
+	PagesWithMultipleH1 int             `json:"pages_with_multiple_h1"`
+	AverageHeadingDepth float64         `json:"average_heading_depth"`
+	HeadingTotals       HeadingCounts   `json:"heading_totals"`
+	Pages               []PageStructure `json:"pages"`
+}
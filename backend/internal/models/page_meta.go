@@ -0,0 +1,97 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PageMeta holds the meta, canonical, viewport and social tags found on a crawled page
+type PageMeta struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	URLID       uint      `json:"url_id" gorm:"not null;index"`
+	CrawlID     uint      `json:"crawl_id" gorm:"not null;uniqueIndex"`
+	Description string    `json:"description" gorm:"type:text"`
+	Robots      string    `json:"robots"`
+	Canonical   string    `json:"canonical" gorm:"type:varchar(2048)"`
+	Viewport    string    `json:"viewport"`                                         // Content of the viewport meta tag
+	AMPURL      string    `json:"amp_url" gorm:"column:amp_url;type:varchar(2048)"` // AMP variant from <link rel="amphtml">
+	CreatedAt   time.Time `json:"created_at"`
+
+	// Open Graph (og:*) and Twitter Card (twitter:*) tags keyed by property name
+	OpenGraph   map[string]string `json:"open_graph" gorm:"-"`
+	TwitterCard map[string]string `json:"twitter_card" gorm:"-"`
+
+	// Favicons, apple-touch icons and web app manifests linked from the page
+	Icons []PageIcon `json:"icons" gorm:"-"`
+
+	// JSON encoded columns backing the maps and list above
+	OpenGraphJSON   string `json:"-" gorm:"column:open_graph;type:text"`
+	TwitterCardJSON string `json:"-" gorm:"column:twitter_card;type:text"`
+	IconsJSON       string `json:"-" gorm:"column:icons;type:text"`
+}
+
+// Icon kinds
+const (
+	IconFavicon        = "favicon"
+	IconAppleTouchIcon = "apple_touch_icon"
+	IconManifest       = "manifest"
+)
+
+// PageIcon is a favicon, apple-touch icon or web app manifest of a page
+type PageIcon struct {
+	Kind         string `json:"kind"` // favicon, apple_touch_icon, manifest
+	URL          string `json:"url"`
+	Sizes        string `json:"sizes,omitempty"`
+	Type         string `json:"type,omitempty"`
+	Implicit     bool   `json:"implicit,omitempty"` // /favicon.ico, checked because the page links no favicon
+	StatusCode   int    `json:"status_code"`
+	IsAccessible bool   `json:"is_accessible"`
+}
+
+// BeforeSave encodes the tag maps and icons into their columns
+func (m *PageMeta) BeforeSave(tx *gorm.DB) error {
+	openGraph, err := json.Marshal(m.OpenGraph)
+	if err != nil {
+		return err
+	}
+	twitterCard, err := json.Marshal(m.TwitterCard)
+	if err != nil {
+		return err
+	}
+
+	icons, err := json.Marshal(m.Icons)
+	if err != nil {
+		return err
+	}
+
+	m.OpenGraphJSON = string(openGraph)
+	m.TwitterCardJSON = string(twitterCard)
+	m.IconsJSON = string(icons)
+	return nil
+}
+
+// AfterFind decodes the tag columns into maps and the icon column into a list
+func (m *PageMeta) AfterFind(tx *gorm.DB) error {
+	m.OpenGraph = map[string]string{}
+	m.TwitterCard = map[string]string{}
+	m.Icons = []PageIcon{}
+
+	if m.OpenGraphJSON != "" {
+		if err := json.Unmarshal([]byte(m.OpenGraphJSON), &m.OpenGraph); err != nil {
+			return err
+		}
+	}
+	if m.TwitterCardJSON != "" {
+		if err := json.Unmarshal([]byte(m.TwitterCardJSON), &m.TwitterCard); err != nil {
+			return err
+		}
+	}
+	if m.IconsJSON != "" && m.IconsJSON != "null" {
+		if err := json.Unmarshal([]byte(m.IconsJSON), &m.Icons); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// ResponseMetrics describes how a page was served. It is embedded in Crawl
+// for the root page and in Page for every page of a deep crawl.
+type ResponseMetrics struct {
+	TTFBMs          int64  `json:"ttfb_ms"`          // Time to first byte of the response
+	DownloadMs      int64  `json:"download_ms"`      // Time until the whole body was read
+	ResponseBytes   int64  `json:"response_bytes"`   // Size of the decoded body
+	ContentEncoding string `json:"content_encoding"` // e.g. gzip, br, empty if uncompressed
+	Protocol        string `json:"protocol"`         // e.g. HTTP/1.1, HTTP/2.0
+	// HTTP/3 runs over QUIC, which the crawler doesn't speak; servers offer it through Alt-Svc
+	HTTP3Advertised  bool `json:"http3_advertised" gorm:"column:http3_advertised"`
+	KeepAlive        bool `json:"keep_alive"`        // The server left the connection open for further requests
+	ConnectionReused bool `json:"connection_reused"` // The request went over a connection opened for an earlier one
+}
+
+// PerformanceSample is the root page performance of one past crawl
+type PerformanceSample struct {
+	CrawlID   uint      `json:"crawl_id"`
+	CreatedAt time.Time `json:"created_at"`
+	ResponseMetrics
+}
@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// CrawlPriority orders the crawls waiting in the crawl queue
+type CrawlPriority string
+
+const (
+	// CrawlPriorityHigh is for crawls a user is waiting for, such as a single rerun
+	CrawlPriorityHigh CrawlPriority = "high"
+	// CrawlPriorityLow is for background work such as bulk reruns and scheduled crawls
+	CrawlPriorityLow CrawlPriority = "low"
+)
+
+// CrawlTask is a crawl waiting in, running from or dead in the crawl queue
+type CrawlTask struct {
+	ID         string        `json:"id"`
+	URLID      uint          `json:"url_id"`
+	Priority   CrawlPriority `json:"priority"`
+	Attempts   int           `json:"attempts"` // Failed runs so far
+	EnqueuedAt time.Time     `json:"enqueued_at"`
+	LastError  string        `json:"last_error,omitempty"`
+	FailedAt   *time.Time    `json:"failed_at,omitempty"` // Time of the last failed run
+	RetryAt    *time.Time    `json:"retry_at,omitempty"`  // When a scheduled task is queued again
+}
+
+// CrawlQueueStats summarizes the crawl queue for inspection
+type CrawlQueueStats struct {
+	Pending        int64       `json:"pending"`         // Waiting for a worker
+	PendingHigh    int64       `json:"pending_high"`    // Pending with high priority
+	PendingLow     int64       `json:"pending_low"`     // Pending with low priority
+	Scheduled      int64       `json:"scheduled"`       // Waiting to be retried
+	Active         int64       `json:"active"`          // Running on a worker
+	Dead           int64       `json:"dead"`            // Out of retries
+	Workers        int64       `json:"workers"`         // Workers of all running worker processes
+	Utilization    float64     `json:"utilization"`     // Share of workers running a crawl, from 0 to 1
+	PendingTasks   []CrawlTask `json:"pending_tasks"`   // Oldest first, high priority first
+	ScheduledTasks []CrawlTask `json:"scheduled_tasks"` // Next retry first
+	DeadTasks      []CrawlTask `json:"dead_tasks"`
+}
@@ -0,0 +1,56 @@
+package models
+
+import "time"
+
+// DefaultPlan names the limits of users without their own quota
+const DefaultPlan = "default"
+
+// QuotaLimits are the limits of a plan; zero means no limit
+type QuotaLimits struct {
+	MaxURLs         int `json:"max_urls"`           // URLs added by the user
+	MaxCrawlsPerDay int `json:"max_crawls_per_day"` // Crawls started by the user per UTC day
+	MaxPages        int `json:"max_pages"`          // Pages per deep crawl of the user's URLs
+}
+
+// UserQuota puts a user on a plan other than the default. Nil limits fall
+// back to the default ones.
+type UserQuota struct {
+	UserID          uint      `json:"user_id" gorm:"primaryKey;autoIncrement:false"`
+	Plan            string    `json:"plan" gorm:"type:varchar(64);not null"`
+	MaxURLs         *int      `json:"max_urls"`
+	MaxCrawlsPerDay *int      `json:"max_crawls_per_day"`
+	MaxPages        *int      `json:"max_pages"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// TableName keeps GORM from treating quota as its own plural
+func (UserQuota) TableName() string {
+	return "user_quotas"
+}
+
+// CrawlUsage counts the crawls a user started on one UTC day
+type CrawlUsage struct {
+	ID     uint   `json:"id" gorm:"primaryKey"`
+	UserID uint   `json:"user_id" gorm:"not null;uniqueIndex:idx_crawl_usage_user_day"`
+	Day    string `json:"day" gorm:"type:char(10);not null;uniqueIndex:idx_crawl_usage_user_day"` // 2006-01-02
+	Crawls int    `json:"crawls" gorm:"not null;default:0"`
+}
+
+// QuotaUsage reports the limits of a user and how much of them is used
+type QuotaUsage struct {
+	Plan          string      `json:"plan"`
+	Unlimited     bool        `json:"unlimited"` // Admins have no limits
+	Limits        QuotaLimits `json:"limits"`
+	URLs          int64       `json:"urls"`
+	CrawlsToday   int         `json:"crawls_today"`
+	CrawlsResetAt time.Time   `json:"crawls_reset_at"`
+}
+
+// UserQuotaRequest sets the plan of a user; omitted limits use the default
+type UserQuotaRequest struct {
+	Plan            string `json:"plan" binding:"required,max=64"`
+	MaxURLs         *int   `json:"max_urls" binding:"omitempty,min=0"`
+	MaxCrawlsPerDay *int   `json:"max_crawls_per_day" binding:"omitempty,min=0"`
+	MaxPages        *int   `json:"max_pages" binding:"omitempty,min=0"`
+}
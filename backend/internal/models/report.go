@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// ReportBundle is an asynchronously generated archive of per-site reports
+type ReportBundle struct {
+	ID           uint       `json:"id" gorm:"primaryKey"`
+	UserID       uint       `json:"user_id" gorm:"not null;index"`
+	Status       string     `json:"status" gorm:"default:'queued'"` // queued, running, completed, error
+	URLIDs       string     `json:"-" gorm:"type:text"`             // JSON array of URL IDs
+	URLCount     int        `json:"url_count"`
+	StorageKey   string     `json:"-" gorm:"column:file_path"` // Key of the archive in the report storage
+	ErrorMessage string     `json:"error_message,omitempty"`
+	StartedAt    *time.Time `json:"started_at"`
+	CompletedAt  *time.Time `json:"completed_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// ReportBundleRequest represents the request to generate a report bundle
+type ReportBundleRequest struct {
+	URLIDs []uint `json:"url_ids" binding:"required"`
+}
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// RequestStats counts the API requests of a route or user
+type RequestStats struct {
+	Calls        int64   `json:"calls"`
+	ClientErrors int64   `json:"client_errors"` // 4xx responses, including rate limited requests
+	ServerErrors int64   `json:"server_errors"` // 5xx responses
+	AvgLatencyMs float64 `json:"avg_latency_ms"`
+	MaxLatencyMs float64 `json:"max_latency_ms"`
+}
+
+// RouteRequestStats are the request stats of one route, e.g. GET /api/v1/urls/:id
+type RouteRequestStats struct {
+	Method string `json:"method"`
+	Route  string `json:"route"` // Route pattern; empty for requests matching no route
+	RequestStats
+}
+
+// UserRequestStats are the request stats of one user; user 0 stands for
+// requests without authentication
+type UserRequestStats struct {
+	UserID   uint   `json:"user_id"`
+	Username string `json:"username,omitempty"`
+	RequestStats
+}
+
+// RequestMetricsReport is the API usage of this server process since it started
+type RequestMetricsReport struct {
+	Since  time.Time           `json:"since"`
+	Routes []RouteRequestStats `json:"routes"` // Most called first
+	Users  []UserRequestStats  `json:"users"`  // Most active first
+}
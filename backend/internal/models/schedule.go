@@ -0,0 +1,53 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CrawlSchedule re-crawls a URL at a fixed interval, optionally only inside a
+// crawl window so heavy crawls avoid the target's peak hours
+type CrawlSchedule struct {
+	ID              uint       `json:"id" gorm:"primaryKey"`
+	URLID           uint       `json:"url_id" gorm:"not null;uniqueIndex"`
+	IntervalMinutes int        `json:"interval_minutes" gorm:"not null"`
+	Enabled         bool       `json:"enabled"`
+	Timezone        string     `json:"timezone" gorm:"default:'UTC'"` // IANA name the window is expressed in, e.g. Europe/Warsaw
+	WindowStart     string     `json:"window_start"`                  // HH:MM, empty allows crawling at any time
+	WindowEnd       string     `json:"window_end"`                    // HH:MM, may be earlier than the start for overnight windows
+	NextRunAt       *time.Time `json:"next_run_at" gorm:"index"`
+	LastRunAt       *time.Time `json:"last_run_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+
+	// Days of the week the window opens on (mon, tue, ...), empty for every day
+	Days     []string `json:"days" gorm:"-"`
+	DaysList string   `json:"-" gorm:"column:days"` // Comma separated: "mon,wed,fri"
+}
+
+// BeforeSave encodes the weekday list into its column
+func (s *CrawlSchedule) BeforeSave(tx *gorm.DB) error {
+	s.DaysList = strings.Join(s.Days, ",")
+	return nil
+}
+
+// AfterFind decodes the weekday column into a list
+func (s *CrawlSchedule) AfterFind(tx *gorm.DB) error {
+	s.Days = []string{}
+	if s.DaysList != "" {
+		s.Days = strings.Split(s.DaysList, ",")
+	}
+	return nil
+}
+
+// CrawlScheduleRequest creates or replaces a URL's crawl schedule
+type CrawlScheduleRequest struct {
+	IntervalMinutes int      `json:"interval_minutes" binding:"required"`
+	Enabled         *bool    `json:"enabled"`
+	Timezone        string   `json:"timezone"`
+	WindowStart     string   `json:"window_start"`
+	WindowEnd       string   `json:"window_end"`
+	Days            []string `json:"days"`
+}
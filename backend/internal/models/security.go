@@ -0,0 +1,22 @@
+package models
+
+// SecurityCheck evaluates a single security response header
+type SecurityCheck struct {
+	Header   string `json:"header"`
+	Title    string `json:"title"`
+	Present  bool   `json:"present"`
+	Value    string `json:"value,omitempty"`
+	Passed   bool   `json:"passed"`
+	Score    int    `json:"score"`
+	MaxScore int    `json:"max_score"`
+	Message  string `json:"message"`
+}
+
+// SecurityReport is the security header audit of a crawl
+type SecurityReport struct {
+	URLID   uint              `json:"url_id"`
+	CrawlID uint              `json:"crawl_id"`
+	Score   int               `json:"score"` // 0-100
+	Headers map[string]string `json:"headers"`
+	Checks  []SecurityCheck   `json:"checks"`
+}
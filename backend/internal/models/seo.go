@@ -0,0 +1,21 @@
+package models
+
+// SEOCheck is a single item of an SEO audit checklist
+type SEOCheck struct {
+	Key      string `json:"key"`
+	Title    string `json:"title"`
+	Passed   bool   `json:"passed"`
+	Score    int    `json:"score"`
+	MaxScore int    `json:"max_score"`
+	Message  string `json:"message"`
+	// Set when an issue has been filed for the failed check
+	ExternalIssue *ExternalIssue `json:"external_issue,omitempty"`
+}
+
+// SEOReport is the scored SEO checklist of a crawl
+type SEOReport struct {
+	URLID   uint       `json:"url_id"`
+	CrawlID uint       `json:"crawl_id"`
+	Score   int        `json:"score"` // 0-100
+	Checks  []SEOCheck `json:"checks"`
+}
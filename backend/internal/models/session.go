@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// Session is a login of a user on one device. Its tokens carry the session
+// ID, so revoking the session rejects them before they expire. Refreshing a
+// token extends the session.
+type Session struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	UserID     uint       `json:"-" gorm:"not null;index"`
+	UserAgent  string     `json:"user_agent" gorm:"type:varchar(512)"`
+	IPAddress  string     `json:"ip_address" gorm:"type:varchar(45)"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt time.Time  `json:"last_used_at"`
+	ExpiresAt  time.Time  `json:"expires_at" gorm:"not null;index"`
+	RevokedAt  *time.Time `json:"-" gorm:"index"`
+	// Current marks the session of the request listing the sessions
+	Current bool `json:"current" gorm:"-"`
+}
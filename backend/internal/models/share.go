@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ShareAudience marks tokens of public report links, so they can't pass for
+// tokens of other kinds
+const ShareAudience = "report-share"
+
+// ShareClaims are the claims of a public report link token
+type ShareClaims struct {
+	URLID     uint `json:"url_id"`
+	CreatedBy uint `json:"created_by,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// ShareRequest asks for a public report link; zero uses the default lifetime
+type ShareRequest struct {
+	ExpiresInHours int `json:"expires_in_hours" binding:"omitempty,min=1"`
+}
+
+// ShareLink is a public, read-only link to the crawl results of a URL
+type ShareLink struct {
+	Token     string    `json:"token"`
+	Path      string    `json:"path"` // API path serving the report
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// SharedReport is what a public report link shows: the crawl results of a
+// URL without its settings, owner or organization
+type SharedReport struct {
+	URL         string               `json:"url"`
+	Title       string               `json:"title"`
+	HTMLVersion string               `json:"html_version"`
+	Status      string               `json:"status"`
+	Crawl       *CrawlStatusResponse `json:"crawl,omitempty"`
+	SEO         *SEOReport           `json:"seo,omitempty"`
+	Security    *SecurityReport      `json:"security,omitempty"`
+	ExpiresAt   time.Time            `json:"expires_at"`
+}
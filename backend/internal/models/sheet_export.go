@@ -0,0 +1,60 @@
+package models
+
+import "time"
+
+// GoogleConnection holds the OAuth tokens of a user's Google account, used to
+// write the user's Google Sheets exports
+type GoogleConnection struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	UserID       uint      `json:"user_id" gorm:"not null;uniqueIndex"`
+	RefreshToken string    `json:"-" gorm:"type:text;not null"`
+	AccessToken  string    `json:"-" gorm:"type:text"`
+	ExpiresAt    time.Time `json:"-"` // Expiry of AccessToken
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// GoogleOAuthAudience marks the state tokens of Google authorizations, so
+// they can't pass for tokens of other kinds
+const GoogleOAuthAudience = "google-oauth"
+
+// GoogleConnectionStatus tells whether Google Sheets exports can be used
+type GoogleConnectionStatus struct {
+	Configured  bool       `json:"configured"` // The deployment has a Google OAuth client
+	Connected   bool       `json:"connected"`  // The user authorized their Google account
+	ConnectedAt *time.Time `json:"connected_at,omitempty"`
+}
+
+// Sheet export kinds
+const (
+	SheetExportURLs        = "urls"
+	SheetExportBrokenLinks = "broken_links"
+)
+
+// SheetExport pushes the URL list or broken link report of an organization,
+// or of the URLs outside organizations, into a Google spreadsheet of the user
+// who created it. With an interval it runs again periodically, replacing the
+// content of the first sheet.
+type SheetExport struct {
+	ID              uint       `json:"id" gorm:"primaryKey"`
+	UserID          uint       `json:"user_id" gorm:"not null;index"` // User whose Google account writes the spreadsheet
+	OrganizationID  uint       `json:"organization_id" gorm:"not null;default:0;index"`
+	Kind            string     `json:"kind" gorm:"type:varchar(20);not null"` // urls, broken_links
+	SpreadsheetID   string     `json:"spreadsheet_id" gorm:"type:varchar(255);not null"`
+	SpreadsheetURL  string     `json:"spreadsheet_url" gorm:"type:text"`
+	IntervalMinutes int        `json:"interval_minutes"` // 0 only exports on demand
+	NextRunAt       *time.Time `json:"next_run_at" gorm:"index"`
+	LastRunAt       *time.Time `json:"last_run_at"`
+	LastRows        int        `json:"last_rows"`                                     // Rows written by the last run, without the header
+	LastError       string     `json:"last_error,omitempty" gorm:"type:varchar(255)"` // Why the last run failed, empty if it succeeded
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// CreateSheetExportRequest creates a Google Sheets export
+type CreateSheetExportRequest struct {
+	Kind string `json:"kind" binding:"required" example:"broken_links"` // urls or broken_links
+	// SpreadsheetID is the ID in the spreadsheet's address; empty creates a new spreadsheet
+	SpreadsheetID   string `json:"spreadsheet_id"`
+	IntervalMinutes int    `json:"interval_minutes" example:"1440"` // 0 only exports on demand
+}
@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+// Sitemap is an XML sitemap whose pages were added as URLs. With a sync
+// interval it is fetched again periodically: new pages are added, and pages
+// no longer listed are flagged on their entry but kept as URLs.
+type Sitemap struct {
+	ID                  uint       `json:"id" gorm:"primaryKey"`
+	UserID              uint       `json:"user_id" gorm:"not null;index"` // User who imported the sitemap and owns the URLs it adds
+	OrganizationID      uint       `json:"organization_id" gorm:"not null;default:0;index"`
+	URL                 string     `json:"url" gorm:"type:varchar(2048);not null"`
+	SyncIntervalMinutes int        `json:"sync_interval_minutes"` // 0 never syncs the sitemap again
+	NextSyncAt          *time.Time `json:"next_sync_at" gorm:"index"`
+	LastSyncedAt        *time.Time `json:"last_synced_at"`
+	LastError           string     `json:"last_error,omitempty" gorm:"type:varchar(255)"` // Why the last sync failed, empty if it succeeded
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+// SitemapEntry links a page listed in a sitemap to its URL
+type SitemapEntry struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	SitemapID uint       `json:"sitemap_id" gorm:"not null;uniqueIndex:idx_sitemap_entries_sitemap_url"`
+	URLID     uint       `json:"url_id" gorm:"not null;uniqueIndex:idx_sitemap_entries_sitemap_url;index"`
+	Loc       string     `json:"loc" gorm:"type:varchar(2048);not null"` // Normalized address the sitemap lists
+	RemovedAt *time.Time `json:"removed_at"`                             // When a sync found the page gone from the sitemap, nil while listed
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// ImportSitemapRequest adds the pages of a sitemap as URLs
+type ImportSitemapRequest struct {
+	URL                 string `json:"url" binding:"required,url" example:"https://example.com/sitemap.xml"`
+	SyncIntervalMinutes int    `json:"sync_interval_minutes" example:"1440"` // 0 imports the sitemap once
+}
+
+// SitemapSyncResult reports what a fetch of a sitemap changed
+type SitemapSyncResult struct {
+	SitemapID uint     `json:"sitemap_id"`
+	Listed    int      `json:"listed"`  // Pages the sitemap lists
+	Added     []string `json:"added"`   // Pages added as URLs or listed again
+	Removed   []string `json:"removed"` // Pages no longer listed
+	Skipped   []string `json:"skipped"` // Pages not added, because they are invalid or over the quota; the next sync tries again
+}
+
+// Drifted reports whether the sync added or removed pages
+func (r *SitemapSyncResult) Drifted() bool {
+	return len(r.Added) > 0 || len(r.Removed) > 0
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// RunningCrawl is a crawl in progress, as listed in the system status
+type RunningCrawl struct {
+	CrawlID    uint      `json:"crawl_id"`
+	URLID      uint      `json:"url_id"`
+	URL        string    `json:"url"`
+	StartedAt  time.Time `json:"started_at"`
+	DurationMs int64     `json:"duration_ms"` // How long the crawl has been running
+}
+
+// FailedCrawl is a crawl that ended with an error, as listed in the system status
+type FailedCrawl struct {
+	CrawlID      uint       `json:"crawl_id"`
+	URLID        uint       `json:"url_id"`
+	URL          string     `json:"url"`
+	ErrorMessage string     `json:"error_message"`
+	CompletedAt  *time.Time `json:"completed_at"`
+}
+
+// SystemStatus is the operational overview of crawling for admins
+type SystemStatus struct {
+	CheckedAt      time.Time        `json:"checked_at"`
+	Queue          *CrawlQueueStats `json:"queue,omitempty"` // Nil when crawls run in the API process
+	RunningCrawls  []RunningCrawl   `json:"running_crawls"`  // Longest running first
+	RecentFailures []FailedCrawl    `json:"recent_failures"` // Newest first
+}
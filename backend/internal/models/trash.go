@@ -0,0 +1,10 @@
+package models
+
+import "time"
+
+// TrashedURL is a soft-deleted URL in the recycle bin
+type TrashedURL struct {
+	URL
+	DeletedAt time.Time  `json:"deleted_at"`
+	PurgeAt   *time.Time `json:"purge_at"` // When the URL is purged for good, nil if trash is kept forever
+}
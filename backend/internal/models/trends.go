@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// CrawlTrendPoint is one completed crawl in the quality history of a URL
+type CrawlTrendPoint struct {
+	CrawlID       uint      `json:"crawl_id"`
+	CrawledAt     time.Time `json:"crawled_at"`
+	BrokenLinks   int       `json:"broken_links"`
+	InternalLinks int       `json:"internal_links"`
+	ExternalLinks int       `json:"external_links"`
+	PagesCrawled  int       `json:"pages_crawled"`
+	DurationMs    int64     `json:"duration_ms"` // From start to completion, 0 if either is unknown
+}
+
+// CrawlTrends is the quality history of a URL, oldest crawl first
+type CrawlTrends struct {
+	URLID  uint              `json:"url_id"`
+	Points []CrawlTrendPoint `json:"points"`
+}
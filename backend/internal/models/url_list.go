@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// URLListItem is a URL as shown in URL lists: the URL's own columns and the
+// counts of its links instead of the links themselves
+type URLListItem struct {
+	ID             uint      `json:"id"`
+	URL            string    `json:"url"`
+	Title          string    `json:"title"`
+	HTMLVersion    string    `json:"html_version"`
+	Status         string    `json:"status"`
+	HasLoginForm   bool      `json:"has_login_form"`
+	NeedsAttention bool      `json:"needs_attention"`
+	UserID         *uint     `json:"user_id,omitempty"`
+	OrganizationID uint      `json:"organization_id"`
+	InternalLinks  int       `json:"internal_links" gorm:"-"`
+	ExternalLinks  int       `json:"external_links" gorm:"-"`
+	BrokenLinks    int       `json:"broken_links" gorm:"-"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
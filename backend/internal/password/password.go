@@ -0,0 +1,99 @@
+// Package password hashes account passwords. New hashes use the configured
+// algorithm, Argon2id or bcrypt, while hashes of either algorithm keep
+// verifying, so the algorithm and its cost can be changed without resetting
+// passwords: outdated hashes are replaced when their user next logs in.
+package password
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrMismatch is returned when a password doesn't match its hash
+var ErrMismatch = errors.New("password does not match")
+
+// ErrUnknownHash is returned for hashes no scheme of the hasher recognizes
+var ErrUnknownHash = errors.New("unknown password hash format")
+
+// Algorithms
+const (
+	AlgorithmArgon2id = "argon2id"
+	AlgorithmBcrypt   = "bcrypt"
+)
+
+// Config selects the algorithm of new hashes and the cost of both algorithms.
+// Zero values use the defaults.
+type Config struct {
+	// Algorithm is "argon2id" or "bcrypt"
+	Algorithm  string
+	BcryptCost int
+	Argon2     Argon2Params
+}
+
+// Scheme is a hashing algorithm with its cost
+type Scheme interface {
+	// Hash returns the encoded hash of password, salt and parameters included
+	Hash(password string) (string, error)
+	// Recognizes reports whether hash was encoded by this algorithm
+	Recognizes(hash string) bool
+	// Verify checks password against a hash of this algorithm, returning
+	// ErrMismatch if it doesn't match
+	Verify(hash, password string) error
+	// Outdated reports whether hash was made with other parameters than the
+	// scheme's and should be replaced
+	Outdated(hash string) bool
+}
+
+// Hasher hashes passwords with its preferred scheme and verifies hashes of
+// any of its schemes
+type Hasher struct {
+	// schemes starts with the preferred one
+	schemes []Scheme
+}
+
+// NewHasher returns a hasher making new hashes with preferred that also
+// verifies the hashes of others
+func NewHasher(preferred Scheme, others ...Scheme) *Hasher {
+	return &Hasher{schemes: append([]Scheme{preferred}, others...)}
+}
+
+// New returns the hasher of cfg, which verifies hashes of both algorithms
+func New(cfg Config) (*Hasher, error) {
+	argon2id := Argon2id{Params: cfg.Argon2}
+	bcrypt := Bcrypt{Cost: cfg.BcryptCost}
+	switch cfg.Algorithm {
+	case "", AlgorithmArgon2id:
+		return NewHasher(argon2id, bcrypt), nil
+	case AlgorithmBcrypt:
+		return NewHasher(bcrypt, argon2id), nil
+	default:
+		return nil, fmt.Errorf("unknown password hashing algorithm %q; use argon2id or bcrypt", cfg.Algorithm)
+	}
+}
+
+// Default returns the hasher of the default configuration
+func Default() *Hasher {
+	hasher, _ := New(Config{})
+	return hasher
+}
+
+// Hash returns the hash of password to store
+func (h *Hasher) Hash(password string) (string, error) {
+	return h.schemes[0].Hash(password)
+}
+
+// Verify checks password against a stored hash. A matching password whose
+// hash was made by another scheme or with outdated parameters reports that it
+// should be rehashed.
+func (h *Hasher) Verify(hash, password string) (rehash bool, err error) {
+	for i, scheme := range h.schemes {
+		if !scheme.Recognizes(hash) {
+			continue
+		}
+		if err := scheme.Verify(hash, password); err != nil {
+			return false, err
+		}
+		return i > 0 || scheme.Outdated(hash), nil
+	}
+	return false, ErrUnknownHash
+}
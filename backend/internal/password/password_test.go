@@ -0,0 +1,97 @@
+package password
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cheapArgon2 keeps the tests fast
+var cheapArgon2 = Argon2Params{Memory: 64, Iterations: 1, Parallelism: 1}
+
+func TestArgon2id(t *testing.T) {
+	scheme := Argon2id{Params: cheapArgon2}
+	hash, err := scheme.Hash("correct horse")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=64,t=1,p=1$"), hash)
+	assert.True(t, scheme.Recognizes(hash))
+	assert.False(t, Bcrypt{}.Recognizes(hash))
+
+	assert.NoError(t, scheme.Verify(hash, "correct horse"))
+	assert.ErrorIs(t, scheme.Verify(hash, "battery staple"), ErrMismatch)
+	assert.Error(t, scheme.Verify("$argon2id$v=19$m=64$salt", "correct horse"), "malformed hashes don't verify")
+
+	other, err := scheme.Hash("correct horse")
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, other, "every hash has its own salt")
+
+	assert.False(t, scheme.Outdated(hash))
+	assert.True(t, Argon2id{Params: Argon2Params{Memory: 128, Iterations: 1, Parallelism: 1}}.Outdated(hash))
+	assert.True(t, Argon2id{Params: Argon2Params{Memory: 64, Iterations: 2, Parallelism: 1}}.Outdated(hash))
+}
+
+func TestBcrypt(t *testing.T) {
+	scheme := Bcrypt{Cost: 4}
+	hash, err := scheme.Hash("correct horse")
+	require.NoError(t, err)
+	assert.True(t, scheme.Recognizes(hash))
+	assert.False(t, Argon2id{}.Recognizes(hash))
+
+	assert.NoError(t, scheme.Verify(hash, "correct horse"))
+	assert.ErrorIs(t, scheme.Verify(hash, "battery staple"), ErrMismatch)
+
+	assert.False(t, scheme.Outdated(hash))
+	assert.True(t, Bcrypt{Cost: 5}.Outdated(hash))
+}
+
+func TestHasher_Verify(t *testing.T) {
+	argon2id := Argon2id{Params: cheapArgon2}
+	bcrypt := Bcrypt{Cost: 4}
+	hasher := NewHasher(argon2id, bcrypt)
+
+	current, err := hasher.Hash("correct horse")
+	require.NoError(t, err)
+	assert.True(t, argon2id.Recognizes(current), "new hashes use the preferred scheme")
+	legacy, err := bcrypt.Hash("correct horse")
+	require.NoError(t, err)
+	weak, err := Argon2id{Params: Argon2Params{Memory: 32, Iterations: 1, Parallelism: 1}}.Hash("correct horse")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		hash     string
+		password string
+		rehash   bool
+		err      error
+	}{
+		{"current hash", current, "correct horse", false, nil},
+		{"other algorithm", legacy, "correct horse", true, nil},
+		{"weaker parameters", weak, "correct horse", true, nil},
+		{"wrong password", legacy, "battery staple", false, ErrMismatch},
+		{"unknown format", "5f4dcc3b5aa765d61d8327deb882cf99", "correct horse", false, ErrUnknownHash},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rehash, err := hasher.Verify(tt.hash, tt.password)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.rehash, rehash)
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	hasher, err := New(Config{Algorithm: AlgorithmBcrypt, BcryptCost: 4, Argon2: cheapArgon2})
+	require.NoError(t, err)
+	hash, err := hasher.Hash("correct horse")
+	require.NoError(t, err)
+	assert.True(t, Bcrypt{}.Recognizes(hash))
+
+	_, err = New(Config{Algorithm: "md5"})
+	assert.Error(t, err)
+}
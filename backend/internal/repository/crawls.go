@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+
+	"web-crawler-backend/internal/models"
+)
+
+// CrawlRepository stores crawls and their events
+type CrawlRepository interface {
+	// Exists reports whether a crawl exists
+	Exists(id uint) (bool, error)
+	// LatestCompleted returns the newest completed crawl of a URL
+	LatestCompleted(urlID uint) (*models.Crawl, error)
+	// SetBrokenLinks updates the broken link count of a crawl
+	SetBrokenLinks(id uint, brokenLinks int) error
+	// Events returns the events of a crawl in the order they happened
+	Events(crawlID uint) ([]models.CrawlEvent, error)
+}
+
+type gormCrawlRepository struct {
+	db *gorm.DB
+}
+
+func (r gormCrawlRepository) Exists(id uint) (bool, error) {
+	var count int64
+	if err := r.db.Model(&models.Crawl{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r gormCrawlRepository) LatestCompleted(urlID uint) (*models.Crawl, error) {
+	var crawl models.Crawl
+	if err := r.db.Where("url_id = ? AND status = ?", urlID, "completed").
+		Order("created_at DESC, id DESC").First(&crawl).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &crawl, nil
+}
+
+func (r gormCrawlRepository) SetBrokenLinks(id uint, brokenLinks int) error {
+	return r.db.Model(&models.Crawl{}).Where("id = ?", id).Update("broken_links", brokenLinks).Error
+}
+
+func (r gormCrawlRepository) Events(crawlID uint) ([]models.CrawlEvent, error) {
+	events := []models.CrawlEvent{}
+	if err := r.db.Where("crawl_id = ?", crawlID).Order("elapsed_ms, id").Find(&events).Error; err != nil {
+		return nil, err
+	}
+	return events, nil
+}
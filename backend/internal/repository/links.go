@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+
+	"web-crawler-backend/internal/models"
+)
+
+// LinkRepository stores the links found by crawls
+type LinkRepository interface {
+	// Broken returns the inaccessible links of a crawl
+	Broken(crawlID uint) ([]models.Link, error)
+	// SetStatus updates the status code of a link and whether it's accessible
+	SetStatus(id uint, statusCode int, accessible bool) error
+}
+
+type gormLinkRepository struct {
+	db *gorm.DB
+}
+
+func (r gormLinkRepository) Broken(crawlID uint) ([]models.Link, error) {
+	var links []models.Link
+	if err := r.db.Where("crawl_id = ? AND is_accessible = ?", crawlID, false).Order("id").Find(&links).Error; err != nil {
+		return nil, err
+	}
+	return links, nil
+}
+
+func (r gormLinkRepository) SetStatus(id uint, statusCode int, accessible bool) error {
+	return r.db.Model(&models.Link{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"status_code": statusCode, "is_accessible": accessible}).Error
+}
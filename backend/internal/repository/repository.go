@@ -0,0 +1,77 @@
+// Package repository hides how URLs, crawls, links, users and sessions are
+// stored behind interfaces, so services can be tested with fakes and the
+// datastore can change without touching business logic. The GORM implementations are
+// created with NewStore.
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// ErrNotFound is returned when a record doesn't exist
+var ErrNotFound = errors.New("record not found")
+
+// Store groups the repositories of one datastore
+type Store interface {
+	URLs() URLRepository
+	Crawls() CrawlRepository
+	Links() LinkRepository
+	Users() UserRepository
+	Sessions() SessionRepository
+	// WithContext returns a store whose queries are traced as part of ctx
+	// and cancelled at its deadline
+	WithContext(ctx context.Context) Store
+	// Transaction runs fn with a store whose changes are committed together,
+	// or rolled back if fn returns an error
+	Transaction(fn func(Store) error) error
+}
+
+type gormStore struct {
+	db *gorm.DB
+}
+
+// NewStore creates a store backed by the database
+func NewStore(db *gorm.DB) Store {
+	return gormStore{db: db}
+}
+
+func (s gormStore) URLs() URLRepository {
+	return gormURLRepository{db: s.db}
+}
+
+func (s gormStore) Crawls() CrawlRepository {
+	return gormCrawlRepository{db: s.db}
+}
+
+func (s gormStore) Links() LinkRepository {
+	return gormLinkRepository{db: s.db}
+}
+
+func (s gormStore) Users() UserRepository {
+	return gormUserRepository{db: s.db}
+}
+
+func (s gormStore) Sessions() SessionRepository {
+	return gormSessionRepository{db: s.db}
+}
+
+func (s gormStore) WithContext(ctx context.Context) Store {
+	return gormStore{db: s.db.WithContext(ctx)}
+}
+
+func (s gormStore) Transaction(fn func(Store) error) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		return fn(gormStore{db: tx})
+	})
+}
+
+// notFound turns GORM's missing record error into ErrNotFound
+func notFound(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"web-crawler-backend/internal/models"
+)
+
+func setupRepositoryTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.URL{}, &models.Crawl{}, &models.Link{}, &models.PageMeta{}, &models.CrawlEvent{}, &models.User{}, &models.Session{}))
+	return db
+}
+
+func TestURLRepository(t *testing.T) {
+	db := setupRepositoryTestDB(t)
+	urls := NewStore(db).URLs()
+
+	orgURL := &models.URL{URL: "https://example.com", OrganizationID: 1}
+	otherURL := &models.URL{URL: "https://example.org"}
+	require.NoError(t, db.Create(orgURL).Error)
+	require.NoError(t, db.Create(otherURL).Error)
+	now := time.Now()
+	require.NoError(t, db.Create(&models.Crawl{URLID: orgURL.ID, Status: "completed", CreatedAt: now.Add(-time.Hour)}).Error)
+	newest := &models.Crawl{URLID: orgURL.ID, Status: "completed", CreatedAt: now}
+	require.NoError(t, db.Create(newest).Error)
+	require.NoError(t, db.Create(&[]models.Link{
+		{URLID: orgURL.ID, CrawlID: newest.ID, LinkURL: "https://example.com/gone", StatusCode: 404},
+		{URLID: orgURL.ID, CrawlID: newest.ID, LinkURL: "https://example.com/ok", StatusCode: 200, IsAccessible: true},
+	}).Error)
+
+	t.Run("a URL with its details", func(t *testing.T) {
+		url, err := urls.FindWithDetails(orgURL.ID)
+		require.NoError(t, err)
+		require.Len(t, url.Crawls, 2)
+		assert.Equal(t, newest.ID, url.Crawls[0].ID)
+		require.Len(t, url.Links, 1)
+		assert.Equal(t, "https://example.com/gone", url.Links[0].LinkURL)
+
+		_, err = urls.FindWithDetails(999)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("deletes are limited to the organization", func(t *testing.T) {
+		organization := uint(1)
+		require.NoError(t, urls.Delete([]uint{orgURL.ID, otherURL.ID}, &organization))
+		_, err := urls.FindWithDetails(orgURL.ID)
+		assert.ErrorIs(t, err, ErrNotFound)
+		_, err = urls.FindWithDetails(otherURL.ID)
+		assert.NoError(t, err)
+	})
+}
+
+func TestCrawlAndLinkRepositories(t *testing.T) {
+	db := setupRepositoryTestDB(t)
+	store := NewStore(db)
+
+	url := &models.URL{URL: "https://example.com"}
+	require.NoError(t, db.Create(url).Error)
+	_, err := store.Crawls().LatestCompleted(url.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	crawl := &models.Crawl{URLID: url.ID, Status: "completed", BrokenLinks: 1}
+	require.NoError(t, db.Create(crawl).Error)
+	require.NoError(t, db.Create(&models.Crawl{URLID: url.ID, Status: "running"}).Error)
+	link := &models.Link{URLID: url.ID, CrawlID: crawl.ID, LinkURL: "https://example.com/gone", StatusCode: 500}
+	require.NoError(t, db.Create(link).Error)
+	require.NoError(t, db.Create(&models.CrawlEvent{CrawlID: crawl.ID, Type: "completed", ElapsedMs: 20}).Error)
+	require.NoError(t, db.Create(&models.CrawlEvent{CrawlID: crawl.ID, Type: "started", ElapsedMs: 0}).Error)
+
+	latest, err := store.Crawls().LatestCompleted(url.ID)
+	require.NoError(t, err)
+	assert.Equal(t, crawl.ID, latest.ID)
+
+	exists, err := store.Crawls().Exists(crawl.ID)
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	events, err := store.Crawls().Events(crawl.ID)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, "started", events[0].Type)
+
+	t.Run("a failed transaction is rolled back", func(t *testing.T) {
+		err := store.Transaction(func(tx Store) error {
+			require.NoError(t, tx.Links().SetStatus(link.ID, 200, true))
+			return errors.New("failed")
+		})
+		assert.Error(t, err)
+		broken, err := store.Links().Broken(crawl.ID)
+		require.NoError(t, err)
+		assert.Len(t, broken, 1)
+	})
+
+	t.Run("a transaction commits all changes", func(t *testing.T) {
+		require.NoError(t, store.Transaction(func(tx Store) error {
+			if err := tx.Links().SetStatus(link.ID, 200, true); err != nil {
+				return err
+			}
+			return tx.Crawls().SetBrokenLinks(crawl.ID, 0)
+		}))
+		broken, err := store.Links().Broken(crawl.ID)
+		require.NoError(t, err)
+		assert.Empty(t, broken)
+		var stored models.Crawl
+		require.NoError(t, db.First(&stored, crawl.ID).Error)
+		assert.Equal(t, 0, stored.BrokenLinks)
+	})
+}
+
+func TestUserRepository(t *testing.T) {
+	users := NewStore(setupRepositoryTestDB(t)).Users()
+
+	active := &models.User{Username: "alice", Email: "alice@example.com", IsActive: true}
+	require.NoError(t, users.Create(active))
+	assert.NotZero(t, active.ID)
+
+	found, err := users.FindByUsernameOrEmail("someone", "alice@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, active.ID, found.ID)
+
+	found, err = users.FindActiveByUsername("alice")
+	require.NoError(t, err)
+	assert.Equal(t, active.ID, found.ID)
+
+	_, err = users.FindActiveByID(active.ID + 1)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, users.SetPassword(active.ID, "$argon2id$rehashed"))
+	found, err = users.FindActiveByID(active.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "$argon2id$rehashed", found.Password)
+}
+
+func TestSessionRepository(t *testing.T) {
+	sessions := NewStore(setupRepositoryTestDB(t)).Sessions()
+	now := time.Now()
+
+	older := &models.Session{UserID: 1, UserAgent: "Firefox", LastUsedAt: now.Add(-time.Hour), ExpiresAt: now.Add(time.Hour)}
+	newer := &models.Session{UserID: 1, UserAgent: "Safari", LastUsedAt: now, ExpiresAt: now.Add(time.Hour)}
+	expired := &models.Session{UserID: 1, LastUsedAt: now, ExpiresAt: now.Add(-time.Minute)}
+	for _, session := range []*models.Session{older, newer, expired} {
+		require.NoError(t, sessions.Create(session))
+	}
+
+	active, err := sessions.ListActive(1, now)
+	require.NoError(t, err)
+	require.Len(t, active, 2)
+	assert.Equal(t, newer.ID, active[0].ID, "most recently used first")
+
+	_, err = sessions.FindActive(expired.ID, now)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, sessions.Extend(older.ID, now.Add(time.Minute), now.Add(2*time.Hour)))
+	found, err := sessions.FindActive(older.ID, now.Add(90*time.Minute))
+	require.NoError(t, err)
+	assert.WithinDuration(t, now.Add(time.Minute), found.LastUsedAt, time.Second)
+
+	assert.ErrorIs(t, sessions.Revoke(2, older.ID, now), ErrNotFound, "sessions of other users")
+	require.NoError(t, sessions.Revoke(1, older.ID, now))
+	assert.ErrorIs(t, sessions.Revoke(1, older.ID, now), ErrNotFound, "already revoked")
+	_, err = sessions.FindActive(older.ID, now)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
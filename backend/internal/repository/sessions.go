@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"web-crawler-backend/internal/models"
+)
+
+// SessionRepository stores the login sessions of users. A session is active
+// until it expires or is revoked.
+type SessionRepository interface {
+	// Create stores a new session and sets its ID
+	Create(session *models.Session) error
+	// FindActive returns a session that is active at now
+	FindActive(id uint, now time.Time) (*models.Session, error)
+	// ListActive returns the sessions of a user active at now, most
+	// recently used first
+	ListActive(userID uint, now time.Time) ([]models.Session, error)
+	// Touch records that a session was used at
+	Touch(id uint, at time.Time) error
+	// Extend records that a session was used at and moves its expiry
+	Extend(id uint, at, expiresAt time.Time) error
+	// Revoke ends an active session of a user, returning ErrNotFound if the
+	// user has no such session
+	Revoke(userID, id uint, at time.Time) error
+}
+
+type gormSessionRepository struct {
+	db *gorm.DB
+}
+
+// active limits a query to the sessions active at now
+func (r gormSessionRepository) active(now time.Time) *gorm.DB {
+	return r.db.Model(&models.Session{}).Where("revoked_at IS NULL AND expires_at > ?", now)
+}
+
+func (r gormSessionRepository) Create(session *models.Session) error {
+	return r.db.Create(session).Error
+}
+
+func (r gormSessionRepository) FindActive(id uint, now time.Time) (*models.Session, error) {
+	var session models.Session
+	if err := r.active(now).Where("id = ?", id).First(&session).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &session, nil
+}
+
+func (r gormSessionRepository) ListActive(userID uint, now time.Time) ([]models.Session, error) {
+	var sessions []models.Session
+	err := r.active(now).Where("user_id = ?", userID).Order("last_used_at DESC, id DESC").Find(&sessions).Error
+	return sessions, err
+}
+
+func (r gormSessionRepository) Touch(id uint, at time.Time) error {
+	return r.db.Model(&models.Session{}).Where("id = ?", id).Update("last_used_at", at).Error
+}
+
+func (r gormSessionRepository) Extend(id uint, at, expiresAt time.Time) error {
+	return r.db.Model(&models.Session{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"last_used_at": at, "expires_at": expiresAt}).Error
+}
+
+func (r gormSessionRepository) Revoke(userID, id uint, at time.Time) error {
+	result := r.active(at).Where("id = ? AND user_id = ?", id, userID).Update("revoked_at", at)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
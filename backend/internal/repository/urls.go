@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+
+	"web-crawler-backend/internal/models"
+)
+
+// URLRepository stores the URLs to crawl
+type URLRepository interface {
+	// FindWithDetails returns a URL with its crawls, newest first, their
+	// page metadata and its broken links
+	FindWithDetails(id uint) (*models.URL, error)
+	// Delete soft deletes URLs. A non-nil organizationID only deletes the
+	// ones of that organization, 0 being the URLs outside any.
+	Delete(ids []uint, organizationID *uint) error
+}
+
+type gormURLRepository struct {
+	db *gorm.DB
+}
+
+func (r gormURLRepository) FindWithDetails(id uint) (*models.URL, error) {
+	var url models.URL
+	err := r.db.
+		Preload("Crawls", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at DESC")
+		}).
+		Preload("Crawls.PageMeta").
+		Preload("Links", func(db *gorm.DB) *gorm.DB {
+			return db.Where("is_accessible = ?", false)
+		}).
+		First(&url, id).Error
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &url, nil
+}
+
+func (r gormURLRepository) Delete(ids []uint, organizationID *uint) error {
+	query := r.db
+	if organizationID != nil {
+		query = query.Where("urls.organization_id = ?", *organizationID)
+	}
+	return query.Delete(&models.URL{}, ids).Error
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+
+	"web-crawler-backend/internal/models"
+)
+
+// UserRepository stores user accounts
+type UserRepository interface {
+	// FindByUsernameOrEmail returns a user, active or not, with either the
+	// username or the email
+	FindByUsernameOrEmail(username, email string) (*models.User, error)
+	// FindActiveByUsername returns an active user by username
+	FindActiveByUsername(username string) (*models.User, error)
+	// FindActiveByID returns an active user by ID
+	FindActiveByID(id uint) (*models.User, error)
+	// Create stores a new user and sets its ID
+	Create(user *models.User) error
+	// SetPassword replaces the password hash of a user
+	SetPassword(id uint, hash string) error
+}
+
+type gormUserRepository struct {
+	db *gorm.DB
+}
+
+func (r gormUserRepository) FindByUsernameOrEmail(username, email string) (*models.User, error) {
+	var user models.User
+	if err := r.db.Where("username = ? OR email = ?", username, email).First(&user).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &user, nil
+}
+
+func (r gormUserRepository) FindActiveByUsername(username string) (*models.User, error) {
+	var user models.User
+	if err := r.db.Where("username = ? AND is_active = ?", username, true).First(&user).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &user, nil
+}
+
+func (r gormUserRepository) FindActiveByID(id uint) (*models.User, error) {
+	var user models.User
+	if err := r.db.Where("id = ? AND is_active = ?", id, true).First(&user).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &user, nil
+}
+
+func (r gormUserRepository) Create(user *models.User) error {
+	return r.db.Create(user).Error
+}
+
+func (r gormUserRepository) SetPassword(id uint, hash string) error {
+	return r.db.Model(&models.User{}).Where("id = ?", id).Update("password", hash).Error
+}
@@ -0,0 +1,400 @@
+package selector
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// cssAttr is an attribute selector such as [href^="https:"]
+type cssAttr struct {
+	key, op, val string
+}
+
+// compound is a sequence of simple selectors that all apply to one element,
+// e.g. a.external[href]
+type compound struct {
+	tag     string
+	id      string
+	classes []string
+	attrs   []cssAttr
+	// nth holds the :nth-child positions the element must have; 0 stands for
+	// :first-child and -1 for :last-child
+	nth []int
+}
+
+// complexSelector is a chain of compound selectors joined by combinators
+type complexSelector struct {
+	compounds []compound
+	// combinators[i] joins compounds[i] and compounds[i+1]: ' ', '>', '+' or '~'
+	combinators []byte
+}
+
+// CSS compiles a CSS selector. Matches are read as text unless the query's
+// Attr is set.
+func CSS(expr string) (*Query, error) {
+	p := &cssParser{s: expr}
+	groups, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Query{match: func(root *html.Node) []*html.Node {
+		var matches []*html.Node
+		walkElements(root, func(n *html.Node) {
+			for _, sel := range groups {
+				if sel.matchAt(n, len(sel.compounds)-1) {
+					matches = append(matches, n)
+					return
+				}
+			}
+		})
+		return matches
+	}}, nil
+}
+
+// matchAt reports whether n matches the compound at index i and the
+// compounds before it match the elements its combinators lead to
+func (sel complexSelector) matchAt(n *html.Node, i int) bool {
+	if !sel.compounds[i].matches(n) {
+		return false
+	}
+	if i == 0 {
+		return true
+	}
+
+	switch sel.combinators[i-1] {
+	case '>':
+		return n.Parent != nil && n.Parent.Type == html.ElementNode && sel.matchAt(n.Parent, i-1)
+	case '+':
+		prev := previousElement(n)
+		return prev != nil && sel.matchAt(prev, i-1)
+	case '~':
+		for prev := previousElement(n); prev != nil; prev = previousElement(prev) {
+			if sel.matchAt(prev, i-1) {
+				return true
+			}
+		}
+		return false
+	default:
+		for p := n.Parent; p != nil && p.Type == html.ElementNode; p = p.Parent {
+			if sel.matchAt(p, i-1) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func (c compound) matches(n *html.Node) bool {
+	if n.Type != html.ElementNode || (c.tag != "" && n.Data != c.tag) {
+		return false
+	}
+	if c.id != "" {
+		if id, _ := attr(n, "id"); id != c.id {
+			return false
+		}
+	}
+	if len(c.classes) > 0 {
+		class, _ := attr(n, "class")
+		classes := strings.Fields(class)
+		for _, want := range c.classes {
+			if !slices.Contains(classes, want) {
+				return false
+			}
+		}
+	}
+	for _, a := range c.attrs {
+		if !a.matches(n) {
+			return false
+		}
+	}
+	if len(c.nth) > 0 {
+		position, count := siblingPosition(n)
+		for _, nth := range c.nth {
+			if (nth == -1 && position != count) || (nth >= 0 && position != max(nth, 1)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (a cssAttr) matches(n *html.Node) bool {
+	v, ok := attr(n, a.key)
+	if !ok {
+		return false
+	}
+	switch a.op {
+	case "=":
+		return v == a.val
+	case "~=":
+		return slices.Contains(strings.Fields(v), a.val)
+	case "|=":
+		return v == a.val || strings.HasPrefix(v, a.val+"-")
+	case "^=":
+		return a.val != "" && strings.HasPrefix(v, a.val)
+	case "$=":
+		return a.val != "" && strings.HasSuffix(v, a.val)
+	case "*=":
+		return a.val != "" && strings.Contains(v, a.val)
+	default:
+		return true
+	}
+}
+
+// previousElement returns the element sibling before n, or nil
+func previousElement(n *html.Node) *html.Node {
+	for prev := n.PrevSibling; prev != nil; prev = prev.PrevSibling {
+		if prev.Type == html.ElementNode {
+			return prev
+		}
+	}
+	return nil
+}
+
+// siblingPosition returns the 1-based position of n among the element
+// children of its parent, and their number
+func siblingPosition(n *html.Node) (int, int) {
+	if n.Parent == nil {
+		return 1, 1
+	}
+	position, count := 0, 0
+	for c := n.Parent.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode {
+			continue
+		}
+		count++
+		if c == n {
+			position = count
+		}
+	}
+	return position, count
+}
+
+// cssParser reads a selector group by recursive descent
+type cssParser struct {
+	s   string
+	pos int
+}
+
+func (p *cssParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("invalid CSS selector %q at offset %d: %s", p.s, p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *cssParser) parse() ([]complexSelector, error) {
+	var groups []complexSelector
+	for {
+		p.skipSpace()
+		sel, err := p.parseComplex()
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, sel)
+
+		if p.pos == len(p.s) {
+			return groups, nil
+		}
+		p.pos++ // the comma parseComplex stopped at
+	}
+}
+
+func (p *cssParser) parseComplex() (complexSelector, error) {
+	var sel complexSelector
+	for {
+		c, err := p.parseCompound()
+		if err != nil {
+			return sel, err
+		}
+		sel.compounds = append(sel.compounds, c)
+
+		spaced := p.skipSpace()
+		if p.pos == len(p.s) || p.s[p.pos] == ',' {
+			return sel, nil
+		}
+		combinator := byte(' ')
+		switch p.s[p.pos] {
+		case '>', '+', '~':
+			combinator = p.s[p.pos]
+			p.pos++
+			p.skipSpace()
+		default:
+			if !spaced {
+				return sel, p.errorf("unexpected %q", p.s[p.pos])
+			}
+		}
+		sel.combinators = append(sel.combinators, combinator)
+	}
+}
+
+func (p *cssParser) parseCompound() (compound, error) {
+	var c compound
+	start := p.pos
+	if p.pos < len(p.s) && p.s[p.pos] == '*' {
+		p.pos++
+	} else if name := p.ident(); name != "" {
+		c.tag = strings.ToLower(name)
+	}
+
+	for p.pos < len(p.s) {
+		switch p.s[p.pos] {
+		case '#':
+			p.pos++
+			id := p.ident()
+			if id == "" {
+				return c, p.errorf("expected an ID after #")
+			}
+			c.id = id
+		case '.':
+			p.pos++
+			class := p.ident()
+			if class == "" {
+				return c, p.errorf("expected a class name after .")
+			}
+			c.classes = append(c.classes, class)
+		case '[':
+			a, err := p.parseAttr()
+			if err != nil {
+				return c, err
+			}
+			c.attrs = append(c.attrs, a)
+		case ':':
+			nth, err := p.parsePseudo()
+			if err != nil {
+				return c, err
+			}
+			c.nth = append(c.nth, nth)
+		default:
+			if p.pos == start {
+				return c, p.errorf("expected a selector")
+			}
+			return c, nil
+		}
+	}
+	if p.pos == start {
+		return c, p.errorf("expected a selector")
+	}
+	return c, nil
+}
+
+// parseAttr reads [key], [key=value] and the other attribute operators
+func (p *cssParser) parseAttr() (cssAttr, error) {
+	var a cssAttr
+	p.pos++ // [
+	p.skipSpace()
+	a.key = strings.ToLower(p.ident())
+	if a.key == "" {
+		return a, p.errorf("expected an attribute name")
+	}
+	p.skipSpace()
+
+	if p.pos < len(p.s) && p.s[p.pos] != ']' {
+		for _, op := range []string{"=", "~=", "|=", "^=", "$=", "*="} {
+			if strings.HasPrefix(p.s[p.pos:], op) {
+				a.op = op
+				break
+			}
+		}
+		if a.op == "" {
+			return a, p.errorf("unknown attribute operator")
+		}
+		p.pos += len(a.op)
+		p.skipSpace()
+
+		value, err := p.value()
+		if err != nil {
+			return a, err
+		}
+		a.val = value
+		p.skipSpace()
+	}
+
+	if p.pos == len(p.s) || p.s[p.pos] != ']' {
+		return a, p.errorf("expected ]")
+	}
+	p.pos++
+	return a, nil
+}
+
+// parsePseudo reads :first-child, :last-child or :nth-child(n)
+func (p *cssParser) parsePseudo() (int, error) {
+	p.pos++ // :
+	name := strings.ToLower(p.ident())
+	switch name {
+	case "first-child":
+		return 0, nil
+	case "last-child":
+		return -1, nil
+	case "nth-child":
+		if p.pos == len(p.s) || p.s[p.pos] != '(' {
+			return 0, p.errorf("expected ( after :nth-child")
+		}
+		end := strings.IndexByte(p.s[p.pos:], ')')
+		if end < 0 {
+			return 0, p.errorf("expected )")
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(p.s[p.pos+1 : p.pos+end]))
+		if err != nil || n < 1 {
+			return 0, p.errorf(":nth-child takes a positive number")
+		}
+		p.pos += end + 1
+		return n, nil
+	default:
+		return 0, p.errorf("unsupported pseudo-class :%s", name)
+	}
+}
+
+// value reads a quoted string or an identifier
+func (p *cssParser) value() (string, error) {
+	if p.pos < len(p.s) && (p.s[p.pos] == '"' || p.s[p.pos] == '\'') {
+		quote := p.s[p.pos]
+		var b strings.Builder
+		for i := p.pos + 1; i < len(p.s); i++ {
+			switch p.s[i] {
+			case '\\':
+				if i+1 < len(p.s) {
+					i++
+					b.WriteByte(p.s[i])
+				}
+			case quote:
+				p.pos = i + 1
+				return b.String(), nil
+			default:
+				b.WriteByte(p.s[i])
+			}
+		}
+		return "", p.errorf("unterminated string")
+	}
+
+	value := p.ident()
+	if value == "" {
+		return "", p.errorf("expected a value")
+	}
+	return value, nil
+}
+
+// ident reads a name of letters, digits, hyphens, underscores and non-ASCII characters
+func (p *cssParser) ident() string {
+	start := p.pos
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		if c == '-' || c == '_' || c >= 0x80 || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' {
+			p.pos++
+		} else {
+			break
+		}
+	}
+	return p.s[start:p.pos]
+}
+
+// skipSpace skips whitespace and reports whether there was any
+func (p *cssParser) skipSpace() bool {
+	start := p.pos
+	for p.pos < len(p.s) && strings.IndexByte(" \t\n\r\f", p.s[p.pos]) >= 0 {
+		p.pos++
+	}
+	return p.pos > start
+}
@@ -0,0 +1,99 @@
+// Package selector finds elements of parsed HTML pages with CSS selectors or
+// a subset of XPath, for user defined extraction rules.
+//
+// CSS selectors support type, universal, #id, .class and attribute selectors
+// ([a], [a=v], [a~=v], [a|=v], [a^=v], [a$=v], [a*=v]), the :first-child,
+// :last-child and :nth-child(n) pseudo-classes, the descendant, child (>),
+// adjacent (+) and sibling (~) combinators, and comma separated groups.
+//
+// XPath expressions are location paths of / and // steps with a name or *,
+// optionally filtered by predicates: [n], [last()], [@a], [@a='v'],
+// [contains(@a,'v')], [starts-with(@a,'v')], [text()='v'] and
+// [contains(text(),'v')]. The last step may be @a or text() to read an
+// attribute or the text instead of the element.
+package selector
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Query selects elements and reads a value from each
+type Query struct {
+	match func(root *html.Node) []*html.Node
+	// Attr reads this attribute of the matched elements instead of their text
+	Attr string
+}
+
+// Nodes returns the matched elements in document order
+func (q *Query) Nodes(root *html.Node) []*html.Node {
+	return q.match(root)
+}
+
+// Values returns the whitespace normalized text of every match, or the value
+// of Attr for the matches that have it
+func (q *Query) Values(root *html.Node) []string {
+	var values []string
+	for _, n := range q.match(root) {
+		if q.Attr == "" {
+			values = append(values, Text(n))
+			continue
+		}
+		for _, attr := range n.Attr {
+			if attr.Key == q.Attr {
+				values = append(values, strings.TrimSpace(attr.Val))
+				break
+			}
+		}
+	}
+	return values
+}
+
+// Text returns the whitespace normalized text inside a node
+func Text(n *html.Node) string {
+	var buf strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			buf.WriteString(n.Data)
+			buf.WriteString(" ")
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return strings.Join(strings.Fields(buf.String()), " ")
+}
+
+// attr returns the value of an attribute and whether it is set
+func attr(n *html.Node, key string) (string, bool) {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val, true
+		}
+	}
+	return "", false
+}
+
+// elementChildren returns the element children of n
+func elementChildren(n *html.Node) []*html.Node {
+	var children []*html.Node
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode {
+			children = append(children, c)
+		}
+	}
+	return children
+}
+
+// walkElements calls fn for every element below n in document order
+func walkElements(n *html.Node, fn func(*html.Node)) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode {
+			fn(c)
+		}
+		walkElements(c, fn)
+	}
+}
@@ -0,0 +1,99 @@
+package selector
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/html"
+)
+
+const page = `<html><body>
+	<nav><a href="/">Home</a><a href="/shop" class="active">Shop</a></nav>
+	<div id="product" class="card featured">
+		<h1>  Coffee   grinder </h1>
+		<span class="price" data-currency="EUR">49.90</span>
+		<ul><li>Steel</li><li>Ceramic</li><li lang="en-GB">Manual</li></ul>
+		<a href="https://example.com/reviews" rel="nofollow noopener">Reviews</a>
+	</div>
+</body></html>`
+
+func parse(t *testing.T) *html.Node {
+	doc, err := html.Parse(strings.NewReader(page))
+	require.NoError(t, err)
+	return doc
+}
+
+func TestCSS(t *testing.T) {
+	doc := parse(t)
+
+	tests := []struct {
+		selector string
+		attr     string
+		want     []string
+	}{
+		{"h1", "", []string{"Coffee grinder"}},
+		{"#product .price", "", []string{"49.90"}},
+		{"div.card.featured > span", "data-currency", []string{"EUR"}},
+		{"nav a", "href", []string{"/", "/shop"}},
+		{"nav > a.active", "", []string{"Shop"}},
+		{"a[href^='https:']", "", []string{"Reviews"}},
+		{`a[rel~="noopener"]`, "", []string{"Reviews"}},
+		{"[href$=shop]", "", []string{"Shop"}},
+		{"[href*=example]", "", []string{"Reviews"}},
+		{"li[lang|=en]", "", []string{"Manual"}},
+		{"li:first-child, li:last-child", "", []string{"Steel", "Manual"}},
+		{"li:nth-child(2)", "", []string{"Ceramic"}},
+		{"h1 + span", "", []string{"49.90"}},
+		{"h1 ~ a", "", []string{"Reviews"}},
+		{"*[id]", "id", []string{"product"}},
+		{"table td", "", nil},
+	}
+	for _, tt := range tests {
+		query, err := CSS(tt.selector)
+		require.NoError(t, err, tt.selector)
+		query.Attr = tt.attr
+		assert.Equal(t, tt.want, query.Values(doc), tt.selector)
+	}
+
+	for _, invalid := range []string{"", "a,", "div >", "a[href", "a[href!=x]", "a:hover", "li:nth-child(0)", "#", "a)", `a[title="x]`} {
+		_, err := CSS(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestXPath(t *testing.T) {
+	doc := parse(t)
+
+	tests := []struct {
+		expr string
+		want []string
+	}{
+		{"//h1", []string{"Coffee grinder"}},
+		{"/html/body/div/h1/text()", []string{"Coffee grinder"}},
+		{"//span[@class='price']", []string{"49.90"}},
+		{`//span[@class="price"]/@data-currency`, []string{"EUR"}},
+		{"//nav/a/@href", []string{"/", "/shop"}},
+		{"//ul/li[2]", []string{"Ceramic"}},
+		{"//li[last()]", []string{"Manual"}},
+		{"//a[contains(@href, 'example')]", []string{"Reviews"}},
+		{"//a[starts-with(@href,'/s')]", []string{"Shop"}},
+		{"//li[text()='Steel']", []string{"Steel"}},
+		{"//li[contains(., 'ram')]", []string{"Ceramic"}},
+		{"//div[@id]//li[@lang]", []string{"Manual"}},
+		{"//*[@class='active']", []string{"Shop"}},
+		{"//body//div//li[1]", []string{"Steel"}},
+		{"//table", nil},
+	}
+	for _, tt := range tests {
+		query, err := XPath(tt.expr)
+		require.NoError(t, err, tt.expr)
+		assert.Equal(t, tt.want, query.Values(doc), tt.expr)
+	}
+
+	for _, invalid := range []string{"", "//", "//a[", "//a[0]", "//a[position() < 3]", "//a//@href", "@href", "//a/text()/b", "//a]"} {
+		_, err := XPath(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
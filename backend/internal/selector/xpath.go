@@ -0,0 +1,236 @@
+package selector
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// xpathStep is one step of a location path, e.g. //div[@class='price']
+type xpathStep struct {
+	// descendant is set for steps reached with // instead of /
+	descendant bool
+	// name is the element name, or * for any element
+	name       string
+	predicates []xpathPredicate
+}
+
+// xpathPredicate filters the elements of a step
+type xpathPredicate struct {
+	// position keeps the nth element, -1 the last one; 0 tests a value instead
+	position int
+	// fn is "exists", "=", "contains" or "starts-with"
+	fn string
+	// attr is the attribute tested, or empty for the element's text
+	attr  string
+	value string
+}
+
+var (
+	xpathName     = regexp.MustCompile(`^(\*|[A-Za-z_][\w.-]*)`)
+	xpathExists   = regexp.MustCompile(`^@([A-Za-z_][\w:.-]*)$`)
+	xpathEquals   = regexp.MustCompile(`^(@[A-Za-z_][\w:.-]*|text\(\)|\.)\s*=\s*("[^"]*"|'[^']*')$`)
+	xpathFunction = regexp.MustCompile(`^(contains|starts-with)\(\s*(@[A-Za-z_][\w:.-]*|text\(\)|\.)\s*,\s*("[^"]*"|'[^']*')\s*\)$`)
+)
+
+// XPath compiles a location path. A final @name step sets the query's Attr;
+// a final text() step, like a path ending with an element, reads the text.
+func XPath(expr string) (*Query, error) {
+	s := strings.TrimSpace(expr)
+	invalid := func(format string, args ...interface{}) error {
+		return fmt.Errorf("invalid XPath %q: %s", expr, fmt.Sprintf(format, args...))
+	}
+
+	query := &Query{}
+	var steps []xpathStep
+	for s != "" {
+		var step xpathStep
+		switch {
+		case strings.HasPrefix(s, "//"):
+			step.descendant = true
+			s = s[2:]
+		case strings.HasPrefix(s, "/"):
+			s = s[1:]
+		case len(steps) > 0:
+			return nil, invalid("expected / before %q", s)
+		}
+
+		if len(steps) > 0 && (strings.HasPrefix(s, "@") || s == "text()") {
+			if step.descendant {
+				return nil, invalid("%s must follow a single /", s)
+			}
+			if strings.HasPrefix(s, "@") {
+				match := xpathExists.FindStringSubmatch(s)
+				if match == nil {
+					return nil, invalid("expected an attribute name after @")
+				}
+				query.Attr = strings.ToLower(match[1])
+			}
+			break
+		}
+
+		name := xpathName.FindString(s)
+		if name == "" {
+			return nil, invalid("expected an element name at %q", s)
+		}
+		step.name = strings.ToLower(name)
+		s = s[len(name):]
+
+		for strings.HasPrefix(s, "[") {
+			end := predicateEnd(s)
+			if end < 0 {
+				return nil, invalid("unterminated predicate")
+			}
+			predicate, err := parsePredicate(strings.TrimSpace(s[1:end]))
+			if err != nil {
+				return nil, invalid("%v", err)
+			}
+			step.predicates = append(step.predicates, predicate)
+			s = s[end+1:]
+		}
+		steps = append(steps, step)
+	}
+	if len(steps) == 0 {
+		return nil, invalid("expected a location path")
+	}
+
+	query.match = func(root *html.Node) []*html.Node {
+		return evaluatePath(root, steps)
+	}
+	return query, nil
+}
+
+// predicateEnd returns the index of the ] closing the predicate s starts
+// with, skipping quoted strings, or -1
+func predicateEnd(s string) int {
+	var quote byte
+	for i := 1; i < len(s); i++ {
+		switch {
+		case quote != 0:
+			if s[i] == quote {
+				quote = 0
+			}
+		case s[i] == '"' || s[i] == '\'':
+			quote = s[i]
+		case s[i] == ']':
+			return i
+		}
+	}
+	return -1
+}
+
+func parsePredicate(s string) (xpathPredicate, error) {
+	if s == "last()" {
+		return xpathPredicate{position: -1}, nil
+	}
+	if n, err := strconv.Atoi(s); err == nil {
+		if n < 1 {
+			return xpathPredicate{}, fmt.Errorf("positions start at 1")
+		}
+		return xpathPredicate{position: n}, nil
+	}
+	if match := xpathExists.FindStringSubmatch(s); match != nil {
+		return xpathPredicate{fn: "exists", attr: strings.ToLower(match[1])}, nil
+	}
+	if match := xpathEquals.FindStringSubmatch(s); match != nil {
+		return xpathPredicate{fn: "=", attr: operandAttr(match[1]), value: unquote(match[2])}, nil
+	}
+	if match := xpathFunction.FindStringSubmatch(s); match != nil {
+		return xpathPredicate{fn: match[1], attr: operandAttr(match[2]), value: unquote(match[3])}, nil
+	}
+	return xpathPredicate{}, fmt.Errorf("unsupported predicate [%s]", s)
+}
+
+// operandAttr returns the attribute name of an @name operand, or empty for text() and .
+func operandAttr(operand string) string {
+	if strings.HasPrefix(operand, "@") {
+		return strings.ToLower(operand[1:])
+	}
+	return ""
+}
+
+func unquote(s string) string {
+	return s[1 : len(s)-1]
+}
+
+// evaluatePath applies the steps starting from root and returns the matched
+// elements in document order
+func evaluatePath(root *html.Node, steps []xpathStep) []*html.Node {
+	context := []*html.Node{root}
+	for _, step := range steps {
+		var next []*html.Node
+		seen := make(map[*html.Node]bool)
+		// With //, a context inside the subtree of an earlier one adds nothing
+		covered := make(map[*html.Node]bool)
+
+		for _, node := range context {
+			parents := []*html.Node{node}
+			if step.descendant {
+				if covered[node] {
+					continue
+				}
+				walkElements(node, func(n *html.Node) {
+					covered[n] = true
+					parents = append(parents, n)
+				})
+			}
+
+			for _, parent := range parents {
+				var candidates []*html.Node
+				for _, child := range elementChildren(parent) {
+					if step.name == "*" || child.Data == step.name {
+						candidates = append(candidates, child)
+					}
+				}
+				for _, predicate := range step.predicates {
+					candidates = predicate.filter(candidates)
+				}
+				for _, candidate := range candidates {
+					if !seen[candidate] {
+						seen[candidate] = true
+						next = append(next, candidate)
+					}
+				}
+			}
+		}
+		context = next
+	}
+	return context
+}
+
+func (p xpathPredicate) filter(nodes []*html.Node) []*html.Node {
+	switch {
+	case p.position == -1:
+		if len(nodes) == 0 {
+			return nil
+		}
+		return nodes[len(nodes)-1:]
+	case p.position > 0:
+		if p.position > len(nodes) {
+			return nil
+		}
+		return nodes[p.position-1 : p.position]
+	}
+
+	var kept []*html.Node
+	for _, n := range nodes {
+		value, ok := Text(n), true
+		if p.attr != "" {
+			value, ok = attr(n, p.attr)
+		}
+		if !ok {
+			continue
+		}
+		switch {
+		case p.fn == "exists",
+			p.fn == "=" && value == p.value,
+			p.fn == "contains" && strings.Contains(value, p.value),
+			p.fn == "starts-with" && strings.HasPrefix(value, p.value):
+			kept = append(kept, n)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,101 @@
+// Package server runs the HTTP API, optionally terminating TLS itself so
+// small deployments don't need a reverse proxy in front of it.
+package server
+
+import (
+	"crypto/tls"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// TLSOptions configures TLS termination. Certificates come either from files
+// or from Let's Encrypt for the listed domains; with neither the API is
+// served over plain HTTP.
+type TLSOptions struct {
+	CertFile string
+	KeyFile  string
+	// AutocertDomains are the host names certificates are requested for
+	AutocertDomains []string
+	// AutocertCacheDir keeps issued certificates across restarts
+	AutocertCacheDir string
+	// AutocertEmail is given to Let's Encrypt for expiry notices; optional
+	AutocertEmail string
+	// RedirectAddr runs a plain HTTP listener, e.g. ":80", that redirects to
+	// HTTPS and answers ACME challenges; empty runs none
+	RedirectAddr string
+}
+
+// Enabled reports whether the API is served over TLS
+func (o TLSOptions) Enabled() bool {
+	return o.CertFile != "" || len(o.AutocertDomains) > 0
+}
+
+// ListenAndServe serves handler on addr until it fails, over TLS if enabled,
+// together with the redirect listener
+func ListenAndServe(addr string, handler http.Handler, options TLSOptions) error {
+	srv := &http.Server{Addr: addr, Handler: handler}
+	if !options.Enabled() {
+		return srv.ListenAndServe()
+	}
+
+	redirect := RedirectToHTTPS(addr)
+	srv.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	if len(options.AutocertDomains) > 0 {
+		manager := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(options.AutocertDomains...),
+			Cache:      autocert.DirCache(options.AutocertCacheDir),
+			Email:      options.AutocertEmail,
+		}
+		srv.TLSConfig = manager.TLSConfig()
+		srv.TLSConfig.MinVersion = tls.VersionTLS12
+		redirect = manager.HTTPHandler(redirect)
+	}
+
+	if options.RedirectAddr != "" {
+		go func() {
+			log.Printf("Redirecting HTTP on %s to HTTPS", options.RedirectAddr)
+			if err := http.ListenAndServe(options.RedirectAddr, redirect); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Printf("HTTP redirect listener failed: %v", err)
+			}
+		}()
+	}
+
+	// The certificate files are empty with autocert, whose TLSConfig supplies them
+	return srv.ListenAndServeTLS(options.CertFile, options.KeyFile)
+}
+
+// RedirectToHTTPS redirects every request to the same URL over HTTPS on the
+// port of httpsAddr. Safe methods get a 301; others a 308, so clients repeat
+// them with their body.
+func RedirectToHTTPS(httpsAddr string) http.Handler {
+	_, port, err := net.SplitHostPort(httpsAddr)
+	if err != nil || port == "443" {
+		port = ""
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if name, _, err := net.SplitHostPort(host); err == nil {
+			host = name
+		}
+		if strings.Contains(host, ":") {
+			// IPv6 literal
+			host = "[" + host + "]"
+		}
+		if port != "" {
+			host += ":" + port
+		}
+
+		status := http.StatusPermanentRedirect
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			status = http.StatusMovedPermanently
+		}
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), status)
+	})
+}
@@ -0,0 +1,39 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedirectToHTTPS(t *testing.T) {
+	tests := []struct {
+		name      string
+		httpsAddr string
+		method    string
+		target    string
+		status    int
+		location  string
+	}{
+		{"default port", ":443", http.MethodGet, "http://example.com/api/v1/urls?page=2", http.StatusMovedPermanently, "https://example.com/api/v1/urls?page=2"},
+		{"custom port", ":8443", http.MethodGet, "http://example.com:8080/healthz", http.StatusMovedPermanently, "https://example.com:8443/healthz"},
+		{"body is kept", ":443", http.MethodPost, "http://example.com/api/v1/urls", http.StatusPermanentRedirect, "https://example.com/api/v1/urls"},
+		{"IPv6 host", ":443", http.MethodHead, "http://[::1]:80/", http.StatusMovedPermanently, "https://[::1]/"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			RedirectToHTTPS(tt.httpsAddr).ServeHTTP(w, httptest.NewRequest(tt.method, tt.target, nil))
+			assert.Equal(t, tt.status, w.Code)
+			assert.Equal(t, tt.location, w.Header().Get("Location"))
+		})
+	}
+}
+
+func TestTLSOptions_Enabled(t *testing.T) {
+	assert.False(t, TLSOptions{RedirectAddr: ":80"}.Enabled())
+	assert.True(t, TLSOptions{CertFile: "cert.pem", KeyFile: "key.pem"}.Enabled())
+	assert.True(t, TLSOptions{AutocertDomains: []string{"crawler.example.com"}}.Enabled())
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/html"
+
+	"web-crawler-backend/internal/models"
+)
+
+// unlabelledInputTypes are input types that don't need a label
+var unlabelledInputTypes = map[string]bool{
+	"hidden": true,
+	"submit": true,
+	"button": true,
+	"image":  true,
+	"reset":  true,
+}
+
+// checkAccessibility runs basic WCAG checks over a parsed page
+func (s *CrawlerService) checkAccessibility(doc *html.Node, data *CrawlData) {
+	var issues []models.AccessibilityIssue
+	addIssue := func(rule, wcag string, n *html.Node, message string) {
+		issues = append(issues, models.AccessibilityIssue{
+			Rule:    rule,
+			WCAG:    wcag,
+			Element: describeElement(n),
+			Message: message,
+		})
+	}
+
+	labelledIDs := make(map[string]bool)
+	var controls []*html.Node
+	lastHeading := 0
+
+	walkNodes(doc, func(n *html.Node) {
+		if n.Type != html.ElementNode {
+			return
+		}
+
+		switch n.Data {
+		case "html":
+			if strings.TrimSpace(getAttr(n, "lang")) == "" {
+				addIssue(models.A11yRuleHTMLLang, "3.1.1", n, "The page doesn't declare its language")
+			}
+		case "img":
+			if _, ok := attrValue(n, "alt"); !ok {
+				addIssue(models.A11yRuleImageAlt, "1.1.1", n, "Image has no alt attribute")
+			}
+		case "label":
+			if target := getAttr(n, "for"); target != "" {
+				labelledIDs[target] = true
+			}
+		case "input", "select", "textarea":
+			if n.Data != "input" || !unlabelledInputTypes[strings.ToLower(getAttr(n, "type"))] {
+				controls = append(controls, n)
+			}
+		case "h1", "h2", "h3", "h4", "h5", "h6":
+			level := int(n.Data[1] - '0')
+			if lastHeading > 0 && level > lastHeading+1 {
+				addIssue(models.A11yRuleHeadingOrder, "1.3.1", n, fmt.Sprintf("Heading level skips from h%d to h%d", lastHeading, level))
+			}
+			lastHeading = level
+		}
+	})
+
+	// Labels may come after their controls, so controls are checked once the whole page is seen
+	for _, control := range controls {
+		if !hasAccessibleLabel(control, labelledIDs) {
+			addIssue(models.A11yRuleFormLabel, "1.3.1", control, "Form control has no label")
+		}
+	}
+
+	data.AccessibilityIssues = issues
+}
+
+// hasAccessibleLabel reports whether a form control is labelled by a <label> or ARIA attribute
+func hasAccessibleLabel(n *html.Node, labelledIDs map[string]bool) bool {
+	if id := getAttr(n, "id"); id != "" && labelledIDs[id] {
+		return true
+	}
+	for _, key := range []string{"aria-label", "aria-labelledby", "title"} {
+		if strings.TrimSpace(getAttr(n, key)) != "" {
+			return true
+		}
+	}
+	for p := n.Parent; p != nil; p = p.Parent {
+		if p.Type == html.ElementNode && p.Data == "label" {
+			return true
+		}
+	}
+	return false
+}
+
+// attrValue returns an attribute's value and whether it is present at all
+func attrValue(n *html.Node, key string) (string, bool) {
+	for _, attr := range n.Attr {
+		if attr.Key == key {
+			return attr.Val, true
+		}
+	}
+	return "", false
+}
+
+// describeElement renders a short opening tag identifying the element
+func describeElement(n *html.Node) string {
+	var b strings.Builder
+	b.WriteString("<" + n.Data)
+	for _, key := range []string{"id", "name", "type", "src"} {
+		if value := getAttr(n, key); value != "" {
+			if len(value) > 100 {
+				value = value[:100] + "..."
+			}
+			fmt.Fprintf(&b, " %s=%q", key, value)
+		}
+	}
+	b.WriteString(">")
+	return b.String()
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/html"
+
+	"web-crawler-backend/internal/models"
+)
+
+func accessibilityRules(issues []models.AccessibilityIssue) []string {
+	rules := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		rules = append(rules, issue.Rule)
+	}
+	return rules
+}
+
+func TestCrawlerService_checkAccessibility(t *testing.T) {
+	db := setupCrawlerTestDB(t)
+	service := NewCrawlerService(db)
+
+	check := func(t *testing.T, content string) []models.AccessibilityIssue {
+		doc, err := html.Parse(strings.NewReader(content))
+		require.NoError(t, err)
+
+		data := &CrawlData{}
+		service.checkAccessibility(doc, data)
+		return data.AccessibilityIssues
+	}
+
+	t.Run("accessible page has no issues", func(t *testing.T) {
+		issues := check(t, `<html lang="en"><body>
+			<h1>Title</h1><h2>Section</h2><h3>Sub</h3><h2>Next</h2>
+			<img src="/a.png" alt="Chart"><img src="/b.png" alt="">
+			<form>
+				<label for="email">Email</label><input id="email" type="email">
+				<label>Name <input type="text" name="name"></label>
+				<input type="search" aria-label="Search">
+				<textarea title="Comment"></textarea>
+				<input type="hidden" name="token"><input type="submit" value="Send">
+			</form>
+		</body></html>`)
+		assert.Empty(t, issues)
+	})
+
+	t.Run("reports each rule", func(t *testing.T) {
+		issues := check(t, `<html><body>
+			<h1>Title</h1><h4>Skipped</h4>
+			<img src="/chart.png">
+			<form><input id="q" type="text" name="q"><select name="country"></select></form>
+		</body></html>`)
+
+		assert.Equal(t, []string{
+			models.A11yRuleHTMLLang,
+			models.A11yRuleHeadingOrder,
+			models.A11yRuleImageAlt,
+			models.A11yRuleFormLabel,
+			models.A11yRuleFormLabel,
+		}, accessibilityRules(issues))
+		assert.Equal(t, "Heading level skips from h1 to h4", issues[1].Message)
+		assert.Equal(t, `<img src="/chart.png">`, issues[2].Element)
+		assert.Equal(t, `<input id="q" name="q" type="text">`, issues[3].Element)
+		assert.Equal(t, "1.1.1", issues[2].WCAG)
+	})
+
+	t.Run("label may follow its control", func(t *testing.T) {
+		issues := check(t, `<html lang="pl"><body><input id="later" type="text"><label for="later">Later</label></body></html>`)
+		assert.Empty(t, issues)
+	})
+}
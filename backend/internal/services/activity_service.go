@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"gorm.io/gorm"
+
+	"web-crawler-backend/internal/models"
+)
+
+type ActivityService struct {
+	db *gorm.DB
+}
+
+func NewActivityService(db *gorm.DB) *ActivityService {
+	return &ActivityService{db: db}
+}
+
+// WithContext returns a copy of the service whose queries run with ctx, so
+// they are traced with the request and cancelled at its deadline
+func (s *ActivityService) WithContext(ctx context.Context) *ActivityService {
+	return &ActivityService{db: s.db.WithContext(ctx)}
+}
+
+// GetFeed returns the user's most recent activity, newest first. An empty
+// types list includes every event type.
+func (s *ActivityService) GetFeed(userID uint, types []string, limit, offset int) ([]*models.ActivityEvent, int64, error) {
+	var events []*models.ActivityEvent
+	var total int64
+
+	query := s.db.Model(&models.ActivityEvent{}).Where("user_id = ?", userID)
+	if len(types) > 0 {
+		query = query.Where("type IN ?", types)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count activity: %w", err)
+	}
+
+	if err := query.Order("created_at DESC, id DESC").Limit(limit).Offset(offset).Find(&events).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch activity: %w", err)
+	}
+
+	return events, total, nil
+}
+
+// recordActivity stores an activity event. Failures are logged rather than
+// returned so they never break the action being recorded.
+func recordActivity(db *gorm.DB, event models.ActivityEvent, metadata map[string]interface{}) {
+	if len(metadata) > 0 {
+		encoded, _ := json.Marshal(metadata)
+		event.Metadata = string(encoded)
+	}
+
+	if err := db.Create(&event).Error; err != nil {
+		log.Printf("Failed to record %s activity: %v", event.Type, err)
+	}
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"web-crawler-backend/internal/models"
+)
+
+func TestActivityService_GetFeed(t *testing.T) {
+	db := setupURLTestDB(t)
+	service := NewActivityService(db)
+
+	base := time.Now().Add(-time.Hour)
+	events := []models.ActivityEvent{
+		{UserID: 1, Type: models.ActivityURLAdded, Message: "first", CreatedAt: base},
+		{UserID: 1, Type: models.ActivityCrawlCompleted, Message: "second", CreatedAt: base.Add(time.Minute)},
+		{UserID: 1, Type: models.ActivityCrawlFailed, Message: "third", CreatedAt: base.Add(2 * time.Minute)},
+		{UserID: 2, Type: models.ActivityURLAdded, Message: "someone else", CreatedAt: base.Add(3 * time.Minute)},
+	}
+	require.NoError(t, db.Create(&events).Error)
+
+	t.Run("returns the caller's events newest first", func(t *testing.T) {
+		feed, total, err := service.GetFeed(1, nil, 2, 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), total)
+		require.Len(t, feed, 2)
+		assert.Equal(t, "third", feed[0].Message)
+		assert.Equal(t, "second", feed[1].Message)
+	})
+
+	t.Run("filters by type", func(t *testing.T) {
+		feed, total, err := service.GetFeed(1, []string{models.ActivityCrawlCompleted, models.ActivityCrawlFailed}, 20, 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+		assert.Len(t, feed, 2)
+	})
+}
+
+func TestActivityRecording(t *testing.T) {
+	t.Run("adding a URL is recorded for the user", func(t *testing.T) {
+		db := setupURLTestDB(t)
+		service := NewURLService(db, &mockCrawlerService{})
+
+		url, err := service.CreateURLForUser("https://example.com", 7)
+		require.NoError(t, err)
+
+		var event models.ActivityEvent
+		require.NoError(t, db.Where("user_id = ?", 7).First(&event).Error)
+		assert.Equal(t, models.ActivityURLAdded, event.Type)
+		assert.Equal(t, url.ID, *event.URLID)
+		assert.Equal(t, uint(7), *event.ActorID)
+	})
+
+	t.Run("finished crawls are recorded for the URL owner", func(t *testing.T) {
+		db := setupCrawlerTestDB(t)
+		options := DefaultCrawlerOptions()
+		options.MaxRetries = 0 // Fail on the first 500
+		service := NewCrawlerServiceWithOptions(db, options)
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		owner := uint(3)
+		urlRecord := &models.URL{URL: server.URL, Status: "pending", UserID: &owner}
+		require.NoError(t, db.Create(urlRecord).Error)
+
+		service.StartCrawl(urlRecord.ID)
+
+		var event models.ActivityEvent
+		require.NoError(t, db.Where("user_id = ?", owner).First(&event).Error)
+		assert.Equal(t, models.ActivityCrawlFailed, event.Type)
+		assert.Contains(t, event.Metadata, "HTTP 500")
+	})
+}
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"web-crawler-backend/internal/models"
+)
+
+var (
+	// ErrAnnotationNotFound is returned when an annotation doesn't exist for the URL
+	ErrAnnotationNotFound = errors.New("annotation not found")
+	// ErrInvalidAnnotation is returned for unknown finding types or statuses
+	ErrInvalidAnnotation = errors.New("invalid annotation")
+)
+
+// annotatableFindings lists the finding types that can be annotated
+var annotatableFindings = map[string]bool{
+	models.FindingBrokenLink:         true,
+	models.FindingAccessibilityIssue: true,
+	models.FindingMixedContent:       true,
+}
+
+// annotationStatuses lists the statuses an annotation can have
+var annotationStatuses = map[string]bool{
+	models.AnnotationAcknowledged:  true,
+	models.AnnotationFalsePositive: true,
+	models.AnnotationAccepted:      true,
+	models.AnnotationIgnored:       true,
+}
+
+// maxFindingKeyLength is the size of the finding_key column
+const maxFindingKeyLength = 768
+
+type AnnotationService struct {
+	db *gorm.DB
+}
+
+func NewAnnotationService(db *gorm.DB) *AnnotationService {
+	return &AnnotationService{db: db}
+}
+
+// WithContext returns a copy of the service whose queries run with ctx, so
+// they are traced with the request and cancelled at its deadline
+func (s *AnnotationService) WithContext(ctx context.Context) *AnnotationService {
+	return &AnnotationService{db: s.db.WithContext(ctx)}
+}
+
+// ListAnnotations returns every annotation of a URL
+func (s *AnnotationService) ListAnnotations(urlID uint) ([]models.FindingAnnotation, error) {
+	if err := s.db.First(&models.URL{}, urlID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrURLNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch URL: %w", err)
+	}
+
+	var annotations []models.FindingAnnotation
+	if err := s.db.Where("url_id = ?", urlID).Order("created_at DESC").Find(&annotations).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch annotations: %w", err)
+	}
+	return annotations, nil
+}
+
+// Annotate marks a finding as known with a note, replacing an earlier annotation of the same finding
+func (s *AnnotationService) Annotate(urlID, userID uint, req models.FindingAnnotationRequest) (*models.FindingAnnotation, error) {
+	if !annotatableFindings[req.FindingType] {
+		return nil, fmt.Errorf("%w: unknown finding type %q", ErrInvalidAnnotation, req.FindingType)
+	}
+	if !annotationStatuses[req.Status] {
+		return nil, fmt.Errorf("%w: status must be %q, %q, %q or %q", ErrInvalidAnnotation,
+			models.AnnotationAcknowledged, models.AnnotationFalsePositive, models.AnnotationAccepted, models.AnnotationIgnored)
+	}
+	if len(req.FindingKey) > maxFindingKeyLength {
+		return nil, fmt.Errorf("%w: finding key is longer than %d characters", ErrInvalidAnnotation, maxFindingKeyLength)
+	}
+	if strings.TrimSpace(req.Reason) == "" {
+		return nil, fmt.Errorf("%w: a reason is required", ErrInvalidAnnotation)
+	}
+
+	var url models.URL
+	if err := s.db.First(&url, urlID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrURLNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch URL: %w", err)
+	}
+
+	var annotation models.FindingAnnotation
+	err := s.db.Where("url_id = ? AND finding_type = ? AND finding_key_hash = ?", urlID, req.FindingType, models.HashFindingKey(req.FindingKey)).
+		First(&annotation).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to fetch annotation: %w", err)
+	}
+
+	annotation.URLID = urlID
+	annotation.FindingType = req.FindingType
+	annotation.FindingKey = req.FindingKey
+	annotation.Status = req.Status
+	annotation.Reason = strings.TrimSpace(req.Reason)
+	annotation.CreatedBy = userID
+	if err := s.db.Save(&annotation).Error; err != nil {
+		return nil, fmt.Errorf("failed to save annotation: %w", err)
+	}
+
+	recordActivity(s.db, models.ActivityEvent{
+		UserID:  userID,
+		ActorID: &userID,
+		Type:    models.ActivityFindingAnnotated,
+		URLID:   &urlID,
+		Message: fmt.Sprintf("Marked %s %s as %s on %s", strings.ReplaceAll(req.FindingType, "_", " "), req.FindingKey, strings.ReplaceAll(req.Status, "_", " "), url.URL),
+	}, map[string]interface{}{
+		"annotation_id": annotation.ID,
+		"finding_type":  annotation.FindingType,
+		"finding_key":   annotation.FindingKey,
+		"status":        annotation.Status,
+		"reason":        annotation.Reason,
+	})
+
+	return &annotation, nil
+}
+
+// DeleteAnnotation removes an annotation so the finding counts again
+func (s *AnnotationService) DeleteAnnotation(urlID, annotationID, userID uint) error {
+	var annotation models.FindingAnnotation
+	if err := s.db.Where("id = ? AND url_id = ?", annotationID, urlID).First(&annotation).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrAnnotationNotFound
+		}
+		return fmt.Errorf("failed to fetch annotation: %w", err)
+	}
+
+	if err := s.db.Delete(&annotation).Error; err != nil {
+		return fmt.Errorf("failed to delete annotation: %w", err)
+	}
+
+	recordActivity(s.db, models.ActivityEvent{
+		UserID:  userID,
+		ActorID: &userID,
+		Type:    models.ActivityFindingCleared,
+		URLID:   &urlID,
+		Message: fmt.Sprintf("Cleared annotation of %s %s", strings.ReplaceAll(annotation.FindingType, "_", " "), annotation.FindingKey),
+	}, map[string]interface{}{
+		"annotation_id": annotation.ID,
+		"finding_type":  annotation.FindingType,
+		"finding_key":   annotation.FindingKey,
+		"status":        annotation.Status,
+		"reason":        annotation.Reason,
+	})
+
+	return nil
+}
+
+// loadAnnotations returns a URL's annotations of one finding type keyed by finding key
+func loadAnnotations(db *gorm.DB, urlID uint, findingType string) (map[string]*models.FindingAnnotation, error) {
+	var annotations []models.FindingAnnotation
+	if err := db.Where("url_id = ? AND finding_type = ?", urlID, findingType).Find(&annotations).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch annotations: %w", err)
+	}
+
+	byKey := make(map[string]*models.FindingAnnotation, len(annotations))
+	for i := range annotations {
+		byKey[annotations[i].FindingKey] = &annotations[i]
+	}
+	return byKey, nil
+}
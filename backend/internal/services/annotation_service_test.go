@@ -0,0 +1,177 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"web-crawler-backend/internal/models"
+)
+
+func TestAnnotationService(t *testing.T) {
+	setup := func(t *testing.T) (*AnnotationService, *URLService, *models.URL) {
+		db := setupURLTestDB(t)
+		url := &models.URL{URL: "https://example.com", Status: "completed"}
+		require.NoError(t, db.Create(url).Error)
+		crawl := &models.Crawl{URLID: url.ID, Status: "completed"}
+		require.NoError(t, db.Create(crawl).Error)
+		require.NoError(t, db.Create(&[]models.Link{
+			{URLID: url.ID, CrawlID: crawl.ID, LinkURL: "https://partner.example.org/old", LinkType: "external", StatusCode: 404, IsAccessible: false},
+			{URLID: url.ID, CrawlID: crawl.ID, LinkURL: "https://example.com/gone", LinkType: "internal", StatusCode: 410, IsAccessible: false},
+		}).Error)
+		return NewAnnotationService(db), NewURLService(db, &mockCrawlerService{}), url
+	}
+
+	request := models.FindingAnnotationRequest{
+		FindingType: models.FindingBrokenLink,
+		FindingKey:  "https://partner.example.org/old",
+		Status:      models.AnnotationIgnored,
+		Reason:      "Partner site blocks crawlers",
+	}
+
+	t.Run("annotations are attached to broken links", func(t *testing.T) {
+		service, urlService, url := setup(t)
+
+		annotation, err := service.Annotate(url.ID, 4, request)
+		require.NoError(t, err)
+		assert.Equal(t, uint(4), annotation.CreatedBy)
+
+		links, _, err := urlService.GetURLLinks(url.ID, "broken", 50, 0)
+		require.NoError(t, err)
+		annotated := 0
+		for _, link := range links {
+			if link.Annotation != nil {
+				annotated++
+				assert.Equal(t, request.FindingKey, link.LinkURL)
+				assert.Equal(t, models.AnnotationIgnored, link.Annotation.Status)
+			}
+		}
+		assert.Equal(t, 1, annotated)
+	})
+
+	t.Run("re-annotating replaces the previous annotation", func(t *testing.T) {
+		service, _, url := setup(t)
+
+		_, err := service.Annotate(url.ID, 4, request)
+		require.NoError(t, err)
+
+		accepted := request
+		accepted.Status = models.AnnotationAccepted
+		accepted.Reason = "Removed from the roadmap"
+		_, err = service.Annotate(url.ID, 5, accepted)
+		require.NoError(t, err)
+
+		annotations, err := service.ListAnnotations(url.ID)
+		require.NoError(t, err)
+		require.Len(t, annotations, 1)
+		assert.Equal(t, models.AnnotationAccepted, annotations[0].Status)
+		assert.Equal(t, uint(5), annotations[0].CreatedBy)
+
+		// Keys are unique by their hash, so long keys sharing a prefix stay apart
+		long := request
+		long.FindingKey = "https://example.com/" + strings.Repeat("a", 700) + "/1"
+		_, err = service.Annotate(url.ID, 5, long)
+		require.NoError(t, err)
+		long.FindingKey = strings.TrimSuffix(long.FindingKey, "1") + "2"
+		_, err = service.Annotate(url.ID, 5, long)
+		require.NoError(t, err)
+		annotations, err = service.ListAnnotations(url.ID)
+		require.NoError(t, err)
+		assert.Len(t, annotations, 3)
+		assert.Equal(t, models.HashFindingKey(annotations[0].FindingKey), annotations[0].FindingKeyHash)
+	})
+
+	t.Run("changes are recorded in the audit trail", func(t *testing.T) {
+		service, _, url := setup(t)
+
+		annotation, err := service.Annotate(url.ID, 4, request)
+		require.NoError(t, err)
+		require.NoError(t, service.DeleteAnnotation(url.ID, annotation.ID, 6))
+		assert.ErrorIs(t, service.DeleteAnnotation(url.ID, annotation.ID, 6), ErrAnnotationNotFound)
+
+		var events []models.ActivityEvent
+		require.NoError(t, service.db.Order("id").Find(&events).Error)
+		require.Len(t, events, 2)
+		assert.Equal(t, models.ActivityFindingAnnotated, events[0].Type)
+		assert.Equal(t, uint(4), *events[0].ActorID)
+		assert.Contains(t, events[0].Metadata, "Partner site blocks crawlers")
+		assert.Equal(t, models.ActivityFindingCleared, events[1].Type)
+		assert.Equal(t, uint(6), *events[1].ActorID)
+	})
+
+	t.Run("annotated issues persist across crawls and stop counting", func(t *testing.T) {
+		service, urlService, url := setup(t)
+		db := service.db
+
+		issues := func() {
+			crawl := &models.Crawl{URLID: url.ID, Status: "completed"}
+			require.NoError(t, db.Create(crawl).Error)
+			require.NoError(t, db.Create(&[]models.AccessibilityIssue{
+				{URLID: url.ID, CrawlID: crawl.ID, Rule: models.A11yRuleImageAlt, Element: `<img src="/logo.png">`},
+				{URLID: url.ID, CrawlID: crawl.ID, Rule: models.A11yRuleImageAlt, Element: `<img src="/chart.png">`},
+			}).Error)
+			require.NoError(t, db.Create(&[]models.MixedContentIssue{
+				{URLID: url.ID, CrawlID: crawl.ID, ResourceType: models.MixedContentImage, ResourceURL: "http://example.com/a.png"},
+			}).Error)
+		}
+		issues()
+
+		_, err := service.Annotate(url.ID, 4, models.FindingAnnotationRequest{
+			FindingType: models.FindingAccessibilityIssue,
+			FindingKey:  models.AccessibilityIssue{Rule: models.A11yRuleImageAlt, Element: `<img src="/logo.png">`}.FindingKey(),
+			Status:      models.AnnotationFalsePositive,
+			Reason:      "The logo has a text label next to it",
+		})
+		require.NoError(t, err)
+		_, err = service.Annotate(url.ID, 4, models.FindingAnnotationRequest{
+			FindingType: models.FindingMixedContent,
+			FindingKey:  "http://example.com/a.png",
+			Status:      models.AnnotationAcknowledged,
+			Reason:      "Moves to https with the CDN migration",
+		})
+		require.NoError(t, err)
+
+		// A recrawl reports the same issues again
+		issues()
+
+		accessibility, err := urlService.GetAccessibilityReport(url.ID, 0, "")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{models.A11yRuleImageAlt: 1}, accessibility.Counts)
+		assert.Equal(t, 1, accessibility.Annotated)
+		require.Len(t, accessibility.Issues, 2)
+		require.NotNil(t, accessibility.Issues[0].Annotation)
+		assert.Equal(t, models.AnnotationFalsePositive, accessibility.Issues[0].Annotation.Status)
+		assert.Nil(t, accessibility.Issues[1].Annotation)
+
+		mixed, err := urlService.GetMixedContentReport(url.ID, 0, "")
+		require.NoError(t, err)
+		assert.Empty(t, mixed.Counts)
+		assert.Equal(t, 1, mixed.Annotated)
+		require.Len(t, mixed.Issues, 1)
+		assert.Equal(t, "Moves to https with the CDN migration", mixed.Issues[0].Annotation.Reason)
+	})
+
+	t.Run("validates the request", func(t *testing.T) {
+		service, _, url := setup(t)
+
+		invalid := request
+		invalid.FindingType = "typo"
+		_, err := service.Annotate(url.ID, 4, invalid)
+		assert.ErrorIs(t, err, ErrInvalidAnnotation)
+
+		invalid = request
+		invalid.Status = "fixed"
+		_, err = service.Annotate(url.ID, 4, invalid)
+		assert.ErrorIs(t, err, ErrInvalidAnnotation)
+
+		invalid = request
+		invalid.FindingKey = strings.Repeat("a", 769)
+		_, err = service.Annotate(url.ID, 4, invalid)
+		assert.ErrorIs(t, err, ErrInvalidAnnotation)
+
+		_, err = service.Annotate(999, 4, request)
+		assert.ErrorIs(t, err, ErrURLNotFound)
+	})
+}
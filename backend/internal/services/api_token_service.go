@@ -0,0 +1,165 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+
+	"web-crawler-backend/internal/models"
+)
+
+var (
+	// ErrAPITokenNotFound is returned for tokens that don't exist, were
+	// revoked, or that the user may not manage
+	ErrAPITokenNotFound = errors.New("API token not found")
+	// ErrInvalidAPIToken is returned for unknown, revoked and expired tokens
+	ErrInvalidAPIToken = errors.New("invalid API token")
+)
+
+// apiTokenBytes is the number of random bytes in an API token
+const apiTokenBytes = 32
+
+// apiTokenPrefixLength is how much of a token is kept to recognize it by
+const apiTokenPrefixLength = 12
+
+// APITokenService issues the API tokens of users and organizations and
+// authenticates the requests made with them
+type APITokenService struct {
+	db  *gorm.DB
+	now func() time.Time
+}
+
+func NewAPITokenService(db *gorm.DB) *APITokenService {
+	return &APITokenService{db: db, now: time.Now}
+}
+
+// WithContext returns a copy of the service whose queries run with ctx, so
+// they are traced as part of the request
+func (s *APITokenService) WithContext(ctx context.Context) *APITokenService {
+	return &APITokenService{db: s.db.WithContext(ctx), now: s.now}
+}
+
+// CreateToken issues a token acting as the user. Tokens of an organization
+// need the user to be one of its admins.
+func (s *APITokenService) CreateToken(userID uint, req *models.CreateAPITokenRequest) (*models.CreatedAPIToken, error) {
+	if req.OrganizationID != 0 {
+		if err := s.checkOrgAdmin(req.OrganizationID, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	secret := make([]byte, apiTokenBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate API token: %w", err)
+	}
+	plain := models.APITokenPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	token := models.APIToken{
+		UserID:         userID,
+		OrganizationID: req.OrganizationID,
+		Name:           req.Name,
+		Scope:          req.Scope,
+		Prefix:         plain[:apiTokenPrefixLength],
+		TokenHash:      hashAPIToken(plain),
+	}
+	if req.ExpiresInDays > 0 {
+		expiresAt := s.now().AddDate(0, 0, req.ExpiresInDays)
+		token.ExpiresAt = &expiresAt
+	}
+	if err := s.db.Create(&token).Error; err != nil {
+		return nil, fmt.Errorf("failed to create API token: %w", err)
+	}
+	return &models.CreatedAPIToken{APIToken: token, Token: plain}, nil
+}
+
+// ListTokens returns the active tokens the user created, or with an
+// organization, the active tokens of that organization for its admins
+func (s *APITokenService) ListTokens(userID, orgID uint) ([]models.APIToken, error) {
+	query := s.db.Where("revoked_at IS NULL")
+	if orgID != 0 {
+		if err := s.checkOrgAdmin(orgID, userID); err != nil {
+			return nil, err
+		}
+		query = query.Where("organization_id = ?", orgID)
+	} else {
+		query = query.Where("user_id = ?", userID)
+	}
+
+	tokens := []models.APIToken{}
+	if err := query.Order("created_at DESC, id DESC").Find(&tokens).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch API tokens: %w", err)
+	}
+	return tokens, nil
+}
+
+// RevokeToken revokes a token the user created, or a token of an
+// organization the user administers
+func (s *APITokenService) RevokeToken(userID, tokenID uint) error {
+	var token models.APIToken
+	if err := s.db.Where("id = ? AND revoked_at IS NULL", tokenID).First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrAPITokenNotFound
+		}
+		return fmt.Errorf("failed to fetch API token: %w", err)
+	}
+	if token.UserID != userID {
+		if token.OrganizationID == 0 || s.checkOrgAdmin(token.OrganizationID, userID) != nil {
+			return ErrAPITokenNotFound
+		}
+	}
+
+	if err := s.db.Model(&token).Update("revoked_at", s.now()).Error; err != nil {
+		return fmt.Errorf("failed to revoke API token: %w", err)
+	}
+	return nil
+}
+
+// Authenticate returns the active token matching plain, recording its use at
+// most once per sessionTouchInterval
+func (s *APITokenService) Authenticate(plain string) (*models.APIToken, error) {
+	now := s.now()
+	var token models.APIToken
+	err := s.db.Where("token_hash = ? AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", hashAPIToken(plain), now).
+		First(&token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidAPIToken
+		}
+		return nil, fmt.Errorf("failed to fetch API token: %w", err)
+	}
+
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= sessionTouchInterval {
+		if err := s.db.Model(&token).Update("last_used_at", now).Error; err != nil {
+			log.Printf("Failed to record use of API token %d: %v", token.ID, err)
+		}
+	}
+	return &token, nil
+}
+
+// checkOrgAdmin returns ErrOrganizationNotFound if the user is not a member
+// of the organization, and ErrOrgPermission if they are not an admin
+func (s *APITokenService) checkOrgAdmin(orgID, userID uint) error {
+	membership, err := NewOrganizationService(s.db).GetMembership(orgID, userID)
+	if err != nil {
+		return err
+	}
+	if !membership.Role.AtLeast(models.OrgRoleAdmin) {
+		return ErrOrgPermission
+	}
+	return nil
+}
+
+// hashAPIToken returns the hash a token is stored and looked up by. Tokens
+// are random, so a fast unsalted hash is enough.
+func hashAPIToken(plain string) string {
+	sum := sha256.Sum256([]byte(plain))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"web-crawler-backend/internal/models"
+)
+
+func TestAPITokenService(t *testing.T) {
+	orgs, db, users := setupOrganizationTest(t)
+	require.NoError(t, db.AutoMigrate(&models.APIToken{}))
+	alice, bob := users[0], users[1]
+	service := NewAPITokenService(db)
+
+	org, err := orgs.CreateOrganization(alice.ID, "Acme")
+	require.NoError(t, err)
+	require.NoError(t, db.Create(&models.Membership{OrganizationID: org.ID, UserID: bob.ID, Role: models.OrgRoleMember}).Error)
+
+	personal, err := service.CreateToken(bob.ID, &models.CreateAPITokenRequest{Name: "CI", Scope: models.APIScopeCrawl})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(personal.Token, models.APITokenPrefix))
+	assert.True(t, strings.HasPrefix(personal.Token, personal.Prefix))
+	assert.NotContains(t, personal.TokenHash, personal.Token, "only a hash is stored")
+	assert.Nil(t, personal.ExpiresAt)
+
+	t.Run("organization tokens need an admin", func(t *testing.T) {
+		_, err := service.CreateToken(bob.ID, &models.CreateAPITokenRequest{Name: "Acme", Scope: models.APIScopeRead, OrganizationID: org.ID})
+		assert.ErrorIs(t, err, ErrOrgPermission)
+		_, err = service.CreateToken(users[2].ID, &models.CreateAPITokenRequest{Name: "Acme", Scope: models.APIScopeRead, OrganizationID: org.ID})
+		assert.ErrorIs(t, err, ErrOrganizationNotFound)
+	})
+
+	shared, err := service.CreateToken(alice.ID, &models.CreateAPITokenRequest{Name: "Acme", Scope: models.APIScopeRead, OrganizationID: org.ID, ExpiresInDays: 30})
+	require.NoError(t, err)
+	require.NotNil(t, shared.ExpiresAt)
+
+	t.Run("authenticates active tokens", func(t *testing.T) {
+		token, err := service.Authenticate(personal.Token)
+		require.NoError(t, err)
+		assert.Equal(t, personal.ID, token.ID)
+		assert.Equal(t, models.APIScopeCrawl, token.Scope)
+		assert.NotNil(t, token.LastUsedAt)
+
+		_, err = service.Authenticate(models.APITokenPrefix + "guess")
+		assert.ErrorIs(t, err, ErrInvalidAPIToken)
+
+		later := NewAPITokenService(db)
+		later.now = func() time.Time { return time.Now().AddDate(0, 0, 31) }
+		_, err = later.Authenticate(shared.Token)
+		assert.ErrorIs(t, err, ErrInvalidAPIToken, "expired")
+	})
+
+	t.Run("lists the tokens of the user or organization", func(t *testing.T) {
+		tokens, err := service.ListTokens(bob.ID, 0)
+		require.NoError(t, err)
+		require.Len(t, tokens, 1)
+		assert.Equal(t, personal.ID, tokens[0].ID)
+
+		tokens, err = service.ListTokens(alice.ID, org.ID)
+		require.NoError(t, err)
+		require.Len(t, tokens, 1)
+		assert.Equal(t, shared.ID, tokens[0].ID)
+
+		_, err = service.ListTokens(bob.ID, org.ID)
+		assert.ErrorIs(t, err, ErrOrgPermission)
+	})
+
+	t.Run("revokes tokens", func(t *testing.T) {
+		assert.ErrorIs(t, service.RevokeToken(alice.ID, personal.ID), ErrAPITokenNotFound, "tokens of other users")
+		require.NoError(t, service.RevokeToken(bob.ID, personal.ID))
+		_, err := service.Authenticate(personal.Token)
+		assert.ErrorIs(t, err, ErrInvalidAPIToken)
+		assert.ErrorIs(t, service.RevokeToken(bob.ID, personal.ID), ErrAPITokenNotFound, "already revoked")
+
+		assert.ErrorIs(t, service.RevokeToken(bob.ID, shared.ID), ErrAPITokenNotFound, "members can't revoke organization tokens")
+		require.NoError(t, service.RevokeToken(alice.ID, shared.ID))
+	})
+}
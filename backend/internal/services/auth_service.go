@@ -1,39 +1,163 @@
 package services
 
 import (
+	"context"
+	"crypto/rand"
 	"errors"
 	"fmt"
+	"log"
+	"net/http"
 	"time"
 
-	"github.com/dgrijalva/jwt-go"
-	"golang.org/x/crypto/bcrypt"
+	"github.com/golang-jwt/jwt/v5"
 	"gorm.io/gorm"
 
+	"web-crawler-backend/internal/apperror"
 	"web-crawler-backend/internal/models"
+	"web-crawler-backend/internal/password"
+	"web-crawler-backend/internal/repository"
 )
 
+// Defaults of AuthOptions
+const (
+	defaultTokenLifetime = 24 * time.Hour
+	defaultClockSkew     = 30 * time.Second
+)
+
+// sessionTouchInterval bounds how often the last use of a session is written
+const sessionTouchInterval = time.Minute
+
+// maxUserAgentLength is the longest user agent stored with a session
+const maxUserAgentLength = 512
+
 var (
-	jwtSecret = []byte("your-secret-key") // In production, use environment variable
+	// ErrUserExists is returned when registering a taken username or email
+	ErrUserExists = apperror.New(http.StatusConflict, "Registration failed", "username or email already exists").WithCode("user_exists")
+	// ErrInvalidCredentials is returned for unknown users and wrong passwords alike
+	ErrInvalidCredentials = apperror.New(http.StatusUnauthorized, "Login failed", "invalid credentials").WithCode("invalid_credentials")
+	// ErrAccountNotFound is returned for users that don't exist or were deactivated
+	ErrAccountNotFound = apperror.New(http.StatusNotFound, "Failed to get profile", "user not found").WithCode("account_not_found")
+	// ErrSessionNotFound is returned for sessions of other users and sessions that ended
+	ErrSessionNotFound = apperror.New(http.StatusNotFound, "Session not found", "The session does not exist or has ended").WithCode("session_not_found")
+	// ErrSessionEnded is returned for tokens of revoked or expired sessions
+	ErrSessionEnded = errors.New("session has been revoked or has expired")
 )
 
+// AuthOptions configures the tokens of the auth service
+type AuthOptions struct {
+	// Secret signs and validates the tokens; empty uses a random key, so
+	// tokens don't survive restarts and aren't shared between instances
+	Secret string
+	// TokenLifetime is how long tokens stay valid, and sessions stay active
+	// without a refreshed token; 0 uses 24 hours
+	TokenLifetime time.Duration
+	// Issuer is set as the iss claim of new tokens and, when not empty,
+	// required of the tokens validated
+	Issuer string
+	// ClockSkew is the leeway given to the expiry and issue times of tokens,
+	// for servers whose clocks differ; 0 uses 30 seconds
+	ClockSkew time.Duration
+}
+
 type AuthService struct {
-	db *gorm.DB
+	store     repository.Store
+	passwords *password.Hasher
+	options   AuthOptions
+	// secret is the key tokens are signed with
+	secret []byte
+	// apiTokens authenticates API tokens; nil accepts only logins
+	apiTokens *APITokenService
+	// userAgent and ipAddress describe the client new sessions are started for
+	userAgent string
+	ipAddress string
 }
 
 func NewAuthService(db *gorm.DB) *AuthService {
-	return &AuthService{db: db}
+	return NewAuthServiceWithStore(repository.NewStore(db))
+}
+
+// NewAuthServiceWithStore creates an auth service that keeps users in store
+func NewAuthServiceWithStore(store repository.Store) *AuthService {
+	return &AuthService{store: store, passwords: password.Default(), secret: randomSecret()}
+}
+
+// randomSecret returns a key for services configured without a secret
+func randomSecret() []byte {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		panic(fmt.Sprintf("failed to generate token secret: %v", err))
+	}
+	return secret
+}
+
+// WithOptions returns a copy of the service issuing and validating tokens
+// with options
+func (s *AuthService) WithOptions(options AuthOptions) *AuthService {
+	copied := *s
+	copied.options = options
+	if options.Secret != "" {
+		copied.secret = []byte(options.Secret)
+	}
+	return &copied
+}
+
+// tokenLifetime returns how long tokens stay valid
+func (s *AuthService) tokenLifetime() time.Duration {
+	if s.options.TokenLifetime <= 0 {
+		return defaultTokenLifetime
+	}
+	return s.options.TokenLifetime
+}
+
+// WithClient returns a copy of the service that records the user agent and
+// IP address of the client with the sessions it starts
+func (s *AuthService) WithClient(userAgent, ipAddress string) *AuthService {
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+	copied := *s
+	copied.userAgent = userAgent
+	copied.ipAddress = ipAddress
+	return &copied
+}
+
+// WithPasswords returns a copy of the service that hashes passwords with
+// passwords. Hashes made by other algorithms or with other costs still
+// verify and are replaced on login.
+func (s *AuthService) WithPasswords(passwords *password.Hasher) *AuthService {
+	copied := *s
+	copied.passwords = passwords
+	return &copied
+}
+
+// WithAPITokens returns a copy of the service that also accepts the API
+// tokens of apiTokens
+func (s *AuthService) WithAPITokens(apiTokens *APITokenService) *AuthService {
+	copied := *s
+	copied.apiTokens = apiTokens
+	return &copied
+}
+
+// WithContext returns a copy of the service whose queries are traced as part of
+// ctx and cancelled at its deadline
+func (s *AuthService) WithContext(ctx context.Context) *AuthService {
+	copied := *s
+	copied.store = s.store.WithContext(ctx)
+	if s.apiTokens != nil {
+		copied.apiTokens = s.apiTokens.WithContext(ctx)
+	}
+	return &copied
 }
 
 // Register creates a new user account
 func (s *AuthService) Register(req *models.RegisterRequest) (*models.User, error) {
 	// Check if username already exists
-	var existingUser models.User
-	if err := s.db.Where("username = ? OR email = ?", req.Username, req.Email).First(&existingUser).Error; err == nil {
-		return nil, errors.New("username or email already exists")
+	if _, err := s.store.Users().FindByUsernameOrEmail(req.Username, req.Email); err == nil {
+		return nil, ErrUserExists
 	}
 
 	// Hash password
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	hashedPassword, err := s.passwords.Hash(req.Password)
 	if err != nil {
 		return nil, fmt.Errorf("failed to hash password: %v", err)
 	}
@@ -42,15 +166,15 @@ func (s *AuthService) Register(req *models.RegisterRequest) (*models.User, error
 	user := models.User{
 		Username:  req.Username,
 		Email:     req.Email,
-		Password:  string(hashedPassword),
+		Password:  hashedPassword,
 		FirstName: req.FirstName,
 		LastName:  req.LastName,
 		IsActive:  true,
 		IsAdmin:   false, // Default to non-admin
 	}
 
-	if err := s.db.Create(&user).Error; err != nil {
-		return nil, fmt.Errorf("failed to create user: %v", err)
+	if err := s.store.Users().Create(&user); err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
 
 	// Don't return password in response
@@ -60,23 +184,31 @@ func (s *AuthService) Register(req *models.RegisterRequest) (*models.User, error
 
 // Login authenticates a user and returns JWT token
 func (s *AuthService) Login(req *models.LoginRequest) (*models.AuthResponse, error) {
-	var user models.User
-	
 	// Find user by username
-	if err := s.db.Where("username = ? AND is_active = ?", req.Username, true).First(&user).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("invalid credentials")
+	user, err := s.store.Users().FindActiveByUsername(req.Username)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrInvalidCredentials
 		}
-		return nil, fmt.Errorf("database error: %v", err)
+		return nil, fmt.Errorf("database error: %w", err)
 	}
 
 	// Check password
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
-		return nil, errors.New("invalid credentials")
+	rehash, err := s.passwords.Verify(user.Password, req.Password)
+	if err != nil {
+		return nil, ErrInvalidCredentials
+	}
+	if rehash {
+		s.rehashPassword(user.ID, req.Password)
+	}
+
+	session, err := s.startSession(user.ID)
+	if err != nil {
+		return nil, err
 	}
 
 	// Generate JWT token
-	token, err := s.generateJWTToken(&user)
+	token, err := s.generateJWTToken(user, session.ID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate token: %v", err)
 	}
@@ -85,29 +217,54 @@ func (s *AuthService) Login(req *models.LoginRequest) (*models.AuthResponse, err
 	user.Password = ""
 
 	return &models.AuthResponse{
-		Token: token,
-		User:  &user,
+		Token:     token,
+		ExpiresAt: session.ExpiresAt,
+		User:      user,
 	}, nil
 }
 
-// ValidateToken validates JWT token and returns user claims
+// rehashPassword replaces a user's outdated password hash with one of the
+// current algorithm and cost. Failures are only logged, the old hash keeps
+// working until the next login.
+func (s *AuthService) rehashPassword(userID uint, plain string) {
+	hash, err := s.passwords.Hash(plain)
+	if err == nil {
+		err = s.store.Users().SetPassword(userID, hash)
+	}
+	if err != nil {
+		log.Printf("Failed to rehash password of user %d: %v", userID, err)
+	}
+}
+
+// ValidateToken validates JWT token and returns user claims. Tokens must
+// expire, must not be issued in the future and, if the service has an
+// issuer, must be issued by it; expiry and issue times get the configured
+// clock skew as leeway.
 func (s *AuthService) ValidateToken(tokenString string) (*models.JWTClaims, error) {
+	clockSkew := s.options.ClockSkew
+	if clockSkew <= 0 {
+		clockSkew = defaultClockSkew
+	}
+	parserOptions := []jwt.ParserOption{jwt.WithExpirationRequired(), jwt.WithIssuedAt(), jwt.WithLeeway(clockSkew)}
+	if s.options.Issuer != "" {
+		parserOptions = append(parserOptions, jwt.WithIssuer(s.options.Issuer))
+	}
+
 	token, err := jwt.ParseWithClaims(tokenString, &models.JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
 		// Validate signing method
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
-		return jwtSecret, nil
-	})
+		return s.secret, nil
+	}, parserOptions...)
 
 	if err != nil {
 		return nil, fmt.Errorf("invalid token: %v", err)
 	}
 
 	if claims, ok := token.Claims.(*models.JWTClaims); ok && token.Valid {
-		// Check if token is expired
-		if claims.ExpiresAt < time.Now().Unix() {
-			return nil, errors.New("token has expired")
+		if err := s.checkSession(claims); err != nil {
+			return nil, err
 		}
 		return claims, nil
 	}
@@ -115,19 +272,46 @@ func (s *AuthService) ValidateToken(tokenString string) (*models.JWTClaims, erro
 	return nil, errors.New("invalid token claims")
 }
 
+// ValidateAPIToken authenticates an API token, returning the claims of its
+// user along with the token. Admin rights of the user only carry over to
+// tokens of the user with the admin scope, not to those of organizations.
+func (s *AuthService) ValidateAPIToken(plain string) (*models.JWTClaims, *models.APIToken, error) {
+	if s.apiTokens == nil {
+		return nil, nil, ErrInvalidAPIToken
+	}
+	token, err := s.apiTokens.Authenticate(plain)
+	if err != nil {
+		return nil, nil, err
+	}
+	user, err := s.store.Users().FindActiveByID(token.UserID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, nil, ErrInvalidAPIToken
+		}
+		return nil, nil, err
+	}
+
+	claims := &models.JWTClaims{
+		UserID:   user.ID,
+		Username: user.Username,
+		IsAdmin:  user.IsAdmin && token.OrganizationID == 0 && token.Scope.AtLeast(models.APIScopeAdmin),
+	}
+	return claims, token, nil
+}
+
 // GetUserByID retrieves user by ID
 func (s *AuthService) GetUserByID(userID uint) (*models.User, error) {
-	var user models.User
-	if err := s.db.Where("id = ? AND is_active = ?", userID, true).First(&user).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("user not found")
+	user, err := s.store.Users().FindActiveByID(userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrAccountNotFound
 		}
-		return nil, fmt.Errorf("database error: %v", err)
+		return nil, fmt.Errorf("database error: %w", err)
 	}
 
 	// Don't return password
 	user.Password = ""
-	return &user, nil
+	return user, nil
 }
 
 // RefreshToken generates a new JWT token for the user
@@ -143,32 +327,105 @@ func (s *AuthService) RefreshToken(tokenString string) (*models.AuthResponse, er
 		return nil, err
 	}
 
+	// Extend the session of the token
+	now := time.Now()
+	expiresAt := now.Add(s.tokenLifetime())
+	if err := s.store.Sessions().Extend(claims.SessionID, now, expiresAt); err != nil {
+		return nil, fmt.Errorf("failed to extend session: %w", err)
+	}
+
 	// Generate new token
-	newToken, err := s.generateJWTToken(user)
+	newToken, err := s.generateJWTToken(user, claims.SessionID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate new token: %v", err)
 	}
 
 	return &models.AuthResponse{
-		Token: newToken,
-		User:  user,
+		Token:     newToken,
+		ExpiresAt: expiresAt,
+		User:      user,
 	}, nil
 }
 
-// generateJWTToken creates a JWT token for the user
-func (s *AuthService) generateJWTToken(user *models.User) (string, error) {
-	// Set token expiration time (24 hours)
-	expirationTime := time.Now().Add(24 * time.Hour)
+// ListSessions returns the active sessions of a user, most recently used
+// first, marking the session of the current request
+func (s *AuthService) ListSessions(userID, currentSessionID uint) ([]models.Session, error) {
+	sessions, err := s.store.Sessions().ListActive(userID, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == currentSessionID
+	}
+	return sessions, nil
+}
+
+// RevokeSession ends a session of a user, rejecting its tokens from now on
+func (s *AuthService) RevokeSession(userID, sessionID uint) error {
+	err := s.store.Sessions().Revoke(userID, sessionID, time.Now())
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrSessionNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	return nil
+}
+
+// startSession records a new session of a user on the service's client
+func (s *AuthService) startSession(userID uint) (*models.Session, error) {
+	now := time.Now()
+	session := &models.Session{
+		UserID:     userID,
+		UserAgent:  s.userAgent,
+		IPAddress:  s.ipAddress,
+		LastUsedAt: now,
+		ExpiresAt:  now.Add(s.tokenLifetime()),
+	}
+	if err := s.store.Sessions().Create(session); err != nil {
+		return nil, fmt.Errorf("failed to start session: %w", err)
+	}
+	return session, nil
+}
+
+// checkSession rejects tokens whose session was revoked or expired, and
+// records the use of the session. Tokens without a session can't be
+// revoked, so they are rejected too.
+func (s *AuthService) checkSession(claims *models.JWTClaims) error {
+	if claims.SessionID == 0 {
+		return ErrSessionEnded
+	}
+	now := time.Now()
+	session, err := s.store.Sessions().FindActive(claims.SessionID, now)
+	if errors.Is(err, repository.ErrNotFound) || (err == nil && session.UserID != claims.UserID) {
+		return ErrSessionEnded
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check session: %w", err)
+	}
+	if now.Sub(session.LastUsedAt) > sessionTouchInterval {
+		if err := s.store.Sessions().Touch(session.ID, now); err != nil {
+			log.Printf("Failed to record use of session %d: %v", session.ID, err)
+		}
+	}
+	return nil
+}
+
+// generateJWTToken creates a JWT token for the user in a session
+func (s *AuthService) generateJWTToken(user *models.User, sessionID uint) (string, error) {
+	now := time.Now()
 
 	// Create claims
 	claims := &models.JWTClaims{
-		UserID:   user.ID,
-		Username: user.Username,
-		IsAdmin:  user.IsAdmin,
-		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: expirationTime.Unix(),
-			IssuedAt:  time.Now().Unix(),
+		UserID:    user.ID,
+		Username:  user.Username,
+		IsAdmin:   user.IsAdmin,
+		SessionID: sessionID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    s.options.Issuer,
 			Subject:   fmt.Sprintf("%d", user.ID),
+			ExpiresAt: jwt.NewNumericDate(now.Add(s.tokenLifetime())),
+			IssuedAt:  jwt.NewNumericDate(now),
 		},
 	}
 
@@ -176,7 +433,7 @@ func (s *AuthService) generateJWTToken(user *models.User) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
 	// Sign token
-	tokenString, err := token.SignedString(jwtSecret)
+	tokenString, err := token.SignedString(s.secret)
 	if err != nil {
 		return "", fmt.Errorf("failed to sign token: %v", err)
 	}
@@ -1,18 +1,19 @@
 package services
 
 import (
+	"context"
 	"testing"
 	"time"
 
-	"github.com/dgrijalva/jwt-go"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
-	"golang.org/x/crypto/bcrypt"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 
 	"web-crawler-backend/internal/models"
+	"web-crawler-backend/internal/password"
 )
 
 func setupTestDB(t *testing.T) *gorm.DB {
@@ -22,7 +23,7 @@ func setupTestDB(t *testing.T) *gorm.DB {
 	require.NoError(t, err)
 
 	// Auto migrate the schema
-	err = db.AutoMigrate(&models.User{})
+	err = db.AutoMigrate(&models.User{}, &models.Session{})
 	require.NoError(t, err)
 
 	return db
@@ -272,14 +273,14 @@ func TestAuthService_ValidateToken(t *testing.T) {
 			UserID:   1,
 			Username: "testuser",
 			IsAdmin:  false,
-			StandardClaims: jwt.StandardClaims{
-				ExpiresAt: time.Now().Add(-time.Hour).Unix(), // Expired 1 hour ago
-				IssuedAt:  time.Now().Add(-2 * time.Hour).Unix(),
+			RegisteredClaims: jwt.RegisteredClaims{
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Hour)), // Expired 1 hour ago
+				IssuedAt:  jwt.NewNumericDate(time.Now().Add(-2 * time.Hour)),
 			},
 		}
 
 		token := jwt.NewWithClaims(jwt.SigningMethodHS256, expiredClaims)
-		tokenString, err := token.SignedString(jwtSecret)
+		tokenString, err := token.SignedString(authService.secret)
 		require.NoError(t, err)
 
 		claims, err := authService.ValidateToken(tokenString)
@@ -349,6 +350,17 @@ func TestAuthService_GetUserByID(t *testing.T) {
 		assert.Nil(t, user)
 		assert.Contains(t, err.Error(), "user not found")
 	})
+
+	t.Run("cancelled context", func(t *testing.T) {
+		db := setupTestDB(t)
+		authService := NewAuthService(db)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		user, err := authService.WithContext(ctx).GetUserByID(1)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, user)
+	})
 }
 
 func TestAuthService_RefreshToken(t *testing.T) {
@@ -385,7 +397,7 @@ func TestAuthService_RefreshToken(t *testing.T) {
 		claims, err := authService.ValidateToken(token2)
 		require.NoError(t, err)
 		assert.Equal(t, authResp.User.ID, claims.UserID)
-		assert.True(t, claims.ExpiresAt > time.Now().Unix())
+		assert.True(t, claims.ExpiresAt.After(time.Now()))
 	})
 
 	t.Run("invalid token refresh", func(t *testing.T) {
@@ -409,7 +421,9 @@ func TestGenerateJWTToken(t *testing.T) {
 			IsAdmin:  false,
 		}
 
-		token, err := authService.generateJWTToken(user)
+		session, err := authService.startSession(user.ID)
+		require.NoError(t, err)
+		token, err := authService.generateJWTToken(user, session.ID)
 		require.NoError(t, err)
 		assert.NotEmpty(t, token)
 
@@ -449,7 +463,117 @@ func TestPasswordHashing(t *testing.T) {
 		assert.NotEmpty(t, dbUser.Password)
 
 		// Should be able to verify the password
-		err = bcrypt.CompareHashAndPassword([]byte(dbUser.Password), []byte(plainPassword))
+		_, err = password.Default().Verify(dbUser.Password, plainPassword)
 		assert.NoError(t, err)
 	})
-} 
\ No newline at end of file
+} 
+func TestAuthService_Sessions(t *testing.T) {
+	db := setupTestDB(t)
+	authService := NewAuthService(db)
+	_, err := authService.Register(&models.RegisterRequest{Username: "alice", Email: "alice@example.com", Password: "password123"})
+	require.NoError(t, err)
+	login := &models.LoginRequest{Username: "alice", Password: "password123"}
+
+	laptop, err := authService.WithClient("Firefox", "203.0.113.7").Login(login)
+	require.NoError(t, err)
+	phone, err := authService.WithClient("Safari", "198.51.100.2").Login(login)
+	require.NoError(t, err)
+
+	claims, err := authService.ValidateToken(laptop.Token)
+	require.NoError(t, err)
+	require.NotZero(t, claims.SessionID)
+
+	sessions, err := authService.ListSessions(claims.UserID, claims.SessionID)
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+	for _, session := range sessions {
+		assert.Equal(t, session.ID == claims.SessionID, session.Current)
+		if session.Current {
+			assert.Equal(t, "Firefox", session.UserAgent)
+			assert.Equal(t, "203.0.113.7", session.IPAddress)
+		}
+	}
+
+	t.Run("sessions of other users can't be revoked", func(t *testing.T) {
+		assert.ErrorIs(t, authService.RevokeSession(claims.UserID+1, claims.SessionID), ErrSessionNotFound)
+	})
+
+	t.Run("revoked sessions reject their tokens", func(t *testing.T) {
+		require.NoError(t, authService.RevokeSession(claims.UserID, claims.SessionID))
+
+		_, err := authService.ValidateToken(laptop.Token)
+		assert.ErrorIs(t, err, ErrSessionEnded)
+		_, err = authService.RefreshToken(laptop.Token)
+		assert.ErrorIs(t, err, ErrSessionEnded)
+		assert.ErrorIs(t, authService.RevokeSession(claims.UserID, claims.SessionID), ErrSessionNotFound)
+
+		_, err = authService.ValidateToken(phone.Token)
+		assert.NoError(t, err, "other sessions stay active")
+		sessions, err := authService.ListSessions(claims.UserID, 0)
+		require.NoError(t, err)
+		require.Len(t, sessions, 1)
+		assert.Equal(t, "Safari", sessions[0].UserAgent)
+	})
+
+	t.Run("tokens without a session are rejected", func(t *testing.T) {
+		user, err := authService.GetUserByID(claims.UserID)
+		require.NoError(t, err)
+		legacy, err := authService.generateJWTToken(user, 0)
+		require.NoError(t, err)
+
+		_, err = authService.ValidateToken(legacy)
+		assert.ErrorIs(t, err, ErrSessionEnded)
+		_, err = authService.RefreshToken(legacy)
+		assert.ErrorIs(t, err, ErrSessionEnded)
+	})
+}
+
+func TestAuthService_WithOptions(t *testing.T) {
+	db := setupTestDB(t)
+	authService := NewAuthService(db).WithOptions(AuthOptions{TokenLifetime: time.Hour, Issuer: "crawler.example.com", ClockSkew: time.Minute})
+	user := &models.User{ID: 1, Username: "testuser"}
+	session, err := authService.startSession(user.ID)
+	require.NoError(t, err)
+
+	token, err := authService.generateJWTToken(user, session.ID)
+	require.NoError(t, err)
+	claims, err := authService.ValidateToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, "crawler.example.com", claims.Issuer)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), claims.ExpiresAt.Time, 2*time.Second)
+
+	sign := func(claims jwt.RegisteredClaims) string {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &models.JWTClaims{UserID: 1, SessionID: session.ID, RegisteredClaims: claims}).SignedString(authService.secret)
+		require.NoError(t, err)
+		return token
+	}
+	now := time.Now()
+
+	_, err = authService.ValidateToken(sign(jwt.RegisteredClaims{Issuer: "crawler.example.com", ExpiresAt: jwt.NewNumericDate(now.Add(-30 * time.Second))}))
+	assert.NoError(t, err, "tokens expired within the clock skew are accepted")
+
+	_, err = authService.ValidateToken(sign(jwt.RegisteredClaims{Issuer: "crawler.example.com", ExpiresAt: jwt.NewNumericDate(now.Add(-2 * time.Minute))}))
+	assert.ErrorContains(t, err, "expired")
+
+	_, err = authService.ValidateToken(sign(jwt.RegisteredClaims{Issuer: "crawler.example.com", IssuedAt: jwt.NewNumericDate(now.Add(time.Hour)), ExpiresAt: jwt.NewNumericDate(now.Add(2 * time.Hour))}))
+	assert.Error(t, err, "tokens issued in the future are rejected")
+
+	_, err = authService.ValidateToken(sign(jwt.RegisteredClaims{Issuer: "elsewhere.example.com", ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour))}))
+	assert.Error(t, err, "tokens of other issuers are rejected")
+
+	_, err = authService.ValidateToken(sign(jwt.RegisteredClaims{Issuer: "crawler.example.com"}))
+	assert.Error(t, err, "tokens must expire")
+
+	t.Run("secret", func(t *testing.T) {
+		signed := NewAuthService(db).WithOptions(AuthOptions{Secret: "a-long-enough-jwt-secret"})
+		token, err := signed.generateJWTToken(user, session.ID)
+		require.NoError(t, err)
+
+		_, err = NewAuthService(db).WithOptions(AuthOptions{Secret: "a-long-enough-jwt-secret"}).ValidateToken(token)
+		assert.NoError(t, err, "instances sharing the secret accept each other's tokens")
+		_, err = NewAuthService(db).WithOptions(AuthOptions{Secret: "another-jwt-secret"}).ValidateToken(token)
+		assert.ErrorContains(t, err, "signature is invalid")
+		_, err = NewAuthService(db).ValidateToken(token)
+		assert.Error(t, err, "services without a secret use their own key")
+	})
+}
@@ -0,0 +1,245 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"web-crawler-backend/internal/models"
+)
+
+var (
+	// ErrThresholdNotFound is returned when a URL or project has no broken link threshold
+	ErrThresholdNotFound = errors.New("broken link threshold not found")
+	// ErrInvalidThreshold is returned for thresholds without any limit
+	ErrInvalidThreshold = errors.New("invalid broken link threshold")
+)
+
+// BrokenLinkAlertService manages the broken link thresholds of URLs and
+// projects. The crawler checks them when a crawl completes.
+type BrokenLinkAlertService struct {
+	db *gorm.DB
+	// organizationID limits the service to the URLs and default of one
+	// organization, 0 for the ones outside any
+	organizationID uint
+}
+
+func NewBrokenLinkAlertService(db *gorm.DB) *BrokenLinkAlertService {
+	return &BrokenLinkAlertService{db: db}
+}
+
+// WithContext returns a copy of the service whose queries run with ctx
+func (s *BrokenLinkAlertService) WithContext(ctx context.Context) *BrokenLinkAlertService {
+	copied := *s
+	copied.db = s.db.WithContext(ctx)
+	return &copied
+}
+
+// WithOrganization returns a copy of the service limited to the organization
+func (s *BrokenLinkAlertService) WithOrganization(organizationID uint) *BrokenLinkAlertService {
+	copied := *s
+	copied.organizationID = organizationID
+	return &copied
+}
+
+// projectScope returns the scope of the project default: the organization, or
+// outside organizations, the user's own URLs
+func (s *BrokenLinkAlertService) projectScope(userID uint) models.BrokenLinkThreshold {
+	if s.organizationID != 0 {
+		return models.BrokenLinkThreshold{OrganizationID: s.organizationID}
+	}
+	return models.BrokenLinkThreshold{UserID: userID}
+}
+
+// urlScope returns the scope of a URL's threshold after checking the URL
+// belongs to the organization
+func (s *BrokenLinkAlertService) urlScope(urlID uint) (models.BrokenLinkThreshold, error) {
+	var url models.URL
+	if err := s.db.Select("id").Where("id = ? AND organization_id = ?", urlID, s.organizationID).First(&url).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.BrokenLinkThreshold{}, ErrURLNotFound
+		}
+		return models.BrokenLinkThreshold{}, fmt.Errorf("failed to fetch URL: %w", err)
+	}
+	return models.BrokenLinkThreshold{OrganizationID: s.organizationID, URLID: urlID}, nil
+}
+
+// GetURLThreshold returns the threshold of a URL itself, without the project default
+func (s *BrokenLinkAlertService) GetURLThreshold(urlID uint) (*models.BrokenLinkThreshold, error) {
+	scope, err := s.urlScope(urlID)
+	if err != nil {
+		return nil, err
+	}
+	return s.get(scope)
+}
+
+// SetURLThreshold creates or replaces the threshold of a URL
+func (s *BrokenLinkAlertService) SetURLThreshold(urlID uint, req models.BrokenLinkThresholdRequest) (*models.BrokenLinkThreshold, error) {
+	scope, err := s.urlScope(urlID)
+	if err != nil {
+		return nil, err
+	}
+	return s.set(scope, req)
+}
+
+// DeleteURLThreshold removes the threshold of a URL, so the project default applies again
+func (s *BrokenLinkAlertService) DeleteURLThreshold(urlID uint) error {
+	scope, err := s.urlScope(urlID)
+	if err != nil {
+		return err
+	}
+	return s.delete(scope)
+}
+
+// GetProjectThreshold returns the default threshold of the organization, or
+// of the user's URLs outside organizations
+func (s *BrokenLinkAlertService) GetProjectThreshold(userID uint) (*models.BrokenLinkThreshold, error) {
+	return s.get(s.projectScope(userID))
+}
+
+// SetProjectThreshold creates or replaces the default threshold
+func (s *BrokenLinkAlertService) SetProjectThreshold(userID uint, req models.BrokenLinkThresholdRequest) (*models.BrokenLinkThreshold, error) {
+	return s.set(s.projectScope(userID), req)
+}
+
+// DeleteProjectThreshold removes the default threshold
+func (s *BrokenLinkAlertService) DeleteProjectThreshold(userID uint) error {
+	return s.delete(s.projectScope(userID))
+}
+
+func (s *BrokenLinkAlertService) get(scope models.BrokenLinkThreshold) (*models.BrokenLinkThreshold, error) {
+	var threshold models.BrokenLinkThreshold
+	if err := s.db.Where("organization_id = ? AND user_id = ? AND url_id = ?", scope.OrganizationID, scope.UserID, scope.URLID).
+		First(&threshold).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrThresholdNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch broken link threshold: %w", err)
+	}
+	return &threshold, nil
+}
+
+func (s *BrokenLinkAlertService) set(scope models.BrokenLinkThreshold, req models.BrokenLinkThresholdRequest) (*models.BrokenLinkThreshold, error) {
+	if req.MaxBrokenLinks == nil && req.MaxBrokenPercent == nil {
+		return nil, fmt.Errorf("%w: set max_broken_links, max_broken_percent or both", ErrInvalidThreshold)
+	}
+
+	threshold := scope
+	threshold.MaxBrokenLinks = req.MaxBrokenLinks
+	threshold.MaxBrokenPercent = req.MaxBrokenPercent
+	if err := s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "organization_id"}, {Name: "user_id"}, {Name: "url_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"max_broken_links", "max_broken_percent", "updated_at"}),
+	}).Create(&threshold).Error; err != nil {
+		return nil, fmt.Errorf("failed to save broken link threshold: %w", err)
+	}
+	return s.get(scope)
+}
+
+func (s *BrokenLinkAlertService) delete(scope models.BrokenLinkThreshold) error {
+	result := s.db.Where("organization_id = ? AND user_id = ? AND url_id = ?", scope.OrganizationID, scope.UserID, scope.URLID).
+		Delete(&models.BrokenLinkThreshold{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete broken link threshold: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrThresholdNotFound
+	}
+	return nil
+}
+
+// thresholdFor returns the threshold that applies to a URL: its own, or the
+// default of its project. It returns nil if neither is set.
+func thresholdFor(db *gorm.DB, url *models.URL) (*models.BrokenLinkThreshold, error) {
+	scopes := db.Where("url_id = ?", url.ID)
+	if url.OrganizationID != 0 {
+		scopes = scopes.Or("organization_id = ? AND user_id = 0 AND url_id = 0", url.OrganizationID)
+	} else if url.UserID != nil {
+		scopes = scopes.Or("organization_id = 0 AND user_id = ? AND url_id = 0", *url.UserID)
+	}
+
+	var thresholds []models.BrokenLinkThreshold
+	if err := db.Where(scopes).Order("url_id DESC").Limit(1).Find(&thresholds).Error; err != nil {
+		return nil, err
+	}
+	if len(thresholds) == 0 {
+		return nil, nil
+	}
+	return &thresholds[0], nil
+}
+
+// annotatedBrokenLinks counts the broken links of a crawl that have an
+// annotation, such as ones accepted or ignored
+func annotatedBrokenLinks(db *gorm.DB, crawl *models.Crawl) (int, error) {
+	annotations, err := loadAnnotations(db, crawl.URLID, models.FindingBrokenLink)
+	if err != nil || len(annotations) == 0 {
+		return 0, err
+	}
+	keys := make([]string, 0, len(annotations))
+	for key := range annotations {
+		keys = append(keys, key)
+	}
+
+	var annotated int64
+	if err := db.Model(&models.Link{}).Where("crawl_id = ? AND is_accessible = ? AND link_url IN ?", crawl.ID, false, keys).
+		Count(&annotated).Error; err != nil {
+		return 0, fmt.Errorf("failed to count annotated links: %w", err)
+	}
+	return int(annotated), nil
+}
+
+// checkBrokenLinkThreshold flags the URL of a completed crawl that crossed
+// its broken link threshold and notifies the URL owner when the flag is
+// raised. Annotated broken links don't count towards the threshold. A later
+// crawl within the threshold clears the flag.
+func (s *CrawlerService) checkBrokenLinkThreshold(urlRecord *models.URL, crawl *models.Crawl) {
+	if crawl.Status != "completed" {
+		return
+	}
+	threshold, err := thresholdFor(s.db, urlRecord)
+	if err != nil {
+		log.Printf("Failed to fetch broken link threshold of URL %d: %v", urlRecord.ID, err)
+		return
+	}
+
+	var annotated int
+	if threshold != nil {
+		if annotated, err = annotatedBrokenLinks(s.db, crawl); err != nil {
+			log.Printf("Failed to count annotated broken links of URL %d: %v", urlRecord.ID, err)
+			return
+		}
+	}
+
+	broken := crawl.BrokenLinks - annotated
+	checked := crawl.InternalLinks + crawl.ExternalLinks
+	exceeded := threshold != nil && threshold.Exceeded(broken, checked)
+	if exceeded == urlRecord.NeedsAttention {
+		return
+	}
+	if err := s.db.Model(&models.URL{}).Where("id = ?", urlRecord.ID).Update("needs_attention", exceeded).Error; err != nil {
+		log.Printf("Failed to flag URL %d: %v", urlRecord.ID, err)
+		return
+	}
+	urlRecord.NeedsAttention = exceeded
+	if !exceeded || urlRecord.UserID == nil {
+		return
+	}
+
+	recordActivity(s.db, models.ActivityEvent{
+		UserID:  *urlRecord.UserID,
+		Type:    models.ActivityBrokenLinksAlert,
+		URLID:   &urlRecord.ID,
+		CrawlID: &crawl.ID,
+		Message: fmt.Sprintf("Crawl of %s found %d broken links, more than its threshold allows", urlRecord.URL, broken),
+	}, map[string]interface{}{
+		"broken_links":       broken,
+		"annotated_links":    annotated,
+		"checked_links":      checked,
+		"max_broken_links":   threshold.MaxBrokenLinks,
+		"max_broken_percent": threshold.MaxBrokenPercent,
+	})
+}
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"web-crawler-backend/internal/models"
+)
+
+func setupBrokenLinkAlertTest(t *testing.T) (*BrokenLinkAlertService, *gorm.DB) {
+	db := setupURLTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.BrokenLinkThreshold{}))
+	return NewBrokenLinkAlertService(db), db
+}
+
+func TestBrokenLinkThreshold_Exceeded(t *testing.T) {
+	five, ten := 5, 10.0
+	tests := []struct {
+		name           string
+		threshold      models.BrokenLinkThreshold
+		broken, total  int
+		expectExceeded bool
+	}{
+		{"count within", models.BrokenLinkThreshold{MaxBrokenLinks: &five}, 5, 10, false},
+		{"count above", models.BrokenLinkThreshold{MaxBrokenLinks: &five}, 6, 100, true},
+		{"ratio within", models.BrokenLinkThreshold{MaxBrokenPercent: &ten}, 10, 100, false},
+		{"ratio above", models.BrokenLinkThreshold{MaxBrokenPercent: &ten}, 3, 20, true},
+		{"ratio without links", models.BrokenLinkThreshold{MaxBrokenPercent: &ten}, 0, 0, false},
+		{"either limit", models.BrokenLinkThreshold{MaxBrokenLinks: &five, MaxBrokenPercent: &ten}, 2, 10, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectExceeded, tt.threshold.Exceeded(tt.broken, tt.total))
+		})
+	}
+}
+
+func TestBrokenLinkAlertService_Thresholds(t *testing.T) {
+	service, db := setupBrokenLinkAlertTest(t)
+	url := &models.URL{URL: "https://example.com"}
+	require.NoError(t, db.Create(url).Error)
+	five, ten := 5, 10.0
+
+	_, err := service.SetURLThreshold(url.ID, models.BrokenLinkThresholdRequest{})
+	assert.ErrorIs(t, err, ErrInvalidThreshold)
+
+	threshold, err := service.SetURLThreshold(url.ID, models.BrokenLinkThresholdRequest{MaxBrokenLinks: &five})
+	require.NoError(t, err)
+	assert.Equal(t, 5, *threshold.MaxBrokenLinks)
+
+	// Setting it again replaces both limits
+	threshold, err = service.SetURLThreshold(url.ID, models.BrokenLinkThresholdRequest{MaxBrokenPercent: &ten})
+	require.NoError(t, err)
+	assert.Nil(t, threshold.MaxBrokenLinks)
+	assert.Equal(t, 10.0, *threshold.MaxBrokenPercent)
+
+	_, err = service.WithOrganization(7).GetURLThreshold(url.ID)
+	assert.ErrorIs(t, err, ErrURLNotFound)
+
+	_, err = service.SetProjectThreshold(1, models.BrokenLinkThresholdRequest{MaxBrokenLinks: &five})
+	require.NoError(t, err)
+	_, err = service.GetProjectThreshold(2)
+	assert.ErrorIs(t, err, ErrThresholdNotFound, "personal defaults are per user")
+	_, err = service.WithOrganization(7).GetProjectThreshold(1)
+	assert.ErrorIs(t, err, ErrThresholdNotFound)
+
+	require.NoError(t, service.DeleteURLThreshold(url.ID))
+	assert.ErrorIs(t, service.DeleteURLThreshold(url.ID), ErrThresholdNotFound)
+}
+
+func TestCrawlerService_CheckBrokenLinkThreshold(t *testing.T) {
+	service, db := setupBrokenLinkAlertTest(t)
+	crawler := NewCrawlerService(db)
+	userID := uint(1)
+	url := &models.URL{URL: "https://example.com", UserID: &userID}
+	other := &models.URL{URL: "https://example.org", UserID: &userID}
+	require.NoError(t, db.Create(url).Error)
+	require.NoError(t, db.Create(other).Error)
+
+	two, fifty := 2, 50.0
+	_, err := service.SetProjectThreshold(userID, models.BrokenLinkThresholdRequest{MaxBrokenLinks: &two})
+	require.NoError(t, err)
+	_, err = service.SetURLThreshold(other.ID, models.BrokenLinkThresholdRequest{MaxBrokenPercent: &fifty})
+	require.NoError(t, err)
+
+	finish := func(url *models.URL, broken int) {
+		crawl := &models.Crawl{URLID: url.ID, Status: "completed", InternalLinks: 10, BrokenLinks: broken}
+		require.NoError(t, db.Create(crawl).Error)
+		crawler.checkBrokenLinkThreshold(url, crawl)
+	}
+	alerts := func() int64 {
+		var count int64
+		require.NoError(t, db.Model(&models.ActivityEvent{}).Where("type = ?", models.ActivityBrokenLinksAlert).Count(&count).Error)
+		return count
+	}
+	needsAttention := func(url *models.URL) bool {
+		var stored models.URL
+		require.NoError(t, db.First(&stored, url.ID).Error)
+		return stored.NeedsAttention
+	}
+
+	finish(url, 2)
+	assert.False(t, needsAttention(url))
+
+	finish(url, 3)
+	assert.True(t, needsAttention(url))
+	assert.Equal(t, int64(1), alerts())
+
+	// Still above it: the flag stays without another notification
+	finish(url, 4)
+	assert.Equal(t, int64(1), alerts())
+
+	finish(url, 1)
+	assert.False(t, needsAttention(url))
+
+	// The URL's own threshold replaces the default
+	finish(other, 3)
+	assert.False(t, needsAttention(other))
+	finish(other, 6)
+	assert.True(t, needsAttention(other))
+	assert.Equal(t, int64(2), alerts())
+
+	t.Run("filterable in URL lists", func(t *testing.T) {
+		filter, err := ParseURLFilter("needs_attention:true")
+		require.NoError(t, err)
+		urls, total, err := NewURLService(db, &mockCrawlerService{}).WithFilter(filter).GetURLs(20, 0, "", "", "created_at", "desc")
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		assert.Equal(t, other.ID, urls[0].ID)
+		assert.True(t, urls[0].NeedsAttention)
+	})
+}
+
+func TestCrawlerService_CheckBrokenLinkThreshold_Annotated(t *testing.T) {
+	service, db := setupBrokenLinkAlertTest(t)
+	crawler := NewCrawlerService(db)
+	userID := uint(1)
+	url := &models.URL{URL: "https://example.com", UserID: &userID}
+	require.NoError(t, db.Create(url).Error)
+
+	two := 2
+	_, err := service.SetURLThreshold(url.ID, models.BrokenLinkThresholdRequest{MaxBrokenLinks: &two})
+	require.NoError(t, err)
+	_, err = NewAnnotationService(db).Annotate(url.ID, userID, models.FindingAnnotationRequest{
+		FindingType: models.FindingBrokenLink,
+		FindingKey:  "https://example.com/gone",
+		Status:      models.AnnotationAccepted,
+		Reason:      "Removed on purpose",
+	})
+	require.NoError(t, err)
+
+	finish := func(brokenURLs ...string) {
+		crawl := &models.Crawl{URLID: url.ID, Status: "completed", InternalLinks: 10, BrokenLinks: len(brokenURLs)}
+		require.NoError(t, db.Create(crawl).Error)
+		for _, linkURL := range brokenURLs {
+			require.NoError(t, db.Create(&models.Link{URLID: url.ID, CrawlID: crawl.ID, LinkURL: linkURL}).Error)
+		}
+		crawler.checkBrokenLinkThreshold(url, crawl)
+		require.NoError(t, db.First(url, url.ID).Error)
+	}
+
+	// The accepted link leaves two broken links, within the threshold
+	finish("https://example.com/gone", "https://example.com/a", "https://example.com/b")
+	assert.False(t, url.NeedsAttention)
+
+	finish("https://example.com/gone", "https://example.com/a", "https://example.com/b", "https://example.com/c")
+	assert.True(t, url.NeedsAttention)
+
+	var event models.ActivityEvent
+	require.NoError(t, db.Where("type = ?", models.ActivityBrokenLinksAlert).First(&event).Error)
+	assert.Contains(t, event.Message, "found 3 broken links")
+}
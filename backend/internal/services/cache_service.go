@@ -0,0 +1,172 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+const (
+	// defaultCacheTTL bounds how long an entry is served when no TTL is configured
+	defaultCacheTTL = 30 * time.Second
+	// cacheKeyPrefix namespaces the keys of this application in Redis
+	cacheKeyPrefix = "webcrawler:cache:"
+	// cacheGenerationKey holds the generation counter that every cache key includes
+	cacheGenerationKey = cacheKeyPrefix + "generation"
+)
+
+// cachedTables are the tables behind the cached queries; writing to any of
+// them invalidates the cache
+var cachedTables = []string{"urls", "crawls", "links"}
+
+// CacheService caches the results of hot read queries, such as the URL list
+// and crawl status polled by dashboards, in Redis.
+//
+// Keys include a generation number and writes invalidate the whole cache by
+// incrementing it, so a result computed while a write happens is stored under
+// the old generation and never served. Entries also expire after the TTL,
+// which bounds staleness from writes the invalidation can't see.
+//
+// A nil *CacheService caches nothing, and Redis errors are logged and treated
+// as misses, so an unavailable Redis only costs database load.
+type CacheService struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+// NewRedisClient connects to Redis at redisURL, e.g. redis://localhost:6379/0
+func NewRedisClient(redisURL string) (*redis.Client, error) {
+	options, err := redis.ParseURL(redisURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Redis URL: %w", err)
+	}
+	client := redis.NewClient(options)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+	return client, nil
+}
+
+// NewCacheService creates a cache stored in Redis; a zero ttl keeps entries
+// for 30 seconds
+func NewCacheService(client *redis.Client, ttl time.Duration) *CacheService {
+	if ttl <= 0 {
+		ttl = defaultCacheTTL
+	}
+	return &CacheService{client: client, ttl: ttl}
+}
+
+// Invalidate drops every cached entry
+func (c *CacheService) Invalidate(ctx context.Context) {
+	if c == nil {
+		return
+	}
+	if err := c.client.Incr(ctx, cacheGenerationKey).Err(); err != nil {
+		log.Printf("Failed to invalidate cache: %v", err)
+	}
+}
+
+// get loads the entry for key into dest and reports whether it was cached.
+// It also returns the full key to store a freshly loaded value under.
+func (c *CacheService) get(ctx context.Context, key string, dest interface{}) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+
+	generation, err := c.client.Get(ctx, cacheGenerationKey).Int64()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		log.Printf("Failed to read cache generation: %v", err)
+		return "", false
+	}
+	key = fmt.Sprintf("%s%d:%s", cacheKeyPrefix, generation, key)
+
+	data, err := c.client.Get(ctx, key).Bytes()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			log.Printf("Failed to read cache entry %s: %v", key, err)
+		}
+		return key, false
+	}
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(dest); err != nil {
+		log.Printf("Failed to decode cache entry %s: %v", key, err)
+		return key, false
+	}
+	return key, true
+}
+
+// set stores value under a key returned by get
+func (c *CacheService) set(ctx context.Context, key string, value interface{}) {
+	if c == nil || key == "" {
+		return
+	}
+
+	var data bytes.Buffer
+	if err := gob.NewEncoder(&data).Encode(value); err != nil {
+		log.Printf("Failed to encode cache entry %s: %v", key, err)
+		return
+	}
+	if err := c.client.Set(ctx, key, data.Bytes(), c.ttl).Err(); err != nil {
+		log.Printf("Failed to write cache entry %s: %v", key, err)
+	}
+}
+
+// Plugin returns a GORM plugin that invalidates the cache after every write
+// to a cached table, whichever service makes it
+func (c *CacheService) Plugin() gorm.Plugin {
+	return cacheInvalidation{cache: c}
+}
+
+// cacheInvalidation is the GORM plugin returned by CacheService.Plugin
+type cacheInvalidation struct {
+	cache *CacheService
+}
+
+// Name implements gorm.Plugin
+func (cacheInvalidation) Name() string {
+	return "cache_invalidation"
+}
+
+// Initialize implements gorm.Plugin
+func (p cacheInvalidation) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	if err := callbacks.Create().After("gorm:create").Register("cache:after_create", p.afterWrite); err != nil {
+		return err
+	}
+	if err := callbacks.Update().After("gorm:update").Register("cache:after_update", p.afterWrite); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().After("gorm:delete").Register("cache:after_delete", p.afterWrite); err != nil {
+		return err
+	}
+	return callbacks.Raw().After("gorm:raw").Register("cache:after_raw", p.afterWrite)
+}
+
+func (p cacheInvalidation) afterWrite(tx *gorm.DB) {
+	if tx.Error != nil || tx.Statement.DryRun {
+		return
+	}
+
+	// Raw statements have no table, so look for the table names in the SQL
+	target := tx.Statement.Table
+	if target == "" {
+		target = strings.ToLower(tx.Statement.SQL.String())
+	}
+	for _, table := range cachedTables {
+		if strings.Contains(target, table) {
+			p.cache.Invalidate(tx.Statement.Context)
+			return
+		}
+	}
+}
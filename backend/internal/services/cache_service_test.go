@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"web-crawler-backend/internal/models"
+)
+
+func setupCacheTest(t *testing.T) (*CacheService, *miniredis.Miniredis, *gorm.DB, *sql.DB) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	cache := NewCacheService(client, time.Minute)
+
+	db := setupURLTestDB(t)
+	require.NoError(t, db.Use(cache.Plugin()))
+
+	// Writes through the raw connection bypass the invalidation callbacks.
+	// A single connection keeps the in-memory database shared.
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	return cache, server, db, sqlDB
+}
+
+func TestCacheService_URLList(t *testing.T) {
+	cache, _, db, sqlDB := setupCacheTest(t)
+	service := NewURLService(db, &mockCrawlerService{}).WithCache(cache)
+	require.NoError(t, db.Create(&models.URL{URL: "https://example.com", Title: "Example", Status: "completed"}).Error)
+
+	urls, total, err := service.GetURLs(10, 0, "", "", "created_at", "desc")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, urls, 1)
+
+	// The second read is served from the cache
+	_, err = sqlDB.Exec("UPDATE urls SET title = 'Changed'")
+	require.NoError(t, err)
+	urls, _, err = service.GetURLs(10, 0, "", "", "created_at", "desc")
+	require.NoError(t, err)
+	assert.Equal(t, "Example", urls[0].Title)
+
+	// Other parameters are cached separately
+	urls, _, err = service.GetURLs(5, 0, "", "", "created_at", "desc")
+	require.NoError(t, err)
+	assert.Equal(t, "Changed", urls[0].Title)
+
+	// Writes through GORM invalidate the cache
+	require.NoError(t, db.Model(&models.URL{}).Where("id = ?", urls[0].ID).Update("title", "Updated").Error)
+	urls, _, err = service.GetURLs(10, 0, "", "", "created_at", "desc")
+	require.NoError(t, err)
+	assert.Equal(t, "Updated", urls[0].Title)
+}
+
+func TestCacheService_CrawlStatus(t *testing.T) {
+	cache, _, db, sqlDB := setupCacheTest(t)
+	service := NewCrawlerService(db).WithCache(cache)
+	url := &models.URL{URL: "https://example.com", Status: "running"}
+	require.NoError(t, db.Create(url).Error)
+	crawl := &models.Crawl{URLID: url.ID, Status: "running"}
+	require.NoError(t, db.Create(crawl).Error)
+
+	status, err := service.GetCrawlStatus(url.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "running", status.Status)
+
+	_, err = sqlDB.Exec("UPDATE crawls SET status = 'completed'")
+	require.NoError(t, err)
+	status, err = service.GetCrawlStatus(url.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "running", status.Status)
+
+	// Raw statements touching a cached table invalidate it too
+	require.NoError(t, db.Exec("UPDATE crawls SET broken_links = 3").Error)
+	status, err = service.GetCrawlStatus(url.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "completed", status.Status)
+	assert.Equal(t, 3, status.BrokenLinks)
+
+	_, err = service.GetCrawlStatus(url.ID + 1)
+	assert.ErrorIs(t, err, ErrURLNotFound)
+}
+
+func TestCacheService_RedisUnavailable(t *testing.T) {
+	cache, server, db, _ := setupCacheTest(t)
+	service := NewURLService(db, &mockCrawlerService{}).WithCache(cache)
+	server.Close()
+
+	// Reads fall back to the database and writes still succeed
+	require.NoError(t, db.Create(&models.URL{URL: "https://example.com", Status: "pending"}).Error)
+	urls, total, err := service.GetURLs(10, 0, "", "", "created_at", "desc")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Len(t, urls, 1)
+}
+
+func TestCacheService_Nil(t *testing.T) {
+	var cache *CacheService
+	cache.Invalidate(context.Background())
+	_, ok := cache.get(context.Background(), "key", &struct{}{})
+	assert.False(t, ok)
+}
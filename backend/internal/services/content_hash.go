@@ -0,0 +1,43 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// invisibleElements hold text that isn't part of the rendered page
+var invisibleElements = map[string]bool{
+	"script":   true,
+	"style":    true,
+	"noscript": true,
+	"template": true,
+}
+
+// contentHash returns a SHA-256 of the page's visible text with whitespace
+// collapsed, so markup and formatting changes don't count as content changes
+func contentHash(doc *html.Node) string {
+	sum := sha256.Sum256([]byte(strings.Join(visibleWords(doc), " ")))
+	return hex.EncodeToString(sum[:])
+}
+
+// visibleWords returns the words of the page's visible text in document order
+func visibleWords(doc *html.Node) []string {
+	var words []string
+	var visit func(*html.Node)
+	visit = func(n *html.Node) {
+		if n.Type == html.ElementNode && invisibleElements[n.Data] {
+			return
+		}
+		if n.Type == html.TextNode {
+			words = append(words, strings.Fields(n.Data)...)
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			visit(c)
+		}
+	}
+	visit(doc)
+	return words
+}
@@ -0,0 +1,231 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"gorm.io/gorm"
+
+	"web-crawler-backend/internal/models"
+)
+
+// GetContentChanges compares each of the URL's latest completed crawls with
+// the one before it, newest first
+func (s *URLService) GetContentChanges(urlID uint, limit int) ([]models.CrawlChange, error) {
+	var url models.URL
+	if err := s.db.First(&url, urlID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrURLNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch URL: %w", err)
+	}
+
+	// One extra crawl so the oldest listed crawl has something to compare with
+	var crawls []models.Crawl
+	if err := s.db.Where("url_id = ? AND status = ?", urlID, "completed").
+		Order("created_at DESC").Limit(limit + 1).Find(&crawls).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch crawls: %w", err)
+	}
+
+	changes := []models.CrawlChange{}
+	if len(crawls) < 2 {
+		return changes, nil
+	}
+
+	crawlIDs := make([]uint, len(crawls))
+	for i, crawl := range crawls {
+		crawlIDs[i] = crawl.ID
+	}
+	links, err := linkURLsByCrawl(s.db, crawlIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < len(crawls)-1; i++ {
+		current, previous := crawls[i], crawls[i+1]
+		change := models.CrawlChange{
+			CrawlID:         current.ID,
+			PreviousCrawlID: previous.ID,
+			CrawledAt:       current.CreatedAt,
+			ContentChanged:  current.ContentHash != "" && previous.ContentHash != "" && current.ContentHash != previous.ContentHash,
+			TitleChanged:    current.Title != previous.Title,
+			HeadingChanges:  headingDeltas(parseHeadingCounts(previous.HeadingCounts), parseHeadingCounts(current.HeadingCounts)),
+		}
+		if change.TitleChanged {
+			change.PreviousTitle = previous.Title
+			change.Title = current.Title
+		}
+		added, removed := diffSets(links[previous.ID], links[current.ID])
+		change.LinksAdded = len(added)
+		change.LinksRemoved = len(removed)
+		change.Changed = change.ContentChanged || change.TitleChanged || len(change.HeadingChanges) > 0 ||
+			change.LinksAdded > 0 || change.LinksRemoved > 0
+
+		changes = append(changes, change)
+	}
+
+	return changes, nil
+}
+
+// linkURLsByCrawl loads the set of link URLs found by each of the given crawls
+func linkURLsByCrawl(db *gorm.DB, crawlIDs []uint) (map[uint]map[string]bool, error) {
+	var rows []struct {
+		CrawlID uint
+		LinkURL string
+	}
+	if err := db.Model(&models.Link{}).Select("crawl_id, link_url").Where("crawl_id IN ?", crawlIDs).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch links: %w", err)
+	}
+
+	sets := make(map[uint]map[string]bool, len(crawlIDs))
+	for _, id := range crawlIDs {
+		sets[id] = map[string]bool{}
+	}
+	for _, row := range rows {
+		sets[row.CrawlID][row.LinkURL] = true
+	}
+	return sets, nil
+}
+
+// diffSets returns the keys only present in after (added) and only present in before (removed)
+func diffSets(before, after map[string]bool) (added, removed []string) {
+	for key := range after {
+		if !before[key] {
+			added = append(added, key)
+		}
+	}
+	for key := range before {
+		if !after[key] {
+			removed = append(removed, key)
+		}
+	}
+	return added, removed
+}
+
+// parseHeadingCounts decodes a crawl's heading counts column, treating invalid JSON as no headings
+func parseHeadingCounts(raw string) models.HeadingCounts {
+	var counts models.HeadingCounts
+	if raw != "" {
+		json.Unmarshal([]byte(raw), &counts)
+	}
+	return counts
+}
+
+// headingDeltas returns the non-zero heading count changes per level
+func headingDeltas(before, after models.HeadingCounts) map[string]int {
+	deltas := map[string]int{}
+	levels := []struct {
+		name          string
+		before, after int
+	}{
+		{"h1", before.H1, after.H1},
+		{"h2", before.H2, after.H2},
+		{"h3", before.H3, after.H3},
+		{"h4", before.H4, after.H4},
+		{"h5", before.H5, after.H5},
+		{"h6", before.H6, after.H6},
+	}
+	for _, level := range levels {
+		if delta := level.after - level.before; delta != 0 {
+			deltas[level.name] = delta
+		}
+	}
+	return deltas
+}
+
+// GetCrawlDiff compares two crawls of a URL. The crawls may be given in any
+// order of time; the diff always describes the change from the first to the second.
+func (s *URLService) GetCrawlDiff(urlID, fromCrawlID, toCrawlID uint) (*models.CrawlDiff, error) {
+	var url models.URL
+	if err := s.db.First(&url, urlID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrURLNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch URL: %w", err)
+	}
+
+	var from, to models.Crawl
+	for _, target := range []struct {
+		crawl *models.Crawl
+		id    uint
+	}{{&from, fromCrawlID}, {&to, toCrawlID}} {
+		if err := s.db.Where("id = ? AND url_id = ?", target.id, urlID).First(target.crawl).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil, ErrCrawlNotFound
+			}
+			return nil, fmt.Errorf("failed to fetch crawl: %w", err)
+		}
+	}
+
+	fromLinks, err := linkAccessibility(s.db, from.ID)
+	if err != nil {
+		return nil, err
+	}
+	toLinks, err := linkAccessibility(s.db, to.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &models.CrawlDiff{
+		URLID:                 urlID,
+		FromCrawlID:           from.ID,
+		ToCrawlID:             to.ID,
+		ContentChanged:        from.ContentHash != "" && to.ContentHash != "" && from.ContentHash != to.ContentHash,
+		TitleChanged:          from.Title != to.Title,
+		HeadingChanges:        headingDeltas(parseHeadingCounts(from.HeadingCounts), parseHeadingCounts(to.HeadingCounts)),
+		BrokenLinksFixed:      []string{},
+		BrokenLinksIntroduced: []string{},
+	}
+
+	fromSet, toSet := map[string]bool{}, map[string]bool{}
+	for link := range fromLinks {
+		fromSet[link] = true
+	}
+	for link := range toLinks {
+		toSet[link] = true
+	}
+	diff.LinksAdded, diff.LinksRemoved = diffSets(fromSet, toSet)
+
+	for link, accessible := range fromLinks {
+		if nowAccessible, ok := toLinks[link]; !accessible && ok && nowAccessible {
+			diff.BrokenLinksFixed = append(diff.BrokenLinksFixed, link)
+		}
+	}
+	for link, accessible := range toLinks {
+		if wasAccessible, ok := fromLinks[link]; !accessible && (!ok || wasAccessible) {
+			diff.BrokenLinksIntroduced = append(diff.BrokenLinksIntroduced, link)
+		}
+	}
+
+	for _, list := range [][]string{diff.LinksAdded, diff.LinksRemoved, diff.BrokenLinksFixed, diff.BrokenLinksIntroduced} {
+		sort.Strings(list)
+	}
+	if diff.LinksAdded == nil {
+		diff.LinksAdded = []string{}
+	}
+	if diff.LinksRemoved == nil {
+		diff.LinksRemoved = []string{}
+	}
+
+	return diff, nil
+}
+
+// linkAccessibility maps each link URL of a crawl to whether it was reachable.
+// A URL linked several times counts as broken if any of its checks failed.
+func linkAccessibility(db *gorm.DB, crawlID uint) (map[string]bool, error) {
+	var links []models.Link
+	if err := db.Select("link_url, is_accessible").Where("crawl_id = ?", crawlID).Find(&links).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch links: %w", err)
+	}
+
+	accessible := make(map[string]bool, len(links))
+	for _, link := range links {
+		if previous, seen := accessible[link.LinkURL]; seen {
+			accessible[link.LinkURL] = previous && link.IsAccessible
+		} else {
+			accessible[link.LinkURL] = link.IsAccessible
+		}
+	}
+	return accessible, nil
+}
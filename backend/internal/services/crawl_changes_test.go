@@ -0,0 +1,143 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/html"
+
+	"web-crawler-backend/internal/models"
+)
+
+func TestContentHash(t *testing.T) {
+	hash := func(t *testing.T, content string) string {
+		doc, err := html.Parse(strings.NewReader(content))
+		require.NoError(t, err)
+		return contentHash(doc)
+	}
+
+	original := hash(t, `<html><body><h1>Welcome</h1><p>Hello   world</p></body></html>`)
+	assert.Len(t, original, 64)
+
+	// Markup, whitespace and scripts don't change the visible text
+	assert.Equal(t, original, hash(t, "<html><body>\n<div><h1 class=\"big\">Welcome</h1>\n<p>Hello\nworld</p></div><script>var t = Date.now()</script></body></html>"))
+	assert.NotEqual(t, original, hash(t, `<html><body><h1>Welcome</h1><p>Hello there</p></body></html>`))
+}
+
+func TestURLService_GetContentChanges(t *testing.T) {
+	db := setupURLTestDB(t)
+	service := NewURLService(db, &mockCrawlerService{})
+
+	url := &models.URL{URL: "https://example.com", Status: "completed"}
+	require.NoError(t, db.Create(url).Error)
+
+	base := time.Now().Add(-time.Hour)
+	first := &models.Crawl{URLID: url.ID, Status: "completed", CreatedAt: base, Title: "Home", ContentHash: "aaa", HeadingCounts: `{"h1":1,"h2":2}`}
+	second := &models.Crawl{URLID: url.ID, Status: "completed", CreatedAt: base.Add(time.Minute), Title: "Home", ContentHash: "aaa", HeadingCounts: `{"h1":1,"h2":2}`}
+	third := &models.Crawl{URLID: url.ID, Status: "completed", CreatedAt: base.Add(2 * time.Minute), Title: "Welcome home", ContentHash: "bbb", HeadingCounts: `{"h1":1,"h2":1,"h3":2}`}
+	for _, crawl := range []*models.Crawl{first, second, third} {
+		require.NoError(t, db.Create(crawl).Error)
+	}
+	require.NoError(t, db.Create(&models.Crawl{URLID: url.ID, Status: "error", CreatedAt: base.Add(3 * time.Minute)}).Error)
+
+	for _, crawl := range []*models.Crawl{first, second} {
+		require.NoError(t, db.Create(&[]models.Link{
+			{URLID: url.ID, CrawlID: crawl.ID, LinkURL: "https://example.com/a"},
+			{URLID: url.ID, CrawlID: crawl.ID, LinkURL: "https://example.com/b"},
+		}).Error)
+	}
+	require.NoError(t, db.Create(&[]models.Link{
+		{URLID: url.ID, CrawlID: third.ID, LinkURL: "https://example.com/a"},
+		{URLID: url.ID, CrawlID: third.ID, LinkURL: "https://example.com/c"},
+		{URLID: url.ID, CrawlID: third.ID, LinkURL: "https://example.com/d"},
+	}).Error)
+
+	t.Run("compares consecutive crawls", func(t *testing.T) {
+		changes, err := service.GetContentChanges(url.ID, 10)
+		require.NoError(t, err)
+		require.Len(t, changes, 2)
+
+		latest := changes[0]
+		assert.Equal(t, third.ID, latest.CrawlID)
+		assert.Equal(t, second.ID, latest.PreviousCrawlID)
+		assert.True(t, latest.Changed)
+		assert.True(t, latest.ContentChanged)
+		assert.True(t, latest.TitleChanged)
+		assert.Equal(t, "Home", latest.PreviousTitle)
+		assert.Equal(t, "Welcome home", latest.Title)
+		assert.Equal(t, map[string]int{"h2": -1, "h3": 2}, latest.HeadingChanges)
+		assert.Equal(t, 2, latest.LinksAdded)
+		assert.Equal(t, 1, latest.LinksRemoved)
+
+		unchanged := changes[1]
+		assert.Equal(t, second.ID, unchanged.CrawlID)
+		assert.False(t, unchanged.Changed)
+		assert.Empty(t, unchanged.HeadingChanges)
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		changes, err := service.GetContentChanges(url.ID, 1)
+		require.NoError(t, err)
+		require.Len(t, changes, 1)
+		assert.Equal(t, third.ID, changes[0].CrawlID)
+	})
+
+	t.Run("URL not found", func(t *testing.T) {
+		_, err := service.GetContentChanges(999, 10)
+		assert.ErrorIs(t, err, ErrURLNotFound)
+	})
+}
+
+func TestURLService_GetCrawlDiff(t *testing.T) {
+	db := setupURLTestDB(t)
+	service := NewURLService(db, &mockCrawlerService{})
+
+	url := &models.URL{URL: "https://example.com", Status: "completed"}
+	require.NoError(t, db.Create(url).Error)
+
+	before := &models.Crawl{URLID: url.ID, Status: "completed", Title: "Home", HeadingCounts: `{"h1":1,"h2":3}`}
+	after := &models.Crawl{URLID: url.ID, Status: "completed", Title: "Home", HeadingCounts: `{"h1":2,"h2":3}`}
+	require.NoError(t, db.Create(before).Error)
+	require.NoError(t, db.Create(after).Error)
+
+	require.NoError(t, db.Create(&[]models.Link{
+		{URLID: url.ID, CrawlID: before.ID, LinkURL: "https://example.com/fixed", IsAccessible: false},
+		{URLID: url.ID, CrawlID: before.ID, LinkURL: "https://example.com/regressed", IsAccessible: true},
+		{URLID: url.ID, CrawlID: before.ID, LinkURL: "https://example.com/removed", IsAccessible: false},
+		{URLID: url.ID, CrawlID: after.ID, LinkURL: "https://example.com/fixed", IsAccessible: true},
+		{URLID: url.ID, CrawlID: after.ID, LinkURL: "https://example.com/regressed", IsAccessible: false},
+		{URLID: url.ID, CrawlID: after.ID, LinkURL: "https://example.com/new-broken", IsAccessible: false},
+	}).Error)
+
+	t.Run("structured diff", func(t *testing.T) {
+		diff, err := service.GetCrawlDiff(url.ID, before.ID, after.ID)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"https://example.com/new-broken"}, diff.LinksAdded)
+		assert.Equal(t, []string{"https://example.com/removed"}, diff.LinksRemoved)
+		assert.Equal(t, []string{"https://example.com/fixed"}, diff.BrokenLinksFixed)
+		assert.Equal(t, []string{"https://example.com/new-broken", "https://example.com/regressed"}, diff.BrokenLinksIntroduced)
+		assert.Equal(t, map[string]int{"h1": 1}, diff.HeadingChanges)
+		assert.False(t, diff.TitleChanged)
+	})
+
+	t.Run("reversed order", func(t *testing.T) {
+		diff, err := service.GetCrawlDiff(url.ID, after.ID, before.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"https://example.com/removed"}, diff.LinksAdded)
+		assert.Equal(t, []string{"https://example.com/regressed"}, diff.BrokenLinksFixed)
+	})
+
+	t.Run("crawl of another URL", func(t *testing.T) {
+		other := &models.URL{URL: "https://other.example.com", Status: "completed"}
+		require.NoError(t, db.Create(other).Error)
+		otherCrawl := &models.Crawl{URLID: other.ID, Status: "completed"}
+		require.NoError(t, db.Create(otherCrawl).Error)
+
+		_, err := service.GetCrawlDiff(url.ID, before.ID, otherCrawl.ID)
+		assert.ErrorIs(t, err, ErrCrawlNotFound)
+	})
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"web-crawler-backend/internal/models"
+)
+
+// slowLinkThreshold is how long a link check may take before it is logged as slow
+var slowLinkThreshold = 3 * time.Second
+
+// crawlEventLog collects the events of one crawl. Link checks add events
+// from several goroutines; all events are saved when the crawl finishes. A
+// nil log records nothing.
+type crawlEventLog struct {
+	mu      sync.Mutex
+	crawlID uint
+	start   time.Time
+	events  []models.CrawlEvent
+}
+
+// newCrawlEventLog starts the event log of a crawl
+func newCrawlEventLog(crawl *models.Crawl) *crawlEventLog {
+	start := time.Now()
+	if crawl.StartedAt != nil {
+		start = *crawl.StartedAt
+	}
+	return &crawlEventLog{crawlID: crawl.ID, start: start}
+}
+
+// add records an event with a formatted message
+func (l *crawlEventLog) add(eventType, format string, args ...interface{}) {
+	if l == nil {
+		return
+	}
+
+	now := time.Now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, models.CrawlEvent{
+		CrawlID:   l.crawlID,
+		Type:      eventType,
+		Message:   fmt.Sprintf(format, args...),
+		ElapsedMs: now.Sub(l.start).Milliseconds(),
+		CreatedAt: now,
+	})
+}
+
+// saveCrawlEvents stores the collected events of a crawl. Failing to store
+// them doesn't fail the crawl.
+func (s *CrawlerService) saveCrawlEvents(events *crawlEventLog) {
+	events.mu.Lock()
+	defer events.mu.Unlock()
+	if len(events.events) == 0 {
+		return
+	}
+	if err := createInBatches(s.db, events.events, s.insertBatchSize()); err != nil {
+		log.Printf("Failed to save the events of crawl %d: %v", events.crawlID, err)
+	}
+}
+
+// GetCrawlEvents returns the events of a crawl in the order they happened
+func (s *CrawlerService) GetCrawlEvents(crawlID uint) ([]models.CrawlEvent, error) {
+	crawls := s.repositories().Crawls()
+	exists, err := crawls.Exists(crawlID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch crawl: %w", err)
+	}
+	if !exists {
+		return nil, ErrCrawlNotFound
+	}
+
+	events, err := crawls.Events(crawlID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch crawl events: %w", err)
+	}
+	return events, nil
+}
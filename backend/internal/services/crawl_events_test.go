@@ -0,0 +1,76 @@
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"web-crawler-backend/internal/models"
+)
+
+func TestCrawlerService_CrawlEvents(t *testing.T) {
+	external := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(50 * time.Millisecond)
+		}
+	}))
+	defer external.Close()
+
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<!DOCTYPE html><html><body>
+			<a href="/about">About</a>
+			<a href="` + external.URL + `/fast">Fast</a>
+			<a href="` + external.URL + `/slow">Slow</a>
+		</body></html>`))
+	}))
+	defer site.Close()
+
+	threshold := slowLinkThreshold
+	slowLinkThreshold = 40 * time.Millisecond
+	t.Cleanup(func() { slowLinkThreshold = threshold })
+
+	db := setupCrawlerTestDB(t)
+	service := NewCrawlerService(db)
+	urlRecord := &models.URL{URL: site.URL + "/", Status: "pending"}
+	require.NoError(t, db.Create(urlRecord).Error)
+	service.StartCrawl(urlRecord.ID)
+
+	var crawl models.Crawl
+	require.NoError(t, db.Where("url_id = ?", urlRecord.ID).First(&crawl).Error)
+
+	events, err := service.GetCrawlEvents(crawl.ID)
+	require.NoError(t, err)
+	var types []string
+	for i, event := range events {
+		types = append(types, event.Type)
+		if i > 0 {
+			assert.GreaterOrEqual(t, event.ElapsedMs, events[i-1].ElapsedMs)
+		}
+	}
+	assert.Equal(t, []string{
+		models.CrawlEventFetchStarted,
+		models.CrawlEventResponseReceived,
+		models.CrawlEventBodyReceived,
+		models.CrawlEventParseComplete,
+		models.CrawlEventLinksQueued,
+		models.CrawlEventSlowLink,
+		models.CrawlEventChecksComplete,
+		models.CrawlEventFinished,
+	}, types)
+	assert.Equal(t, "Parsed HTML5 page: 3 links, 0 images, 0 forms", events[3].Message)
+	assert.Equal(t, "2 links queued for check", events[4].Message)
+	assert.Contains(t, events[5].Message, external.URL+"/slow took")
+	assert.Equal(t, "Crawl completed", events[7].Message)
+
+	_, err = service.GetCrawlEvents(crawl.ID + 1)
+	assert.ErrorIs(t, err, ErrCrawlNotFound)
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+)
+
+// defaultMaxResponseBytes is the page size limit used when none is configured (10 MiB)
+const defaultMaxResponseBytes = 10 << 20
+
+// htmlContentTypes are the media types the crawler parses
+var htmlContentTypes = map[string]bool{
+	"text/html":             true,
+	"application/xhtml+xml": true,
+}
+
+// skipError reports a page the crawler deliberately did not parse
+type skipError struct {
+	reason string
+}
+
+func (e *skipError) Error() string {
+	return "skipped: " + e.reason
+}
+
+// checkContentType skips responses that are not HTML, such as PDFs or videos.
+// Responses without a Content-Type are parsed.
+func checkContentType(resp *http.Response) error {
+	contentType := resp.Header.Get("Content-Type")
+	if contentType == "" {
+		return nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || !htmlContentTypes[mediaType] {
+		return &skipError{reason: fmt.Sprintf("non-HTML content type %s", contentType)}
+	}
+	return nil
+}
+
+// checkContentLength skips responses that announce a body over the size limit,
+// before any of it is downloaded
+func checkContentLength(resp *http.Response, limit int64) error {
+	if limit > 0 && resp.ContentLength > limit {
+		return responseTooLarge(limit)
+	}
+	return nil
+}
+
+func responseTooLarge(limit int64) error {
+	return &skipError{reason: fmt.Sprintf("response larger than %d bytes", limit)}
+}
+
+// responseLimit returns the maximum page size the crawler downloads
+func (s *CrawlerService) responseLimit() int64 {
+	if s.options.MaxResponseBytes > 0 {
+		return s.options.MaxResponseBytes
+	}
+	return defaultMaxResponseBytes
+}
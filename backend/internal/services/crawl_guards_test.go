@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"web-crawler-backend/internal/models"
+)
+
+func TestCheckContentType(t *testing.T) {
+	tests := []struct {
+		contentType string
+		skipped     bool
+	}{
+		{"", false},
+		{"text/html", false},
+		{"text/html; charset=utf-8", false},
+		{"TEXT/HTML", false},
+		{"application/xhtml+xml", false},
+		{"application/pdf", true},
+		{"video/mp4", true},
+		{"text/htmlx", true},
+		{"not a media type;;", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.contentType, func(t *testing.T) {
+			resp := &http.Response{Header: http.Header{}}
+			if tt.contentType != "" {
+				resp.Header.Set("Content-Type", tt.contentType)
+			}
+			err := checkContentType(resp)
+			if !tt.skipped {
+				assert.NoError(t, err)
+				return
+			}
+			var skip *skipError
+			assert.ErrorAs(t, err, &skip)
+		})
+	}
+}
+
+func TestResponseTimer_readBodyLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(strings.Repeat("a", 100)))
+	}))
+	defer server.Close()
+
+	resp, timer, err := timedGet(context.Background(), server.URL)
+	require.NoError(t, err)
+	require.NoError(t, timer.readBody(resp, 100))
+	assert.Equal(t, int64(100), timer.Metrics.ResponseBytes)
+
+	resp, timer, err = timedGet(context.Background(), server.URL)
+	require.NoError(t, err)
+	err = timer.readBody(resp, 99)
+	var skip *skipError
+	require.ErrorAs(t, err, &skip)
+	assert.Equal(t, "response larger than 99 bytes", skip.reason)
+}
+
+func TestCrawlerService_ResponseGuards(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/report.pdf":
+			w.Header().Set("Content-Type", "application/pdf")
+			w.Write([]byte("%PDF-1.4"))
+		case "/large":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte("<html><body>" + strings.Repeat("<p>filler</p>", 200) + "</body></html>"))
+		default:
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte(`<html><body><a href="/report.pdf">Report</a><a href="/large">Large</a></body></html>`))
+		}
+	}))
+	defer server.Close()
+
+	options := DefaultCrawlerOptions()
+	options.MaxResponseBytes = 512
+
+	crawl := func(t *testing.T, service *CrawlerService, urlRecord *models.URL) models.Crawl {
+		require.NoError(t, service.db.Create(urlRecord).Error)
+		service.StartCrawl(urlRecord.ID)
+
+		var crawl models.Crawl
+		require.NoError(t, service.db.Where("url_id = ?", urlRecord.ID).First(&crawl).Error)
+		return crawl
+	}
+
+	t.Run("skips non-HTML root page", func(t *testing.T) {
+		service := NewCrawlerService(setupCrawlerTestDB(t))
+
+		result := crawl(t, service, &models.URL{URL: server.URL + "/report.pdf", Status: "pending"})
+		assert.Equal(t, "skipped", result.Status)
+		assert.Equal(t, "non-HTML content type application/pdf", result.SkipReason)
+		assert.Empty(t, result.ErrorMessage)
+
+		var url models.URL
+		require.NoError(t, service.db.First(&url, result.URLID).Error)
+		assert.Equal(t, "skipped", url.Status)
+	})
+
+	t.Run("skips oversized root page", func(t *testing.T) {
+		service := NewCrawlerServiceWithOptions(setupCrawlerTestDB(t), options)
+
+		result := crawl(t, service, &models.URL{URL: server.URL + "/large", Status: "pending"})
+		assert.Equal(t, "skipped", result.Status)
+		assert.Equal(t, "response larger than 512 bytes", result.SkipReason)
+	})
+
+	t.Run("records skipped pages of deep crawls", func(t *testing.T) {
+		service := NewCrawlerServiceWithOptions(setupCrawlerTestDB(t), options)
+
+		result := crawl(t, service, &models.URL{URL: server.URL + "/", Status: "pending", MaxDepth: 1})
+		assert.Equal(t, "completed", result.Status)
+
+		var pages []models.Page
+		require.NoError(t, service.db.Where("crawl_id = ?", result.ID).Order("id").Find(&pages).Error)
+		require.Len(t, pages, 3)
+		assert.Equal(t, "skipped: non-HTML content type application/pdf", pages[1].ErrorMessage)
+		assert.Equal(t, "skipped: response larger than 512 bytes", pages[2].ErrorMessage)
+	})
+}
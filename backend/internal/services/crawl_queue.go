@@ -0,0 +1,647 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"web-crawler-backend/internal/models"
+)
+
+const (
+	// crawlQueuePrefix namespaces the keys of the crawl queue in Redis
+	crawlQueuePrefix = "webcrawler:queue:crawl:"
+	// tasksListed is how many pending, scheduled and dead tasks Stats returns each
+	tasksListed = 100
+	// lostWorkerError is recorded for tasks whose worker stopped renewing its lease
+	lostWorkerError = "worker stopped responding"
+)
+
+var (
+	// ErrCrawlTaskNotFound is returned for task IDs that are not in the list an operation works on
+	ErrCrawlTaskNotFound = errors.New("crawl task not found")
+	// ErrCrawlTaskRunning is returned when cancelling a task a worker is running
+	ErrCrawlTaskRunning = errors.New("crawl task is running")
+)
+
+// CrawlRunner runs a crawl to completion. An error means the crawl could not
+// be run, e.g. because the database failed, and is worth retrying; failures
+// of the crawled site are recorded on the crawl and are not errors.
+type CrawlRunner interface {
+	RunCrawl(ctx context.Context, urlID uint) error
+}
+
+// CrawlQueueOptions holds the retry and lease settings of the crawl queue
+type CrawlQueueOptions struct {
+	// MaxRetries is how often a failed task is retried before it is moved to
+	// the dead letter list
+	MaxRetries int
+	// RetryBaseDelay is the delay before the first retry; it doubles with every retry
+	RetryBaseDelay time.Duration
+	// LeaseTimeout is how long a task stays claimed by a worker without a lease
+	// renewal. Tasks of workers that died are run again after it.
+	LeaseTimeout time.Duration
+	// PollInterval is how often idle workers look for tasks
+	PollInterval time.Duration
+}
+
+// DefaultCrawlQueueOptions returns the settings used when none are configured
+func DefaultCrawlQueueOptions() CrawlQueueOptions {
+	return CrawlQueueOptions{
+		MaxRetries:     3,
+		RetryBaseDelay: 30 * time.Second,
+		LeaseTimeout:   2 * time.Minute,
+		PollInterval:   time.Second,
+	}
+}
+
+// CrawlQueue distributes crawls over Redis, so any backend replica or worker
+// process can run them. Tasks move from a pending list to the active set
+// while a worker runs them; failed tasks are retried with exponential
+// backoff and end up in the dead letter list once out of retries.
+//
+// Each priority has its own pending list, and workers only take low priority
+// tasks while no high priority task is waiting, so a crawl a user asked for
+// doesn't wait behind a bulk rerun.
+type CrawlQueue struct {
+	client    *redis.Client
+	options   CrawlQueueOptions
+	heartbeat Heartbeat
+}
+
+// NewCrawlQueue creates a crawl queue; zero durations take their defaults
+func NewCrawlQueue(client *redis.Client, options CrawlQueueOptions) *CrawlQueue {
+	defaults := DefaultCrawlQueueOptions()
+	if options.MaxRetries < 0 {
+		options.MaxRetries = 0
+	}
+	if options.RetryBaseDelay <= 0 {
+		options.RetryBaseDelay = defaults.RetryBaseDelay
+	}
+	if options.LeaseTimeout <= 0 {
+		options.LeaseTimeout = defaults.LeaseTimeout
+	}
+	if options.PollInterval <= 0 {
+		options.PollInterval = defaults.PollInterval
+	}
+	return &CrawlQueue{client: client, options: options}
+}
+
+// Heartbeat reports when the queue maintenance last ticked, for readiness checks
+func (q *CrawlQueue) Heartbeat() *Heartbeat {
+	return &q.heartbeat
+}
+
+func crawlQueueKey(name string) string {
+	return crawlQueuePrefix + name
+}
+
+func crawlTaskKey(id string) string {
+	return crawlQueuePrefix + "task:" + id
+}
+
+// pendingKey returns the pending list of a priority; unknown priorities are low
+func pendingKey(priority models.CrawlPriority) string {
+	if priority == models.CrawlPriorityHigh {
+		return crawlQueueKey("pending:high")
+	}
+	return crawlQueueKey("pending:low")
+}
+
+// Enqueue adds a crawl of a URL to the queue; an empty priority is low
+func (q *CrawlQueue) Enqueue(ctx context.Context, urlID uint, priority models.CrawlPriority) (*models.CrawlTask, error) {
+	if priority != models.CrawlPriorityHigh {
+		priority = models.CrawlPriorityLow
+	}
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, fmt.Errorf("failed to generate task ID: %w", err)
+	}
+	task := &models.CrawlTask{ID: hex.EncodeToString(id), URLID: urlID, Priority: priority, EnqueuedAt: time.Now()}
+
+	_, err := q.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, crawlTaskKey(task.ID),
+			"url_id", task.URLID,
+			"priority", string(task.Priority),
+			"attempts", 0,
+			"enqueued_at", task.EnqueuedAt.UnixMilli())
+		pipe.RPush(ctx, pendingKey(task.Priority), task.ID)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to enqueue crawl: %w", err)
+	}
+	return task, nil
+}
+
+// dequeueScript moves the oldest high priority task, or if there is none the
+// oldest low priority task, to the active set, leased until ARGV[1]
+var dequeueScript = redis.NewScript(`
+local id = redis.call('LPOP', KEYS[1])
+if not id then
+	id = redis.call('LPOP', KEYS[2])
+end
+if not id then
+	return false
+end
+redis.call('ZADD', KEYS[3], ARGV[1], id)
+return id
+`)
+
+// dequeue claims the next pending task, or returns nil if there is none
+func (q *CrawlQueue) dequeue(ctx context.Context, now time.Time) (*models.CrawlTask, error) {
+	for {
+		deadline := now.Add(q.options.LeaseTimeout).UnixMilli()
+		id, err := dequeueScript.Run(ctx, q.client,
+			[]string{pendingKey(models.CrawlPriorityHigh), pendingKey(models.CrawlPriorityLow), crawlQueueKey("active")},
+			deadline).Text()
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to dequeue crawl: %w", err)
+		}
+
+		task, err := q.loadTask(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if task != nil {
+			return task, nil
+		}
+		// The task was purged while pending
+		q.client.ZRem(ctx, crawlQueueKey("active"), id)
+	}
+}
+
+// loadTask reads a task, or returns nil if it doesn't exist
+func (q *CrawlQueue) loadTask(ctx context.Context, id string) (*models.CrawlTask, error) {
+	fields, err := q.client.HGetAll(ctx, crawlTaskKey(id)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load crawl task: %w", err)
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	task := &models.CrawlTask{
+		ID:        id,
+		Priority:  models.CrawlPriority(fields["priority"]),
+		LastError: fields["last_error"],
+	}
+	urlID, _ := strconv.ParseUint(fields["url_id"], 10, 64)
+	task.URLID = uint(urlID)
+	task.Attempts, _ = strconv.Atoi(fields["attempts"])
+	enqueuedAt, _ := strconv.ParseInt(fields["enqueued_at"], 10, 64)
+	task.EnqueuedAt = time.UnixMilli(enqueuedAt)
+	if failedAt, err := strconv.ParseInt(fields["failed_at"], 10, 64); err == nil {
+		t := time.UnixMilli(failedAt)
+		task.FailedAt = &t
+	}
+	return task, nil
+}
+
+// complete removes a finished task from the queue
+func (q *CrawlQueue) complete(ctx context.Context, task *models.CrawlTask) error {
+	_, err := q.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRem(ctx, crawlQueueKey("active"), task.ID)
+		pipe.Del(ctx, crawlTaskKey(task.ID))
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to complete crawl task: %w", err)
+	}
+	return nil
+}
+
+// fail records a failed run of a task and schedules a retry, or moves the
+// task to the dead letter list once it is out of retries
+func (q *CrawlQueue) fail(ctx context.Context, task *models.CrawlTask, runErr error, now time.Time) error {
+	// A worker that lost its lease no longer owns the task
+	removed, err := q.client.ZRem(ctx, crawlQueueKey("active"), task.ID).Result()
+	if err != nil {
+		return fmt.Errorf("failed to release crawl task: %w", err)
+	}
+	if removed == 0 {
+		return nil
+	}
+
+	task.Attempts++
+	task.LastError = runErr.Error()
+	task.FailedAt = &now
+	_, err = q.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, crawlTaskKey(task.ID),
+			"attempts", task.Attempts,
+			"last_error", task.LastError,
+			"failed_at", now.UnixMilli())
+		if task.Attempts > q.options.MaxRetries {
+			pipe.LPush(ctx, crawlQueueKey("dead"), task.ID)
+		} else {
+			retryAt := now.Add(q.options.RetryBaseDelay << (task.Attempts - 1))
+			pipe.ZAdd(ctx, crawlQueueKey("scheduled"), redis.Z{Score: float64(retryAt.UnixMilli()), Member: task.ID})
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record crawl task failure: %w", err)
+	}
+	return nil
+}
+
+// renewLease extends the lease of a running task
+func (q *CrawlQueue) renewLease(ctx context.Context, task *models.CrawlTask, now time.Time) error {
+	deadline := now.Add(q.options.LeaseTimeout).UnixMilli()
+	return q.client.ZAddXX(ctx, crawlQueueKey("active"), redis.Z{Score: float64(deadline), Member: task.ID}).Err()
+}
+
+// promoteScript moves tasks due for a retry back to the pending list of their
+// priority, and requeues tasks whose lease expired, counting that as a failed
+// attempt
+var promoteScript = redis.NewScript(`
+local function requeue(id)
+	if redis.call('HGET', ARGV[3] .. id, 'priority') == 'high' then
+		redis.call('RPUSH', KEYS[3], id)
+	else
+		redis.call('RPUSH', KEYS[4], id)
+	end
+end
+local moved = 0
+for _, id in ipairs(redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1])) do
+	redis.call('ZREM', KEYS[1], id)
+	requeue(id)
+	moved = moved + 1
+end
+for _, id in ipairs(redis.call('ZRANGEBYSCORE', KEYS[2], '-inf', ARGV[1])) do
+	redis.call('ZREM', KEYS[2], id)
+	local key = ARGV[3] .. id
+	local attempts = redis.call('HINCRBY', key, 'attempts', 1)
+	redis.call('HSET', key, 'last_error', ARGV[4], 'failed_at', ARGV[1])
+	if attempts > tonumber(ARGV[2]) then
+		redis.call('LPUSH', KEYS[5], id)
+	else
+		requeue(id)
+	end
+	moved = moved + 1
+end
+return moved
+`)
+
+// promote requeues tasks that are due for a retry or lost their worker
+func (q *CrawlQueue) promote(ctx context.Context, now time.Time) (int, error) {
+	moved, err := promoteScript.Run(ctx, q.client,
+		[]string{
+			crawlQueueKey("scheduled"), crawlQueueKey("active"),
+			pendingKey(models.CrawlPriorityHigh), pendingKey(models.CrawlPriorityLow),
+			crawlQueueKey("dead"),
+		},
+		now.UnixMilli(), q.options.MaxRetries, crawlQueuePrefix+"task:", lostWorkerError).Int()
+	if err != nil {
+		return 0, fmt.Errorf("failed to promote crawl tasks: %w", err)
+	}
+	return moved, nil
+}
+
+// Stats counts the tasks in each state and the workers of all processes, and
+// lists the oldest pending, the next scheduled and the newest dead tasks
+func (q *CrawlQueue) Stats(ctx context.Context) (*models.CrawlQueueStats, error) {
+	var pendingHigh, pendingLow, scheduled, active, dead *redis.IntCmd
+	var pendingHighIDs, pendingLowIDs, deadIDs *redis.StringSliceCmd
+	var scheduledIDs *redis.ZSliceCmd
+	_, err := q.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pendingHigh = pipe.LLen(ctx, pendingKey(models.CrawlPriorityHigh))
+		pendingLow = pipe.LLen(ctx, pendingKey(models.CrawlPriorityLow))
+		scheduled = pipe.ZCard(ctx, crawlQueueKey("scheduled"))
+		active = pipe.ZCard(ctx, crawlQueueKey("active"))
+		dead = pipe.LLen(ctx, crawlQueueKey("dead"))
+		pendingHighIDs = pipe.LRange(ctx, pendingKey(models.CrawlPriorityHigh), 0, tasksListed-1)
+		pendingLowIDs = pipe.LRange(ctx, pendingKey(models.CrawlPriorityLow), 0, tasksListed-1)
+		scheduledIDs = pipe.ZRangeWithScores(ctx, crawlQueueKey("scheduled"), 0, tasksListed-1)
+		deadIDs = pipe.LRange(ctx, crawlQueueKey("dead"), 0, tasksListed-1)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read crawl queue: %w", err)
+	}
+
+	stats := &models.CrawlQueueStats{
+		Pending:     pendingHigh.Val() + pendingLow.Val(),
+		PendingHigh: pendingHigh.Val(),
+		PendingLow:  pendingLow.Val(),
+		Scheduled:   scheduled.Val(),
+		Active:      active.Val(),
+		Dead:        dead.Val(),
+	}
+	if stats.Workers, err = q.countWorkers(ctx); err != nil {
+		return nil, err
+	}
+	if stats.Workers > 0 {
+		stats.Utilization = float64(stats.Active) / float64(stats.Workers)
+	}
+
+	pendingIDs := append(pendingHighIDs.Val(), pendingLowIDs.Val()...)
+	if len(pendingIDs) > tasksListed {
+		pendingIDs = pendingIDs[:tasksListed]
+	}
+	if stats.PendingTasks, err = q.loadTasks(ctx, pendingIDs); err != nil {
+		return nil, err
+	}
+	if stats.DeadTasks, err = q.loadTasks(ctx, deadIDs.Val()); err != nil {
+		return nil, err
+	}
+	stats.ScheduledTasks = []models.CrawlTask{}
+	for _, entry := range scheduledIDs.Val() {
+		task, err := q.loadTask(ctx, entry.Member.(string))
+		if err != nil {
+			return nil, err
+		}
+		if task != nil {
+			retryAt := time.UnixMilli(int64(entry.Score))
+			task.RetryAt = &retryAt
+			stats.ScheduledTasks = append(stats.ScheduledTasks, *task)
+		}
+	}
+	return stats, nil
+}
+
+// loadTasks reads the tasks with the IDs, skipping tasks that don't exist
+func (q *CrawlQueue) loadTasks(ctx context.Context, ids []string) ([]models.CrawlTask, error) {
+	tasks := []models.CrawlTask{}
+	for _, id := range ids {
+		task, err := q.loadTask(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if task != nil {
+			tasks = append(tasks, *task)
+		}
+	}
+	return tasks, nil
+}
+
+// CancelTask removes a pending, scheduled or dead task from the queue. Tasks a
+// worker is running can't be cancelled.
+func (q *CrawlQueue) CancelTask(ctx context.Context, id string) error {
+	var removed []*redis.IntCmd
+	_, err := q.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		removed = []*redis.IntCmd{
+			pipe.LRem(ctx, pendingKey(models.CrawlPriorityHigh), 1, id),
+			pipe.LRem(ctx, pendingKey(models.CrawlPriorityLow), 1, id),
+			pipe.ZRem(ctx, crawlQueueKey("scheduled"), id),
+			pipe.LRem(ctx, crawlQueueKey("dead"), 1, id),
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to cancel crawl task: %w", err)
+	}
+
+	for _, cmd := range removed {
+		if cmd.Val() > 0 {
+			if err := q.client.Del(ctx, crawlTaskKey(id)).Err(); err != nil {
+				return fmt.Errorf("failed to cancel crawl task: %w", err)
+			}
+			return nil
+		}
+	}
+
+	if _, err := q.client.ZScore(ctx, crawlQueueKey("active"), id).Result(); err == nil {
+		return ErrCrawlTaskRunning
+	} else if !errors.Is(err, redis.Nil) {
+		return fmt.Errorf("failed to cancel crawl task: %w", err)
+	}
+	return ErrCrawlTaskNotFound
+}
+
+// RequeueTask queues a scheduled task again right away instead of waiting
+// for its retry
+func (q *CrawlQueue) RequeueTask(ctx context.Context, id string) (*models.CrawlTask, error) {
+	removed, err := q.client.ZRem(ctx, crawlQueueKey("scheduled"), id).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to requeue crawl task: %w", err)
+	}
+	if removed == 0 {
+		return nil, ErrCrawlTaskNotFound
+	}
+
+	task, err := q.loadTask(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil {
+		return nil, ErrCrawlTaskNotFound
+	}
+	if err := q.client.RPush(ctx, pendingKey(task.Priority), id).Err(); err != nil {
+		return nil, fmt.Errorf("failed to requeue crawl task: %w", err)
+	}
+	return task, nil
+}
+
+// registerWorkers announces the workers of this process until the key
+// expires; the maintenance loop renews it while the process runs
+func (q *CrawlQueue) registerWorkers(ctx context.Context, key string, workers int) error {
+	return q.client.Set(ctx, key, workers, q.options.LeaseTimeout).Err()
+}
+
+// countWorkers adds up the workers of all processes that renewed their registration
+func (q *CrawlQueue) countWorkers(ctx context.Context) (int64, error) {
+	var total int64
+	iter := q.client.Scan(ctx, 0, crawlQueueKey("workers:*"), 100).Iterator()
+	for iter.Next(ctx) {
+		workers, err := q.client.Get(ctx, iter.Val()).Int64()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to count crawl workers: %w", err)
+		}
+		total += workers
+	}
+	if err := iter.Err(); err != nil {
+		return 0, fmt.Errorf("failed to count crawl workers: %w", err)
+	}
+	return total, nil
+}
+
+// RetryDead moves a dead task back to the pending list of its priority with
+// its retries reset
+func (q *CrawlQueue) RetryDead(ctx context.Context, id string) (*models.CrawlTask, error) {
+	removed, err := q.client.LRem(ctx, crawlQueueKey("dead"), 1, id).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retry crawl task: %w", err)
+	}
+	if removed == 0 {
+		return nil, ErrCrawlTaskNotFound
+	}
+
+	task, err := q.loadTask(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil {
+		return nil, ErrCrawlTaskNotFound
+	}
+	task.Attempts = 0
+
+	_, err = q.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, crawlTaskKey(id), "attempts", 0)
+		pipe.RPush(ctx, pendingKey(task.Priority), id)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to retry crawl task: %w", err)
+	}
+	return task, nil
+}
+
+// PurgeDead deletes every dead task and returns how many there were
+func (q *CrawlQueue) PurgeDead(ctx context.Context) (int, error) {
+	ids, err := q.client.LRange(ctx, crawlQueueKey("dead"), 0, -1).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge dead crawl tasks: %w", err)
+	}
+
+	_, err = q.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, id := range ids {
+			pipe.LRem(ctx, crawlQueueKey("dead"), 1, id)
+			pipe.Del(ctx, crawlTaskKey(id))
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge dead crawl tasks: %w", err)
+	}
+	return len(ids), nil
+}
+
+// Start runs workers that pull crawls from the queue until the returned stop
+// function is called. Crawls already running are finished; their tasks are
+// requeued by other workers if the process exits first.
+func (q *CrawlQueue) Start(runner CrawlRunner, workers int) (stop func()) {
+	if workers <= 0 {
+		workers = 1
+	}
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+
+	// The workers are registered per process, so the queue can report how
+	// many there are across all processes
+	id := make([]byte, 8)
+	rand.Read(id)
+	workersKey := crawlQueueKey("workers:" + hex.EncodeToString(id))
+	if err := q.registerWorkers(context.Background(), workersKey, workers); err != nil {
+		log.Printf("Failed to register crawl queue workers: %v", err)
+	}
+
+	// Retries and tasks of lost workers are moved back by one maintenance loop
+	ticker := time.NewTicker(q.options.PollInterval)
+	q.heartbeat.start(q.options.PollInterval, time.Now())
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case now := <-ticker.C:
+				if _, err := q.promote(context.Background(), now); err != nil {
+					log.Printf("Crawl queue maintenance failed: %v", err)
+				}
+				if err := q.registerWorkers(context.Background(), workersKey, workers); err != nil {
+					log.Printf("Failed to register crawl queue workers: %v", err)
+				}
+				q.heartbeat.beat(now)
+			case <-done:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			q.work(runner, done)
+		}()
+	}
+
+	return func() {
+		close(done)
+		wg.Wait()
+		q.client.Del(context.Background(), workersKey)
+	}
+}
+
+// work runs tasks one at a time until done is closed
+func (q *CrawlQueue) work(runner CrawlRunner, done <-chan struct{}) {
+	for {
+		select {
+		case <-done:
+			return
+		default:
+		}
+
+		task, err := q.dequeue(context.Background(), time.Now())
+		if err != nil {
+			log.Printf("Crawl queue worker failed: %v", err)
+		}
+		if task == nil {
+			select {
+			case <-done:
+				return
+			case <-time.After(q.options.PollInterval):
+			}
+			continue
+		}
+		q.run(runner, task)
+	}
+}
+
+// run runs a task, renewing its lease while the crawl is in progress
+func (q *CrawlQueue) run(runner CrawlRunner, task *models.CrawlTask) {
+	ctx := context.Background()
+	running := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(q.options.LeaseTimeout / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case now := <-ticker.C:
+				if err := q.renewLease(ctx, task, now); err != nil {
+					log.Printf("Failed to renew lease of crawl task %s: %v", task.ID, err)
+				}
+			case <-running:
+				return
+			}
+		}
+	}()
+
+	err := runCrawlTask(runner, task)
+	close(running)
+
+	if err != nil {
+		log.Printf("Crawl task %s for URL %d failed: %v", task.ID, task.URLID, err)
+		err = q.fail(ctx, task, err, time.Now())
+	} else {
+		err = q.complete(ctx, task)
+	}
+	if err != nil {
+		log.Printf("Crawl queue worker failed: %v", err)
+	}
+}
+
+// runCrawlTask runs the crawl of a task, turning a panic into an error
+func runCrawlTask(runner CrawlRunner, task *models.CrawlTask) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("crawl panicked: %v", recovered)
+		}
+	}()
+	return runner.RunCrawl(context.Background(), task.URLID)
+}
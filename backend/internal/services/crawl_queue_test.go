@@ -0,0 +1,301 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"web-crawler-backend/internal/models"
+)
+
+func setupCrawlQueueTest(t *testing.T, options CrawlQueueOptions) *CrawlQueue {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return NewCrawlQueue(client, options)
+}
+
+func queueStats(t *testing.T, queue *CrawlQueue) *models.CrawlQueueStats {
+	stats, err := queue.Stats(context.Background())
+	require.NoError(t, err)
+	return stats
+}
+
+func TestCrawlQueue_EnqueueAndComplete(t *testing.T) {
+	queue := setupCrawlQueueTest(t, CrawlQueueOptions{})
+	ctx := context.Background()
+	now := time.Now()
+
+	task, err := queue.Enqueue(ctx, 7, models.CrawlPriorityLow)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), queueStats(t, queue).Pending)
+
+	claimed, err := queue.dequeue(ctx, now)
+	require.NoError(t, err)
+	require.NotNil(t, claimed)
+	assert.Equal(t, task.ID, claimed.ID)
+	assert.Equal(t, uint(7), claimed.URLID)
+	stats := queueStats(t, queue)
+	assert.Equal(t, int64(0), stats.Pending)
+	assert.Equal(t, int64(1), stats.Active)
+
+	empty, err := queue.dequeue(ctx, now)
+	require.NoError(t, err)
+	assert.Nil(t, empty)
+
+	require.NoError(t, queue.complete(ctx, claimed))
+	assert.Equal(t, &models.CrawlQueueStats{
+		PendingTasks:   []models.CrawlTask{},
+		ScheduledTasks: []models.CrawlTask{},
+		DeadTasks:      []models.CrawlTask{},
+	}, queueStats(t, queue))
+}
+
+func TestCrawlQueue_RetriesAndDeadLetters(t *testing.T) {
+	queue := setupCrawlQueueTest(t, CrawlQueueOptions{MaxRetries: 1, RetryBaseDelay: time.Minute})
+	ctx := context.Background()
+	now := time.Now()
+
+	_, err := queue.Enqueue(ctx, 7, models.CrawlPriorityLow)
+	require.NoError(t, err)
+	task, err := queue.dequeue(ctx, now)
+	require.NoError(t, err)
+	require.NoError(t, queue.fail(ctx, task, errors.New("database is down"), now))
+	assert.Equal(t, int64(1), queueStats(t, queue).Scheduled)
+
+	// The retry waits for its backoff
+	moved, err := queue.promote(ctx, now.Add(30*time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, 0, moved)
+	moved, err = queue.promote(ctx, now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 1, moved)
+
+	task, err = queue.dequeue(ctx, now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 1, task.Attempts)
+	require.NoError(t, queue.fail(ctx, task, errors.New("database is still down"), now.Add(time.Minute)))
+
+	stats := queueStats(t, queue)
+	assert.Equal(t, int64(0), stats.Scheduled)
+	assert.Equal(t, int64(1), stats.Dead)
+	require.Len(t, stats.DeadTasks, 1)
+	assert.Equal(t, 2, stats.DeadTasks[0].Attempts)
+	assert.Equal(t, "database is still down", stats.DeadTasks[0].LastError)
+	assert.NotNil(t, stats.DeadTasks[0].FailedAt)
+
+	_, err = queue.RetryDead(ctx, "unknown")
+	assert.ErrorIs(t, err, ErrCrawlTaskNotFound)
+	retried, err := queue.RetryDead(ctx, task.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 0, retried.Attempts)
+	stats = queueStats(t, queue)
+	assert.Equal(t, int64(1), stats.Pending)
+	assert.Equal(t, int64(0), stats.Dead)
+}
+
+func TestCrawlQueue_CancelAndRequeue(t *testing.T) {
+	queue := setupCrawlQueueTest(t, CrawlQueueOptions{MaxRetries: 1, RetryBaseDelay: time.Minute})
+	ctx := context.Background()
+	now := time.Now()
+
+	running, err := queue.Enqueue(ctx, 1, models.CrawlPriorityLow)
+	require.NoError(t, err)
+	_, err = queue.dequeue(ctx, now)
+	require.NoError(t, err)
+	require.NoError(t, queue.fail(ctx, running, errors.New("database is down"), now))
+	high, err := queue.Enqueue(ctx, 2, models.CrawlPriorityHigh)
+	require.NoError(t, err)
+	low, err := queue.Enqueue(ctx, 3, models.CrawlPriorityLow)
+	require.NoError(t, err)
+
+	stats := queueStats(t, queue)
+	require.Len(t, stats.PendingTasks, 2)
+	assert.Equal(t, high.ID, stats.PendingTasks[0].ID)
+	assert.Equal(t, low.ID, stats.PendingTasks[1].ID)
+	require.Len(t, stats.ScheduledTasks, 1)
+	require.NotNil(t, stats.ScheduledTasks[0].RetryAt)
+	assert.WithinDuration(t, now.Add(time.Minute), *stats.ScheduledTasks[0].RetryAt, time.Millisecond)
+
+	t.Run("a pending task is cancelled", func(t *testing.T) {
+		require.NoError(t, queue.CancelTask(ctx, low.ID))
+		assert.ErrorIs(t, queue.CancelTask(ctx, low.ID), ErrCrawlTaskNotFound)
+		assert.Equal(t, int64(1), queueStats(t, queue).Pending)
+	})
+
+	t.Run("a scheduled task is requeued without waiting", func(t *testing.T) {
+		_, err := queue.RequeueTask(ctx, "unknown")
+		assert.ErrorIs(t, err, ErrCrawlTaskNotFound)
+		requeued, err := queue.RequeueTask(ctx, running.ID)
+		require.NoError(t, err)
+		assert.Equal(t, uint(1), requeued.URLID)
+		stats := queueStats(t, queue)
+		assert.Equal(t, int64(0), stats.Scheduled)
+		assert.Equal(t, int64(2), stats.Pending)
+	})
+
+	t.Run("a running task can't be cancelled", func(t *testing.T) {
+		claimed, err := queue.dequeue(ctx, now)
+		require.NoError(t, err)
+		assert.ErrorIs(t, queue.CancelTask(ctx, claimed.ID), ErrCrawlTaskRunning)
+	})
+}
+
+func TestCrawlQueue_LostWorker(t *testing.T) {
+	queue := setupCrawlQueueTest(t, CrawlQueueOptions{MaxRetries: 1, LeaseTimeout: time.Minute})
+	ctx := context.Background()
+	now := time.Now()
+
+	_, err := queue.Enqueue(ctx, 7, models.CrawlPriorityLow)
+	require.NoError(t, err)
+	task, err := queue.dequeue(ctx, now)
+	require.NoError(t, err)
+
+	// A renewed lease keeps the task with its worker
+	require.NoError(t, queue.renewLease(ctx, task, now.Add(50*time.Second)))
+	moved, err := queue.promote(ctx, now.Add(90*time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, 0, moved)
+
+	moved, err = queue.promote(ctx, now.Add(2*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 1, moved)
+	task, err = queue.dequeue(ctx, now.Add(2*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 1, task.Attempts)
+	assert.Equal(t, lostWorkerError, task.LastError)
+
+	// The worker that lost the task can no longer fail it
+	stale := *task
+	moved, err = queue.promote(ctx, now.Add(5*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 1, moved)
+	require.NoError(t, queue.fail(ctx, &stale, errors.New("late"), now.Add(5*time.Minute)))
+
+	stats := queueStats(t, queue)
+	assert.Equal(t, int64(1), stats.Dead)
+	assert.Equal(t, lostWorkerError, stats.DeadTasks[0].LastError)
+
+	purged, err := queue.PurgeDead(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, purged)
+	assert.Equal(t, int64(0), queueStats(t, queue).Dead)
+}
+
+func TestCrawlQueue_Priority(t *testing.T) {
+	queue := setupCrawlQueueTest(t, CrawlQueueOptions{MaxRetries: 1, RetryBaseDelay: time.Minute})
+	ctx := context.Background()
+	now := time.Now()
+
+	for _, urlID := range []uint{1, 2} {
+		_, err := queue.Enqueue(ctx, urlID, models.CrawlPriorityLow)
+		require.NoError(t, err)
+	}
+	_, err := queue.Enqueue(ctx, 3, models.CrawlPriorityHigh)
+	require.NoError(t, err)
+	stats := queueStats(t, queue)
+	assert.Equal(t, int64(3), stats.Pending)
+	assert.Equal(t, int64(1), stats.PendingHigh)
+	assert.Equal(t, int64(2), stats.PendingLow)
+
+	// High priority tasks jump ahead of low priority ones enqueued earlier
+	task, err := queue.dequeue(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, uint(3), task.URLID)
+	assert.Equal(t, models.CrawlPriorityHigh, task.Priority)
+
+	// and keep their priority when retried
+	require.NoError(t, queue.fail(ctx, task, errors.New("database is down"), now))
+	_, err = queue.promote(ctx, now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), queueStats(t, queue).PendingHigh)
+
+	var order []uint
+	for {
+		task, err := queue.dequeue(ctx, now.Add(time.Minute))
+		require.NoError(t, err)
+		if task == nil {
+			break
+		}
+		order = append(order, task.URLID)
+	}
+	assert.Equal(t, []uint{3, 1, 2}, order)
+}
+
+// fakeCrawlRunner records the crawls it runs and fails or panics for chosen URLs
+type fakeCrawlRunner struct {
+	mu   sync.Mutex
+	runs []uint
+}
+
+func (r *fakeCrawlRunner) RunCrawl(ctx context.Context, urlID uint) error {
+	r.mu.Lock()
+	r.runs = append(r.runs, urlID)
+	r.mu.Unlock()
+
+	switch urlID {
+	case 2:
+		return errors.New("database is down")
+	case 3:
+		panic("crawler bug")
+	}
+	return nil
+}
+
+func (r *fakeCrawlRunner) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.runs)
+}
+
+func TestCrawlQueue_Start(t *testing.T) {
+	queue := setupCrawlQueueTest(t, CrawlQueueOptions{MaxRetries: 0, PollInterval: 10 * time.Millisecond})
+	for _, urlID := range []uint{1, 2, 3} {
+		_, err := queue.Enqueue(context.Background(), urlID, models.CrawlPriorityLow)
+		require.NoError(t, err)
+	}
+
+	runner := &fakeCrawlRunner{}
+	stop := queue.Start(runner, 2)
+	assert.Eventually(t, func() bool {
+		return queueStats(t, queue).Dead == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, int64(2), queueStats(t, queue).Workers)
+	stop()
+
+	assert.Equal(t, 3, runner.count())
+	stats := queueStats(t, queue)
+	assert.Equal(t, int64(0), stats.Pending)
+	assert.Equal(t, int64(0), stats.Active)
+	assert.Equal(t, int64(0), stats.Workers, "stopped workers are unregistered")
+	lastErrors := []string{stats.DeadTasks[0].LastError, stats.DeadTasks[1].LastError}
+	assert.ElementsMatch(t, []string{"database is down", "crawl panicked: crawler bug"}, lastErrors)
+}
+
+func TestCrawlerService_QueuesCrawls(t *testing.T) {
+	queue := setupCrawlQueueTest(t, CrawlQueueOptions{})
+	db := setupCrawlerTestDB(t)
+	url := &models.URL{URL: "https://example.com", Status: "pending"}
+	require.NoError(t, db.Create(url).Error)
+
+	service := NewCrawlerService(db).WithQueue(queue)
+	service.StartCrawl(url.ID)
+
+	assert.Equal(t, int64(1), queueStats(t, queue).PendingHigh)
+	var crawls int64
+	require.NoError(t, db.Model(&models.Crawl{}).Count(&crawls).Error)
+	assert.Equal(t, int64(0), crawls)
+
+	// Bulk reruns wait behind crawls users started
+	require.NoError(t, service.BulkRerunCrawls([]uint{url.ID}))
+	assert.Eventually(t, func() bool {
+		return queueStats(t, queue).PendingLow == 1
+	}, 5*time.Second, 10*time.Millisecond)
+}
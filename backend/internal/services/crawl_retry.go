@@ -0,0 +1,70 @@
+package services
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// defaultRetryBaseDelay is the first backoff delay when none is configured
+	defaultRetryBaseDelay = time.Second
+	// defaultRetryMaxDelay caps backoff and Retry-After delays when none is configured
+	defaultRetryMaxDelay = 30 * time.Second
+)
+
+// retryDelay decides whether a failed seed request is retried and how long to
+// wait first. Network errors other than unknown hosts, 429 and 5xx responses are retried up to
+// MaxRetries times with exponential backoff; a Retry-After header overrides
+// the backoff. attempt is the number of requests made so far.
+func (s *CrawlerService) retryDelay(attempt int, resp *http.Response, err error) (time.Duration, bool) {
+	if attempt > s.options.MaxRetries {
+		return 0, false
+	}
+	if err == nil && resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < 500 {
+		return 0, false
+	}
+	// A host that does not exist will not appear on a retry
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+		return 0, false
+	}
+
+	baseDelay, maxDelay := s.options.RetryBaseDelay, s.options.RetryMaxDelay
+	if baseDelay <= 0 {
+		baseDelay = defaultRetryBaseDelay
+	}
+	if maxDelay <= 0 {
+		maxDelay = defaultRetryMaxDelay
+	}
+
+	delay := baseDelay << (attempt - 1)
+	if err == nil {
+		if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			delay = retryAfter
+		}
+	}
+	if delay > maxDelay || delay < 0 {
+		delay = maxDelay
+	}
+	return delay, true
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(0, date.Sub(now)), true
+	}
+	return 0, false
+}
@@ -0,0 +1,152 @@
+package services
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"web-crawler-backend/internal/models"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	delay, ok := parseRetryAfter("7", now)
+	assert.True(t, ok)
+	assert.Equal(t, 7*time.Second, delay)
+
+	delay, ok = parseRetryAfter("Wed, 01 May 2024 12:00:30 GMT", now)
+	assert.True(t, ok)
+	assert.Equal(t, 30*time.Second, delay)
+
+	delay, ok = parseRetryAfter("Wed, 01 May 2024 11:00:00 GMT", now)
+	assert.True(t, ok)
+	assert.Zero(t, delay)
+
+	for _, value := range []string{"", "-1", "soon"} {
+		_, ok = parseRetryAfter(value, now)
+		assert.False(t, ok, value)
+	}
+}
+
+func TestCrawlerService_retryDelay(t *testing.T) {
+	service := NewCrawlerServiceWithOptions(nil, CrawlerOptions{MaxRetries: 3, RetryBaseDelay: time.Second, RetryMaxDelay: 3 * time.Second})
+	response := func(status int, retryAfter string) *http.Response {
+		resp := &http.Response{StatusCode: status, Header: http.Header{}}
+		if retryAfter != "" {
+			resp.Header.Set("Retry-After", retryAfter)
+		}
+		return resp
+	}
+
+	tests := []struct {
+		name    string
+		attempt int
+		resp    *http.Response
+		err     error
+		delay   time.Duration
+		retry   bool
+	}{
+		{"network error", 1, nil, errors.New("connection refused"), time.Second, true},
+		{"backoff doubles", 2, response(503, ""), nil, 2 * time.Second, true},
+		{"backoff is capped", 3, response(500, ""), nil, 3 * time.Second, true},
+		{"retry after", 1, response(429, "3"), nil, 3 * time.Second, true},
+		{"retry after is capped", 1, response(429, "3600"), nil, 3 * time.Second, true},
+		{"unknown host", 1, nil, &net.DNSError{Err: "no such host", Name: "missing.example", IsNotFound: true}, 0, false},
+		{"retries exhausted", 4, response(503, ""), nil, 0, false},
+		{"client error", 1, response(404, ""), nil, 0, false},
+		{"success", 1, response(200, ""), nil, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delay, retry := service.retryDelay(tt.attempt, tt.resp, tt.err)
+			assert.Equal(t, tt.retry, retry)
+			assert.Equal(t, tt.delay, delay)
+		})
+	}
+}
+
+func TestCrawlerService_RetriesSeedRequest(t *testing.T) {
+	options := DefaultCrawlerOptions()
+	options.RetryBaseDelay = time.Millisecond
+	options.RetryMaxDelay = 10 * time.Millisecond
+
+	t.Run("succeeds after transient failures", func(t *testing.T) {
+		db := setupCrawlerTestDB(t)
+		service := NewCrawlerServiceWithOptions(db, options)
+
+		var requests atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch requests.Add(1) {
+			case 1:
+				w.WriteHeader(http.StatusServiceUnavailable)
+			case 2:
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusTooManyRequests)
+			default:
+				w.Header().Set("Content-Type", "text/html")
+				w.Write([]byte("<html><head><title>Back</title></head></html>"))
+			}
+		}))
+		defer server.Close()
+
+		urlRecord := &models.URL{URL: server.URL, Status: "pending"}
+		require.NoError(t, db.Create(urlRecord).Error)
+		service.StartCrawl(urlRecord.ID)
+
+		var crawl models.Crawl
+		require.NoError(t, db.Where("url_id = ?", urlRecord.ID).First(&crawl).Error)
+		assert.Equal(t, "completed", crawl.Status)
+		assert.Equal(t, 3, crawl.Attempts)
+		assert.Equal(t, "Back", crawl.Title)
+	})
+
+	t.Run("gives up after the retry limit", func(t *testing.T) {
+		db := setupCrawlerTestDB(t)
+		service := NewCrawlerServiceWithOptions(db, options)
+
+		var requests atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer server.Close()
+
+		urlRecord := &models.URL{URL: server.URL, Status: "pending"}
+		require.NoError(t, db.Create(urlRecord).Error)
+		service.StartCrawl(urlRecord.ID)
+
+		var crawl models.Crawl
+		require.NoError(t, db.Where("url_id = ?", urlRecord.ID).First(&crawl).Error)
+		assert.Equal(t, "error", crawl.Status)
+		assert.Equal(t, 3, crawl.Attempts)
+		assert.Equal(t, int32(3), requests.Load())
+		assert.Contains(t, crawl.ErrorMessage, "502")
+	})
+
+	t.Run("does not retry client errors", func(t *testing.T) {
+		db := setupCrawlerTestDB(t)
+		service := NewCrawlerServiceWithOptions(db, options)
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		urlRecord := &models.URL{URL: server.URL, Status: "pending"}
+		require.NoError(t, db.Create(urlRecord).Error)
+		service.StartCrawl(urlRecord.ID)
+
+		var crawl models.Crawl
+		require.NoError(t, db.Where("url_id = ?", urlRecord.ID).First(&crawl).Error)
+		assert.Equal(t, 1, crawl.Attempts)
+	})
+}
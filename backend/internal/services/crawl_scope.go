@@ -0,0 +1,149 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"web-crawler-backend/internal/models"
+)
+
+const (
+	// maxScopePatterns caps how many include or exclude patterns a URL may have
+	maxScopePatterns = 50
+	// maxScopeQueryParams caps the max_query_params setting
+	maxScopeQueryParams = 50
+)
+
+// crawlScope decides which discovered pages a deep crawl may visit
+type crawlScope struct {
+	host            string
+	allowSubdomains bool
+	include         []*regexp.Regexp
+	exclude         []*regexp.Regexp
+	stripQuery      bool
+	maxQueryParams  int
+}
+
+// newCrawlScope builds the scope of a URL's deep crawl. Patterns are
+// validated when saved, so one that no longer compiles is logged and skipped.
+func newCrawlScope(urlRecord *models.URL, rootURL *url.URL) *crawlScope {
+	scope := &crawlScope{
+		host:            rootURL.Host,
+		allowSubdomains: urlRecord.AllowSubdomains,
+		stripQuery:      urlRecord.StripQuery,
+		maxQueryParams:  urlRecord.MaxQueryParams,
+	}
+
+	for _, list := range []struct {
+		patterns []string
+		compiled *[]*regexp.Regexp
+	}{{urlRecord.IncludePatterns, &scope.include}, {urlRecord.ExcludePatterns, &scope.exclude}} {
+		for _, pattern := range list.patterns {
+			re, err := compileScopePattern(pattern)
+			if err != nil {
+				log.Printf("Skipping crawl scope pattern %q of URL %d: %v", pattern, urlRecord.ID, err)
+				continue
+			}
+			*list.compiled = append(*list.compiled, re)
+		}
+	}
+
+	return scope
+}
+
+// resolve returns the normalized page URL to visit for a link, and false if
+// the link is outside the crawl scope
+func (s *crawlScope) resolve(link string) (string, bool) {
+	linkURL, err := url.Parse(link)
+	if err != nil || (linkURL.Scheme != "http" && linkURL.Scheme != "https") {
+		return "", false
+	}
+	if linkURL.Host != s.host && !(s.allowSubdomains && strings.HasSuffix(linkURL.Host, "."+s.host)) {
+		return "", false
+	}
+
+	if s.maxQueryParams > 0 && len(linkURL.Query()) > s.maxQueryParams {
+		return "", false
+	}
+	if s.stripQuery {
+		linkURL.RawQuery = ""
+		linkURL.ForceQuery = false
+	}
+
+	target := linkURL.Path
+	if target == "" {
+		target = "/"
+	}
+	if linkURL.RawQuery != "" {
+		target += "?" + linkURL.RawQuery
+	}
+
+	if len(s.include) > 0 && !matchesAny(s.include, target) {
+		return "", false
+	}
+	if matchesAny(s.exclude, target) {
+		return "", false
+	}
+
+	return normalizePageURL(linkURL), true
+}
+
+func matchesAny(patterns []*regexp.Regexp, target string) bool {
+	for _, re := range patterns {
+		if re.MatchString(target) {
+			return true
+		}
+	}
+	return false
+}
+
+// compileScopePattern compiles an include or exclude pattern. Patterns are
+// matched against the path and query of a page, e.g. /blog/post?id=1.
+// A "re:" prefix marks a regular expression; anything else is a glob where
+// * matches any run of characters and ? a single one.
+func compileScopePattern(pattern string) (*regexp.Regexp, error) {
+	if expr, ok := strings.CutPrefix(pattern, "re:"); ok {
+		return regexp.Compile(expr)
+	}
+
+	var b strings.Builder
+	b.WriteString("^")
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
+
+// validateScopePatterns trims a pattern list and checks that every pattern compiles
+func validateScopePatterns(field string, patterns []string) ([]string, error) {
+	if len(patterns) > maxScopePatterns {
+		return nil, fmt.Errorf("%w: %s may have at most %d patterns", ErrInvalidCrawlSettings, field, maxScopePatterns)
+	}
+
+	cleaned := make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		if strings.Contains(pattern, "\n") {
+			return nil, fmt.Errorf("%w: %s pattern %q contains a line break", ErrInvalidCrawlSettings, field, pattern)
+		}
+		if _, err := compileScopePattern(pattern); err != nil {
+			return nil, fmt.Errorf("%w: invalid %s pattern %q: %v", ErrInvalidCrawlSettings, field, pattern, err)
+		}
+		cleaned = append(cleaned, pattern)
+	}
+	return cleaned, nil
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"web-crawler-backend/internal/models"
+)
+
+func TestCrawlScope_resolve(t *testing.T) {
+	root, err := url.Parse("https://example.com/")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		record models.URL
+		link   string
+		want   string
+		ok     bool
+	}{
+		{"same host", models.URL{}, "https://example.com/about#team", "https://example.com/about", true},
+		{"other host", models.URL{}, "https://other.com/", "", false},
+		{"subdomain not allowed", models.URL{}, "https://blog.example.com/", "", false},
+		{"subdomain allowed", models.URL{AllowSubdomains: true}, "https://blog.example.com/post", "https://blog.example.com/post", true},
+		{"lookalike host", models.URL{AllowSubdomains: true}, "https://notexample.com/", "", false},
+		{"non-http scheme", models.URL{}, "mailto:hi@example.com", "", false},
+		{"glob exclude", models.URL{ExcludePatterns: []string{"/admin/*"}}, "https://example.com/admin/users", "", false},
+		{"glob matches whole path", models.URL{ExcludePatterns: []string{"/admin"}}, "https://example.com/admin/users", "https://example.com/admin/users", true},
+		{"regex exclude", models.URL{ExcludePatterns: []string{`re:^/calendar/\d{4}`}}, "https://example.com/calendar/2024/05", "", false},
+		{"include miss", models.URL{IncludePatterns: []string{"/blog*"}}, "https://example.com/shop", "", false},
+		{"include hit", models.URL{IncludePatterns: []string{"/blog*"}}, "https://example.com/blog/post", "https://example.com/blog/post", true},
+		{"exclude query", models.URL{ExcludePatterns: []string{"*?*sort=*"}}, "https://example.com/shop?sort=price", "", false},
+		{"too many query params", models.URL{MaxQueryParams: 1}, "https://example.com/shop?a=1&b=2", "", false},
+		{"query params within limit", models.URL{MaxQueryParams: 2}, "https://example.com/shop?a=1&b=2", "https://example.com/shop?a=1&b=2", true},
+		{"strip query", models.URL{StripQuery: true, ExcludePatterns: []string{"*sort=*"}}, "https://example.com/shop?sort=price", "https://example.com/shop", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := newCrawlScope(&tt.record, root).resolve(tt.link)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestValidateScopePatterns(t *testing.T) {
+	patterns, err := validateScopePatterns("exclude_patterns", []string{" /admin/* ", "", "re:^/tag/"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/admin/*", "re:^/tag/"}, patterns)
+
+	_, err = validateScopePatterns("exclude_patterns", []string{"re:[a-"})
+	assert.ErrorIs(t, err, ErrInvalidCrawlSettings)
+
+	_, err = validateScopePatterns("include_patterns", make([]string, maxScopePatterns+1))
+	assert.ErrorIs(t, err, ErrInvalidCrawlSettings)
+}
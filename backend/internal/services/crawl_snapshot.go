@@ -0,0 +1,250 @@
+package services
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"time"
+
+	"golang.org/x/net/html"
+	"gorm.io/gorm"
+
+	"web-crawler-backend/internal/models"
+	"web-crawler-backend/internal/storage"
+)
+
+var (
+	// ErrCrawlNotFound is returned for crawls that don't exist
+	ErrCrawlNotFound = errors.New("crawl not found")
+	// ErrNoSnapshot is returned for crawls whose page wasn't kept
+	ErrNoSnapshot = errors.New("crawl has no stored snapshot")
+	// ErrCrawlNotCompleted is returned when reprocessing a crawl that hasn't completed
+	ErrCrawlNotCompleted = errors.New("only completed crawls can be reprocessed")
+)
+
+// WithSnapshots returns a copy of the service that keeps the HTML of every
+// seed page in store, so crawls can be reprocessed without fetching again
+func (s *CrawlerService) WithSnapshots(store storage.Storage) *CrawlerService {
+	copied := *s
+	copied.snapshots = store
+	return &copied
+}
+
+// WithContext returns a copy of the service whose queries and storage
+// requests are cancelled with ctx
+func (s *CrawlerService) WithContext(ctx context.Context) *CrawlerService {
+	copied := *s
+	copied.db = s.db.WithContext(ctx)
+	return &copied
+}
+
+// snapshotKey is where the page of a crawl is kept
+func snapshotKey(crawl *models.Crawl) string {
+	return fmt.Sprintf("url-%d/crawl-%d.html.gz", crawl.URLID, crawl.ID)
+}
+
+// storeSnapshot keeps the gzipped page of a crawl and records its key. A
+// failure only costs the ability to reprocess, so the crawl goes on.
+func (s *CrawlerService) storeSnapshot(crawl *models.Crawl, body []byte) {
+	if s.snapshots == nil {
+		return
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	gz.Write(body)
+	if err := gz.Close(); err != nil {
+		log.Printf("Failed to compress snapshot of crawl %d: %v", crawl.ID, err)
+		return
+	}
+
+	key := snapshotKey(crawl)
+	if err := s.snapshots.Put(s.traceContext(), key, buf.Bytes(), "application/gzip"); err != nil {
+		log.Printf("Failed to store snapshot of crawl %d: %v", crawl.ID, err)
+		return
+	}
+	crawl.SnapshotKey = key
+}
+
+// loadSnapshot returns the page kept for a crawl
+func (s *CrawlerService) loadSnapshot(crawl *models.Crawl) ([]byte, error) {
+	if s.snapshots == nil || crawl.SnapshotKey == "" {
+		return nil, ErrNoSnapshot
+	}
+
+	body, _, err := s.snapshots.Get(s.traceContext(), crawl.SnapshotKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, ErrNoSnapshot
+		}
+		return nil, err
+	}
+	defer body.Close()
+
+	gz, err := gzip.NewReader(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	defer gz.Close()
+	page, err := io.ReadAll(gz)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	return page, nil
+}
+
+// ReprocessCrawl extracts the data of a completed crawl again from its
+// snapshot, so improvements to the extraction apply to past crawls. No
+// requests are sent: link and image checks are taken from the crawl's
+// previous results, and links it didn't have before stay unchecked.
+// Security headers and timings are kept as they were.
+func (s *CrawlerService) ReprocessCrawl(crawlID uint) (*models.Crawl, error) {
+	var crawl models.Crawl
+	if err := s.db.First(&crawl, crawlID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCrawlNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch crawl: %w", err)
+	}
+	if crawl.Status != "completed" {
+		return nil, ErrCrawlNotCompleted
+	}
+
+	var urlRecord models.URL
+	if err := s.db.Preload("ExtractionRules").First(&urlRecord, crawl.URLID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCrawlNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch URL: %w", err)
+	}
+
+	page, err := s.loadSnapshot(&crawl)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := html.Parse(bytes.NewReader(page))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot: %w", err)
+	}
+
+	data, _ := s.parsePageData(doc, &urlRecord)
+	seedSources := seedLinkSources(&urlRecord)
+	if err := s.reuseChecks(&crawl, data, seedSources); err != nil {
+		return nil, err
+	}
+	if err := s.compareKeywords(&crawl, data.KeywordChecks); err != nil {
+		return nil, err
+	}
+	applyCrawlData(&crawl, data)
+	now := time.Now()
+
+	// Broken links found on deeper pages of a deep crawl are kept as they were
+	var deepBroken int64
+	if err := s.db.Model(&models.Link{}).Where("crawl_id = ? AND found_on_url NOT IN ? AND is_accessible = ?", crawl.ID, seedSources, false).
+		Count(&deepBroken).Error; err != nil {
+		return nil, fmt.Errorf("failed to count broken links: %w", err)
+	}
+	crawl.InternalLinks += int(deepBroken)
+	crawl.BrokenLinks += int(deepBroken)
+	crawl.ReprocessedAt = &now
+
+	var latestID uint
+	if err := s.db.Model(&models.Crawl{}).Where("url_id = ?", crawl.URLID).
+		Order("created_at DESC, id DESC").Limit(1).Pluck("id", &latestID).Error; err != nil {
+		return nil, fmt.Errorf("failed to find latest crawl: %w", err)
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("crawl_id = ? AND found_on_url IN ?", crawl.ID, seedSources).Delete(&models.Link{}).Error; err != nil {
+			return err
+		}
+		for _, child := range []interface{}{&models.Image{}, &models.Form{}, &models.AccessibilityIssue{}, &models.MixedContentIssue{}, &models.PageMeta{}} {
+			if err := tx.Where("crawl_id = ?", crawl.ID).Delete(child).Error; err != nil {
+				return err
+			}
+		}
+		if err := s.insertCrawlData(tx, &urlRecord, &crawl, data); err != nil {
+			return err
+		}
+		if err := updateCrawl(tx, &crawl); err != nil {
+			return err
+		}
+		// The URL shows the results of its latest crawl only
+		if latestID == crawl.ID {
+			applyURLData(&urlRecord, data)
+			return tx.Model(&models.URL{}).Where("id = ?", urlRecord.ID).Updates(urlDataColumns(&urlRecord)).Error
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save reprocessed crawl: %w", err)
+	}
+	return &crawl, nil
+}
+
+// reuseChecks copies the link, image and icon checks of the crawl's stored
+// results for the seed page to the extracted data and counts broken links.
+// Internal links are assumed accessible as during crawls; new external links
+// and images stay unchecked and count as accessible.
+func (s *CrawlerService) reuseChecks(crawl *models.Crawl, data *CrawlData, seedSources []string) error {
+	var links []models.Link
+	if err := s.db.Where("crawl_id = ? AND found_on_url IN ?", crawl.ID, seedSources).Find(&links).Error; err != nil {
+		return fmt.Errorf("failed to fetch links: %w", err)
+	}
+	checkedLinks := make(map[string]models.Link, len(links))
+	for _, link := range links {
+		checkedLinks[link.LinkURL] = link
+	}
+
+	data.BrokenLinks = 0
+	for i := range data.Links {
+		link := &data.Links[i]
+		if checked, ok := checkedLinks[link.LinkURL]; ok {
+			link.StatusCode = checked.StatusCode
+			link.IsAccessible = checked.IsAccessible
+		} else if link.LinkType == "internal" {
+			link.StatusCode = 200
+		}
+		if !link.IsAccessible {
+			data.BrokenLinks++
+		}
+	}
+
+	var images []models.Image
+	if err := s.db.Where("crawl_id = ?", crawl.ID).Find(&images).Error; err != nil {
+		return fmt.Errorf("failed to fetch images: %w", err)
+	}
+	checkedImages := make(map[string]models.Image, len(images))
+	for _, image := range images {
+		checkedImages[image.Src] = image
+	}
+	for i := range data.Images {
+		if checked, ok := checkedImages[data.Images[i].Src]; ok {
+			data.Images[i].StatusCode = checked.StatusCode
+			data.Images[i].IsBroken = checked.IsBroken
+		}
+	}
+
+	var meta models.PageMeta
+	err := s.db.Where("crawl_id = ?", crawl.ID).Limit(1).Find(&meta).Error
+	if err != nil {
+		return fmt.Errorf("failed to fetch page metadata: %w", err)
+	}
+	reuseIconChecks(data.Meta.Icons, meta.Icons)
+	return nil
+}
+
+// seedLinkSources are the found_on_url values of the links on the seed page
+// of a URL. Links stored before their page was recorded have none.
+func seedLinkSources(urlRecord *models.URL) []string {
+	sources := []string{""}
+	if parsed, err := url.Parse(urlRecord.URL); err == nil {
+		sources = append(sources, parsed.String())
+	}
+	return sources
+}
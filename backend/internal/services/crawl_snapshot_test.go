@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"web-crawler-backend/internal/models"
+	"web-crawler-backend/internal/storage"
+)
+
+func TestCrawlerService_ReprocessCrawl(t *testing.T) {
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer broken.Close()
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head><title>Snapshot</title></head><body><h1>Snapshot</h1>` +
+			`<a href="` + broken.URL + `/missing">Missing</a><img src="/logo.png"></body></html>`))
+	}))
+	defer server.Close()
+
+	db := setupCrawlerTestDB(t)
+	service := NewCrawlerService(db).WithSnapshots(storage.NewLocal(t.TempDir()))
+
+	urlRecord := &models.URL{URL: server.URL, Status: "pending"}
+	require.NoError(t, db.Create(urlRecord).Error)
+	require.NoError(t, service.RunCrawl(context.Background(), urlRecord.ID))
+
+	var crawl models.Crawl
+	require.NoError(t, db.Where("url_id = ?", urlRecord.ID).First(&crawl).Error)
+	require.Equal(t, "completed", crawl.Status)
+	assert.Equal(t, "url-1/crawl-1.html.gz", crawl.SnapshotKey)
+	assert.Equal(t, 1, crawl.BrokenLinks)
+
+	t.Run("extracts again without requests", func(t *testing.T) {
+		require.NoError(t, db.Model(&crawl).Update("title", "Stale").Error)
+		require.NoError(t, db.Model(urlRecord).Update("title", "Stale").Error)
+		requests.Store(0)
+
+		reprocessed, err := service.ReprocessCrawl(crawl.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int32(0), requests.Load())
+		assert.Equal(t, "Snapshot", reprocessed.Title)
+		assert.Equal(t, 1, reprocessed.BrokenLinks)
+		assert.NotNil(t, reprocessed.ReprocessedAt)
+
+		var links []models.Link
+		require.NoError(t, db.Where("crawl_id = ?", crawl.ID).Find(&links).Error)
+		require.Len(t, links, 1)
+		assert.Equal(t, http.StatusNotFound, links[0].StatusCode)
+		assert.False(t, links[0].IsAccessible)
+
+		var images []models.Image
+		require.NoError(t, db.Where("crawl_id = ?", crawl.ID).Find(&images).Error)
+		require.Len(t, images, 1)
+		assert.Equal(t, http.StatusOK, images[0].StatusCode)
+
+		var updatedURL models.URL
+		require.NoError(t, db.First(&updatedURL, urlRecord.ID).Error)
+		assert.Equal(t, "Snapshot", updatedURL.Title)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := service.ReprocessCrawl(999)
+		assert.ErrorIs(t, err, ErrCrawlNotFound)
+
+		_, err = NewCrawlerService(db).ReprocessCrawl(crawl.ID)
+		assert.ErrorIs(t, err, ErrNoSnapshot)
+
+		running := &models.Crawl{URLID: urlRecord.ID, Status: "running"}
+		require.NoError(t, db.Create(running).Error)
+		_, err = service.ReprocessCrawl(running.ID)
+		assert.ErrorIs(t, err, ErrCrawlNotCompleted)
+	})
+}
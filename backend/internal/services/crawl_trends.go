@@ -0,0 +1,44 @@
+package services
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"web-crawler-backend/internal/models"
+)
+
+// GetCrawlTrends returns the link counts and durations of the URL's latest
+// completed crawls, oldest first, so they can be plotted over time
+func (s *URLService) GetCrawlTrends(urlID uint, limit int) (*models.CrawlTrends, error) {
+	var url models.URL
+	if err := s.db.First(&url, urlID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrURLNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch URL: %w", err)
+	}
+
+	var crawls []models.Crawl
+	if err := s.db.Where("url_id = ? AND status = ?", urlID, "completed").
+		Order("created_at DESC").Limit(limit).Find(&crawls).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch crawls: %w", err)
+	}
+
+	trends := &models.CrawlTrends{URLID: urlID, Points: make([]models.CrawlTrendPoint, len(crawls))}
+	for i, crawl := range crawls {
+		point := models.CrawlTrendPoint{
+			CrawlID:       crawl.ID,
+			CrawledAt:     crawl.CreatedAt,
+			BrokenLinks:   crawl.BrokenLinks,
+			InternalLinks: crawl.InternalLinks,
+			ExternalLinks: crawl.ExternalLinks,
+			PagesCrawled:  crawl.PagesCrawled,
+		}
+		if crawl.StartedAt != nil && crawl.CompletedAt != nil {
+			point.DurationMs = crawl.CompletedAt.Sub(*crawl.StartedAt).Milliseconds()
+		}
+		trends.Points[len(crawls)-1-i] = point
+	}
+	return trends, nil
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"web-crawler-backend/internal/models"
+)
+
+func TestURLService_GetCrawlTrends(t *testing.T) {
+	db := setupURLTestDB(t)
+	service := NewURLService(db, &mockCrawlerService{})
+
+	url := &models.URL{URL: "https://example.com", Status: "completed"}
+	require.NoError(t, db.Create(url).Error)
+
+	base := time.Now().Add(-time.Hour)
+	started := base.Add(time.Second)
+	completed := started.Add(1500 * time.Millisecond)
+	crawls := []*models.Crawl{
+		{URLID: url.ID, Status: "completed", CreatedAt: base, BrokenLinks: 5, InternalLinks: 10, ExternalLinks: 3, PagesCrawled: 1, StartedAt: &started, CompletedAt: &completed},
+		{URLID: url.ID, Status: "error", CreatedAt: base.Add(time.Minute)},
+		{URLID: url.ID, Status: "completed", CreatedAt: base.Add(2 * time.Minute), BrokenLinks: 2, InternalLinks: 12, ExternalLinks: 4, PagesCrawled: 3},
+		{URLID: url.ID, Status: "completed", CreatedAt: base.Add(3 * time.Minute), BrokenLinks: 0, InternalLinks: 12, ExternalLinks: 4},
+	}
+	for _, crawl := range crawls {
+		require.NoError(t, db.Create(crawl).Error)
+	}
+
+	trends, err := service.GetCrawlTrends(url.ID, 10)
+	require.NoError(t, err)
+	assert.Equal(t, url.ID, trends.URLID)
+	require.Len(t, trends.Points, 3)
+
+	// Oldest first, failed crawls left out
+	oldest := trends.Points[0]
+	assert.Equal(t, crawls[0].ID, oldest.CrawlID)
+	assert.Equal(t, 5, oldest.BrokenLinks)
+	assert.Equal(t, 10, oldest.InternalLinks)
+	assert.Equal(t, 3, oldest.ExternalLinks)
+	assert.Equal(t, int64(1500), oldest.DurationMs)
+	assert.Equal(t, crawls[2].ID, trends.Points[1].CrawlID)
+	assert.Zero(t, trends.Points[1].DurationMs)
+	assert.Equal(t, crawls[3].ID, trends.Points[2].CrawlID)
+
+	// The limit keeps the latest crawls
+	trends, err = service.GetCrawlTrends(url.ID, 2)
+	require.NoError(t, err)
+	require.Len(t, trends.Points, 2)
+	assert.Equal(t, crawls[2].ID, trends.Points[0].CrawlID)
+
+	_, err = service.GetCrawlTrends(url.ID+1, 10)
+	assert.ErrorIs(t, err, ErrURLNotFound)
+}
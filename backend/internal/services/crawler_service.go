@@ -1,37 +1,126 @@
 package services
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"math"
 	"net/http"
 	"net/url"
+	"regexp"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"golang.org/x/net/html"
 	"web-crawler-backend/internal/models"
+	"web-crawler-backend/internal/repository"
+	"web-crawler-backend/internal/storage"
 )
 
 type CrawlerService struct {
-	db *gorm.DB
+	db      *gorm.DB
+	options CrawlerOptions
+	cache   *CacheService
+	queue   *CrawlQueue
+	quota   *QuotaService
+	// linkChecks remembers external link checks across crawls; nil checks every time
+	linkChecks *LinkCheckCache
+	// flags turns features such as deep crawls off per user; nil uses the defaults
+	flags *FeatureFlagService
+	// snapshots keeps the HTML of seed pages for reprocessing; nil keeps none
+	snapshots storage.Storage
+	// extractors run on every page after the built-in ones
+	extractors []Extractor
+	// store replaces db for the queries moved to repositories; nil uses db
+	store repository.Store
+}
+
+// CrawlerOptions holds tunable crawler settings
+type CrawlerOptions struct {
+	// MaxConcurrency is the initial number of parallel link checks per host
+	MaxConcurrency int
+	// MaxHostQPS is the initial request rate per host; it is reduced when a host slows down
+	MaxHostQPS float64
+	// MaxPages is the page limit for deep crawls of URLs without their own limit
+	MaxPages int
+	// MaxResponseBytes is the largest page body downloaded; bigger pages are skipped
+	MaxResponseBytes int64
+	// MaxRetries is how often a seed request failing with a network error, 429 or 5xx is retried
+	MaxRetries int
+	// RetryBaseDelay is the first backoff delay; it doubles with every retry
+	RetryBaseDelay time.Duration
+	// RetryMaxDelay caps the backoff and any Retry-After delay requested by the server
+	RetryMaxDelay time.Duration
+	// InsertBatchSize is how many links or issues are inserted per statement
+	InsertBatchSize int
+}
+
+// DefaultCrawlerOptions returns the settings used when none are configured
+func DefaultCrawlerOptions() CrawlerOptions {
+	return CrawlerOptions{
+		MaxConcurrency:   5,
+		MaxHostQPS:       10,
+		MaxPages:         100,
+		MaxResponseBytes: defaultMaxResponseBytes,
+		MaxRetries:       2,
+		RetryBaseDelay:   defaultRetryBaseDelay,
+		RetryMaxDelay:    defaultRetryMaxDelay,
+		InsertBatchSize:  200,
+	}
 }
 
 // Ensure CrawlerService implements CrawlerServiceInterface
 var _ CrawlerServiceInterface = (*CrawlerService)(nil)
 
 func NewCrawlerService(db *gorm.DB) *CrawlerService {
-	return &CrawlerService{db: db}
+	return NewCrawlerServiceWithOptions(db, DefaultCrawlerOptions())
+}
+
+// NewCrawlerServiceWithOptions creates a crawler service with custom settings
+func NewCrawlerServiceWithOptions(db *gorm.DB, options CrawlerOptions) *CrawlerService {
+	return &CrawlerService{db: db, options: options}
 }
 
 // StartCrawl initiates the crawling process for a URL
 func (s *CrawlerService) StartCrawl(urlID uint) {
+	s.StartCrawlContext(context.Background(), urlID)
+}
+
+// WithQueue returns a copy of the service that hands crawls to a queue
+// instead of running them itself; a nil queue runs them in process
+func (s *CrawlerService) WithQueue(queue *CrawlQueue) *CrawlerService {
+	copied := *s
+	copied.queue = queue
+	return &copied
+}
+
+// WithQuota returns a copy of the service that caps deep crawls at the page
+// quota of the user who added the URL; a nil quota leaves them uncapped
+func (s *CrawlerService) WithQuota(quota *QuotaService) *CrawlerService {
+	copied := *s
+	copied.quota = quota
+	return &copied
+}
+
+// startCrawl runs a crawl of a URL. It returns an error if the crawl could
+// not be started; a URL deleted in the meantime is not an error.
+func (s *CrawlerService) startCrawl(urlID uint) error {
 	// Get URL record
 	var urlRecord models.URL
-	if err := s.db.First(&urlRecord, urlID).Error; err != nil {
+	if err := s.db.Preload("ExtractionRules").First(&urlRecord, urlID).Error; err != nil {
 		log.Printf("Failed to find URL record %d: %v", urlID, err)
-		return
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to find URL %d: %w", urlID, err)
 	}
 
 	// Create crawl record
@@ -44,14 +133,15 @@ func (s *CrawlerService) StartCrawl(urlID uint) {
 
 	if err := s.db.Create(crawl).Error; err != nil {
 		log.Printf("Failed to create crawl record: %v", err)
-		return
+		return fmt.Errorf("failed to create crawl record: %w", err)
 	}
 
 	// Update URL status
-	s.db.Model(&urlRecord).Update("status", "running")
+	s.db.Model(&models.URL{}).Where("id = ?", urlID).Update("status", "running")
 
 	// Perform crawling
 	s.performCrawl(&urlRecord, crawl)
+	return nil
 }
 
 // performCrawl does the actual crawling work
@@ -60,32 +150,153 @@ func (s *CrawlerService) performCrawl(urlRecord *models.URL, crawl *models.Crawl
 		// Complete crawl
 		now := time.Now()
 		crawl.CompletedAt = &now
-		s.db.Save(crawl)
+		if err := updateCrawl(s.db, crawl); err != nil {
+			log.Printf("Failed to update crawl %d: %v", crawl.ID, err)
+		}
 
-		// Update URL status
+		// Update URL status; the results of an unchanged page are still current.
+		// Only the columns the crawl owns are written: the URL may have been
+		// edited while the crawl ran, or deleted, and a deleted URL stays in
+		// the trash.
 		urlRecord.Status = crawl.Status
-		s.db.Save(urlRecord)
+		if crawl.Status == "unchanged" {
+			urlRecord.Status = "completed"
+		}
+		columns := map[string]interface{}{}
+		if crawl.Status == "completed" {
+			columns = urlDataColumns(urlRecord)
+		}
+		columns["status"] = urlRecord.Status
+		if err := s.db.Unscoped().Model(&models.URL{}).Where("id = ?", urlRecord.ID).Updates(columns).Error; err != nil {
+			log.Printf("Failed to update URL %d: %v", urlRecord.ID, err)
+		}
+
+		s.recordCrawlFinished(urlRecord, crawl)
+		s.checkBrokenLinkThreshold(urlRecord, crawl)
+	}()
+
+	events := newCrawlEventLog(crawl)
+	defer func() {
+		outcome := crawl.Status
+		if crawl.ErrorMessage != "" {
+			outcome += ": " + crawl.ErrorMessage
+		} else if crawl.SkipReason != "" {
+			outcome += ": " + crawl.SkipReason
+		}
+		events.add(models.CrawlEventFinished, "Crawl %s", outcome)
+		s.saveCrawlEvents(events)
 	}()
 
-	// Make HTTP request
-	resp, err := http.Get(urlRecord.URL)
+	throttle := NewHostThrottle(s.options.MaxConcurrency, s.options.MaxHostQPS)
+	defer func() {
+		crawl.CrawlLog = strings.Join(throttle.Log(), "\n")
+	}()
+
+	// A deep crawl turned off by its feature flag only crawls the root page.
+	// The setting is restored before the URL is saved, which happens in an
+	// earlier deferred function.
+	if depth := urlRecord.MaxDepth; depth > 0 && !s.flags.Enabled(models.FeatureDeepCrawl, ownerID(urlRecord)) {
+		urlRecord.MaxDepth = 0
+		defer func() { urlRecord.MaxDepth = depth }()
+		events.add(models.CrawlEventFeatureDisabled, "Deep crawls are turned off, crawling the root page only")
+	}
+
+	// Make HTTP request, retrying transient failures
+	validators := s.conditionalHeader(urlRecord)
+	fetchNote := ""
+	if validators != nil {
+		fetchNote = ", conditional"
+	}
+	seedHost := hostOf(urlRecord.URL)
+	var resp *http.Response
+	var timer *responseTimer
+	var fetchStart time.Time
+	var err error
+	for {
+		crawl.Attempts++
+		throttle.Acquire(seedHost)
+		events.add(models.CrawlEventFetchStarted, "GET %s (attempt %d%s)", urlRecord.URL, crawl.Attempts, fetchNote)
+		fetchStart = time.Now()
+		resp, timer, err = timedGetWithHeader(s.traceContext(), urlRecord.URL, validators)
+
+		delay, retry := s.retryDelay(crawl.Attempts, resp, err)
+		if !retry {
+			break
+		}
+
+		statusCode := 0
+		if resp != nil {
+			statusCode = resp.StatusCode
+			resp.Body.Close()
+			log.Printf("URL %s returned status %d, retrying in %s", urlRecord.URL, statusCode, delay)
+			events.add(models.CrawlEventRetryScheduled, "HTTP %d, retrying in %s", statusCode, delay)
+		} else {
+			log.Printf("Failed to fetch URL %s, retrying in %s: %v", urlRecord.URL, delay, err)
+			events.add(models.CrawlEventRetryScheduled, "Request failed, retrying in %s: %v", delay, err)
+		}
+		throttle.Release(seedHost, time.Since(fetchStart), statusCode)
+		time.Sleep(delay)
+	}
 	if err != nil {
+		throttle.Release(seedHost, time.Since(fetchStart), 0)
 		crawl.Status = "error"
 		crawl.ErrorMessage = fmt.Sprintf("HTTP request failed: %v", err)
 		log.Printf("Failed to fetch URL %s: %v", urlRecord.URL, err)
 		return
 	}
-	defer resp.Body.Close()
-
+	events.add(models.CrawlEventResponseReceived, "HTTP %d over %s after %d ms", resp.StatusCode, resp.Proto, timer.Metrics.TTFBMs)
+	if resp.StatusCode == http.StatusNotModified && validators != nil {
+		resp.Body.Close()
+		throttle.Release(seedHost, time.Since(fetchStart), resp.StatusCode)
+		crawl.ResponseMetrics = timer.Metrics
+		crawl.Status = "unchanged"
+		crawl.ETag = validators.Get("If-None-Match")
+		crawl.LastModified = validators.Get("If-Modified-Since")
+		recordValidators(crawl, resp.Header)
+		log.Printf("URL %s is unchanged since its last crawl", urlRecord.URL)
+		return
+	}
 	if resp.StatusCode >= 400 {
+		resp.Body.Close()
+		throttle.Release(seedHost, time.Since(fetchStart), resp.StatusCode)
+		crawl.ResponseMetrics = timer.Metrics
 		crawl.Status = "error"
 		crawl.ErrorMessage = fmt.Sprintf("HTTP %d: %s", resp.StatusCode, resp.Status)
 		log.Printf("URL %s returned status %d", urlRecord.URL, resp.StatusCode)
 		return
 	}
 
+	err = checkContentType(resp)
+	if err == nil {
+		err = checkContentLength(resp, s.responseLimit())
+	}
+	if err == nil {
+		err = timer.readBody(resp, s.responseLimit())
+	} else {
+		resp.Body.Close()
+	}
+	throttle.Release(seedHost, time.Since(fetchStart), resp.StatusCode)
+	crawl.ResponseMetrics = timer.Metrics
+	if skip, ok := err.(*skipError); ok {
+		crawl.Status = "skipped"
+		crawl.SkipReason = skip.reason
+		log.Printf("Skipped URL %s: %s", urlRecord.URL, skip.reason)
+		return
+	}
+	if err != nil {
+		crawl.Status = "error"
+		crawl.ErrorMessage = fmt.Sprintf("Reading response failed: %v", err)
+		log.Printf("Failed to read response of URL %s: %v", urlRecord.URL, err)
+		return
+	}
+
+	// Keep the page so its results can be extracted again later
+	body, _ := io.ReadAll(resp.Body)
+	events.add(models.CrawlEventBodyReceived, "Received %d bytes in %d ms", timer.Metrics.ResponseBytes, timer.Metrics.DownloadMs)
+	s.storeSnapshot(crawl, body)
+
 	// Parse HTML
-	doc, err := html.Parse(resp.Body)
+	doc, err := html.Parse(bytes.NewReader(body))
 	if err != nil {
 		crawl.Status = "error"
 		crawl.ErrorMessage = fmt.Sprintf("HTML parsing failed: %v", err)
@@ -94,95 +305,332 @@ func (s *CrawlerService) performCrawl(urlRecord *models.URL, crawl *models.Crawl
 	}
 
 	// Extract data
-	data := s.extractData(doc, urlRecord.URL)
+	data := s.extractDataWithThrottle(doc, urlRecord, throttle, events)
+	if err := s.compareKeywords(crawl, data.KeywordChecks); err != nil {
+		log.Printf("Failed to compare keywords of URL %s: %v", urlRecord.URL, err)
+	}
+	applyURLData(urlRecord, data)
+	applyCrawlData(crawl, data)
 
-	// Update URL record
+	securityHeaders, securityScore, securityChecks := analyzeSecurityHeaders(resp.Header, resp.Request.URL.Scheme == "https")
+	securityHeadersJSON, _ := json.Marshal(securityHeaders)
+	securityChecksJSON, _ := json.Marshal(securityChecks)
+	crawl.SecurityScore = securityScore
+	crawl.SecurityHeaders = string(securityHeadersJSON)
+	crawl.SecurityChecks = string(securityChecksJSON)
+	recordValidators(crawl, resp.Header)
+	crawl.Status = "completed"
+
+	if err := s.saveCrawlData(urlRecord, crawl, data); err != nil {
+		crawl.Status = "error"
+		crawl.ErrorMessage = fmt.Sprintf("Saving crawl results failed: %v", err)
+		log.Printf("Failed to save crawl results for URL %s: %v", urlRecord.URL, err)
+		return
+	}
+	s.recordKeywordsMissing(urlRecord, crawl, data.KeywordChecks)
+
+	// Store the root page and follow internal links for deep crawls
+	s.crawlSite(urlRecord, crawl, data, resp.StatusCode, throttle)
+	if urlRecord.MaxDepth > 0 {
+		events.add(models.CrawlEventPagesCrawled, "Deep crawl visited %d pages", crawl.PagesCrawled)
+	}
+}
+
+// conditionalHeader returns the validators of the URL's latest completed
+// crawl as conditional request headers, or nil if there are none. Deep
+// crawls always fetch the page: deeper pages may change although it didn't.
+func (s *CrawlerService) conditionalHeader(urlRecord *models.URL) http.Header {
+	if urlRecord.MaxDepth > 0 {
+		return nil
+	}
+
+	var last models.Crawl
+	if err := s.db.Where("url_id = ? AND status = ?", urlRecord.ID, "completed").
+		Order("created_at DESC, id DESC").Limit(1).Find(&last).Error; err != nil {
+		log.Printf("Failed to find the last crawl of URL %s: %v", urlRecord.URL, err)
+		return nil
+	}
+
+	header := http.Header{}
+	if last.ETag != "" {
+		header.Set("If-None-Match", last.ETag)
+	}
+	if last.LastModified != "" {
+		header.Set("If-Modified-Since", last.LastModified)
+	}
+	if len(header) == 0 {
+		return nil
+	}
+	return header
+}
+
+// recordValidators keeps the ETag and Last-Modified headers of the seed page
+// for the next crawl, ignoring values too long to store
+func recordValidators(crawl *models.Crawl, header http.Header) {
+	if etag := header.Get("ETag"); etag != "" && len(etag) <= 255 {
+		crawl.ETag = etag
+	}
+	if lastModified := header.Get("Last-Modified"); lastModified != "" && len(lastModified) <= 64 {
+		crawl.LastModified = lastModified
+	}
+}
+
+// applyURLData updates a URL with the results of its latest crawl
+func applyURLData(urlRecord *models.URL, data *CrawlData) {
 	urlRecord.Title = data.Title
 	urlRecord.HTMLVersion = data.HTMLVersion
-	urlRecord.HasLoginForm = data.HasLoginForm
+	if urlRecord.LoginFormOverride != nil {
+		urlRecord.HasLoginForm = *urlRecord.LoginFormOverride
+	} else {
+		urlRecord.HasLoginForm = data.HasLoginForm
+	}
+}
+
+// updateCrawl writes a crawl back. Unlike Save it never inserts, so a crawl
+// purged with its URL while it ran isn't brought back.
+func updateCrawl(db *gorm.DB, crawl *models.Crawl) error {
+	return db.Model(crawl).Select("*").Omit(clause.Associations).Updates(crawl).Error
+}
+
+// urlDataColumns returns the columns of a URL applyURLData sets, for updates
+// that leave the rest of the URL as it is
+func urlDataColumns(urlRecord *models.URL) map[string]interface{} {
+	return map[string]interface{}{
+		"title":          urlRecord.Title,
+		"html_version":   urlRecord.HTMLVersion,
+		"has_login_form": urlRecord.HasLoginForm,
+	}
+}
 
-	// Update crawl record
+// applyCrawlData records the results extracted from the seed page on the crawl
+func applyCrawlData(crawl *models.Crawl, data *CrawlData) {
 	crawl.InternalLinks = data.InternalLinks
 	crawl.ExternalLinks = data.ExternalLinks
 	crawl.BrokenLinks = data.BrokenLinks
-	
+
 	headingCountsJSON, _ := json.Marshal(data.HeadingCounts)
 	crawl.HeadingCounts = string(headingCountsJSON)
-	crawl.Status = "completed"
+	crawl.Title = data.Title
+	crawl.ContentHash = data.ContentHash
+	crawl.WordCount = data.WordCount
+	crawl.ReadabilityScore = data.Readability
+	crawl.LoginFormDetected = data.HasLoginForm
+	crawl.LoginFormConfidence = loginFormConfidence(data.LoginFormScore)
+	loginEvidenceJSON, _ := json.Marshal(data.LoginFormEvidence)
+	crawl.LoginFormEvidence = string(loginEvidenceJSON)
 
-	// Save links
-	for _, link := range data.Links {
-		link.URLID = urlRecord.ID
-		link.CrawlID = crawl.ID
-		s.db.Create(&link)
+	seoScore, seoChecks := analyzeSEO(data)
+	seoChecksJSON, _ := json.Marshal(seoChecks)
+	crawl.SEOScore = seoScore
+	crawl.SEOChecks = string(seoChecksJSON)
+
+	crawl.ExtractedData = ""
+	if len(data.Extracted) > 0 {
+		extractedJSON, err := json.Marshal(data.Extracted)
+		if err != nil {
+			log.Printf("Failed to encode extracted data of crawl %d: %v", crawl.ID, err)
+		} else {
+			crawl.ExtractedData = string(extractedJSON)
+		}
+	}
+
+	crawl.KeywordChecks = ""
+	if len(data.KeywordChecks) > 0 {
+		keywordChecksJSON, _ := json.Marshal(data.KeywordChecks)
+		crawl.KeywordChecks = string(keywordChecksJSON)
+	}
+}
+
+// saveCrawlData stores the links, images, forms, issues and meta tags of the seed page
+// in one transaction, inserting rows in batches
+func (s *CrawlerService) saveCrawlData(urlRecord *models.URL, crawl *models.Crawl, data *CrawlData) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		return s.insertCrawlData(tx, urlRecord, crawl, data)
+	})
+}
+
+// insertCrawlData inserts the seed page results within tx
+func (s *CrawlerService) insertCrawlData(tx *gorm.DB, urlRecord *models.URL, crawl *models.Crawl, data *CrawlData) error {
+	for i := range data.Links {
+		data.Links[i].URLID = urlRecord.ID
+		data.Links[i].CrawlID = crawl.ID
+	}
+	for i := range data.Images {
+		data.Images[i].URLID = urlRecord.ID
+		data.Images[i].CrawlID = crawl.ID
+	}
+	for i := range data.Forms {
+		data.Forms[i].URLID = urlRecord.ID
+		data.Forms[i].CrawlID = crawl.ID
+	}
+	for i := range data.AccessibilityIssues {
+		data.AccessibilityIssues[i].URLID = urlRecord.ID
+		data.AccessibilityIssues[i].CrawlID = crawl.ID
+	}
+	for i := range data.MixedContentIssues {
+		data.MixedContentIssues[i].URLID = urlRecord.ID
+		data.MixedContentIssues[i].CrawlID = crawl.ID
+	}
+	data.Meta.URLID = urlRecord.ID
+	data.Meta.CrawlID = crawl.ID
+
+	batchSize := s.insertBatchSize()
+	if err := createInBatches(tx, data.Links, batchSize); err != nil {
+		return fmt.Errorf("links: %w", err)
+	}
+	if err := createInBatches(tx, data.Images, batchSize); err != nil {
+		return fmt.Errorf("images: %w", err)
 	}
+	if err := createInBatches(tx, data.Forms, batchSize); err != nil {
+		return fmt.Errorf("forms: %w", err)
+	}
+	if err := createInBatches(tx, data.AccessibilityIssues, batchSize); err != nil {
+		return fmt.Errorf("accessibility issues: %w", err)
+	}
+	if err := createInBatches(tx, data.MixedContentIssues, batchSize); err != nil {
+		return fmt.Errorf("mixed content issues: %w", err)
+	}
+	if err := tx.Create(&data.Meta).Error; err != nil {
+		return fmt.Errorf("page meta: %w", err)
+	}
+	return nil
+}
+
+// insertBatchSize returns how many rows are inserted per statement
+func (s *CrawlerService) insertBatchSize() int {
+	if s.options.InsertBatchSize > 0 {
+		return s.options.InsertBatchSize
+	}
+	return DefaultCrawlerOptions().InsertBatchSize
+}
+
+// createInBatches inserts rows with as few statements as the batch size allows;
+// GORM rejects empty slices, so those are skipped
+func createInBatches[T any](tx *gorm.DB, rows []T, batchSize int) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	return tx.CreateInBatches(rows, batchSize).Error
+}
+
+// recordCrawlFinished adds the finished crawl to the URL owner's activity feed
+func (s *CrawlerService) recordCrawlFinished(urlRecord *models.URL, crawl *models.Crawl) {
+	if urlRecord.UserID == nil {
+		return
+	}
+
+	event := models.ActivityEvent{
+		UserID:  *urlRecord.UserID,
+		Type:    models.ActivityCrawlCompleted,
+		URLID:   &urlRecord.ID,
+		CrawlID: &crawl.ID,
+		Message: fmt.Sprintf("Crawl of %s completed", urlRecord.URL),
+	}
+	metadata := map[string]interface{}{
+		"broken_links":  crawl.BrokenLinks,
+		"pages_crawled": crawl.PagesCrawled,
+		"seo_score":     crawl.SEOScore,
+	}
+	if crawl.Status == "error" {
+		event.Type = models.ActivityCrawlFailed
+		event.Message = fmt.Sprintf("Crawl of %s failed", urlRecord.URL)
+		metadata = map[string]interface{}{"error": crawl.ErrorMessage}
+	}
+	if crawl.Status == "unchanged" {
+		event.Message = fmt.Sprintf("Crawl of %s found the page unchanged", urlRecord.URL)
+		metadata = map[string]interface{}{"unchanged": true}
+	}
+	if crawl.Status == "skipped" {
+		event.Type = models.ActivityCrawlFailed
+		event.Message = fmt.Sprintf("Crawl of %s skipped", urlRecord.URL)
+		metadata = map[string]interface{}{"skip_reason": crawl.SkipReason}
+	}
+
+	recordActivity(s.db, event, metadata)
 }
 
 // CrawlData holds extracted data from crawling
 type CrawlData struct {
-	Title         string
-	HTMLVersion   string
-	HasLoginForm  bool
+	Title             string
+	HTMLVersion       string
+	HasLoginForm      bool
+	LoginFormScore    int
+	LoginFormEvidence []string
 	HeadingCounts models.HeadingCounts
-	InternalLinks int
+	InternalLinks int // Links on the page, counting repeated ones
 	ExternalLinks int
 	BrokenLinks   int
-	Links         []models.Link
+	Links         []models.Link // One per distinct URL
+	Images        []models.Image
+	Forms         []models.Form
+	Meta          models.PageMeta
+	ContentHash   string // SHA-256 of the normalized page text
+
+	AccessibilityIssues []models.AccessibilityIssue
+	MixedContentIssues  []models.MixedContentIssue
+
+	// Extracted holds the results of custom extractors by name
+	Extracted map[string]interface{}
+	// KeywordChecks reports the URL's keywords found on the page
+	KeywordChecks []models.KeywordCheck
+	// linkIndex locates the link to each URL in Links
+	linkIndex map[string]int
+
+	// WordCount and Readability describe the visible text; Readability is
+	// the Flesch reading ease, nil for pages without text
+	WordCount   int
+	Readability *float64
 }
 
 // extractData extracts relevant data from HTML document
 func (s *CrawlerService) extractData(doc *html.Node, baseURL string) *CrawlData {
+	return s.extractDataWithThrottle(doc, &models.URL{URL: baseURL}, NewHostThrottle(s.options.MaxConcurrency, s.options.MaxHostQPS), nil)
+}
+
+// extractDataWithThrottle extracts data with every extractor but the ones the
+// URL disables, checking links through the given host throttle
+func (s *CrawlerService) extractDataWithThrottle(doc *html.Node, urlRecord *models.URL, throttle *HostThrottle, events *crawlEventLog) *CrawlData {
+	data, ok := s.parsePageData(doc, urlRecord)
+	if ok {
+		events.add(models.CrawlEventParseComplete, "Parsed %s page: %d links, %d images, %d forms",
+			data.HTMLVersion, len(data.Links), len(data.Images), len(data.Forms))
+		s.checkLinkAccessibility(data, throttle, urlRecord.CheckInternalLinks, events)
+		s.checkImageAvailability(data, throttle)
+		s.checkIconAvailability(data, throttle)
+		events.add(models.CrawlEventChecksComplete, "Checks complete: %d broken links", data.BrokenLinks)
+	}
+	return data
+}
+
+// parsePageData extracts everything from a page that needs no requests. It
+// reports false if the URL doesn't parse, leaving the data empty.
+func (s *CrawlerService) parsePageData(doc *html.Node, urlRecord *models.URL) (*CrawlData, bool) {
 	data := &CrawlData{
-		HTMLVersion:   "HTML5", // Default assumption
+		HTMLVersion:   "Unknown", // Set from the doctype during traversal
 		HeadingCounts: models.HeadingCounts{},
 		Links:         []models.Link{},
+		Meta: models.PageMeta{
+			OpenGraph:   map[string]string{},
+			TwitterCard: map[string]string{},
+		},
 	}
 
-	parsedBaseURL, err := url.Parse(baseURL)
+	parsedBaseURL, err := url.Parse(urlRecord.URL)
 	if err != nil {
-		log.Printf("Failed to parse base URL %s: %v", baseURL, err)
-		return data
+		log.Printf("Failed to parse base URL %s: %v", urlRecord.URL, err)
+		return data, false
 	}
 
-	s.traverseHTML(doc, data, parsedBaseURL)
-	s.checkLinkAccessibility(data)
+	s.pipeline(urlRecord.DisabledExtractors).run(doc, &PageContext{BaseURL: parsedBaseURL, Data: data, URL: urlRecord})
+	data.ContentHash = contentHash(doc)
 
-	return data
+	return data, true
 }
 
-// traverseHTML recursively traverses HTML nodes to extract data
+// traverseHTML hands every node below n to every extractor, without the
+// analyses of the whole document
 func (s *CrawlerService) traverseHTML(n *html.Node, data *CrawlData, baseURL *url.URL) {
-	if n.Type == html.ElementNode {
-		switch n.Data {
-		case "title":
-			if data.Title == "" && n.FirstChild != nil {
-				data.Title = strings.TrimSpace(n.FirstChild.Data)
-			}
-		case "h1":
-			data.HeadingCounts.H1++
-		case "h2":
-			data.HeadingCounts.H2++
-		case "h3":
-			data.HeadingCounts.H3++
-		case "h4":
-			data.HeadingCounts.H4++
-		case "h5":
-			data.HeadingCounts.H5++
-		case "h6":
-			data.HeadingCounts.H6++
-		case "a":
-			s.processLink(n, data, baseURL)
-		case "form":
-			s.checkLoginForm(n, data)
-		case "html":
-			s.detectHTMLVersion(n, data)
-		}
-	}
-
-	// Traverse children
-	for c := n.FirstChild; c != nil; c = c.NextSibling {
-		s.traverseHTML(c, data, baseURL)
-	}
+	s.pipeline(nil).traverse(n, &PageContext{BaseURL: baseURL, Data: data})
 }
 
 // processLink processes anchor tags and categorizes links
@@ -213,118 +661,623 @@ func (s *CrawlerService) processLink(n *html.Node, data *CrawlData, baseURL *url
 	}
 
 	resolvedURL := baseURL.ResolveReference(linkURL)
-	
-	// Determine link type
-	linkType := "external"
-	if resolvedURL.Host == baseURL.Host {
-		linkType = "internal"
-	}
+	linkType := classifyLink(linkURL, resolvedURL, baseURL)
+
+	rel := strings.Join(strings.Fields(strings.ToLower(getAttr(n, "rel"))), " ")
+	relValues := strings.Fields(rel)
 
 	link := models.Link{
 		LinkURL:      resolvedURL.String(),
+		FoundOnURL:   baseURL.String(),
 		LinkText:     linkText,
 		LinkType:     linkType,
 		StatusCode:   0, // Will be set during accessibility check
 		IsAccessible: true,
+		Rel:          rel,
+		Target:       strings.TrimSpace(getAttr(n, "target")),
+		Nofollow:     slices.Contains(relValues, "nofollow"),
+		Sponsored:    slices.Contains(relValues, "sponsored"),
+		UGC:          slices.Contains(relValues, "ugc"),
+		Context:      linkContext(n),
+		Occurrences:  1,
 	}
 
-	data.Links = append(data.Links, link)
+	// Repeated links, e.g. in the navigation and footer, are stored and checked once
+	if i, seen := data.linkIndex[link.LinkURL]; seen {
+		data.Links[i].Occurrences++
+		if data.Links[i].LinkText == "" {
+			data.Links[i].LinkText = linkText
+		}
+	} else {
+		if data.linkIndex == nil {
+			data.linkIndex = map[string]int{}
+		}
+		data.linkIndex[link.LinkURL] = len(data.Links)
+		data.Links = append(data.Links, link)
+	}
 
-	if linkType == "internal" {
+	switch linkType {
+	case "internal":
 		data.InternalLinks++
-	} else {
+	case "external":
 		data.ExternalLinks++
 	}
 }
 
-// checkLoginForm checks if the form might be a login form
+// classifyLink returns the type of a link: internal or external for web
+// pages, anchor for a fragment of the same page, mailto and tel for contact
+// links, and other for schemes like javascript: or ftp:. Only internal and
+// external links are counted and checked.
+func classifyLink(href, resolved, baseURL *url.URL) string {
+	if href.Scheme == "" && href.Host == "" && href.Path == "" && href.RawQuery == "" && href.Fragment != "" {
+		return "anchor"
+	}
+
+	switch strings.ToLower(resolved.Scheme) {
+	case "http", "https":
+		if resolved.Host == baseURL.Host {
+			return "internal"
+		}
+		return "external"
+	case "mailto":
+		return "mailto"
+	case "tel":
+		return "tel"
+	default:
+		return "other"
+	}
+}
+
+// processMeta records description, robots, Open Graph and Twitter Card meta tags
+func (s *CrawlerService) processMeta(n *html.Node, data *CrawlData) {
+	name := strings.ToLower(strings.TrimSpace(getAttr(n, "name")))
+	property := strings.ToLower(strings.TrimSpace(getAttr(n, "property")))
+	content := strings.TrimSpace(getAttr(n, "content"))
+
+	if content == "" {
+		return
+	}
+
+	meta := &data.Meta
+	switch {
+	case name == "description":
+		if meta.Description == "" {
+			meta.Description = content
+		}
+	case name == "robots":
+		if meta.Robots == "" {
+			meta.Robots = content
+		}
+	case name == "viewport":
+		if meta.Viewport == "" {
+			meta.Viewport = content
+		}
+	case strings.HasPrefix(property, "og:"):
+		setIfMissing(&meta.OpenGraph, property, content)
+	case strings.HasPrefix(name, "twitter:"):
+		setIfMissing(&meta.TwitterCard, name, content)
+	case strings.HasPrefix(property, "twitter:"):
+		// Some sites use property= for Twitter tags too
+		setIfMissing(&meta.TwitterCard, property, content)
+	}
+}
+
+// linkContext reports whether a link sits in the page's navigation or footer
+func linkContext(n *html.Node) string {
+	for p := n.Parent; p != nil; p = p.Parent {
+		if p.Type != html.ElementNode {
+			continue
+		}
+		role := strings.ToLower(getAttr(p, "role"))
+		switch {
+		case p.Data == "nav" || role == "navigation":
+			return models.LinkContextNav
+		case p.Data == "footer" || role == "contentinfo":
+			return models.LinkContextFooter
+		}
+	}
+	return models.LinkContextContent
+}
+
+// processLinkTag records the canonical URL from <link rel="canonical"> and
+// the AMP variant from <link rel="amphtml">
+func (s *CrawlerService) processLinkTag(n *html.Node, data *CrawlData, baseURL *url.URL) {
+	href := strings.TrimSpace(getAttr(n, "href"))
+	if href == "" {
+		return
+	}
+
+	switch {
+	case hasRel(n, "canonical"):
+		if data.Meta.Canonical == "" {
+			data.Meta.Canonical = resolveHref(baseURL, href)
+		}
+	case hasRel(n, "amphtml"):
+		if data.Meta.AMPURL == "" {
+			data.Meta.AMPURL = resolveHref(baseURL, href)
+		}
+	}
+}
+
+// processImage records an <img> element with its alt text and size
+// attributes. Inline data: images are recorded by their media type.
+func (s *CrawlerService) processImage(n *html.Node, data *CrawlData, baseURL *url.URL) {
+	src := strings.TrimSpace(getAttr(n, "src"))
+	if src == "" {
+		return
+	}
+
+	image := models.Image{
+		Src:        dataURIMarker(src),
+		Width:      strings.TrimSpace(getAttr(n, "width")),
+		Height:     strings.TrimSpace(getAttr(n, "height")),
+		MissingAlt: true,
+	}
+	if !strings.HasPrefix(src, "data:") {
+		image.Src = resolveHref(baseURL, src)
+	}
+	if len(image.Src) > maxImageSrcLength {
+		image.Src = image.Src[:maxImageSrcLength]
+	}
+	if alt, ok := attrValue(n, "alt"); ok {
+		image.Alt = strings.TrimSpace(alt)
+		image.MissingAlt = false
+	}
+
+	data.Images = append(data.Images, image)
+}
+
+// maxImageSrcLength is the size of the images' src column; longer sources are
+// cut to fit
+const maxImageSrcLength = 2048
+
+// dataURIMarker replaces an inline data: image with its media type, such as
+// data:image/png, as the encoded image itself can be megabytes long
+func dataURIMarker(src string) string {
+	if !strings.HasPrefix(src, "data:") {
+		return src
+	}
+	mediaType, _, _ := strings.Cut(strings.TrimPrefix(src, "data:"), ",")
+	mediaType, _, _ = strings.Cut(mediaType, ";")
+	return "data:" + strings.ToLower(strings.TrimSpace(mediaType))
+}
+
+// loginKeywords are words in a form's action, id, name or class that suggest authentication
+var loginKeywords = []string{"login", "log-in", "log_in", "signin", "sign-in", "sign_in", "logon", "auth", "session"}
+
+// loginSubmitTexts are submit button labels typically used by login forms
+var loginSubmitTexts = []string{"log in", "login", "sign in", "signin", "log on", "logon"}
+
+// loginFormThreshold is the score at which a form is treated as a login form.
+// A password input alone reaches it; otherwise a login keyword on the form
+// and a login submit label are both needed. New password inputs, as on
+// signup and password change forms, only count as one.
+const loginFormThreshold = 2
+
+// loginFormCertainScore is the score at which the detection is fully confident
+const loginFormCertainScore = 4
+
+// loginFormConfidence maps a login form score to a confidence between 0 and 1
+func loginFormConfidence(score int) float64 {
+	return math.Round(min(float64(score)/loginFormCertainScore, 1)*100) / 100
+}
+
+// checkLoginForm scores the form and records it as a login form if it reaches the threshold
 func (s *CrawlerService) checkLoginForm(n *html.Node, data *CrawlData) {
-	// Look for common login form indicators
-	loginIndicators := []string{"login", "signin", "email", "username", "password"}
-	
-	// Check form attributes and content
-	formHTML := s.nodeToString(n)
-	lowerHTML := strings.ToLower(formHTML)
-	
-	for _, indicator := range loginIndicators {
-		if strings.Contains(lowerHTML, indicator) {
-			data.HasLoginForm = true
+	score, evidence := s.scoreLoginForm(n)
+	if score <= data.LoginFormScore {
+		return
+	}
+
+	// Keep the evidence of the most convincing form on the page
+	data.LoginFormScore = score
+	data.LoginFormEvidence = evidence
+	data.HasLoginForm = score >= loginFormThreshold
+}
+
+// scoreLoginForm returns how strongly a form looks like a login form and why
+func (s *CrawlerService) scoreLoginForm(form *html.Node) (int, []string) {
+	score := 0
+	var evidence []string
+
+	for _, key := range []string{"action", "id", "name", "class"} {
+		if keyword := containsAny(strings.ToLower(getAttr(form, key)), loginKeywords); keyword != "" {
+			score++
+			evidence = append(evidence, fmt.Sprintf("form %s contains %q", key, keyword))
+			break
+		}
+	}
+
+	var passwords int
+	var currentPassword, newPassword, username bool
+	var submitLabel string
+	walkNodes(form, func(n *html.Node) {
+		if n.Type != html.ElementNode {
 			return
 		}
+
+		switch n.Data {
+		case "input":
+			inputType := strings.ToLower(getAttr(n, "type"))
+			autocomplete := strings.Fields(strings.ToLower(getAttr(n, "autocomplete")))
+			switch inputType {
+			case "password":
+				passwords++
+				currentPassword = currentPassword || slices.Contains(autocomplete, "current-password")
+				newPassword = newPassword || slices.Contains(autocomplete, "new-password")
+			case "submit", "button", "image":
+				label := getAttr(n, "value")
+				if inputType == "image" {
+					label = getAttr(n, "alt")
+				}
+				if submitLabel == "" && containsAny(strings.ToLower(label), loginSubmitTexts) != "" {
+					submitLabel = strings.TrimSpace(label)
+				}
+			default:
+				username = username || slices.Contains(autocomplete, "username")
+			}
+		case "button":
+			buttonType := strings.ToLower(getAttr(n, "type"))
+			label := textContent(n)
+			if buttonType != "reset" && submitLabel == "" && containsAny(strings.ToLower(label), loginSubmitTexts) != "" {
+				submitLabel = label
+			}
+		}
+	})
+
+	switch {
+	case currentPassword:
+		score += 3
+		evidence = append(evidence, `autocomplete "current-password"`)
+	case newPassword || passwords > 1:
+		// Signup and password change forms ask for a new password, often twice
+		score++
+		evidence = append(evidence, "new password input")
+	case passwords == 1:
+		score += 2
+		evidence = append(evidence, "password input")
 	}
+	if username {
+		score++
+		evidence = append(evidence, `autocomplete "username"`)
+	}
+	if submitLabel != "" {
+		score++
+		evidence = append(evidence, fmt.Sprintf("submit text %q", submitLabel))
+	}
+
+	return score, evidence
+}
+
+// containsAny returns the first keyword contained in s, or ""
+func containsAny(s string, keywords []string) string {
+	for _, keyword := range keywords {
+		if strings.Contains(s, keyword) {
+			return keyword
+		}
+	}
+	return ""
+}
+
+// walkNodes calls fn for n and all of its descendants
+func walkNodes(n *html.Node, fn func(*html.Node)) {
+	fn(n)
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		walkNodes(c, fn)
+	}
+}
+
+// textContent returns the whitespace-normalised text inside a node
+func textContent(n *html.Node) string {
+	var buf strings.Builder
+	walkNodes(n, func(c *html.Node) {
+		if c.Type == html.TextNode {
+			buf.WriteString(c.Data)
+			buf.WriteString(" ")
+		}
+	})
+	return strings.Join(strings.Fields(buf.String()), " ")
 }
 
-// detectHTMLVersion detects HTML version from doctype or html tag
+// doctypePublicID matches the language and version in a doctype public identifier,
+// e.g. "-//W3C//DTD XHTML 1.0 Transitional//EN"
+var doctypePublicID = regexp.MustCompile(`(?i)//DTD\s+(X?HTML)\s+([^/]*?)\s*//`)
+
+// detectHTMLVersion detects HTML version from the doctype token
 func (s *CrawlerService) detectHTMLVersion(n *html.Node, data *CrawlData) {
-	// Simple HTML5 detection (most modern websites)
-	data.HTMLVersion = "HTML5"
+	data.HTMLVersion = htmlVersionFromDoctype(n)
 }
 
-// checkLinkAccessibility checks if links are accessible
-func (s *CrawlerService) checkLinkAccessibility(data *CrawlData) {
-	client := &http.Client{
-		Timeout: 10 * time.Second,
+// htmlVersionFromDoctype maps a doctype node to a human readable HTML version
+func htmlVersionFromDoctype(n *html.Node) string {
+	var publicID, systemID string
+	for _, attr := range n.Attr {
+		switch attr.Key {
+		case "public":
+			publicID = strings.TrimSpace(attr.Val)
+		case "system":
+			systemID = strings.TrimSpace(attr.Val)
+		}
+	}
+
+	if !strings.EqualFold(n.Data, "html") {
+		return "Unknown"
+	}
+
+	// <!DOCTYPE html> and the legacy-compat form both mean HTML5
+	if publicID == "" {
+		if systemID == "" || strings.EqualFold(systemID, "about:legacy-compat") {
+			return "HTML5"
+		}
+		return "Unknown"
+	}
+
+	match := doctypePublicID.FindStringSubmatch(publicID)
+	if match == nil {
+		return "Unknown"
 	}
 
+	language := strings.ToUpper(match[1])
+	version := strings.Join(strings.Fields(match[2]), " ")
+
+	// HTML 4.0 and 4.01 without a variant name are the Strict DTDs
+	if language == "HTML" && (version == "4.0" || version == "4.01") {
+		version += " Strict"
+	}
+
+	return strings.TrimSpace(language + " " + version)
+}
+
+// checkLinkAccessibility checks if links are accessible. External links, and
+// internal ones if checkInternal is set, are checked in parallel, limited per
+// host by the throttle. Otherwise internal links are assumed accessible.
+// Slow checks are recorded in the crawl's events.
+func (s *CrawlerService) checkLinkAccessibility(data *CrawlData, throttle *HostThrottle, checkInternal bool, events *crawlEventLog) {
+	var queued []*models.Link
 	for i := range data.Links {
 		link := &data.Links[i]
-		
-		// Skip checking internal links for now (to avoid self-crawling)
-		if link.LinkType == "internal" {
+
+		switch link.LinkType {
+		case "internal":
+			if checkInternal {
+				queued = append(queued, link)
+				continue
+			}
+			// Skip checking internal links unless asked to (to avoid self-crawling)
 			link.StatusCode = 200
-			continue
+		case "external":
+			queued = append(queued, link)
 		}
+	}
+	events.add(models.CrawlEventLinksQueued, "%d links queued for check", len(queued))
 
-		// Make HEAD request to check accessibility
-		resp, err := client.Head(link.LinkURL)
-		if err != nil {
-			link.StatusCode = 0
-			link.IsAccessible = false
+	jobs := make(chan *models.Link)
+	var wg sync.WaitGroup
+
+	workers := max(1, s.options.MaxConcurrency)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for link := range jobs {
+				if elapsed := s.checkLink(linkCheckClient, link, throttle); elapsed >= slowLinkThreshold {
+					events.add(models.CrawlEventSlowLink, "%s took %s to answer with status %d",
+						link.LinkURL, elapsed.Round(time.Millisecond), link.StatusCode)
+				}
+			}
+		}()
+	}
+
+	for _, link := range queued {
+		jobs <- link
+	}
+	close(jobs)
+	wg.Wait()
+
+	for _, link := range data.Links {
+		if !link.IsAccessible {
 			data.BrokenLinks++
-			continue
 		}
+	}
+}
 
-		link.StatusCode = resp.StatusCode
-		if resp.StatusCode >= 400 {
-			link.IsAccessible = false
-			data.BrokenLinks++
+// checkLink makes a HEAD request to check a single link's accessibility and
+// returns how long the request took, 0 if a cached result was used. Checks of
+// external links are shared across crawls; internal links always reflect the
+// current state of the crawled site.
+func (s *CrawlerService) checkLink(client *http.Client, link *models.Link, throttle *HostThrottle) time.Duration {
+	ctx := s.traceContext()
+	linkChecks := s.linkChecks
+	if link.LinkType != "external" {
+		linkChecks = nil
+	}
+
+	status, ok := linkChecks.Get(ctx, link.LinkURL)
+	var elapsed time.Duration
+	if !ok {
+		status, elapsed = timedHeadStatus(ctx, client, link.LinkURL, throttle)
+		linkChecks.Set(ctx, link.LinkURL, status)
+	}
+	link.StatusCode = status
+	if link.StatusCode == 0 || link.StatusCode >= 400 {
+		link.IsAccessible = false
+	}
+	return elapsed
+}
+
+// checkImageAvailability requests every distinct image once and flags broken ones
+func (s *CrawlerService) checkImageAvailability(data *CrawlData, throttle *HostThrottle) {
+	// Inline data: images have nothing to fetch, and sources cut to fit
+	// their column would be reported broken
+	var unique []string
+	seen := make(map[string]bool)
+	for _, image := range data.Images {
+		if !strings.HasPrefix(image.Src, "data:") && len(image.Src) < maxImageSrcLength && !seen[image.Src] {
+			seen[image.Src] = true
+			unique = append(unique, image.Src)
+		}
+	}
+
+	statuses := make(map[string]int, len(unique))
+	srcs := make(chan string)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	workers := max(1, s.options.MaxConcurrency)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for src := range srcs {
+				status := headStatus(s.traceContext(), linkCheckClient, src, throttle)
+				mu.Lock()
+				statuses[src] = status
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, src := range unique {
+		srcs <- src
+	}
+	close(srcs)
+	wg.Wait()
+
+	for i := range data.Images {
+		image := &data.Images[i]
+		if status, checked := statuses[image.Src]; checked {
+			image.StatusCode = status
+			image.IsBroken = status == 0 || status >= 400
 		}
 	}
 }
 
-// nodeToString converts HTML node to string (simplified)
-func (s *CrawlerService) nodeToString(n *html.Node) string {
-	var buf strings.Builder
-	s.renderNode(&buf, n)
-	return buf.String()
+// headStatus makes a HEAD request through the throttle and returns the status code, or 0 if it failed
+func headStatus(ctx context.Context, client *http.Client, target string, throttle *HostThrottle) int {
+	status, _ := timedHeadStatus(ctx, client, target, throttle)
+	return status
 }
 
-func (s *CrawlerService) renderNode(buf *strings.Builder, n *html.Node) {
-	switch n.Type {
-	case html.ElementNode:
-		buf.WriteString("<" + n.Data)
-		for _, attr := range n.Attr {
-			buf.WriteString(fmt.Sprintf(` %s="%s"`, attr.Key, attr.Val))
+// timedHeadStatus is headStatus that also returns how long the request took,
+// without the time spent waiting for the throttle
+func timedHeadStatus(ctx context.Context, client *http.Client, target string, throttle *HostThrottle) (int, time.Duration) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, target, nil)
+	if err != nil {
+		return 0, 0
+	}
+
+	host := hostOf(target)
+	throttle.Acquire(host)
+	start := time.Now()
+
+	resp, err := client.Do(req)
+	if err != nil {
+		elapsed := time.Since(start)
+		throttle.Release(host, elapsed, 0)
+		return 0, elapsed
+	}
+	resp.Body.Close()
+	elapsed := time.Since(start)
+	throttle.Release(host, elapsed, resp.StatusCode)
+
+	return resp.StatusCode, elapsed
+}
+
+// getAttr returns the value of an attribute, or "" if it isn't set
+func getAttr(n *html.Node, key string) string {
+	for _, attr := range n.Attr {
+		if attr.Key == key {
+			return attr.Val
 		}
-		buf.WriteString(">")
-		
-		for c := n.FirstChild; c != nil; c = c.NextSibling {
-			s.renderNode(buf, c)
+	}
+	return ""
+}
+
+// hasRel reports whether the node's rel attribute contains the given value
+func hasRel(n *html.Node, value string) bool {
+	for _, rel := range strings.Fields(strings.ToLower(getAttr(n, "rel"))) {
+		if rel == value {
+			return true
 		}
-		
-		buf.WriteString("</" + n.Data + ">")
-	case html.TextNode:
-		buf.WriteString(n.Data)
 	}
+	return false
+}
+
+// resolveHref resolves an href against the page URL, returning it unchanged if it can't be parsed
+func resolveHref(baseURL *url.URL, href string) string {
+	ref, err := url.Parse(href)
+	if err != nil || baseURL == nil {
+		return href
+	}
+	return baseURL.ResolveReference(ref).String()
+}
+
+// setIfMissing stores a value in the map unless the key is already present
+func setIfMissing(m *map[string]string, key, value string) {
+	if *m == nil {
+		*m = map[string]string{}
+	}
+	if _, ok := (*m)[key]; !ok {
+		(*m)[key] = value
+	}
+}
+
+// hostOf returns the host part of a URL, or the raw string if it can't be parsed
+func hostOf(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Host == "" {
+		return rawURL
+	}
+	return parsed.Host
+}
+
+// WithCache returns a copy of the service that caches crawl statuses; a nil
+// cache disables caching
+func (s *CrawlerService) WithCache(cache *CacheService) *CrawlerService {
+	copied := *s
+	copied.cache = cache
+	return &copied
+}
+
+// WithLinkCheckCache returns a copy of the service that reuses external link
+// checks from recent crawls; a nil cache checks every link
+func (s *CrawlerService) WithLinkCheckCache(cache *LinkCheckCache) *CrawlerService {
+	copied := *s
+	copied.linkChecks = cache
+	return &copied
+}
+
+// WithFeatureFlags returns a copy of the service that consults feature flags
+// for the owner of each crawled URL; nil uses the defaults
+func (s *CrawlerService) WithFeatureFlags(flags *FeatureFlagService) *CrawlerService {
+	copied := *s
+	copied.flags = flags
+	return &copied
 }
 
 // GetCrawlStatus returns the status of a crawl
 func (s *CrawlerService) GetCrawlStatus(urlID uint) (*models.CrawlStatusResponse, error) {
+	ctx := s.db.Statement.Context
+	var cached models.CrawlStatusResponse
+	cacheKey, ok := s.cache.get(ctx, fmt.Sprintf("crawl_status:%d", urlID), &cached)
+	if ok {
+		return &cached, nil
+	}
+
+	status, err := s.getCrawlStatus(urlID)
+	if err != nil {
+		return nil, err
+	}
+	s.cache.set(ctx, cacheKey, status)
+	return status, nil
+}
+
+func (s *CrawlerService) getCrawlStatus(urlID uint) (*models.CrawlStatusResponse, error) {
 	var url models.URL
 	if err := s.db.Preload("Crawls", func(db *gorm.DB) *gorm.DB {
 		return db.Order("created_at DESC").Limit(1)
 	}).First(&url, urlID).Error; err != nil {
-		return nil, fmt.Errorf("URL not found")
+		return nil, ErrURLNotFound
 	}
 
 	if len(url.Crawls) == 0 {
@@ -335,32 +1288,104 @@ func (s *CrawlerService) GetCrawlStatus(urlID uint) (*models.CrawlStatusResponse
 		}, nil
 	}
 
-	crawl := url.Crawls[0]
-	
+	// An unchanged crawl reports the results of the crawl it confirmed
+	latest := url.Crawls[0]
+	crawl := latest
+	if latest.Status == "unchanged" {
+		var confirmed models.Crawl
+		if err := s.db.Where("url_id = ? AND status = ?", urlID, "completed").
+			Order("created_at DESC, id DESC").Limit(1).Find(&confirmed).Error; err != nil {
+			return nil, fmt.Errorf("failed to fetch the last completed crawl: %w", err)
+		}
+		crawl = confirmed
+	}
+
 	// Parse heading counts
 	var headingCounts models.HeadingCounts
 	if crawl.HeadingCounts != "" {
 		json.Unmarshal([]byte(crawl.HeadingCounts), &headingCounts)
 	}
 
+	var loginEvidence []string
+	if crawl.LoginFormEvidence != "" {
+		json.Unmarshal([]byte(crawl.LoginFormEvidence), &loginEvidence)
+	}
+
+	var extracted struct {
+		Fields map[string]interface{} `json:"fields"`
+	}
+	if crawl.ExtractedData != "" {
+		json.Unmarshal([]byte(crawl.ExtractedData), &extracted)
+	}
+
+	var keywords []models.KeywordCheck
+	if crawl.KeywordChecks != "" {
+		json.Unmarshal([]byte(crawl.KeywordChecks), &keywords)
+	}
+
+	trend, err := s.performanceTrend(urlID)
+	if err != nil {
+		return nil, err
+	}
+
 	return &models.CrawlStatusResponse{
-		ID:            crawl.ID,
+		ID:            latest.ID,
 		URL:           url.URL,
-		Status:        crawl.Status,
+		Status:        latest.Status,
 		InternalLinks: crawl.InternalLinks,
 		ExternalLinks: crawl.ExternalLinks,
 		BrokenLinks:   crawl.BrokenLinks,
 		HeadingCounts: &headingCounts,
-		StartedAt:     crawl.StartedAt,
-		CompletedAt:   crawl.CompletedAt,
-		ErrorMessage:  crawl.ErrorMessage,
+		LoginForm: &models.LoginFormResult{
+			Detected: crawl.LoginFormDetected,
+			Evidence: loginEvidence,
+			Confidence: crawl.LoginFormConfidence,
+			Override: url.LoginFormOverride,
+			Effective: url.HasLoginForm,
+		},
+		Performance:      &latest.ResponseMetrics,
+		PerformanceTrend: trend,
+		Fields:           extracted.Fields,
+		Keywords:         keywords,
+		WordCount:        crawl.WordCount,
+		ReadabilityScore: crawl.ReadabilityScore,
+		StartedAt:     latest.StartedAt,
+		CompletedAt:   latest.CompletedAt,
+		ErrorMessage:  latest.ErrorMessage,
 	}, nil
 }
 
-// BulkRerunCrawls restarts crawling for multiple URLs
+// performanceTrend returns the root page performance of the URL's latest
+// completed crawls, oldest first
+func (s *CrawlerService) performanceTrend(urlID uint) ([]models.PerformanceSample, error) {
+	var crawls []models.Crawl
+	if err := s.db.Where("url_id = ? AND status = ?", urlID, "completed").
+		Order("created_at DESC").Limit(performanceTrendLength).Find(&crawls).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch performance trend: %w", err)
+	}
+
+	trend := make([]models.PerformanceSample, len(crawls))
+	for i, crawl := range crawls {
+		trend[len(crawls)-1-i] = models.PerformanceSample{
+			CrawlID:         crawl.ID,
+			CreatedAt:       crawl.CreatedAt,
+			ResponseMetrics: crawl.ResponseMetrics,
+		}
+	}
+	return trend, nil
+}
+
+// BulkRerunCrawls restarts crawling for multiple URLs with low priority
 func (s *CrawlerService) BulkRerunCrawls(urlIDs []uint) error {
+	return s.BulkRerunCrawlsWithPriority(urlIDs, models.CrawlPriorityLow)
+}
+
+// BulkRerunCrawlsWithPriority restarts crawling for multiple URLs. The crawls
+// are linked to the trace of the service's context but not cancelled with it.
+func (s *CrawlerService) BulkRerunCrawlsWithPriority(urlIDs []uint, priority models.CrawlPriority) error {
+	ctx := s.traceContext()
 	for _, urlID := range urlIDs {
-		go s.StartCrawl(urlID)
+		go s.StartCrawlWithPriority(ctx, urlID, priority)
 	}
 	return nil
 } 
\ No newline at end of file
@@ -1,8 +1,10 @@
 package services
 
 import (
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
 	"time"
@@ -24,7 +26,7 @@ func setupCrawlerTestDB(t *testing.T) *gorm.DB {
 	require.NoError(t, err)
 
 	// Auto migrate all models
-	err = db.AutoMigrate(&models.URL{}, &models.Crawl{}, &models.Link{}, &models.User{})
+	err = db.AutoMigrate(&models.URL{}, &models.Crawl{}, &models.Link{}, &models.PageMeta{}, &models.Page{}, &models.PageLink{}, &models.Image{}, &models.Form{}, &models.CrawlEvent{}, &models.AccessibilityIssue{}, &models.MixedContentIssue{}, &models.ActivityEvent{}, &models.ExtractionRule{}, &models.User{})
 	require.NoError(t, err)
 
 	return db
@@ -89,6 +91,8 @@ func TestCrawlerService_StartCrawl(t *testing.T) {
 		assert.Equal(t, "completed", crawl.Status)
 		assert.NotNil(t, crawl.StartedAt)
 		assert.NotNil(t, crawl.CompletedAt)
+		assert.Equal(t, int64(len(testHTML)), crawl.ResponseBytes)
+		assert.Equal(t, "HTTP/1.1", crawl.Protocol)
 	})
 
 	t.Run("crawl non-existent URL", func(t *testing.T) {
@@ -216,6 +220,108 @@ func TestCrawlerService_extractData(t *testing.T) {
 		assert.Empty(t, data.Title)
 		assert.Equal(t, 1, data.HeadingCounts.H1)
 	})
+
+	t.Run("link attributes and context", func(t *testing.T) {
+		db := setupCrawlerTestDB(t)
+		service := NewCrawlerService(db)
+
+		htmlContent := `<html><body>
+			<nav><ul><li><a href="/about">About</a></li></ul></nav>
+			<div role="navigation"><a href="/blog">Blog</a></div>
+			<p><a href="https://partner.com" rel="Sponsored  NoFollow" target="_blank">Partner</a></p>
+			<p><a href="https://forum.example.org" rel="ugc">Comment</a></p>
+			<footer><a href="/privacy">Privacy</a></footer>
+		</body></html>`
+
+		doc, err := html.Parse(strings.NewReader(htmlContent))
+		require.NoError(t, err)
+		base, err := url.Parse("https://example.com")
+		require.NoError(t, err)
+
+		data := &CrawlData{}
+		service.traverseHTML(doc, data, base)
+		require.Len(t, data.Links, 5)
+
+		assert.Equal(t, models.LinkContextNav, data.Links[0].Context)
+		assert.Equal(t, models.LinkContextNav, data.Links[1].Context)
+
+		partner := data.Links[2]
+		assert.Equal(t, models.LinkContextContent, partner.Context)
+		assert.Equal(t, "sponsored nofollow", partner.Rel)
+		assert.Equal(t, "_blank", partner.Target)
+		assert.True(t, partner.Nofollow)
+		assert.True(t, partner.Sponsored)
+		assert.False(t, partner.UGC)
+
+		assert.True(t, data.Links[3].UGC)
+		assert.False(t, data.Links[3].Nofollow)
+		assert.Equal(t, models.LinkContextFooter, data.Links[4].Context)
+	})
+
+	t.Run("repeated links are stored and checked once", func(t *testing.T) {
+		var heads int
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodHead && r.URL.Path == "/status" {
+				heads++
+			}
+		}))
+		defer server.Close()
+
+		db := setupCrawlerTestDB(t)
+		service := NewCrawlerService(db)
+
+		htmlContent := `<html><body>
+			<nav><a href="/pricing"><img src="data:image/gif;base64,R0lGODlhAQABAAAAACw=" alt=""></a><a href="` + server.URL + `/status">Status</a></nav>
+			<p><a href="/pricing">Pricing</a></p>
+			<footer><a href="/pricing">Pricing</a><a href="` + server.URL + `/status">Status</a></footer>
+		</body></html>`
+
+		doc, err := html.Parse(strings.NewReader(htmlContent))
+		require.NoError(t, err)
+
+		data := service.extractData(doc, "https://example.com")
+		require.Len(t, data.Links, 2)
+		assert.Equal(t, 3, data.Links[0].Occurrences)
+		assert.Equal(t, "Pricing", data.Links[0].LinkText, "the text of a later occurrence fills in a missing one")
+		assert.Equal(t, models.LinkContextNav, data.Links[0].Context)
+		assert.Equal(t, 2, data.Links[1].Occurrences)
+		assert.Equal(t, 3, data.InternalLinks)
+		assert.Equal(t, 2, data.ExternalLinks)
+		assert.Equal(t, 1, heads)
+	})
+
+	t.Run("anchor and non-HTTP links are reported but not checked", func(t *testing.T) {
+		db := setupCrawlerTestDB(t)
+		service := NewCrawlerService(db)
+
+		htmlContent := `<html><body>
+			<a href="#pricing">Pricing</a>
+			<a href="mailto:sales@example.com">Email</a>
+			<a href="TEL:+48123456789">Call</a>
+			<a href="javascript:void(0)">Menu</a>
+			<a href="ftp://files.example.com/report.pdf">Report</a>
+			<a href="/about#team">Team</a>
+		</body></html>`
+
+		doc, err := html.Parse(strings.NewReader(htmlContent))
+		require.NoError(t, err)
+
+		data := service.extractData(doc, "https://example.com/")
+		var types []string
+		for _, link := range data.Links {
+			types = append(types, link.LinkType)
+			if link.LinkType != "internal" {
+				assert.Zero(t, link.StatusCode, link.LinkURL)
+				assert.True(t, link.IsAccessible, link.LinkURL)
+			}
+		}
+		assert.Equal(t, []string{"anchor", "mailto", "tel", "other", "other", "internal"}, types)
+		assert.Equal(t, "https://example.com/#pricing", data.Links[0].LinkURL)
+		assert.Equal(t, "mailto:sales@example.com", data.Links[1].LinkURL)
+		assert.Equal(t, 1, data.InternalLinks)
+		assert.Equal(t, 0, data.ExternalLinks)
+		assert.Equal(t, 0, data.BrokenLinks)
+	})
 }
 
 func TestCrawlerService_GetCrawlStatus(t *testing.T) {
@@ -264,7 +370,7 @@ func TestCrawlerService_GetCrawlStatus(t *testing.T) {
 		status, err := service.GetCrawlStatus(999)
 		assert.Error(t, err)
 		assert.Nil(t, status)
-		assert.Contains(t, err.Error(), "URL not found")
+		assert.ErrorIs(t, err, ErrURLNotFound)
 	})
 
 	t.Run("no crawl data", func(t *testing.T) {
@@ -283,6 +389,34 @@ func TestCrawlerService_GetCrawlStatus(t *testing.T) {
 		assert.Equal(t, "pending", status.Status)
 		assert.Equal(t, 0, status.InternalLinks)
 	})
+
+	t.Run("performance trend", func(t *testing.T) {
+		db := setupCrawlerTestDB(t)
+		service := NewCrawlerService(db)
+
+		urlRecord := &models.URL{URL: "https://example.com", Status: "completed"}
+		require.NoError(t, db.Create(urlRecord).Error)
+
+		base := time.Now().Add(-time.Hour)
+		for i, ttfb := range []int64{120, 80, 95} {
+			require.NoError(t, db.Create(&models.Crawl{
+				URLID:           urlRecord.ID,
+				Status:          "completed",
+				CreatedAt:       base.Add(time.Duration(i) * time.Minute),
+				ResponseMetrics: models.ResponseMetrics{TTFBMs: ttfb, ResponseBytes: 2048, Protocol: "HTTP/2.0"},
+			}).Error)
+		}
+		require.NoError(t, db.Create(&models.Crawl{URLID: urlRecord.ID, Status: "error", CreatedAt: base.Add(-time.Minute)}).Error)
+
+		status, err := service.GetCrawlStatus(urlRecord.ID)
+		require.NoError(t, err)
+		require.NotNil(t, status.Performance)
+		assert.Equal(t, int64(95), status.Performance.TTFBMs)
+		require.Len(t, status.PerformanceTrend, 3)
+		assert.Equal(t, int64(120), status.PerformanceTrend[0].TTFBMs)
+		assert.Equal(t, int64(95), status.PerformanceTrend[2].TTFBMs)
+		assert.Equal(t, "HTTP/2.0", status.PerformanceTrend[2].Protocol)
+	})
 }
 
 func TestCrawlerService_BulkRerunCrawls(t *testing.T) {
@@ -290,6 +424,12 @@ func TestCrawlerService_BulkRerunCrawls(t *testing.T) {
 		db := setupCrawlerTestDB(t)
 		service := NewCrawlerService(db)
 
+		// The crawls run in goroutines; every connection to :memory: is a
+		// database of its own
+		sqlDB, err := db.DB()
+		require.NoError(t, err)
+		sqlDB.SetMaxOpenConns(1)
+
 		// Create test URLs
 		url1 := &models.URL{URL: "https://example1.com", Status: "completed"}
 		url2 := &models.URL{URL: "https://example2.com", Status: "completed"}
@@ -297,7 +437,7 @@ func TestCrawlerService_BulkRerunCrawls(t *testing.T) {
 		require.NoError(t, db.Create(url2).Error)
 
 		// Run bulk rerun
-		err := service.BulkRerunCrawls([]uint{url1.ID, url2.ID})
+		err = service.BulkRerunCrawls([]uint{url1.ID, url2.ID})
 		require.NoError(t, err)
 
 		// Wait for async operations to complete
@@ -343,14 +483,77 @@ func TestCrawlerService_checkLoginForm(t *testing.T) {
 		assert.True(t, data.HasLoginForm)
 	})
 
-	t.Run("detects login button", func(t *testing.T) {
+	t.Run("detects login action with login button", func(t *testing.T) {
+		htmlContent := `<form action="/users/sign-in"><input type="text" name="user"><button type="submit">Sign in</button></form>`
+		doc, _ := html.Parse(strings.NewReader(htmlContent))
+
+		data := &CrawlData{}
+		service.traverseHTML(doc, data, nil)
+
+		assert.True(t, data.HasLoginForm)
+		assert.Equal(t, []string{`form action contains "sign-in"`, `submit text "Sign in"`}, data.LoginFormEvidence)
+	})
+
+	t.Run("login button alone is not enough", func(t *testing.T) {
 		htmlContent := `<form><input type="submit" value="Login"></form>`
 		doc, _ := html.Parse(strings.NewReader(htmlContent))
-		
+
 		data := &CrawlData{}
 		service.traverseHTML(doc, data, nil)
-		
+
+		assert.False(t, data.HasLoginForm)
+		assert.Equal(t, []string{`submit text "Login"`}, data.LoginFormEvidence)
+	})
+
+	t.Run("ignores newsletter signup", func(t *testing.T) {
+		htmlContent := `<form action="/newsletter" class="subscribe"><input type="email" name="email" placeholder="Your email"><button>Subscribe</button></form>`
+		doc, _ := html.Parse(strings.NewReader(htmlContent))
+
+		data := &CrawlData{}
+		service.traverseHTML(doc, data, nil)
+
+		assert.False(t, data.HasLoginForm)
+		assert.Empty(t, data.LoginFormEvidence)
+	})
+
+	t.Run("keeps evidence of the strongest form", func(t *testing.T) {
+		htmlContent := `<form action="/subscribe"><input type="email" name="email"></form>
+			<form id="login-form"><input type="password" name="pass"><input type="submit" value="Log in"></form>`
+		doc, _ := html.Parse(strings.NewReader(htmlContent))
+
+		data := &CrawlData{}
+		service.traverseHTML(doc, data, nil)
+
+		assert.True(t, data.HasLoginForm)
+		assert.Equal(t, 4, data.LoginFormScore)
+		assert.Contains(t, data.LoginFormEvidence, "password input")
+	})
+
+	t.Run("signup forms ask for a new password", func(t *testing.T) {
+		htmlContent := `<form action="/register"><input type="email" name="email" autocomplete="email">
+			<input type="password" name="password" autocomplete="new-password"><input type="password" name="confirm">
+			<button>Create account</button></form>`
+		doc, _ := html.Parse(strings.NewReader(htmlContent))
+
+		data := &CrawlData{}
+		service.traverseHTML(doc, data, nil)
+
+		assert.False(t, data.HasLoginForm)
+		assert.Equal(t, []string{"new password input"}, data.LoginFormEvidence)
+	})
+
+	t.Run("autocomplete hints raise the confidence", func(t *testing.T) {
+		htmlContent := `<form><input name="user" autocomplete="username"><input type="password" name="pass" autocomplete="section-main current-password"></form>`
+		doc, _ := html.Parse(strings.NewReader(htmlContent))
+
+		data := &CrawlData{}
+		service.traverseHTML(doc, data, nil)
+
 		assert.True(t, data.HasLoginForm)
+		assert.Equal(t, []string{`autocomplete "current-password"`, `autocomplete "username"`}, data.LoginFormEvidence)
+		assert.Equal(t, 1.0, loginFormConfidence(data.LoginFormScore))
+		assert.Equal(t, 0.5, loginFormConfidence(loginFormThreshold))
+		assert.Zero(t, loginFormConfidence(0))
 	})
 
 	t.Run("no login form", func(t *testing.T) {
@@ -362,4 +565,565 @@ func TestCrawlerService_checkLoginForm(t *testing.T) {
 		
 		assert.False(t, data.HasLoginForm)
 	})
-} 
\ No newline at end of file
+} 
+func TestCrawlerService_detectHTMLVersion(t *testing.T) {
+	db := setupCrawlerTestDB(t)
+	service := NewCrawlerService(db)
+
+	tests := []struct {
+		name     string
+		doctype  string
+		expected string
+	}{
+		{"HTML5", `<!DOCTYPE html>`, "HTML5"},
+		{"HTML5 legacy compat", `<!DOCTYPE html SYSTEM "about:legacy-compat">`, "HTML5"},
+		{"HTML 4.01 Strict", `<!DOCTYPE HTML PUBLIC "-//W3C//DTD HTML 4.01//EN" "http://www.w3.org/TR/html4/strict.dtd">`, "HTML 4.01 Strict"},
+		{"HTML 4.01 Transitional", `<!DOCTYPE HTML PUBLIC "-//W3C//DTD HTML 4.01 Transitional//EN" "http://www.w3.org/TR/html4/loose.dtd">`, "HTML 4.01 Transitional"},
+		{"HTML 4.01 Frameset", `<!DOCTYPE HTML PUBLIC "-//W3C//DTD HTML 4.01 Frameset//EN" "http://www.w3.org/TR/html4/frameset.dtd">`, "HTML 4.01 Frameset"},
+		{"XHTML 1.0 Strict", `<!DOCTYPE html PUBLIC "-//W3C//DTD XHTML 1.0 Strict//EN" "http://www.w3.org/TR/xhtml1/DTD/xhtml1-strict.dtd">`, "XHTML 1.0 Strict"},
+		{"XHTML 1.0 Transitional", `<!DOCTYPE html PUBLIC "-//W3C//DTD XHTML 1.0 Transitional//EN" "http://www.w3.org/TR/xhtml1/DTD/xhtml1-transitional.dtd">`, "XHTML 1.0 Transitional"},
+		{"XHTML 1.1", `<!DOCTYPE html PUBLIC "-//W3C//DTD XHTML 1.1//EN" "http://www.w3.org/TR/xhtml11/DTD/xhtml11.dtd">`, "XHTML 1.1"},
+		{"HTML 3.2", `<!DOCTYPE HTML PUBLIC "-//W3C//DTD HTML 3.2 Final//EN">`, "HTML 3.2 Final"},
+		{"no doctype", ``, "Unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			htmlContent := tt.doctype + `<html><head><title>Page</title></head><body></body></html>`
+			doc, err := html.Parse(strings.NewReader(htmlContent))
+			require.NoError(t, err)
+
+			data := service.extractData(doc, "https://example.com")
+			assert.Equal(t, tt.expected, data.HTMLVersion)
+		})
+	}
+
+	t.Run("stored on URL record after crawl", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte(`<!DOCTYPE html PUBLIC "-//W3C//DTD XHTML 1.0 Strict//EN" "http://www.w3.org/TR/xhtml1/DTD/xhtml1-strict.dtd"><html><head><title>Old</title></head></html>`))
+		}))
+		defer server.Close()
+
+		urlRecord := &models.URL{URL: server.URL, Status: "pending"}
+		require.NoError(t, db.Create(urlRecord).Error)
+
+		service.StartCrawl(urlRecord.ID)
+
+		var updated models.URL
+		require.NoError(t, db.First(&updated, urlRecord.ID).Error)
+		assert.Equal(t, "XHTML 1.0 Strict", updated.HTMLVersion)
+	})
+}
+
+func TestCrawlerService_extractMeta(t *testing.T) {
+	db := setupCrawlerTestDB(t)
+	service := NewCrawlerService(db)
+
+	htmlContent := `<!DOCTYPE html>
+	<html>
+	<head>
+		<title>Meta Page</title>
+		<meta name="description" content="A page about things">
+		<meta name="Robots" content="noindex, follow">
+		<link rel="canonical" href="/canonical-page">
+		<link rel="amphtml" href="/amp/page">
+		<meta name="viewport" content="width=device-width, initial-scale=1">
+		<meta property="og:title" content="OG Title">
+		<meta property="og:image" content="https://example.com/image.png">
+		<meta name="twitter:card" content="summary_large_image">
+		<meta property="twitter:site" content="@example">
+		<meta name="description" content="Second description is ignored">
+	</head>
+	<body></body>
+	</html>`
+
+	doc, err := html.Parse(strings.NewReader(htmlContent))
+	require.NoError(t, err)
+
+	data := service.extractData(doc, "https://example.com/page")
+
+	assert.Equal(t, "A page about things", data.Meta.Description)
+	assert.Equal(t, "noindex, follow", data.Meta.Robots)
+	assert.Equal(t, "https://example.com/canonical-page", data.Meta.Canonical)
+	assert.Equal(t, "https://example.com/amp/page", data.Meta.AMPURL)
+	assert.Equal(t, "width=device-width, initial-scale=1", data.Meta.Viewport)
+	assert.Equal(t, map[string]string{
+		"og:title": "OG Title",
+		"og:image": "https://example.com/image.png",
+	}, data.Meta.OpenGraph)
+	assert.Equal(t, map[string]string{
+		"twitter:card": "summary_large_image",
+		"twitter:site": "@example",
+	}, data.Meta.TwitterCard)
+
+	t.Run("stored with the crawl", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte(htmlContent))
+		}))
+		defer server.Close()
+
+		urlRecord := &models.URL{URL: server.URL, Status: "pending"}
+		require.NoError(t, db.Create(urlRecord).Error)
+
+		service.StartCrawl(urlRecord.ID)
+
+		var crawl models.Crawl
+		require.NoError(t, db.Preload("PageMeta").Where("url_id = ?", urlRecord.ID).First(&crawl).Error)
+		require.NotNil(t, crawl.PageMeta)
+		assert.Equal(t, "A page about things", crawl.PageMeta.Description)
+		assert.Equal(t, "OG Title", crawl.PageMeta.OpenGraph["og:title"])
+		assert.Equal(t, server.URL+"/canonical-page", crawl.PageMeta.Canonical)
+		assert.Equal(t, server.URL+"/amp/page", crawl.PageMeta.AMPURL)
+	})
+}
+
+func TestCrawlerService_DeepCrawl(t *testing.T) {
+	pages := map[string]string{
+		"/":         `<html><head><title>Home</title></head><body><h1>Home</h1><h2>Intro</h2><a href="/about">About</a><a href="/blog#top">Blog</a><a href="https://external.com">External</a></body></html>`,
+		"/about":    `<html><head><title>About</title></head><body><h2>No H1 here</h2><a href="/">Home</a><a href="/about/team">Team</a></body></html>`,
+		"/blog":     `<html><head><title>Blog</title></head><body><h1>Blog</h1><h1>Posts</h1><h3>Latest</h3></body></html>`,
+		"/about/team": `<html><head><title>Team</title></head><body><h1>Team</h1></body></html>`,
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := pages[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	t.Run("follows internal links up to max depth", func(t *testing.T) {
+		db := setupCrawlerTestDB(t)
+		service := NewCrawlerService(db)
+
+		urlRecord := &models.URL{URL: server.URL + "/", Status: "pending", MaxDepth: 1}
+		require.NoError(t, db.Create(urlRecord).Error)
+
+		service.StartCrawl(urlRecord.ID)
+
+		var crawl models.Crawl
+		require.NoError(t, db.Where("url_id = ?", urlRecord.ID).First(&crawl).Error)
+		assert.Equal(t, 3, crawl.PagesCrawled)
+
+		var stored []models.Page
+		require.NoError(t, db.Where("crawl_id = ?", crawl.ID).Order("id").Find(&stored).Error)
+		require.Len(t, stored, 3)
+		assert.Equal(t, 0, stored[0].Depth)
+		assert.Equal(t, server.URL+"/about", stored[1].PageURL)
+		assert.Equal(t, server.URL+"/blog", stored[2].PageURL)
+		assert.Equal(t, 1, stored[2].Depth)
+		assert.Equal(t, 3, stored[2].HeadingDepth)
+		assert.Len(t, stored[2].ContentHash, 64)
+		assert.NotEqual(t, stored[1].ContentHash, stored[2].ContentHash)
+
+		var edges []models.PageLink
+		require.NoError(t, db.Where("crawl_id = ?", crawl.ID).Order("id").Find(&edges).Error)
+		var pairs []string
+		for _, edge := range edges {
+			pairs = append(pairs, strings.TrimPrefix(edge.SourceURL, server.URL)+" -> "+strings.TrimPrefix(edge.TargetURL, server.URL))
+		}
+		assert.Equal(t, []string{"/ -> /about", "/ -> /blog", "/about -> /", "/about -> /about/team"}, pairs)
+	})
+
+	t.Run("respects the page limit", func(t *testing.T) {
+		db := setupCrawlerTestDB(t)
+		service := NewCrawlerService(db)
+
+		urlRecord := &models.URL{URL: server.URL + "/", Status: "pending", MaxDepth: 5, MaxPages: 2}
+		require.NoError(t, db.Create(urlRecord).Error)
+
+		service.StartCrawl(urlRecord.ID)
+
+		var count int64
+		db.Model(&models.Page{}).Where("url_id = ?", urlRecord.ID).Count(&count)
+		assert.Equal(t, int64(2), count)
+	})
+
+	t.Run("single page crawl stores the root page", func(t *testing.T) {
+		db := setupCrawlerTestDB(t)
+		service := NewCrawlerService(db)
+
+		urlRecord := &models.URL{URL: server.URL + "/", Status: "pending"}
+		require.NoError(t, db.Create(urlRecord).Error)
+
+		service.StartCrawl(urlRecord.ID)
+
+		var stored []models.Page
+		require.NoError(t, db.Where("url_id = ?", urlRecord.ID).Find(&stored).Error)
+		require.Len(t, stored, 1)
+		assert.Equal(t, "Home", stored[0].Title)
+		assert.Equal(t, 2, stored[0].HeadingDepth)
+
+		var edges int64
+		db.Model(&models.PageLink{}).Where("url_id = ?", urlRecord.ID).Count(&edges)
+		assert.Zero(t, edges)
+	})
+
+	t.Run("skips pages excluded by the crawl scope", func(t *testing.T) {
+		db := setupCrawlerTestDB(t)
+		service := NewCrawlerService(db)
+
+		urlRecord := &models.URL{URL: server.URL + "/", Status: "pending", MaxDepth: 2, ExcludePatterns: []string{"/about*"}}
+		require.NoError(t, db.Create(urlRecord).Error)
+
+		service.StartCrawl(urlRecord.ID)
+
+		var crawled []string
+		require.NoError(t, db.Model(&models.Page{}).Where("url_id = ?", urlRecord.ID).Order("id").Pluck("page_url", &crawled).Error)
+		assert.Equal(t, []string{server.URL + "/", server.URL + "/blog"}, crawled)
+	})
+
+	t.Run("records where broken links were found", func(t *testing.T) {
+		site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/":
+				w.Header().Set("Content-Type", "text/html")
+				w.Write([]byte(`<html><body><a href="/docs">Docs</a><a href="/gone">Gone</a></body></html>`))
+			case "/docs":
+				w.Header().Set("Content-Type", "text/html")
+				w.Write([]byte(`<html><body><a href="/gone">Gone</a><a href="/missing">Missing</a></body></html>`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		defer site.Close()
+
+		db := setupCrawlerTestDB(t)
+		service := NewCrawlerService(db)
+
+		urlRecord := &models.URL{URL: site.URL + "/", Status: "pending", MaxDepth: 2}
+		require.NoError(t, db.Create(urlRecord).Error)
+
+		service.StartCrawl(urlRecord.ID)
+
+		var crawl models.Crawl
+		require.NoError(t, db.Where("url_id = ?", urlRecord.ID).First(&crawl).Error)
+		assert.Equal(t, 3, crawl.BrokenLinks)
+		assert.Equal(t, 4, crawl.InternalLinks)
+
+		var links []models.Link
+		require.NoError(t, db.Where("crawl_id = ? AND is_accessible = ?", crawl.ID, false).Order("id").Find(&links).Error)
+		var found []string
+		for _, link := range links {
+			found = append(found, strings.TrimPrefix(link.LinkURL, site.URL)+" on "+strings.TrimPrefix(link.FoundOnURL, site.URL))
+			assert.Equal(t, http.StatusNotFound, link.StatusCode)
+		}
+		assert.Equal(t, []string{"/gone on /", "/gone on /docs", "/missing on /docs"}, found)
+	})
+}
+
+func TestCrawlerService_ConditionalFetch(t *testing.T) {
+	var conditional []string
+	version := `"v1"`
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		conditional = append(conditional, r.Header.Get("If-None-Match"))
+		if r.Header.Get("If-None-Match") == version {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", version)
+		w.Header().Set("Last-Modified", "Wed, 14 Oct 2026 08:00:00 GMT")
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head><title>Home</title></head><body><a href="/about">About</a></body></html>`))
+	}))
+	defer site.Close()
+
+	db := setupCrawlerTestDB(t)
+	service := NewCrawlerService(db)
+	urlRecord := &models.URL{URL: site.URL + "/", Status: "pending"}
+	require.NoError(t, db.Create(urlRecord).Error)
+
+	service.StartCrawl(urlRecord.ID)
+	var first models.Crawl
+	require.NoError(t, db.Where("url_id = ?", urlRecord.ID).First(&first).Error)
+	assert.Equal(t, "completed", first.Status)
+	assert.Equal(t, `"v1"`, first.ETag)
+	assert.Equal(t, "Wed, 14 Oct 2026 08:00:00 GMT", first.LastModified)
+
+	t.Run("an unchanged page records a lightweight crawl", func(t *testing.T) {
+		service.StartCrawl(urlRecord.ID)
+		assert.Equal(t, []string{"", `"v1"`}, conditional)
+
+		var latest models.Crawl
+		require.NoError(t, db.Where("url_id = ?", urlRecord.ID).Order("id DESC").First(&latest).Error)
+		assert.Equal(t, "unchanged", latest.Status)
+		assert.Equal(t, `"v1"`, latest.ETag)
+		assert.NotNil(t, latest.CompletedAt)
+
+		var links int64
+		require.NoError(t, db.Model(&models.Link{}).Where("crawl_id = ?", latest.ID).Count(&links).Error)
+		assert.Zero(t, links)
+
+		var stored models.URL
+		require.NoError(t, db.First(&stored, urlRecord.ID).Error)
+		assert.Equal(t, "completed", stored.Status)
+		assert.Equal(t, "Home", stored.Title)
+
+		status, err := service.GetCrawlStatus(urlRecord.ID)
+		require.NoError(t, err)
+		assert.Equal(t, latest.ID, status.ID)
+		assert.Equal(t, "unchanged", status.Status)
+		assert.Equal(t, 1, status.InternalLinks, "the results of the confirmed crawl are reported")
+	})
+
+	t.Run("a changed page is crawled again", func(t *testing.T) {
+		version = `"v2"`
+		service.StartCrawl(urlRecord.ID)
+
+		var latest models.Crawl
+		require.NoError(t, db.Where("url_id = ?", urlRecord.ID).Order("id DESC").First(&latest).Error)
+		assert.Equal(t, "completed", latest.Status)
+		assert.Equal(t, `"v2"`, latest.ETag)
+	})
+
+	t.Run("deep crawls always fetch the page", func(t *testing.T) {
+		require.NoError(t, db.Model(urlRecord).Update("max_depth", 1).Error)
+		conditional = nil
+		service.StartCrawl(urlRecord.ID)
+		require.NotEmpty(t, conditional)
+		assert.Empty(t, conditional[0])
+	})
+}
+
+func TestCrawlerService_CheckInternalLinks(t *testing.T) {
+	var heads int
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte(`<html><body><a href="/about">About</a><a href="/missing">Missing</a></body></html>`))
+		case "/about":
+			heads++
+		case "/missing":
+			heads++
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer site.Close()
+
+	crawl := func(t *testing.T, checkInternal bool) (models.Crawl, []models.Link) {
+		db := setupCrawlerTestDB(t)
+		service := NewCrawlerService(db)
+
+		urlRecord := &models.URL{URL: site.URL + "/", Status: "pending", CheckInternalLinks: checkInternal}
+		require.NoError(t, db.Create(urlRecord).Error)
+		service.StartCrawl(urlRecord.ID)
+
+		var crawl models.Crawl
+		require.NoError(t, db.Where("url_id = ?", urlRecord.ID).First(&crawl).Error)
+		var links []models.Link
+		require.NoError(t, db.Where("crawl_id = ?", crawl.ID).Order("id").Find(&links).Error)
+		require.Len(t, links, 2)
+		return crawl, links
+	}
+
+	t.Run("internal links are assumed accessible by default", func(t *testing.T) {
+		heads = 0
+		crawl, links := crawl(t, false)
+		assert.Zero(t, heads)
+		assert.Equal(t, 0, crawl.BrokenLinks)
+		assert.Equal(t, http.StatusOK, links[1].StatusCode)
+		assert.True(t, links[1].IsAccessible)
+	})
+
+	t.Run("internal links are requested when enabled", func(t *testing.T) {
+		heads = 0
+		crawl, links := crawl(t, true)
+		assert.Equal(t, 2, heads)
+		assert.Equal(t, 1, crawl.BrokenLinks)
+		assert.Equal(t, http.StatusOK, links[0].StatusCode)
+		assert.True(t, links[0].IsAccessible)
+		assert.Equal(t, http.StatusNotFound, links[1].StatusCode)
+		assert.False(t, links[1].IsAccessible)
+	})
+}
+
+func TestCrawlerService_performCrawlKeepsConcurrentEdits(t *testing.T) {
+	db := setupCrawlerTestDB(t)
+	service := NewCrawlerService(db)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<!DOCTYPE html><html><head><title>Fresh title</title></head><body></body></html>`))
+	}))
+	defer server.Close()
+
+	urlRecord := &models.URL{URL: server.URL, Status: "pending"}
+	require.NoError(t, db.Create(urlRecord).Error)
+	rule := &models.ExtractionRule{URLID: urlRecord.ID, Name: "price", Type: "css", Selector: ".price"}
+	require.NoError(t, db.Create(rule).Error)
+
+	// The crawl starts with the URL and its rules as they were
+	var started models.URL
+	require.NoError(t, db.Preload("ExtractionRules").First(&started, urlRecord.ID).Error)
+	crawl := &models.Crawl{URLID: urlRecord.ID, Status: "running"}
+	require.NoError(t, db.Create(crawl).Error)
+
+	// and the user edits both while it runs
+	require.NoError(t, db.Delete(rule).Error)
+	require.NoError(t, db.Model(&models.URL{}).Where("id = ?", urlRecord.ID).Update("max_pages", 7).Error)
+
+	service.performCrawl(&started, crawl)
+
+	var stored models.URL
+	require.NoError(t, db.First(&stored, urlRecord.ID).Error)
+	assert.Equal(t, "completed", stored.Status)
+	assert.Equal(t, "Fresh title", stored.Title)
+	assert.Equal(t, 7, stored.MaxPages)
+	var rules int64
+	require.NoError(t, db.Model(&models.ExtractionRule{}).Count(&rules).Error)
+	assert.Zero(t, rules)
+}
+
+func TestCrawlerService_performCrawlOfDeletedURLs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<!DOCTYPE html><html><head><title>Example</title></head><body></body></html>`))
+	}))
+	defer server.Close()
+
+	// start loads the URL and creates its crawl as startCrawl does
+	start := func(t *testing.T, db *gorm.DB) (*models.URL, *models.Crawl) {
+		urlRecord := &models.URL{URL: server.URL, Status: "running"}
+		require.NoError(t, db.Create(urlRecord).Error)
+		var started models.URL
+		require.NoError(t, db.Preload("ExtractionRules").First(&started, urlRecord.ID).Error)
+		crawl := &models.Crawl{URLID: urlRecord.ID, Status: "running"}
+		require.NoError(t, db.Create(crawl).Error)
+		return &started, crawl
+	}
+
+	t.Run("trashed while crawling stays in the trash", func(t *testing.T) {
+		db := setupCrawlerTestDB(t)
+		urlRecord, crawl := start(t, db)
+		require.NoError(t, db.Delete(&models.URL{}, urlRecord.ID).Error)
+
+		NewCrawlerService(db).performCrawl(urlRecord, crawl)
+
+		var stored models.URL
+		require.NoError(t, db.Unscoped().First(&stored, urlRecord.ID).Error)
+		assert.True(t, stored.DeletedAt.Valid)
+		assert.Equal(t, "completed", stored.Status, "the trashed URL can be purged once its crawl is done")
+	})
+
+	t.Run("purged while crawling stays purged", func(t *testing.T) {
+		db := setupCrawlerTestDB(t)
+		urlRecord, crawl := start(t, db)
+		require.NoError(t, db.Where("url_id = ?", urlRecord.ID).Delete(&models.Crawl{}).Error)
+		require.NoError(t, db.Unscoped().Delete(&models.URL{}, urlRecord.ID).Error)
+
+		NewCrawlerService(db).performCrawl(urlRecord, crawl)
+
+		var urls, crawls int64
+		require.NoError(t, db.Unscoped().Model(&models.URL{}).Count(&urls).Error)
+		require.NoError(t, db.Model(&models.Crawl{}).Count(&crawls).Error)
+		assert.Zero(t, urls)
+		assert.Zero(t, crawls)
+	})
+}
+
+func TestCrawlerService_extractImages(t *testing.T) {
+	db := setupCrawlerTestDB(t)
+	service := NewCrawlerService(db)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/logo.png" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	htmlContent := `<html><body>
+		<img src="/logo.png" alt="Company logo" width="120" height="40">
+		<img src="/missing.png">
+		<img src="/logo.png" alt="">
+		<img src="data:image/gif;base64,R0lGODlhAQABAAAAACw=" alt="pixel">
+		<img src="data:image/PNG;base64,` + strings.Repeat("iVBORw0KGgo", 1000) + `">
+		<img src="/` + strings.Repeat("a", 3000) + `.png">
+	</body></html>`
+
+	doc, err := html.Parse(strings.NewReader(htmlContent))
+	require.NoError(t, err)
+
+	data := service.extractData(doc, server.URL)
+	require.Len(t, data.Images, 6)
+
+	logo := data.Images[0]
+	assert.Equal(t, server.URL+"/logo.png", logo.Src)
+	assert.Equal(t, "Company logo", logo.Alt)
+	assert.Equal(t, "120", logo.Width)
+	assert.False(t, logo.MissingAlt)
+	assert.False(t, logo.IsBroken)
+	assert.Equal(t, http.StatusOK, logo.StatusCode)
+
+	missing := data.Images[1]
+	assert.True(t, missing.MissingAlt)
+	assert.True(t, missing.IsBroken)
+	assert.Equal(t, http.StatusNotFound, missing.StatusCode)
+
+	decorative := data.Images[2]
+	assert.False(t, decorative.MissingAlt)
+	assert.Empty(t, decorative.Alt)
+
+	inline := data.Images[3]
+	assert.Equal(t, "data:image/gif", inline.Src)
+	assert.False(t, inline.IsBroken)
+	assert.Zero(t, inline.StatusCode)
+	assert.Equal(t, "data:image/png", data.Images[4].Src)
+
+	// Too long for its column: cut to fit and left unchecked
+	long := data.Images[5]
+	assert.Len(t, long.Src, maxImageSrcLength)
+	assert.True(t, strings.HasPrefix(long.Src, server.URL+"/aaa"))
+	assert.False(t, long.IsBroken)
+	assert.Zero(t, long.StatusCode)
+}
+
+func TestCrawlerService_saveCrawlData(t *testing.T) {
+	db := setupCrawlerTestDB(t)
+	options := DefaultCrawlerOptions()
+	options.InsertBatchSize = 100
+	service := NewCrawlerServiceWithOptions(db, options)
+
+	urlRecord := &models.URL{URL: "https://example.com", Status: "running"}
+	require.NoError(t, db.Create(urlRecord).Error)
+	crawl := &models.Crawl{URLID: urlRecord.ID, Status: "running"}
+	require.NoError(t, db.Create(crawl).Error)
+
+	data := &CrawlData{Meta: models.PageMeta{OpenGraph: map[string]string{}, TwitterCard: map[string]string{}}}
+	for i := 0; i < 250; i++ {
+		data.Links = append(data.Links, models.Link{LinkURL: fmt.Sprintf("https://example.com/%d", i), LinkType: "internal"})
+	}
+	data.Images = []models.Image{{Src: "https://example.com/logo.png"}}
+
+	statements := map[string]int{}
+	require.NoError(t, db.Callback().Create().After("gorm:create").Register("test:count_inserts", func(tx *gorm.DB) {
+		statements[tx.Statement.Table]++
+	}))
+
+	require.NoError(t, service.saveCrawlData(urlRecord, crawl, data))
+	assert.Equal(t, 3, statements["links"])
+	assert.Equal(t, 1, statements["images"])
+	assert.Zero(t, statements["accessibility_issues"])
+
+	var links int64
+	db.Model(&models.Link{}).Where("crawl_id = ? AND url_id = ?", crawl.ID, urlRecord.ID).Count(&links)
+	assert.Equal(t, int64(250), links)
+
+	var meta models.PageMeta
+	assert.NoError(t, db.Where("crawl_id = ?", crawl.ID).First(&meta).Error)
+}
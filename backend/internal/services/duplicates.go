@@ -0,0 +1,81 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+
+	"gorm.io/gorm"
+
+	"web-crawler-backend/internal/models"
+)
+
+// GetDuplicates groups the pages of the latest completed crawl of a URL by
+// content hash and by canonical URL. Only groups of two or more pages are
+// reported; content clusters come first.
+func (s *URLService) GetDuplicates(urlID uint) (*models.DuplicatesReport, error) {
+	var url models.URL
+	if err := s.db.First(&url, urlID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrURLNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch URL: %w", err)
+	}
+
+	report := &models.DuplicatesReport{URLID: urlID, Clusters: []models.DuplicateCluster{}}
+
+	var crawl models.Crawl
+	if err := s.db.Where("url_id = ? AND status = ?", urlID, "completed").Order("created_at DESC").First(&crawl).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return report, nil
+		}
+		return nil, fmt.Errorf("failed to fetch latest crawl: %w", err)
+	}
+	report.CrawlID = crawl.ID
+
+	var pages []models.Page
+	if err := s.db.Where("crawl_id = ? AND error_message = ?", crawl.ID, "").Order("id ASC").Find(&pages).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch pages: %w", err)
+	}
+
+	byContent := map[string][]models.Page{}
+	byCanonical := map[string][]models.Page{}
+	for _, page := range pages {
+		if page.ContentHash != "" {
+			byContent[page.ContentHash] = append(byContent[page.ContentHash], page)
+		}
+		if page.Canonical != "" {
+			byCanonical[page.Canonical] = append(byCanonical[page.Canonical], page)
+		}
+	}
+
+	report.Clusters = append(report.Clusters, duplicateClusters(models.DuplicateByContent, byContent)...)
+	report.Clusters = append(report.Clusters, duplicateClusters(models.DuplicateByCanonical, byCanonical)...)
+	return report, nil
+}
+
+// duplicateClusters turns groups of two or more pages into clusters, largest first
+func duplicateClusters(clusterType string, groups map[string][]models.Page) []models.DuplicateCluster {
+	var clusters []models.DuplicateCluster
+	for key, pages := range groups {
+		if len(pages) < 2 {
+			continue
+		}
+
+		cluster := models.DuplicateCluster{Type: clusterType, Key: key, CanonicalConsistent: true}
+		for _, page := range pages {
+			cluster.Pages = append(cluster.Pages, models.DuplicatePage{PageURL: page.PageURL, Title: page.Title, Canonical: page.Canonical})
+			if page.Canonical == "" || page.Canonical != pages[0].Canonical {
+				cluster.CanonicalConsistent = false
+			}
+		}
+		clusters = append(clusters, cluster)
+	}
+
+	sort.Slice(clusters, func(i, j int) bool {
+		if len(clusters[i].Pages) != len(clusters[j].Pages) {
+			return len(clusters[i].Pages) > len(clusters[j].Pages)
+		}
+		return clusters[i].Key < clusters[j].Key
+	})
+	return clusters
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"web-crawler-backend/internal/models"
+)
+
+func TestURLService_GetDuplicates(t *testing.T) {
+	db := setupURLTestDB(t)
+	service := NewURLService(db, &mockCrawlerService{})
+
+	url := &models.URL{URL: "https://example.com", Status: "completed", MaxDepth: 2}
+	require.NoError(t, db.Create(url).Error)
+
+	t.Run("no completed crawl", func(t *testing.T) {
+		report, err := service.GetDuplicates(url.ID)
+		require.NoError(t, err)
+		assert.Zero(t, report.CrawlID)
+		assert.Empty(t, report.Clusters)
+	})
+
+	crawl := &models.Crawl{URLID: url.ID, Status: "completed"}
+	require.NoError(t, db.Create(crawl).Error)
+	require.NoError(t, db.Create(&[]models.Page{
+		{URLID: url.ID, CrawlID: crawl.ID, PageURL: "https://example.com/", ContentHash: "home"},
+		// Printer-friendly copy that points back to the original
+		{URLID: url.ID, CrawlID: crawl.ID, PageURL: "https://example.com/shoes", ContentHash: "shoes", Canonical: "https://example.com/shoes"},
+		{URLID: url.ID, CrawlID: crawl.ID, PageURL: "https://example.com/shoes?print=1", ContentHash: "shoes", Canonical: "https://example.com/shoes"},
+		// Copies without a canonical tag
+		{URLID: url.ID, CrawlID: crawl.ID, PageURL: "https://example.com/a", ContentHash: "same"},
+		{URLID: url.ID, CrawlID: crawl.ID, PageURL: "https://example.com/b", ContentHash: "same"},
+		{URLID: url.ID, CrawlID: crawl.ID, PageURL: "https://example.com/c", ContentHash: "same"},
+		{URLID: url.ID, CrawlID: crawl.ID, PageURL: "https://example.com/gone", ContentHash: "same", ErrorMessage: "HTTP 404: 404 Not Found"},
+	}).Error)
+
+	report, err := service.GetDuplicates(url.ID)
+	require.NoError(t, err)
+	assert.Equal(t, crawl.ID, report.CrawlID)
+	require.Len(t, report.Clusters, 3)
+
+	uncanonical := report.Clusters[0]
+	assert.Equal(t, models.DuplicateByContent, uncanonical.Type)
+	assert.Equal(t, "same", uncanonical.Key)
+	assert.Len(t, uncanonical.Pages, 3)
+	assert.False(t, uncanonical.CanonicalConsistent)
+
+	shoes := report.Clusters[1]
+	assert.Equal(t, models.DuplicateByContent, shoes.Type)
+	assert.True(t, shoes.CanonicalConsistent)
+
+	canonical := report.Clusters[2]
+	assert.Equal(t, models.DuplicateByCanonical, canonical.Type)
+	assert.Equal(t, "https://example.com/shoes", canonical.Key)
+	assert.Len(t, canonical.Pages, 2)
+}
@@ -0,0 +1,309 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+
+	"web-crawler-backend/internal/apperror"
+	"web-crawler-backend/internal/models"
+)
+
+// ErrEnvironmentPairNotFound is returned for pairs that don't exist, belong
+// to another organization or have a URL in the trash
+var ErrEnvironmentPairNotFound = apperror.New(http.StatusNotFound, "Environment pair not found", "The requested environment pair does not exist").WithCode("environment_pair_not_found")
+
+const (
+	// compareMaxWait bounds how long a comparison waits for its crawls when
+	// the request has no deadline
+	compareMaxWait = 2 * time.Minute
+	// comparePollInterval is how often the crawls of a comparison are checked
+	comparePollInterval = 500 * time.Millisecond
+)
+
+// EnvironmentService links the URLs of a site on two deployments, such as
+// production and staging, and compares their crawls
+type EnvironmentService struct {
+	db      *gorm.DB
+	crawler CrawlServiceInterface
+	// organizationID limits the service to the pairs and URLs of one
+	// organization, 0 for the ones outside any organization
+	organizationID uint
+	pollInterval   time.Duration
+}
+
+func NewEnvironmentService(db *gorm.DB, crawler CrawlServiceInterface) *EnvironmentService {
+	return &EnvironmentService{db: db, crawler: crawler, pollInterval: comparePollInterval}
+}
+
+// WithContext returns a copy of the service whose queries run with ctx, so
+// they are traced with the request and cancelled at its deadline
+func (s *EnvironmentService) WithContext(ctx context.Context) *EnvironmentService {
+	copied := *s
+	copied.db = s.db.WithContext(ctx)
+	return &copied
+}
+
+// WithOrganization returns a copy of the service limited to the pairs and
+// URLs of the organization
+func (s *EnvironmentService) WithOrganization(organizationID uint) *EnvironmentService {
+	copied := *s
+	copied.organizationID = organizationID
+	return &copied
+}
+
+// CreatePair links two URLs of the organization as an environment pair
+func (s *EnvironmentService) CreatePair(userID uint, req *models.CreateEnvironmentPairRequest) (*models.EnvironmentPair, error) {
+	var count int64
+	if err := s.db.Model(&models.URL{}).
+		Where("id IN ? AND organization_id = ?", []uint{req.BaselineURLID, req.CandidateURLID}, s.organizationID).
+		Count(&count).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch URLs: %w", err)
+	}
+	if count != 2 {
+		return nil, ErrURLNotFound
+	}
+
+	pair := &models.EnvironmentPair{
+		OrganizationID: s.organizationID,
+		Name:           req.Name,
+		BaselineURLID:  req.BaselineURLID,
+		CandidateURLID: req.CandidateURLID,
+		CreatedBy:      userID,
+	}
+	if err := s.db.Create(pair).Error; err != nil {
+		return nil, fmt.Errorf("failed to create environment pair: %w", err)
+	}
+	return s.GetPair(pair.ID)
+}
+
+// ListPairs returns the pairs of the organization with their URLs
+func (s *EnvironmentService) ListPairs() ([]models.EnvironmentPair, error) {
+	pairs := []models.EnvironmentPair{}
+	if err := s.db.Preload("BaselineURL").Preload("CandidateURL").
+		Where("organization_id = ?", s.organizationID).
+		Order("name ASC, id ASC").Find(&pairs).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch environment pairs: %w", err)
+	}
+	return pairs, nil
+}
+
+// GetPair returns a pair of the organization with its URLs
+func (s *EnvironmentService) GetPair(id uint) (*models.EnvironmentPair, error) {
+	var pair models.EnvironmentPair
+	if err := s.db.Preload("BaselineURL").Preload("CandidateURL").
+		Where("id = ? AND organization_id = ?", id, s.organizationID).First(&pair).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrEnvironmentPairNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch environment pair: %w", err)
+	}
+	if pair.BaselineURL == nil || pair.CandidateURL == nil {
+		// One of the URLs is in the trash
+		return nil, ErrEnvironmentPairNotFound
+	}
+	return &pair, nil
+}
+
+// DeletePair unlinks the URLs of a pair; the URLs and their crawls are kept
+func (s *EnvironmentService) DeletePair(id uint) error {
+	result := s.db.Where("id = ? AND organization_id = ?", id, s.organizationID).Delete(&models.EnvironmentPair{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete environment pair: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrEnvironmentPairNotFound
+	}
+	return nil
+}
+
+// Compare compares the latest completed crawls of a pair without crawling
+func (s *EnvironmentService) Compare(pairID uint) (*models.EnvironmentComparison, error) {
+	pair, err := s.GetPair(pairID)
+	if err != nil {
+		return nil, err
+	}
+	return s.compare(pair)
+}
+
+// CrawlAndCompare crawls both URLs of a pair and compares the results. It
+// waits for the crawls until ctx ends, or compareMaxWait without a deadline;
+// if they haven't finished by then, it compares the latest completed crawls
+// and reports the comparison as incomplete.
+func (s *EnvironmentService) CrawlAndCompare(ctx context.Context, pairID uint) (*models.EnvironmentComparison, error) {
+	pair, err := s.GetPair(pairID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Crawls started from here on have higher IDs than the latest ones now
+	urlIDs := []uint{pair.BaselineURLID, pair.CandidateURLID}
+	since := make(map[uint]uint, len(urlIDs))
+	for _, urlID := range urlIDs {
+		latest, err := s.latestCrawl(urlID)
+		if err != nil {
+			return nil, err
+		}
+		since[urlID] = latest.ID
+	}
+	for _, urlID := range urlIDs {
+		go s.crawler.StartCrawlWithPriority(ctx, urlID, models.CrawlPriorityHigh)
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, compareMaxWait)
+		defer cancel()
+	}
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+	for !s.crawlsFinished(since) {
+		select {
+		case <-ctx.Done():
+			// Compare what there is with a query that outlives the request
+			return s.WithContext(context.WithoutCancel(ctx)).compare(pair)
+		case <-ticker.C:
+		}
+	}
+	return s.compare(pair)
+}
+
+// crawlsFinished reports whether every URL has a finished crawl newer than the
+// given crawl ID. Lookup errors count as not finished and are retried.
+func (s *EnvironmentService) crawlsFinished(since map[uint]uint) bool {
+	for urlID, crawlID := range since {
+		latest, err := s.latestCrawl(urlID)
+		if err != nil || latest.ID <= crawlID || !crawlFinished(latest.Status) {
+			return false
+		}
+	}
+	return true
+}
+
+// compare builds the comparison of the latest completed crawls of a pair
+func (s *EnvironmentService) compare(pair *models.EnvironmentPair) (*models.EnvironmentComparison, error) {
+	comparison := &models.EnvironmentComparison{PairID: pair.ID, Complete: true}
+	var links [2]map[string]int
+	for i, target := range []struct {
+		side *models.EnvironmentCrawl
+		url  *models.URL
+	}{{&comparison.Baseline, pair.BaselineURL}, {&comparison.Candidate, pair.CandidateURL}} {
+		latest, err := s.latestCrawl(target.url.ID)
+		if err != nil {
+			return nil, err
+		}
+		*target.side = models.EnvironmentCrawl{URLID: target.url.ID, URL: target.url.URL, Status: latest.Status}
+		if latest.ID == 0 {
+			target.side.Status = "pending"
+		}
+		if !crawlFinished(target.side.Status) {
+			comparison.Complete = false
+		}
+
+		var crawl models.Crawl
+		if err := s.db.Where("url_id = ? AND status = ?", target.url.ID, "completed").
+			Order("created_at DESC, id DESC").Limit(1).Find(&crawl).Error; err != nil {
+			return nil, fmt.Errorf("failed to fetch the last completed crawl: %w", err)
+		}
+		target.side.CrawlID = crawl.ID
+		target.side.Title = crawl.Title
+		target.side.HeadingCounts = parseHeadingCounts(crawl.HeadingCounts)
+		target.side.BrokenLinks = crawl.BrokenLinks
+
+		if links[i], err = linkStatuses(s.db, crawl.ID, target.url.URL); err != nil {
+			return nil, err
+		}
+	}
+
+	baseline, candidate := links[0], links[1]
+	comparison.TitleChanged = comparison.Baseline.Title != comparison.Candidate.Title
+	comparison.HeadingChanges = headingDeltas(comparison.Baseline.HeadingCounts, comparison.Candidate.HeadingCounts)
+	comparison.LinksOnlyInBaseline = []string{}
+	comparison.LinksOnlyInCandidate = []string{}
+	comparison.StatusChanges = []models.LinkStatusDifference{}
+	for link, status := range baseline {
+		candidateStatus, ok := candidate[link]
+		if !ok {
+			comparison.LinksOnlyInBaseline = append(comparison.LinksOnlyInBaseline, link)
+		} else if candidateStatus != status {
+			comparison.StatusChanges = append(comparison.StatusChanges, models.LinkStatusDifference{
+				Link: link, BaselineStatus: status, CandidateStatus: candidateStatus,
+			})
+		}
+	}
+	for link := range candidate {
+		if _, ok := baseline[link]; !ok {
+			comparison.LinksOnlyInCandidate = append(comparison.LinksOnlyInCandidate, link)
+		}
+	}
+	sort.Strings(comparison.LinksOnlyInBaseline)
+	sort.Strings(comparison.LinksOnlyInCandidate)
+	sort.Slice(comparison.StatusChanges, func(i, j int) bool {
+		return comparison.StatusChanges[i].Link < comparison.StatusChanges[j].Link
+	})
+
+	comparison.Identical = !comparison.TitleChanged && len(comparison.HeadingChanges) == 0 &&
+		len(comparison.LinksOnlyInBaseline) == 0 && len(comparison.LinksOnlyInCandidate) == 0 && len(comparison.StatusChanges) == 0
+	return comparison, nil
+}
+
+// latestCrawl returns the newest crawl of a URL, or a zero crawl if it has none
+func (s *EnvironmentService) latestCrawl(urlID uint) (models.Crawl, error) {
+	var crawl models.Crawl
+	if err := s.db.Select("id, status").Where("url_id = ?", urlID).
+		Order("id DESC").Limit(1).Find(&crawl).Error; err != nil {
+		return crawl, fmt.Errorf("failed to fetch the latest crawl: %w", err)
+	}
+	return crawl, nil
+}
+
+// linkStatuses maps the links of a crawl to the HTTP status of their check,
+// keeping the status of a failed check for links found several times. Links
+// to the host of pageURL are keyed by their path and query, so the same link
+// on another deployment gets the same key.
+func linkStatuses(db *gorm.DB, crawlID uint, pageURL string) (map[string]int, error) {
+	statuses := map[string]int{}
+	if crawlID == 0 {
+		return statuses, nil
+	}
+	var links []models.Link
+	if err := db.Select("link_url, status_code, is_accessible").Where("crawl_id = ?", crawlID).Find(&links).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch links: %w", err)
+	}
+
+	host := ""
+	if page, err := url.Parse(pageURL); err == nil {
+		host = page.Host
+	}
+	for _, link := range links {
+		key := link.LinkURL
+		if parsed, err := url.Parse(link.LinkURL); err == nil && parsed.Host != "" && parsed.Host == host {
+			key = parsed.EscapedPath()
+			if key == "" {
+				key = "/"
+			}
+			if parsed.RawQuery != "" {
+				key += "?" + parsed.RawQuery
+			}
+		}
+		if _, seen := statuses[key]; !seen || !link.IsAccessible {
+			statuses[key] = link.StatusCode
+		}
+	}
+	return statuses, nil
+}
+
+// crawlFinished reports whether a crawl status is final
+func crawlFinished(status string) bool {
+	switch status {
+	case "completed", "unchanged", "skipped", "error":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,164 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"web-crawler-backend/internal/models"
+)
+
+// pageCrawl is what the fake crawler finds on a page
+type pageCrawl struct {
+	title    string
+	headings string
+	links    map[string]int
+}
+
+// fakeEnvironmentCrawler completes crawls with fixed results, or leaves them
+// running for pages it has no results for
+type fakeEnvironmentCrawler struct {
+	CrawlServiceInterface
+	db    *gorm.DB
+	pages map[uint]pageCrawl
+}
+
+func (f *fakeEnvironmentCrawler) StartCrawlWithPriority(ctx context.Context, urlID uint, priority models.CrawlPriority) {
+	page, ok := f.pages[urlID]
+	if !ok {
+		f.db.Create(&models.Crawl{URLID: urlID, Status: "running"})
+		return
+	}
+	addCompletedCrawl(f.db, urlID, page)
+}
+
+func addCompletedCrawl(db *gorm.DB, urlID uint, page pageCrawl) {
+	crawl := &models.Crawl{URLID: urlID, Status: "completed", Title: page.title, HeadingCounts: page.headings}
+	db.Create(crawl)
+	for link, status := range page.links {
+		db.Create(&models.Link{URLID: urlID, CrawlID: crawl.ID, LinkURL: link, StatusCode: status, IsAccessible: status < 400})
+	}
+}
+
+func setupEnvironmentTest(t *testing.T) (*EnvironmentService, *fakeEnvironmentCrawler, *gorm.DB) {
+	db := setupURLTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.EnvironmentPair{}))
+	// The crawls are started concurrently; every connection to :memory: is a
+	// database of its own
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	crawler := &fakeEnvironmentCrawler{db: db, pages: map[uint]pageCrawl{}}
+	service := NewEnvironmentService(db, crawler)
+	service.pollInterval = time.Millisecond
+	return service, crawler, db
+}
+
+func createEnvironmentPair(t *testing.T, service *EnvironmentService, db *gorm.DB) *models.EnvironmentPair {
+	production := &models.URL{URL: "https://example.com/"}
+	staging := &models.URL{URL: "https://staging.example.com/"}
+	require.NoError(t, db.Create(production).Error)
+	require.NoError(t, db.Create(staging).Error)
+
+	pair, err := service.CreatePair(1, &models.CreateEnvironmentPairRequest{
+		Name: "Home", BaselineURLID: production.ID, CandidateURLID: staging.ID,
+	})
+	require.NoError(t, err)
+	return pair
+}
+
+func TestEnvironmentService_CreatePair(t *testing.T) {
+	service, _, db := setupEnvironmentTest(t)
+	pair := createEnvironmentPair(t, service, db)
+	assert.Equal(t, "https://example.com/", pair.BaselineURL.URL)
+	assert.Equal(t, "https://staging.example.com/", pair.CandidateURL.URL)
+
+	t.Run("URLs of another organization", func(t *testing.T) {
+		_, err := service.WithOrganization(7).CreatePair(1, &models.CreateEnvironmentPairRequest{
+			Name: "Home", BaselineURLID: pair.BaselineURLID, CandidateURLID: pair.CandidateURLID,
+		})
+		assert.ErrorIs(t, err, ErrURLNotFound)
+	})
+
+	t.Run("pairs are scoped to the organization", func(t *testing.T) {
+		_, err := service.WithOrganization(7).GetPair(pair.ID)
+		assert.ErrorIs(t, err, ErrEnvironmentPairNotFound)
+		assert.ErrorIs(t, service.WithOrganization(7).DeletePair(pair.ID), ErrEnvironmentPairNotFound)
+
+		pairs, err := service.ListPairs()
+		require.NoError(t, err)
+		assert.Len(t, pairs, 1)
+	})
+
+	t.Run("pairs with a URL in the trash", func(t *testing.T) {
+		require.NoError(t, db.Delete(&models.URL{}, pair.CandidateURLID).Error)
+		_, err := service.GetPair(pair.ID)
+		assert.ErrorIs(t, err, ErrEnvironmentPairNotFound)
+	})
+}
+
+func TestEnvironmentService_CrawlAndCompare(t *testing.T) {
+	service, crawler, db := setupEnvironmentTest(t)
+	pair := createEnvironmentPair(t, service, db)
+
+	crawler.pages[pair.BaselineURLID] = pageCrawl{
+		title:    "Example",
+		headings: `{"h1":1,"h2":3}`,
+		links: map[string]int{
+			"https://example.com/about":   200,
+			"https://example.com/pricing": 200,
+			"https://example.com/blog":    200,
+			"https://docs.example.org/":   200,
+		},
+	}
+	crawler.pages[pair.CandidateURLID] = pageCrawl{
+		title:    "Example (staging)",
+		headings: `{"h1":1,"h2":2}`,
+		links: map[string]int{
+			"https://staging.example.com/about":   200,
+			"https://staging.example.com/pricing": 404,
+			"https://staging.example.com/careers": 200,
+			"https://docs.example.org/":           200,
+		},
+	}
+
+	comparison, err := service.CrawlAndCompare(context.Background(), pair.ID)
+	require.NoError(t, err)
+	assert.True(t, comparison.Complete)
+	assert.False(t, comparison.Identical)
+	assert.True(t, comparison.TitleChanged)
+	assert.Equal(t, "Example", comparison.Baseline.Title)
+	assert.Equal(t, "completed", comparison.Candidate.Status)
+	assert.Equal(t, map[string]int{"h2": -1}, comparison.HeadingChanges)
+	assert.Equal(t, []string{"/blog"}, comparison.LinksOnlyInBaseline)
+	assert.Equal(t, []string{"/careers"}, comparison.LinksOnlyInCandidate)
+	assert.Equal(t, []models.LinkStatusDifference{
+		{Link: "/pricing", BaselineStatus: 200, CandidateStatus: 404},
+	}, comparison.StatusChanges)
+}
+
+func TestEnvironmentService_CrawlAndCompareTimeout(t *testing.T) {
+	service, crawler, db := setupEnvironmentTest(t)
+	pair := createEnvironmentPair(t, service, db)
+
+	// The same page on both deployments, crawled before; the new crawl of
+	// staging doesn't finish in time
+	page := pageCrawl{title: "Example", headings: `{"h1":1}`, links: map[string]int{}}
+	addCompletedCrawl(db, pair.BaselineURLID, page)
+	addCompletedCrawl(db, pair.CandidateURLID, page)
+	crawler.pages[pair.BaselineURLID] = page
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	comparison, err := service.CrawlAndCompare(ctx, pair.ID)
+	require.NoError(t, err)
+	assert.False(t, comparison.Complete)
+	assert.Equal(t, "running", comparison.Candidate.Status)
+	assert.NotZero(t, comparison.Candidate.CrawlID)
+	assert.True(t, comparison.Identical)
+}
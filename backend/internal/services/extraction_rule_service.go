@@ -0,0 +1,219 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"gorm.io/gorm"
+
+	"web-crawler-backend/internal/models"
+	"web-crawler-backend/internal/selector"
+)
+
+var (
+	// ErrExtractionRuleNotFound is returned when a rule doesn't exist for the URL
+	ErrExtractionRuleNotFound = errors.New("extraction rule not found")
+	// ErrInvalidExtractionRule is returned for bad names, types and selectors
+	ErrInvalidExtractionRule = errors.New("invalid extraction rule")
+)
+
+// maxExtractionRules limits the rules of a URL, as every crawl evaluates all of them
+const maxExtractionRules = 50
+
+var ruleNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,100}$`)
+
+type ExtractionRuleService struct {
+	db *gorm.DB
+}
+
+func NewExtractionRuleService(db *gorm.DB) *ExtractionRuleService {
+	return &ExtractionRuleService{db: db}
+}
+
+// WithContext returns a copy of the service whose queries run with ctx, so
+// they are traced with the request and cancelled at its deadline
+func (s *ExtractionRuleService) WithContext(ctx context.Context) *ExtractionRuleService {
+	return &ExtractionRuleService{db: s.db.WithContext(ctx)}
+}
+
+// ListRules returns the extraction rules of a URL by name
+func (s *ExtractionRuleService) ListRules(urlID uint) ([]models.ExtractionRule, error) {
+	if err := s.findURL(urlID); err != nil {
+		return nil, err
+	}
+
+	var rules []models.ExtractionRule
+	if err := s.db.Where("url_id = ?", urlID).Order("name ASC").Find(&rules).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch extraction rules: %w", err)
+	}
+	return rules, nil
+}
+
+// CreateRule adds an extraction rule to a URL
+func (s *ExtractionRuleService) CreateRule(urlID uint, req models.ExtractionRuleRequest) (*models.ExtractionRule, error) {
+	if err := s.findURL(urlID); err != nil {
+		return nil, err
+	}
+
+	var count int64
+	if err := s.db.Model(&models.ExtractionRule{}).Where("url_id = ?", urlID).Count(&count).Error; err != nil {
+		return nil, fmt.Errorf("failed to count extraction rules: %w", err)
+	}
+	if count >= maxExtractionRules {
+		return nil, fmt.Errorf("%w: a URL can have at most %d rules", ErrInvalidExtractionRule, maxExtractionRules)
+	}
+
+	rule := &models.ExtractionRule{URLID: urlID}
+	if err := s.applyRequest(rule, req); err != nil {
+		return nil, err
+	}
+	if err := s.db.Create(rule).Error; err != nil {
+		return nil, fmt.Errorf("failed to create extraction rule: %w", err)
+	}
+	return rule, nil
+}
+
+// UpdateRule replaces the name, selector and options of a rule
+func (s *ExtractionRuleService) UpdateRule(urlID, ruleID uint, req models.ExtractionRuleRequest) (*models.ExtractionRule, error) {
+	rule, err := s.findRule(urlID, ruleID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.applyRequest(rule, req); err != nil {
+		return nil, err
+	}
+	if err := s.db.Save(rule).Error; err != nil {
+		return nil, fmt.Errorf("failed to update extraction rule: %w", err)
+	}
+	return rule, nil
+}
+
+// DeleteRule removes a rule; earlier crawls keep the values it extracted
+func (s *ExtractionRuleService) DeleteRule(urlID, ruleID uint) error {
+	rule, err := s.findRule(urlID, ruleID)
+	if err != nil {
+		return err
+	}
+	if err := s.db.Delete(rule).Error; err != nil {
+		return fmt.Errorf("failed to delete extraction rule: %w", err)
+	}
+	return nil
+}
+
+func (s *ExtractionRuleService) findURL(urlID uint) error {
+	if err := s.db.First(&models.URL{}, urlID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrURLNotFound
+		}
+		return fmt.Errorf("failed to fetch URL: %w", err)
+	}
+	return nil
+}
+
+func (s *ExtractionRuleService) findRule(urlID, ruleID uint) (*models.ExtractionRule, error) {
+	var rule models.ExtractionRule
+	if err := s.db.Where("id = ? AND url_id = ?", ruleID, urlID).First(&rule).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrExtractionRuleNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch extraction rule: %w", err)
+	}
+	return &rule, nil
+}
+
+// applyRequest validates a request and copies it into the rule. Names are
+// unique per URL since they key the extracted fields.
+func (s *ExtractionRuleService) applyRequest(rule *models.ExtractionRule, req models.ExtractionRuleRequest) error {
+	name := strings.TrimSpace(req.Name)
+	if !ruleNamePattern.MatchString(name) {
+		return fmt.Errorf("%w: names may only contain letters, digits, _ and - (at most 100)", ErrInvalidExtractionRule)
+	}
+
+	candidate := models.ExtractionRule{
+		Name:      name,
+		Type:      strings.ToLower(strings.TrimSpace(req.Type)),
+		Selector:  strings.TrimSpace(req.Selector),
+		Attribute: strings.ToLower(strings.TrimSpace(req.Attribute)),
+		Multiple:  req.Multiple,
+	}
+	if candidate.Type == "" {
+		candidate.Type = models.SelectorCSS
+	}
+	if len(candidate.Attribute) > 100 {
+		return fmt.Errorf("%w: attribute names are at most 100 characters", ErrInvalidExtractionRule)
+	}
+	if _, err := compileRule(candidate); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidExtractionRule, err)
+	}
+
+	var taken int64
+	if err := s.db.Model(&models.ExtractionRule{}).
+		Where("url_id = ? AND name = ? AND id <> ?", rule.URLID, name, rule.ID).Count(&taken).Error; err != nil {
+		return fmt.Errorf("failed to check extraction rule names: %w", err)
+	}
+	if taken > 0 {
+		return fmt.Errorf("%w: the URL already has a rule named %q", ErrInvalidExtractionRule, name)
+	}
+
+	rule.Name = candidate.Name
+	rule.Type = candidate.Type
+	rule.Selector = candidate.Selector
+	rule.Attribute = candidate.Attribute
+	rule.Multiple = candidate.Multiple
+	return nil
+}
+
+// compileRule compiles the selector of a rule. The rule's attribute takes
+// precedence over one selected by an XPath @name step.
+func compileRule(rule models.ExtractionRule) (*selector.Query, error) {
+	var query *selector.Query
+	var err error
+	switch rule.Type {
+	case models.SelectorCSS:
+		query, err = selector.CSS(rule.Selector)
+	case models.SelectorXPath:
+		query, err = selector.XPath(rule.Selector)
+	default:
+		return nil, fmt.Errorf("type must be %q or %q", models.SelectorCSS, models.SelectorXPath)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if rule.Attribute != "" {
+		query.Attr = rule.Attribute
+	}
+	return query, nil
+}
+
+// extractFields evaluates the rules on a page. Fields are the first value
+// found, or every value for rules with Multiple set; rules without a match
+// extract null.
+func extractFields(doc *html.Node, rules []models.ExtractionRule) map[string]interface{} {
+	fields := make(map[string]interface{}, len(rules))
+	for _, rule := range rules {
+		query, err := compileRule(rule)
+		if err != nil {
+			log.Printf("Skipping extraction rule %d: %v", rule.ID, err)
+			continue
+		}
+
+		values := query.Values(doc)
+		switch {
+		case rule.Multiple:
+			if values == nil {
+				values = []string{}
+			}
+			fields[rule.Name] = values
+		case len(values) > 0:
+			fields[rule.Name] = values[0]
+		default:
+			fields[rule.Name] = nil
+		}
+	}
+	return fields
+}
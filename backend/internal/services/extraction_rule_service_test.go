@@ -0,0 +1,96 @@
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"web-crawler-backend/internal/models"
+)
+
+func TestExtractionRuleService(t *testing.T) {
+	setup := func(t *testing.T) (*ExtractionRuleService, *CrawlerService, *models.URL) {
+		db := setupCrawlerTestDB(t)
+		url := &models.URL{URL: "https://example.com", Status: "pending"}
+		require.NoError(t, db.Create(url).Error)
+		return NewExtractionRuleService(db), NewCrawlerService(db), url
+	}
+
+	t.Run("validates rules", func(t *testing.T) {
+		service, _, url := setup(t)
+
+		rule, err := service.CreateRule(url.ID, models.ExtractionRuleRequest{Name: "price", Selector: ".price"})
+		require.NoError(t, err)
+		assert.Equal(t, models.SelectorCSS, rule.Type)
+
+		for _, req := range []models.ExtractionRuleRequest{
+			{Name: "price", Selector: "h1"},
+			{Name: "has space", Selector: "h1"},
+			{Name: "title", Type: "regex", Selector: "h1"},
+			{Name: "title", Selector: "h1["},
+			{Name: "title", Type: models.SelectorXPath, Selector: "//h1[position() < 2]"},
+		} {
+			_, err := service.CreateRule(url.ID, req)
+			assert.ErrorIs(t, err, ErrInvalidExtractionRule, req)
+		}
+
+		_, err = service.CreateRule(url.ID+1, models.ExtractionRuleRequest{Name: "price", Selector: ".price"})
+		assert.ErrorIs(t, err, ErrURLNotFound)
+	})
+
+	t.Run("updates and deletes rules", func(t *testing.T) {
+		service, _, url := setup(t)
+
+		rule, err := service.CreateRule(url.ID, models.ExtractionRuleRequest{Name: "price", Selector: ".price"})
+		require.NoError(t, err)
+		updated, err := service.UpdateRule(url.ID, rule.ID, models.ExtractionRuleRequest{Name: "price", Type: "XPath", Selector: "//span"})
+		require.NoError(t, err)
+		assert.Equal(t, models.SelectorXPath, updated.Type)
+
+		_, err = service.UpdateRule(url.ID+1, rule.ID, models.ExtractionRuleRequest{Name: "price", Selector: "span"})
+		assert.ErrorIs(t, err, ErrExtractionRuleNotFound)
+
+		require.NoError(t, service.DeleteRule(url.ID, rule.ID))
+		rules, err := service.ListRules(url.ID)
+		require.NoError(t, err)
+		assert.Empty(t, rules)
+	})
+
+	t.Run("crawls extract the fields", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte(`<html><head><title>Grinder</title></head><body>
+				<span class="price" data-currency="EUR">49.90</span>
+				<ul><li>Steel</li><li>Ceramic</li></ul>
+			</body></html>`))
+		}))
+		defer server.Close()
+
+		service, crawler, url := setup(t)
+		require.NoError(t, crawler.db.Model(url).Update("url", server.URL).Error)
+		for _, req := range []models.ExtractionRuleRequest{
+			{Name: "price", Selector: "span.price"},
+			{Name: "currency", Type: models.SelectorXPath, Selector: "//span/@data-currency"},
+			{Name: "materials", Selector: "li", Multiple: true},
+			{Name: "sku", Selector: "[itemprop=sku]"},
+		} {
+			_, err := service.CreateRule(url.ID, req)
+			require.NoError(t, err)
+		}
+
+		crawler.StartCrawl(url.ID)
+
+		status, err := crawler.GetCrawlStatus(url.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "completed", status.Status)
+		assert.Equal(t, map[string]interface{}{
+			"price":     "49.90",
+			"currency":  "EUR",
+			"materials": []interface{}{"Steel", "Ceramic"},
+			"sku":       nil,
+		}, status.Fields)
+	})
+}
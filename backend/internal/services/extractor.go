@@ -0,0 +1,243 @@
+package services
+
+import (
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/html"
+
+	"web-crawler-backend/internal/models"
+)
+
+// Names of the built-in extractors
+const (
+	ExtractorHTMLVersion   = "html_version"
+	ExtractorTitle         = "title"
+	ExtractorHeadings      = "headings"
+	ExtractorLinks         = "links"
+	ExtractorForms         = "forms"
+	ExtractorMeta          = "meta"
+	ExtractorImages        = "images"
+	ExtractorAccessibility = "accessibility"
+	ExtractorMixedContent  = "mixed_content"
+	ExtractorFields        = "fields"
+	ExtractorKeywords      = "keywords"
+	ExtractorReadability   = "readability"
+	ExtractorIcons         = "icons"
+)
+
+// Extractor collects one kind of data from a page. Pages are walked once and
+// every node is handed to every enabled extractor, so new analyses don't need
+// changes to the traversal.
+type Extractor interface {
+	// Name identifies the extractor in the settings of a URL, e.g. "links"
+	Name() string
+	// Visit is called for every node of the page in document order
+	Visit(n *html.Node, page *PageContext)
+}
+
+// DocumentExtractor is implemented by extractors that analyze the whole page
+// once every node has been visited. Deep crawls only run them on the seed page.
+type DocumentExtractor interface {
+	Extractor
+	Finish(doc *html.Node, page *PageContext)
+}
+
+// PageContext is the page being extracted
+type PageContext struct {
+	BaseURL *url.URL
+	Data    *CrawlData
+	// URL is the crawled URL with its settings and extraction rules
+	URL *models.URL
+}
+
+// Set records the result of a custom extractor. Results are stored with the
+// crawl as a JSON object keyed by name.
+func (p *PageContext) Set(name string, value interface{}) {
+	if p.Data.Extracted == nil {
+		p.Data.Extracted = map[string]interface{}{}
+	}
+	p.Data.Extracted[name] = value
+}
+
+// extractorFunc adapts a function to the Extractor interface
+type extractorFunc struct {
+	name  string
+	visit func(n *html.Node, page *PageContext)
+}
+
+func (e extractorFunc) Name() string { return e.name }
+
+func (e extractorFunc) Visit(n *html.Node, page *PageContext) { e.visit(n, page) }
+
+// NewExtractor returns an extractor that calls visit for every node
+func NewExtractor(name string, visit func(n *html.Node, page *PageContext)) Extractor {
+	return extractorFunc{name: name, visit: visit}
+}
+
+// elementExtractor returns an extractor that calls visit for the elements
+// with one of the given tags
+func elementExtractor(name string, visit func(n *html.Node, page *PageContext), tags ...string) Extractor {
+	return NewExtractor(name, func(n *html.Node, page *PageContext) {
+		if n.Type == html.ElementNode && slices.Contains(tags, n.Data) {
+			visit(n, page)
+		}
+	})
+}
+
+// documentExtractor analyzes the whole page after the traversal, optionally
+// collecting nodes during it
+type documentExtractor struct {
+	name   string
+	visit  func(n *html.Node, page *PageContext)
+	finish func(doc *html.Node, page *PageContext)
+}
+
+func (e documentExtractor) Name() string { return e.name }
+
+func (e documentExtractor) Visit(n *html.Node, page *PageContext) {
+	if e.visit != nil {
+		e.visit(n, page)
+	}
+}
+
+func (e documentExtractor) Finish(doc *html.Node, page *PageContext) { e.finish(doc, page) }
+
+// WithExtractors returns a copy of the service that also runs the given
+// extractors on every page, after the built-in ones
+func (s *CrawlerService) WithExtractors(extractors ...Extractor) *CrawlerService {
+	copied := *s
+	copied.extractors = append(slices.Clip(s.extractors), extractors...)
+	return &copied
+}
+
+// builtinExtractors returns the extractors every crawl runs unless disabled
+func (s *CrawlerService) builtinExtractors() []Extractor {
+	return []Extractor{
+		NewExtractor(ExtractorHTMLVersion, func(n *html.Node, page *PageContext) {
+			if n.Type == html.DoctypeNode {
+				s.detectHTMLVersion(n, page.Data)
+			}
+		}),
+		elementExtractor(ExtractorTitle, func(n *html.Node, page *PageContext) {
+			if page.Data.Title == "" && n.FirstChild != nil {
+				page.Data.Title = strings.TrimSpace(n.FirstChild.Data)
+			}
+		}, "title"),
+		elementExtractor(ExtractorHeadings, func(n *html.Node, page *PageContext) {
+			countHeading(&page.Data.HeadingCounts, n.Data)
+		}, "h1", "h2", "h3", "h4", "h5", "h6"),
+		elementExtractor(ExtractorLinks, func(n *html.Node, page *PageContext) {
+			s.processLink(n, page.Data, page.BaseURL)
+		}, "a"),
+		elementExtractor(ExtractorForms, func(n *html.Node, page *PageContext) {
+			s.processForm(n, page.Data, page.BaseURL)
+		}, "form"),
+		elementExtractor(ExtractorMeta, func(n *html.Node, page *PageContext) {
+			if n.Data == "meta" {
+				s.processMeta(n, page.Data)
+			} else {
+				s.processLinkTag(n, page.Data, page.BaseURL)
+			}
+		}, "meta", "link"),
+		elementExtractor(ExtractorImages, func(n *html.Node, page *PageContext) {
+			s.processImage(n, page.Data, page.BaseURL)
+		}, "img"),
+		documentExtractor{name: ExtractorAccessibility, finish: func(doc *html.Node, page *PageContext) {
+			s.checkAccessibility(doc, page.Data)
+		}},
+		documentExtractor{name: ExtractorMixedContent, finish: func(doc *html.Node, page *PageContext) {
+			s.checkMixedContent(doc, page.Data, page.BaseURL)
+		}},
+		documentExtractor{name: ExtractorFields, finish: func(doc *html.Node, page *PageContext) {
+			if page.URL != nil && len(page.URL.ExtractionRules) > 0 {
+				page.Set(ExtractorFields, extractFields(doc, page.URL.ExtractionRules))
+			}
+		}},
+		documentExtractor{name: ExtractorReadability, finish: func(doc *html.Node, page *PageContext) {
+			stats := measureReadability(visibleWords(doc))
+			page.Data.WordCount = stats.Words
+			page.Data.Readability = stats.FleschReadingEase()
+		}},
+		documentExtractor{
+			name: ExtractorIcons,
+			visit: func(n *html.Node, page *PageContext) {
+				if n.Type == html.ElementNode && n.Data == "link" {
+					s.processIcon(n, page.Data, page.BaseURL)
+				}
+			},
+			finish: func(doc *html.Node, page *PageContext) {
+				addImplicitFavicon(page.Data, page.BaseURL)
+			},
+		},
+		documentExtractor{name: ExtractorKeywords, finish: func(doc *html.Node, page *PageContext) {
+			if page.URL != nil {
+				page.Data.KeywordChecks = checkKeywords(doc, page.URL.Keywords)
+			}
+		}},
+	}
+}
+
+// countHeading adds a heading element to the counts
+func countHeading(counts *models.HeadingCounts, tag string) {
+	switch tag {
+	case "h1":
+		counts.H1++
+	case "h2":
+		counts.H2++
+	case "h3":
+		counts.H3++
+	case "h4":
+		counts.H4++
+	case "h5":
+		counts.H5++
+	case "h6":
+		counts.H6++
+	}
+}
+
+// ExtractorNames returns the names of the built-in and custom extractors, sorted
+func (s *CrawlerService) ExtractorNames() []string {
+	var names []string
+	for _, extractor := range append(s.builtinExtractors(), s.extractors...) {
+		names = append(names, extractor.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+// extractorPipeline is the extractors enabled for a URL, in the order they run
+type extractorPipeline []Extractor
+
+// pipeline returns the built-in and custom extractors except the disabled ones
+func (s *CrawlerService) pipeline(disabled []string) extractorPipeline {
+	var pipeline extractorPipeline
+	for _, extractor := range append(s.builtinExtractors(), s.extractors...) {
+		if !slices.Contains(disabled, extractor.Name()) {
+			pipeline = append(pipeline, extractor)
+		}
+	}
+	return pipeline
+}
+
+// traverse hands n and every node below it to the extractors
+func (p extractorPipeline) traverse(n *html.Node, page *PageContext) {
+	for _, extractor := range p {
+		extractor.Visit(n, page)
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		p.traverse(c, page)
+	}
+}
+
+// run traverses the page and then lets the document extractors analyze it
+func (p extractorPipeline) run(doc *html.Node, page *PageContext) {
+	p.traverse(doc, page)
+	for _, extractor := range p {
+		if document, ok := extractor.(DocumentExtractor); ok {
+			document.Finish(doc, page)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/html"
+
+	"web-crawler-backend/internal/models"
+)
+
+func TestExtractorPipeline(t *testing.T) {
+	doc, err := html.Parse(strings.NewReader(`<!DOCTYPE html><html><head><title>Shop</title></head><body>
+		<h1>Products</h1><a href="/cart">Cart</a><img src="/logo.png">
+		<script type="application/ld+json">{}</script><script type="application/ld+json">{}</script>
+	</body></html>`))
+	require.NoError(t, err)
+
+	// Counts the structured data blocks of a page
+	structuredData := NewExtractor("structured_data", func(n *html.Node, page *PageContext) {
+		if n.Type == html.ElementNode && n.Data == "script" && getAttr(n, "type") == "application/ld+json" {
+			count, _ := page.Data.Extracted["structured_data"].(int)
+			page.Set("structured_data", count+1)
+		}
+	})
+	service := NewCrawlerService(setupCrawlerTestDB(t)).WithExtractors(structuredData)
+
+	t.Run("runs built-in and custom extractors", func(t *testing.T) {
+		data, ok := service.parsePageData(doc, &models.URL{URL: "https://example.com"})
+		require.True(t, ok)
+		assert.Equal(t, "HTML5", data.HTMLVersion)
+		assert.Equal(t, "Shop", data.Title)
+		assert.Equal(t, 1, data.HeadingCounts.H1)
+		assert.Len(t, data.Links, 1)
+		assert.Len(t, data.Images, 1)
+		assert.Equal(t, 2, data.Extracted["structured_data"])
+
+		crawl := &models.Crawl{}
+		applyCrawlData(crawl, data)
+		assert.JSONEq(t, `{"structured_data":2}`, crawl.ExtractedData)
+	})
+
+	t.Run("skips disabled extractors", func(t *testing.T) {
+		data, ok := service.parsePageData(doc, &models.URL{
+			URL:                "https://example.com",
+			DisabledExtractors: []string{ExtractorLinks, ExtractorImages, "structured_data"},
+		})
+		require.True(t, ok)
+		assert.Equal(t, "Shop", data.Title)
+		assert.Empty(t, data.Links)
+		assert.Empty(t, data.Images)
+		assert.Nil(t, data.Extracted)
+	})
+
+	t.Run("lists extractor names", func(t *testing.T) {
+		names := service.ExtractorNames()
+		assert.Contains(t, names, ExtractorMixedContent)
+		assert.Contains(t, names, "structured_data")
+		assert.NotContains(t, NewCrawlerService(nil).ExtractorNames(), "structured_data")
+	})
+}
@@ -0,0 +1,199 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"web-crawler-backend/internal/models"
+)
+
+// ErrUnknownFeatureFlag is returned for flag names no feature consults
+var ErrUnknownFeatureFlag = errors.New("unknown feature flag")
+
+// featureFlag describes a feature that can be rolled out gradually
+type featureFlag struct {
+	description string
+	// enabled is the default while the flag has no stored setting
+	enabled bool
+}
+
+// featureFlags are the flags the services consult. Flags for features that
+// already shipped default to on, so turning them off is a kill switch.
+var featureFlags = map[string]featureFlag{
+	models.FeatureDeepCrawl: {description: "Crawls follow internal links beyond the root page", enabled: true},
+}
+
+// FeatureFlagService decides whether a feature is on for a user. Admins store
+// a setting per flag, turning it on for everyone or for chosen users;
+// overrides from the environment win over it, e.g. to turn a feature off on
+// one deployment. A nil *FeatureFlagService uses the defaults.
+type FeatureFlagService struct {
+	db        *gorm.DB
+	overrides map[string]bool
+}
+
+// NewFeatureFlagService creates the service with the overrides of the
+// environment; overrides of unknown flags are ignored with a warning
+func NewFeatureFlagService(db *gorm.DB, overrides map[string]bool) *FeatureFlagService {
+	known := make(map[string]bool, len(overrides))
+	for name, enabled := range overrides {
+		if _, ok := featureFlags[name]; !ok {
+			log.Printf("Ignoring override of unknown feature flag %q", name)
+			continue
+		}
+		known[name] = enabled
+	}
+	return &FeatureFlagService{db: db, overrides: known}
+}
+
+// Enabled reports whether a feature is on for a user; user 0 only gets
+// features that are on for everyone. If the setting can't be read, the
+// default is used.
+func (s *FeatureFlagService) Enabled(name string, userID uint) bool {
+	flag := featureFlags[name]
+	if s == nil {
+		return flag.enabled
+	}
+	if enabled, ok := s.overrides[name]; ok {
+		return enabled
+	}
+
+	var stored []models.FeatureFlag
+	if err := s.db.Where("name = ?", name).Limit(1).Find(&stored).Error; err != nil {
+		log.Printf("Failed to read feature flag %s, using its default: %v", name, err)
+		return flag.enabled
+	}
+	if len(stored) == 0 {
+		return flag.enabled
+	}
+	return stored[0].Enabled || containsUserID(parseUserIDs(stored[0].UserIDList), userID)
+}
+
+// EnabledFor lists the features that are on for a user
+func (s *FeatureFlagService) EnabledFor(userID uint) []string {
+	enabled := []string{}
+	for _, name := range featureFlagNames() {
+		if s.Enabled(name, userID) {
+			enabled = append(enabled, name)
+		}
+	}
+	return enabled
+}
+
+// List returns the effective setting of every flag, sorted by name
+func (s *FeatureFlagService) List() ([]models.FeatureFlagState, error) {
+	var stored []models.FeatureFlag
+	if err := s.db.Find(&stored).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch feature flags: %w", err)
+	}
+	byName := make(map[string]models.FeatureFlag, len(stored))
+	for _, flag := range stored {
+		byName[flag.Name] = flag
+	}
+
+	states := make([]models.FeatureFlagState, 0, len(featureFlags))
+	for _, name := range featureFlagNames() {
+		flag, ok := byName[name]
+		if !ok {
+			flag = models.FeatureFlag{Name: name, Enabled: featureFlags[name].enabled}
+		}
+		states = append(states, s.state(flag))
+	}
+	return states, nil
+}
+
+// Set stores who a feature is rolled out to
+func (s *FeatureFlagService) Set(name string, req models.FeatureFlagRequest) (*models.FeatureFlagState, error) {
+	if _, ok := featureFlags[name]; !ok {
+		return nil, ErrUnknownFeatureFlag
+	}
+
+	flag := models.FeatureFlag{Name: name, Enabled: req.Enabled, UserIDList: formatUserIDs(req.UserIDs)}
+	err := s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"enabled", "user_ids", "updated_at"}),
+	}).Create(&flag).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to save feature flag: %w", err)
+	}
+
+	state := s.state(flag)
+	return &state, nil
+}
+
+// Reset deletes the stored setting of a flag, so its default applies again
+func (s *FeatureFlagService) Reset(name string) error {
+	if _, ok := featureFlags[name]; !ok {
+		return ErrUnknownFeatureFlag
+	}
+	if err := s.db.Where("name = ?", name).Delete(&models.FeatureFlag{}).Error; err != nil {
+		return fmt.Errorf("failed to reset feature flag: %w", err)
+	}
+	return nil
+}
+
+// state combines a stored or default flag with its description and override
+func (s *FeatureFlagService) state(flag models.FeatureFlag) models.FeatureFlagState {
+	state := models.FeatureFlagState{
+		Name:        flag.Name,
+		Description: featureFlags[flag.Name].description,
+		Default:     featureFlags[flag.Name].enabled,
+		Enabled:     flag.Enabled,
+		UserIDs:     parseUserIDs(flag.UserIDList),
+	}
+	if enabled, ok := s.overrides[flag.Name]; ok {
+		state.Override = &enabled
+	}
+	return state
+}
+
+func featureFlagNames() []string {
+	names := make([]string, 0, len(featureFlags))
+	for name := range featureFlags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// formatUserIDs stores user IDs newline separated, sorted and without duplicates
+func formatUserIDs(userIDs []uint) string {
+	seen := make(map[uint]bool, len(userIDs))
+	lines := make([]string, 0, len(userIDs))
+	sorted := append([]uint(nil), userIDs...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	for _, id := range sorted {
+		if id == 0 || seen[id] {
+			continue
+		}
+		seen[id] = true
+		lines = append(lines, strconv.FormatUint(uint64(id), 10))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func parseUserIDs(list string) []uint {
+	userIDs := []uint{}
+	for _, line := range strings.Split(list, "\n") {
+		if id, err := strconv.ParseUint(strings.TrimSpace(line), 10, 32); err == nil {
+			userIDs = append(userIDs, uint(id))
+		}
+	}
+	return userIDs
+}
+
+func containsUserID(userIDs []uint, userID uint) bool {
+	for _, id := range userIDs {
+		if userID != 0 && id == userID {
+			return true
+		}
+	}
+	return false
+}
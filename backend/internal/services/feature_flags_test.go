@@ -0,0 +1,102 @@
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"web-crawler-backend/internal/models"
+)
+
+func setupFeatureFlagTestDB(t *testing.T) *gorm.DB {
+	db := setupURLTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.FeatureFlag{}))
+	return db
+}
+
+func TestFeatureFlagService(t *testing.T) {
+	db := setupFeatureFlagTestDB(t)
+	service := NewFeatureFlagService(db, nil)
+
+	t.Run("flags without a setting use their default", func(t *testing.T) {
+		assert.True(t, service.Enabled(models.FeatureDeepCrawl, 1))
+		assert.True(t, (*FeatureFlagService)(nil).Enabled(models.FeatureDeepCrawl, 1))
+		assert.False(t, service.Enabled("unknown", 1))
+	})
+
+	t.Run("a flag is rolled out to chosen users", func(t *testing.T) {
+		state, err := service.Set(models.FeatureDeepCrawl, models.FeatureFlagRequest{UserIDs: []uint{3, 2, 3}})
+		require.NoError(t, err)
+		assert.Equal(t, []uint{2, 3}, state.UserIDs)
+		assert.True(t, state.Default)
+
+		assert.True(t, service.Enabled(models.FeatureDeepCrawl, 2))
+		assert.False(t, service.Enabled(models.FeatureDeepCrawl, 1))
+		assert.False(t, service.Enabled(models.FeatureDeepCrawl, 0))
+		assert.Empty(t, service.EnabledFor(1))
+		assert.Equal(t, []string{models.FeatureDeepCrawl}, service.EnabledFor(3))
+
+		_, err = service.Set(models.FeatureDeepCrawl, models.FeatureFlagRequest{Enabled: true})
+		require.NoError(t, err)
+		assert.True(t, service.Enabled(models.FeatureDeepCrawl, 1), "a second setting replaces the first")
+	})
+
+	t.Run("environment overrides win", func(t *testing.T) {
+		overridden := NewFeatureFlagService(db, map[string]bool{models.FeatureDeepCrawl: false, "unknown": true})
+		assert.False(t, overridden.Enabled(models.FeatureDeepCrawl, 1))
+
+		states, err := overridden.List()
+		require.NoError(t, err)
+		require.Len(t, states, 1)
+		assert.True(t, states[0].Enabled)
+		require.NotNil(t, states[0].Override)
+		assert.False(t, *states[0].Override)
+	})
+
+	t.Run("a reset flag uses its default again", func(t *testing.T) {
+		_, err := service.Set(models.FeatureDeepCrawl, models.FeatureFlagRequest{})
+		require.NoError(t, err)
+		assert.False(t, service.Enabled(models.FeatureDeepCrawl, 1))
+		require.NoError(t, service.Reset(models.FeatureDeepCrawl))
+		assert.True(t, service.Enabled(models.FeatureDeepCrawl, 1))
+	})
+
+	t.Run("unknown flags can't be set", func(t *testing.T) {
+		_, err := service.Set("unknown", models.FeatureFlagRequest{Enabled: true})
+		assert.ErrorIs(t, err, ErrUnknownFeatureFlag)
+		assert.ErrorIs(t, service.Reset("unknown"), ErrUnknownFeatureFlag)
+	})
+}
+
+func TestCrawlerService_DeepCrawlFeatureFlag(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head><title>Page</title></head><body><a href="/about">About</a></body></html>`))
+	}))
+	defer server.Close()
+
+	db := setupCrawlerTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.FeatureFlag{}))
+	service := NewCrawlerService(db).WithFeatureFlags(NewFeatureFlagService(db, map[string]bool{models.FeatureDeepCrawl: false}))
+
+	urlRecord := &models.URL{URL: server.URL + "/", Status: "pending", MaxDepth: 2}
+	require.NoError(t, db.Create(urlRecord).Error)
+	service.StartCrawl(urlRecord.ID)
+
+	var crawl models.Crawl
+	require.NoError(t, db.Where("url_id = ?", urlRecord.ID).First(&crawl).Error)
+	assert.Equal(t, "completed", crawl.Status)
+	assert.Equal(t, 1, crawl.PagesCrawled, "only the root page is crawled")
+
+	var url models.URL
+	require.NoError(t, db.First(&url, urlRecord.ID).Error)
+	assert.Equal(t, 2, url.MaxDepth, "the URL keeps its setting")
+
+	var events int64
+	require.NoError(t, db.Model(&models.CrawlEvent{}).Where("crawl_id = ? AND type = ?", crawl.ID, models.CrawlEventFeatureDisabled).Count(&events).Error)
+	assert.Equal(t, int64(1), events)
+}
@@ -0,0 +1,234 @@
+package services
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/html"
+
+	"web-crawler-backend/internal/models"
+)
+
+// searchFieldNames are input names conventionally used for a search query
+var searchFieldNames = []string{"q", "s", "query", "search", "keyword", "keywords", "term", "search_query"}
+
+// checkoutKeywords are words in a form's action, id, name or class that suggest a payment step
+var checkoutKeywords = []string{"checkout", "payment", "billing", "purchase"}
+
+// cardFieldKeywords are words in a field's name, id or autocomplete token that suggest card details
+var cardFieldKeywords = []string{"cc-number", "cc-csc", "cc-exp", "card-number", "cardnumber", "card_number", "cvv", "cvc"}
+
+// newsletterKeywords suggest a mailing list signup in a form's attributes or submit label
+var newsletterKeywords = []string{"newsletter", "subscribe", "mailing", "mailchimp"}
+
+// contactKeywords suggest a contact form in a form's action, id, name or class
+var contactKeywords = []string{"contact", "enquiry", "inquiry", "feedback"}
+
+// formFields summarizes the fields of a form
+type formFields struct {
+	inputs      int // Fields the user fills in
+	types       map[string]bool
+	names       []string // Lowercase names, ids and autocomplete tokens of the fields
+	textareas   int
+	submitTexts []string
+}
+
+// processForm checks the form for a login form and records it with its type
+func (s *CrawlerService) processForm(n *html.Node, data *CrawlData, baseURL *url.URL) {
+	s.checkLoginForm(n, data)
+	data.Forms = append(data.Forms, s.classifyForm(n, baseURL))
+}
+
+// classifyForm describes a form and guesses its purpose from its fields,
+// attributes and submit labels. Login forms take precedence, then checkout,
+// search, newsletter and contact forms.
+func (s *CrawlerService) classifyForm(n *html.Node, baseURL *url.URL) models.Form {
+	form := models.Form{
+		Type:   models.FormTypeOther,
+		Method: strings.ToUpper(strings.TrimSpace(getAttr(n, "method"))),
+		Action: strings.TrimSpace(getAttr(n, "action")),
+	}
+	if form.Method == "" {
+		form.Method = "GET"
+	}
+	switch {
+	case form.Action != "":
+		form.Action = resolveHref(baseURL, form.Action)
+	case baseURL != nil:
+		form.Action = baseURL.String()
+	}
+
+	fields := collectFormFields(n)
+	form.InputCount = fields.inputs
+
+	var evidence []string
+	add := func(reasons ...string) {
+		for _, reason := range reasons {
+			if reason != "" {
+				evidence = append(evidence, reason)
+			}
+		}
+	}
+
+	if score, loginEvidence := s.scoreLoginForm(n); score >= loginFormThreshold {
+		form.Type = models.FormTypeLogin
+		add(loginEvidence...)
+	} else if cardField, keyword := fields.containsName(cardFieldKeywords), formAttrKeyword(n, checkoutKeywords); cardField != "" || keyword != "" {
+		form.Type = models.FormTypeCheckout
+		if cardField != "" {
+			add(fmt.Sprintf("card field %q", cardField))
+		}
+		add(keyword)
+	} else if reasons := searchEvidence(n, fields); len(reasons) > 0 {
+		form.Type = models.FormTypeSearch
+		add(reasons...)
+	} else if reasons := newsletterEvidence(n, fields); len(reasons) > 0 {
+		form.Type = models.FormTypeNewsletter
+		add(reasons...)
+	} else if reasons := contactEvidence(n, fields); len(reasons) > 0 {
+		form.Type = models.FormTypeContact
+		add(reasons...)
+	}
+
+	form.Evidence = strings.Join(evidence, ", ")
+	return form
+}
+
+// formAttrKeyword describes the first keyword found in the form's action, id, name or class, or returns ""
+func formAttrKeyword(form *html.Node, keywords []string) string {
+	for _, key := range []string{"action", "id", "name", "class"} {
+		if keyword := containsAny(strings.ToLower(getAttr(form, key)), keywords); keyword != "" {
+			return fmt.Sprintf("form %s contains %q", key, keyword)
+		}
+	}
+	return ""
+}
+
+// collectFormFields counts the fields of a form and collects their names and submit labels
+func collectFormFields(form *html.Node) formFields {
+	fields := formFields{types: map[string]bool{}}
+	walkNodes(form, func(n *html.Node) {
+		if n.Type != html.ElementNode {
+			return
+		}
+
+		switch n.Data {
+		case "input":
+			inputType := strings.ToLower(strings.TrimSpace(getAttr(n, "type")))
+			if inputType == "" {
+				inputType = "text"
+			}
+			switch inputType {
+			case "hidden", "reset":
+				return
+			case "submit", "button", "image":
+				label := getAttr(n, "value")
+				if inputType == "image" {
+					label = getAttr(n, "alt")
+				}
+				fields.submitTexts = append(fields.submitTexts, strings.ToLower(strings.TrimSpace(label)))
+				return
+			}
+			fields.inputs++
+			fields.types[inputType] = true
+		case "textarea":
+			fields.inputs++
+			fields.textareas++
+		case "select":
+			fields.inputs++
+		case "button":
+			if strings.ToLower(getAttr(n, "type")) != "reset" {
+				fields.submitTexts = append(fields.submitTexts, strings.ToLower(textContent(n)))
+			}
+			return
+		default:
+			return
+		}
+
+		for _, key := range []string{"name", "id", "autocomplete"} {
+			if value := strings.ToLower(strings.TrimSpace(getAttr(n, key))); value != "" {
+				fields.names = append(fields.names, value)
+			}
+		}
+	})
+	return fields
+}
+
+// containsName returns the first field name, id or autocomplete token containing one of the keywords
+func (f formFields) containsName(keywords []string) string {
+	for _, name := range f.names {
+		if containsAny(name, keywords) != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// hasEmailField reports whether the form asks for an email address
+func (f formFields) hasEmailField() bool {
+	return f.types["email"] || f.containsName([]string{"email", "e-mail"}) != ""
+}
+
+// searchEvidence returns why a form looks like a search form, if it does
+func searchEvidence(n *html.Node, fields formFields) []string {
+	var reasons []string
+	for p := n; p != nil; p = p.Parent {
+		if p.Type == html.ElementNode && (p.Data == "search" || strings.EqualFold(getAttr(p, "role"), "search")) {
+			reasons = append(reasons, "search landmark")
+			break
+		}
+	}
+	if fields.types["search"] {
+		reasons = append(reasons, "search input")
+	}
+	for _, name := range fields.names {
+		if slices.Contains(searchFieldNames, name) {
+			reasons = append(reasons, fmt.Sprintf("input named %q", name))
+			break
+		}
+	}
+	// A search action alone isn't enough: filters often submit to search pages
+	if len(reasons) > 0 || fields.inputs == 1 {
+		if keyword := formAttrKeyword(n, []string{"search"}); keyword != "" {
+			reasons = append(reasons, keyword)
+		}
+	}
+	return reasons
+}
+
+// newsletterEvidence returns why a form looks like a newsletter signup, if it
+// does. Signups ask for an email address and little else.
+func newsletterEvidence(n *html.Node, fields formFields) []string {
+	if !fields.hasEmailField() || fields.textareas > 0 {
+		return nil
+	}
+
+	var reasons []string
+	if keyword := formAttrKeyword(n, newsletterKeywords); keyword != "" {
+		reasons = append(reasons, keyword)
+	}
+	for _, text := range fields.submitTexts {
+		if containsAny(text, newsletterKeywords) != "" {
+			reasons = append(reasons, fmt.Sprintf("submit text %q", text))
+			break
+		}
+	}
+	if len(reasons) == 0 && fields.inputs == 1 {
+		reasons = append(reasons, "single email input")
+	}
+	return reasons
+}
+
+// contactEvidence returns why a form looks like a contact form, if it does
+func contactEvidence(n *html.Node, fields formFields) []string {
+	var reasons []string
+	if keyword := formAttrKeyword(n, contactKeywords); keyword != "" {
+		reasons = append(reasons, keyword)
+	}
+	if fields.textareas > 0 && (fields.hasEmailField() || fields.types["tel"]) {
+		reasons = append(reasons, "message field with contact details")
+	}
+	return reasons
+}
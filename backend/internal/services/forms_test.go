@@ -0,0 +1,96 @@
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/html"
+
+	"web-crawler-backend/internal/models"
+)
+
+func TestCrawlerService_classifyForm(t *testing.T) {
+	service := NewCrawlerService(setupCrawlerTestDB(t))
+	baseURL, _ := url.Parse("https://example.com/shop/")
+
+	for _, tc := range []struct {
+		name     string
+		html     string
+		formType string
+		evidence string
+	}{
+		{"login", `<form action="/login" method="post"><input name="user"><input type="password" name="pass"></form>`, models.FormTypeLogin, `form action contains "login", password input`},
+		{"checkout", `<form action="/pay"><input name="name"><input autocomplete="cc-number"><input name="cvc"></form>`, models.FormTypeCheckout, `card field "cc-number"`},
+		{"search landmark", `<div role="search"><form action="/find"><input type="search" name="q"></form></div>`, models.FormTypeSearch, `search landmark, search input, input named "q"`},
+		{"search action with one field", `<form action="/search"><input name="term_text"></form>`, models.FormTypeSearch, `form action contains "search"`},
+		{"newsletter", `<form action="/list"><input type="email" name="email"><input name="first_name"><button>Subscribe now</button></form>`, models.FormTypeNewsletter, `submit text "subscribe now"`},
+		{"single email input", `<form action="/list"><input type="email" name="address"></form>`, models.FormTypeNewsletter, "single email input"},
+		{"contact", `<form method="post"><input name="name"><input type="email" name="email"><textarea name="message"></textarea></form>`, models.FormTypeContact, "message field with contact details"},
+		{"other", `<form action="/cart"><select name="size"></select><input type="number" name="qty"></form>`, models.FormTypeOther, ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			doc, err := html.Parse(strings.NewReader(tc.html))
+			require.NoError(t, err)
+
+			data := &CrawlData{}
+			service.pipeline(nil).traverse(doc, &PageContext{BaseURL: baseURL, Data: data})
+			require.Len(t, data.Forms, 1)
+			assert.Equal(t, tc.formType, data.Forms[0].Type)
+			assert.Equal(t, tc.evidence, data.Forms[0].Evidence)
+		})
+	}
+
+	t.Run("method, action and inputs", func(t *testing.T) {
+		doc, err := html.Parse(strings.NewReader(`<form method="post" action="checkout">
+			<input type="hidden" name="token"><input name="street"><select name="country"></select>
+			<textarea name="notes"></textarea><input type="submit" value="Order"></form>
+			<form><input name="coupon"></form>`))
+		require.NoError(t, err)
+
+		data := &CrawlData{}
+		service.pipeline(nil).traverse(doc, &PageContext{BaseURL: baseURL, Data: data})
+		require.Len(t, data.Forms, 2)
+		assert.Equal(t, "POST", data.Forms[0].Method)
+		assert.Equal(t, "https://example.com/shop/checkout", data.Forms[0].Action)
+		assert.Equal(t, 3, data.Forms[0].InputCount)
+		assert.Equal(t, "GET", data.Forms[1].Method)
+		assert.Equal(t, "https://example.com/shop/", data.Forms[1].Action, "forms without an action submit to the page")
+	})
+}
+
+func TestURLService_GetURLForms(t *testing.T) {
+	db := setupCrawlerTestDB(t)
+	crawler := NewCrawlerService(db)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><body>
+			<form role="search"><input type="search" name="q"></form>
+			<form id="login"><input type="password" name="pass"></form>
+		</body></html>`))
+	}))
+	defer server.Close()
+
+	urlRecord := &models.URL{URL: server.URL, Status: "pending"}
+	require.NoError(t, db.Create(urlRecord).Error)
+	crawler.StartCrawl(urlRecord.ID)
+
+	service := NewURLService(db, crawler)
+	forms, total, err := service.GetURLForms(urlRecord.ID, "", 50, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Equal(t, models.FormTypeSearch, forms[0].Type)
+
+	forms, total, err = service.GetURLForms(urlRecord.ID, models.FormTypeLogin, 50, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, urlRecord.ID, forms[0].URLID)
+
+	_, _, err = service.GetURLForms(urlRecord.ID+1, "", 50, 0)
+	assert.ErrorIs(t, err, ErrURLNotFound)
+}
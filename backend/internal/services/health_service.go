@@ -0,0 +1,216 @@
+package services
+
+import (
+	"context"
+	"os"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"web-crawler-backend/internal/models"
+)
+
+const (
+	// healthCheckTimeout bounds each dependency check of a readiness probe
+	healthCheckTimeout = 2 * time.Second
+	// missedHeartbeats is how many ticks a worker may miss before it counts as stalled
+	missedHeartbeats = 3
+	// migrationsTable is where golang-migrate records the schema version
+	migrationsTable = "schema_migrations"
+)
+
+// Component and overall health states
+const (
+	HealthOK       = "ok"
+	HealthDegraded = "degraded"
+)
+
+// migrationFilePattern matches golang-migrate up files, e.g. 000001_create_users.up.sql
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_.+\.up\.sql$`)
+
+// Heartbeat tracks when a background worker last completed a tick
+type Heartbeat struct {
+	mu       sync.Mutex
+	interval time.Duration
+	last     time.Time
+}
+
+// start records the tick interval and counts the start as the first beat
+func (h *Heartbeat) start(interval time.Duration, now time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.interval = interval
+	h.last = now
+}
+
+func (h *Heartbeat) beat(now time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.last = now
+}
+
+// check reports the last beat, and false if the worker never started or stopped beating
+func (h *Heartbeat) check(now time.Time) (time.Time, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.last.IsZero() {
+		return h.last, false
+	}
+	return h.last, now.Sub(h.last) <= missedHeartbeats*h.interval
+}
+
+// ComponentHealth is the state of one dependency of the service
+type ComponentHealth struct {
+	Status  string                 `json:"status"`
+	Message string                 `json:"message,omitempty"`
+	Details map[string]interface{} `json:"details,omitempty"`
+}
+
+// HealthReport is the result of a readiness check
+type HealthReport struct {
+	Status     string                     `json:"status"`
+	Components map[string]ComponentHealth `json:"components"`
+}
+
+// HealthService checks whether the service can handle traffic: the database
+// answers, the schema is fully migrated and the crawl workers are running
+type HealthService struct {
+	db              *gorm.DB
+	latestMigration uint
+	workers         map[string]*Heartbeat
+}
+
+// NewHealthService creates a health service. The newest migration in
+// migrationsDir is the version the schema must be at; if the directory can't
+// be read, only the dirty flag of the schema is checked.
+func NewHealthService(db *gorm.DB, migrationsDir string) *HealthService {
+	return &HealthService{
+		db:              db,
+		latestMigration: latestMigrationVersion(migrationsDir),
+		workers:         make(map[string]*Heartbeat),
+	}
+}
+
+// AddWorker registers a background worker whose heartbeat is checked for readiness
+func (s *HealthService) AddWorker(name string, heartbeat *Heartbeat) {
+	s.workers[name] = heartbeat
+}
+
+// Readiness checks every component. The report is degraded if any component is.
+func (s *HealthService) Readiness(ctx context.Context, now time.Time) HealthReport {
+	report := HealthReport{Status: HealthOK, Components: make(map[string]ComponentHealth)}
+
+	report.Components["database"] = s.checkDatabase(ctx)
+	if report.Components["database"].Status == HealthOK {
+		report.Components["migrations"] = s.checkMigrations(ctx)
+		report.Components["crawls"] = s.checkCrawls(ctx)
+	} else {
+		unavailable := ComponentHealth{Status: HealthDegraded, Message: "Database unavailable"}
+		report.Components["migrations"] = unavailable
+		report.Components["crawls"] = unavailable
+	}
+	for name, heartbeat := range s.workers {
+		report.Components[name] = checkWorker(heartbeat, now)
+	}
+
+	for _, component := range report.Components {
+		if component.Status != HealthOK {
+			report.Status = HealthDegraded
+		}
+	}
+	return report
+}
+
+func (s *HealthService) checkDatabase(ctx context.Context) ComponentHealth {
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return ComponentHealth{Status: HealthDegraded, Message: err.Error()}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+	started := time.Now()
+	if err := sqlDB.PingContext(ctx); err != nil {
+		return ComponentHealth{Status: HealthDegraded, Message: err.Error()}
+	}
+
+	stats := sqlDB.Stats()
+	return ComponentHealth{Status: HealthOK, Details: map[string]interface{}{
+		"latency_ms":       time.Since(started).Milliseconds(),
+		"open_connections": stats.OpenConnections,
+		"in_use":           stats.InUse,
+	}}
+}
+
+// checkMigrations reads the version golang-migrate recorded. Databases set up
+// with AutoMigrate have no version table and are reported as unmanaged.
+func (s *HealthService) checkMigrations(ctx context.Context) ComponentHealth {
+	db := s.db.WithContext(ctx)
+	if !db.Migrator().HasTable(migrationsTable) {
+		return ComponentHealth{Status: HealthOK, Message: "Schema managed by AutoMigrate"}
+	}
+
+	var state struct {
+		Version uint
+		Dirty   bool
+	}
+	if err := db.Table(migrationsTable).Select("version, dirty").Limit(1).Scan(&state).Error; err != nil {
+		return ComponentHealth{Status: HealthDegraded, Message: err.Error()}
+	}
+
+	details := map[string]interface{}{"version": state.Version, "dirty": state.Dirty}
+	if s.latestMigration > 0 {
+		details["latest"] = s.latestMigration
+	}
+	switch {
+	case state.Dirty:
+		return ComponentHealth{Status: HealthDegraded, Message: "Last migration failed and left the schema dirty", Details: details}
+	case state.Version < s.latestMigration:
+		return ComponentHealth{Status: HealthDegraded, Message: "Migrations pending", Details: details}
+	}
+	return ComponentHealth{Status: HealthOK, Details: details}
+}
+
+func (s *HealthService) checkCrawls(ctx context.Context) ComponentHealth {
+	var running int64
+	if err := s.db.WithContext(ctx).Model(&models.Crawl{}).Where("status = ?", "running").Count(&running).Error; err != nil {
+		return ComponentHealth{Status: HealthDegraded, Message: err.Error()}
+	}
+	return ComponentHealth{Status: HealthOK, Details: map[string]interface{}{"running": running}}
+}
+
+func checkWorker(heartbeat *Heartbeat, now time.Time) ComponentHealth {
+	last, alive := heartbeat.check(now)
+	if last.IsZero() {
+		return ComponentHealth{Status: HealthDegraded, Message: "Worker not started"}
+	}
+
+	details := map[string]interface{}{"last_heartbeat": last}
+	if !alive {
+		return ComponentHealth{Status: HealthDegraded, Message: "Worker stopped responding", Details: details}
+	}
+	return ComponentHealth{Status: HealthOK, Details: details}
+}
+
+// latestMigrationVersion returns the highest version among the up migrations in dir, or 0
+func latestMigrationVersion(dir string) uint {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0
+	}
+
+	var latest uint64
+	for _, entry := range entries {
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+		if version, err := strconv.ParseUint(match[1], 10, 32); err == nil && version > latest {
+			latest = version
+		}
+	}
+	return uint(latest)
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"web-crawler-backend/internal/models"
+)
+
+func TestHealthService_Readiness(t *testing.T) {
+	now := time.Now()
+
+	t.Run("healthy", func(t *testing.T) {
+		db := setupURLTestDB(t)
+		url := &models.URL{URL: "https://example.com", Status: "running"}
+		require.NoError(t, db.Create(url).Error)
+		require.NoError(t, db.Create(&models.Crawl{URLID: url.ID, Status: "running"}).Error)
+
+		var scheduler Heartbeat
+		scheduler.start(time.Minute, now.Add(-90*time.Second))
+		service := NewHealthService(db, t.TempDir())
+		service.AddWorker("scheduler", &scheduler)
+
+		report := service.Readiness(context.Background(), now)
+		assert.Equal(t, HealthOK, report.Status)
+		assert.Equal(t, HealthOK, report.Components["database"].Status)
+		assert.Equal(t, "Schema managed by AutoMigrate", report.Components["migrations"].Message)
+		assert.EqualValues(t, 1, report.Components["crawls"].Details["running"])
+		assert.Equal(t, HealthOK, report.Components["scheduler"].Status)
+	})
+
+	t.Run("stalled and missing workers", func(t *testing.T) {
+		var stalled, missing Heartbeat
+		stalled.start(time.Minute, now.Add(-10*time.Minute))
+		service := NewHealthService(setupURLTestDB(t), t.TempDir())
+		service.AddWorker("scheduler", &stalled)
+		service.AddWorker("watchdog", &missing)
+
+		report := service.Readiness(context.Background(), now)
+		assert.Equal(t, HealthDegraded, report.Status)
+		assert.Equal(t, "Worker stopped responding", report.Components["scheduler"].Message)
+		assert.Equal(t, "Worker not started", report.Components["watchdog"].Message)
+	})
+
+	t.Run("pending and dirty migrations", func(t *testing.T) {
+		db := setupURLTestDB(t)
+		require.NoError(t, db.Exec("CREATE TABLE schema_migrations (version bigint NOT NULL PRIMARY KEY, dirty boolean NOT NULL)").Error)
+		require.NoError(t, db.Exec("INSERT INTO schema_migrations (version, dirty) VALUES (2, false)").Error)
+
+		dir := t.TempDir()
+		for _, name := range []string{"000001_init.up.sql", "000002_more.up.sql", "000003_latest.up.sql", "000003_latest.down.sql"} {
+			require.NoError(t, os.WriteFile(filepath.Join(dir, name), nil, 0o644))
+		}
+		service := NewHealthService(db, dir)
+
+		report := service.Readiness(context.Background(), now)
+		assert.Equal(t, HealthDegraded, report.Status)
+		assert.Equal(t, "Migrations pending", report.Components["migrations"].Message)
+		assert.EqualValues(t, 3, report.Components["migrations"].Details["latest"])
+
+		require.NoError(t, db.Exec("UPDATE schema_migrations SET version = 3, dirty = true").Error)
+		report = service.Readiness(context.Background(), now)
+		assert.Equal(t, "Last migration failed and left the schema dirty", report.Components["migrations"].Message)
+
+		require.NoError(t, db.Exec("UPDATE schema_migrations SET dirty = false").Error)
+		report = service.Readiness(context.Background(), now)
+		assert.Equal(t, HealthOK, report.Status)
+	})
+
+	t.Run("database down", func(t *testing.T) {
+		db := setupURLTestDB(t)
+		sqlDB, err := db.DB()
+		require.NoError(t, err)
+		require.NoError(t, sqlDB.Close())
+
+		report := NewHealthService(db, t.TempDir()).Readiness(context.Background(), now)
+		assert.Equal(t, HealthDegraded, report.Status)
+		assert.Equal(t, HealthDegraded, report.Components["database"].Status)
+		assert.Equal(t, "Database unavailable", report.Components["migrations"].Message)
+	})
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"net/url"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/html"
+
+	"web-crawler-backend/internal/models"
+)
+
+// iconKind returns the kind of icon a <link> element declares, or "" if it isn't one
+func iconKind(n *html.Node) string {
+	switch {
+	case hasRel(n, "apple-touch-icon"), hasRel(n, "apple-touch-icon-precomposed"):
+		return models.IconAppleTouchIcon
+	case hasRel(n, "icon"):
+		return models.IconFavicon
+	case hasRel(n, "manifest"):
+		return models.IconManifest
+	}
+	return ""
+}
+
+// processIcon records a favicon, apple-touch icon or manifest link. Icons
+// count as accessible until the availability check says otherwise.
+func (s *CrawlerService) processIcon(n *html.Node, data *CrawlData, baseURL *url.URL) {
+	kind := iconKind(n)
+	href := strings.TrimSpace(getAttr(n, "href"))
+	if kind == "" || href == "" {
+		return
+	}
+
+	icon := models.PageIcon{
+		Kind:         kind,
+		URL:          href,
+		Sizes:        strings.TrimSpace(getAttr(n, "sizes")),
+		Type:         strings.TrimSpace(getAttr(n, "type")),
+		IsAccessible: true,
+	}
+	if !strings.HasPrefix(href, "data:") {
+		icon.URL = resolveHref(baseURL, href)
+	}
+	data.Meta.Icons = append(data.Meta.Icons, icon)
+}
+
+// addImplicitFavicon adds /favicon.ico of the page's origin, which browsers
+// request when a page links no favicon
+func addImplicitFavicon(data *CrawlData, baseURL *url.URL) {
+	for _, icon := range data.Meta.Icons {
+		if icon.Kind == models.IconFavicon {
+			return
+		}
+	}
+	if baseURL.Scheme != "http" && baseURL.Scheme != "https" {
+		return
+	}
+
+	data.Meta.Icons = append(data.Meta.Icons, models.PageIcon{
+		Kind:         models.IconFavicon,
+		URL:          (&url.URL{Scheme: baseURL.Scheme, Host: baseURL.Host, Path: "/favicon.ico"}).String(),
+		Implicit:     true,
+		IsAccessible: true,
+	})
+}
+
+// checkIconAvailability requests every icon and manifest of the page with a HEAD request
+func (s *CrawlerService) checkIconAvailability(data *CrawlData, throttle *HostThrottle) {
+	var wg sync.WaitGroup
+	for i := range data.Meta.Icons {
+		icon := &data.Meta.Icons[i]
+		if strings.HasPrefix(icon.URL, "data:") {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			icon.StatusCode = headStatus(s.traceContext(), linkCheckClient, icon.URL, throttle)
+			icon.IsAccessible = icon.StatusCode > 0 && icon.StatusCode < 400
+		}()
+	}
+	wg.Wait()
+}
+
+// reuseIconChecks copies the availability of icons checked by an earlier
+// extraction of the same page
+func reuseIconChecks(icons []models.PageIcon, checked []models.PageIcon) {
+	statuses := make(map[string]models.PageIcon, len(checked))
+	for _, icon := range checked {
+		statuses[icon.URL] = icon
+	}
+	for i := range icons {
+		if previous, ok := statuses[icons[i].URL]; ok {
+			icons[i].StatusCode = previous.StatusCode
+			icons[i].IsAccessible = previous.IsAccessible
+		}
+	}
+}
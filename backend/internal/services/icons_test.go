@@ -0,0 +1,76 @@
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/html"
+
+	"web-crawler-backend/internal/models"
+)
+
+func TestCrawlerService_extractIcons(t *testing.T) {
+	t.Run("collects favicons, touch icons and manifests", func(t *testing.T) {
+		service := NewCrawlerService(setupCrawlerTestDB(t))
+		doc, err := html.Parse(strings.NewReader(`<html><head>
+			<link rel="shortcut icon" href="/favicon.ico">
+			<link rel="icon" type="image/png" sizes="32x32" href="data:image/png;base64,iVBORw0KGgo=">
+			<link rel="apple-touch-icon" sizes="180x180" href="/apple-touch-icon.png">
+			<link rel="manifest" href="site.webmanifest">
+			<link rel="stylesheet" href="/style.css">
+		</head><body></body></html>`))
+		require.NoError(t, err)
+
+		data, ok := service.parsePageData(doc, &models.URL{URL: "https://example.com/docs/"})
+		require.True(t, ok)
+		assert.Equal(t, []models.PageIcon{
+			{Kind: models.IconFavicon, URL: "https://example.com/favicon.ico", IsAccessible: true},
+			{Kind: models.IconFavicon, URL: "data:image/png;base64,iVBORw0KGgo=", Sizes: "32x32", Type: "image/png", IsAccessible: true},
+			{Kind: models.IconAppleTouchIcon, URL: "https://example.com/apple-touch-icon.png", Sizes: "180x180", IsAccessible: true},
+			{Kind: models.IconManifest, URL: "https://example.com/docs/site.webmanifest", IsAccessible: true},
+		}, data.Meta.Icons)
+	})
+
+	t.Run("falls back to /favicon.ico and checks availability", func(t *testing.T) {
+		db := setupCrawlerTestDB(t)
+		service := NewCrawlerService(db)
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/blog/post":
+				w.Header().Set("Content-Type", "text/html")
+				w.Write([]byte(`<html><head><link rel="manifest" href="/manifest.json"></head><body></body></html>`))
+			case "/manifest.json":
+				w.WriteHeader(http.StatusOK)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		defer server.Close()
+
+		urlRecord := &models.URL{URL: server.URL + "/blog/post", Status: "pending"}
+		require.NoError(t, db.Create(urlRecord).Error)
+		service.StartCrawl(urlRecord.ID)
+
+		var crawl models.Crawl
+		require.NoError(t, db.Preload("PageMeta").Where("url_id = ?", urlRecord.ID).First(&crawl).Error)
+		require.NotNil(t, crawl.PageMeta)
+		require.Len(t, crawl.PageMeta.Icons, 2)
+
+		manifest := crawl.PageMeta.Icons[0]
+		assert.Equal(t, models.IconManifest, manifest.Kind)
+		assert.True(t, manifest.IsAccessible)
+		assert.Equal(t, http.StatusOK, manifest.StatusCode)
+
+		favicon := crawl.PageMeta.Icons[1]
+		assert.Equal(t, server.URL+"/favicon.ico", favicon.URL)
+		assert.True(t, favicon.Implicit)
+		assert.False(t, favicon.IsAccessible)
+		assert.Equal(t, http.StatusNotFound, favicon.StatusCode)
+		assert.Contains(t, crawl.SEOChecks, `"key":"favicon","title":"Favicon","passed":false`)
+	})
+}
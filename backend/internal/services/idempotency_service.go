@@ -0,0 +1,147 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+
+	"web-crawler-backend/internal/models"
+)
+
+// defaultIdempotencyTTL is how long stored responses are replayed when no TTL is configured
+const defaultIdempotencyTTL = 24 * time.Hour
+
+var (
+	// ErrIdempotencyKeyInUse is returned while the first request with a key is still in progress
+	ErrIdempotencyKeyInUse = errors.New("a request with this idempotency key is still in progress")
+	// ErrIdempotencyKeyReused is returned when a key is sent again with a different request body
+	ErrIdempotencyKeyReused = errors.New("idempotency key was already used for a different request")
+)
+
+// IdempotencyRequest identifies a write request sent with an Idempotency-Key header
+type IdempotencyRequest struct {
+	UserID      uint
+	Key         string
+	Method      string
+	Path        string
+	RequestHash string
+}
+
+// IdempotencyService stores the responses of write requests by idempotency
+// key until they expire
+type IdempotencyService struct {
+	db  *gorm.DB
+	ttl time.Duration
+}
+
+// NewIdempotencyService creates the service; a zero ttl keeps responses for 24 hours
+func NewIdempotencyService(db *gorm.DB, ttl time.Duration) *IdempotencyService {
+	if ttl <= 0 {
+		ttl = defaultIdempotencyTTL
+	}
+	return &IdempotencyService{db: db, ttl: ttl}
+}
+
+// Begin claims the key of a request. The first request gets a record that is
+// not yet completed and must be finished with Complete or Release; retries of
+// it get the completed record to replay.
+func (s *IdempotencyService) Begin(req IdempotencyRequest, now time.Time) (*models.IdempotencyKey, error) {
+	record := &models.IdempotencyKey{
+		UserID:      req.UserID,
+		Key:         req.Key,
+		Method:      req.Method,
+		Path:        req.Path,
+		RequestHash: req.RequestHash,
+		ExpiresAt:   now.Add(s.ttl),
+	}
+
+	err := s.db.Create(record).Error
+	if err == nil {
+		return record, nil
+	}
+	if !isDuplicateKeyError(err) {
+		return nil, fmt.Errorf("failed to store idempotency key: %w", err)
+	}
+
+	var existing models.IdempotencyKey
+	if err := s.db.Where(map[string]interface{}{
+		"user_id": req.UserID,
+		"key":     req.Key,
+		"method":  req.Method,
+		"path":    req.Path,
+	}).First(&existing).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch idempotency key: %w", err)
+	}
+
+	// An expired key is free to be used again
+	if !existing.ExpiresAt.After(now) {
+		if err := s.db.Delete(&existing).Error; err != nil {
+			return nil, fmt.Errorf("failed to delete expired idempotency key: %w", err)
+		}
+		return s.Begin(req, now)
+	}
+
+	if existing.RequestHash != req.RequestHash {
+		return nil, ErrIdempotencyKeyReused
+	}
+	if !existing.Completed() {
+		return nil, ErrIdempotencyKeyInUse
+	}
+	return &existing, nil
+}
+
+// Complete stores the response of the first request with a key, to be replayed to its retries
+func (s *IdempotencyService) Complete(record *models.IdempotencyKey, statusCode int, contentType string, body []byte) error {
+	err := s.db.Model(record).Updates(map[string]interface{}{
+		"status_code":   statusCode,
+		"content_type":  contentType,
+		"response_body": body,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to store idempotent response: %w", err)
+	}
+	return nil
+}
+
+// Release frees the key of a request that failed without a response worth
+// replaying, so a retry runs the request again
+func (s *IdempotencyService) Release(record *models.IdempotencyKey) error {
+	if err := s.db.Delete(record).Error; err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
+
+// PurgeExpired deletes the keys that expired before now and returns how many were deleted
+func (s *IdempotencyService) PurgeExpired(now time.Time) (int64, error) {
+	result := s.db.Where("expires_at <= ?", now).Delete(&models.IdempotencyKey{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to purge idempotency keys: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// Start purges expired keys every tick until the returned stop function is called
+func (s *IdempotencyService) Start(tick time.Duration) (stop func()) {
+	ticker := time.NewTicker(tick)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case now := <-ticker.C:
+				if _, err := s.PurgeExpired(now); err != nil {
+					log.Printf("Idempotency key purge failed: %v", err)
+				}
+			case <-done:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+
+	return func() { close(done) }
+}
@@ -0,0 +1,45 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"web-crawler-backend/internal/models"
+)
+
+func TestIdempotencyService_Expiry(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.IdempotencyKey{}))
+
+	service := NewIdempotencyService(db, time.Hour)
+	now := time.Now()
+	req := IdempotencyRequest{UserID: 1, Key: "abc", Method: "POST", Path: "/api/v1/urls", RequestHash: "hash"}
+
+	record, err := service.Begin(req, now)
+	require.NoError(t, err)
+	require.NoError(t, service.Complete(record, 201, "application/json", []byte(`{}`)))
+
+	replay, err := service.Begin(req, now.Add(59*time.Minute))
+	require.NoError(t, err)
+	assert.True(t, replay.Completed())
+	assert.Equal(t, []byte(`{}`), replay.ResponseBody)
+
+	// Once expired, the key starts over, even for a different request
+	req.RequestHash = "other"
+	fresh, err := service.Begin(req, now.Add(61*time.Minute))
+	require.NoError(t, err)
+	assert.False(t, fresh.Completed())
+
+	purged, err := service.PurgeExpired(now.Add(3 * time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+}
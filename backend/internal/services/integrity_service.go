@@ -0,0 +1,270 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"web-crawler-backend/internal/models"
+)
+
+// maxIntegrityDetails caps how many example rows are listed per check
+const maxIntegrityDetails = 20
+
+// crawlChildren are the tables whose rows belong to a single crawl
+var crawlChildren = []interface{}{
+	&models.Link{},
+	&models.PageMeta{},
+	&models.Page{},
+	&models.Image{},
+	&models.Form{},
+	&models.CrawlEvent{},
+	&models.AccessibilityIssue{},
+	&models.MixedContentIssue{},
+	&models.PageLink{},
+}
+
+// IntegrityOptions configures an integrity scan
+type IntegrityOptions struct {
+	// Repair fixes the inconsistencies found instead of only reporting them
+	Repair bool
+	// StuckAfter is how long a crawl may run before it is considered stuck
+	StuckAfter time.Duration
+}
+
+// IntegrityCheck is the outcome of a single consistency check
+type IntegrityCheck struct {
+	Name     string   `json:"name"`
+	Found    int      `json:"found"`
+	Repaired int      `json:"repaired"`
+	Details  []string `json:"details,omitempty"`
+}
+
+// IntegrityReport summarizes an integrity scan
+type IntegrityReport struct {
+	Repair bool             `json:"repair"`
+	Checks []IntegrityCheck `json:"checks"`
+}
+
+// Issues returns the total number of inconsistencies found
+func (r *IntegrityReport) Issues() int {
+	total := 0
+	for _, check := range r.Checks {
+		total += check.Found
+	}
+	return total
+}
+
+type IntegrityService struct {
+	db *gorm.DB
+}
+
+func NewIntegrityService(db *gorm.DB) *IntegrityService {
+	return &IntegrityService{db: db}
+}
+
+// Run scans the database for inconsistencies and optionally repairs them
+func (s *IntegrityService) Run(opts IntegrityOptions) (*IntegrityReport, error) {
+	report := &IntegrityReport{Repair: opts.Repair}
+
+	checks := []func(IntegrityOptions) (IntegrityCheck, error){
+		s.checkOrphanCrawls,
+		s.checkOrphanLinks,
+		s.checkStuckCrawls,
+		s.checkLinkCounters,
+	}
+	for _, check := range checks {
+		result, err := check(opts)
+		if err != nil {
+			return nil, err
+		}
+		report.Checks = append(report.Checks, result)
+	}
+
+	return report, nil
+}
+
+// checkOrphanCrawls finds crawls whose URL row no longer exists. Soft-deleted
+// URLs still count as existing so their history survives a restore.
+func (s *IntegrityService) checkOrphanCrawls(opts IntegrityOptions) (IntegrityCheck, error) {
+	check := IntegrityCheck{Name: "crawls without URLs"}
+
+	var crawlIDs []uint
+	if err := s.db.Model(&models.Crawl{}).
+		Where("NOT EXISTS (SELECT 1 FROM urls WHERE urls.id = crawls.url_id)").
+		Pluck("id", &crawlIDs).Error; err != nil {
+		return check, fmt.Errorf("failed to find orphan crawls: %w", err)
+	}
+
+	check.Found = len(crawlIDs)
+	for _, id := range crawlIDs {
+		check.addDetail(fmt.Sprintf("crawl %d", id))
+	}
+
+	if opts.Repair && len(crawlIDs) > 0 {
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			for _, child := range crawlChildren {
+				if err := tx.Where("crawl_id IN ?", crawlIDs).Delete(child).Error; err != nil {
+					return err
+				}
+			}
+			return tx.Where("id IN ?", crawlIDs).Delete(&models.Crawl{}).Error
+		})
+		if err != nil {
+			return check, fmt.Errorf("failed to delete orphan crawls: %w", err)
+		}
+		check.Repaired = len(crawlIDs)
+	}
+
+	return check, nil
+}
+
+// checkOrphanLinks finds links whose crawl no longer exists
+func (s *IntegrityService) checkOrphanLinks(opts IntegrityOptions) (IntegrityCheck, error) {
+	check := IntegrityCheck{Name: "links without crawls"}
+
+	orphans := s.db.Model(&models.Link{}).Where("NOT EXISTS (SELECT 1 FROM crawls WHERE crawls.id = links.crawl_id)")
+
+	var linkIDs []uint
+	if err := orphans.Pluck("id", &linkIDs).Error; err != nil {
+		return check, fmt.Errorf("failed to find orphan links: %w", err)
+	}
+
+	check.Found = len(linkIDs)
+	for _, id := range linkIDs {
+		check.addDetail(fmt.Sprintf("link %d", id))
+	}
+
+	if opts.Repair && len(linkIDs) > 0 {
+		result := s.db.Where("id IN ?", linkIDs).Delete(&models.Link{})
+		if result.Error != nil {
+			return check, fmt.Errorf("failed to delete orphan links: %w", result.Error)
+		}
+		check.Repaired = int(result.RowsAffected)
+	}
+
+	return check, nil
+}
+
+// checkStuckCrawls finds crawls that have been running for longer than
+// StuckAfter, usually because the server stopped mid-crawl, and URLs left
+// in the running state without a running crawl
+func (s *IntegrityService) checkStuckCrawls(opts IntegrityOptions) (IntegrityCheck, error) {
+	check := IntegrityCheck{Name: "URLs stuck running"}
+	cutoff := time.Now().Add(-opts.StuckAfter)
+
+	var crawls []models.Crawl
+	if err := s.db.Where("status = ? AND (started_at IS NULL OR started_at < ?)", "running", cutoff).Find(&crawls).Error; err != nil {
+		return check, fmt.Errorf("failed to find stuck crawls: %w", err)
+	}
+
+	var urls []models.URL
+	if err := s.db.Where("status = ? AND updated_at < ?", "running", cutoff).
+		Where("NOT EXISTS (SELECT 1 FROM crawls WHERE crawls.url_id = urls.id AND crawls.status = ? AND crawls.started_at >= ?)", "running", cutoff).
+		Find(&urls).Error; err != nil {
+		return check, fmt.Errorf("failed to find stuck URLs: %w", err)
+	}
+
+	check.Found = len(crawls) + len(urls)
+	for _, crawl := range crawls {
+		check.addDetail(fmt.Sprintf("crawl %d of URL %d", crawl.ID, crawl.URLID))
+	}
+	for _, url := range urls {
+		check.addDetail(fmt.Sprintf("URL %d (%s)", url.ID, url.URL))
+	}
+
+	if opts.Repair && check.Found > 0 {
+		now := time.Now()
+		for _, crawl := range crawls {
+			if err := s.db.Model(&crawl).Updates(map[string]interface{}{
+				"status":        "error",
+				"error_message": "Crawl interrupted",
+				"completed_at":  now,
+			}).Error; err != nil {
+				return check, fmt.Errorf("failed to reset crawl %d: %w", crawl.ID, err)
+			}
+			check.Repaired++
+		}
+
+		for _, url := range urls {
+			if err := s.db.Model(&url).Update("status", "error").Error; err != nil {
+				return check, fmt.Errorf("failed to reset URL %d: %w", url.ID, err)
+			}
+			check.Repaired++
+		}
+
+		// URLs of the interrupted crawls are no longer running either
+		if len(crawls) > 0 {
+			urlIDs := make([]uint, 0, len(crawls))
+			for _, crawl := range crawls {
+				urlIDs = append(urlIDs, crawl.URLID)
+			}
+			if err := s.db.Model(&models.URL{}).Where("id IN ? AND status = ?", urlIDs, "running").Update("status", "error").Error; err != nil {
+				return check, fmt.Errorf("failed to reset URLs: %w", err)
+			}
+		}
+	}
+
+	return check, nil
+}
+
+// linkCounts is a crawl's stored counters next to the counts of its link rows
+type linkCounts struct {
+	ID                  uint
+	InternalLinks       int
+	ExternalLinks       int
+	BrokenLinks         int
+	ActualInternalLinks int
+	ActualExternalLinks int
+	ActualBrokenLinks   int
+}
+
+// checkLinkCounters finds completed crawls whose link counters don't match their link rows
+func (s *IntegrityService) checkLinkCounters(opts IntegrityOptions) (IntegrityCheck, error) {
+	check := IntegrityCheck{Name: "crawl link counters"}
+
+	var mismatches []linkCounts
+	err := s.db.Table("crawls").
+		Select(`crawls.id, crawls.internal_links, crawls.external_links, crawls.broken_links,
+			COALESCE(SUM(CASE WHEN links.link_type = 'internal' THEN links.occurrences ELSE 0 END), 0) AS actual_internal_links,
+			COALESCE(SUM(CASE WHEN links.link_type = 'external' THEN links.occurrences ELSE 0 END), 0) AS actual_external_links,
+			COALESCE(SUM(CASE WHEN links.is_accessible = ? THEN 1 ELSE 0 END), 0) AS actual_broken_links`, false).
+		Joins("LEFT JOIN links ON links.crawl_id = crawls.id").
+		Where("crawls.status = ?", "completed").
+		Group("crawls.id, crawls.internal_links, crawls.external_links, crawls.broken_links").
+		Having(`crawls.internal_links <> COALESCE(SUM(CASE WHEN links.link_type = 'internal' THEN links.occurrences ELSE 0 END), 0)
+			OR crawls.external_links <> COALESCE(SUM(CASE WHEN links.link_type = 'external' THEN links.occurrences ELSE 0 END), 0)
+			OR crawls.broken_links <> COALESCE(SUM(CASE WHEN links.is_accessible = ? THEN 1 ELSE 0 END), 0)`, false).
+		Scan(&mismatches).Error
+	if err != nil {
+		return check, fmt.Errorf("failed to compare link counters: %w", err)
+	}
+
+	check.Found = len(mismatches)
+	for _, m := range mismatches {
+		check.addDetail(fmt.Sprintf("crawl %d: stored %d/%d/%d internal/external/broken, actual %d/%d/%d",
+			m.ID, m.InternalLinks, m.ExternalLinks, m.BrokenLinks, m.ActualInternalLinks, m.ActualExternalLinks, m.ActualBrokenLinks))
+	}
+
+	if opts.Repair {
+		for _, m := range mismatches {
+			if err := s.db.Model(&models.Crawl{}).Where("id = ?", m.ID).Updates(map[string]interface{}{
+				"internal_links": m.ActualInternalLinks,
+				"external_links": m.ActualExternalLinks,
+				"broken_links":   m.ActualBrokenLinks,
+			}).Error; err != nil {
+				return check, fmt.Errorf("failed to update counters of crawl %d: %w", m.ID, err)
+			}
+			check.Repaired++
+		}
+	}
+
+	return check, nil
+}
+
+func (c *IntegrityCheck) addDetail(detail string) {
+	if len(c.Details) < maxIntegrityDetails {
+		c.Details = append(c.Details, detail)
+	}
+}
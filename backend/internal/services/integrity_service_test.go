@@ -0,0 +1,123 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"web-crawler-backend/internal/models"
+)
+
+// seedIntegrityIssues creates one inconsistency of every kind next to a healthy crawl
+func seedIntegrityIssues(t *testing.T, db *gorm.DB) (healthy, stuck, miscounted *models.Crawl) {
+	longAgo := time.Now().Add(-3 * time.Hour)
+
+	url := &models.URL{URL: "https://example.com", Status: "completed"}
+	require.NoError(t, db.Create(url).Error)
+
+	// Repeated links are stored once but counted every time
+	healthy = &models.Crawl{URLID: url.ID, Status: "completed", InternalLinks: 2, BrokenLinks: 1}
+	require.NoError(t, db.Create(healthy).Error)
+	require.NoError(t, db.Create(&models.Link{URLID: url.ID, CrawlID: healthy.ID, LinkURL: "https://example.com/a", LinkType: "internal", Occurrences: 2}).Error)
+
+	miscounted = &models.Crawl{URLID: url.ID, Status: "completed", InternalLinks: 5, ExternalLinks: 5}
+	require.NoError(t, db.Create(miscounted).Error)
+	require.NoError(t, db.Create(&models.Link{URLID: url.ID, CrawlID: miscounted.ID, LinkURL: "https://other.com", LinkType: "external", IsAccessible: true}).Error)
+
+	running := &models.URL{URL: "https://running.example.com", Status: "running"}
+	require.NoError(t, db.Create(running).Error)
+	stuck = &models.Crawl{URLID: running.ID, Status: "running", StartedAt: &longAgo}
+	require.NoError(t, db.Create(stuck).Error)
+
+	orphan := &models.Crawl{URLID: 999, Status: "completed", BrokenLinks: 1}
+	require.NoError(t, db.Create(orphan).Error)
+	require.NoError(t, db.Create(&models.Link{URLID: 999, CrawlID: orphan.ID, LinkURL: "https://gone.example.com"}).Error)
+
+	require.NoError(t, db.Create(&models.Link{URLID: url.ID, CrawlID: 12345, LinkURL: "https://example.com/lost"}).Error)
+
+	return healthy, stuck, miscounted
+}
+
+func findCheck(t *testing.T, report *IntegrityReport, name string) IntegrityCheck {
+	for _, check := range report.Checks {
+		if check.Name == name {
+			return check
+		}
+	}
+	t.Fatalf("check %q missing from report", name)
+	return IntegrityCheck{}
+}
+
+func TestIntegrityService_Run(t *testing.T) {
+	t.Run("reports without changing anything", func(t *testing.T) {
+		db := setupURLTestDB(t)
+		_, stuck, _ := seedIntegrityIssues(t, db)
+
+		report, err := NewIntegrityService(db).Run(IntegrityOptions{StuckAfter: time.Hour})
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, findCheck(t, report, "crawls without URLs").Found)
+		assert.Equal(t, 1, findCheck(t, report, "links without crawls").Found)
+		assert.Equal(t, 1, findCheck(t, report, "URLs stuck running").Found)
+		assert.Equal(t, 1, findCheck(t, report, "crawl link counters").Found)
+		assert.Equal(t, 4, report.Issues())
+
+		var crawl models.Crawl
+		require.NoError(t, db.First(&crawl, stuck.ID).Error)
+		assert.Equal(t, "running", crawl.Status)
+	})
+
+	t.Run("repairs inconsistencies", func(t *testing.T) {
+		db := setupURLTestDB(t)
+		healthy, stuck, miscounted := seedIntegrityIssues(t, db)
+
+		report, err := NewIntegrityService(db).Run(IntegrityOptions{Repair: true, StuckAfter: time.Hour})
+		require.NoError(t, err)
+		for _, check := range report.Checks {
+			assert.Equal(t, check.Found, check.Repaired, check.Name)
+		}
+
+		var crawl models.Crawl
+		require.NoError(t, db.First(&crawl, stuck.ID).Error)
+		assert.Equal(t, "error", crawl.Status)
+		var url models.URL
+		require.NoError(t, db.First(&url, stuck.URLID).Error)
+		assert.Equal(t, "error", url.Status)
+
+		var recounted models.Crawl
+		require.NoError(t, db.First(&recounted, miscounted.ID).Error)
+		assert.Equal(t, 0, recounted.InternalLinks)
+		assert.Equal(t, 1, recounted.ExternalLinks)
+		assert.Equal(t, 0, recounted.BrokenLinks)
+
+		var untouched models.Crawl
+		require.NoError(t, db.First(&untouched, healthy.ID).Error)
+		assert.Equal(t, 2, untouched.InternalLinks)
+
+		var crawls, links int64
+		db.Model(&models.Crawl{}).Count(&crawls)
+		db.Model(&models.Link{}).Count(&links)
+		assert.Equal(t, int64(3), crawls)
+		assert.Equal(t, int64(2), links)
+
+		report, err = NewIntegrityService(db).Run(IntegrityOptions{StuckAfter: time.Hour})
+		require.NoError(t, err)
+		assert.Zero(t, report.Issues())
+	})
+
+	t.Run("recent running crawls are not stuck", func(t *testing.T) {
+		db := setupURLTestDB(t)
+		now := time.Now()
+
+		url := &models.URL{URL: "https://example.com", Status: "running"}
+		require.NoError(t, db.Create(url).Error)
+		require.NoError(t, db.Create(&models.Crawl{URLID: url.ID, Status: "running", StartedAt: &now}).Error)
+
+		report, err := NewIntegrityService(db).Run(IntegrityOptions{StuckAfter: time.Hour})
+		require.NoError(t, err)
+		assert.Zero(t, report.Issues())
+	})
+}
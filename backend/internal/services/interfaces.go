@@ -0,0 +1,78 @@
+package services
+
+import (
+	"context"
+
+	"web-crawler-backend/internal/models"
+)
+
+// URLServiceInterface is the URL service as the request handlers use it, so
+// they can be tested with fakes or given another implementation
+type URLServiceInterface interface {
+	// ForRequest returns the service bound to the context of a request and
+	// limited to the URLs of its organization
+	ForRequest(ctx context.Context, organizationID uint) URLServiceInterface
+	// Filtered returns the service with its URL lists limited by filter
+	Filtered(filter URLFilter) URLServiceInterface
+
+	CreateURLForUser(url string, userID uint) (*models.URL, error)
+	GetURLs(limit, offset int, search, status, sortBy, sortOrder string) ([]*models.URLListItem, int64, error)
+	GetURLsAfter(limit int, cursor, search, status, sortBy, sortOrder string) ([]*models.URLListItem, int64, string, error)
+	ListVersion(search, status string) (string, error)
+	GetURL(id uint) (*models.URL, error)
+	URLVersion(id uint) (string, error)
+	DeleteURL(id uint) error
+	BulkDeleteURLs(ids []uint) error
+	UpdateCrawlSettings(id uint, req models.CrawlSettingsRequest) (*models.URL, error)
+	SetLoginFormOverride(id uint, override *bool) (*models.URL, error)
+
+	GetURLLinks(urlID uint, linkType string, limit, offset int) ([]*models.Link, int64, error)
+	GetURLLinksAfter(urlID uint, linkType string, limit int, cursor string) ([]*models.Link, int64, string, error)
+	GetURLImages(urlID uint, filter string, limit, offset int) ([]*models.Image, int64, error)
+	GetURLForms(urlID uint, formType string, limit, offset int) ([]*models.Form, int64, error)
+
+	GetSEOReport(urlID uint) (*models.SEOReport, error)
+	GetSecurityReport(urlID uint) (*models.SecurityReport, error)
+	GetAccessibilityReport(urlID, crawlID uint, rule string) (*models.AccessibilityReport, error)
+	GetMixedContentReport(urlID, crawlID uint, resourceType string) (*models.MixedContentReport, error)
+	GetStructureReport(urlID uint) (*models.StructureReport, error)
+	GetDuplicates(urlID uint) (*models.DuplicatesReport, error)
+	GetLinkGraph(urlID uint) (*models.LinkGraph, error)
+	GetContentChanges(urlID uint, limit int) ([]models.CrawlChange, error)
+	GetCrawlTrends(urlID uint, limit int) (*models.CrawlTrends, error)
+	GetCrawlDiff(urlID, fromCrawlID, toCrawlID uint) (*models.CrawlDiff, error)
+}
+
+// CrawlServiceInterface is the crawler service as the request handlers use
+// it, so they can be tested with fakes or given another implementation
+type CrawlServiceInterface interface {
+	// ForRequest returns the service bound to the context of a request
+	ForRequest(ctx context.Context) CrawlServiceInterface
+
+	StartCrawlWithPriority(ctx context.Context, urlID uint, priority models.CrawlPriority)
+	GetCrawlStatus(urlID uint) (*models.CrawlStatusResponse, error)
+	ReprocessCrawl(crawlID uint) (*models.Crawl, error)
+	GetCrawlEvents(crawlID uint) ([]models.CrawlEvent, error)
+	RecheckBrokenLinks(urlID uint) (*models.LinkRecheckResult, error)
+	BulkRerunCrawlsWithPriority(urlIDs []uint, priority models.CrawlPriority) error
+}
+
+var (
+	_ URLServiceInterface   = (*URLService)(nil)
+	_ CrawlServiceInterface = (*CrawlerService)(nil)
+)
+
+// ForRequest binds the service to a request, see URLServiceInterface
+func (s *URLService) ForRequest(ctx context.Context, organizationID uint) URLServiceInterface {
+	return s.WithContext(ctx).WithOrganization(organizationID)
+}
+
+// Filtered returns a copy of the service with filter, see WithFilter
+func (s *URLService) Filtered(filter URLFilter) URLServiceInterface {
+	return s.WithFilter(filter)
+}
+
+// ForRequest binds the service to a request, see CrawlServiceInterface
+func (s *CrawlerService) ForRequest(ctx context.Context) CrawlServiceInterface {
+	return s.WithContext(ctx)
+}
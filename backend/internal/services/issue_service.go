@@ -0,0 +1,226 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"web-crawler-backend/internal/issuetracker"
+	"web-crawler-backend/internal/models"
+)
+
+var (
+	// ErrTrackerNotConfigured is returned for trackers this deployment has no credentials for
+	ErrTrackerNotConfigured = errors.New("issue tracker not configured")
+	// ErrInvalidFinding is returned for finding types issues can't be filed for
+	ErrInvalidFinding = errors.New("invalid finding")
+	// ErrFindingNotFound is returned when the latest crawl of the URL doesn't report the finding
+	ErrFindingNotFound = errors.New("finding not found")
+	// ErrTrackerFailed is returned when the tracker couldn't be reached or refused the issue
+	ErrTrackerFailed = errors.New("issue tracker request failed")
+)
+
+// IssueService files GitHub and Jira issues for crawl findings and keeps
+// the link to them
+type IssueService struct {
+	db       *gorm.DB
+	trackers map[string]issuetracker.Tracker
+}
+
+// NewIssueService returns a service filing issues in the given trackers,
+// keyed by their name
+func NewIssueService(db *gorm.DB, trackers map[string]issuetracker.Tracker) *IssueService {
+	return &IssueService{db: db, trackers: trackers}
+}
+
+// WithContext returns a copy of the service whose queries and tracker
+// requests run with ctx
+func (s *IssueService) WithContext(ctx context.Context) *IssueService {
+	copied := *s
+	copied.db = s.db.WithContext(ctx)
+	return &copied
+}
+
+// ListIssues returns the issues filed for the findings of a URL, newest first
+func (s *IssueService) ListIssues(urlID uint) ([]models.ExternalIssue, error) {
+	if err := s.db.First(&models.URL{}, urlID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrURLNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch URL: %w", err)
+	}
+
+	var issues []models.ExternalIssue
+	if err := s.db.Where("url_id = ?", urlID).Order("created_at DESC").Find(&issues).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch issues: %w", err)
+	}
+	return issues, nil
+}
+
+// CreateIssue files an issue for a finding of the latest completed crawl of
+// the URL and stores its link. A finding gets a single issue: if one was
+// filed already it is returned instead, with created false.
+func (s *IssueService) CreateIssue(urlID, userID uint, req models.CreateExternalIssueRequest) (*models.ExternalIssue, bool, error) {
+	tracker, ok := s.trackers[req.Tracker]
+	if !ok {
+		return nil, false, fmt.Errorf("%w: %q", ErrTrackerNotConfigured, req.Tracker)
+	}
+	if req.FindingType != models.FindingBrokenLink && req.FindingType != models.FindingSEOCheck {
+		return nil, false, fmt.Errorf("%w: issues can be filed for %q and %q findings", ErrInvalidFinding, models.FindingBrokenLink, models.FindingSEOCheck)
+	}
+
+	var url models.URL
+	if err := s.db.First(&url, urlID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, false, ErrURLNotFound
+		}
+		return nil, false, fmt.Errorf("failed to fetch URL: %w", err)
+	}
+
+	var existing models.ExternalIssue
+	err := s.db.Where("url_id = ? AND finding_type = ? AND finding_key_hash = ?", urlID, req.FindingType, models.HashFindingKey(req.FindingKey)).
+		First(&existing).Error
+	if err == nil {
+		return &existing, false, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, false, fmt.Errorf("failed to fetch issue: %w", err)
+	}
+
+	var crawl models.Crawl
+	if err := s.db.Where("url_id = ? AND status = ?", urlID, "completed").Order("created_at DESC").First(&crawl).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, false, ErrFindingNotFound
+		}
+		return nil, false, fmt.Errorf("failed to fetch latest crawl: %w", err)
+	}
+
+	var content issuetracker.Issue
+	if req.FindingType == models.FindingBrokenLink {
+		content, err = s.brokenLinkIssue(&url, &crawl, req.FindingKey)
+	} else {
+		content, err = seoCheckIssue(&url, &crawl, req.FindingKey)
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	ctx := context.Background()
+	if s.db.Statement.Context != nil {
+		ctx = s.db.Statement.Context
+	}
+	filed, err := tracker.Create(ctx, content)
+	if err != nil {
+		return nil, false, fmt.Errorf("%w: %v", ErrTrackerFailed, err)
+	}
+
+	issue := models.ExternalIssue{
+		URLID:       urlID,
+		FindingType: req.FindingType,
+		FindingKey:  req.FindingKey,
+		Tracker:     req.Tracker,
+		IssueKey:    filed.Key,
+		IssueURL:    filed.URL,
+		CreatedBy:   userID,
+	}
+	if err := s.db.Create(&issue).Error; err != nil {
+		// The issue exists in the tracker, so report where it is
+		return nil, false, fmt.Errorf("failed to save issue %s (%s): %w", filed.Key, filed.URL, err)
+	}
+
+	recordActivity(s.db, models.ActivityEvent{
+		UserID:  userID,
+		ActorID: &userID,
+		Type:    models.ActivityIssueFiled,
+		URLID:   &urlID,
+		CrawlID: &crawl.ID,
+		Message: fmt.Sprintf("Filed %s for %s %s on %s", filed.Key, strings.ReplaceAll(req.FindingType, "_", " "), req.FindingKey, url.URL),
+	}, map[string]interface{}{
+		"issue_id":     issue.ID,
+		"tracker":      issue.Tracker,
+		"issue_key":    issue.IssueKey,
+		"issue_url":    issue.IssueURL,
+		"finding_type": issue.FindingType,
+		"finding_key":  issue.FindingKey,
+	})
+
+	return &issue, true, nil
+}
+
+// brokenLinkIssue describes a broken link of the crawl, keyed by its URL
+func (s *IssueService) brokenLinkIssue(url *models.URL, crawl *models.Crawl, linkURL string) (issuetracker.Issue, error) {
+	var link models.Link
+	if err := s.db.Where("crawl_id = ? AND link_url = ? AND is_accessible = ?", crawl.ID, linkURL, false).First(&link).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return issuetracker.Issue{}, fmt.Errorf("%w: no broken link to %s", ErrFindingNotFound, linkURL)
+		}
+		return issuetracker.Issue{}, fmt.Errorf("failed to fetch link: %w", err)
+	}
+
+	foundOn := link.FoundOnURL
+	if foundOn == "" {
+		foundOn = url.URL
+	}
+	status := "no response"
+	if link.StatusCode != 0 {
+		status = fmt.Sprintf("HTTP %d", link.StatusCode)
+	}
+	return issuetracker.Issue{
+		Title: fmt.Sprintf("Broken link to %s", linkURL),
+		Body: fmt.Sprintf("The crawl of %s found a broken %s link.\n\nLink: %s\nFound on: %s\nStatus: %s\nCrawled: %s",
+			url.URL, link.LinkType, linkURL, foundOn, status, crawlTime(crawl)),
+		Labels: []string{"broken link"},
+	}, nil
+}
+
+// seoCheckIssue describes a failed SEO check of the crawl, keyed by the check key
+func seoCheckIssue(url *models.URL, crawl *models.Crawl, key string) (issuetracker.Issue, error) {
+	var checks []models.SEOCheck
+	if crawl.SEOChecks != "" {
+		if err := json.Unmarshal([]byte(crawl.SEOChecks), &checks); err != nil {
+			return issuetracker.Issue{}, fmt.Errorf("invalid SEO checks: %w", err)
+		}
+	}
+
+	for _, check := range checks {
+		if check.Key != key {
+			continue
+		}
+		if check.Passed {
+			return issuetracker.Issue{}, fmt.Errorf("%w: SEO check %s passed", ErrFindingNotFound, key)
+		}
+		return issuetracker.Issue{
+			Title: fmt.Sprintf("SEO: %s on %s", check.Title, url.URL),
+			Body: fmt.Sprintf("The crawl of %s failed the %s check.\n\n%s\nScore: %d of %d\nCrawled: %s",
+				url.URL, check.Title, check.Message, check.Score, check.MaxScore, crawlTime(crawl)),
+			Labels: []string{"seo"},
+		}, nil
+	}
+	return issuetracker.Issue{}, fmt.Errorf("%w: no SEO check %s", ErrFindingNotFound, key)
+}
+
+// crawlTime formats when the crawl completed, or started if that's unknown
+func crawlTime(crawl *models.Crawl) string {
+	if crawl.CompletedAt != nil {
+		return crawl.CompletedAt.UTC().Format("2006-01-02 15:04 MST")
+	}
+	return crawl.CreatedAt.UTC().Format("2006-01-02 15:04 MST")
+}
+
+// loadExternalIssues returns the issues filed for a URL's findings of one type keyed by finding key
+func loadExternalIssues(db *gorm.DB, urlID uint, findingType string) (map[string]*models.ExternalIssue, error) {
+	var issues []models.ExternalIssue
+	if err := db.Where("url_id = ? AND finding_type = ?", urlID, findingType).Find(&issues).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch issues: %w", err)
+	}
+
+	byKey := make(map[string]*models.ExternalIssue, len(issues))
+	for i := range issues {
+		byKey[issues[i].FindingKey] = &issues[i]
+	}
+	return byKey, nil
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"web-crawler-backend/internal/issuetracker"
+	"web-crawler-backend/internal/models"
+)
+
+// fakeTracker records the issues filed in it
+type fakeTracker struct {
+	issues []issuetracker.Issue
+	err    error
+}
+
+func (f *fakeTracker) Create(ctx context.Context, issue issuetracker.Issue) (*issuetracker.Created, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	f.issues = append(f.issues, issue)
+	key := fmt.Sprintf("WEB-%d", len(f.issues))
+	return &issuetracker.Created{Key: key, URL: "https://acme.atlassian.net/browse/" + key}, nil
+}
+
+func TestIssueService(t *testing.T) {
+	setup := func(t *testing.T) (*IssueService, *fakeTracker, *URLService, *models.URL) {
+		db := setupURLTestDB(t)
+		url := &models.URL{URL: "https://example.com", Status: "completed"}
+		require.NoError(t, db.Create(url).Error)
+		crawl := &models.Crawl{URLID: url.ID, Status: "completed",
+			SEOChecks: `[{"key":"canonical","title":"Canonical URL","passed":false,"score":0,"max_score":10,"message":"No canonical URL"},{"key":"favicon","passed":true}]`}
+		require.NoError(t, db.Create(crawl).Error)
+		require.NoError(t, db.Create(&models.Link{URLID: url.ID, CrawlID: crawl.ID, LinkURL: "https://example.com/gone", LinkType: "internal", StatusCode: 404}).Error)
+
+		tracker := &fakeTracker{}
+		service := NewIssueService(db, map[string]issuetracker.Tracker{issuetracker.Jira: tracker})
+		return service, tracker, NewURLService(db, &mockCrawlerService{}), url
+	}
+
+	t.Run("issues are linked on their findings", func(t *testing.T) {
+		service, tracker, urlService, url := setup(t)
+
+		issue, created, err := service.CreateIssue(url.ID, 4, models.CreateExternalIssueRequest{
+			Tracker: issuetracker.Jira, FindingType: models.FindingBrokenLink, FindingKey: "https://example.com/gone",
+		})
+		require.NoError(t, err)
+		assert.True(t, created)
+		assert.Equal(t, "WEB-1", issue.IssueKey)
+		require.Len(t, tracker.issues, 1)
+		assert.Equal(t, "Broken link to https://example.com/gone", tracker.issues[0].Title)
+		assert.Contains(t, tracker.issues[0].Body, "Status: HTTP 404")
+
+		_, _, err = service.CreateIssue(url.ID, 4, models.CreateExternalIssueRequest{
+			Tracker: issuetracker.Jira, FindingType: models.FindingSEOCheck, FindingKey: "canonical",
+		})
+		require.NoError(t, err)
+		assert.Contains(t, tracker.issues[1].Body, "No canonical URL")
+
+		links, _, err := urlService.GetURLLinks(url.ID, "broken", 50, 0)
+		require.NoError(t, err)
+		require.Len(t, links, 1)
+		require.NotNil(t, links[0].ExternalIssue)
+		assert.Equal(t, "https://acme.atlassian.net/browse/WEB-1", links[0].ExternalIssue.IssueURL)
+
+		report, err := urlService.GetSEOReport(url.ID)
+		require.NoError(t, err)
+		require.NotNil(t, report.Checks[0].ExternalIssue)
+		assert.Equal(t, "WEB-2", report.Checks[0].ExternalIssue.IssueKey)
+		assert.Nil(t, report.Checks[1].ExternalIssue)
+	})
+
+	t.Run("a finding gets a single issue", func(t *testing.T) {
+		service, tracker, _, url := setup(t)
+		req := models.CreateExternalIssueRequest{Tracker: issuetracker.Jira, FindingType: models.FindingSEOCheck, FindingKey: "canonical"}
+
+		first, _, err := service.CreateIssue(url.ID, 4, req)
+		require.NoError(t, err)
+		again, created, err := service.CreateIssue(url.ID, 5, req)
+		require.NoError(t, err)
+		assert.False(t, created)
+		assert.Equal(t, first.ID, again.ID)
+		assert.Equal(t, models.HashFindingKey("canonical"), again.FindingKeyHash)
+		assert.Len(t, tracker.issues, 1)
+
+		issues, err := service.ListIssues(url.ID)
+		require.NoError(t, err)
+		assert.Len(t, issues, 1)
+
+		var events int64
+		require.NoError(t, service.db.Model(&models.ActivityEvent{}).Where("type = ?", models.ActivityIssueFiled).Count(&events).Error)
+		assert.Equal(t, int64(1), events)
+	})
+
+	t.Run("validates the request", func(t *testing.T) {
+		service, tracker, _, url := setup(t)
+
+		tests := []struct {
+			name string
+			req  models.CreateExternalIssueRequest
+			err  error
+		}{
+			{"unconfigured tracker", models.CreateExternalIssueRequest{Tracker: issuetracker.GitHub, FindingType: models.FindingSEOCheck, FindingKey: "canonical"}, ErrTrackerNotConfigured},
+			{"unknown finding type", models.CreateExternalIssueRequest{Tracker: issuetracker.Jira, FindingType: "typo", FindingKey: "canonical"}, ErrInvalidFinding},
+			{"passed check", models.CreateExternalIssueRequest{Tracker: issuetracker.Jira, FindingType: models.FindingSEOCheck, FindingKey: "favicon"}, ErrFindingNotFound},
+			{"working link", models.CreateExternalIssueRequest{Tracker: issuetracker.Jira, FindingType: models.FindingBrokenLink, FindingKey: "https://example.com/"}, ErrFindingNotFound},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, _, err := service.CreateIssue(url.ID, 4, tt.req)
+				assert.ErrorIs(t, err, tt.err)
+			})
+		}
+		assert.Empty(t, tracker.issues)
+
+		_, _, err := service.CreateIssue(999, 4, tests[2].req)
+		assert.ErrorIs(t, err, ErrURLNotFound)
+	})
+
+	t.Run("tracker failures aren't stored", func(t *testing.T) {
+		service, tracker, _, url := setup(t)
+		tracker.err = fmt.Errorf("%w: 401 Unauthorized", issuetracker.ErrRejected)
+
+		_, _, err := service.CreateIssue(url.ID, 4, models.CreateExternalIssueRequest{
+			Tracker: issuetracker.Jira, FindingType: models.FindingSEOCheck, FindingKey: "canonical",
+		})
+		assert.ErrorIs(t, err, ErrTrackerFailed)
+
+		issues, err := service.ListIssues(url.ID)
+		require.NoError(t, err)
+		assert.Empty(t, issues)
+	})
+}
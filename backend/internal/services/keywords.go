@@ -0,0 +1,138 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/html"
+	"gorm.io/gorm"
+
+	"web-crawler-backend/internal/models"
+)
+
+// Limits of the keywords watched on a URL
+const (
+	maxKeywords      = 50
+	maxKeywordLength = 255
+)
+
+// normalizeKeyword collapses whitespace so phrases match however the page wraps them
+func normalizeKeyword(keyword string) string {
+	return strings.Join(strings.Fields(keyword), " ")
+}
+
+// validateKeywords normalizes the keywords of a URL, dropping empty ones and
+// duplicates that only differ in case or whitespace
+func validateKeywords(keywords []string) ([]string, error) {
+	var valid []string
+	seen := make(map[string]bool)
+	for _, keyword := range keywords {
+		keyword = normalizeKeyword(keyword)
+		if keyword == "" || seen[strings.ToLower(keyword)] {
+			continue
+		}
+		if len(keyword) > maxKeywordLength {
+			return nil, fmt.Errorf("%w: keywords are at most %d characters", ErrInvalidCrawlSettings, maxKeywordLength)
+		}
+		seen[strings.ToLower(keyword)] = true
+		valid = append(valid, keyword)
+	}
+	if len(valid) > maxKeywords {
+		return nil, fmt.Errorf("%w: a URL can watch at most %d keywords", ErrInvalidCrawlSettings, maxKeywords)
+	}
+	return valid, nil
+}
+
+// checkKeywords counts the case-insensitive occurrences of every keyword in
+// the visible text of a page
+func checkKeywords(doc *html.Node, keywords []string) []models.KeywordCheck {
+	if len(keywords) == 0 {
+		return nil
+	}
+
+	text := strings.ToLower(strings.Join(visibleWords(doc), " "))
+	checks := make([]models.KeywordCheck, 0, len(keywords))
+	for _, keyword := range keywords {
+		check := models.KeywordCheck{
+			Keyword:     keyword,
+			Status:      models.KeywordMissing,
+			Occurrences: strings.Count(text, strings.ToLower(normalizeKeyword(keyword))),
+		}
+		if check.Occurrences > 0 {
+			check.Status = models.KeywordPresent
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// compareKeywords marks the checks that differ from the previous completed
+// crawl of the URL that checked keywords
+func (s *CrawlerService) compareKeywords(crawl *models.Crawl, checks []models.KeywordCheck) error {
+	if len(checks) == 0 {
+		return nil
+	}
+
+	var previous models.Crawl
+	err := s.db.Select("id", "keyword_checks").
+		Where("url_id = ? AND id < ? AND status = ? AND keyword_checks <> ''", crawl.URLID, crawl.ID, "completed").
+		Order("id DESC").First(&previous).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to fetch previous keyword checks: %w", err)
+	}
+
+	var previousChecks []models.KeywordCheck
+	if err := json.Unmarshal([]byte(previous.KeywordChecks), &previousChecks); err != nil {
+		return fmt.Errorf("failed to decode keyword checks of crawl %d: %w", previous.ID, err)
+	}
+	occurrences := make(map[string]int, len(previousChecks))
+	for _, check := range previousChecks {
+		occurrences[strings.ToLower(check.Keyword)] = check.Occurrences
+	}
+
+	for i := range checks {
+		count, ok := occurrences[strings.ToLower(checks[i].Keyword)]
+		if !ok {
+			continue
+		}
+		checks[i].PreviousOccurrences = &count
+		checks[i].Changed = count != checks[i].Occurrences
+	}
+	return nil
+}
+
+// recordKeywordsMissing notifies the URL owner of keywords the crawl no longer found
+func (s *CrawlerService) recordKeywordsMissing(urlRecord *models.URL, crawl *models.Crawl, checks []models.KeywordCheck) {
+	if urlRecord.UserID == nil {
+		return
+	}
+
+	var missing []string
+	for _, check := range checks {
+		if check.Disappeared() {
+			missing = append(missing, check.Keyword)
+		}
+	}
+	if len(missing) == 0 {
+		return
+	}
+
+	quoted := make([]string, len(missing))
+	for i, keyword := range missing {
+		quoted[i] = fmt.Sprintf("%q", keyword)
+	}
+	recordActivity(s.db, models.ActivityEvent{
+		UserID:  *urlRecord.UserID,
+		Type:    models.ActivityKeywordMissing,
+		URLID:   &urlRecord.ID,
+		CrawlID: &crawl.ID,
+		Message: fmt.Sprintf("%s no longer found on %s", strings.Join(quoted, ", "), urlRecord.URL),
+	}, map[string]interface{}{
+		"keywords": missing,
+	})
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"web-crawler-backend/internal/models"
+)
+
+func TestKeywordMonitoring(t *testing.T) {
+	body := `<html><body><p>Free
+		shipping on all orders. Free shipping!</p><p>Read our terms of service.</p>
+		<script>var promo = "Black Friday";</script></body></html>`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	db := setupCrawlerTestDB(t)
+	service := NewCrawlerService(db)
+	userID := uint(7)
+	url := &models.URL{URL: server.URL, UserID: &userID, Keywords: []string{"free shipping", "Terms of Service", "Black Friday"}}
+	require.NoError(t, db.Create(url).Error)
+
+	service.StartCrawl(url.ID)
+	status, err := service.GetCrawlStatus(url.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []models.KeywordCheck{
+		{Keyword: "free shipping", Status: models.KeywordPresent, Occurrences: 2},
+		{Keyword: "Terms of Service", Status: models.KeywordPresent, Occurrences: 1},
+		{Keyword: "Black Friday", Status: models.KeywordMissing},
+	}, status.Keywords)
+
+	// The terms disappear and shipping is mentioned once
+	body = `<html><body><p>Free shipping on all orders.</p></body></html>`
+	service.StartCrawl(url.ID)
+	status, err = service.GetCrawlStatus(url.ID)
+	require.NoError(t, err)
+	two, one, zero := 2, 1, 0
+	assert.Equal(t, []models.KeywordCheck{
+		{Keyword: "free shipping", Status: models.KeywordPresent, Occurrences: 1, Changed: true, PreviousOccurrences: &two},
+		{Keyword: "Terms of Service", Status: models.KeywordMissing, Changed: true, PreviousOccurrences: &one},
+		{Keyword: "Black Friday", Status: models.KeywordMissing, PreviousOccurrences: &zero},
+	}, status.Keywords)
+
+	var events []models.ActivityEvent
+	require.NoError(t, db.Where("type = ?", models.ActivityKeywordMissing).Find(&events).Error)
+	require.Len(t, events, 1)
+	assert.Equal(t, userID, events[0].UserID)
+	assert.Contains(t, events[0].Message, `"Terms of Service" no longer found`)
+	assert.JSONEq(t, `{"keywords":["Terms of Service"]}`, events[0].Metadata)
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// linkCheckKeyPrefix namespaces the link check results in Redis
+const linkCheckKeyPrefix = "webcrawler:linkcheck:"
+
+// LinkCheckCache remembers the status codes of external link checks in Redis
+// for a while, so a URL linked from many crawled pages, e.g. during bulk
+// reruns, is requested once per TTL instead of once per crawl. Unlike the
+// query cache, entries aren't invalidated by writes: they describe other
+// sites, not this database.
+//
+// Only answers are cached: failed requests, 429 and 5xx responses are likely
+// transient and checked again next time. A nil *LinkCheckCache caches nothing,
+// and Redis errors are logged and treated as misses.
+type LinkCheckCache struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+// NewLinkCheckCache creates a link check cache stored in Redis. A ttl of zero
+// or less disables it and returns nil.
+func NewLinkCheckCache(client *redis.Client, ttl time.Duration) *LinkCheckCache {
+	if client == nil || ttl <= 0 {
+		return nil
+	}
+	return &LinkCheckCache{client: client, ttl: ttl}
+}
+
+// Get returns the cached status code of target and whether there was one
+func (c *LinkCheckCache) Get(ctx context.Context, target string) (int, bool) {
+	if c == nil {
+		return 0, false
+	}
+
+	status, err := c.client.Get(ctx, linkCheckKey(target)).Int()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			log.Printf("Failed to read link check of %s: %v", target, err)
+		}
+		return 0, false
+	}
+	return status, true
+}
+
+// Set caches the status code of target unless it looks transient
+func (c *LinkCheckCache) Set(ctx context.Context, target string, status int) {
+	if c == nil || status == 0 || status == http.StatusTooManyRequests || status >= 500 {
+		return
+	}
+	if err := c.client.Set(ctx, linkCheckKey(target), status, c.ttl).Err(); err != nil {
+		log.Printf("Failed to cache link check of %s: %v", target, err)
+	}
+}
+
+// linkCheckKey hashes the URL so long URLs make keys of a fixed size
+func linkCheckKey(target string) string {
+	sum := sha256.Sum256([]byte(target))
+	return linkCheckKeyPrefix + hex.EncodeToString(sum[:])
+}
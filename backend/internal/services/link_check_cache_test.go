@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+
+	"web-crawler-backend/internal/models"
+)
+
+func TestLinkCheckCache(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	var requests atomic.Int32
+	status := http.StatusNotFound
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(status)
+	}))
+	defer target.Close()
+
+	service := NewCrawlerService(setupCrawlerTestDB(t)).WithLinkCheckCache(NewLinkCheckCache(client, time.Minute))
+	check := func() models.Link {
+		link := models.Link{LinkURL: target.URL + "/page", LinkType: "external", IsAccessible: true}
+		service.checkLink(target.Client(), &link, NewHostThrottle(1, 0))
+		return link
+	}
+
+	t.Run("a checked link is reused by later crawls", func(t *testing.T) {
+		first, second := check(), check()
+		assert.Equal(t, int32(1), requests.Load())
+		assert.Equal(t, http.StatusNotFound, second.StatusCode)
+		assert.False(t, first.IsAccessible)
+		assert.False(t, second.IsAccessible)
+	})
+
+	t.Run("the link is checked again once the entry expires", func(t *testing.T) {
+		server.FastForward(2 * time.Minute)
+		check()
+		assert.Equal(t, int32(2), requests.Load())
+	})
+
+	t.Run("server errors are not cached", func(t *testing.T) {
+		server.FlushAll()
+		status = http.StatusServiceUnavailable
+		check()
+		link := check()
+		assert.Equal(t, int32(4), requests.Load())
+		assert.Equal(t, http.StatusServiceUnavailable, link.StatusCode)
+	})
+
+	t.Run("a zero TTL disables the cache", func(t *testing.T) {
+		assert.Nil(t, NewLinkCheckCache(client, 0))
+		_, ok := (*LinkCheckCache)(nil).Get(context.Background(), target.URL)
+		assert.False(t, ok)
+	})
+}
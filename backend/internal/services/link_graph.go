@@ -0,0 +1,132 @@
+package services
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"web-crawler-backend/internal/models"
+)
+
+// GetLinkGraph returns the page-to-page link graph of the latest completed crawl of a URL
+func (s *URLService) GetLinkGraph(urlID uint) (*models.LinkGraph, error) {
+	var urlRecord models.URL
+	if err := s.db.First(&urlRecord, urlID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrURLNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch URL: %w", err)
+	}
+
+	graph := &models.LinkGraph{URLID: urlID, Nodes: []models.GraphNode{}, Edges: []models.GraphEdge{}, Orphans: []string{}}
+
+	var crawl models.Crawl
+	if err := s.db.Where("url_id = ? AND status = ?", urlID, "completed").Order("created_at DESC").First(&crawl).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return graph, nil
+		}
+		return nil, fmt.Errorf("failed to fetch latest crawl: %w", err)
+	}
+	graph.CrawlID = crawl.ID
+
+	var pages []models.Page
+	if err := s.db.Where("crawl_id = ?", crawl.ID).Order("depth ASC, id ASC").Find(&pages).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch pages: %w", err)
+	}
+
+	var links []models.PageLink
+	if err := s.db.Where("crawl_id = ?", crawl.ID).Order("id ASC").Find(&links).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch page links: %w", err)
+	}
+
+	nodes := map[string]*models.GraphNode{}
+	var order []string
+	addNode := func(node models.GraphNode) *models.GraphNode {
+		if existing, ok := nodes[node.URL]; ok {
+			return existing
+		}
+		nodes[node.URL] = &node
+		order = append(order, node.URL)
+		return &node
+	}
+
+	root := ""
+	for _, page := range pages {
+		pageURL := page.PageURL
+		if parsed, err := url.Parse(page.PageURL); err == nil {
+			pageURL = normalizePageURL(parsed)
+		}
+		if page.Depth == 0 && root == "" {
+			root = pageURL
+		}
+		addNode(models.GraphNode{URL: pageURL, Title: page.Title, Depth: page.Depth, StatusCode: page.StatusCode, Crawled: true})
+	}
+
+	for _, link := range links {
+		source := addNode(models.GraphNode{URL: link.SourceURL})
+		target, known := nodes[link.TargetURL]
+		if !known {
+			// Linked but not crawled, one level deeper than the page linking to it
+			target = addNode(models.GraphNode{URL: link.TargetURL, Depth: source.Depth + 1})
+		}
+		source.Outbound++
+		target.Inbound++
+		graph.Edges = append(graph.Edges, models.GraphEdge{Source: link.SourceURL, Target: link.TargetURL})
+	}
+
+	for _, key := range order {
+		node := nodes[key]
+		if node.Crawled && node.Inbound == 0 && node.URL != root {
+			node.Orphan = true
+			graph.Orphans = append(graph.Orphans, node.URL)
+		}
+		graph.Nodes = append(graph.Nodes, *node)
+	}
+	sort.Strings(graph.Orphans)
+
+	return graph, nil
+}
+
+// RenderLinkGraphDOT renders a link graph in the GraphViz DOT language. Pages
+// beyond the crawl limits are dashed and orphan pages are highlighted.
+func RenderLinkGraphDOT(graph *models.LinkGraph) string {
+	var b strings.Builder
+	b.WriteString("digraph site {\n")
+	b.WriteString("  node [shape=box];\n")
+
+	for _, node := range graph.Nodes {
+		label := dotEscape(node.URL)
+		if node.Title != "" {
+			label = dotEscape(node.Title) + `\n` + label
+		}
+
+		attrs := []string{`label="` + label + `"`}
+		switch {
+		case !node.Crawled:
+			attrs = append(attrs, "style=dashed")
+		case node.Orphan:
+			attrs = append(attrs, "color=red")
+		}
+		fmt.Fprintf(&b, "  %s [%s];\n", dotQuote(node.URL), strings.Join(attrs, ", "))
+	}
+
+	for _, edge := range graph.Edges {
+		fmt.Fprintf(&b, "  %s -> %s;\n", dotQuote(edge.Source), dotQuote(edge.Target))
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// dotQuote quotes a DOT identifier
+func dotQuote(s string) string {
+	return `"` + dotEscape(s) + `"`
+}
+
+// dotEscape escapes backslashes and quotes inside a quoted DOT string
+func dotEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s)
+}
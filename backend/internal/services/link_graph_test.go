@@ -0,0 +1,69 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"web-crawler-backend/internal/models"
+)
+
+func TestURLService_GetLinkGraph(t *testing.T) {
+	db := setupURLTestDB(t)
+	service := NewURLService(db, &mockCrawlerService{})
+
+	url := &models.URL{URL: "https://example.com", Status: "completed", MaxDepth: 2}
+	require.NoError(t, db.Create(url).Error)
+
+	t.Run("no completed crawl", func(t *testing.T) {
+		graph, err := service.GetLinkGraph(url.ID)
+		require.NoError(t, err)
+		assert.Zero(t, graph.CrawlID)
+		assert.Empty(t, graph.Nodes)
+	})
+
+	crawl := &models.Crawl{URLID: url.ID, Status: "completed"}
+	require.NoError(t, db.Create(crawl).Error)
+	require.NoError(t, db.Create(&[]models.Page{
+		{URLID: url.ID, CrawlID: crawl.ID, PageURL: "https://example.com", Depth: 0, Title: "Home", StatusCode: 200},
+		{URLID: url.ID, CrawlID: crawl.ID, PageURL: "https://example.com/about", Depth: 1, Title: "About", StatusCode: 200},
+		{URLID: url.ID, CrawlID: crawl.ID, PageURL: "https://example.com/old", Depth: 1, StatusCode: 200},
+	}).Error)
+	require.NoError(t, db.Create(&[]models.PageLink{
+		{URLID: url.ID, CrawlID: crawl.ID, SourceURL: "https://example.com/", TargetURL: "https://example.com/about"},
+		{URLID: url.ID, CrawlID: crawl.ID, SourceURL: "https://example.com/about", TargetURL: "https://example.com/"},
+		{URLID: url.ID, CrawlID: crawl.ID, SourceURL: "https://example.com/about", TargetURL: "https://example.com/deep"},
+	}).Error)
+
+	graph, err := service.GetLinkGraph(url.ID)
+	require.NoError(t, err)
+
+	t.Run("nodes and edges", func(t *testing.T) {
+		assert.Equal(t, crawl.ID, graph.CrawlID)
+		require.Len(t, graph.Nodes, 4)
+		assert.Len(t, graph.Edges, 3)
+
+		byURL := map[string]models.GraphNode{}
+		for _, node := range graph.Nodes {
+			byURL[node.URL] = node
+		}
+		assert.Equal(t, 1, byURL["https://example.com/"].Inbound)
+		assert.Equal(t, 2, byURL["https://example.com/about"].Outbound)
+		assert.False(t, byURL["https://example.com/deep"].Crawled)
+		assert.Equal(t, 2, byURL["https://example.com/deep"].Depth)
+	})
+
+	t.Run("orphan pages", func(t *testing.T) {
+		assert.Equal(t, []string{"https://example.com/old"}, graph.Orphans)
+	})
+
+	t.Run("DOT output", func(t *testing.T) {
+		dot := RenderLinkGraphDOT(graph)
+		assert.Contains(t, dot, "digraph site {")
+		assert.Contains(t, dot, `"https://example.com/" -> "https://example.com/about";`)
+		assert.Contains(t, dot, `"https://example.com/deep" [label="https://example.com/deep", style=dashed];`)
+		assert.Contains(t, dot, `"https://example.com/old" [label="https://example.com/old", color=red];`)
+		assert.Contains(t, dot, `label="About\nhttps://example.com/about"`)
+	})
+}
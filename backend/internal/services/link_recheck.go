@@ -0,0 +1,115 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+
+	"web-crawler-backend/internal/models"
+	"web-crawler-backend/internal/repository"
+)
+
+// ErrNoCompletedCrawl is returned when a URL has no completed crawl to work on
+var ErrNoCompletedCrawl = errors.New("URL has no completed crawl")
+
+// RecheckBrokenLinks requests the broken links of the URL's latest completed
+// crawl again, without crawling the page, and updates their status and the
+// crawl's broken link count. Scores are left as they were until the next
+// crawl or reprocess.
+func (s *CrawlerService) RecheckBrokenLinks(urlID uint) (*models.LinkRecheckResult, error) {
+	store := s.repositories()
+	crawl, err := store.Crawls().LatestCompleted(urlID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrNoCompletedCrawl
+		}
+		return nil, fmt.Errorf("failed to fetch latest crawl: %w", err)
+	}
+
+	links, err := store.Links().Broken(crawl.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch broken links: %w", err)
+	}
+
+	// A page linked from several pages is requested once
+	targets := make(map[string]int)
+	for _, link := range links {
+		if isHTTPURL(link.LinkURL) {
+			targets[link.LinkURL] = 0
+		}
+	}
+	s.recheckTargets(targets)
+
+	result := &models.LinkRecheckResult{CrawlID: crawl.ID, Links: make([]models.Link, 0, len(links))}
+	err = store.Transaction(func(tx repository.Store) error {
+		for _, link := range links {
+			status, ok := targets[link.LinkURL]
+			if !ok {
+				continue
+			}
+			link.StatusCode = status
+			link.IsAccessible = status != 0 && status < 400
+			if err := tx.Links().SetStatus(link.ID, link.StatusCode, link.IsAccessible); err != nil {
+				return err
+			}
+
+			result.Checked++
+			if link.IsAccessible {
+				result.Fixed++
+			} else {
+				result.StillBroken++
+			}
+			result.Links = append(result.Links, link)
+		}
+
+		result.BrokenLinks = crawl.BrokenLinks - result.Fixed
+		return tx.Crawls().SetBrokenLinks(crawl.ID, result.BrokenLinks)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save rechecked links: %w", err)
+	}
+	return result, nil
+}
+
+// recheckTargets fills in the status code of every target, checked in
+// parallel and limited per host like the links of a crawl. Fresh results
+// replace cached ones, since a recheck is meant to verify fixes.
+func (s *CrawlerService) recheckTargets(targets map[string]int) {
+	throttle := NewHostThrottle(s.options.MaxConcurrency, s.options.MaxHostQPS)
+	ctx := s.traceContext()
+
+	pending := make([]string, 0, len(targets))
+	for target := range targets {
+		pending = append(pending, target)
+	}
+
+	jobs := make(chan string)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for w := 0; w < max(1, s.options.MaxConcurrency); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for target := range jobs {
+				status := headStatus(ctx, linkCheckClient, target, throttle)
+				s.linkChecks.Set(ctx, target, status)
+				mu.Lock()
+				targets[target] = status
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, target := range pending {
+		jobs <- target
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// isHTTPURL reports whether the link can be requested over HTTP
+func isHTTPURL(link string) bool {
+	parsed, err := url.Parse(link)
+	return err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https")
+}
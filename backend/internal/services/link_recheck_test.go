@@ -0,0 +1,72 @@
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"web-crawler-backend/internal/models"
+)
+
+func TestCrawlerService_RecheckBrokenLinks(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Path != "/fixed" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	db := setupCrawlerTestDB(t)
+	service := NewCrawlerService(db)
+
+	urlRecord := &models.URL{URL: "https://example.com/", Status: "completed"}
+	require.NoError(t, db.Create(urlRecord).Error)
+
+	t.Run("a URL without a completed crawl", func(t *testing.T) {
+		_, err := service.RecheckBrokenLinks(urlRecord.ID)
+		assert.ErrorIs(t, err, ErrNoCompletedCrawl)
+	})
+
+	crawl := &models.Crawl{URLID: urlRecord.ID, Status: "completed", BrokenLinks: 4}
+	require.NoError(t, db.Create(crawl).Error)
+	links := []models.Link{
+		{LinkURL: server.URL + "/fixed", FoundOnURL: urlRecord.URL, LinkType: "external", StatusCode: 500},
+		{LinkURL: server.URL + "/gone", FoundOnURL: urlRecord.URL, LinkType: "external", StatusCode: 404},
+		{LinkURL: server.URL + "/fixed", FoundOnURL: "https://example.com/docs", LinkType: "internal", StatusCode: 500},
+		{LinkURL: "mailto:broken", FoundOnURL: urlRecord.URL, LinkType: "mailto"},
+		{LinkURL: server.URL + "/ok", FoundOnURL: urlRecord.URL, LinkType: "external", StatusCode: 200, IsAccessible: true},
+	}
+	for i := range links {
+		links[i].URLID = urlRecord.ID
+		links[i].CrawlID = crawl.ID
+	}
+	require.NoError(t, db.Create(&links).Error)
+
+	result, err := service.RecheckBrokenLinks(urlRecord.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), requests.Load(), "every broken page is requested once")
+	assert.Equal(t, crawl.ID, result.CrawlID)
+	assert.Equal(t, 3, result.Checked)
+	assert.Equal(t, 2, result.Fixed)
+	assert.Equal(t, 1, result.StillBroken)
+	assert.Equal(t, 2, result.BrokenLinks)
+	require.Len(t, result.Links, 3)
+	assert.Equal(t, http.StatusOK, result.Links[0].StatusCode)
+	assert.True(t, result.Links[0].IsAccessible)
+	assert.Equal(t, http.StatusNotFound, result.Links[1].StatusCode)
+	assert.False(t, result.Links[1].IsAccessible)
+
+	var stored models.Crawl
+	require.NoError(t, db.First(&stored, crawl.ID).Error)
+	assert.Equal(t, 2, stored.BrokenLinks)
+
+	var broken int64
+	require.NoError(t, db.Model(&models.Link{}).Where("crawl_id = ? AND is_accessible = ?", crawl.ID, false).Count(&broken).Error)
+	assert.Equal(t, int64(2), broken)
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/html"
+
+	"web-crawler-backend/internal/models"
+)
+
+// checkMixedContent flags http:// scripts, stylesheets, iframes and images
+// referenced by an https page. Relative and protocol-relative references
+// inherit the page's scheme, so only absolute http URLs can be mixed.
+func (s *CrawlerService) checkMixedContent(doc *html.Node, data *CrawlData, baseURL *url.URL) {
+	if baseURL.Scheme != "https" {
+		return
+	}
+
+	var issues []models.MixedContentIssue
+	addIssue := func(resourceType string, n *html.Node, resource string) {
+		resource = strings.TrimSpace(resource)
+		if !strings.HasPrefix(strings.ToLower(resource), "http://") {
+			return
+		}
+		issues = append(issues, models.MixedContentIssue{
+			ResourceType: resourceType,
+			ResourceURL:  resource,
+			Blocked:      resourceType != models.MixedContentImage,
+			Element:      describeElement(n),
+		})
+	}
+
+	walkNodes(doc, func(n *html.Node) {
+		if n.Type != html.ElementNode {
+			return
+		}
+
+		switch n.Data {
+		case "script":
+			addIssue(models.MixedContentScript, n, getAttr(n, "src"))
+		case "link":
+			if slices.Contains(strings.Fields(strings.ToLower(getAttr(n, "rel"))), "stylesheet") {
+				addIssue(models.MixedContentStylesheet, n, getAttr(n, "href"))
+			}
+		case "iframe":
+			addIssue(models.MixedContentIframe, n, getAttr(n, "src"))
+		case "img":
+			addIssue(models.MixedContentImage, n, getAttr(n, "src"))
+			for _, candidate := range strings.Split(getAttr(n, "srcset"), ",") {
+				if fields := strings.Fields(candidate); len(fields) > 0 {
+					addIssue(models.MixedContentImage, n, fields[0])
+				}
+			}
+		}
+	})
+
+	data.MixedContentIssues = issues
+}
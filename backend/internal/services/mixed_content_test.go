@@ -0,0 +1,61 @@
+package services
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/html"
+
+	"web-crawler-backend/internal/models"
+)
+
+func TestCrawlerService_checkMixedContent(t *testing.T) {
+	db := setupCrawlerTestDB(t)
+	service := NewCrawlerService(db)
+
+	const page = `<html><head>
+		<script src="http://cdn.example.com/app.js"></script>
+		<script src="https://cdn.example.com/safe.js"></script>
+		<link rel="stylesheet" href="HTTP://cdn.example.com/site.css">
+		<link rel="icon" href="http://example.com/favicon.ico">
+	</head><body>
+		<img src="http://example.com/logo.png" srcset="https://example.com/a.png 1x, http://example.com/b.png 2x">
+		<img src="//example.com/relative.png">
+		<iframe src="http://video.example.com/embed"></iframe>
+	</body></html>`
+
+	check := func(t *testing.T, pageURL string) []models.MixedContentIssue {
+		doc, err := html.Parse(strings.NewReader(page))
+		require.NoError(t, err)
+		base, err := url.Parse(pageURL)
+		require.NoError(t, err)
+
+		data := &CrawlData{}
+		service.checkMixedContent(doc, data, base)
+		return data.MixedContentIssues
+	}
+
+	t.Run("https page", func(t *testing.T) {
+		issues := check(t, "https://example.com/")
+		require.Len(t, issues, 5)
+
+		byURL := map[string]models.MixedContentIssue{}
+		for _, issue := range issues {
+			byURL[issue.ResourceURL] = issue
+		}
+		assert.Equal(t, models.MixedContentScript, byURL["http://cdn.example.com/app.js"].ResourceType)
+		assert.True(t, byURL["http://cdn.example.com/app.js"].Blocked)
+		assert.Equal(t, models.MixedContentStylesheet, byURL["HTTP://cdn.example.com/site.css"].ResourceType)
+		assert.Equal(t, models.MixedContentIframe, byURL["http://video.example.com/embed"].ResourceType)
+		assert.Equal(t, models.MixedContentImage, byURL["http://example.com/b.png"].ResourceType)
+		assert.False(t, byURL["http://example.com/logo.png"].Blocked)
+		assert.NotContains(t, byURL, "http://example.com/favicon.ico")
+	})
+
+	t.Run("http page has no mixed content", func(t *testing.T) {
+		assert.Empty(t, check(t, "http://example.com/"))
+	})
+}
@@ -0,0 +1,366 @@
+package services
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"web-crawler-backend/internal/models"
+)
+
+const (
+	// minMonitorInterval and maxMonitorInterval bound the minutes between checks
+	minMonitorInterval = 1
+	maxMonitorInterval = 24 * 60
+	// monitorBatchSize caps how many due monitors are checked per tick
+	monitorBatchSize = 200
+	// monitorConcurrency is how many checks run at the same time
+	monitorConcurrency = 10
+	// monitorTimeout is how long a check waits for the response headers
+	monitorTimeout = 10 * time.Second
+	// monitorErrorLength fits check errors into their column
+	monitorErrorLength = 255
+)
+
+var (
+	// ErrMonitorNotFound is returned when a URL isn't monitored
+	ErrMonitorNotFound = errors.New("monitor not found")
+	// ErrInvalidMonitor is returned for out of range check intervals
+	ErrInvalidMonitor = errors.New("invalid monitor")
+	// ErrInvalidPeriod is returned for availability periods that don't parse
+	ErrInvalidPeriod = errors.New("invalid period")
+)
+
+type MonitorService struct {
+	db     *gorm.DB
+	client *http.Client
+	// history is how long checks are kept; zero keeps them forever
+	history   time.Duration
+	heartbeat Heartbeat
+}
+
+func NewMonitorService(db *gorm.DB, history time.Duration) *MonitorService {
+	return &MonitorService{
+		db:      db,
+		client:  &http.Client{Transport: crawlerTransport, Timeout: monitorTimeout, CheckRedirect: checkCrawlerRedirect},
+		history: history,
+	}
+}
+
+// Heartbeat reports when the monitor last ticked, for readiness checks
+func (s *MonitorService) Heartbeat() *Heartbeat {
+	return &s.heartbeat
+}
+
+// Start checks due monitors every tick until the returned stop function is called
+func (s *MonitorService) Start(tick time.Duration) (stop func()) {
+	ticker := time.NewTicker(tick)
+	s.heartbeat.start(tick, time.Now())
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case now := <-ticker.C:
+				s.RunDue(now)
+				if err := s.PurgeHistory(now); err != nil {
+					log.Printf("Monitor history purge failed: %v", err)
+				}
+				s.heartbeat.beat(now)
+			case <-done:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+
+	return func() { close(done) }
+}
+
+// RunDue checks the monitors that are due and returns how many were checked
+func (s *MonitorService) RunDue(now time.Time) int {
+	var monitors []models.Monitor
+	if err := s.db.Where("enabled = ? AND next_check_at <= ?", true, now).
+		Order("next_check_at ASC").Limit(monitorBatchSize).Find(&monitors).Error; err != nil {
+		log.Printf("Failed to load due monitors: %v", err)
+		return 0
+	}
+
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, monitorConcurrency)
+	checked := 0
+	for i := range monitors {
+		monitor := &monitors[i]
+
+		var url models.URL
+		if err := s.db.Select("id", "url").First(&url, monitor.URLID).Error; err != nil {
+			log.Printf("Skipping monitor %d: URL %d not found", monitor.ID, monitor.URLID)
+			continue
+		}
+
+		// Claim the run first so a slow check isn't started again on the next
+		// tick, nor by another replica
+		next := now.Add(time.Duration(monitor.IntervalMinutes) * time.Minute)
+		claimed, err := claimRun(s.db, &models.Monitor{}, monitor.ID, "next_check_at", *monitor.NextCheckAt, map[string]interface{}{
+			"next_check_at": next,
+		})
+		if err != nil {
+			log.Printf("Failed to update monitor %d: %v", monitor.ID, err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		checked++
+		wg.Add(1)
+		slots <- struct{}{}
+		go func() {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+			s.record(monitor, s.check(url.URL, now))
+		}()
+	}
+	wg.Wait()
+
+	return checked
+}
+
+// check requests a URL once. The body is never read; the check only needs
+// the status code, the time to the response headers and the TLS handshake.
+func (s *MonitorService) check(target string, now time.Time) models.MonitorCheck {
+	result := models.MonitorCheck{CheckedAt: now}
+
+	ctx, cancel := context.WithTimeout(context.Background(), monitorTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		result.Error = truncateError(err)
+		return result
+	}
+
+	start := time.Now()
+	resp, err := s.client.Do(req)
+	result.ResponseMs = time.Since(start).Milliseconds()
+	if err != nil {
+		if isCertificateError(err) {
+			result.TLSValid = new(bool)
+		}
+		result.Error = truncateError(err)
+		return result
+	}
+	resp.Body.Close()
+
+	result.StatusCode = resp.StatusCode
+	result.Up = resp.StatusCode < 400
+	if !result.Up {
+		result.Error = resp.Status
+	}
+	if resp.TLS != nil && len(resp.TLS.PeerCertificates) > 0 {
+		valid := true
+		expires := resp.TLS.PeerCertificates[0].NotAfter
+		result.TLSValid = &valid
+		result.TLSExpiresAt = &expires
+	}
+	return result
+}
+
+// isCertificateError reports whether a request failed because the server's
+// certificate didn't verify
+func isCertificateError(err error) bool {
+	var verification *tls.CertificateVerificationError
+	var unknownAuthority x509.UnknownAuthorityError
+	var invalid x509.CertificateInvalidError
+	var hostname x509.HostnameError
+	return errors.As(err, &verification) || errors.As(err, &unknownAuthority) ||
+		errors.As(err, &invalid) || errors.As(err, &hostname)
+}
+
+func truncateError(err error) string {
+	message := err.Error()
+	if len(message) > monitorErrorLength {
+		message = message[:monitorErrorLength]
+	}
+	return message
+}
+
+// record stores a check and the monitor's latest result
+func (s *MonitorService) record(monitor *models.Monitor, check models.MonitorCheck) {
+	check.URLID = monitor.URLID
+	if err := s.db.Create(&check).Error; err != nil {
+		log.Printf("Failed to store check of monitor %d: %v", monitor.ID, err)
+		return
+	}
+	if err := s.db.Model(monitor).Updates(map[string]interface{}{
+		"last_check_at": check.CheckedAt,
+		"last_up":       check.Up,
+	}).Error; err != nil {
+		log.Printf("Failed to update monitor %d: %v", monitor.ID, err)
+	}
+}
+
+// PurgeHistory deletes the checks older than the history period
+func (s *MonitorService) PurgeHistory(now time.Time) error {
+	if s.history <= 0 {
+		return nil
+	}
+	if err := s.db.Where("checked_at < ?", now.Add(-s.history)).Delete(&models.MonitorCheck{}).Error; err != nil {
+		return fmt.Errorf("failed to purge monitor checks: %w", err)
+	}
+	return nil
+}
+
+// GetMonitor returns the monitor of a URL
+func (s *MonitorService) GetMonitor(urlID uint) (*models.Monitor, error) {
+	var monitor models.Monitor
+	if err := s.db.Where("url_id = ?", urlID).First(&monitor).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrMonitorNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch monitor: %w", err)
+	}
+	return &monitor, nil
+}
+
+// SetMonitor creates or replaces the monitor of a URL. The first check runs
+// on the next tick.
+func (s *MonitorService) SetMonitor(urlID uint, req models.MonitorRequest) (*models.Monitor, error) {
+	if req.IntervalMinutes < minMonitorInterval || req.IntervalMinutes > maxMonitorInterval {
+		return nil, fmt.Errorf("%w: interval_minutes must be between %d and %d", ErrInvalidMonitor, minMonitorInterval, maxMonitorInterval)
+	}
+
+	if err := s.db.First(&models.URL{}, urlID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrURLNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch URL: %w", err)
+	}
+
+	var monitor models.Monitor
+	if err := s.db.Where("url_id = ?", urlID).First(&monitor).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to fetch monitor: %w", err)
+	}
+
+	now := time.Now()
+	monitor.URLID = urlID
+	monitor.IntervalMinutes = req.IntervalMinutes
+	monitor.Enabled = req.Enabled == nil || *req.Enabled
+	monitor.NextCheckAt = &now
+	if err := s.db.Save(&monitor).Error; err != nil {
+		return nil, fmt.Errorf("failed to save monitor: %w", err)
+	}
+	return &monitor, nil
+}
+
+// DeleteMonitor stops monitoring a URL; its check history is kept
+func (s *MonitorService) DeleteMonitor(urlID uint) error {
+	result := s.db.Where("url_id = ?", urlID).Delete(&models.Monitor{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete monitor: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrMonitorNotFound
+	}
+	return nil
+}
+
+// ListChecks returns the latest checks of a URL, newest first
+func (s *MonitorService) ListChecks(urlID uint, limit, offset int) ([]models.MonitorCheck, int64, error) {
+	query := s.db.Model(&models.MonitorCheck{}).Where("url_id = ?", urlID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count monitor checks: %w", err)
+	}
+
+	var checks []models.MonitorCheck
+	if err := query.Order("checked_at DESC, id DESC").Limit(limit).Offset(offset).Find(&checks).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch monitor checks: %w", err)
+	}
+	return checks, total, nil
+}
+
+// parsePeriod parses availability periods such as 24h, 7d or 90m
+func parsePeriod(period string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(period, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 1 || n > 365 {
+			return 0, fmt.Errorf("%w: days must be between 1 and 365", ErrInvalidPeriod)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(period)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("%w: expected a duration such as 24h or 7d", ErrInvalidPeriod)
+	}
+	return d, nil
+}
+
+// GetAvailability summarizes the checks of a URL over the period before now
+func (s *MonitorService) GetAvailability(urlID uint, period string, now time.Time) (*models.Availability, error) {
+	d, err := parsePeriod(period)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.db.First(&models.URL{}, urlID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrURLNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch URL: %w", err)
+	}
+
+	availability := &models.Availability{URLID: urlID, Period: period, Since: now.Add(-d)}
+	var totals struct {
+		Checks        int64
+		UpChecks      int64
+		AvgResponseMs *float64
+	}
+	if err := s.db.Model(&models.MonitorCheck{}).
+		Select("COUNT(*) AS checks, "+
+			"SUM(CASE WHEN up THEN 1 ELSE 0 END) AS up_checks, "+
+			"AVG(CASE WHEN up THEN response_ms END) AS avg_response_ms").
+		Where("url_id = ? AND checked_at >= ?", urlID, availability.Since).
+		Scan(&totals).Error; err != nil {
+		return nil, fmt.Errorf("failed to summarize monitor checks: %w", err)
+	}
+	availability.Checks = totals.Checks
+	availability.UpChecks = totals.UpChecks
+	availability.AvgResponseMs = totals.AvgResponseMs
+	if totals.Checks > 0 {
+		uptime := float64(totals.UpChecks) * 100 / float64(totals.Checks)
+		availability.UptimePercent = &uptime
+	}
+
+	var last models.MonitorCheck
+	err = s.db.Where("url_id = ?", urlID).Order("checked_at DESC, id DESC").First(&last).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to fetch latest monitor check: %w", err)
+	}
+	if err == nil {
+		availability.LastCheck = &last
+	}
+
+	var tlsCheck models.MonitorCheck
+	err = s.db.Where("url_id = ? AND tls_expires_at IS NOT NULL", urlID).Order("checked_at DESC, id DESC").First(&tlsCheck).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to fetch TLS expiry: %w", err)
+	}
+	if err == nil {
+		availability.TLSExpiresAt = tlsCheck.TLSExpiresAt
+	}
+
+	return availability, nil
+}
@@ -0,0 +1,140 @@
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"web-crawler-backend/internal/models"
+)
+
+func setupMonitorTest(t *testing.T) (*gorm.DB, *MonitorService) {
+	db := setupURLTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.Monitor{}, &models.MonitorCheck{}))
+	return db, NewMonitorService(db, 24*time.Hour)
+}
+
+func TestMonitorService(t *testing.T) {
+	t.Run("validates the interval", func(t *testing.T) {
+		db, service := setupMonitorTest(t)
+		url := &models.URL{URL: "https://example.com"}
+		require.NoError(t, db.Create(url).Error)
+
+		_, err := service.SetMonitor(url.ID, models.MonitorRequest{IntervalMinutes: 0})
+		assert.ErrorIs(t, err, ErrInvalidMonitor)
+		_, err = service.SetMonitor(url.ID+1, models.MonitorRequest{IntervalMinutes: 5})
+		assert.ErrorIs(t, err, ErrURLNotFound)
+
+		monitor, err := service.SetMonitor(url.ID, models.MonitorRequest{IntervalMinutes: 5})
+		require.NoError(t, err)
+		assert.True(t, monitor.Enabled)
+
+		require.NoError(t, service.DeleteMonitor(url.ID))
+		assert.ErrorIs(t, service.DeleteMonitor(url.ID), ErrMonitorNotFound)
+	})
+
+	t.Run("records checks and availability", func(t *testing.T) {
+		healthy := true
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !healthy {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+		}))
+		defer server.Close()
+
+		db, service := setupMonitorTest(t)
+		service.client = server.Client()
+		url := &models.URL{URL: server.URL}
+		require.NoError(t, db.Create(url).Error)
+		_, err := service.SetMonitor(url.ID, models.MonitorRequest{IntervalMinutes: 5})
+		require.NoError(t, err)
+
+		now := time.Now()
+		assert.Equal(t, 1, service.RunDue(now))
+		assert.Equal(t, 0, service.RunDue(now.Add(time.Minute)), "not due before the interval")
+		healthy = false
+		assert.Equal(t, 1, service.RunDue(now.Add(5*time.Minute)))
+		healthy = true
+		assert.Equal(t, 1, service.RunDue(now.Add(10*time.Minute)))
+		assert.Equal(t, 1, service.RunDue(now.Add(15*time.Minute)))
+
+		checks, total, err := service.ListChecks(url.ID, 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(4), total)
+		down := checks[2]
+		assert.False(t, down.Up)
+		assert.Equal(t, http.StatusServiceUnavailable, down.StatusCode)
+		require.NotNil(t, checks[0].TLSValid)
+		assert.True(t, *checks[0].TLSValid)
+		assert.NotNil(t, checks[0].TLSExpiresAt)
+
+		availability, err := service.GetAvailability(url.ID, "1d", now.Add(20*time.Minute))
+		require.NoError(t, err)
+		assert.Equal(t, int64(4), availability.Checks)
+		assert.Equal(t, int64(3), availability.UpChecks)
+		require.NotNil(t, availability.UptimePercent)
+		assert.InDelta(t, 75.0, *availability.UptimePercent, 0.01)
+		assert.NotNil(t, availability.AvgResponseMs)
+		assert.Equal(t, checks[0].ID, availability.LastCheck.ID)
+		assert.NotNil(t, availability.TLSExpiresAt)
+
+		monitor, err := service.GetMonitor(url.ID)
+		require.NoError(t, err)
+		require.NotNil(t, monitor.LastUp)
+		assert.True(t, *monitor.LastUp)
+
+		_, err = service.GetAvailability(url.ID, "forever", now)
+		assert.ErrorIs(t, err, ErrInvalidPeriod)
+
+		require.NoError(t, service.PurgeHistory(now.Add(24*time.Hour+7*time.Minute)))
+		_, total, err = service.ListChecks(url.ID, 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), total, "checks older than the history are purged")
+	})
+
+	t.Run("untrusted certificates fail the check", func(t *testing.T) {
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		defer server.Close()
+
+		_, service := setupMonitorTest(t)
+		check := service.check(server.URL, time.Now())
+		assert.False(t, check.Up)
+		require.NotNil(t, check.TLSValid)
+		assert.False(t, *check.TLSValid)
+		assert.NotEmpty(t, check.Error)
+	})
+
+	t.Run("replicas check a due monitor once", func(t *testing.T) {
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		defer server.Close()
+
+		db, service := setupMonitorTest(t)
+		url := &models.URL{URL: server.URL}
+		require.NoError(t, db.Create(url).Error)
+		_, err := service.SetMonitor(url.ID, models.MonitorRequest{IntervalMinutes: 5})
+		require.NoError(t, err)
+
+		// The other replica runs right after this one loaded the due monitors
+		now := time.Now()
+		service.client = server.Client()
+		other := NewMonitorService(db, 24*time.Hour)
+		other.client = server.Client()
+		otherChecked, ran := 0, false
+		require.NoError(t, db.Callback().Query().After("gorm:query").Register("test:replica", func(tx *gorm.DB) {
+			if tx.Statement.Table == "monitors" && !ran {
+				ran = true
+				otherChecked = other.RunDue(now)
+			}
+		}))
+
+		assert.Equal(t, 1, service.RunDue(now)+otherChecked)
+		_, total, err := service.ListChecks(url.ID, 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+	})
+}
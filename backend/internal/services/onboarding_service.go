@@ -0,0 +1,279 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"web-crawler-backend/internal/models"
+)
+
+var (
+	// ErrUnknownOnboardingStep is returned for step keys that can't be completed by the client
+	ErrUnknownOnboardingStep = errors.New("unknown onboarding step")
+	// ErrDemoDisabled is returned when no sample site is configured
+	ErrDemoDisabled = errors.New("demo crawl is disabled")
+)
+
+// onboardingSteps lists the steps in order. Derived steps are completed
+// automatically from the user's data, the rest are marked by the client.
+var onboardingSteps = []struct {
+	key     string
+	title   string
+	derived bool
+}{
+	{models.OnboardingStepAccountCreated, "Create your account", true},
+	{models.OnboardingStepDemoExplored, "Explore the demo crawl", false},
+	{models.OnboardingStepFirstURLAdded, "Add your first URL", true},
+	{models.OnboardingStepFirstCrawlCompleted, "Complete your first crawl", true},
+	{models.OnboardingStepReportViewed, "View a crawl report", false},
+}
+
+// demoCrawlMaxAge is how long a crawl of the shared sample site is shown to
+// new users before the next demo request crawls it again
+const demoCrawlMaxAge = 24 * time.Hour
+
+type OnboardingService struct {
+	db         *gorm.DB
+	urlService *URLService
+	sampleURL  string
+}
+
+func NewOnboardingService(db *gorm.DB, urlService *URLService, sampleURL string) *OnboardingService {
+	return &OnboardingService{
+		db:         db,
+		urlService: urlService,
+		sampleURL:  sampleURL,
+	}
+}
+
+// GetStatus returns the user's onboarding steps and current position
+func (s *OnboardingService) GetStatus(userID uint) (*models.OnboardingStatus, error) {
+	state, err := s.loadState(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	completed, err := parseCompletedSteps(state.CompletedSteps)
+	if err != nil {
+		return nil, err
+	}
+
+	derived, err := s.derivedSteps(userID, state)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &models.OnboardingStatus{Completed: true}
+	for _, step := range onboardingSteps {
+		item := models.OnboardingStep{Key: step.key, Title: step.title}
+		if at, ok := completed[step.key]; ok {
+			item.Completed = true
+			item.CompletedAt = &at
+		} else if derived[step.key] {
+			item.Completed = true
+		}
+
+		if !item.Completed && status.CurrentStep == "" {
+			status.CurrentStep = step.key
+			status.Completed = false
+		}
+		status.Steps = append(status.Steps, item)
+	}
+
+	if state.DemoURLID != nil {
+		var demo models.URL
+		if err := s.db.First(&demo, *state.DemoURLID).Error; err == nil {
+			status.Demo = &demo
+		}
+	}
+
+	return status, nil
+}
+
+// CompleteStep marks a client-driven step as done
+func (s *OnboardingService) CompleteStep(userID uint, key string) (*models.OnboardingStatus, error) {
+	valid := false
+	for _, step := range onboardingSteps {
+		if step.key == key && !step.derived {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return nil, ErrUnknownOnboardingStep
+	}
+
+	state, err := s.loadState(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	completed, err := parseCompletedSteps(state.CompletedSteps)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, done := completed[key]; !done {
+		completed[key] = time.Now()
+		encoded, _ := json.Marshal(completed)
+		state.CompletedSteps = string(encoded)
+		if err := s.db.Save(state).Error; err != nil {
+			return nil, fmt.Errorf("failed to save onboarding state: %w", err)
+		}
+	}
+
+	return s.GetStatus(userID)
+}
+
+// CreateDemoCrawl adds the sample site for the user. The site is only
+// crawled when it is new or its latest crawl is stale; otherwise the user
+// shares the crawl other users already see.
+func (s *OnboardingService) CreateDemoCrawl(userID uint) (*models.URL, error) {
+	if s.sampleURL == "" {
+		return nil, ErrDemoDisabled
+	}
+
+	state, err := s.loadState(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	demo, err := s.currentDemo()
+	if err != nil {
+		return nil, err
+	}
+	if demo == nil {
+		// The sample site is shared, so it's created without an owner
+		if demo, err = s.urlService.CreateURL(s.sampleURL); err != nil {
+			return nil, fmt.Errorf("failed to create demo crawl: %w", err)
+		}
+	}
+
+	state.DemoURLID = &demo.ID
+	if err := s.db.Save(state).Error; err != nil {
+		return nil, fmt.Errorf("failed to save onboarding state: %w", err)
+	}
+
+	return demo, nil
+}
+
+// currentDemo returns the sample site if it is being crawled or was crawled
+// within demoCrawlMaxAge, or nil if it is missing or stale
+func (s *OnboardingService) currentDemo() (*models.URL, error) {
+	sampleURL, err := NormalizeURL(s.sampleURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var demos []models.URL
+	if err := s.db.Where("organization_id = ? AND url = ?", 0, sampleURL).Limit(1).Find(&demos).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch demo URL: %w", err)
+	}
+	if len(demos) == 0 {
+		return nil, nil
+	}
+	demo := &demos[0]
+	if demo.Status == "pending" || demo.Status == "running" {
+		return demo, nil
+	}
+
+	var recent int64
+	if err := s.db.Model(&models.Crawl{}).
+		Where("url_id = ? AND status IN ? AND created_at > ?", demo.ID, []string{"completed", "unchanged"}, time.Now().Add(-demoCrawlMaxAge)).
+		Count(&recent).Error; err != nil {
+		return nil, fmt.Errorf("failed to check demo crawls: %w", err)
+	}
+	if recent == 0 {
+		return nil, nil
+	}
+	return demo, nil
+}
+
+// RemoveDemo detaches the demo crawl from the user and deletes the sample URL
+// once no other user is still using it
+func (s *OnboardingService) RemoveDemo(userID uint) error {
+	state, err := s.loadState(userID)
+	if err != nil {
+		return err
+	}
+
+	if state.DemoURLID == nil {
+		return nil
+	}
+
+	demoURLID := *state.DemoURLID
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(state).Update("demo_url_id", nil).Error; err != nil {
+			return fmt.Errorf("failed to update onboarding state: %w", err)
+		}
+
+		var remaining int64
+		if err := tx.Model(&models.OnboardingState{}).Where("demo_url_id = ?", demoURLID).Count(&remaining).Error; err != nil {
+			return fmt.Errorf("failed to check demo usage: %w", err)
+		}
+		if remaining > 0 {
+			return nil
+		}
+
+		if err := tx.Where("id = ? AND user_id IS NULL", demoURLID).Delete(&models.URL{}).Error; err != nil {
+			return fmt.Errorf("failed to delete demo URL: %w", err)
+		}
+		return nil
+	})
+}
+
+// loadState returns the user's onboarding state, creating it on first access
+func (s *OnboardingService) loadState(userID uint) (*models.OnboardingState, error) {
+	var state models.OnboardingState
+	err := s.db.Where(models.OnboardingState{UserID: userID}).FirstOrCreate(&state).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load onboarding state: %w", err)
+	}
+	return &state, nil
+}
+
+// derivedSteps works out which automatic steps the user has completed
+func (s *OnboardingService) derivedSteps(userID uint, state *models.OnboardingState) (map[string]bool, error) {
+	derived := map[string]bool{models.OnboardingStepAccountCreated: true}
+
+	urls := s.db.Model(&models.URL{}).Where("user_id = ?", userID)
+	if state.DemoURLID != nil {
+		urls = urls.Where("id <> ?", *state.DemoURLID)
+	}
+
+	var urlCount int64
+	if err := urls.Count(&urlCount).Error; err != nil {
+		return nil, fmt.Errorf("failed to count URLs: %w", err)
+	}
+	derived[models.OnboardingStepFirstURLAdded] = urlCount > 0
+
+	if urlCount > 0 {
+		var crawlCount int64
+		err := s.db.Model(&models.Crawl{}).
+			Joins("JOIN urls ON urls.id = crawls.url_id").
+			Where("urls.user_id = ? AND urls.deleted_at IS NULL AND crawls.status = ?", userID, "completed").
+			Count(&crawlCount).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to count crawls: %w", err)
+		}
+		derived[models.OnboardingStepFirstCrawlCompleted] = crawlCount > 0
+	}
+
+	return derived, nil
+}
+
+// parseCompletedSteps decodes the stored step completion times
+func parseCompletedSteps(raw string) (map[string]time.Time, error) {
+	completed := make(map[string]time.Time)
+	if raw == "" {
+		return completed, nil
+	}
+	if err := json.Unmarshal([]byte(raw), &completed); err != nil {
+		return nil, fmt.Errorf("invalid onboarding state: %w", err)
+	}
+	return completed, nil
+}
@@ -0,0 +1,171 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"web-crawler-backend/internal/models"
+)
+
+func setupOnboardingTest(t *testing.T, sampleURL string) (*OnboardingService, *gorm.DB) {
+	db := setupURLTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.OnboardingState{}))
+
+	urlService := NewURLService(db, &mockCrawlerService{})
+	return NewOnboardingService(db, urlService, sampleURL), db
+}
+
+func stepCompleted(status *models.OnboardingStatus, key string) bool {
+	for _, step := range status.Steps {
+		if step.Key == key {
+			return step.Completed
+		}
+	}
+	return false
+}
+
+func TestOnboardingService_GetStatus(t *testing.T) {
+	t.Run("new user starts after account creation", func(t *testing.T) {
+		service, _ := setupOnboardingTest(t, "")
+
+		status, err := service.GetStatus(1)
+		require.NoError(t, err)
+		assert.Len(t, status.Steps, 5)
+		assert.True(t, stepCompleted(status, models.OnboardingStepAccountCreated))
+		assert.Equal(t, models.OnboardingStepDemoExplored, status.CurrentStep)
+		assert.False(t, status.Completed)
+	})
+
+	t.Run("derives steps from the user's URLs and crawls", func(t *testing.T) {
+		service, db := setupOnboardingTest(t, "")
+
+		userID := uint(1)
+		urlRecord := &models.URL{URL: "https://mine.com", Status: "completed", UserID: &userID}
+		require.NoError(t, db.Create(urlRecord).Error)
+		require.NoError(t, db.Create(&models.Crawl{URLID: urlRecord.ID, Status: "completed"}).Error)
+
+		status, err := service.GetStatus(userID)
+		require.NoError(t, err)
+		assert.True(t, stepCompleted(status, models.OnboardingStepFirstURLAdded))
+		assert.True(t, stepCompleted(status, models.OnboardingStepFirstCrawlCompleted))
+
+		// Another user's progress is separate
+		other, err := service.GetStatus(2)
+		require.NoError(t, err)
+		assert.False(t, stepCompleted(other, models.OnboardingStepFirstURLAdded))
+	})
+
+	t.Run("all steps completed", func(t *testing.T) {
+		service, db := setupOnboardingTest(t, "")
+
+		userID := uint(1)
+		urlRecord := &models.URL{URL: "https://mine.com", UserID: &userID}
+		require.NoError(t, db.Create(urlRecord).Error)
+		require.NoError(t, db.Create(&models.Crawl{URLID: urlRecord.ID, Status: "completed"}).Error)
+
+		_, err := service.CompleteStep(userID, models.OnboardingStepDemoExplored)
+		require.NoError(t, err)
+		status, err := service.CompleteStep(userID, models.OnboardingStepReportViewed)
+		require.NoError(t, err)
+
+		assert.True(t, status.Completed)
+		assert.Empty(t, status.CurrentStep)
+	})
+}
+
+func TestOnboardingService_CompleteStep(t *testing.T) {
+	service, _ := setupOnboardingTest(t, "")
+
+	t.Run("marks client step as done", func(t *testing.T) {
+		status, err := service.CompleteStep(1, models.OnboardingStepDemoExplored)
+		require.NoError(t, err)
+		assert.True(t, stepCompleted(status, models.OnboardingStepDemoExplored))
+		assert.Equal(t, models.OnboardingStepFirstURLAdded, status.CurrentStep)
+	})
+
+	t.Run("rejects derived steps", func(t *testing.T) {
+		_, err := service.CompleteStep(1, models.OnboardingStepFirstURLAdded)
+		assert.ErrorIs(t, err, ErrUnknownOnboardingStep)
+	})
+
+	t.Run("rejects unknown steps", func(t *testing.T) {
+		_, err := service.CompleteStep(1, "bogus")
+		assert.ErrorIs(t, err, ErrUnknownOnboardingStep)
+	})
+}
+
+func TestOnboardingService_DemoCrawl(t *testing.T) {
+	t.Run("creates and removes demo crawl", func(t *testing.T) {
+		service, db := setupOnboardingTest(t, "https://books.toscrape.com/")
+
+		demo, err := service.CreateDemoCrawl(1)
+		require.NoError(t, err)
+		assert.Equal(t, "https://books.toscrape.com", demo.URL)
+		assert.Nil(t, demo.UserID)
+
+		status, err := service.GetStatus(1)
+		require.NoError(t, err)
+		require.NotNil(t, status.Demo)
+		assert.Equal(t, demo.ID, status.Demo.ID)
+
+		// The demo doesn't count as the user's own first URL
+		assert.False(t, stepCompleted(status, models.OnboardingStepFirstURLAdded))
+
+		require.NoError(t, service.RemoveDemo(1))
+
+		status, err = service.GetStatus(1)
+		require.NoError(t, err)
+		assert.Nil(t, status.Demo)
+
+		var count int64
+		db.Model(&models.URL{}).Where("id = ?", demo.ID).Count(&count)
+		assert.Equal(t, int64(0), count)
+	})
+
+	t.Run("keeps shared demo while other users use it", func(t *testing.T) {
+		service, db := setupOnboardingTest(t, "https://books.toscrape.com/")
+
+		demo, err := service.CreateDemoCrawl(1)
+		require.NoError(t, err)
+		_, err = service.CreateDemoCrawl(2)
+		require.NoError(t, err)
+
+		require.NoError(t, service.RemoveDemo(1))
+
+		var count int64
+		db.Model(&models.URL{}).Where("id = ?", demo.ID).Count(&count)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("crawls the shared demo only when it is stale", func(t *testing.T) {
+		service, db := setupOnboardingTest(t, "https://books.toscrape.com/")
+
+		demo := &models.URL{URL: "https://books.toscrape.com", Status: "completed"}
+		require.NoError(t, db.Create(demo).Error)
+		crawl := &models.Crawl{URLID: demo.ID, Status: "completed", CreatedAt: time.Now().Add(-time.Hour)}
+		require.NoError(t, db.Create(crawl).Error)
+
+		reused, err := service.CreateDemoCrawl(1)
+		require.NoError(t, err)
+		assert.Equal(t, demo.ID, reused.ID)
+		assert.Equal(t, "completed", reused.Status)
+
+		require.NoError(t, db.Model(crawl).Update("created_at", time.Now().Add(-2*demoCrawlMaxAge)).Error)
+
+		recrawled, err := service.CreateDemoCrawl(2)
+		require.NoError(t, err)
+		assert.Equal(t, demo.ID, recrawled.ID)
+		assert.Equal(t, "pending", recrawled.Status)
+	})
+
+	t.Run("disabled without sample URL", func(t *testing.T) {
+		service, _ := setupOnboardingTest(t, "")
+
+		_, err := service.CreateDemoCrawl(1)
+		assert.ErrorIs(t, err, ErrDemoDisabled)
+	})
+}
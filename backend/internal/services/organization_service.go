@@ -0,0 +1,252 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"web-crawler-backend/internal/models"
+)
+
+var (
+	// ErrOrganizationNotFound is returned for organizations that don't exist
+	// or that the user is not a member of
+	ErrOrganizationNotFound = errors.New("organization not found")
+	// ErrMemberNotFound is returned when a user is not a member of the organization
+	ErrMemberNotFound = errors.New("member not found")
+	// ErrAlreadyMember is returned when adding a user who is already a member
+	ErrAlreadyMember = errors.New("user is already a member")
+	// ErrOrgPermission is returned when the role of the acting member doesn't allow a change
+	ErrOrgPermission = errors.New("insufficient organization role")
+	// ErrLastOwner is returned when a change would leave an organization without owners
+	ErrLastOwner = errors.New("organization needs at least one owner")
+)
+
+// OrganizationService manages organizations and their members. URLs belong to
+// an organization through their organization_id, with 0 holding the URLs
+// outside any organization.
+type OrganizationService struct {
+	db *gorm.DB
+}
+
+func NewOrganizationService(db *gorm.DB) *OrganizationService {
+	return &OrganizationService{db: db}
+}
+
+// WithContext returns a copy of the service whose queries run with ctx, so
+// they are traced as part of the request
+func (s *OrganizationService) WithContext(ctx context.Context) *OrganizationService {
+	return &OrganizationService{db: s.db.WithContext(ctx)}
+}
+
+// CreateOrganization creates an organization with the user as its owner
+func (s *OrganizationService) CreateOrganization(userID uint, name string) (*models.OrganizationWithRole, error) {
+	org := &models.Organization{Name: name, CreatedBy: userID}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(org).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.Membership{OrganizationID: org.ID, UserID: userID, Role: models.OrgRoleOwner}).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create organization: %w", err)
+	}
+	return &models.OrganizationWithRole{Organization: *org, Role: models.OrgRoleOwner}, nil
+}
+
+// ListOrganizations returns the organizations the user is a member of
+func (s *OrganizationService) ListOrganizations(userID uint) ([]*models.OrganizationWithRole, error) {
+	orgs := []*models.OrganizationWithRole{}
+	err := s.db.Model(&models.Organization{}).
+		Select("organizations.*, memberships.role").
+		Joins("JOIN memberships ON memberships.organization_id = organizations.id").
+		Where("memberships.user_id = ?", userID).
+		Order("organizations.name ASC, organizations.id ASC").
+		Scan(&orgs).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch organizations: %w", err)
+	}
+	return orgs, nil
+}
+
+// GetMembership returns the membership of a user in an organization, or
+// ErrOrganizationNotFound if the user is not a member
+func (s *OrganizationService) GetMembership(orgID, userID uint) (*models.Membership, error) {
+	var membership models.Membership
+	err := s.db.Where("organization_id = ? AND user_id = ?", orgID, userID).First(&membership).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOrganizationNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch membership: %w", err)
+	}
+	return &membership, nil
+}
+
+// ListMembers returns the members of an organization with their users
+func (s *OrganizationService) ListMembers(orgID uint) ([]*models.Membership, error) {
+	members := []*models.Membership{}
+	err := s.db.Preload("User").Where("organization_id = ?", orgID).Order("id ASC").Find(&members).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch members: %w", err)
+	}
+	return members, nil
+}
+
+// AddMember adds a user to an organization on behalf of the acting member.
+// Admins may add members; only owners may add other owners.
+func (s *OrganizationService) AddMember(orgID uint, actor *models.Membership, username string, role models.OrgRole) (*models.Membership, error) {
+	if !canGrant(actor, role) {
+		return nil, ErrOrgPermission
+	}
+
+	var user models.User
+	if err := s.db.Select("id", "username", "email").Where("username = ?", username).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch user: %w", err)
+	}
+
+	membership := &models.Membership{OrganizationID: orgID, UserID: user.ID, Role: role}
+	if err := s.db.Create(membership).Error; err != nil {
+		if isDuplicateKeyError(err) {
+			return nil, ErrAlreadyMember
+		}
+		return nil, fmt.Errorf("failed to add member: %w", err)
+	}
+	membership.User = &user
+	return membership, nil
+}
+
+// UpdateMemberRole changes the role of a member on behalf of the acting
+// member. Only owners may change owners or make others owner, and the last
+// owner can't be demoted.
+func (s *OrganizationService) UpdateMemberRole(orgID uint, actor *models.Membership, userID uint, role models.OrgRole) (*models.Membership, error) {
+	var membership *models.Membership
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		membership, err = s.changeableMember(tx, orgID, actor, userID)
+		if err != nil {
+			return err
+		}
+		if !canGrant(actor, role) {
+			return ErrOrgPermission
+		}
+		if membership.Role == models.OrgRoleOwner && role != models.OrgRoleOwner {
+			if err := checkOtherOwners(tx, orgID, userID); err != nil {
+				return err
+			}
+		}
+
+		membership.Role = role
+		return tx.Model(membership).Update("role", role).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return membership, nil
+}
+
+// RemoveMember removes a user from an organization on behalf of the acting
+// member. Members may always leave, except for the last owner.
+func (s *OrganizationService) RemoveMember(orgID uint, actor *models.Membership, userID uint) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var membership *models.Membership
+		var err error
+		if actor.UserID == userID {
+			membership = actor
+		} else if membership, err = s.changeableMember(tx, orgID, actor, userID); err != nil {
+			return err
+		}
+
+		if membership.Role == models.OrgRoleOwner {
+			if err := checkOtherOwners(tx, orgID, userID); err != nil {
+				return err
+			}
+		}
+		if err := tx.Delete(&models.Membership{}, membership.ID).Error; err != nil {
+			return fmt.Errorf("failed to remove member: %w", err)
+		}
+		return nil
+	})
+}
+
+// URLInOrganization reports whether a URL, including deleted ones, belongs to an organization
+func (s *OrganizationService) URLInOrganization(urlID, orgID uint) (bool, error) {
+	var count int64
+	err := s.db.Unscoped().Model(&models.URL{}).Where("id = ? AND organization_id = ?", urlID, orgID).Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to check URL: %w", err)
+	}
+	return count > 0, nil
+}
+
+// CrawlInOrganization reports whether a crawl belongs to a URL of an organization
+func (s *OrganizationService) CrawlInOrganization(crawlID, orgID uint) (bool, error) {
+	var count int64
+	err := s.db.Model(&models.Crawl{}).Joins("JOIN urls ON urls.id = crawls.url_id").
+		Where("crawls.id = ? AND urls.organization_id = ?", crawlID, orgID).Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to check crawl: %w", err)
+	}
+	return count > 0, nil
+}
+
+// FilterURLIDs returns the IDs of urlIDs that belong to an organization
+func (s *OrganizationService) FilterURLIDs(orgID uint, urlIDs []uint) ([]uint, error) {
+	ids := []uint{}
+	if len(urlIDs) == 0 {
+		return ids, nil
+	}
+	err := s.db.Model(&models.URL{}).Where("id IN ? AND organization_id = ?", urlIDs, orgID).Pluck("id", &ids).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to check URLs: %w", err)
+	}
+	return ids, nil
+}
+
+// changeableMember returns the membership of a user that the acting member
+// may change: admins manage non-owners, owners manage everyone
+func (s *OrganizationService) changeableMember(tx *gorm.DB, orgID uint, actor *models.Membership, userID uint) (*models.Membership, error) {
+	if !actor.Role.AtLeast(models.OrgRoleAdmin) {
+		return nil, ErrOrgPermission
+	}
+
+	var membership models.Membership
+	if err := tx.Where("organization_id = ? AND user_id = ?", orgID, userID).First(&membership).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrMemberNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch member: %w", err)
+	}
+	if membership.Role == models.OrgRoleOwner && actor.Role != models.OrgRoleOwner {
+		return nil, ErrOrgPermission
+	}
+	return &membership, nil
+}
+
+// canGrant reports whether the acting member may give someone a role
+func canGrant(actor *models.Membership, role models.OrgRole) bool {
+	if role == models.OrgRoleOwner {
+		return actor.Role == models.OrgRoleOwner
+	}
+	return actor.Role.AtLeast(models.OrgRoleAdmin)
+}
+
+// checkOtherOwners returns ErrLastOwner unless the organization has an owner besides userID
+func checkOtherOwners(tx *gorm.DB, orgID, userID uint) error {
+	var owners int64
+	err := tx.Model(&models.Membership{}).
+		Where("organization_id = ? AND role = ? AND user_id <> ?", orgID, models.OrgRoleOwner, userID).
+		Count(&owners).Error
+	if err != nil {
+		return fmt.Errorf("failed to count owners: %w", err)
+	}
+	if owners == 0 {
+		return ErrLastOwner
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"web-crawler-backend/internal/models"
+)
+
+func setupOrganizationTest(t *testing.T) (*OrganizationService, *gorm.DB, []*models.User) {
+	db := setupURLTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.Organization{}, &models.Membership{}))
+
+	var users []*models.User
+	for _, name := range []string{"alice", "bob", "carol"} {
+		user := &models.User{Username: name, Email: name + "@example.com", Password: "secret"}
+		require.NoError(t, db.Create(user).Error)
+		users = append(users, user)
+	}
+	return NewOrganizationService(db), db, users
+}
+
+func TestOrganizationService_Members(t *testing.T) {
+	service, _, users := setupOrganizationTest(t)
+	alice, bob, carol := users[0], users[1], users[2]
+
+	org, err := service.CreateOrganization(alice.ID, "Acme")
+	require.NoError(t, err)
+	assert.Equal(t, models.OrgRoleOwner, org.Role)
+
+	owner, err := service.GetMembership(org.ID, alice.ID)
+	require.NoError(t, err)
+	_, err = service.GetMembership(org.ID, bob.ID)
+	assert.ErrorIs(t, err, ErrOrganizationNotFound)
+
+	admin, err := service.AddMember(org.ID, owner, "bob", models.OrgRoleAdmin)
+	require.NoError(t, err)
+	assert.Equal(t, bob.ID, admin.UserID)
+	_, err = service.AddMember(org.ID, owner, "bob", models.OrgRoleMember)
+	assert.ErrorIs(t, err, ErrAlreadyMember)
+	_, err = service.AddMember(org.ID, owner, "nobody", models.OrgRoleMember)
+	assert.ErrorIs(t, err, ErrUserNotFound)
+
+	// Admins manage members, but not owners
+	_, err = service.AddMember(org.ID, admin, "carol", models.OrgRoleOwner)
+	assert.ErrorIs(t, err, ErrOrgPermission)
+	viewer, err := service.AddMember(org.ID, admin, "carol", models.OrgRoleViewer)
+	require.NoError(t, err)
+	_, err = service.UpdateMemberRole(org.ID, admin, alice.ID, models.OrgRoleMember)
+	assert.ErrorIs(t, err, ErrOrgPermission)
+	_, err = service.UpdateMemberRole(org.ID, viewer, carol.ID, models.OrgRoleMember)
+	assert.ErrorIs(t, err, ErrOrgPermission)
+
+	updated, err := service.UpdateMemberRole(org.ID, admin, carol.ID, models.OrgRoleMember)
+	require.NoError(t, err)
+	assert.Equal(t, models.OrgRoleMember, updated.Role)
+
+	members, err := service.ListMembers(org.ID)
+	require.NoError(t, err)
+	require.Len(t, members, 3)
+	assert.Equal(t, "alice", members[0].User.Username)
+
+	orgs, err := service.ListOrganizations(carol.ID)
+	require.NoError(t, err)
+	require.Len(t, orgs, 1)
+	assert.Equal(t, "Acme", orgs[0].Name)
+	assert.Equal(t, models.OrgRoleMember, orgs[0].Role)
+
+	// The last owner can neither leave nor be demoted
+	assert.ErrorIs(t, service.RemoveMember(org.ID, owner, alice.ID), ErrLastOwner)
+	_, err = service.UpdateMemberRole(org.ID, owner, alice.ID, models.OrgRoleAdmin)
+	assert.ErrorIs(t, err, ErrLastOwner)
+
+	// Once there is another owner, they can
+	_, err = service.UpdateMemberRole(org.ID, owner, bob.ID, models.OrgRoleOwner)
+	require.NoError(t, err)
+	require.NoError(t, service.RemoveMember(org.ID, owner, alice.ID))
+	orgs, err = service.ListOrganizations(alice.ID)
+	require.NoError(t, err)
+	assert.Empty(t, orgs)
+
+	// Members may leave on their own
+	member, err := service.GetMembership(org.ID, carol.ID)
+	require.NoError(t, err)
+	require.NoError(t, service.RemoveMember(org.ID, member, carol.ID))
+	assert.ErrorIs(t, service.RemoveMember(org.ID, admin, carol.ID), ErrMemberNotFound)
+}
+
+func TestURLService_WithOrganization(t *testing.T) {
+	orgService, db, users := setupOrganizationTest(t)
+	org, err := orgService.CreateOrganization(users[0].ID, "Acme")
+	require.NoError(t, err)
+
+	personal := NewURLService(db, &mockCrawlerService{}).WithOrganization(0)
+	team := NewURLService(db, &mockCrawlerService{}).WithOrganization(org.ID)
+
+	// The same URL can be added once per organization
+	mine, err := personal.CreateURL("https://example.com")
+	require.NoError(t, err)
+	shared, err := team.CreateURL("https://example.com")
+	require.NoError(t, err)
+	assert.NotEqual(t, mine.ID, shared.ID)
+	assert.Equal(t, org.ID, shared.OrganizationID)
+	again, err := team.CreateURL("https://example.com/")
+	require.NoError(t, err)
+	assert.Equal(t, shared.ID, again.ID)
+	_, err = personal.CreateURL("https://example.org")
+	require.NoError(t, err)
+
+	urls, total, err := team.GetURLs(10, 0, "", "", "created_at", "asc")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, urls, 1)
+	assert.Equal(t, shared.ID, urls[0].ID)
+
+	_, total, err = personal.GetURLs(10, 0, "", "", "created_at", "asc")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+
+	inOrg, err := orgService.URLInOrganization(shared.ID, org.ID)
+	require.NoError(t, err)
+	assert.True(t, inOrg)
+	inOrg, err = orgService.URLInOrganization(mine.ID, org.ID)
+	require.NoError(t, err)
+	assert.False(t, inOrg)
+	ids, err := orgService.FilterURLIDs(org.ID, []uint{mine.ID, shared.ID})
+	require.NoError(t, err)
+	assert.Equal(t, []uint{shared.ID}, ids)
+
+	// Bulk deletes leave URLs of other organizations alone
+	require.NoError(t, team.BulkDeleteURLs([]uint{mine.ID, shared.ID}))
+	var remaining int64
+	require.NoError(t, db.Model(&models.URL{}).Count(&remaining).Error)
+	assert.Equal(t, int64(2), remaining)
+}
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrInvalidCursor is returned for pagination cursors that are malformed or
+// were issued for a different sort order
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// pageCursor points just past the last row of a page for keyset pagination.
+// It records the sort key of that row together with its ID, which breaks
+// ties between rows with the same sort value.
+type pageCursor struct {
+	Sort  string `json:"s"`
+	Value string `json:"v"`
+	// Values holds the sort key of orders with several columns instead of Value
+	Values []string `json:"vs,omitempty"`
+	ID     uint     `json:"id"`
+}
+
+// encode returns the cursor in the opaque form handed to clients
+func (c pageCursor) encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor parses a client cursor. An empty cursor starts at the first page.
+func decodeCursor(raw, sort string) (*pageCursor, error) {
+	if raw == "" {
+		return nil, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var cursor pageCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == 0 {
+		return nil, ErrInvalidCursor
+	}
+	if cursor.Sort != sort {
+		return nil, fmt.Errorf("%w: it was issued for a different sort order", ErrInvalidCursor)
+	}
+	return &cursor, nil
+}
+
+// cursorTimeValue formats a timestamp sort key so it survives the round trip
+func cursorTimeValue(t time.Time) string {
+	return t.Format(time.RFC3339Nano)
+}
+
+// keysetColumn is a column of a keyset pagination sort order
+type keysetColumn struct {
+	Name  string
+	Order string // asc or desc
+	// Time columns are compared as timestamps
+	Time bool
+}
+
+// applyKeyset orders query by column and ID and skips every row up to and
+// including the cursor. Time columns are compared as timestamps.
+func applyKeyset(query *gorm.DB, table, column, order string, timeColumn bool, cursor *pageCursor) (*gorm.DB, error) {
+	return applyKeysetColumns(query, table, []keysetColumn{{Name: column, Order: order, Time: timeColumn}}, cursor)
+}
+
+// applyKeysetColumns orders query by the columns and ID, which follows the
+// order of the last column, and skips every row up to and including the
+// cursor. Cursors of a single column carry its value in Value, others in Values.
+func applyKeysetColumns(query *gorm.DB, table string, columns []keysetColumn, cursor *pageCursor) (*gorm.DB, error) {
+	orders := make([]string, 0, len(columns)+1)
+	for _, column := range columns {
+		orders = append(orders, fmt.Sprintf("%s.%s %s", table, column.Name, strings.ToUpper(column.Order)))
+	}
+	idOrder := strings.ToUpper(columns[len(columns)-1].Order)
+	orders = append(orders, fmt.Sprintf("%s.id %s", table, idOrder))
+	query = query.Order(strings.Join(orders, ", "))
+	if cursor == nil {
+		return query, nil
+	}
+
+	raw := cursor.Values
+	if len(columns) == 1 {
+		raw = []string{cursor.Value}
+	}
+	if len(raw) != len(columns) {
+		return nil, ErrInvalidCursor
+	}
+	values := make([]interface{}, len(columns))
+	for i, column := range columns {
+		values[i] = raw[i]
+		if column.Time {
+			t, err := time.Parse(time.RFC3339Nano, raw[i])
+			if err != nil {
+				return nil, ErrInvalidCursor
+			}
+			values[i] = t
+		}
+	}
+
+	// Rows after the cursor sort after it on the first column that differs,
+	// or on the ID if all columns are equal
+	comparison := func(order string) string {
+		if strings.EqualFold(order, "desc") {
+			return "<"
+		}
+		return ">"
+	}
+	var clauses []string
+	var args []interface{}
+	for i := 0; i <= len(columns); i++ {
+		var parts []string
+		for j := 0; j < i; j++ {
+			parts = append(parts, fmt.Sprintf("%s.%s = ?", table, columns[j].Name))
+			args = append(args, values[j])
+		}
+		if i < len(columns) {
+			parts = append(parts, fmt.Sprintf("%s.%s %s ?", table, columns[i].Name, comparison(columns[i].Order)))
+			args = append(args, values[i])
+		} else {
+			parts = append(parts, fmt.Sprintf("%s.id %s ?", table, comparison(idOrder)))
+			args = append(args, cursor.ID)
+		}
+		clauses = append(clauses, "("+strings.Join(parts, " AND ")+")")
+	}
+	return query.Where("("+strings.Join(clauses, " OR ")+")", args...), nil
+}
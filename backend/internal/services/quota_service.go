@@ -0,0 +1,242 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"web-crawler-backend/internal/models"
+)
+
+const (
+	// QuotaURLs limits the URLs a user added
+	QuotaURLs = "urls"
+	// QuotaCrawlsPerDay limits the crawls a user starts per UTC day
+	QuotaCrawlsPerDay = "crawls_per_day"
+	// QuotaPages limits the pages of a deep crawl
+	QuotaPages = "pages_per_crawl"
+)
+
+var (
+	// ErrQuotaExceeded matches every QuotaExceededError
+	ErrQuotaExceeded = errors.New("quota exceeded")
+	// ErrUserNotFound is returned for quotas of users that don't exist
+	ErrUserNotFound = errors.New("user not found")
+)
+
+// QuotaExceededError reports which quota a request would exceed
+type QuotaExceededError struct {
+	Quota string
+	Limit int
+	Used  int
+	// ResetsAt is when the quota frees up again, for quotas that reset
+	ResetsAt *time.Time
+}
+
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("%s quota exceeded: %d of %d used", e.Quota, e.Used, e.Limit)
+}
+
+// Is makes errors.Is(err, ErrQuotaExceeded) match
+func (e *QuotaExceededError) Is(target error) bool {
+	return target == ErrQuotaExceeded
+}
+
+// QuotaService enforces per-user limits on URLs, daily crawls and deep crawl
+// pages. Users get the default limits unless an admin put them on another
+// plan; admins themselves have no limits. A nil *QuotaService limits nothing.
+type QuotaService struct {
+	db       *gorm.DB
+	defaults models.QuotaLimits
+	now      func() time.Time
+}
+
+// NewQuotaService creates the service with the limits of the default plan
+func NewQuotaService(db *gorm.DB, defaults models.QuotaLimits) *QuotaService {
+	return &QuotaService{db: db, defaults: defaults, now: time.Now}
+}
+
+// limits returns the plan and limits of a user; admins get no limits
+func (s *QuotaService) limits(userID uint) (string, models.QuotaLimits, bool, error) {
+	var user models.User
+	if err := s.db.Select("id", "is_admin").First(&user, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", models.QuotaLimits{}, false, ErrUserNotFound
+		}
+		return "", models.QuotaLimits{}, false, fmt.Errorf("failed to fetch user: %w", err)
+	}
+	if user.IsAdmin {
+		return "admin", models.QuotaLimits{}, true, nil
+	}
+
+	var quota models.UserQuota
+	err := s.db.Where("user_id = ?", userID).Limit(1).Find(&quota).Error
+	if err != nil {
+		return "", models.QuotaLimits{}, false, fmt.Errorf("failed to fetch quota: %w", err)
+	}
+	if quota.UserID == 0 {
+		return models.DefaultPlan, s.defaults, false, nil
+	}
+
+	limits := s.defaults
+	if quota.MaxURLs != nil {
+		limits.MaxURLs = *quota.MaxURLs
+	}
+	if quota.MaxCrawlsPerDay != nil {
+		limits.MaxCrawlsPerDay = *quota.MaxCrawlsPerDay
+	}
+	if quota.MaxPages != nil {
+		limits.MaxPages = *quota.MaxPages
+	}
+	return quota.Plan, limits, false, nil
+}
+
+// day returns the UTC day crawls are counted for and when it ends
+func (s *QuotaService) day() (string, time.Time) {
+	now := s.now().UTC()
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	return start.Format("2006-01-02"), start.AddDate(0, 0, 1)
+}
+
+// GetUsage returns the limits of a user and how much of them is used
+func (s *QuotaService) GetUsage(userID uint) (*models.QuotaUsage, error) {
+	plan, limits, unlimited, err := s.limits(userID)
+	if err != nil {
+		return nil, err
+	}
+	day, resetAt := s.day()
+	usage := &models.QuotaUsage{Plan: plan, Unlimited: unlimited, Limits: limits, CrawlsResetAt: resetAt}
+
+	if err := s.db.Model(&models.URL{}).Where("user_id = ?", userID).Count(&usage.URLs).Error; err != nil {
+		return nil, fmt.Errorf("failed to count URLs: %w", err)
+	}
+	if err := s.db.Model(&models.CrawlUsage{}).Where("user_id = ? AND day = ?", userID, day).
+		Select("COALESCE(SUM(crawls), 0)").Scan(&usage.CrawlsToday).Error; err != nil {
+		return nil, fmt.Errorf("failed to count crawls: %w", err)
+	}
+	return usage, nil
+}
+
+// CheckURLs returns a QuotaExceededError if the user may not add another URL
+func (s *QuotaService) CheckURLs(userID uint) error {
+	if s == nil || userID == 0 {
+		return nil
+	}
+	_, limits, _, err := s.limits(userID)
+	if err != nil || limits.MaxURLs == 0 {
+		return err
+	}
+
+	var count int64
+	if err := s.db.Model(&models.URL{}).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to count URLs: %w", err)
+	}
+	if count >= int64(limits.MaxURLs) {
+		return &QuotaExceededError{Quota: QuotaURLs, Limit: limits.MaxURLs, Used: int(count)}
+	}
+	return nil
+}
+
+// ReserveCrawls counts n crawls against the daily quota of the user, or
+// returns a QuotaExceededError and counts nothing if they don't fit
+func (s *QuotaService) ReserveCrawls(userID uint, n int) error {
+	if s == nil || userID == 0 || n <= 0 {
+		return nil
+	}
+	_, limits, _, err := s.limits(userID)
+	if err != nil || limits.MaxCrawlsPerDay == 0 {
+		return err
+	}
+
+	day, resetAt := s.day()
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		// The upsert locks the row, so concurrent reservations are counted one after another
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "day"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{"crawls": gorm.Expr("crawl_usages.crawls + ?", n)}),
+		}).Create(&models.CrawlUsage{UserID: userID, Day: day, Crawls: n}).Error
+		if err != nil {
+			return fmt.Errorf("failed to count crawls: %w", err)
+		}
+
+		var usage models.CrawlUsage
+		if err := tx.Where("user_id = ? AND day = ?", userID, day).First(&usage).Error; err != nil {
+			return fmt.Errorf("failed to count crawls: %w", err)
+		}
+		if usage.Crawls > limits.MaxCrawlsPerDay {
+			return &QuotaExceededError{
+				Quota:    QuotaCrawlsPerDay,
+				Limit:    limits.MaxCrawlsPerDay,
+				Used:     usage.Crawls - n,
+				ResetsAt: &resetAt,
+			}
+		}
+		return nil
+	})
+}
+
+// CheckPages returns a QuotaExceededError if deep crawls of the user's URLs
+// may not fetch that many pages
+func (s *QuotaService) CheckPages(userID uint, pages int) error {
+	if s == nil || userID == 0 {
+		return nil
+	}
+	_, limits, _, err := s.limits(userID)
+	if err != nil || limits.MaxPages == 0 {
+		return err
+	}
+	if pages > limits.MaxPages {
+		return &QuotaExceededError{Quota: QuotaPages, Limit: limits.MaxPages, Used: pages}
+	}
+	return nil
+}
+
+// PageLimit returns the page limit of deep crawls of the user's URLs, or 0 if there is none
+func (s *QuotaService) PageLimit(userID uint) (int, error) {
+	if s == nil || userID == 0 {
+		return 0, nil
+	}
+	_, limits, _, err := s.limits(userID)
+	if errors.Is(err, ErrUserNotFound) {
+		return 0, nil
+	}
+	return limits.MaxPages, err
+}
+
+// SetUserQuota puts a user on a plan with its own limits
+func (s *QuotaService) SetUserQuota(userID uint, req models.UserQuotaRequest) (*models.UserQuota, error) {
+	var user models.User
+	if err := s.db.Select("id").First(&user, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch user: %w", err)
+	}
+
+	quota := &models.UserQuota{
+		UserID:          userID,
+		Plan:            req.Plan,
+		MaxURLs:         req.MaxURLs,
+		MaxCrawlsPerDay: req.MaxCrawlsPerDay,
+		MaxPages:        req.MaxPages,
+	}
+	err := s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"plan", "max_urls", "max_crawls_per_day", "max_pages", "updated_at"}),
+	}).Create(quota).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to save quota: %w", err)
+	}
+	return quota, nil
+}
+
+// DeleteUserQuota puts a user back on the default plan
+func (s *QuotaService) DeleteUserQuota(userID uint) error {
+	if err := s.db.Where("user_id = ?", userID).Delete(&models.UserQuota{}).Error; err != nil {
+		return fmt.Errorf("failed to delete quota: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"web-crawler-backend/internal/models"
+)
+
+func setupQuotaTest(t *testing.T, defaults models.QuotaLimits) (*QuotaService, *gorm.DB, *models.User) {
+	db := setupURLTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.UserQuota{}, &models.CrawlUsage{}))
+	user := &models.User{Username: "alice", Email: "alice@example.com", Password: "secret"}
+	require.NoError(t, db.Create(user).Error)
+	return NewQuotaService(db, defaults), db, user
+}
+
+func TestQuotaService_URLs(t *testing.T) {
+	service, db, user := setupQuotaTest(t, models.QuotaLimits{MaxURLs: 1})
+
+	require.NoError(t, service.CheckURLs(user.ID))
+	require.NoError(t, db.Create(&models.URL{URL: "https://example.com", UserID: &user.ID}).Error)
+
+	err := service.CheckURLs(user.ID)
+	require.ErrorIs(t, err, ErrQuotaExceeded)
+	var quotaErr *QuotaExceededError
+	require.ErrorAs(t, err, &quotaErr)
+	assert.Equal(t, QuotaURLs, quotaErr.Quota)
+	assert.Equal(t, 1, quotaErr.Limit)
+	assert.Equal(t, 1, quotaErr.Used)
+	assert.Nil(t, quotaErr.ResetsAt)
+
+	// A bigger plan lifts the limit
+	maxURLs := 5
+	_, err = service.SetUserQuota(user.ID, models.UserQuotaRequest{Plan: "pro", MaxURLs: &maxURLs})
+	require.NoError(t, err)
+	require.NoError(t, service.CheckURLs(user.ID))
+
+	usage, err := service.GetUsage(user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "pro", usage.Plan)
+	assert.Equal(t, 5, usage.Limits.MaxURLs)
+	assert.Equal(t, int64(1), usage.URLs)
+
+	require.NoError(t, service.DeleteUserQuota(user.ID))
+	assert.ErrorIs(t, service.CheckURLs(user.ID), ErrQuotaExceeded)
+
+	_, err = service.SetUserQuota(user.ID+1, models.UserQuotaRequest{Plan: "pro"})
+	assert.ErrorIs(t, err, ErrUserNotFound)
+}
+
+func TestQuotaService_CrawlsPerDay(t *testing.T) {
+	service, _, user := setupQuotaTest(t, models.QuotaLimits{MaxCrawlsPerDay: 3})
+	now := time.Date(2024, 5, 1, 22, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	require.NoError(t, service.ReserveCrawls(user.ID, 2))
+
+	// Reservations that don't fit count nothing
+	err := service.ReserveCrawls(user.ID, 2)
+	var quotaErr *QuotaExceededError
+	require.ErrorAs(t, err, &quotaErr)
+	assert.Equal(t, QuotaCrawlsPerDay, quotaErr.Quota)
+	assert.Equal(t, 2, quotaErr.Used)
+	assert.Equal(t, time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC), *quotaErr.ResetsAt)
+
+	require.NoError(t, service.ReserveCrawls(user.ID, 1))
+	usage, err := service.GetUsage(user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 3, usage.CrawlsToday)
+
+	// The quota resets at midnight UTC
+	now = now.Add(3 * time.Hour)
+	require.NoError(t, service.ReserveCrawls(user.ID, 3))
+}
+
+func TestQuotaService_Pages(t *testing.T) {
+	service, db, user := setupQuotaTest(t, models.QuotaLimits{MaxPages: 20})
+
+	require.NoError(t, service.CheckPages(user.ID, 20))
+	assert.ErrorIs(t, service.CheckPages(user.ID, 21), ErrQuotaExceeded)
+
+	// Deep crawls of the user's URLs are capped at the quota
+	crawler := NewCrawlerService(db).WithQuota(service)
+	assert.Equal(t, 20, crawler.pageLimit(&models.URL{MaxPages: 50, UserID: &user.ID}))
+	assert.Equal(t, 10, crawler.pageLimit(&models.URL{MaxPages: 10, UserID: &user.ID}))
+	assert.Equal(t, 50, crawler.pageLimit(&models.URL{MaxPages: 50}))
+}
+
+func TestQuotaService_Unlimited(t *testing.T) {
+	service, db, user := setupQuotaTest(t, models.QuotaLimits{MaxURLs: 1, MaxCrawlsPerDay: 1, MaxPages: 1})
+	require.NoError(t, db.Model(user).Update("is_admin", true).Error)
+
+	require.NoError(t, db.Create(&models.URL{URL: "https://example.com", UserID: &user.ID}).Error)
+	assert.NoError(t, service.CheckURLs(user.ID))
+	assert.NoError(t, service.ReserveCrawls(user.ID, 10))
+	assert.NoError(t, service.CheckPages(user.ID, 100))
+	usage, err := service.GetUsage(user.ID)
+	require.NoError(t, err)
+	assert.True(t, usage.Unlimited)
+
+	// A nil service and requests without a user are not limited either
+	var disabled *QuotaService
+	assert.NoError(t, disabled.CheckURLs(user.ID))
+	assert.NoError(t, disabled.ReserveCrawls(user.ID, 1))
+	assert.NoError(t, service.ReserveCrawls(0, 1))
+}
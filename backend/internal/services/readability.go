@@ -0,0 +1,72 @@
+package services
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// readability holds the text statistics of a page's visible text
+type readability struct {
+	Words     int
+	Sentences int
+	Syllables int
+}
+
+// measureReadability counts the words, sentences and syllables of the
+// visible words of a page. Tokens without letters or digits, such as "|" or
+// "—", aren't words. A sentence ends with a word ending in ., ! or ?; text
+// without any counts as one sentence.
+func measureReadability(tokens []string) readability {
+	var r readability
+	for _, token := range tokens {
+		word := strings.TrimFunc(token, func(c rune) bool {
+			return !unicode.IsLetter(c) && !unicode.IsDigit(c)
+		})
+		if word == "" {
+			continue
+		}
+		r.Words++
+		r.Syllables += countSyllables(word)
+
+		if end := strings.TrimRight(token, `"')]}»”’`); strings.HasSuffix(end, ".") || strings.HasSuffix(end, "!") || strings.HasSuffix(end, "?") {
+			r.Sentences++
+		}
+	}
+	if r.Words > 0 && r.Sentences == 0 {
+		r.Sentences = 1
+	}
+	return r
+}
+
+// FleschReadingEase returns the Flesch reading ease score, roughly 0 (very
+// hard) to 100 (very easy), rounded to one decimal. Pages without words have
+// no score.
+func (r readability) FleschReadingEase() *float64 {
+	if r.Words == 0 {
+		return nil
+	}
+	score := 206.835 - 1.015*float64(r.Words)/float64(r.Sentences) - 84.6*float64(r.Syllables)/float64(r.Words)
+	score = math.Round(score*10) / 10
+	return &score
+}
+
+// countSyllables estimates the syllables of an English word from its vowel
+// groups, not counting a silent final e. Numbers and words without vowels
+// count as one syllable.
+func countSyllables(word string) int {
+	word = strings.ToLower(word)
+	count := 0
+	previousVowel := false
+	for _, c := range word {
+		vowel := strings.ContainsRune("aeiouy", c)
+		if vowel && !previousVowel {
+			count++
+		}
+		previousVowel = vowel
+	}
+	if count > 1 && strings.HasSuffix(word, "e") && !strings.HasSuffix(word, "le") {
+		count--
+	}
+	return max(count, 1)
+}
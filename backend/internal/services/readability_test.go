@@ -0,0 +1,56 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/html"
+
+	"web-crawler-backend/internal/models"
+)
+
+func TestCountSyllables(t *testing.T) {
+	for word, want := range map[string]int{
+		"the": 1, "cat": 1, "make": 1, "table": 2, "reading": 2, "readability": 5, "rhythm": 1, "2024": 1,
+	} {
+		assert.Equal(t, want, countSyllables(word), word)
+	}
+}
+
+func TestReadability(t *testing.T) {
+	t.Run("scores simple and complex text", func(t *testing.T) {
+		simple := measureReadability(strings.Fields("The cat sat on the mat. The dog ran. It was fun!"))
+		assert.Equal(t, readability{Words: 12, Sentences: 3, Syllables: 12}, simple)
+
+		complex := measureReadability(strings.Fields("Organizational interdependencies necessitate comprehensive institutional considerations."))
+		assert.Equal(t, 1, complex.Sentences)
+
+		assert.Greater(t, *simple.FleschReadingEase(), 100.0)
+		assert.Less(t, *complex.FleschReadingEase(), 0.0)
+	})
+
+	t.Run("ignores punctuation tokens", func(t *testing.T) {
+		stats := measureReadability(strings.Fields(`Home | Shop — "Sale ends today."`))
+		assert.Equal(t, 5, stats.Words)
+		assert.Equal(t, 1, stats.Sentences)
+		assert.Nil(t, measureReadability(nil).FleschReadingEase())
+	})
+
+	t.Run("extracted from the visible text", func(t *testing.T) {
+		doc, err := html.Parse(strings.NewReader(`<html><head><title>Hi</title><style>p { color: red }</style></head>
+			<body><p>The cat sat on the mat.</p><script>var words = "not counted";</script></body></html>`))
+		require.NoError(t, err)
+
+		data, ok := NewCrawlerService(nil).parsePageData(doc, &models.URL{URL: "https://example.com"})
+		require.True(t, ok)
+		assert.Equal(t, 7, data.WordCount) // Including the title
+		require.NotNil(t, data.Readability)
+
+		crawl := &models.Crawl{}
+		applyCrawlData(crawl, data)
+		assert.Equal(t, 7, crawl.WordCount)
+		assert.Equal(t, data.Readability, crawl.ReadabilityScore)
+	})
+}
@@ -0,0 +1,476 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"path"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+	"gorm.io/gorm"
+
+	"web-crawler-backend/internal/models"
+	"web-crawler-backend/internal/storage"
+)
+
+const (
+	// maxBundleURLs caps how many sites a single bundle may contain
+	maxBundleURLs = 500
+	// maxReportBrokenLinks caps how many broken links are listed in one site report
+	maxReportBrokenLinks = 100
+	// reportWorkers is the number of site reports rendered in parallel
+	reportWorkers = 4
+)
+
+// ErrBundleNotFound is returned when a report bundle doesn't exist for the caller
+var ErrBundleNotFound = errors.New("report bundle not found")
+
+type ReportService struct {
+	db *gorm.DB
+	// store keeps the generated archives
+	store storage.Storage
+	// organizationID limits bundles to the URLs of one organization; nil allows any URL
+	organizationID *uint
+}
+
+func NewReportService(db *gorm.DB, store storage.Storage) *ReportService {
+	return &ReportService{db: db, store: store}
+}
+
+// WithContext returns a copy of the service whose queries and downloads
+// belong to ctx
+func (s *ReportService) WithContext(ctx context.Context) *ReportService {
+	copied := *s
+	copied.db = s.db.WithContext(ctx)
+	return &copied
+}
+
+// WithOrganization returns a copy of the service that only bundles URLs of an
+// organization; 0 allows the URLs outside any organization
+func (s *ReportService) WithOrganization(organizationID uint) *ReportService {
+	copied := *s
+	copied.organizationID = &organizationID
+	return &copied
+}
+
+// siteReport holds the data rendered for a single URL
+type siteReport struct {
+	URL           models.URL
+	Crawl         *models.Crawl
+	HeadingCounts models.HeadingCounts
+	BrokenLinks   []models.Link
+	// AnnotatedBrokenLinks counts broken links that were annotated as known
+	AnnotatedBrokenLinks int
+}
+
+// CreateBundle queues a report bundle for the given URLs and starts generating it
+func (s *ReportService) CreateBundle(userID uint, urlIDs []uint) (*models.ReportBundle, error) {
+	urlIDs = uniqueIDs(urlIDs)
+	if len(urlIDs) == 0 {
+		return nil, errors.New("at least one URL ID must be provided")
+	}
+	if len(urlIDs) > maxBundleURLs {
+		return nil, fmt.Errorf("a bundle may contain at most %d URLs", maxBundleURLs)
+	}
+
+	var found int64
+	query := s.db.Model(&models.URL{}).Where("id IN ?", urlIDs)
+	if s.organizationID != nil {
+		query = query.Where("organization_id = ?", *s.organizationID)
+	}
+	if err := query.Count(&found).Error; err != nil {
+		return nil, fmt.Errorf("failed to verify URLs: %w", err)
+	}
+	if int(found) != len(urlIDs) {
+		return nil, errors.New("one or more URLs not found")
+	}
+
+	idsJSON, _ := json.Marshal(urlIDs)
+	bundle := &models.ReportBundle{
+		UserID:   userID,
+		Status:   "queued",
+		URLIDs:   string(idsJSON),
+		URLCount: len(urlIDs),
+	}
+	if err := s.db.Create(bundle).Error; err != nil {
+		return nil, fmt.Errorf("failed to create report bundle: %w", err)
+	}
+
+	go s.generateBundle(bundle.ID)
+
+	return bundle, nil
+}
+
+// GetBundle returns a bundle owned by the user
+func (s *ReportService) GetBundle(userID, bundleID uint) (*models.ReportBundle, error) {
+	var bundle models.ReportBundle
+	if err := s.db.Where("id = ? AND user_id = ?", bundleID, userID).First(&bundle).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrBundleNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch report bundle: %w", err)
+	}
+	return &bundle, nil
+}
+
+// generateBundle renders every site report and writes the zip archive
+func (s *ReportService) generateBundle(bundleID uint) {
+	var bundle models.ReportBundle
+	if err := s.db.First(&bundle, bundleID).Error; err != nil {
+		log.Printf("Failed to find report bundle %d: %v", bundleID, err)
+		return
+	}
+
+	now := time.Now()
+	bundle.Status = "running"
+	bundle.StartedAt = &now
+	s.db.Save(&bundle)
+
+	key, err := s.writeBundle(&bundle)
+
+	completed := time.Now()
+	bundle.CompletedAt = &completed
+	if err != nil {
+		log.Printf("Failed to generate report bundle %d: %v", bundleID, err)
+		bundle.Status = "error"
+		bundle.ErrorMessage = err.Error()
+	} else {
+		bundle.Status = "completed"
+		bundle.StorageKey = key
+	}
+	s.db.Save(&bundle)
+}
+
+// OpenBundle opens the archive of a completed bundle and returns its size
+func (s *ReportService) OpenBundle(bundle *models.ReportBundle) (io.ReadCloser, int64, error) {
+	// Bundles made before storage keys were recorded hold a file path below
+	// the reports directory, whose base name is the key
+	file, size, err := s.store.Get(s.db.Statement.Context, path.Base(bundle.StorageKey))
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, 0, ErrBundleNotFound
+	}
+	return file, size, err
+}
+
+// writeBundle builds the archive for a bundle, stores it and returns its key
+func (s *ReportService) writeBundle(bundle *models.ReportBundle) (string, error) {
+	var urlIDs []uint
+	if err := json.Unmarshal([]byte(bundle.URLIDs), &urlIDs); err != nil {
+		return "", fmt.Errorf("invalid URL list: %w", err)
+	}
+
+	reports, err := s.loadSiteReports(urlIDs)
+	if err != nil {
+		return "", err
+	}
+
+	// Render PDFs in parallel, then write them to the archive in a stable order
+	pdfs := make([][]byte, len(reports))
+	errs := make([]error, len(reports))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < reportWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				pdfs[i], errs[i] = renderSitePDF(reports[i])
+			}
+		}()
+	}
+	for i := range reports {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return "", fmt.Errorf("failed to render report for %s: %w", reports[i].URL.URL, err)
+		}
+	}
+
+	var file bytes.Buffer
+	archive := zip.NewWriter(&file)
+	for i, report := range reports {
+		name := fmt.Sprintf("sites/%d-%s.pdf", report.URL.ID, sanitizeFileName(hostOf(report.URL.URL)))
+		w, err := archive.Create(name)
+		if err != nil {
+			return "", fmt.Errorf("failed to add %s to bundle: %w", name, err)
+		}
+		if _, err := w.Write(pdfs[i]); err != nil {
+			return "", fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+
+	summary, err := archive.Create("summary.csv")
+	if err != nil {
+		return "", fmt.Errorf("failed to add summary to bundle: %w", err)
+	}
+	if err := writeSummaryCSV(summary, reports); err != nil {
+		return "", fmt.Errorf("failed to write summary: %w", err)
+	}
+
+	brokenLinks, err := archive.Create("broken_links.csv")
+	if err != nil {
+		return "", fmt.Errorf("failed to add broken links to bundle: %w", err)
+	}
+	if err := writeBrokenLinksCSV(brokenLinks, reports); err != nil {
+		return "", fmt.Errorf("failed to write broken links: %w", err)
+	}
+
+	if err := archive.Close(); err != nil {
+		return "", fmt.Errorf("failed to finalize bundle: %w", err)
+	}
+
+	key := fmt.Sprintf("report-bundle-%d.zip", bundle.ID)
+	if err := s.store.Put(context.Background(), key, file.Bytes(), "application/zip"); err != nil {
+		return "", fmt.Errorf("failed to store bundle: %w", err)
+	}
+	return key, nil
+}
+
+// loadSiteReports loads each URL with its latest crawl and broken links
+func (s *ReportService) loadSiteReports(urlIDs []uint) ([]*siteReport, error) {
+	var urls []models.URL
+	if err := s.db.Where("id IN ?", urlIDs).Order("id ASC").Find(&urls).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch URLs: %w", err)
+	}
+
+	reports := make([]*siteReport, 0, len(urls))
+	for _, u := range urls {
+		report := &siteReport{URL: u}
+
+		var crawl models.Crawl
+		err := s.db.Where("url_id = ?", u.ID).Order("created_at DESC").First(&crawl).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("failed to fetch crawl for URL %d: %w", u.ID, err)
+		}
+		if err == nil {
+			report.Crawl = &crawl
+			if crawl.HeadingCounts != "" {
+				json.Unmarshal([]byte(crawl.HeadingCounts), &report.HeadingCounts)
+			}
+			if err := s.db.Where("crawl_id = ? AND is_accessible = ?", crawl.ID, false).
+				Limit(maxReportBrokenLinks).Find(&report.BrokenLinks).Error; err != nil {
+				return nil, fmt.Errorf("failed to fetch links for URL %d: %w", u.ID, err)
+			}
+
+			annotations, err := loadAnnotations(s.db, u.ID, models.FindingBrokenLink)
+			if err != nil {
+				return nil, err
+			}
+			if len(annotations) > 0 {
+				keys := make([]string, 0, len(annotations))
+				for key := range annotations {
+					keys = append(keys, key)
+				}
+				var annotated int64
+				if err := s.db.Model(&models.Link{}).Where("crawl_id = ? AND is_accessible = ? AND link_url IN ?", crawl.ID, false, keys).
+					Count(&annotated).Error; err != nil {
+					return nil, fmt.Errorf("failed to count annotated links for URL %d: %w", u.ID, err)
+				}
+				report.AnnotatedBrokenLinks = int(annotated)
+			}
+			for i := range report.BrokenLinks {
+				report.BrokenLinks[i].Annotation = annotations[report.BrokenLinks[i].LinkURL]
+			}
+		}
+
+		reports = append(reports, report)
+	}
+
+	return reports, nil
+}
+
+// renderSitePDF renders a single site report as a PDF document
+func renderSitePDF(report *siteReport) ([]byte, error) {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.SetTitle(report.URL.URL, true)
+	pdf.AddPage()
+
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.MultiCell(0, 8, tr(report.URL.URL), "", "L", false)
+	pdf.SetFont("Helvetica", "", 11)
+	if report.URL.Title != "" {
+		pdf.MultiCell(0, 6, tr(report.URL.Title), "", "L", false)
+	}
+	pdf.Ln(4)
+
+	row := func(label, value string) {
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.CellFormat(50, 6, label, "", 0, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.MultiCell(0, 6, tr(value), "", "L", false)
+	}
+
+	row("Status", report.URL.Status)
+	row("HTML version", report.URL.HTMLVersion)
+	row("Login form", strconv.FormatBool(report.URL.HasLoginForm))
+
+	if report.Crawl == nil {
+		pdf.Ln(4)
+		pdf.MultiCell(0, 6, "This URL has not been crawled yet.", "", "L", false)
+	} else {
+		crawl := report.Crawl
+		if crawl.CompletedAt != nil {
+			row("Last crawled", crawl.CompletedAt.Format(time.RFC1123))
+		}
+		if crawl.ErrorMessage != "" {
+			row("Crawl error", crawl.ErrorMessage)
+		}
+		row("Internal links", strconv.Itoa(crawl.InternalLinks))
+		row("External links", strconv.Itoa(crawl.ExternalLinks))
+		row("Broken links", strconv.Itoa(crawl.BrokenLinks))
+		if report.AnnotatedBrokenLinks > 0 {
+			row("Annotated", strconv.Itoa(report.AnnotatedBrokenLinks))
+		}
+
+		h := report.HeadingCounts
+		row("Headings", fmt.Sprintf("H1: %d  H2: %d  H3: %d  H4: %d  H5: %d  H6: %d", h.H1, h.H2, h.H3, h.H4, h.H5, h.H6))
+
+		if m := crawl.ResponseMetrics; m.Protocol != "" {
+			pdf.Ln(4)
+			pdf.SetFont("Helvetica", "B", 12)
+			pdf.CellFormat(0, 8, "Performance", "", 1, "L", false, 0, "")
+			row("Time to first byte", fmt.Sprintf("%d ms", m.TTFBMs))
+			row("Download", fmt.Sprintf("%d ms, %d bytes", m.DownloadMs, m.ResponseBytes))
+			protocol := m.Protocol
+			if m.HTTP3Advertised {
+				protocol += ", HTTP/3 advertised"
+			}
+			row("Protocol", protocol)
+			compression := m.ContentEncoding
+			if compression == "" {
+				compression = "none"
+			}
+			row("Compression", compression)
+			row("Keep-alive", strconv.FormatBool(m.KeepAlive))
+		}
+
+		if len(report.BrokenLinks) > 0 {
+			pdf.Ln(4)
+			pdf.SetFont("Helvetica", "B", 12)
+			pdf.CellFormat(0, 8, "Broken links", "", 1, "L", false, 0, "")
+			pdf.SetFont("Helvetica", "", 9)
+			for _, link := range report.BrokenLinks {
+				line := fmt.Sprintf("[%d] %s", link.StatusCode, link.LinkURL)
+				if link.FoundOnURL != "" && link.FoundOnURL != report.URL.URL {
+					line += " on " + link.FoundOnURL
+				}
+				if link.Annotation != nil {
+					// Annotated findings are greyed out with their reason
+					pdf.SetTextColor(128, 128, 128)
+					line += fmt.Sprintf(" (%s: %s)", link.Annotation.Status, link.Annotation.Reason)
+				}
+				pdf.MultiCell(0, 5, tr(line), "", "L", false)
+				pdf.SetTextColor(0, 0, 0)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeSummaryCSV writes one row per site with its key metrics
+func writeSummaryCSV(w io.Writer, reports []*siteReport) error {
+	return csv.NewWriter(w).WriteAll(summaryRows(reports))
+}
+
+// summaryRows returns a header and one row per site with its key metrics
+func summaryRows(reports []*siteReport) [][]string {
+	rows := [][]string{{"id", "url", "title", "status", "html_version", "has_login_form", "internal_links", "external_links", "broken_links", "annotated_broken_links", "last_crawled"}}
+
+	for _, report := range reports {
+		record := []string{
+			strconv.FormatUint(uint64(report.URL.ID), 10),
+			report.URL.URL,
+			report.URL.Title,
+			report.URL.Status,
+			report.URL.HTMLVersion,
+			strconv.FormatBool(report.URL.HasLoginForm),
+			"", "", "", "", "",
+		}
+		if crawl := report.Crawl; crawl != nil {
+			record[6] = strconv.Itoa(crawl.InternalLinks)
+			record[7] = strconv.Itoa(crawl.ExternalLinks)
+			record[8] = strconv.Itoa(crawl.BrokenLinks)
+			record[9] = strconv.Itoa(report.AnnotatedBrokenLinks)
+			if crawl.CompletedAt != nil {
+				record[10] = crawl.CompletedAt.Format(time.RFC3339)
+			}
+		}
+		rows = append(rows, record)
+	}
+	return rows
+}
+
+// writeBrokenLinksCSV writes one row per broken link with the page it was found on
+func writeBrokenLinksCSV(w io.Writer, reports []*siteReport) error {
+	return csv.NewWriter(w).WriteAll(brokenLinkRows(reports))
+}
+
+// brokenLinkRows returns a header and one row per broken link with the page it was found on
+func brokenLinkRows(reports []*siteReport) [][]string {
+	rows := [][]string{{"url_id", "url", "link_url", "status_code", "found_on_url", "annotation"}}
+
+	for _, report := range reports {
+		for _, link := range report.BrokenLinks {
+			annotation := ""
+			if link.Annotation != nil {
+				annotation = link.Annotation.Status
+			}
+			rows = append(rows, []string{
+				strconv.FormatUint(uint64(report.URL.ID), 10),
+				report.URL.URL,
+				link.LinkURL,
+				strconv.Itoa(link.StatusCode),
+				link.FoundOnURL,
+				annotation,
+			})
+		}
+	}
+	return rows
+}
+
+// uniqueIDs removes duplicate IDs while keeping their order
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	result := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+// sanitizeFileName keeps only characters that are safe in archive entry names
+func sanitizeFileName(name string) string {
+	buf := make([]byte, 0, len(name))
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '.', c == '-':
+			buf = append(buf, c)
+		default:
+			buf = append(buf, '_')
+		}
+	}
+	return string(buf)
+}
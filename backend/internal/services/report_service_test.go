@@ -0,0 +1,158 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"web-crawler-backend/internal/models"
+	"web-crawler-backend/internal/storage"
+)
+
+func setupReportTest(t *testing.T) (*ReportService, *gorm.DB) {
+	db := setupURLTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.ReportBundle{}))
+
+	// Bundles are generated in a goroutine; keep it on the same in-memory database
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	return NewReportService(db, storage.NewLocal(t.TempDir())), db
+}
+
+func waitForBundle(t *testing.T, service *ReportService, userID, bundleID uint) *models.ReportBundle {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		bundle, err := service.GetBundle(userID, bundleID)
+		require.NoError(t, err)
+		if bundle.Status == "completed" || bundle.Status == "error" {
+			return bundle
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("report bundle %d did not finish", bundleID)
+	return nil
+}
+
+func TestReportService_CreateBundle(t *testing.T) {
+	t.Run("generates zip with per-site PDFs and summary", func(t *testing.T) {
+		service, db := setupReportTest(t)
+
+		crawled := &models.URL{URL: "https://example.com", Title: "Example", Status: "completed"}
+		pending := &models.URL{URL: "https://pending.com", Status: "pending"}
+		require.NoError(t, db.Create(crawled).Error)
+		require.NoError(t, db.Create(pending).Error)
+
+		completedAt := time.Now()
+		crawl := &models.Crawl{
+			URLID:         crawled.ID,
+			Status:        "completed",
+			CompletedAt:   &completedAt,
+			InternalLinks: 3,
+			ExternalLinks: 2,
+			BrokenLinks:   1,
+			HeadingCounts: `{"h1":1,"h2":2}`,
+		}
+		require.NoError(t, db.Create(crawl).Error)
+		require.NoError(t, db.Create(&models.Link{
+			URLID:        crawled.ID,
+			CrawlID:      crawl.ID,
+			LinkURL:      "https://example.com/missing",
+			FoundOnURL:   "https://example.com/docs",
+			LinkType:     "internal",
+			StatusCode:   404,
+			IsAccessible: false,
+		}).Error)
+		require.NoError(t, db.Create(&models.FindingAnnotation{
+			URLID:       crawled.ID,
+			FindingType: models.FindingBrokenLink,
+			FindingKey:  "https://example.com/missing",
+			Status:      models.AnnotationIgnored,
+			Reason:      "Page intentionally removed",
+		}).Error)
+
+		bundle, err := service.CreateBundle(7, []uint{crawled.ID, pending.ID, crawled.ID})
+		require.NoError(t, err)
+		assert.Equal(t, 2, bundle.URLCount)
+
+		bundle = waitForBundle(t, service, 7, bundle.ID)
+		require.Equal(t, "completed", bundle.Status, bundle.ErrorMessage)
+
+		file, size, err := service.OpenBundle(bundle)
+		require.NoError(t, err)
+		data, err := io.ReadAll(file)
+		file.Close()
+		require.NoError(t, err)
+		archive, err := zip.NewReader(bytes.NewReader(data), size)
+		require.NoError(t, err)
+
+		var names []string
+		var summary, brokenLinks string
+		for _, f := range archive.File {
+			names = append(names, f.Name)
+			if f.Name == "summary.csv" || f.Name == "broken_links.csv" {
+				rc, err := f.Open()
+				require.NoError(t, err)
+				content, _ := io.ReadAll(rc)
+				rc.Close()
+				if f.Name == "summary.csv" {
+					summary = string(content)
+				} else {
+					brokenLinks = string(content)
+				}
+			}
+		}
+
+		assert.Contains(t, names, "sites/1-example.com.pdf")
+		assert.Contains(t, names, "sites/2-pending.com.pdf")
+		assert.Contains(t, names, "summary.csv")
+
+		lines := strings.Split(strings.TrimSpace(summary), "\n")
+		require.Len(t, lines, 3)
+		assert.Contains(t, lines[1], "https://example.com,Example,completed")
+		assert.Contains(t, lines[1], ",3,2,1,1,")
+
+		assert.Contains(t, brokenLinks, "https://example.com/missing,404,https://example.com/docs,ignored")
+	})
+
+	t.Run("rejects unknown URLs", func(t *testing.T) {
+		service, _ := setupReportTest(t)
+
+		bundle, err := service.CreateBundle(1, []uint{999})
+		assert.Error(t, err)
+		assert.Nil(t, bundle)
+	})
+
+	t.Run("rejects empty list", func(t *testing.T) {
+		service, _ := setupReportTest(t)
+
+		_, err := service.CreateBundle(1, nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestReportService_GetBundle(t *testing.T) {
+	service, db := setupReportTest(t)
+
+	bundle := &models.ReportBundle{UserID: 1, Status: "completed"}
+	require.NoError(t, db.Create(bundle).Error)
+
+	t.Run("owner can fetch bundle", func(t *testing.T) {
+		found, err := service.GetBundle(1, bundle.ID)
+		require.NoError(t, err)
+		assert.Equal(t, bundle.ID, found.ID)
+	})
+
+	t.Run("other users cannot see bundle", func(t *testing.T) {
+		_, err := service.GetBundle(2, bundle.ID)
+		assert.ErrorIs(t, err, ErrBundleNotFound)
+	})
+}
@@ -0,0 +1,35 @@
+package services
+
+import "web-crawler-backend/internal/repository"
+
+// WithStore returns a copy of the service that reads and writes through the
+// repositories of store, e.g. fakes in tests, instead of its database
+func (s *CrawlerService) WithStore(store repository.Store) *CrawlerService {
+	copied := *s
+	copied.store = store
+	return &copied
+}
+
+// repositories returns the service's store bound to its context
+func (s *CrawlerService) repositories() repository.Store {
+	if s.store == nil {
+		return repository.NewStore(s.db)
+	}
+	return s.store.WithContext(s.traceContext())
+}
+
+// WithStore returns a copy of the service that reads and writes through the
+// repositories of store, e.g. fakes in tests, instead of its database
+func (s *URLService) WithStore(store repository.Store) *URLService {
+	copied := *s
+	copied.store = store
+	return &copied
+}
+
+// repositories returns the service's store bound to its context
+func (s *URLService) repositories() repository.Store {
+	if s.store == nil {
+		return repository.NewStore(s.db)
+	}
+	return s.store.WithContext(s.context())
+}
@@ -0,0 +1,194 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+
+	"web-crawler-backend/internal/models"
+	"web-crawler-backend/internal/password"
+	"web-crawler-backend/internal/repository"
+)
+
+// fakeStore keeps users and crawls in memory; the repositories a test doesn't
+// need are nil
+type fakeStore struct {
+	users    *fakeUsers
+	sessions *fakeSessions
+	crawls   repository.CrawlRepository
+}
+
+func (s fakeStore) URLs() repository.URLRepository     { return nil }
+func (s fakeStore) Crawls() repository.CrawlRepository { return s.crawls }
+func (s fakeStore) Links() repository.LinkRepository   { return nil }
+func (s fakeStore) Users() repository.UserRepository   { return s.users }
+
+func (s fakeStore) Sessions() repository.SessionRepository { return s.sessions }
+
+func (s fakeStore) WithContext(ctx context.Context) repository.Store { return s }
+
+func (s fakeStore) Transaction(fn func(repository.Store) error) error { return fn(s) }
+
+type fakeUsers struct {
+	users []*models.User
+}
+
+func (f *fakeUsers) find(match func(*models.User) bool) (*models.User, error) {
+	for _, user := range f.users {
+		if match(user) {
+			copied := *user
+			return &copied, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (f *fakeUsers) FindByUsernameOrEmail(username, email string) (*models.User, error) {
+	return f.find(func(u *models.User) bool { return u.Username == username || u.Email == email })
+}
+
+func (f *fakeUsers) FindActiveByUsername(username string) (*models.User, error) {
+	return f.find(func(u *models.User) bool { return u.IsActive && u.Username == username })
+}
+
+func (f *fakeUsers) FindActiveByID(id uint) (*models.User, error) {
+	return f.find(func(u *models.User) bool { return u.IsActive && u.ID == id })
+}
+
+func (f *fakeUsers) Create(user *models.User) error {
+	user.ID = uint(len(f.users) + 1)
+	copied := *user
+	f.users = append(f.users, &copied)
+	return nil
+}
+
+func (f *fakeUsers) SetPassword(id uint, hash string) error {
+	for _, user := range f.users {
+		if user.ID == id {
+			user.Password = hash
+			return nil
+		}
+	}
+	return repository.ErrNotFound
+}
+
+type fakeSessions struct {
+	sessions []*models.Session
+}
+
+func (f *fakeSessions) Create(session *models.Session) error {
+	session.ID = uint(len(f.sessions) + 1)
+	copied := *session
+	f.sessions = append(f.sessions, &copied)
+	return nil
+}
+
+func (f *fakeSessions) FindActive(id uint, now time.Time) (*models.Session, error) {
+	for _, session := range f.sessions {
+		if session.ID == id && session.RevokedAt == nil && session.ExpiresAt.After(now) {
+			copied := *session
+			return &copied, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (f *fakeSessions) ListActive(userID uint, now time.Time) ([]models.Session, error) {
+	var sessions []models.Session
+	for _, session := range f.sessions {
+		if session.UserID == userID && session.RevokedAt == nil && session.ExpiresAt.After(now) {
+			sessions = append(sessions, *session)
+		}
+	}
+	return sessions, nil
+}
+
+func (f *fakeSessions) Touch(id uint, at time.Time) error {
+	return f.Extend(id, at, f.sessions[id-1].ExpiresAt)
+}
+
+func (f *fakeSessions) Extend(id uint, at, expiresAt time.Time) error {
+	f.sessions[id-1].LastUsedAt = at
+	f.sessions[id-1].ExpiresAt = expiresAt
+	return nil
+}
+
+func (f *fakeSessions) Revoke(userID, id uint, at time.Time) error {
+	session, err := f.FindActive(id, at)
+	if err != nil || session.UserID != userID {
+		return repository.ErrNotFound
+	}
+	f.sessions[id-1].RevokedAt = &at
+	return nil
+}
+
+// noCrawls is a crawl repository without any crawls
+type noCrawls struct{ repository.CrawlRepository }
+
+func (noCrawls) Exists(id uint) (bool, error) { return false, nil }
+
+func (noCrawls) LatestCompleted(urlID uint) (*models.Crawl, error) {
+	return nil, repository.ErrNotFound
+}
+
+func TestAuthService_WithStore(t *testing.T) {
+	users := &fakeUsers{}
+	service := NewAuthServiceWithStore(fakeStore{users: users, sessions: &fakeSessions{}})
+
+	user, err := service.Register(&models.RegisterRequest{Username: "alice", Email: "alice@example.com", Password: "password123"})
+	require.NoError(t, err)
+	assert.Empty(t, user.Password)
+	require.Len(t, users.users, 1)
+	_, err = password.Default().Verify(users.users[0].Password, "password123")
+	assert.NoError(t, err)
+
+	_, err = service.Register(&models.RegisterRequest{Username: "alice2", Email: "alice@example.com", Password: "password123"})
+	assert.ErrorIs(t, err, ErrUserExists)
+
+	response, err := service.Login(&models.LoginRequest{Username: "alice", Password: "password123"})
+	require.NoError(t, err)
+	assert.NotEmpty(t, response.Token)
+	assert.Equal(t, user.ID, response.User.ID)
+
+	_, err = service.Login(&models.LoginRequest{Username: "alice", Password: "wrong"})
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+
+	_, err = service.GetUserByID(42)
+	assert.ErrorIs(t, err, ErrAccountNotFound)
+}
+
+func TestAuthService_RehashOnLogin(t *testing.T) {
+	legacy, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	require.NoError(t, err)
+	users := &fakeUsers{users: []*models.User{{ID: 1, Username: "alice", Password: string(legacy), IsActive: true}}}
+	argon2id := password.Argon2id{Params: password.Argon2Params{Memory: 64, Iterations: 1, Parallelism: 1}}
+	service := NewAuthServiceWithStore(fakeStore{users: users, sessions: &fakeSessions{}}).WithPasswords(password.NewHasher(argon2id, password.Bcrypt{}))
+
+	_, err = service.Login(&models.LoginRequest{Username: "alice", Password: "wrong"})
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+	assert.Equal(t, string(legacy), users.users[0].Password, "failed logins keep the hash")
+
+	_, err = service.Login(&models.LoginRequest{Username: "alice", Password: "password123"})
+	require.NoError(t, err)
+	rehashed := users.users[0].Password
+	assert.True(t, argon2id.Recognizes(rehashed), "the bcrypt hash is replaced by an Argon2id one")
+	assert.NoError(t, argon2id.Verify(rehashed, "password123"))
+
+	_, err = service.Login(&models.LoginRequest{Username: "alice", Password: "password123"})
+	require.NoError(t, err)
+	assert.Equal(t, rehashed, users.users[0].Password, "current hashes are kept")
+}
+
+func TestCrawlerService_WithStore(t *testing.T) {
+	service := NewCrawlerService(setupCrawlerTestDB(t)).WithStore(fakeStore{crawls: noCrawls{}})
+
+	_, err := service.GetCrawlEvents(1)
+	assert.ErrorIs(t, err, ErrCrawlNotFound)
+
+	_, err = service.RecheckBrokenLinks(1)
+	assert.ErrorIs(t, err, ErrNoCompletedCrawl)
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"web-crawler-backend/internal/models"
+)
+
+// requestTotals accumulates the requests of a route or user
+type requestTotals struct {
+	calls        int64
+	clientErrors int64
+	serverErrors int64
+	latency      time.Duration
+	maxLatency   time.Duration
+}
+
+func (t *requestTotals) add(status int, latency time.Duration) {
+	t.calls++
+	switch {
+	case status >= http.StatusInternalServerError:
+		t.serverErrors++
+	case status >= http.StatusBadRequest:
+		t.clientErrors++
+	}
+	t.latency += latency
+	t.maxLatency = max(t.maxLatency, latency)
+}
+
+func (t *requestTotals) stats() models.RequestStats {
+	stats := models.RequestStats{
+		Calls:        t.calls,
+		ClientErrors: t.clientErrors,
+		ServerErrors: t.serverErrors,
+		MaxLatencyMs: float64(t.maxLatency.Microseconds()) / 1000,
+	}
+	if t.calls > 0 {
+		stats.AvgLatencyMs = float64(t.latency.Microseconds()) / 1000 / float64(t.calls)
+	}
+	return stats
+}
+
+type routeKey struct {
+	method string
+	route  string
+}
+
+// RequestMetrics counts API calls, errors and latency per route and per user,
+// for spotting abusive clients and planning quotas. Like the rate limiters,
+// it is kept in memory, so each server process reports its own requests
+// since it started.
+type RequestMetrics struct {
+	mu        sync.Mutex
+	since     time.Time
+	routes    map[routeKey]*requestTotals
+	users     map[uint]*requestTotals
+	usernames map[uint]string
+}
+
+// NewRequestMetrics creates empty request metrics starting now
+func NewRequestMetrics() *RequestMetrics {
+	return &RequestMetrics{
+		since:     time.Now(),
+		routes:    make(map[routeKey]*requestTotals),
+		users:     make(map[uint]*requestTotals),
+		usernames: make(map[uint]string),
+	}
+}
+
+// Record counts a request of a route by a user; userID 0 is an anonymous request
+func (m *RequestMetrics) Record(method, route string, userID uint, username string, status int, latency time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := routeKey{method: method, route: route}
+	if m.routes[key] == nil {
+		m.routes[key] = &requestTotals{}
+	}
+	m.routes[key].add(status, latency)
+
+	if m.users[userID] == nil {
+		m.users[userID] = &requestTotals{}
+	}
+	m.users[userID].add(status, latency)
+	if username != "" {
+		m.usernames[userID] = username
+	}
+}
+
+// Report returns the stats of every route and user, most requests first
+func (m *RequestMetrics) Report() *models.RequestMetricsReport {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	report := &models.RequestMetricsReport{
+		Since:  m.since,
+		Routes: make([]models.RouteRequestStats, 0, len(m.routes)),
+		Users:  make([]models.UserRequestStats, 0, len(m.users)),
+	}
+	for key, totals := range m.routes {
+		report.Routes = append(report.Routes, models.RouteRequestStats{Method: key.method, Route: key.route, RequestStats: totals.stats()})
+	}
+	for userID, totals := range m.users {
+		report.Users = append(report.Users, models.UserRequestStats{UserID: userID, Username: m.usernames[userID], RequestStats: totals.stats()})
+	}
+
+	sort.Slice(report.Routes, func(i, j int) bool {
+		a, b := report.Routes[i], report.Routes[j]
+		if a.Calls != b.Calls {
+			return a.Calls > b.Calls
+		}
+		if a.Route != b.Route {
+			return a.Route < b.Route
+		}
+		return a.Method < b.Method
+	})
+	sort.Slice(report.Users, func(i, j int) bool {
+		a, b := report.Users[i], report.Users[j]
+		if a.Calls != b.Calls {
+			return a.Calls > b.Calls
+		}
+		return a.UserID < b.UserID
+	})
+	return report
+}
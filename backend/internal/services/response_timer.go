@@ -0,0 +1,106 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"strings"
+	"time"
+
+	"web-crawler-backend/internal/models"
+)
+
+// performanceTrendLength is how many past crawls the performance trend covers
+const performanceTrendLength = 10
+
+// responseTimer measures a single page request
+type responseTimer struct {
+	start   time.Time
+	Metrics models.ResponseMetrics
+}
+
+// timedGet issues a GET request, recording the time to first byte, protocol
+// and content encoding. The body is left unread; call readBody to finish the
+// measurement.
+func timedGet(ctx context.Context, target string) (*http.Response, *responseTimer, error) {
+	return timedGetWithHeader(ctx, target, nil)
+}
+
+// timedGetWithHeader is timedGet with extra request headers, e.g. the
+// validators of a conditional request
+func timedGetWithHeader(ctx context.Context, target string, header http.Header) (*http.Response, *responseTimer, error) {
+	timer := &responseTimer{start: time.Now()}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, timer, err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	var firstByte time.Time
+	var reused bool
+	trace := &httptrace.ClientTrace{
+		GotConn:              func(info httptrace.GotConnInfo) { reused = info.Reused },
+		GotFirstResponseByte: func() { firstByte = time.Now() },
+	}
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+
+	resp, err := crawlerClient.Do(req)
+	if err != nil {
+		return nil, timer, err
+	}
+
+	if firstByte.IsZero() {
+		firstByte = time.Now()
+	}
+	timer.Metrics.TTFBMs = firstByte.Sub(timer.start).Milliseconds()
+	timer.Metrics.Protocol = resp.Proto
+	timer.Metrics.ContentEncoding = resp.Header.Get("Content-Encoding")
+	if resp.Uncompressed {
+		// The transport requested gzip itself and removed the header after decoding
+		timer.Metrics.ContentEncoding = "gzip"
+	}
+	timer.Metrics.HTTP3Advertised = offersHTTP3(resp.Header.Values("Alt-Svc"))
+	timer.Metrics.KeepAlive = !resp.Close
+	timer.Metrics.ConnectionReused = reused
+
+	return resp, timer, nil
+}
+
+// offersHTTP3 reports whether Alt-Svc headers offer HTTP/3, e.g. h3=":443"
+// or a draft version like h3-29
+func offersHTTP3(altSvc []string) bool {
+	for _, header := range altSvc {
+		for _, service := range strings.Split(header, ",") {
+			protocol, _, _ := strings.Cut(strings.TrimSpace(service), "=")
+			if protocol == "h3" || strings.HasPrefix(protocol, "h3-") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// readBody reads the whole response body, recording its size and the total
+// download time, and replaces the body so it can still be parsed. Reading
+// stops with a skip error once the body exceeds limit bytes; a limit of 0
+// reads everything.
+func (t *responseTimer) readBody(resp *http.Response, limit int64) error {
+	reader := resp.Body
+	if limit > 0 {
+		reader = io.NopCloser(io.LimitReader(resp.Body, limit+1))
+	}
+	body, err := io.ReadAll(reader)
+	resp.Body.Close()
+	t.Metrics.DownloadMs = time.Since(t.start).Milliseconds()
+	t.Metrics.ResponseBytes = int64(len(body))
+	if err == nil && limit > 0 && int64(len(body)) > limit {
+		body, err = nil, responseTooLarge(limit)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return err
+}
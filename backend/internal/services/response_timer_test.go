@@ -0,0 +1,64 @@
+package services
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimedGet(t *testing.T) {
+	const body = "<html><body>Hello</body></html>"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Header().Set("Alt-Svc", `h3=":443"; ma=86400, h2=":443"`)
+		if strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			w.Header().Set("Content-Encoding", "gzip")
+			gz := gzip.NewWriter(w)
+			defer gz.Close()
+			gz.Write([]byte(body))
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	resp, timer, err := timedGet(context.Background(), server.URL)
+	require.NoError(t, err)
+	require.NoError(t, timer.readBody(resp, 0))
+
+	assert.Equal(t, "gzip", timer.Metrics.ContentEncoding)
+	assert.Equal(t, "HTTP/1.1", timer.Metrics.Protocol)
+	assert.Equal(t, int64(len(body)), timer.Metrics.ResponseBytes)
+	assert.GreaterOrEqual(t, timer.Metrics.DownloadMs, timer.Metrics.TTFBMs)
+	assert.True(t, timer.Metrics.HTTP3Advertised)
+	assert.True(t, timer.Metrics.KeepAlive)
+	assert.False(t, timer.Metrics.ConnectionReused)
+
+	// The body can still be read after measuring
+	content, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, body, string(content))
+
+	// The second request reuses the kept-alive connection
+	resp.Body.Close()
+	resp, timer, err = timedGet(context.Background(), server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.True(t, timer.Metrics.ConnectionReused)
+}
+
+func TestOffersHTTP3(t *testing.T) {
+	assert.True(t, offersHTTP3([]string{`h3-29=":443"; ma=3600`}))
+	assert.True(t, offersHTTP3([]string{`h2=":443"`, `h3=":8443"`}))
+	assert.False(t, offersHTTP3([]string{`h2=":443"; ma=3600`}))
+	assert.False(t, offersHTTP3([]string{"clear"}))
+	assert.False(t, offersHTTP3(nil))
+}
@@ -0,0 +1,219 @@
+package services
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+
+	"web-crawler-backend/internal/models"
+	"web-crawler-backend/internal/storage"
+)
+
+// RetentionOptions configures how much crawl history is kept
+type RetentionOptions struct {
+	// KeepCrawls is how many of the latest crawls are kept per URL; zero keeps every crawl
+	KeepCrawls int
+	// Archive receives a gzipped JSON copy of every crawl before it is
+	// deleted, in one folder per URL; nil deletes without archiving
+	Archive storage.Storage
+	// Snapshots holds the pages of crawls, which are deleted with them
+	Snapshots storage.Storage
+}
+
+// RetentionResult counts what a retention run removed
+type RetentionResult struct {
+	URLs   int   `json:"urls"`
+	Crawls int   `json:"crawls"`
+	Links  int64 `json:"links"`
+}
+
+// RetentionService bounds the crawl history of every URL. Each rerun adds a
+// crawl with a full set of links, so old crawls are archived and deleted.
+type RetentionService struct {
+	db   *gorm.DB
+	opts RetentionOptions
+}
+
+func NewRetentionService(db *gorm.DB, opts RetentionOptions) *RetentionService {
+	return &RetentionService{db: db, opts: opts}
+}
+
+// ApplyRetention deletes every crawl beyond the latest KeepCrawls of each
+// URL, archiving it first if an archive storage is set. Crawls still queued
+// or running are never deleted, and neither is the latest completed crawl,
+// whose results later unchanged crawls report.
+func (s *RetentionService) ApplyRetention() (*RetentionResult, error) {
+	result := &RetentionResult{}
+	if s.opts.KeepCrawls <= 0 {
+		return result, nil
+	}
+
+	var urlIDs []uint
+	if err := s.db.Model(&models.Crawl{}).Group("url_id").
+		Having("COUNT(*) > ?", s.opts.KeepCrawls).Pluck("url_id", &urlIDs).Error; err != nil {
+		return nil, fmt.Errorf("failed to find URLs over the crawl limit: %w", err)
+	}
+
+	for _, urlID := range urlIDs {
+		// MySQL doesn't support LIMIT in IN subqueries, so the kept crawls are looked up first
+		var keptIDs []uint
+		if err := s.db.Model(&models.Crawl{}).Where("url_id = ?", urlID).
+			Order("created_at DESC, id DESC").Limit(s.opts.KeepCrawls).Pluck("id", &keptIDs).Error; err != nil {
+			return nil, fmt.Errorf("failed to find latest crawls of URL %d: %w", urlID, err)
+		}
+		var completedIDs []uint
+		if err := s.db.Model(&models.Crawl{}).Where("url_id = ? AND status = ?", urlID, "completed").
+			Order("created_at DESC, id DESC").Limit(1).Pluck("id", &completedIDs).Error; err != nil {
+			return nil, fmt.Errorf("failed to find the latest completed crawl of URL %d: %w", urlID, err)
+		}
+		keptIDs = append(keptIDs, completedIDs...)
+
+		var crawlIDs []uint
+		if err := s.db.Model(&models.Crawl{}).
+			Where("url_id = ? AND id NOT IN ? AND status NOT IN ?", urlID, keptIDs, []string{"queued", "running"}).
+			Pluck("id", &crawlIDs).Error; err != nil {
+			return nil, fmt.Errorf("failed to find old crawls of URL %d: %w", urlID, err)
+		}
+		if len(crawlIDs) == 0 {
+			continue
+		}
+
+		if s.opts.Archive != nil {
+			if err := s.archiveCrawls(urlID, crawlIDs); err != nil {
+				return nil, err
+			}
+		}
+
+		var snapshotKeys []string
+		if err := s.db.Model(&models.Crawl{}).Where("id IN ? AND snapshot_key <> ''", crawlIDs).
+			Pluck("snapshot_key", &snapshotKeys).Error; err != nil {
+			return nil, fmt.Errorf("failed to find snapshots of URL %d: %w", urlID, err)
+		}
+
+		links, err := s.deleteCrawls(crawlIDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to delete old crawls of URL %d: %w", urlID, err)
+		}
+		s.deleteSnapshots(snapshotKeys)
+		result.URLs++
+		result.Crawls += len(crawlIDs)
+		result.Links += links
+	}
+
+	return result, nil
+}
+
+// Start applies the retention policy every tick until the returned stop function is called
+func (s *RetentionService) Start(tick time.Duration) (stop func()) {
+	ticker := time.NewTicker(tick)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				if result, err := s.ApplyRetention(); err != nil {
+					log.Printf("Crawl retention failed: %v", err)
+				} else if result.Crawls > 0 {
+					log.Printf("Removed %d old crawls with %d links from %d URLs", result.Crawls, result.Links, result.URLs)
+				}
+			case <-done:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+
+	return func() { close(done) }
+}
+
+// crawlArchive is the archived form of a deleted crawl
+type crawlArchive struct {
+	ArchivedAt time.Time     `json:"archived_at"`
+	URL        string        `json:"url"`
+	Crawl      models.Crawl  `json:"crawl"`
+	Links      []models.Link `json:"links"`
+}
+
+// archiveCrawls stores each crawl with its page meta and links as
+// url-<id>/crawl-<id>.json.gz
+func (s *RetentionService) archiveCrawls(urlID uint, crawlIDs []uint) error {
+	var url models.URL
+	if err := s.db.Unscoped().Select("id", "url").First(&url, urlID).Error; err != nil && err != gorm.ErrRecordNotFound {
+		return fmt.Errorf("failed to fetch URL %d: %w", urlID, err)
+	}
+
+	for _, crawlID := range crawlIDs {
+		var crawl models.Crawl
+		if err := s.db.Preload("PageMeta").First(&crawl, crawlID).Error; err != nil {
+			return fmt.Errorf("failed to fetch crawl %d: %w", crawlID, err)
+		}
+		archive := crawlArchive{ArchivedAt: time.Now(), URL: url.URL, Crawl: crawl}
+		if err := s.db.Where("crawl_id = ?", crawlID).Order("id ASC").Find(&archive.Links).Error; err != nil {
+			return fmt.Errorf("failed to fetch links of crawl %d: %w", crawlID, err)
+		}
+
+		data, err := gzipJSON(archive)
+		if err != nil {
+			return fmt.Errorf("failed to archive crawl %d: %w", crawlID, err)
+		}
+		key := fmt.Sprintf("url-%d/crawl-%d.json.gz", urlID, crawlID)
+		if err := s.opts.Archive.Put(context.Background(), key, data, "application/gzip"); err != nil {
+			return fmt.Errorf("failed to archive crawl %d: %w", crawlID, err)
+		}
+	}
+	return nil
+}
+
+// deleteCrawls deletes crawls and every row that belongs to them, and returns
+// the number of links deleted. Activity entries stay, detached from the crawls.
+func (s *RetentionService) deleteCrawls(crawlIDs []uint) (int64, error) {
+	var links int64
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		for _, child := range crawlChildren {
+			result := tx.Where("crawl_id IN ?", crawlIDs).Delete(child)
+			if result.Error != nil {
+				return result.Error
+			}
+			if _, ok := child.(*models.Link); ok {
+				links = result.RowsAffected
+			}
+		}
+		if err := tx.Model(&models.ActivityEvent{}).Where("crawl_id IN ?", crawlIDs).Update("crawl_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Where("id IN ?", crawlIDs).Delete(&models.Crawl{}).Error
+	})
+	return links, err
+}
+
+// deleteSnapshots removes the pages of deleted crawls. Failures only leave
+// files behind, so they are logged.
+func (s *RetentionService) deleteSnapshots(keys []string) {
+	if s.opts.Snapshots == nil {
+		return
+	}
+	for _, key := range keys {
+		if err := s.opts.Snapshots.Delete(context.Background(), key); err != nil {
+			log.Printf("Failed to delete snapshot %s: %v", key, err)
+		}
+	}
+}
+
+func gzipJSON(value interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(gz).Encode(value); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"web-crawler-backend/internal/models"
+	"web-crawler-backend/internal/storage"
+)
+
+func TestRetentionService_ApplyRetention(t *testing.T) {
+	db := setupURLTestDB(t)
+	archiveDir := t.TempDir()
+	service := NewRetentionService(db, RetentionOptions{KeepCrawls: 2, Archive: storage.NewLocal(archiveDir)})
+
+	url := &models.URL{URL: "https://example.com", Status: "completed"}
+	require.NoError(t, db.Create(url).Error)
+	other := &models.URL{URL: "https://other.com", Status: "completed"}
+	require.NoError(t, db.Create(other).Error)
+
+	// Five crawls of the first URL, the oldest one stuck running
+	start := time.Now().Add(-time.Hour)
+	var crawls []*models.Crawl
+	for i, status := range []string{"running", "completed", "error", "completed", "completed"} {
+		crawl := &models.Crawl{URLID: url.ID, Status: status, CreatedAt: start.Add(time.Duration(i) * time.Minute)}
+		require.NoError(t, db.Create(crawl).Error)
+		require.NoError(t, db.Create(&models.Link{URLID: url.ID, CrawlID: crawl.ID, LinkURL: "https://example.com/a"}).Error)
+		crawls = append(crawls, crawl)
+	}
+	require.NoError(t, db.Create(&models.Crawl{URLID: other.ID, Status: "completed"}).Error)
+	require.NoError(t, db.Create(&models.ActivityEvent{UserID: 1, Type: models.ActivityCrawlCompleted, URLID: &url.ID, CrawlID: &crawls[1].ID}).Error)
+
+	result, err := service.ApplyRetention()
+	require.NoError(t, err)
+	assert.Equal(t, &RetentionResult{URLs: 1, Crawls: 2, Links: 2}, result)
+
+	var remaining []uint
+	require.NoError(t, db.Model(&models.Crawl{}).Where("url_id = ?", url.ID).Order("id").Pluck("id", &remaining).Error)
+	assert.Equal(t, []uint{crawls[0].ID, crawls[3].ID, crawls[4].ID}, remaining, "the running crawl is kept")
+
+	var links int64
+	require.NoError(t, db.Model(&models.Link{}).Where("crawl_id IN ?", []uint{crawls[1].ID, crawls[2].ID}).Count(&links).Error)
+	assert.Zero(t, links)
+
+	var activity models.ActivityEvent
+	require.NoError(t, db.First(&activity).Error)
+	assert.Nil(t, activity.CrawlID)
+
+	// Deleted crawls are archived with their links
+	file, err := os.Open(filepath.Join(archiveDir, "url-1", "crawl-2.json.gz"))
+	require.NoError(t, err)
+	defer file.Close()
+	gz, err := gzip.NewReader(file)
+	require.NoError(t, err)
+	var archive crawlArchive
+	require.NoError(t, json.NewDecoder(gz).Decode(&archive))
+	assert.Equal(t, "https://example.com", archive.URL)
+	assert.Equal(t, crawls[1].ID, archive.Crawl.ID)
+	assert.Len(t, archive.Links, 1)
+
+	// A second run has nothing left to do
+	result, err = service.ApplyRetention()
+	require.NoError(t, err)
+	assert.Zero(t, result.Crawls)
+}
+
+func TestRetentionService_KeepsLatestCompleted(t *testing.T) {
+	db := setupURLTestDB(t)
+	service := NewRetentionService(db, RetentionOptions{KeepCrawls: 2})
+	crawler := NewCrawlerService(db)
+
+	url := &models.URL{URL: "https://example.com", Status: "completed"}
+	require.NoError(t, db.Create(url).Error)
+
+	// A completed crawl confirmed by three unchanged ones
+	start := time.Now().Add(-time.Hour)
+	var crawls []*models.Crawl
+	for i, status := range []string{"completed", "completed", "unchanged", "unchanged", "unchanged"} {
+		crawl := &models.Crawl{URLID: url.ID, Status: status, BrokenLinks: i + 1, CreatedAt: start.Add(time.Duration(i) * time.Minute)}
+		require.NoError(t, db.Create(crawl).Error)
+		crawls = append(crawls, crawl)
+	}
+
+	result, err := service.ApplyRetention()
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Crawls)
+
+	var remaining []uint
+	require.NoError(t, db.Model(&models.Crawl{}).Where("url_id = ?", url.ID).Order("id").Pluck("id", &remaining).Error)
+	assert.Equal(t, []uint{crawls[1].ID, crawls[3].ID, crawls[4].ID}, remaining)
+
+	status, err := crawler.getCrawlStatus(url.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, status.BrokenLinks, "unchanged crawls still report the completed one")
+}
+
+func TestRetentionService_KeepAll(t *testing.T) {
+	db := setupURLTestDB(t)
+	require.NoError(t, db.Create(&models.Crawl{URLID: 1, Status: "completed"}).Error)
+	require.NoError(t, db.Create(&models.Crawl{URLID: 1, Status: "completed"}).Error)
+
+	result, err := NewRetentionService(db, RetentionOptions{}).ApplyRetention()
+	require.NoError(t, err)
+	assert.Zero(t, result.Crawls)
+}
@@ -0,0 +1,325 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"web-crawler-backend/internal/models"
+)
+
+const (
+	// minScheduleInterval is the shortest allowed interval between scheduled crawls
+	minScheduleInterval = 15
+	// schedulerBatchSize caps how many due schedules are processed per tick
+	schedulerBatchSize = 100
+)
+
+var (
+	// ErrScheduleNotFound is returned when a URL has no crawl schedule
+	ErrScheduleNotFound = errors.New("crawl schedule not found")
+	// ErrInvalidSchedule is returned for schedules with an invalid interval or window
+	ErrInvalidSchedule = errors.New("invalid crawl schedule")
+)
+
+// weekdays maps the short day names accepted in schedules to time.Weekday
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+type SchedulerService struct {
+	db             *gorm.DB
+	crawlerService CrawlerServiceInterface
+	heartbeat      Heartbeat
+}
+
+func NewSchedulerService(db *gorm.DB, crawlerService CrawlerServiceInterface) *SchedulerService {
+	return &SchedulerService{db: db, crawlerService: crawlerService}
+}
+
+// Heartbeat reports when the worker last ticked, for readiness checks
+func (s *SchedulerService) Heartbeat() *Heartbeat {
+	return &s.heartbeat
+}
+
+// Start runs due schedules every tick until the returned stop function is called
+func (s *SchedulerService) Start(tick time.Duration) (stop func()) {
+	ticker := time.NewTicker(tick)
+	s.heartbeat.start(tick, time.Now())
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case now := <-ticker.C:
+				s.RunDue(now)
+				s.heartbeat.beat(now)
+			case <-done:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+
+	return func() { close(done) }
+}
+
+// RunDue starts crawls for schedules that are due and inside their crawl
+// window. Schedules outside their window are pushed to the next opening.
+// It returns the IDs of the URLs whose crawls were started.
+func (s *SchedulerService) RunDue(now time.Time) []uint {
+	var schedules []models.CrawlSchedule
+	if err := s.db.Where("enabled = ? AND next_run_at <= ?", true, now).
+		Order("next_run_at ASC").Limit(schedulerBatchSize).Find(&schedules).Error; err != nil {
+		log.Printf("Failed to load due crawl schedules: %v", err)
+		return nil
+	}
+
+	var started []uint
+	for i := range schedules {
+		schedule := &schedules[i]
+
+		window, err := parseCrawlWindow(schedule)
+		if err != nil {
+			log.Printf("Skipping crawl schedule %d: %v", schedule.ID, err)
+			continue
+		}
+
+		if !window.allows(now) {
+			next := window.nextOpening(now)
+			schedule.NextRunAt = &next
+			s.db.Model(schedule).Update("next_run_at", next)
+			continue
+		}
+
+		var url models.URL
+		if err := s.db.First(&url, schedule.URLID).Error; err != nil {
+			log.Printf("Skipping crawl schedule %d: URL %d not found", schedule.ID, schedule.URLID)
+			continue
+		}
+		if url.Status == "running" {
+			// Try again on the next tick
+			continue
+		}
+
+		next := now.Add(time.Duration(schedule.IntervalMinutes) * time.Minute)
+		claimed, err := claimRun(s.db, &models.CrawlSchedule{}, schedule.ID, "next_run_at", *schedule.NextRunAt, map[string]interface{}{
+			"last_run_at": now,
+			"next_run_at": next,
+		})
+		if err != nil {
+			log.Printf("Failed to update crawl schedule %d: %v", schedule.ID, err)
+			continue
+		}
+		if !claimed {
+			// Another replica started it
+			continue
+		}
+
+		go s.startCrawl(url.ID)
+		started = append(started, url.ID)
+	}
+
+	return started
+}
+
+// claimRun moves a due run of the row with id by applying updates, which
+// must set column to the next run. Every replica runs the workers, so the
+// update only applies while column still holds due, the run this replica
+// loaded; it reports whether it did, and only then may the run start.
+func claimRun(db *gorm.DB, model interface{}, id uint, column string, due time.Time, updates map[string]interface{}) (bool, error) {
+	result := db.Model(model).Where("id = ? AND "+column+" = ?", id, due).Updates(updates)
+	return result.RowsAffected == 1, result.Error
+}
+
+// startCrawl starts a scheduled crawl, with low priority if the crawler queues by priority
+func (s *SchedulerService) startCrawl(urlID uint) {
+	if starter, ok := s.crawlerService.(priorityCrawlStarter); ok {
+		starter.StartCrawlWithPriority(context.Background(), urlID, models.CrawlPriorityLow)
+		return
+	}
+	s.crawlerService.StartCrawl(urlID)
+}
+
+// GetSchedule returns the crawl schedule of a URL
+func (s *SchedulerService) GetSchedule(urlID uint) (*models.CrawlSchedule, error) {
+	var schedule models.CrawlSchedule
+	if err := s.db.Where("url_id = ?", urlID).First(&schedule).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrScheduleNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch crawl schedule: %w", err)
+	}
+	return &schedule, nil
+}
+
+// SetSchedule creates or replaces the crawl schedule of a URL
+func (s *SchedulerService) SetSchedule(urlID uint, req models.CrawlScheduleRequest) (*models.CrawlSchedule, error) {
+	var url models.URL
+	if err := s.db.First(&url, urlID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrURLNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch URL: %w", err)
+	}
+
+	var schedule models.CrawlSchedule
+	if err := s.db.Where("url_id = ?", urlID).First(&schedule).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to fetch crawl schedule: %w", err)
+	}
+
+	schedule.URLID = urlID
+	schedule.IntervalMinutes = req.IntervalMinutes
+	schedule.Enabled = req.Enabled == nil || *req.Enabled
+	schedule.Timezone = req.Timezone
+	if schedule.Timezone == "" {
+		schedule.Timezone = "UTC"
+	}
+	schedule.WindowStart = req.WindowStart
+	schedule.WindowEnd = req.WindowEnd
+	schedule.Days = make([]string, 0, len(req.Days))
+	for _, day := range req.Days {
+		schedule.Days = append(schedule.Days, strings.ToLower(strings.TrimSpace(day)))
+	}
+
+	if schedule.IntervalMinutes < minScheduleInterval {
+		return nil, fmt.Errorf("%w: interval_minutes must be at least %d", ErrInvalidSchedule, minScheduleInterval)
+	}
+	window, err := parseCrawlWindow(&schedule)
+	if err != nil {
+		return nil, err
+	}
+
+	// Run as soon as the window allows it
+	next := window.nextOpening(time.Now())
+	schedule.NextRunAt = &next
+
+	if err := s.db.Save(&schedule).Error; err != nil {
+		return nil, fmt.Errorf("failed to save crawl schedule: %w", err)
+	}
+
+	return &schedule, nil
+}
+
+// DeleteSchedule removes the crawl schedule of a URL
+func (s *SchedulerService) DeleteSchedule(urlID uint) error {
+	result := s.db.Where("url_id = ?", urlID).Delete(&models.CrawlSchedule{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete crawl schedule: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrScheduleNotFound
+	}
+	return nil
+}
+
+// crawlWindow is the parsed form of a schedule's allowed crawl hours
+type crawlWindow struct {
+	location *time.Location
+	start    int // Minutes after midnight
+	end      int
+	days     map[time.Weekday]bool // Empty allows every day
+	always   bool
+}
+
+// parseCrawlWindow validates and parses a schedule's window settings
+func parseCrawlWindow(schedule *models.CrawlSchedule) (*crawlWindow, error) {
+	location, err := time.LoadLocation(schedule.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unknown timezone %q", ErrInvalidSchedule, schedule.Timezone)
+	}
+
+	window := &crawlWindow{location: location, days: map[time.Weekday]bool{}}
+	for _, day := range schedule.Days {
+		weekday, ok := weekdays[day]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown day %q", ErrInvalidSchedule, day)
+		}
+		window.days[weekday] = true
+	}
+
+	if schedule.WindowStart == "" && schedule.WindowEnd == "" {
+		window.always = true
+		window.end = 24 * 60
+		return window, nil
+	}
+
+	if window.start, err = parseClock(schedule.WindowStart); err != nil {
+		return nil, err
+	}
+	if window.end, err = parseClock(schedule.WindowEnd); err != nil {
+		return nil, err
+	}
+	if window.start == window.end {
+		return nil, fmt.Errorf("%w: window_start and window_end must differ", ErrInvalidSchedule)
+	}
+
+	return window, nil
+}
+
+// parseClock converts HH:MM into minutes after midnight
+func parseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q is not a HH:MM time", ErrInvalidSchedule, value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// allows reports whether a crawl may start at t. Overnight windows belong to
+// the day they open on, so a Monday 22:00-04:00 window also covers early Tuesday.
+func (w *crawlWindow) allows(t time.Time) bool {
+	local := t.In(w.location)
+	minute := local.Hour()*60 + local.Minute()
+
+	if w.always {
+		return w.dayAllowed(local.Weekday())
+	}
+
+	if w.start < w.end {
+		return minute >= w.start && minute < w.end && w.dayAllowed(local.Weekday())
+	}
+
+	// Overnight window
+	if minute >= w.start {
+		return w.dayAllowed(local.Weekday())
+	}
+	if minute < w.end {
+		return w.dayAllowed(local.AddDate(0, 0, -1).Weekday())
+	}
+	return false
+}
+
+// nextOpening returns t if the window is open, otherwise the time it next opens
+func (w *crawlWindow) nextOpening(t time.Time) time.Time {
+	if w.allows(t) {
+		return t
+	}
+
+	local := t.In(w.location)
+	for offset := 0; offset <= 7; offset++ {
+		day := local.AddDate(0, 0, offset)
+		opening := time.Date(day.Year(), day.Month(), day.Day(), w.start/60, w.start%60, 0, 0, w.location)
+		if opening.After(t) && w.dayAllowed(opening.Weekday()) {
+			return opening
+		}
+	}
+
+	// Unreachable with at least one allowed day, but never schedule in the past
+	return t.Add(24 * time.Hour)
+}
+
+func (w *crawlWindow) dayAllowed(day time.Weekday) bool {
+	return len(w.days) == 0 || w.days[day]
+}
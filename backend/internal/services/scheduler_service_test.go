@@ -0,0 +1,174 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"web-crawler-backend/internal/models"
+)
+
+func setupSchedulerTest(t *testing.T) (*gorm.DB, *SchedulerService, *models.URL) {
+	db := setupURLTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.CrawlSchedule{}))
+
+	url := &models.URL{URL: "https://example.com", Status: "completed"}
+	require.NoError(t, db.Create(url).Error)
+
+	return db, NewSchedulerService(db, &mockCrawlerService{}), url
+}
+
+func TestCrawlWindow(t *testing.T) {
+	warsaw, err := time.LoadLocation("Europe/Warsaw")
+	require.NoError(t, err)
+
+	t.Run("daytime window", func(t *testing.T) {
+		window, err := parseCrawlWindow(&models.CrawlSchedule{Timezone: "Europe/Warsaw", WindowStart: "02:00", WindowEnd: "05:00"})
+		require.NoError(t, err)
+
+		assert.True(t, window.allows(time.Date(2024, 3, 4, 3, 0, 0, 0, warsaw)))
+		assert.False(t, window.allows(time.Date(2024, 3, 4, 5, 0, 0, 0, warsaw)))
+		assert.False(t, window.allows(time.Date(2024, 3, 4, 14, 0, 0, 0, warsaw)))
+
+		next := window.nextOpening(time.Date(2024, 3, 4, 14, 0, 0, 0, warsaw))
+		assert.Equal(t, time.Date(2024, 3, 5, 2, 0, 0, 0, warsaw), next)
+	})
+
+	t.Run("overnight window on selected days", func(t *testing.T) {
+		window, err := parseCrawlWindow(&models.CrawlSchedule{Timezone: "UTC", WindowStart: "22:00", WindowEnd: "04:00", Days: []string{"sat"}})
+		require.NoError(t, err)
+
+		saturday := time.Date(2024, 3, 9, 23, 0, 0, 0, time.UTC)
+		assert.True(t, window.allows(saturday))
+		assert.True(t, window.allows(saturday.Add(4*time.Hour)))   // Sunday 03:00
+		assert.False(t, window.allows(saturday.Add(24*time.Hour))) // Sunday 23:00
+
+		next := window.nextOpening(time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC))
+		assert.Equal(t, time.Date(2024, 3, 9, 22, 0, 0, 0, time.UTC), next)
+	})
+
+	t.Run("rejects invalid settings", func(t *testing.T) {
+		_, err := parseCrawlWindow(&models.CrawlSchedule{Timezone: "Mars/Olympus"})
+		assert.ErrorIs(t, err, ErrInvalidSchedule)
+
+		_, err = parseCrawlWindow(&models.CrawlSchedule{Timezone: "UTC", WindowStart: "25:00", WindowEnd: "03:00"})
+		assert.ErrorIs(t, err, ErrInvalidSchedule)
+
+		_, err = parseCrawlWindow(&models.CrawlSchedule{Timezone: "UTC", Days: []string{"someday"}})
+		assert.ErrorIs(t, err, ErrInvalidSchedule)
+	})
+}
+
+func TestSchedulerService_SetSchedule(t *testing.T) {
+	t.Run("creates and replaces a schedule", func(t *testing.T) {
+		_, service, url := setupSchedulerTest(t)
+
+		schedule, err := service.SetSchedule(url.ID, models.CrawlScheduleRequest{IntervalMinutes: 60})
+		require.NoError(t, err)
+		assert.True(t, schedule.Enabled)
+		assert.Equal(t, "UTC", schedule.Timezone)
+		require.NotNil(t, schedule.NextRunAt)
+
+		_, err = service.SetSchedule(url.ID, models.CrawlScheduleRequest{
+			IntervalMinutes: 1440,
+			WindowStart:     "02:00",
+			WindowEnd:       "05:00",
+			Days:            []string{"Mon", "wed"},
+		})
+		require.NoError(t, err)
+
+		stored, err := service.GetSchedule(url.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 1440, stored.IntervalMinutes)
+		assert.Equal(t, []string{"mon", "wed"}, stored.Days)
+	})
+
+	t.Run("rejects short intervals", func(t *testing.T) {
+		_, service, url := setupSchedulerTest(t)
+
+		_, err := service.SetSchedule(url.ID, models.CrawlScheduleRequest{IntervalMinutes: 1})
+		assert.ErrorIs(t, err, ErrInvalidSchedule)
+	})
+
+	t.Run("unknown URL", func(t *testing.T) {
+		_, service, _ := setupSchedulerTest(t)
+
+		_, err := service.SetSchedule(999, models.CrawlScheduleRequest{IntervalMinutes: 60})
+		assert.ErrorIs(t, err, ErrURLNotFound)
+	})
+}
+
+func TestSchedulerService_RunDue(t *testing.T) {
+	now := time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Minute)
+
+	t.Run("starts due crawls inside the window", func(t *testing.T) {
+		db, service, url := setupSchedulerTest(t)
+		require.NoError(t, db.Create(&models.CrawlSchedule{URLID: url.ID, IntervalMinutes: 60, Enabled: true, Timezone: "UTC", NextRunAt: &past}).Error)
+
+		assert.Equal(t, []uint{url.ID}, service.RunDue(now))
+
+		stored, err := service.GetSchedule(url.ID)
+		require.NoError(t, err)
+		assert.True(t, stored.NextRunAt.Equal(now.Add(time.Hour)))
+		assert.True(t, stored.LastRunAt.Equal(now))
+	})
+
+	t.Run("defers crawls outside the window", func(t *testing.T) {
+		db, service, url := setupSchedulerTest(t)
+		require.NoError(t, db.Create(&models.CrawlSchedule{
+			URLID: url.ID, IntervalMinutes: 60, Enabled: true, Timezone: "UTC",
+			WindowStart: "02:00", WindowEnd: "05:00", NextRunAt: &past,
+		}).Error)
+
+		assert.Empty(t, service.RunDue(now))
+
+		stored, err := service.GetSchedule(url.ID)
+		require.NoError(t, err)
+		assert.True(t, stored.NextRunAt.Equal(time.Date(2024, 3, 5, 2, 0, 0, 0, time.UTC)))
+		assert.Nil(t, stored.LastRunAt)
+	})
+
+	t.Run("skips running URLs and disabled schedules", func(t *testing.T) {
+		db, service, url := setupSchedulerTest(t)
+		require.NoError(t, db.Model(url).Update("status", "running").Error)
+		require.NoError(t, db.Create(&models.CrawlSchedule{URLID: url.ID, IntervalMinutes: 60, Enabled: true, Timezone: "UTC", NextRunAt: &past}).Error)
+
+		other := &models.URL{URL: "https://other.example.com", Status: "completed"}
+		require.NoError(t, db.Create(other).Error)
+		require.NoError(t, db.Create(&models.CrawlSchedule{URLID: other.ID, IntervalMinutes: 60, Enabled: false, Timezone: "UTC", NextRunAt: &past}).Error)
+
+		assert.Empty(t, service.RunDue(now))
+	})
+
+	t.Run("starts a due crawl once across replicas", func(t *testing.T) {
+		db, service, url := setupSchedulerTest(t)
+		require.NoError(t, db.Create(&models.CrawlSchedule{URLID: url.ID, IntervalMinutes: 60, Enabled: true, Timezone: "UTC", NextRunAt: &past}).Error)
+
+		// The other replica runs right after this one loaded the due schedules
+		other := NewSchedulerService(db, &mockCrawlerService{})
+		var otherStarted []uint
+		ran := false
+		require.NoError(t, db.Callback().Query().After("gorm:query").Register("test:replica", func(tx *gorm.DB) {
+			if tx.Statement.Table == "crawl_schedules" && !ran {
+				ran = true
+				otherStarted = other.RunDue(now)
+			}
+		}))
+
+		assert.Len(t, append(service.RunDue(now), otherStarted...), 1)
+	})
+}
+
+func TestSchedulerService_DeleteSchedule(t *testing.T) {
+	_, service, url := setupSchedulerTest(t)
+
+	_, err := service.SetSchedule(url.ID, models.CrawlScheduleRequest{IntervalMinutes: 60})
+	require.NoError(t, err)
+
+	require.NoError(t, service.DeleteSchedule(url.ID))
+	assert.ErrorIs(t, service.DeleteSchedule(url.ID), ErrScheduleNotFound)
+}
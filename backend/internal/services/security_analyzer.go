@@ -0,0 +1,169 @@
+package services
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"web-crawler-backend/internal/models"
+)
+
+// hstsMinMaxAge is the HSTS max-age, in seconds, that earns full credit (180 days)
+const hstsMinMaxAge = 15552000
+
+// securityHeaders lists the response headers captured for the security audit
+var securityHeaders = []string{
+	"Strict-Transport-Security",
+	"Content-Security-Policy",
+	"Content-Security-Policy-Report-Only",
+	"X-Frame-Options",
+	"X-Content-Type-Options",
+	"Referrer-Policy",
+	"Permissions-Policy",
+}
+
+// weakReferrerPolicies leak the full URL to other origins
+var weakReferrerPolicies = map[string]bool{
+	"unsafe-url":                 true,
+	"no-referrer-when-downgrade": true,
+}
+
+// analyzeSecurityHeaders scores the security headers of a response. The
+// maximum scores add up to 100, so the total is a percentage.
+func analyzeSecurityHeaders(header http.Header, https bool) (map[string]string, int, []models.SecurityCheck) {
+	captured := map[string]string{}
+	for _, name := range securityHeaders {
+		if value := header.Get(name); value != "" {
+			captured[name] = value
+		}
+	}
+
+	checks := []models.SecurityCheck{
+		checkHSTS(captured["Strict-Transport-Security"], https),
+		checkCSP(captured["Content-Security-Policy"], captured["Content-Security-Policy-Report-Only"]),
+		checkFrameOptions(captured["X-Frame-Options"], captured["Content-Security-Policy"]),
+		checkContentTypeOptions(captured["X-Content-Type-Options"]),
+		checkReferrerPolicy(captured["Referrer-Policy"]),
+	}
+
+	total := 0
+	for _, check := range checks {
+		total += check.Score
+	}
+	return captured, total, checks
+}
+
+func checkHSTS(value string, https bool) models.SecurityCheck {
+	check := models.SecurityCheck{Header: "Strict-Transport-Security", Title: "HTTP Strict Transport Security", MaxScore: 25, Present: value != "", Value: value}
+
+	if !https {
+		check.Message = "The page is served over plain HTTP"
+		return check
+	}
+	if value == "" {
+		check.Message = "HSTS header is missing"
+		return check
+	}
+
+	maxAge := -1
+	for _, directive := range strings.Split(value, ";") {
+		name, arg, _ := strings.Cut(strings.TrimSpace(directive), "=")
+		if strings.EqualFold(name, "max-age") {
+			if seconds, err := strconv.Atoi(strings.Trim(arg, `"`)); err == nil {
+				maxAge = seconds
+			}
+		}
+	}
+
+	switch {
+	case maxAge <= 0:
+		check.Message = "HSTS header has no valid max-age"
+	case maxAge < hstsMinMaxAge:
+		check.Score = check.MaxScore / 2
+		check.Message = fmt.Sprintf("HSTS max-age is %d seconds; use at least %d", maxAge, hstsMinMaxAge)
+	default:
+		check.Passed = true
+		check.Score = check.MaxScore
+		check.Message = fmt.Sprintf("HSTS max-age is %d seconds", maxAge)
+	}
+	return check
+}
+
+func checkCSP(value, reportOnly string) models.SecurityCheck {
+	check := models.SecurityCheck{Header: "Content-Security-Policy", Title: "Content Security Policy", MaxScore: 25, Present: value != "", Value: value}
+
+	switch {
+	case value == "" && reportOnly != "":
+		check.Score = check.MaxScore / 5
+		check.Value = reportOnly
+		check.Message = "CSP is only reported, not enforced"
+	case value == "":
+		check.Message = "CSP header is missing"
+	case strings.Contains(value, "'unsafe-inline'") || strings.Contains(value, "'unsafe-eval'"):
+		check.Score = check.MaxScore / 2
+		check.Message = "CSP allows 'unsafe-inline' or 'unsafe-eval'"
+	default:
+		check.Passed = true
+		check.Score = check.MaxScore
+		check.Message = "CSP is enforced"
+	}
+	return check
+}
+
+func checkFrameOptions(value, csp string) models.SecurityCheck {
+	check := models.SecurityCheck{Header: "X-Frame-Options", Title: "Clickjacking protection", MaxScore: 20, Present: value != "", Value: value}
+
+	switch option := strings.ToUpper(strings.TrimSpace(value)); {
+	case option == "DENY" || option == "SAMEORIGIN":
+		check.Passed = true
+		check.Score = check.MaxScore
+		check.Message = "Framing is restricted to " + strings.ToLower(option)
+	case strings.Contains(csp, "frame-ancestors"):
+		check.Passed = true
+		check.Score = check.MaxScore
+		check.Message = "Framing is restricted by the CSP frame-ancestors directive"
+	case value != "":
+		check.Message = fmt.Sprintf("Unsupported X-Frame-Options value %q", value)
+	default:
+		check.Message = "The page can be framed by any site"
+	}
+	return check
+}
+
+func checkContentTypeOptions(value string) models.SecurityCheck {
+	check := models.SecurityCheck{Header: "X-Content-Type-Options", Title: "MIME sniffing protection", MaxScore: 15, Present: value != "", Value: value}
+
+	switch {
+	case strings.EqualFold(strings.TrimSpace(value), "nosniff"):
+		check.Passed = true
+		check.Score = check.MaxScore
+		check.Message = "MIME sniffing is disabled"
+	case value != "":
+		check.Message = fmt.Sprintf("Unsupported X-Content-Type-Options value %q", value)
+	default:
+		check.Message = "X-Content-Type-Options header is missing"
+	}
+	return check
+}
+
+func checkReferrerPolicy(value string) models.SecurityCheck {
+	check := models.SecurityCheck{Header: "Referrer-Policy", Title: "Referrer policy", MaxScore: 15, Present: value != "", Value: value}
+
+	// Browsers apply the last policy they understand
+	policies := strings.Split(value, ",")
+	policy := strings.ToLower(strings.TrimSpace(policies[len(policies)-1]))
+
+	switch {
+	case policy == "":
+		check.Message = "Referrer-Policy header is missing"
+	case weakReferrerPolicies[policy]:
+		check.Score = check.MaxScore / 3
+		check.Message = fmt.Sprintf("Referrer policy %q leaks full URLs to other sites", policy)
+	default:
+		check.Passed = true
+		check.Score = check.MaxScore
+		check.Message = fmt.Sprintf("Referrer policy is %q", policy)
+	}
+	return check
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"web-crawler-backend/internal/models"
+)
+
+func securityCheckByHeader(checks []models.SecurityCheck, header string) models.SecurityCheck {
+	for _, check := range checks {
+		if check.Header == header {
+			return check
+		}
+	}
+	return models.SecurityCheck{}
+}
+
+func TestAnalyzeSecurityHeaders(t *testing.T) {
+	t.Run("hardened response scores 100", func(t *testing.T) {
+		header := http.Header{}
+		header.Set("Strict-Transport-Security", "max-age=31536000; includeSubDomains")
+		header.Set("Content-Security-Policy", "default-src 'self'")
+		header.Set("X-Frame-Options", "DENY")
+		header.Set("X-Content-Type-Options", "nosniff")
+		header.Set("Referrer-Policy", "strict-origin-when-cross-origin")
+		header.Set("Server", "nginx")
+
+		captured, score, checks := analyzeSecurityHeaders(header, true)
+		assert.Equal(t, 100, score)
+		for _, check := range checks {
+			assert.True(t, check.Passed, check.Header)
+		}
+		assert.Len(t, captured, 5)
+		assert.NotContains(t, captured, "Server")
+	})
+
+	t.Run("bare response scores 0", func(t *testing.T) {
+		captured, score, checks := analyzeSecurityHeaders(http.Header{}, true)
+		assert.Zero(t, score)
+		assert.Empty(t, captured)
+		for _, check := range checks {
+			assert.False(t, check.Present, check.Header)
+		}
+	})
+
+	t.Run("HSTS", func(t *testing.T) {
+		short := checkHSTS("max-age=3600", true)
+		assert.False(t, short.Passed)
+		assert.Equal(t, short.MaxScore/2, short.Score)
+
+		assert.Zero(t, checkHSTS("includeSubDomains", true).Score)
+		assert.Zero(t, checkHSTS("max-age=31536000", false).Score)
+	})
+
+	t.Run("CSP", func(t *testing.T) {
+		unsafe := checkCSP("script-src 'self' 'unsafe-inline'", "")
+		assert.False(t, unsafe.Passed)
+		assert.Equal(t, unsafe.MaxScore/2, unsafe.Score)
+
+		reportOnly := checkCSP("", "default-src 'self'")
+		assert.False(t, reportOnly.Present)
+		assert.Equal(t, "default-src 'self'", reportOnly.Value)
+		assert.Positive(t, reportOnly.Score)
+	})
+
+	t.Run("frame-ancestors replaces X-Frame-Options", func(t *testing.T) {
+		assert.True(t, checkFrameOptions("", "frame-ancestors 'none'").Passed)
+		assert.False(t, checkFrameOptions("ALLOW-FROM https://example.com", "").Passed)
+	})
+
+	t.Run("referrer policy uses the last value", func(t *testing.T) {
+		assert.True(t, checkReferrerPolicy("unsafe-url, strict-origin").Passed)
+		assert.False(t, checkReferrerPolicy("no-referrer-when-downgrade").Passed)
+	})
+}
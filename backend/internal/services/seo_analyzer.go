@@ -0,0 +1,255 @@
+package services
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"web-crawler-backend/internal/models"
+)
+
+// Recommended lengths used by the SEO checks
+const (
+	seoTitleMinLength       = 30
+	seoTitleMaxLength       = 60
+	seoDescriptionMinLength = 50
+	seoDescriptionMaxLength = 160
+	// seoBrokenLinkTolerance is the broken link ratio that still earns partial credit
+	seoBrokenLinkTolerance = 0.05
+	// seoMinMaximumScale is the lowest viewport maximum-scale that still lets users zoom
+	seoMinMaximumScale = 2.0
+)
+
+// analyzeSEO scores a crawled page against a fixed checklist. The maximum
+// scores add up to 100, so the total is a percentage. Informational checks,
+// such as the AMP variant, have a maximum score of 0.
+func analyzeSEO(data *CrawlData) (int, []models.SEOCheck) {
+	checks := []models.SEOCheck{
+		checkTitleLength(data.Title),
+		checkMetaDescription(data.Meta.Description),
+		checkSingleH1(data.HeadingCounts.H1),
+		checkAltCoverage(data.Images),
+		checkCanonical(data.Meta.Canonical),
+		checkBrokenLinks(len(data.Links), data.BrokenLinks),
+		checkFavicon(data.Meta.Icons),
+		checkViewport(data.Meta.Viewport),
+		checkAMP(data.Meta.AMPURL),
+	}
+
+	total := 0
+	for _, check := range checks {
+		total += check.Score
+	}
+	return total, checks
+}
+
+func checkTitleLength(title string) models.SEOCheck {
+	check := models.SEOCheck{Key: "title_length", Title: "Title length", MaxScore: 15}
+	length := utf8.RuneCountInString(title)
+
+	switch {
+	case length == 0:
+		check.Message = "The page has no title"
+	case length < seoTitleMinLength || length > seoTitleMaxLength:
+		check.Score = check.MaxScore / 2
+		check.Message = fmt.Sprintf("Title is %d characters; aim for %d-%d", length, seoTitleMinLength, seoTitleMaxLength)
+	default:
+		check.Passed = true
+		check.Score = check.MaxScore
+		check.Message = fmt.Sprintf("Title is %d characters", length)
+	}
+	return check
+}
+
+func checkMetaDescription(description string) models.SEOCheck {
+	check := models.SEOCheck{Key: "meta_description", Title: "Meta description", MaxScore: 15}
+	length := utf8.RuneCountInString(description)
+
+	switch {
+	case length == 0:
+		check.Message = "The page has no meta description"
+	case length < seoDescriptionMinLength || length > seoDescriptionMaxLength:
+		check.Score = check.MaxScore * 2 / 3
+		check.Message = fmt.Sprintf("Meta description is %d characters; aim for %d-%d", length, seoDescriptionMinLength, seoDescriptionMaxLength)
+	default:
+		check.Passed = true
+		check.Score = check.MaxScore
+		check.Message = fmt.Sprintf("Meta description is %d characters", length)
+	}
+	return check
+}
+
+func checkSingleH1(h1Count int) models.SEOCheck {
+	check := models.SEOCheck{Key: "single_h1", Title: "Single H1 heading", MaxScore: 15}
+
+	switch h1Count {
+	case 0:
+		check.Message = "The page has no H1 heading"
+	case 1:
+		check.Passed = true
+		check.Score = check.MaxScore
+		check.Message = "The page has exactly one H1 heading"
+	default:
+		check.Score = check.MaxScore / 3
+		check.Message = fmt.Sprintf("The page has %d H1 headings", h1Count)
+	}
+	return check
+}
+
+func checkAltCoverage(images []models.Image) models.SEOCheck {
+	check := models.SEOCheck{Key: "image_alt_coverage", Title: "Image alt text", MaxScore: 15}
+
+	if len(images) == 0 {
+		check.Passed = true
+		check.Score = check.MaxScore
+		check.Message = "The page has no images"
+		return check
+	}
+
+	withAlt := 0
+	for _, image := range images {
+		if !image.MissingAlt {
+			withAlt++
+		}
+	}
+
+	check.Passed = withAlt == len(images)
+	check.Score = check.MaxScore * withAlt / len(images)
+	check.Message = fmt.Sprintf("%d of %d images have alt text", withAlt, len(images))
+	return check
+}
+
+func checkCanonical(canonical string) models.SEOCheck {
+	check := models.SEOCheck{Key: "canonical", Title: "Canonical URL", MaxScore: 10}
+
+	if canonical == "" {
+		check.Message = "The page has no canonical link"
+		return check
+	}
+
+	check.Passed = true
+	check.Score = check.MaxScore
+	check.Message = "Canonical URL: " + canonical
+	return check
+}
+
+func checkBrokenLinks(total, broken int) models.SEOCheck {
+	check := models.SEOCheck{Key: "broken_links", Title: "Broken links", MaxScore: 15}
+
+	if broken == 0 {
+		check.Passed = true
+		check.Score = check.MaxScore
+		check.Message = "No broken links found"
+		return check
+	}
+
+	ratio := float64(broken) / float64(total)
+	if ratio <= seoBrokenLinkTolerance {
+		check.Score = check.MaxScore / 2
+	}
+	check.Message = fmt.Sprintf("%d of %d links are broken (%.1f%%)", broken, total, ratio*100)
+	return check
+}
+
+// checkFavicon passes if a linked favicon, or /favicon.ico when none is
+// linked, could be fetched
+func checkFavicon(icons []models.PageIcon) models.SEOCheck {
+	check := models.SEOCheck{Key: "favicon", Title: "Favicon", MaxScore: 5}
+
+	var favicon *models.PageIcon
+	for i := range icons {
+		if icons[i].Kind != models.IconFavicon {
+			continue
+		}
+		if icons[i].IsAccessible {
+			check.Passed = true
+			check.Score = check.MaxScore
+			check.Message = "Favicon: " + icons[i].URL
+			return check
+		}
+		if favicon == nil {
+			favicon = &icons[i]
+		}
+	}
+
+	if favicon == nil {
+		check.Message = "The page has no favicon"
+		return check
+	}
+
+	failure := "could not be fetched"
+	if favicon.StatusCode > 0 {
+		failure = fmt.Sprintf("returned HTTP %d", favicon.StatusCode)
+	}
+	if favicon.Implicit {
+		check.Message = fmt.Sprintf("The page links no favicon and %s %s", favicon.URL, failure)
+	} else {
+		check.Message = fmt.Sprintf("The favicon %s %s", favicon.URL, failure)
+	}
+	return check
+}
+
+// checkViewport passes if the viewport meta tag adapts the page to the
+// device width without preventing zoom
+func checkViewport(viewport string) models.SEOCheck {
+	check := models.SEOCheck{Key: "mobile_viewport", Title: "Mobile viewport", MaxScore: 10}
+
+	if viewport == "" {
+		check.Message = "The page has no viewport meta tag, so mobile browsers render it at desktop width"
+		return check
+	}
+
+	properties := parseViewport(viewport)
+	if properties["width"] != "device-width" {
+		check.Message = "The viewport doesn't set width=device-width: " + viewport
+		return check
+	}
+
+	scalable := properties["user-scalable"]
+	maximumScale, err := strconv.ParseFloat(properties["maximum-scale"], 64)
+	if scalable == "no" || scalable == "0" || (err == nil && maximumScale < seoMinMaximumScale) {
+		check.Score = check.MaxScore / 2
+		check.Message = "The viewport prevents users from zooming: " + viewport
+		return check
+	}
+
+	check.Passed = true
+	check.Score = check.MaxScore
+	check.Message = "Viewport: " + viewport
+	return check
+}
+
+// parseViewport splits the content of a viewport meta tag into lowercase
+// properties. Browsers accept semicolons as separators too.
+func parseViewport(viewport string) map[string]string {
+	properties := map[string]string{}
+	for _, part := range strings.FieldsFunc(strings.ToLower(viewport), func(r rune) bool { return r == ',' || r == ';' }) {
+		key, value, _ := strings.Cut(part, "=")
+		properties[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return properties
+}
+
+// checkAMP reports the AMP variant of the page. Pages don't need one, so the
+// check only fails when the amphtml link isn't an absolute web URL.
+func checkAMP(ampURL string) models.SEOCheck {
+	check := models.SEOCheck{Key: "amp", Title: "AMP version"}
+
+	if ampURL == "" {
+		check.Passed = true
+		check.Message = "The page has no AMP version"
+		return check
+	}
+
+	parsed, err := url.Parse(ampURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		check.Message = "The AMP link is not a valid URL: " + ampURL
+		return check
+	}
+
+	check.Passed = true
+	check.Message = "AMP version: " + ampURL
+	return check
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"web-crawler-backend/internal/models"
+)
+
+func seoCheckByKey(checks []models.SEOCheck, key string) models.SEOCheck {
+	for _, check := range checks {
+		if check.Key == key {
+			return check
+		}
+	}
+	return models.SEOCheck{}
+}
+
+func TestAnalyzeSEO(t *testing.T) {
+	t.Run("well optimized page scores 100", func(t *testing.T) {
+		data := &CrawlData{
+			Title:         "A descriptive page title of good length",
+			HeadingCounts: models.HeadingCounts{H1: 1, H2: 3},
+			Links:         make([]models.Link, 10),
+			Images:        []models.Image{{Alt: "Logo"}, {Alt: ""}},
+			Meta: models.PageMeta{
+				Description: "A meta description that is long enough to be shown in search results.",
+				Canonical:   "https://example.com/",
+				Viewport:    "width=device-width, initial-scale=1",
+				AMPURL:      "https://example.com/amp/",
+				Icons:       []models.PageIcon{{Kind: models.IconFavicon, URL: "https://example.com/favicon.ico", IsAccessible: true}},
+			},
+		}
+
+		score, checks := analyzeSEO(data)
+		assert.Equal(t, 100, score)
+		for _, check := range checks {
+			assert.True(t, check.Passed, check.Key)
+		}
+	})
+
+	t.Run("empty page scores only the checks without content", func(t *testing.T) {
+		score, checks := analyzeSEO(&CrawlData{})
+
+		// No images and no broken links are passing conditions
+		assert.Equal(t, 30, score)
+		assert.False(t, seoCheckByKey(checks, "title_length").Passed)
+		assert.False(t, seoCheckByKey(checks, "meta_description").Passed)
+		assert.False(t, seoCheckByKey(checks, "single_h1").Passed)
+		assert.False(t, seoCheckByKey(checks, "canonical").Passed)
+		assert.Equal(t, "The page has no favicon", seoCheckByKey(checks, "favicon").Message)
+	})
+
+	t.Run("partial credit", func(t *testing.T) {
+		data := &CrawlData{
+			Title:         "Short",
+			HeadingCounts: models.HeadingCounts{H1: 3},
+			Links:         make([]models.Link, 40),
+			BrokenLinks:   2,
+			Images:        []models.Image{{Alt: "a"}, {MissingAlt: true}, {MissingAlt: true}},
+			Meta:          models.PageMeta{Description: strings.Repeat("x", 200)},
+		}
+
+		_, checks := analyzeSEO(data)
+		assert.Equal(t, 7, seoCheckByKey(checks, "title_length").Score)
+		assert.Equal(t, 10, seoCheckByKey(checks, "meta_description").Score)
+		assert.Equal(t, 5, seoCheckByKey(checks, "single_h1").Score)
+		assert.Equal(t, 5, seoCheckByKey(checks, "image_alt_coverage").Score)
+		assert.Equal(t, 7, seoCheckByKey(checks, "broken_links").Score)
+		assert.Equal(t, "2 of 40 links are broken (5.0%)", seoCheckByKey(checks, "broken_links").Message)
+	})
+
+	t.Run("viewport must fit the device and allow zoom", func(t *testing.T) {
+		for viewport, want := range map[string]int{
+			"":                                      0,
+			"width=1024":                            0,
+			"width=device-width; initial-scale=1":   10,
+			"width=device-width, user-scalable=no":  5,
+			"Width=Device-Width, maximum-scale=1.0": 5,
+		} {
+			check := checkViewport(viewport)
+			assert.Equal(t, want, check.Score, viewport)
+			assert.Equal(t, want == 10, check.Passed, viewport)
+		}
+	})
+
+	t.Run("AMP is informational", func(t *testing.T) {
+		assert.True(t, checkAMP("").Passed)
+		assert.False(t, checkAMP("javascript:void(0)").Passed)
+		assert.Zero(t, checkAMP("https://example.com/amp/").MaxScore)
+	})
+
+	t.Run("favicon must be reachable", func(t *testing.T) {
+		data := &CrawlData{Meta: models.PageMeta{Icons: []models.PageIcon{
+			{Kind: models.IconManifest, URL: "https://example.com/site.webmanifest", IsAccessible: true},
+			{Kind: models.IconFavicon, URL: "https://example.com/favicon.ico", Implicit: true, StatusCode: 404},
+		}}}
+
+		_, checks := analyzeSEO(data)
+		favicon := seoCheckByKey(checks, "favicon")
+		assert.False(t, favicon.Passed)
+		assert.Equal(t, "The page links no favicon and https://example.com/favicon.ico returned HTTP 404", favicon.Message)
+	})
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+
+	"web-crawler-backend/internal/models"
+)
+
+var (
+	// ErrShareNotFound is returned for share tokens that are invalid or whose URL is gone
+	ErrShareNotFound = errors.New("shared report not found")
+	// ErrShareExpired is returned for share tokens past their expiry
+	ErrShareExpired = errors.New("shared report link expired")
+	// ErrInvalidShareTTL is returned for links asking to live longer than allowed
+	ErrInvalidShareTTL = errors.New("invalid share link lifetime")
+)
+
+// ShareOptions configures public report links
+type ShareOptions struct {
+	// Secret signs the link tokens
+	Secret string
+	// TTL is how long links stay valid unless they ask for less
+	TTL time.Duration
+	// MaxTTL bounds how long links may ask to stay valid; zero allows TTL only
+	MaxTTL time.Duration
+}
+
+// ShareService creates signed, expiring links that show the crawl results of
+// a URL without authentication. Links are not stored: the token carries the
+// URL and the expiry, so they can't be revoked other than by deleting the URL
+// or rotating the secret.
+type ShareService struct {
+	db         *gorm.DB
+	urlService *URLService
+	crawler    CrawlerServiceInterface
+	options    ShareOptions
+	now        func() time.Time
+}
+
+func NewShareService(db *gorm.DB, urlService *URLService, crawler CrawlerServiceInterface, options ShareOptions) *ShareService {
+	if options.MaxTTL < options.TTL {
+		options.MaxTTL = options.TTL
+	}
+	return &ShareService{db: db, urlService: urlService, crawler: crawler, options: options, now: time.Now}
+}
+
+// WithContext returns a copy of the service whose queries run with ctx, so
+// they are traced as part of the request
+func (s *ShareService) WithContext(ctx context.Context) *ShareService {
+	copied := *s
+	copied.db = s.db.WithContext(ctx)
+	copied.urlService = s.urlService.WithContext(ctx)
+	return &copied
+}
+
+// CreateShare returns a link to the crawl results of a URL that expires after
+// ttl, or after the default lifetime if ttl is zero
+func (s *ShareService) CreateShare(urlID, userID uint, ttl time.Duration) (*models.ShareLink, error) {
+	if ttl == 0 {
+		ttl = s.options.TTL
+	}
+	if ttl < 0 || ttl > s.options.MaxTTL {
+		return nil, fmt.Errorf("%w: links may live at most %s", ErrInvalidShareTTL, s.options.MaxTTL)
+	}
+
+	var url models.URL
+	if err := s.db.Select("id").First(&url, urlID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrURLNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch URL: %w", err)
+	}
+
+	now := s.now()
+	expiresAt := now.Add(ttl)
+	claims := &models.ShareClaims{
+		URLID:     urlID,
+		CreatedBy: userID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{models.ShareAudience},
+			Subject:   strconv.FormatUint(uint64(urlID), 10),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(s.options.Secret))
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign share link: %w", err)
+	}
+
+	return &models.ShareLink{
+		Token:     token,
+		Path:      "/api/v1/public/reports/" + token,
+		ExpiresAt: claims.ExpiresAt.Time.UTC(),
+	}, nil
+}
+
+// parseToken checks the signature, audience and expiry of a share token
+func (s *ShareService) parseToken(token string) (*models.ShareClaims, error) {
+	claims := &models.ShareClaims{}
+	// Claims are checked below against the service's clock
+	parser := jwt.NewParser(jwt.WithoutClaimsValidation())
+	_, err := parser.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return []byte(s.options.Secret), nil
+	})
+	if err != nil || !slices.Contains(claims.Audience, models.ShareAudience) || claims.URLID == 0 {
+		return nil, ErrShareNotFound
+	}
+	if claims.ExpiresAt == nil || s.now().Unix() > claims.ExpiresAt.Unix() {
+		return nil, ErrShareExpired
+	}
+	return claims, nil
+}
+
+// GetSharedReport returns the crawl results a share token links to
+func (s *ShareService) GetSharedReport(token string) (*models.SharedReport, error) {
+	claims, err := s.parseToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	var url models.URL
+	if err := s.db.First(&url, claims.URLID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrShareNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch URL: %w", err)
+	}
+
+	report := &models.SharedReport{
+		URL:         url.URL,
+		Title:       url.Title,
+		HTMLVersion: url.HTMLVersion,
+		Status:      url.Status,
+		ExpiresAt:   claims.ExpiresAt.Time.UTC(),
+	}
+	if report.Crawl, err = s.crawler.GetCrawlStatus(url.ID); err != nil {
+		return nil, err
+	}
+	// Only crawls that completed have results worth showing
+	if report.Crawl.Status == "completed" {
+		if report.SEO, err = s.urlService.GetSEOReport(url.ID); err != nil {
+			return nil, err
+		}
+		if report.Security, err = s.urlService.GetSecurityReport(url.ID); err != nil {
+			return nil, err
+		}
+	}
+	return report, nil
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"web-crawler-backend/internal/models"
+)
+
+func TestShareService(t *testing.T) {
+	db := setupURLTestDB(t)
+	crawler := &mockCrawlerService{}
+	options := ShareOptions{Secret: "secret", TTL: 24 * time.Hour, MaxTTL: 48 * time.Hour}
+	service := NewShareService(db, NewURLService(db, crawler), crawler, options)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	url := &models.URL{URL: "https://example.com", Title: "Example", Status: "completed"}
+	require.NoError(t, db.Create(url).Error)
+	require.NoError(t, db.Create(&models.Crawl{URLID: url.ID, Status: "completed", SEOScore: 80, SecurityScore: 60}).Error)
+
+	link, err := service.CreateShare(url.ID, 1, 0)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(24*time.Hour), link.ExpiresAt)
+	assert.Equal(t, "/api/v1/public/reports/"+link.Token, link.Path)
+
+	report, err := service.GetSharedReport(link.Token)
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com", report.URL)
+	assert.Equal(t, "Example", report.Title)
+	require.NotNil(t, report.SEO)
+	assert.Equal(t, 80, report.SEO.Score)
+	require.NotNil(t, report.Security)
+	assert.Equal(t, 60, report.Security.Score)
+
+	// Links can't outlive the maximum lifetime
+	_, err = service.CreateShare(url.ID, 1, 72*time.Hour)
+	assert.ErrorIs(t, err, ErrInvalidShareTTL)
+	_, err = service.CreateShare(url.ID+1, 1, 0)
+	assert.ErrorIs(t, err, ErrURLNotFound)
+
+	// Tokens signed with another secret, or tampered with, are rejected
+	other := NewShareService(db, NewURLService(db, crawler), crawler, ShareOptions{Secret: "other", TTL: time.Hour})
+	_, err = other.GetSharedReport(link.Token)
+	assert.ErrorIs(t, err, ErrShareNotFound)
+	_, err = service.GetSharedReport(link.Token + "x")
+	assert.ErrorIs(t, err, ErrShareNotFound)
+
+	// Expired links and links of deleted URLs stop working
+	now = now.Add(25 * time.Hour)
+	_, err = service.GetSharedReport(link.Token)
+	assert.ErrorIs(t, err, ErrShareExpired)
+
+	fresh, err := service.CreateShare(url.ID, 1, time.Hour)
+	require.NoError(t, err)
+	require.NoError(t, db.Delete(url).Error)
+	_, err = service.GetSharedReport(fresh.Token)
+	assert.ErrorIs(t, err, ErrShareNotFound)
+}
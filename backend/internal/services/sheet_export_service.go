@@ -0,0 +1,449 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"web-crawler-backend/internal/apperror"
+	"web-crawler-backend/internal/googlesheets"
+	"web-crawler-backend/internal/models"
+)
+
+const (
+	// minSheetExportInterval is the shortest interval of scheduled exports, in minutes
+	minSheetExportInterval = 60
+	// sheetExportBatchSize caps how many due exports one tick runs
+	sheetExportBatchSize = 20
+	// GoogleStateTTL is how long users have to grant access on Google's consent page
+	GoogleStateTTL = 10 * time.Minute
+	// googleNonceLength is the number of random bytes binding an authorization
+	// to the browser that started it
+	googleNonceLength = 32
+	// maxLastErrorLength is the size of the last_error column
+	maxLastErrorLength = 255
+)
+
+var (
+	// ErrGoogleNotConfigured is returned when the deployment has no Google OAuth client
+	ErrGoogleNotConfigured = apperror.New(http.StatusNotImplemented, "Google Sheets not configured",
+		"Google Sheets exports are not enabled on this server").WithCode("google_not_configured")
+	// ErrGoogleNotConnected is returned when the user hasn't authorized their Google account
+	ErrGoogleNotConnected = errors.New("no Google account connected")
+	// ErrInvalidGoogleState is returned for authorization callbacks with a forged or expired state
+	ErrInvalidGoogleState = errors.New("invalid or expired Google authorization")
+	// ErrSheetExportNotFound is returned for exports that don't exist in the organization
+	ErrSheetExportNotFound = apperror.New(http.StatusNotFound, "Sheet export not found",
+		"The requested export does not exist").WithCode("sheet_export_not_found")
+	// ErrInvalidSheetExport is returned for unknown export kinds and intervals out of range
+	ErrInvalidSheetExport = errors.New("invalid sheet export")
+	// ErrSheetsFailed is returned when Google couldn't be reached or refused a request
+	ErrSheetsFailed = errors.New("Google Sheets request failed")
+)
+
+// SheetExportService connects users' Google accounts and pushes URL lists and
+// broken link reports into their spreadsheets, on demand or on a schedule
+type SheetExportService struct {
+	db *gorm.DB
+	// google is nil when the deployment has no OAuth client
+	google *googlesheets.Client
+	// stateSecret signs the state of authorizations
+	stateSecret []byte
+	// organizationID limits the service to the exports of one organization,
+	// 0 for the ones outside any
+	organizationID uint
+	heartbeat      *Heartbeat
+}
+
+// NewSheetExportService creates the service; a nil client disables exports
+func NewSheetExportService(db *gorm.DB, google *googlesheets.Client, stateSecret string) *SheetExportService {
+	return &SheetExportService{db: db, google: google, stateSecret: []byte(stateSecret), heartbeat: &Heartbeat{}}
+}
+
+// WithContext returns a copy of the service whose queries and Google
+// requests run with ctx
+func (s *SheetExportService) WithContext(ctx context.Context) *SheetExportService {
+	copied := *s
+	copied.db = s.db.WithContext(ctx)
+	return &copied
+}
+
+// WithOrganization returns a copy of the service limited to the exports of
+// the organization
+func (s *SheetExportService) WithOrganization(organizationID uint) *SheetExportService {
+	copied := *s
+	copied.organizationID = organizationID
+	return &copied
+}
+
+// Heartbeat reports when the export worker last ticked, for readiness checks
+func (s *SheetExportService) Heartbeat() *Heartbeat {
+	return s.heartbeat
+}
+
+func (s *SheetExportService) context() context.Context {
+	if s.db.Statement.Context != nil {
+		return s.db.Statement.Context
+	}
+	return context.Background()
+}
+
+// AuthorizeURL returns Google's consent page for the user and the nonce of
+// the authorization. Its signed state identifies the user when Google
+// redirects back to the callback; the nonce stays with the browser that
+// started the authorization, so nobody else can complete it.
+func (s *SheetExportService) AuthorizeURL(userID uint) (string, string, error) {
+	if s.google == nil {
+		return "", "", ErrGoogleNotConfigured
+	}
+	random := make([]byte, googleNonceLength)
+	if _, err := rand.Read(random); err != nil {
+		return "", "", fmt.Errorf("failed to generate authorization nonce: %w", err)
+	}
+	nonce := base64.RawURLEncoding.EncodeToString(random)
+
+	now := time.Now()
+	state, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Audience:  jwt.ClaimStrings{models.GoogleOAuthAudience},
+		Subject:   strconv.FormatUint(uint64(userID), 10),
+		ID:        nonce,
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(GoogleStateTTL)),
+	}).SignedString(s.stateSecret)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to sign authorization state: %w", err)
+	}
+	return s.google.AuthCodeURL(state), nonce, nil
+}
+
+// Connect completes an authorization: it checks the state and that nonce is
+// the one of the state, trades the code for tokens and stores them for the
+// user the state was issued to
+func (s *SheetExportService) Connect(state, nonce, code string) error {
+	if s.google == nil {
+		return ErrGoogleNotConfigured
+	}
+	claims := &jwt.RegisteredClaims{}
+	_, err := jwt.NewParser(jwt.WithAudience(models.GoogleOAuthAudience), jwt.WithExpirationRequired()).
+		ParseWithClaims(state, claims, func(token *jwt.Token) (interface{}, error) {
+			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+			}
+			return s.stateSecret, nil
+		})
+	if err != nil || nonce == "" || subtle.ConstantTimeCompare([]byte(claims.ID), []byte(nonce)) != 1 {
+		return ErrInvalidGoogleState
+	}
+	userID, err := strconv.ParseUint(claims.Subject, 10, 32)
+	if err != nil || userID == 0 {
+		return ErrInvalidGoogleState
+	}
+
+	token, err := s.google.Exchange(s.context(), code)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrSheetsFailed, err)
+	}
+	if token.RefreshToken == "" {
+		return fmt.Errorf("%w: Google returned no refresh token", ErrSheetsFailed)
+	}
+
+	connection := models.GoogleConnection{
+		UserID:       uint(userID),
+		RefreshToken: token.RefreshToken,
+		AccessToken:  token.AccessToken,
+		ExpiresAt:    token.Expiry,
+	}
+	if err := s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"refresh_token", "access_token", "expires_at", "updated_at"}),
+	}).Create(&connection).Error; err != nil {
+		return fmt.Errorf("failed to save Google connection: %w", err)
+	}
+	return nil
+}
+
+// GetConnection tells whether exports are enabled and the user connected
+func (s *SheetExportService) GetConnection(userID uint) (*models.GoogleConnectionStatus, error) {
+	status := &models.GoogleConnectionStatus{Configured: s.google != nil}
+	var connection models.GoogleConnection
+	if err := s.db.Where("user_id = ?", userID).First(&connection).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return status, nil
+		}
+		return nil, fmt.Errorf("failed to fetch Google connection: %w", err)
+	}
+	status.Connected = true
+	status.ConnectedAt = &connection.UpdatedAt
+	return status, nil
+}
+
+// Disconnect forgets the user's Google tokens; their exports fail until
+// they connect again
+func (s *SheetExportService) Disconnect(userID uint) error {
+	result := s.db.Where("user_id = ?", userID).Delete(&models.GoogleConnection{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete Google connection: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrGoogleNotConnected
+	}
+	return nil
+}
+
+// accessToken returns a valid access token of the user, refreshing it when
+// it is about to expire. A revoked authorization removes the connection.
+func (s *SheetExportService) accessToken(userID uint) (string, error) {
+	var connection models.GoogleConnection
+	if err := s.db.Where("user_id = ?", userID).First(&connection).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", ErrGoogleNotConnected
+		}
+		return "", fmt.Errorf("failed to fetch Google connection: %w", err)
+	}
+	if connection.AccessToken != "" && time.Until(connection.ExpiresAt) > time.Minute {
+		return connection.AccessToken, nil
+	}
+
+	token, err := s.google.Refresh(s.context(), connection.RefreshToken)
+	if errors.Is(err, googlesheets.ErrRevoked) {
+		s.db.Delete(&connection)
+		return "", ErrGoogleNotConnected
+	}
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrSheetsFailed, err)
+	}
+	if err := s.db.Model(&connection).Updates(map[string]interface{}{
+		"access_token": token.AccessToken,
+		"expires_at":   token.Expiry,
+	}).Error; err != nil {
+		log.Printf("Failed to save Google access token of user %d: %v", userID, err)
+	}
+	return token.AccessToken, nil
+}
+
+// CreateExport creates an export for the user and runs it. Without a
+// spreadsheet ID a spreadsheet is created in the user's Google Drive.
+func (s *SheetExportService) CreateExport(userID uint, req *models.CreateSheetExportRequest) (*models.SheetExport, error) {
+	if s.google == nil {
+		return nil, ErrGoogleNotConfigured
+	}
+	if req.Kind != models.SheetExportURLs && req.Kind != models.SheetExportBrokenLinks {
+		return nil, fmt.Errorf("%w: kind must be %q or %q", ErrInvalidSheetExport, models.SheetExportURLs, models.SheetExportBrokenLinks)
+	}
+	if req.IntervalMinutes != 0 && req.IntervalMinutes < minSheetExportInterval {
+		return nil, fmt.Errorf("%w: interval_minutes must be 0 or at least %d", ErrInvalidSheetExport, minSheetExportInterval)
+	}
+
+	export := &models.SheetExport{
+		UserID:          userID,
+		OrganizationID:  s.organizationID,
+		Kind:            req.Kind,
+		SpreadsheetID:   req.SpreadsheetID,
+		IntervalMinutes: req.IntervalMinutes,
+	}
+	if export.SpreadsheetID == "" {
+		accessToken, err := s.accessToken(userID)
+		if err != nil {
+			return nil, err
+		}
+		title := "URL list"
+		if req.Kind == models.SheetExportBrokenLinks {
+			title = "Broken links"
+		}
+		spreadsheet, err := s.google.CreateSpreadsheet(s.context(), accessToken, title)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrSheetsFailed, err)
+		}
+		export.SpreadsheetID = spreadsheet.ID
+		export.SpreadsheetURL = spreadsheet.URL
+	} else {
+		export.SpreadsheetURL = "https://docs.google.com/spreadsheets/d/" + export.SpreadsheetID
+	}
+
+	now := time.Now()
+	if export.IntervalMinutes > 0 {
+		next := now.Add(time.Duration(export.IntervalMinutes) * time.Minute)
+		export.NextRunAt = &next
+	}
+	if err := s.db.Create(export).Error; err != nil {
+		return nil, fmt.Errorf("failed to create sheet export: %w", err)
+	}
+
+	if err := s.run(export, now); err != nil {
+		return export, err
+	}
+	return export, nil
+}
+
+// ListExports returns the exports of the organization, newest first
+func (s *SheetExportService) ListExports() ([]models.SheetExport, error) {
+	exports := []models.SheetExport{}
+	if err := s.db.Where("organization_id = ?", s.organizationID).
+		Order("created_at DESC, id DESC").Find(&exports).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch sheet exports: %w", err)
+	}
+	return exports, nil
+}
+
+// GetExport returns an export of the organization
+func (s *SheetExportService) GetExport(id uint) (*models.SheetExport, error) {
+	var export models.SheetExport
+	if err := s.db.Where("id = ? AND organization_id = ?", id, s.organizationID).First(&export).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSheetExportNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch sheet export: %w", err)
+	}
+	return &export, nil
+}
+
+// RunExport writes an export now, without waiting for its interval. It
+// writes with the Google account of the user who created the export.
+func (s *SheetExportService) RunExport(id uint) (*models.SheetExport, error) {
+	if s.google == nil {
+		return nil, ErrGoogleNotConfigured
+	}
+	export, err := s.GetExport(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.run(export, time.Now()); err != nil {
+		return export, err
+	}
+	return export, nil
+}
+
+// DeleteExport stops an export; the spreadsheet is kept
+func (s *SheetExportService) DeleteExport(id uint) error {
+	result := s.db.Where("id = ? AND organization_id = ?", id, s.organizationID).Delete(&models.SheetExport{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete sheet export: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrSheetExportNotFound
+	}
+	return nil
+}
+
+// Start runs due exports every tick until the returned stop function is called
+func (s *SheetExportService) Start(tick time.Duration) (stop func()) {
+	ticker := time.NewTicker(tick)
+	s.heartbeat.start(tick, time.Now())
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case now := <-ticker.C:
+				s.RunDue(now)
+				s.heartbeat.beat(now)
+			case <-done:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+
+	return func() { close(done) }
+}
+
+// RunDue runs the exports that are due and returns how many succeeded
+func (s *SheetExportService) RunDue(now time.Time) int {
+	if s.google == nil {
+		return 0
+	}
+	var exports []models.SheetExport
+	if err := s.db.Where("interval_minutes > 0 AND next_run_at <= ?", now).
+		Order("next_run_at ASC").Limit(sheetExportBatchSize).Find(&exports).Error; err != nil {
+		log.Printf("Failed to load due sheet exports: %v", err)
+		return 0
+	}
+
+	succeeded := 0
+	for i := range exports {
+		export := &exports[i]
+
+		// Claim the run first so a slow export isn't started again on the next
+		// tick, nor by another replica
+		next := now.Add(time.Duration(export.IntervalMinutes) * time.Minute)
+		claimed, err := claimRun(s.db, &models.SheetExport{}, export.ID, "next_run_at", *export.NextRunAt, map[string]interface{}{
+			"next_run_at": next,
+		})
+		if err != nil {
+			log.Printf("Failed to update sheet export %d: %v", export.ID, err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		if err := s.run(export, now); err != nil {
+			log.Printf("Failed to run sheet export %d: %v", export.ID, err)
+			continue
+		}
+		succeeded++
+	}
+	return succeeded
+}
+
+// run writes the rows of an export to its spreadsheet and records the outcome
+func (s *SheetExportService) run(export *models.SheetExport, now time.Time) error {
+	rows, err := s.rows(export)
+	if err == nil {
+		var accessToken string
+		if accessToken, err = s.accessToken(export.UserID); err == nil {
+			if writeErr := s.google.WriteRows(s.context(), accessToken, export.SpreadsheetID, rows); writeErr != nil {
+				err = fmt.Errorf("%w: %v", ErrSheetsFailed, writeErr)
+			}
+		}
+	}
+
+	export.LastRunAt = &now
+	export.LastError = ""
+	if err != nil {
+		export.LastError = err.Error()
+		if len(export.LastError) > maxLastErrorLength {
+			export.LastError = export.LastError[:maxLastErrorLength]
+		}
+	} else {
+		export.LastRows = len(rows) - 1
+	}
+	if saveErr := s.db.Model(export).Updates(map[string]interface{}{
+		"last_run_at": export.LastRunAt,
+		"last_rows":   export.LastRows,
+		"last_error":  export.LastError,
+	}).Error; saveErr != nil {
+		log.Printf("Failed to record run of sheet export %d: %v", export.ID, saveErr)
+	}
+	return err
+}
+
+// rows returns the header and rows of an export: the site summaries or the
+// broken links of the bundle reports, for the URLs of its organization
+func (s *SheetExportService) rows(export *models.SheetExport) ([][]string, error) {
+	var urlIDs []uint
+	if err := s.db.Model(&models.URL{}).Where("organization_id = ?", export.OrganizationID).
+		Order("id ASC").Limit(maxBundleURLs).Pluck("id", &urlIDs).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch URLs: %w", err)
+	}
+
+	reports, err := (&ReportService{db: s.db}).loadSiteReports(urlIDs)
+	if err != nil {
+		return nil, err
+	}
+	if export.Kind == models.SheetExportBrokenLinks {
+		return brokenLinkRows(reports), nil
+	}
+	return summaryRows(reports), nil
+}
@@ -0,0 +1,281 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"web-crawler-backend/internal/googlesheets"
+	"web-crawler-backend/internal/models"
+)
+
+// googleServer fakes Google's token endpoint and the Sheets API, keeping
+// the rows written to each spreadsheet
+type googleServer struct {
+	*httptest.Server
+	mu      sync.Mutex
+	revoked bool
+	sheets  map[string][][]string
+}
+
+func newGoogleServer(t *testing.T) *googleServer {
+	server := &googleServer{sheets: map[string][][]string{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		server.mu.Lock()
+		defer server.mu.Unlock()
+		require.NoError(t, r.ParseForm())
+		switch {
+		case r.PostForm.Get("grant_type") == "authorization_code" && r.PostForm.Get("code") == "code-1":
+			w.Write([]byte(`{"access_token": "access-1", "refresh_token": "refresh-1", "expires_in": 3600}`))
+		case r.PostForm.Get("grant_type") == "refresh_token" && !server.revoked:
+			w.Write([]byte(`{"access_token": "access-2", "expires_in": 3600}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error": "invalid_grant"}`))
+		}
+	})
+	mux.HandleFunc("/v4/spreadsheets", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"spreadsheetId": "sheet-1", "spreadsheetUrl": "https://docs.google.com/spreadsheets/d/sheet-1/edit"}`))
+	})
+	mux.HandleFunc("/v4/spreadsheets/", func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer access-") {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		id := strings.Split(strings.TrimPrefix(r.URL.Path, "/v4/spreadsheets/"), "/")[0]
+		server.mu.Lock()
+		defer server.mu.Unlock()
+		if r.Method == http.MethodPut {
+			var body struct {
+				Values [][]string `json:"values"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			server.sheets[id] = body.Values
+		}
+		w.Write([]byte(`{}`))
+	})
+	server.Server = httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func (s *googleServer) rows(spreadsheetID string) [][]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sheets[spreadsheetID]
+}
+
+func setupSheetExportTest(t *testing.T) (*SheetExportService, *gorm.DB, *googleServer) {
+	db := setupURLTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.GoogleConnection{}, &models.SheetExport{}))
+	server := newGoogleServer(t)
+	client, err := googlesheets.New(googlesheets.Options{
+		ClientID:     "client",
+		ClientSecret: "secret",
+		RedirectURL:  "https://crawler.example.com/api/v1/integrations/google/callback",
+		TokenURL:     server.URL + "/token",
+		APIURL:       server.URL,
+	})
+	require.NoError(t, err)
+	return NewSheetExportService(db, client, "state-secret"), db, server
+}
+
+// connectGoogle runs the authorization of the user as Google's redirect would
+func connectGoogle(t *testing.T, service *SheetExportService, userID uint) {
+	consent, nonce, err := service.AuthorizeURL(userID)
+	require.NoError(t, err)
+	parsed, err := url.Parse(consent)
+	require.NoError(t, err)
+	require.NoError(t, service.Connect(parsed.Query().Get("state"), nonce, "code-1"))
+}
+
+func TestSheetExportService_Connect(t *testing.T) {
+	service, db, _ := setupSheetExportTest(t)
+
+	status, err := service.GetConnection(1)
+	require.NoError(t, err)
+	assert.True(t, status.Configured)
+	assert.False(t, status.Connected)
+
+	connectGoogle(t, service, 1)
+	status, err = service.GetConnection(1)
+	require.NoError(t, err)
+	assert.True(t, status.Connected)
+
+	// Connecting again replaces the tokens
+	connectGoogle(t, service, 1)
+	var count int64
+	require.NoError(t, db.Model(&models.GoogleConnection{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+
+	t.Run("forged states", func(t *testing.T) {
+		other := NewSheetExportService(db, service.google, "other-secret")
+		consent, nonce, err := other.AuthorizeURL(2)
+		require.NoError(t, err)
+		parsed, err := url.Parse(consent)
+		require.NoError(t, err)
+		assert.ErrorIs(t, service.Connect(parsed.Query().Get("state"), nonce, "code-1"), ErrInvalidGoogleState)
+		assert.ErrorIs(t, service.Connect("not-a-token", nonce, "code-1"), ErrInvalidGoogleState)
+	})
+
+	t.Run("states of another browser", func(t *testing.T) {
+		consent, _, err := service.AuthorizeURL(2)
+		require.NoError(t, err)
+		_, otherNonce, err := service.AuthorizeURL(2)
+		require.NoError(t, err)
+		parsed, err := url.Parse(consent)
+		require.NoError(t, err)
+		state := parsed.Query().Get("state")
+		assert.ErrorIs(t, service.Connect(state, "", "code-1"), ErrInvalidGoogleState)
+		assert.ErrorIs(t, service.Connect(state, otherNonce, "code-1"), ErrInvalidGoogleState)
+	})
+
+	t.Run("rejected codes", func(t *testing.T) {
+		consent, nonce, err := service.AuthorizeURL(2)
+		require.NoError(t, err)
+		parsed, err := url.Parse(consent)
+		require.NoError(t, err)
+		assert.ErrorIs(t, service.Connect(parsed.Query().Get("state"), nonce, "code-2"), ErrSheetsFailed)
+	})
+
+	require.NoError(t, service.Disconnect(1))
+	assert.ErrorIs(t, service.Disconnect(1), ErrGoogleNotConnected)
+
+	t.Run("not configured", func(t *testing.T) {
+		disabled := NewSheetExportService(db, nil, "state-secret")
+		_, _, err := disabled.AuthorizeURL(1)
+		assert.ErrorIs(t, err, ErrGoogleNotConfigured)
+		status, err := disabled.GetConnection(1)
+		require.NoError(t, err)
+		assert.False(t, status.Configured)
+	})
+}
+
+func TestSheetExportService_CreateExport(t *testing.T) {
+	service, db, server := setupSheetExportTest(t)
+	userID := uint(1)
+	example := &models.URL{URL: "https://example.com", Title: "Example", UserID: &userID}
+	require.NoError(t, db.Create(example).Error)
+	require.NoError(t, db.Create(&models.URL{URL: "https://example.org", OrganizationID: 7}).Error)
+	crawl := &models.Crawl{URLID: example.ID, Status: "completed", BrokenLinks: 1}
+	require.NoError(t, db.Create(crawl).Error)
+	require.NoError(t, db.Create(&models.Link{URLID: example.ID, CrawlID: crawl.ID, LinkURL: "https://example.com/missing", LinkType: "internal", StatusCode: 404}).Error)
+
+	_, err := service.CreateExport(userID, &models.CreateSheetExportRequest{Kind: models.SheetExportURLs})
+	assert.ErrorIs(t, err, ErrGoogleNotConnected)
+	connectGoogle(t, service, userID)
+
+	export, err := service.CreateExport(userID, &models.CreateSheetExportRequest{Kind: models.SheetExportURLs})
+	require.NoError(t, err)
+	assert.Equal(t, "sheet-1", export.SpreadsheetID)
+	assert.Equal(t, 1, export.LastRows, "URLs of other organizations aren't exported")
+	rows := server.rows("sheet-1")
+	require.Len(t, rows, 2)
+	assert.Contains(t, rows[1], "https://example.com")
+
+	export, err = service.CreateExport(userID, &models.CreateSheetExportRequest{Kind: models.SheetExportBrokenLinks, SpreadsheetID: "sheet-2"})
+	require.NoError(t, err)
+	assert.Equal(t, "https://docs.google.com/spreadsheets/d/sheet-2", export.SpreadsheetURL)
+	rows = server.rows("sheet-2")
+	require.Len(t, rows, 2)
+	assert.Contains(t, rows[1], "https://example.com/missing")
+
+	tests := []struct {
+		name string
+		req  models.CreateSheetExportRequest
+	}{
+		{"unknown kind", models.CreateSheetExportRequest{Kind: "crawls"}},
+		{"short interval", models.CreateSheetExportRequest{Kind: models.SheetExportURLs, IntervalMinutes: 30}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.CreateExport(userID, &tt.req)
+			assert.ErrorIs(t, err, ErrInvalidSheetExport)
+		})
+	}
+
+	t.Run("exports of other organizations", func(t *testing.T) {
+		_, err := service.WithOrganization(7).RunExport(export.ID)
+		assert.ErrorIs(t, err, ErrSheetExportNotFound)
+		assert.ErrorIs(t, service.WithOrganization(7).DeleteExport(export.ID), ErrSheetExportNotFound)
+
+		exports, err := service.ListExports()
+		require.NoError(t, err)
+		assert.Len(t, exports, 2)
+	})
+}
+
+func TestSheetExportService_RunDue(t *testing.T) {
+	service, db, server := setupSheetExportTest(t)
+	userID := uint(1)
+	connectGoogle(t, service, userID)
+
+	export, err := service.CreateExport(userID, &models.CreateSheetExportRequest{
+		Kind: models.SheetExportURLs, SpreadsheetID: "sheet-1", IntervalMinutes: 60,
+	})
+	require.NoError(t, err)
+	_, err = service.CreateExport(userID, &models.CreateSheetExportRequest{Kind: models.SheetExportURLs, SpreadsheetID: "sheet-2"})
+	require.NoError(t, err)
+	assert.Len(t, server.rows("sheet-1"), 1, "only the header without URLs")
+
+	now := time.Now()
+	assert.Zero(t, service.RunDue(now), "not due yet, and exports without an interval never are")
+
+	require.NoError(t, db.Create(&models.URL{URL: "https://example.com", UserID: &userID}).Error)
+	assert.Equal(t, 1, service.RunDue(now.Add(61*time.Minute)))
+	assert.Len(t, server.rows("sheet-1"), 2)
+
+	var stored models.SheetExport
+	require.NoError(t, db.First(&stored, export.ID).Error)
+	assert.True(t, stored.NextRunAt.After(now.Add(2*time.Hour)))
+
+	t.Run("revoked authorizations", func(t *testing.T) {
+		// Expire the access token so the next run has to refresh it
+		require.NoError(t, db.Model(&models.GoogleConnection{}).Where("user_id = ?", userID).
+			Update("expires_at", now).Error)
+		server.mu.Lock()
+		server.revoked = true
+		server.mu.Unlock()
+
+		assert.Zero(t, service.RunDue(now.Add(3*time.Hour)))
+		require.NoError(t, db.First(&stored, export.ID).Error)
+		assert.Contains(t, stored.LastError, ErrGoogleNotConnected.Error())
+
+		status, err := service.GetConnection(userID)
+		require.NoError(t, err)
+		assert.False(t, status.Connected)
+	})
+}
+
+func TestSheetExportService_RunDueAcrossReplicas(t *testing.T) {
+	service, db, _ := setupSheetExportTest(t)
+	userID := uint(1)
+	connectGoogle(t, service, userID)
+	_, err := service.CreateExport(userID, &models.CreateSheetExportRequest{
+		Kind: models.SheetExportURLs, SpreadsheetID: "sheet-1", IntervalMinutes: 60,
+	})
+	require.NoError(t, err)
+
+	// The other replica runs right after this one loaded the due exports
+	now := time.Now().Add(61 * time.Minute)
+	other := NewSheetExportService(db, service.google, "state-secret")
+	otherSucceeded, ran := 0, false
+	require.NoError(t, db.Callback().Query().After("gorm:query").Register("test:replica", func(tx *gorm.DB) {
+		if tx.Statement.Table == "sheet_exports" && !ran {
+			ran = true
+			otherSucceeded = other.RunDue(now)
+		}
+	}))
+
+	assert.Equal(t, 1, service.RunDue(now)+otherSucceeded)
+}
@@ -0,0 +1,301 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+
+	"golang.org/x/net/html"
+
+	"web-crawler-backend/internal/models"
+)
+
+const (
+	// maxCrawlDepth caps how many links deep a site may be crawled
+	maxCrawlDepth = 10
+	// maxCrawlPagesLimit caps the per-URL page limit
+	maxCrawlPagesLimit = 5000
+)
+
+// pageJob is a page waiting to be fetched during a deep crawl
+type pageJob struct {
+	url   string
+	depth int
+}
+
+// crawlSite follows internal links from the root page breadth-first and
+// stores every visited page. The root page has already been fetched and
+// extracted by performCrawl. Links to pages that turn out broken are recorded
+// with the pages they were found on.
+func (s *CrawlerService) crawlSite(urlRecord *models.URL, crawl *models.Crawl, root *CrawlData, rootStatus int, throttle *HostThrottle) {
+	rootURL, err := url.Parse(urlRecord.URL)
+	if err != nil {
+		return
+	}
+
+	maxPages := s.pageLimit(urlRecord)
+	scope := newCrawlScope(urlRecord, rootURL)
+	rootKey := normalizePageURL(rootURL)
+	visited := map[string]bool{rootKey: true}
+	broken := map[string]int{}
+
+	s.savePage(urlRecord, crawl, &models.Page{PageURL: urlRecord.URL, Depth: 0, StatusCode: rootStatus, ResponseMetrics: crawl.ResponseMetrics}, root)
+	crawl.PagesCrawled = 1
+	if urlRecord.MaxDepth > 0 {
+		s.savePageLinks(urlRecord, crawl, rootKey, root.Links, scope)
+	}
+
+	extractors := s.pipeline(urlRecord.DisabledExtractors)
+	queue := s.enqueueLinks(nil, root.Links, scope, 1, urlRecord.MaxDepth, visited)
+	for len(queue) > 0 && crawl.PagesCrawled < maxPages {
+		job := queue[0]
+		queue = queue[1:]
+
+		page := &models.Page{PageURL: job.url, Depth: job.depth}
+		data, err := s.fetchPage(job.url, throttle, page, extractors)
+		if err != nil {
+			page.ErrorMessage = err.Error()
+			if page.StatusCode == 0 || page.StatusCode >= 400 {
+				broken[job.url] = page.StatusCode
+			}
+		}
+		s.savePage(urlRecord, crawl, page, data)
+		crawl.PagesCrawled++
+
+		if data != nil {
+			s.savePageLinks(urlRecord, crawl, job.url, data.Links, scope)
+			queue = s.enqueueLinks(queue, data.Links, scope, job.depth+1, urlRecord.MaxDepth, visited)
+		}
+	}
+
+	if len(broken) > 0 {
+		s.recordBrokenPages(urlRecord, crawl, root, scope, rootKey, broken)
+	}
+}
+
+// recordBrokenPages flags the links to pages that failed during a deep crawl
+// as broken. Links of the root page are updated; links on deeper pages, known
+// from the link graph, are stored as broken internal links found on those
+// pages and counted like the links of the root page.
+func (s *CrawlerService) recordBrokenPages(urlRecord *models.URL, crawl *models.Crawl, root *CrawlData, scope *crawlScope, rootKey string, broken map[string]int) {
+	for i := range root.Links {
+		link := &root.Links[i]
+		target, ok := scope.resolve(link.LinkURL)
+		status, isBroken := broken[target]
+		if !ok || !isBroken || !link.IsAccessible {
+			continue
+		}
+
+		link.StatusCode = status
+		link.IsAccessible = false
+		if err := s.db.Model(&models.Link{}).Where("id = ?", link.ID).
+			Updates(map[string]interface{}{"status_code": status, "is_accessible": false}).Error; err != nil {
+			log.Printf("Failed to flag broken link %s: %v", link.LinkURL, err)
+			continue
+		}
+		crawl.BrokenLinks++
+	}
+
+	targets := make([]string, 0, len(broken))
+	for target := range broken {
+		targets = append(targets, target)
+	}
+	var edges []models.PageLink
+	if err := s.db.Where("crawl_id = ? AND source_url <> ? AND target_url IN ?", crawl.ID, rootKey, targets).
+		Order("id").Find(&edges).Error; err != nil {
+		log.Printf("Failed to find the pages linking to broken pages of URL %s: %v", urlRecord.URL, err)
+		return
+	}
+
+	links := make([]models.Link, 0, len(edges))
+	for _, edge := range edges {
+		links = append(links, models.Link{
+			URLID:       urlRecord.ID,
+			CrawlID:     crawl.ID,
+			LinkURL:     edge.TargetURL,
+			FoundOnURL:  edge.SourceURL,
+			LinkType:    "internal",
+			StatusCode:  broken[edge.TargetURL],
+			Occurrences: 1,
+		})
+	}
+	if err := createInBatches(s.db, links, s.insertBatchSize()); err != nil {
+		log.Printf("Failed to save broken links of URL %s: %v", urlRecord.URL, err)
+		return
+	}
+	crawl.InternalLinks += len(links)
+	crawl.BrokenLinks += len(links)
+}
+
+// pageLimit returns how many pages may be visited for a URL, capped by the
+// page quota of the user who added it
+func (s *CrawlerService) pageLimit(urlRecord *models.URL) int {
+	limit := DefaultCrawlerOptions().MaxPages
+	if urlRecord.MaxPages > 0 {
+		limit = urlRecord.MaxPages
+	} else if s.options.MaxPages > 0 {
+		limit = s.options.MaxPages
+	}
+
+	if urlRecord.UserID != nil {
+		quota, err := s.quota.PageLimit(*urlRecord.UserID)
+		if err != nil {
+			log.Printf("Failed to read page quota of user %d: %v", *urlRecord.UserID, err)
+		}
+		if quota > 0 && quota < limit {
+			limit = quota
+		}
+	}
+	return limit
+}
+
+// ownerID returns the ID of the user who added a URL, or 0 if unknown
+func ownerID(urlRecord *models.URL) uint {
+	if urlRecord.UserID == nil {
+		return 0
+	}
+	return *urlRecord.UserID
+}
+
+// enqueueLinks adds unvisited in-scope links to the queue if they are within the depth limit
+func (s *CrawlerService) enqueueLinks(queue []pageJob, links []models.Link, scope *crawlScope, depth, maxDepth int, visited map[string]bool) []pageJob {
+	if depth > maxDepth {
+		return queue
+	}
+
+	for _, link := range links {
+		key, ok := scope.resolve(link.LinkURL)
+		if !ok || visited[key] {
+			continue
+		}
+		visited[key] = true
+		queue = append(queue, pageJob{url: key, depth: depth})
+	}
+
+	return queue
+}
+
+// fetchPage downloads and extracts a single page of a deep crawl. Links on the
+// page are collected for traversal but not checked for accessibility, and
+// document extractors are skipped.
+func (s *CrawlerService) fetchPage(pageURL string, throttle *HostThrottle, page *models.Page, extractors extractorPipeline) (*CrawlData, error) {
+	host := hostOf(pageURL)
+	throttle.Acquire(host)
+	start := time.Now()
+	resp, timer, err := timedGet(s.traceContext(), pageURL)
+	if err != nil {
+		throttle.Release(host, time.Since(start), 0)
+		return nil, fmt.Errorf("HTTP request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	page.StatusCode = resp.StatusCode
+	page.ResponseMetrics = timer.Metrics
+	if resp.StatusCode >= 400 {
+		throttle.Release(host, time.Since(start), resp.StatusCode)
+		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, resp.Status)
+	}
+	if err := checkContentType(resp); err != nil {
+		throttle.Release(host, time.Since(start), resp.StatusCode)
+		return nil, err
+	}
+	if err := checkContentLength(resp, s.responseLimit()); err != nil {
+		throttle.Release(host, time.Since(start), resp.StatusCode)
+		return nil, err
+	}
+
+	err = timer.readBody(resp, s.responseLimit())
+	throttle.Release(host, time.Since(start), resp.StatusCode)
+	page.ResponseMetrics = timer.Metrics
+	if _, ok := err.(*skipError); ok {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading response failed: %v", err)
+	}
+
+	doc, err := html.Parse(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("HTML parsing failed: %v", err)
+	}
+
+	baseURL, err := url.Parse(pageURL)
+	if err != nil {
+		return nil, err
+	}
+
+	data := &CrawlData{
+		HTMLVersion: "Unknown",
+		Links:       []models.Link{},
+		Meta: models.PageMeta{
+			OpenGraph:   map[string]string{},
+			TwitterCard: map[string]string{},
+		},
+	}
+	extractors.traverse(doc, &PageContext{BaseURL: baseURL, Data: data})
+	data.ContentHash = contentHash(doc)
+	return data, nil
+}
+
+// savePage stores a visited page with its heading structure
+func (s *CrawlerService) savePage(urlRecord *models.URL, crawl *models.Crawl, page *models.Page, data *CrawlData) {
+	page.URLID = urlRecord.ID
+	page.CrawlID = crawl.ID
+	if data != nil {
+		page.Title = data.Title
+		headingCountsJSON, _ := json.Marshal(data.HeadingCounts)
+		page.HeadingCounts = string(headingCountsJSON)
+		page.HeadingDepth = headingDepth(data.HeadingCounts)
+		page.Canonical = data.Meta.Canonical
+		page.ContentHash = data.ContentHash
+	}
+
+	if err := s.db.Create(page).Error; err != nil {
+		log.Printf("Failed to save page %s: %v", page.PageURL, err)
+	}
+}
+
+// savePageLinks stores the link graph edges from a page to the in-scope pages it links to
+func (s *CrawlerService) savePageLinks(urlRecord *models.URL, crawl *models.Crawl, source string, links []models.Link, scope *crawlScope) {
+	seen := map[string]bool{source: true}
+	var edges []models.PageLink
+	for _, link := range links {
+		target, ok := scope.resolve(link.LinkURL)
+		if !ok || seen[target] {
+			continue
+		}
+		seen[target] = true
+		edges = append(edges, models.PageLink{URLID: urlRecord.ID, CrawlID: crawl.ID, SourceURL: source, TargetURL: target})
+	}
+
+	if len(edges) == 0 {
+		return
+	}
+	if err := createInBatches(s.db, edges, s.insertBatchSize()); err != nil {
+		log.Printf("Failed to save links of page %s: %v", source, err)
+	}
+}
+
+// headingDepth returns the deepest heading level present, or 0 without headings
+func headingDepth(counts models.HeadingCounts) int {
+	levels := []int{counts.H1, counts.H2, counts.H3, counts.H4, counts.H5, counts.H6}
+	for i := len(levels) - 1; i >= 0; i-- {
+		if levels[i] > 0 {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// normalizePageURL drops the fragment so in-page anchors aren't crawled twice
+func normalizePageURL(u *url.URL) string {
+	normalized := *u
+	normalized.Fragment = ""
+	normalized.RawFragment = ""
+	if normalized.Path == "" {
+		normalized.Path = "/"
+	}
+	return normalized.String()
+}
@@ -0,0 +1,477 @@
+package services
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"web-crawler-backend/internal/apperror"
+	"web-crawler-backend/internal/models"
+)
+
+const (
+	// minSitemapSyncInterval is the shortest allowed interval between syncs
+	minSitemapSyncInterval = 60
+	// sitemapBatchSize caps how many due sitemaps are synced per tick
+	sitemapBatchSize = 20
+	// sitemapMaxBytes caps the size of a fetched sitemap file
+	sitemapMaxBytes = 10 << 20
+	// sitemapMaxFiles caps the sitemaps of an index that are fetched
+	sitemapMaxFiles = 20
+	// sitemapMaxEntries caps the pages imported from one sitemap
+	sitemapMaxEntries = 5000
+	// sitemapTimeout bounds the fetch of one sitemap file
+	sitemapTimeout = 30 * time.Second
+)
+
+var (
+	// ErrSitemapNotFound is returned for sitemaps that don't exist or belong
+	// to another organization
+	ErrSitemapNotFound = apperror.New(http.StatusNotFound, "Sitemap not found", "The requested sitemap does not exist").WithCode("sitemap_not_found")
+	// ErrInvalidSitemap is returned for sitemaps that can't be fetched or parsed
+	ErrInvalidSitemap = errors.New("invalid sitemap")
+)
+
+// sitemapDocument is a sitemap, listing pages, or a sitemap index, listing
+// other sitemaps
+type sitemapDocument struct {
+	URLs []struct {
+		Loc string `xml:"loc"`
+	} `xml:"url"`
+	Sitemaps []struct {
+		Loc string `xml:"loc"`
+	} `xml:"sitemap"`
+}
+
+// SitemapService imports the pages of XML sitemaps as URLs and keeps them in
+// sync, reporting pages added to or removed from a sitemap in the activity
+// feed of its owner
+type SitemapService struct {
+	db        *gorm.DB
+	urls      *URLService
+	validator *URLValidator
+	quota     *QuotaService
+	client    *http.Client
+	// organizationID limits the service to the sitemaps of one organization,
+	// 0 for the ones outside any
+	organizationID uint
+	heartbeat      *Heartbeat
+}
+
+// NewSitemapService creates the service; the validator checks sitemap URLs
+// like URLService checks page URLs, and a nil validator accepts any host
+func NewSitemapService(db *gorm.DB, urls *URLService, validator *URLValidator) *SitemapService {
+	return &SitemapService{
+		db:        db,
+		urls:      urls,
+		validator: validator,
+		client:    &http.Client{Transport: crawlerTransport, Timeout: sitemapTimeout, CheckRedirect: checkCrawlerRedirect},
+		heartbeat: &Heartbeat{},
+	}
+}
+
+// WithQuota returns a copy of the service that only adds pages while the
+// owner of the sitemap is within their URL and crawl quotas
+func (s *SitemapService) WithQuota(quota *QuotaService) *SitemapService {
+	copied := *s
+	copied.quota = quota
+	return &copied
+}
+
+// WithContext returns a copy of the service whose queries and fetches run
+// with ctx
+func (s *SitemapService) WithContext(ctx context.Context) *SitemapService {
+	copied := *s
+	copied.db = s.db.WithContext(ctx)
+	copied.urls = s.urls.WithContext(ctx)
+	return &copied
+}
+
+// WithOrganization returns a copy of the service limited to the sitemaps of
+// the organization
+func (s *SitemapService) WithOrganization(organizationID uint) *SitemapService {
+	copied := *s
+	copied.organizationID = organizationID
+	return &copied
+}
+
+// Heartbeat reports when the sync worker last ticked, for readiness checks
+func (s *SitemapService) Heartbeat() *Heartbeat {
+	return s.heartbeat
+}
+
+// Start syncs due sitemaps every tick until the returned stop function is called
+func (s *SitemapService) Start(tick time.Duration) (stop func()) {
+	ticker := time.NewTicker(tick)
+	s.heartbeat.start(tick, time.Now())
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case now := <-ticker.C:
+				s.RunDue(now)
+				s.heartbeat.beat(now)
+			case <-done:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+
+	return func() { close(done) }
+}
+
+// RunDue syncs the sitemaps that are due and returns how many were synced
+func (s *SitemapService) RunDue(now time.Time) int {
+	var sitemaps []models.Sitemap
+	if err := s.db.Where("sync_interval_minutes > 0 AND next_sync_at <= ?", now).
+		Order("next_sync_at ASC").Limit(sitemapBatchSize).Find(&sitemaps).Error; err != nil {
+		log.Printf("Failed to load due sitemaps: %v", err)
+		return 0
+	}
+
+	synced := 0
+	for i := range sitemaps {
+		sitemap := &sitemaps[i]
+
+		// Claim the run first so a slow sync isn't started again on the next
+		// tick, nor by another replica
+		next := now.Add(time.Duration(sitemap.SyncIntervalMinutes) * time.Minute)
+		claimed, err := claimRun(s.db, &models.Sitemap{}, sitemap.ID, "next_sync_at", *sitemap.NextSyncAt, map[string]interface{}{
+			"next_sync_at": next,
+		})
+		if err != nil {
+			log.Printf("Failed to update sitemap %d: %v", sitemap.ID, err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		if _, err := s.sync(sitemap, now, true); err != nil {
+			log.Printf("Failed to sync sitemap %d: %v", sitemap.ID, err)
+			continue
+		}
+		synced++
+	}
+	return synced
+}
+
+// Import fetches a sitemap and adds its pages as URLs of the user
+func (s *SitemapService) Import(userID uint, req *models.ImportSitemapRequest) (*models.Sitemap, *models.SitemapSyncResult, error) {
+	if req.SyncIntervalMinutes != 0 && req.SyncIntervalMinutes < minSitemapSyncInterval {
+		return nil, nil, fmt.Errorf("%w: sync_interval_minutes must be 0 or at least %d", ErrInvalidSitemap, minSitemapSyncInterval)
+	}
+	address, err := NormalizeURL(req.URL)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Fail before anything is stored if the sitemap can't be read
+	locs, err := s.fetch(address)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	sitemap := &models.Sitemap{
+		UserID:              userID,
+		OrganizationID:      s.organizationID,
+		URL:                 address,
+		SyncIntervalMinutes: req.SyncIntervalMinutes,
+	}
+	if sitemap.SyncIntervalMinutes > 0 {
+		next := now.Add(time.Duration(sitemap.SyncIntervalMinutes) * time.Minute)
+		sitemap.NextSyncAt = &next
+	}
+	if err := s.db.Create(sitemap).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to create sitemap: %w", err)
+	}
+
+	result, err := s.apply(sitemap, locs, now, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sitemap, result, nil
+}
+
+// Sync fetches a sitemap of the organization again, adding new pages and
+// flagging removed ones
+func (s *SitemapService) Sync(id uint) (*models.SitemapSyncResult, error) {
+	sitemap, err := s.GetSitemap(id)
+	if err != nil {
+		return nil, err
+	}
+	return s.sync(sitemap, time.Now(), true)
+}
+
+// sync fetches a sitemap and applies its pages, recording fetch failures on
+// the sitemap
+func (s *SitemapService) sync(sitemap *models.Sitemap, now time.Time, notify bool) (*models.SitemapSyncResult, error) {
+	locs, err := s.fetch(sitemap.URL)
+	if err != nil {
+		s.db.Model(sitemap).Update("last_error", truncateError(err))
+		return nil, err
+	}
+	return s.apply(sitemap, locs, now, notify)
+}
+
+// apply adds the listed pages that have no entry yet, flags entries no longer
+// listed and unflags the ones listed again. With notify, changes are reported
+// in the owner's activity feed.
+func (s *SitemapService) apply(sitemap *models.Sitemap, locs []string, now time.Time, notify bool) (*models.SitemapSyncResult, error) {
+	result := &models.SitemapSyncResult{
+		SitemapID: sitemap.ID,
+		Listed:    len(locs),
+		Added:     []string{},
+		Removed:   []string{},
+		Skipped:   []string{},
+	}
+
+	var entries []models.SitemapEntry
+	if err := s.db.Where("sitemap_id = ?", sitemap.ID).Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch sitemap entries: %w", err)
+	}
+	existing := make(map[string]*models.SitemapEntry, len(entries))
+	for i := range entries {
+		existing[entries[i].Loc] = &entries[i]
+	}
+
+	listed := make(map[string]bool, len(locs))
+	for _, loc := range locs {
+		listed[loc] = true
+		if entry, ok := existing[loc]; ok {
+			if entry.RemovedAt != nil {
+				if err := s.db.Model(entry).Update("removed_at", nil).Error; err != nil {
+					return nil, fmt.Errorf("failed to update sitemap entry: %w", err)
+				}
+				result.Added = append(result.Added, loc)
+			}
+			continue
+		}
+
+		urlID, err := s.addURL(sitemap, loc)
+		if err != nil {
+			if errors.Is(err, ErrQuotaExceeded) || errors.Is(err, ErrInvalidURL) || errors.Is(err, ErrURLNotAllowed) {
+				result.Skipped = append(result.Skipped, loc)
+				continue
+			}
+			return nil, err
+		}
+		if err := s.db.Create(&models.SitemapEntry{SitemapID: sitemap.ID, URLID: urlID, Loc: loc}).Error; err != nil {
+			return nil, fmt.Errorf("failed to create sitemap entry: %w", err)
+		}
+		result.Added = append(result.Added, loc)
+	}
+
+	for loc, entry := range existing {
+		if listed[loc] || entry.RemovedAt != nil {
+			continue
+		}
+		if err := s.db.Model(entry).Update("removed_at", now).Error; err != nil {
+			return nil, fmt.Errorf("failed to update sitemap entry: %w", err)
+		}
+		result.Removed = append(result.Removed, loc)
+	}
+
+	if err := s.db.Model(sitemap).Updates(map[string]interface{}{
+		"last_synced_at": now,
+		"last_error":     "",
+	}).Error; err != nil {
+		return nil, fmt.Errorf("failed to update sitemap: %w", err)
+	}
+
+	if notify && result.Drifted() {
+		recordActivity(s.db, models.ActivityEvent{
+			UserID:  sitemap.UserID,
+			Type:    models.ActivitySitemapDrift,
+			Message: fmt.Sprintf("%d pages added to and %d removed from %s", len(result.Added), len(result.Removed), sitemap.URL),
+		}, map[string]interface{}{
+			"sitemap_id": sitemap.ID,
+			"added":      result.Added,
+			"removed":    result.Removed,
+		})
+	}
+	return result, nil
+}
+
+// addURL returns the URL of a page, adding and crawling it for the owner of
+// the sitemap if the organization doesn't have it yet
+func (s *SitemapService) addURL(sitemap *models.Sitemap, loc string) (uint, error) {
+	var url models.URL
+	err := s.db.Select("id").Where("organization_id = ? AND url = ?", sitemap.OrganizationID, loc).First(&url).Error
+	if err == nil {
+		return url.ID, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, fmt.Errorf("failed to fetch URL: %w", err)
+	}
+
+	// Adding a URL crawls it, so it counts against both quotas
+	if err := s.quota.CheckURLs(sitemap.UserID); err != nil {
+		return 0, err
+	}
+	if err := s.quota.ReserveCrawls(sitemap.UserID, 1); err != nil {
+		return 0, err
+	}
+	added, err := s.urls.WithOrganization(sitemap.OrganizationID).CreateURLForUser(loc, sitemap.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return added.ID, nil
+}
+
+// fetch returns the normalized page addresses a sitemap lists, following the
+// sitemaps of an index one level deep
+func (s *SitemapService) fetch(address string) ([]string, error) {
+	doc, err := s.fetchDocument(address)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := make([]string, 0, len(doc.URLs))
+	for _, entry := range doc.URLs {
+		raw = append(raw, entry.Loc)
+	}
+	for i, child := range doc.Sitemaps {
+		if i == sitemapMaxFiles {
+			break
+		}
+		childDoc, err := s.fetchDocument(strings.TrimSpace(child.Loc))
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range childDoc.URLs {
+			raw = append(raw, entry.Loc)
+		}
+	}
+
+	seen := make(map[string]bool, len(raw))
+	locs := make([]string, 0, len(raw))
+	for _, loc := range raw {
+		normalized, err := NormalizeURL(strings.TrimSpace(loc))
+		if err != nil || seen[normalized] {
+			continue
+		}
+		seen[normalized] = true
+		locs = append(locs, normalized)
+		if len(locs) == sitemapMaxEntries {
+			break
+		}
+	}
+	return locs, nil
+}
+
+// fetchDocument fetches and parses one sitemap file, gzipped or not
+func (s *SitemapService) fetchDocument(address string) (*sitemapDocument, error) {
+	ctx := context.Background()
+	if s.db.Statement.Context != nil {
+		ctx = s.db.Statement.Context
+	}
+	if s.validator != nil {
+		if err := s.validator.ValidateContext(ctx, address); err != nil {
+			return nil, err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, address, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSitemap, err)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to fetch %s: %v", ErrInvalidSitemap, address, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: %s answered %d", ErrInvalidSitemap, address, resp.StatusCode)
+	}
+
+	body := bufio.NewReader(io.LimitReader(resp.Body, sitemapMaxBytes))
+	var reader io.Reader = body
+	if magic, _ := body.Peek(2); len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(body)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidSitemap, err)
+		}
+		defer gz.Close()
+		reader = io.LimitReader(gz, sitemapMaxBytes)
+	}
+
+	var doc sitemapDocument
+	if err := xml.NewDecoder(reader).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("%w: %s is not an XML sitemap: %v", ErrInvalidSitemap, address, err)
+	}
+	return &doc, nil
+}
+
+// ListSitemaps returns the sitemaps of the organization, newest first
+func (s *SitemapService) ListSitemaps() ([]models.Sitemap, error) {
+	sitemaps := []models.Sitemap{}
+	if err := s.db.Where("organization_id = ?", s.organizationID).
+		Order("created_at DESC, id DESC").Find(&sitemaps).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch sitemaps: %w", err)
+	}
+	return sitemaps, nil
+}
+
+// GetSitemap returns a sitemap of the organization
+func (s *SitemapService) GetSitemap(id uint) (*models.Sitemap, error) {
+	var sitemap models.Sitemap
+	if err := s.db.Where("id = ? AND organization_id = ?", id, s.organizationID).First(&sitemap).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSitemapNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch sitemap: %w", err)
+	}
+	return &sitemap, nil
+}
+
+// GetEntries returns the pages of a sitemap, optionally only the ones a sync
+// found removed
+func (s *SitemapService) GetEntries(id uint, removedOnly bool, limit, offset int) ([]models.SitemapEntry, int64, error) {
+	if _, err := s.GetSitemap(id); err != nil {
+		return nil, 0, err
+	}
+
+	query := s.db.Model(&models.SitemapEntry{}).Where("sitemap_id = ?", id)
+	if removedOnly {
+		query = query.Where("removed_at IS NOT NULL")
+	}
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count sitemap entries: %w", err)
+	}
+	entries := []models.SitemapEntry{}
+	if err := query.Order("loc ASC").Limit(limit).Offset(offset).Find(&entries).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch sitemap entries: %w", err)
+	}
+	return entries, total, nil
+}
+
+// DeleteSitemap stops syncing a sitemap; the URLs it added are kept
+func (s *SitemapService) DeleteSitemap(id uint) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ? AND organization_id = ?", id, s.organizationID).Delete(&models.Sitemap{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete sitemap: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrSitemapNotFound
+		}
+		if err := tx.Where("sitemap_id = ?", id).Delete(&models.SitemapEntry{}).Error; err != nil {
+			return fmt.Errorf("failed to delete sitemap entries: %w", err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,233 @@
+package services
+
+import (
+	"compress/gzip"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"web-crawler-backend/internal/models"
+)
+
+// sitemapServer serves a sitemap whose pages the test can change
+type sitemapServer struct {
+	*httptest.Server
+	mu    sync.Mutex
+	pages []string
+}
+
+func newSitemapServer(t *testing.T, pages ...string) *sitemapServer {
+	server := &sitemapServer{pages: pages}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/sitemap.xml", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, server.document())
+	})
+	mux.HandleFunc("/sitemap_index.xml", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
+<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <sitemap><loc>%s/pages.xml.gz</loc></sitemap>
+</sitemapindex>`, server.URL)
+	})
+	mux.HandleFunc("/pages.xml.gz", func(w http.ResponseWriter, r *http.Request) {
+		gz := gzip.NewWriter(w)
+		fmt.Fprint(gz, server.document())
+		gz.Close()
+	})
+	server.Server = httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func (s *sitemapServer) setPages(pages ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pages = pages
+}
+
+func (s *sitemapServer) document() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n" + `<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">`)
+	for _, page := range s.pages {
+		fmt.Fprintf(&b, "<url><loc>%s%s</loc></url>", s.URL, page)
+	}
+	b.WriteString("</urlset>")
+	return b.String()
+}
+
+func setupSitemapTest(t *testing.T) (*SitemapService, *gorm.DB, *models.User) {
+	db := setupURLTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.Sitemap{}, &models.SitemapEntry{}, &models.UserQuota{}, &models.CrawlUsage{}))
+	user := &models.User{Username: "alice", Email: "alice@example.com", Password: "secret"}
+	require.NoError(t, db.Create(user).Error)
+	return NewSitemapService(db, NewURLService(db, &mockCrawlerService{}), nil), db, user
+}
+
+func TestSitemapService_ImportAndSync(t *testing.T) {
+	service, db, user := setupSitemapTest(t)
+	server := newSitemapServer(t, "/a", "/b", "/b")
+
+	sitemap, result, err := service.Import(user.ID, &models.ImportSitemapRequest{
+		URL: server.URL + "/sitemap.xml", SyncIntervalMinutes: 60,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Listed)
+	assert.Equal(t, []string{server.URL + "/a", server.URL + "/b"}, result.Added)
+	require.NotNil(t, sitemap.NextSyncAt)
+
+	var urls int64
+	require.NoError(t, db.Model(&models.URL{}).Where("user_id = ?", user.ID).Count(&urls).Error)
+	assert.Equal(t, int64(2), urls)
+
+	t.Run("pages added and removed", func(t *testing.T) {
+		server.setPages("/b", "/c")
+		result, err := service.Sync(sitemap.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{server.URL + "/c"}, result.Added)
+		assert.Equal(t, []string{server.URL + "/a"}, result.Removed)
+
+		removed, total, err := service.GetEntries(sitemap.ID, true, 20, 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		assert.Equal(t, server.URL+"/a", removed[0].Loc)
+
+		var events []models.ActivityEvent
+		require.NoError(t, db.Where("type = ?", models.ActivitySitemapDrift).Find(&events).Error)
+		require.Len(t, events, 1)
+		assert.Equal(t, user.ID, events[0].UserID)
+		assert.Contains(t, events[0].Message, "1 pages added to and 1 removed")
+	})
+
+	t.Run("removed pages listed again", func(t *testing.T) {
+		server.setPages("/a", "/b", "/c")
+		result, err := service.Sync(sitemap.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{server.URL + "/a"}, result.Added)
+		assert.Empty(t, result.Removed)
+
+		_, total, err := service.GetEntries(sitemap.ID, true, 20, 0)
+		require.NoError(t, err)
+		assert.Zero(t, total)
+	})
+
+	t.Run("unchanged sitemaps don't notify", func(t *testing.T) {
+		result, err := service.Sync(sitemap.ID)
+		require.NoError(t, err)
+		assert.False(t, result.Drifted())
+
+		var events int64
+		require.NoError(t, db.Model(&models.ActivityEvent{}).Where("type = ?", models.ActivitySitemapDrift).Count(&events).Error)
+		assert.Equal(t, int64(2), events)
+	})
+
+	t.Run("sitemaps of other organizations", func(t *testing.T) {
+		_, err := service.WithOrganization(7).Sync(sitemap.ID)
+		assert.ErrorIs(t, err, ErrSitemapNotFound)
+	})
+}
+
+func TestSitemapService_RunDue(t *testing.T) {
+	service, db, user := setupSitemapTest(t)
+	server := newSitemapServer(t, "/a")
+
+	sitemap, _, err := service.Import(user.ID, &models.ImportSitemapRequest{
+		URL: server.URL + "/sitemap.xml", SyncIntervalMinutes: 60,
+	})
+	require.NoError(t, err)
+	_, _, err = service.Import(user.ID, &models.ImportSitemapRequest{URL: server.URL + "/sitemap_index.xml"})
+	require.NoError(t, err)
+
+	now := time.Now()
+	assert.Zero(t, service.RunDue(now), "not due yet, and imports without an interval never are")
+
+	server.setPages("/a", "/b")
+	assert.Equal(t, 1, service.RunDue(now.Add(61*time.Minute)))
+
+	var synced models.Sitemap
+	require.NoError(t, db.First(&synced, sitemap.ID).Error)
+	assert.True(t, synced.NextSyncAt.After(now.Add(2*time.Hour)))
+	entries, _, err := service.GetEntries(sitemap.ID, false, 20, 0)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+
+	t.Run("failed syncs are recorded", func(t *testing.T) {
+		server.Close()
+		assert.Zero(t, service.RunDue(now.Add(3*time.Hour)))
+		require.NoError(t, db.First(&synced, sitemap.ID).Error)
+		assert.Contains(t, synced.LastError, "invalid sitemap")
+	})
+}
+
+func TestSitemapService_RunDueAcrossReplicas(t *testing.T) {
+	service, db, user := setupSitemapTest(t)
+	server := newSitemapServer(t, "/a")
+	_, _, err := service.Import(user.ID, &models.ImportSitemapRequest{
+		URL: server.URL + "/sitemap.xml", SyncIntervalMinutes: 60,
+	})
+	require.NoError(t, err)
+
+	// The other replica runs right after this one loaded the due sitemaps
+	now := time.Now().Add(61 * time.Minute)
+	other := NewSitemapService(db, NewURLService(db, &mockCrawlerService{}), nil)
+	otherSynced, ran := 0, false
+	require.NoError(t, db.Callback().Query().After("gorm:query").Register("test:replica", func(tx *gorm.DB) {
+		if tx.Statement.Table == "sitemaps" && !ran {
+			ran = true
+			otherSynced = other.RunDue(now)
+		}
+	}))
+
+	assert.Equal(t, 1, service.RunDue(now)+otherSynced)
+}
+
+func TestSitemapService_Index(t *testing.T) {
+	service, _, user := setupSitemapTest(t)
+	server := newSitemapServer(t, "/a", "/b")
+
+	_, result, err := service.Import(user.ID, &models.ImportSitemapRequest{URL: server.URL + "/sitemap_index.xml"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{server.URL + "/a", server.URL + "/b"}, result.Added)
+}
+
+func TestSitemapService_Quota(t *testing.T) {
+	service, db, user := setupSitemapTest(t)
+	service = service.WithQuota(NewQuotaService(db, models.QuotaLimits{MaxURLs: 1}))
+	server := newSitemapServer(t, "/a", "/b")
+
+	_, result, err := service.Import(user.ID, &models.ImportSitemapRequest{URL: server.URL + "/sitemap.xml"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{server.URL + "/a"}, result.Added)
+	assert.Equal(t, []string{server.URL + "/b"}, result.Skipped)
+}
+
+func TestSitemapService_ImportInvalid(t *testing.T) {
+	service, db, user := setupSitemapTest(t)
+	server := newSitemapServer(t)
+
+	tests := []struct {
+		name string
+		req  models.ImportSitemapRequest
+	}{
+		{"missing sitemap", models.ImportSitemapRequest{URL: server.URL + "/missing.xml"}},
+		{"short interval", models.ImportSitemapRequest{URL: server.URL + "/sitemap.xml", SyncIntervalMinutes: 30}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := service.Import(user.ID, &tt.req)
+			assert.ErrorIs(t, err, ErrInvalidSitemap)
+		})
+	}
+
+	var count int64
+	require.NoError(t, db.Model(&models.Sitemap{}).Count(&count).Error)
+	assert.Zero(t, count)
+}
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"web-crawler-backend/internal/models"
+)
+
+// recentFailuresListed is how many failed crawls the system status lists
+const recentFailuresListed = 20
+
+var (
+	// ErrCrawlNotRunning is returned when cancelling a crawl that already finished
+	ErrCrawlNotRunning = errors.New("crawl is not running")
+	// ErrCrawlRunning is returned when requeueing a crawl whose URL is being crawled
+	ErrCrawlRunning = errors.New("URL is being crawled")
+)
+
+// SystemStatusService gives admins the operational view of crawling that
+// otherwise needs database and Redis access: the queue, the crawls running
+// and the crawls that failed recently
+type SystemStatusService struct {
+	db             *gorm.DB
+	crawlerService CrawlerServiceInterface
+	// queue is inspected when crawls run through it; nil when they run in process
+	queue *CrawlQueue
+}
+
+// NewSystemStatusService creates the service; a nil queue leaves it out of the status
+func NewSystemStatusService(db *gorm.DB, crawlerService CrawlerServiceInterface, queue *CrawlQueue) *SystemStatusService {
+	return &SystemStatusService{db: db, crawlerService: crawlerService, queue: queue}
+}
+
+// Status reports the queue, the running crawls with their durations and the
+// newest failed crawls
+func (s *SystemStatusService) Status(ctx context.Context, now time.Time) (*models.SystemStatus, error) {
+	status := &models.SystemStatus{
+		CheckedAt:      now,
+		RunningCrawls:  []models.RunningCrawl{},
+		RecentFailures: []models.FailedCrawl{},
+	}
+
+	if s.queue != nil {
+		stats, err := s.queue.Stats(ctx)
+		if err != nil {
+			return nil, err
+		}
+		status.Queue = stats
+	}
+
+	db := s.db.WithContext(ctx)
+	var running []struct {
+		CrawlID   uint
+		URLID     uint
+		URL       string
+		StartedAt *time.Time
+	}
+	if err := db.Table("crawls").
+		Select("crawls.id AS crawl_id, crawls.url_id, urls.url, crawls.started_at").
+		Joins("JOIN urls ON urls.id = crawls.url_id").
+		Where("crawls.status = ?", "running").
+		Order("crawls.started_at, crawls.id").
+		Scan(&running).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch running crawls: %w", err)
+	}
+	for _, crawl := range running {
+		entry := models.RunningCrawl{CrawlID: crawl.CrawlID, URLID: crawl.URLID, URL: crawl.URL}
+		if crawl.StartedAt != nil {
+			entry.StartedAt = *crawl.StartedAt
+			entry.DurationMs = now.Sub(*crawl.StartedAt).Milliseconds()
+		}
+		status.RunningCrawls = append(status.RunningCrawls, entry)
+	}
+
+	if err := db.Table("crawls").
+		Select("crawls.id AS crawl_id, crawls.url_id, urls.url, crawls.error_message, crawls.completed_at").
+		Joins("JOIN urls ON urls.id = crawls.url_id").
+		Where("crawls.status = ?", "error").
+		Order("crawls.completed_at DESC, crawls.id DESC").
+		Limit(recentFailuresListed).
+		Scan(&status.RecentFailures).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch failed crawls: %w", err)
+	}
+	return status, nil
+}
+
+// CancelCrawl marks a running crawl as failed, together with its URL, like
+// the watchdog does for overdue crawls. The crawl itself isn't interrupted;
+// if it finishes after all, its real result overwrites the cancellation.
+func (s *SystemStatusService) CancelCrawl(crawlID uint, now time.Time) (*models.Crawl, error) {
+	var crawl models.Crawl
+	if err := s.db.First(&crawl, crawlID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCrawlNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch crawl: %w", err)
+	}
+	if crawl.Status != "running" {
+		return nil, ErrCrawlNotRunning
+	}
+
+	crawl.Status = "error"
+	crawl.ErrorMessage = "Cancelled by an administrator"
+	crawl.CompletedAt = &now
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&crawl).Updates(map[string]interface{}{
+			"status":        crawl.Status,
+			"error_message": crawl.ErrorMessage,
+			"completed_at":  now,
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.URL{}).Where("id = ? AND status = ?", crawl.URLID, "running").Update("status", "error").Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel crawl %d: %w", crawlID, err)
+	}
+	return &crawl, nil
+}
+
+// RequeueCrawl starts a new crawl of the URL of a crawl, e.g. one that
+// failed. It returns the ID of the URL.
+func (s *SystemStatusService) RequeueCrawl(crawlID uint) (uint, error) {
+	var crawl models.Crawl
+	if err := s.db.First(&crawl, crawlID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, ErrCrawlNotFound
+		}
+		return 0, fmt.Errorf("failed to fetch crawl: %w", err)
+	}
+
+	var urlRecord models.URL
+	if err := s.db.First(&urlRecord, crawl.URLID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, ErrCrawlNotFound
+		}
+		return 0, fmt.Errorf("failed to fetch URL: %w", err)
+	}
+	if urlRecord.Status == "running" {
+		return 0, ErrCrawlRunning
+	}
+
+	if err := s.db.Model(&urlRecord).Update("status", "pending").Error; err != nil {
+		return 0, fmt.Errorf("failed to requeue URL %d: %w", urlRecord.ID, err)
+	}
+	go s.crawlerService.StartCrawl(urlRecord.ID)
+	return urlRecord.ID, nil
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"web-crawler-backend/internal/models"
+)
+
+func TestSystemStatusService(t *testing.T) {
+	db := setupURLTestDB(t)
+	crawler := &mockCrawlerService{}
+	queue := setupCrawlQueueTest(t, CrawlQueueOptions{})
+	service := NewSystemStatusService(db, crawler, queue)
+	now := time.Now()
+	longAgo, recently := now.Add(-time.Hour), now.Add(-time.Minute)
+
+	stuck := &models.URL{URL: "https://stuck.example.com", Status: "running"}
+	busy := &models.URL{URL: "https://busy.example.com", Status: "running"}
+	broken := &models.URL{URL: "https://broken.example.com", Status: "error"}
+	for _, url := range []*models.URL{stuck, busy, broken} {
+		require.NoError(t, db.Create(url).Error)
+	}
+	stuckCrawl := &models.Crawl{URLID: stuck.ID, Status: "running", StartedAt: &longAgo}
+	busyCrawl := &models.Crawl{URLID: busy.ID, Status: "running", StartedAt: &recently}
+	failedCrawl := &models.Crawl{URLID: broken.ID, Status: "error", ErrorMessage: "connection refused", CompletedAt: &recently}
+	for _, crawl := range []*models.Crawl{busyCrawl, stuckCrawl, failedCrawl} {
+		require.NoError(t, db.Create(crawl).Error)
+	}
+	_, err := queue.Enqueue(context.Background(), busy.ID, models.CrawlPriorityLow)
+	require.NoError(t, err)
+
+	t.Run("status lists the queue, running and failed crawls", func(t *testing.T) {
+		status, err := service.Status(context.Background(), now)
+		require.NoError(t, err)
+		require.NotNil(t, status.Queue)
+		assert.Equal(t, int64(1), status.Queue.Pending)
+
+		require.Len(t, status.RunningCrawls, 2)
+		assert.Equal(t, stuckCrawl.ID, status.RunningCrawls[0].CrawlID)
+		assert.Equal(t, stuck.URL, status.RunningCrawls[0].URL)
+		assert.Equal(t, time.Hour.Milliseconds(), status.RunningCrawls[0].DurationMs)
+		assert.Equal(t, busyCrawl.ID, status.RunningCrawls[1].CrawlID)
+
+		require.Len(t, status.RecentFailures, 1)
+		assert.Equal(t, failedCrawl.ID, status.RecentFailures[0].CrawlID)
+		assert.Equal(t, broken.URL, status.RecentFailures[0].URL)
+		assert.Equal(t, "connection refused", status.RecentFailures[0].ErrorMessage)
+	})
+
+	t.Run("the queue is left out when crawls run in process", func(t *testing.T) {
+		status, err := NewSystemStatusService(db, crawler, nil).Status(context.Background(), now)
+		require.NoError(t, err)
+		assert.Nil(t, status.Queue)
+	})
+
+	t.Run("a running crawl is cancelled", func(t *testing.T) {
+		crawl, err := service.CancelCrawl(stuckCrawl.ID, now)
+		require.NoError(t, err)
+		assert.Equal(t, "error", crawl.Status)
+
+		var stored models.Crawl
+		require.NoError(t, db.First(&stored, stuckCrawl.ID).Error)
+		assert.Equal(t, "Cancelled by an administrator", stored.ErrorMessage)
+		var url models.URL
+		require.NoError(t, db.First(&url, stuck.ID).Error)
+		assert.Equal(t, "error", url.Status)
+
+		_, err = service.CancelCrawl(stuckCrawl.ID, now)
+		assert.ErrorIs(t, err, ErrCrawlNotRunning)
+		_, err = service.CancelCrawl(9999, now)
+		assert.ErrorIs(t, err, ErrCrawlNotFound)
+	})
+
+	t.Run("a failed crawl is requeued", func(t *testing.T) {
+		urlID, err := service.RequeueCrawl(failedCrawl.ID)
+		require.NoError(t, err)
+		assert.Equal(t, broken.ID, urlID)
+		time.Sleep(10 * time.Millisecond) // Allow goroutine to execute
+		assert.True(t, crawler.startCrawlCalled)
+		assert.Equal(t, broken.ID, crawler.lastURLID)
+
+		var url models.URL
+		require.NoError(t, db.First(&url, broken.ID).Error)
+		assert.Equal(t, "pending", url.Status)
+	})
+
+	t.Run("a URL being crawled isn't requeued", func(t *testing.T) {
+		_, err := service.RequeueCrawl(busyCrawl.ID)
+		assert.ErrorIs(t, err, ErrCrawlRunning)
+	})
+}
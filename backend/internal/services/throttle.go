@@ -0,0 +1,180 @@
+package services
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// throttleWindow is the number of recent responses used to compute the 5xx rate
+	throttleWindow = 10
+	// throttleMinSamples is the number of responses needed before adapting
+	throttleMinSamples = 3
+	// throttleLatencyFactor is how much slower than the baseline a host may get before backing off
+	throttleLatencyFactor = 2.0
+	// throttleMinLatency avoids backing off on hosts that are merely fast and slightly noisy
+	throttleMinLatency = 500 * time.Millisecond
+	// throttleErrorRate is the share of 5xx responses in the window that triggers a back off
+	throttleErrorRate = 0.3
+	// throttleMinQPS is the floor for the per-host request rate
+	throttleMinQPS = 0.5
+)
+
+// hostState tracks the current limits and recent behaviour of a single host
+type hostState struct {
+	concurrency int
+	qps         float64
+	inFlight    int
+	nextSlot    time.Time
+
+	baseline   time.Duration
+	avgLatency time.Duration
+	samples    int
+	window     []bool // true for 5xx responses
+}
+
+// HostThrottle limits concurrency and request rate per host and backs off
+// when a host's response times rise or it starts returning 5xx errors
+type HostThrottle struct {
+	mu             sync.Mutex
+	cond           *sync.Cond
+	maxConcurrency int
+	maxQPS         float64
+	hosts          map[string]*hostState
+	log            []string
+}
+
+// NewHostThrottle creates a throttle with the given initial per-host limits
+func NewHostThrottle(maxConcurrency int, maxQPS float64) *HostThrottle {
+	if maxConcurrency < 1 {
+		maxConcurrency = 1
+	}
+	if maxQPS <= 0 {
+		maxQPS = throttleMinQPS
+	}
+
+	t := &HostThrottle{
+		maxConcurrency: maxConcurrency,
+		maxQPS:         maxQPS,
+		hosts:          make(map[string]*hostState),
+	}
+	t.cond = sync.NewCond(&t.mu)
+	return t
+}
+
+// host returns the state for a host, creating it on first use. Caller must hold t.mu.
+func (t *HostThrottle) host(name string) *hostState {
+	h, ok := t.hosts[name]
+	if !ok {
+		h = &hostState{
+			concurrency: t.maxConcurrency,
+			qps:         t.maxQPS,
+		}
+		t.hosts[name] = h
+	}
+	return h
+}
+
+// Acquire blocks until a request to the host is allowed by its current limits
+func (t *HostThrottle) Acquire(hostName string) {
+	t.mu.Lock()
+	h := t.host(hostName)
+	for h.inFlight >= h.concurrency {
+		t.cond.Wait()
+	}
+	h.inFlight++
+
+	now := time.Now()
+	start := now
+	if h.nextSlot.After(now) {
+		start = h.nextSlot
+	}
+	h.nextSlot = start.Add(time.Duration(float64(time.Second) / h.qps))
+	t.mu.Unlock()
+
+	if wait := time.Until(start); wait > 0 {
+		time.Sleep(wait)
+	}
+}
+
+// Release records the outcome of a request and frees its concurrency slot.
+// statusCode should be 0 when the request failed without a response.
+func (t *HostThrottle) Release(hostName string, latency time.Duration, statusCode int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	h := t.host(hostName)
+	h.inFlight--
+	t.observe(hostName, h, latency, statusCode)
+	t.cond.Broadcast()
+}
+
+// observe updates latency and error statistics and backs off if needed. Caller must hold t.mu.
+func (t *HostThrottle) observe(hostName string, h *hostState, latency time.Duration, statusCode int) {
+	h.samples++
+	if h.samples <= throttleMinSamples {
+		// Build the baseline from the first few responses
+		h.baseline += (latency - h.baseline) / time.Duration(h.samples)
+		h.avgLatency = h.baseline
+	} else {
+		h.avgLatency = time.Duration(0.3*float64(latency) + 0.7*float64(h.avgLatency))
+	}
+
+	h.window = append(h.window, statusCode >= 500)
+	if len(h.window) > throttleWindow {
+		h.window = h.window[1:]
+	}
+
+	if h.samples < throttleMinSamples {
+		return
+	}
+
+	failures := 0
+	for _, is5xx := range h.window {
+		if is5xx {
+			failures++
+		}
+	}
+	errorRate := float64(failures) / float64(len(h.window))
+
+	var reason string
+	switch {
+	case len(h.window) >= throttleMinSamples && errorRate >= throttleErrorRate:
+		reason = fmt.Sprintf("5xx rate %.0f%%", errorRate*100)
+	case h.avgLatency > throttleMinLatency && float64(h.avgLatency) > throttleLatencyFactor*float64(h.baseline):
+		reason = fmt.Sprintf("avg response time %s (baseline %s)", h.avgLatency.Round(time.Millisecond), h.baseline.Round(time.Millisecond))
+	default:
+		return
+	}
+
+	if h.concurrency == 1 && h.qps <= throttleMinQPS {
+		return
+	}
+
+	h.concurrency = max(1, h.concurrency/2)
+	h.qps = max(throttleMinQPS, h.qps/2)
+
+	// Start a fresh window so the next decision is based on the new rate
+	h.window = h.window[:0]
+	h.baseline = h.avgLatency
+
+	t.log = append(t.log, fmt.Sprintf("throttle: %s reduced to concurrency=%d qps=%.2f (%s)", hostName, h.concurrency, h.qps, reason))
+}
+
+// Rate returns the currently applied concurrency and QPS for a host
+func (t *HostThrottle) Rate(hostName string) (int, float64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	h := t.host(hostName)
+	return h.concurrency, h.qps
+}
+
+// Log returns the recorded rate adjustments
+func (t *HostThrottle) Log() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return append([]string(nil), t.log...)
+}
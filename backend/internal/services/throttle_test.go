@@ -0,0 +1,88 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordResponse feeds a response into the throttle statistics without waiting for a slot
+func recordResponse(throttle *HostThrottle, host string, latency time.Duration, statusCode int) {
+	throttle.mu.Lock()
+	defer throttle.mu.Unlock()
+	throttle.observe(host, throttle.host(host), latency, statusCode)
+}
+
+func TestHostThrottle(t *testing.T) {
+	t.Run("keeps initial rate for healthy host", func(t *testing.T) {
+		throttle := NewHostThrottle(4, 100)
+
+		for i := 0; i < 10; i++ {
+			throttle.Acquire("example.com")
+			throttle.Release("example.com", 50*time.Millisecond, 200)
+		}
+
+		concurrency, qps := throttle.Rate("example.com")
+		assert.Equal(t, 4, concurrency)
+		assert.Equal(t, 100.0, qps)
+		assert.Empty(t, throttle.Log())
+	})
+
+	t.Run("backs off on 5xx responses", func(t *testing.T) {
+		throttle := NewHostThrottle(4, 100)
+
+		for i := 0; i < 3; i++ {
+			recordResponse(throttle, "example.com", 10*time.Millisecond, 503)
+		}
+
+		concurrency, qps := throttle.Rate("example.com")
+		assert.Equal(t, 2, concurrency)
+		assert.Equal(t, 50.0, qps)
+
+		log := throttle.Log()
+		if assert.Len(t, log, 1) {
+			assert.Contains(t, log[0], "example.com")
+			assert.Contains(t, log[0], "5xx rate")
+		}
+	})
+
+	t.Run("backs off when response times rise", func(t *testing.T) {
+		throttle := NewHostThrottle(4, 100)
+
+		for i := 0; i < 3; i++ {
+			recordResponse(throttle, "slow.com", 200*time.Millisecond, 200)
+		}
+		for i := 0; i < 5; i++ {
+			recordResponse(throttle, "slow.com", 3*time.Second, 200)
+		}
+
+		concurrency, _ := throttle.Rate("slow.com")
+		assert.Less(t, concurrency, 4)
+		assert.Contains(t, throttle.Log()[0], "avg response time")
+	})
+
+	t.Run("limits are tracked per host", func(t *testing.T) {
+		throttle := NewHostThrottle(4, 100)
+
+		for i := 0; i < 3; i++ {
+			recordResponse(throttle, "broken.com", 10*time.Millisecond, 500)
+		}
+
+		concurrency, _ := throttle.Rate("healthy.com")
+		assert.Equal(t, 4, concurrency)
+	})
+
+	t.Run("never drops below minimum rate", func(t *testing.T) {
+		throttle := NewHostThrottle(1, throttleMinQPS)
+
+		for i := 0; i < 10; i++ {
+			recordResponse(throttle, "example.com", 10*time.Millisecond, 500)
+		}
+
+		concurrency, qps := throttle.Rate("example.com")
+		assert.Equal(t, 1, concurrency)
+		assert.Equal(t, throttleMinQPS, qps)
+		assert.Empty(t, throttle.Log())
+	})
+}
@@ -0,0 +1,156 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"web-crawler-backend/internal/models"
+)
+
+// TokenGuardOptions configures the detection of clients guessing tokens
+type TokenGuardOptions struct {
+	// MaxFailures invalid tokens from one IP within Window raise an
+	// incident; zero disables detection
+	MaxFailures int
+	Window      time.Duration
+	// BlockFor rejects the token requests of an IP for this long after it
+	// raised an incident; zero only logs the incident
+	BlockFor time.Duration
+}
+
+// tokenFailures counts the invalid tokens of one IP in the current window
+type tokenFailures struct {
+	count        int
+	reset        time.Time
+	blockedUntil time.Time
+}
+
+// TokenGuard watches the token endpoints for IPs sending many invalid
+// tokens, logs them in the security audit log and optionally blocks them for
+// a while. Counts are kept in memory, per replica. A nil guard does nothing.
+type TokenGuard struct {
+	db      *gorm.DB
+	options TokenGuardOptions
+
+	mu        sync.Mutex
+	clients   map[string]*tokenFailures
+	nextSweep time.Time
+	now       func() time.Time
+}
+
+func NewTokenGuard(db *gorm.DB, options TokenGuardOptions) *TokenGuard {
+	if options.Window <= 0 {
+		options.Window = 10 * time.Minute
+	}
+	return &TokenGuard{
+		db:      db,
+		options: options,
+		clients: make(map[string]*tokenFailures),
+		now:     time.Now,
+	}
+}
+
+// Blocked reports whether the IP is blocked, and until when
+func (g *TokenGuard) Blocked(ip string) (time.Time, bool) {
+	if g == nil {
+		return time.Time{}, false
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	client, ok := g.clients[ip]
+	if !ok || !g.now().Before(client.blockedUntil) {
+		return time.Time{}, false
+	}
+	return client.blockedUntil, true
+}
+
+// RecordFailure counts an invalid token sent by the IP to path. The failure
+// that reaches the limit logs an incident, blocks the IP if blocking is on,
+// and starts the count over.
+func (g *TokenGuard) RecordFailure(ctx context.Context, ip, path string) {
+	if g == nil || g.options.MaxFailures <= 0 {
+		return
+	}
+	incident, ok := g.countFailure(ip, path)
+	if !ok {
+		return
+	}
+
+	log.Printf("Auth incident: %d invalid tokens from %s within %s", incident.Failures, ip, g.options.Window)
+	if err := g.db.WithContext(ctx).Create(incident).Error; err != nil {
+		log.Printf("Failed to record auth incident of %s: %v", ip, err)
+	}
+}
+
+// countFailure counts a failure of the IP, returning the incident it raised
+func (g *TokenGuard) countFailure(ip, path string) (*models.AuthIncident, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := g.now()
+	g.sweep(now)
+
+	client, exists := g.clients[ip]
+	if !exists {
+		client = &tokenFailures{}
+		g.clients[ip] = client
+	}
+	if !now.Before(client.reset) {
+		client.count = 0
+		client.reset = now.Add(g.options.Window)
+	}
+
+	client.count++
+	if client.count < g.options.MaxFailures {
+		return nil, false
+	}
+
+	incident := &models.AuthIncident{
+		Type:      models.AuthIncidentInvalidTokens,
+		IPAddress: ip,
+		Path:      path,
+		Failures:  client.count,
+		CreatedAt: now,
+	}
+	if g.options.BlockFor > 0 {
+		client.blockedUntil = now.Add(g.options.BlockFor)
+		blockedUntil := client.blockedUntil
+		incident.BlockedUntil = &blockedUntil
+	}
+	client.count = 0
+	return incident, true
+}
+
+// sweep drops IPs with neither recent failures nor a block, once per window
+func (g *TokenGuard) sweep(now time.Time) {
+	if now.Before(g.nextSweep) {
+		return
+	}
+	for ip, client := range g.clients {
+		if !now.Before(client.reset) && !now.Before(client.blockedUntil) {
+			delete(g.clients, ip)
+		}
+	}
+	g.nextSweep = now.Add(g.options.Window)
+}
+
+// Incidents returns the security audit log, newest first
+func (g *TokenGuard) Incidents(ctx context.Context, limit, offset int) ([]models.AuthIncident, int64, error) {
+	var incidents []models.AuthIncident
+	var total int64
+
+	query := g.db.WithContext(ctx).Model(&models.AuthIncident{})
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count auth incidents: %w", err)
+	}
+	if err := query.Order("created_at DESC, id DESC").Limit(limit).Offset(offset).Find(&incidents).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch auth incidents: %w", err)
+	}
+	return incidents, total, nil
+}
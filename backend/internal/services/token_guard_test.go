@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"web-crawler-backend/internal/models"
+)
+
+func newTestTokenGuard(t *testing.T, options TokenGuardOptions, now *time.Time) *TokenGuard {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.AuthIncident{}))
+
+	guard := NewTokenGuard(db, options)
+	guard.now = func() time.Time { return *now }
+	return guard
+}
+
+func TestTokenGuard(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1_700_000_000, 0).UTC()
+	guard := newTestTokenGuard(t, TokenGuardOptions{MaxFailures: 3, Window: time.Minute, BlockFor: 5 * time.Minute}, &now)
+
+	guard.RecordFailure(ctx, "10.0.0.1", "/api/v1/auth/refresh")
+	guard.RecordFailure(ctx, "10.0.0.1", "/api/v1/auth/refresh")
+	_, blocked := guard.Blocked("10.0.0.1")
+	assert.False(t, blocked, "below the limit")
+
+	// Failures of the previous window don't count
+	now = now.Add(time.Minute)
+	guard.RecordFailure(ctx, "10.0.0.1", "/api/v1/auth/refresh")
+	guard.RecordFailure(ctx, "10.0.0.1", "/api/v1/auth/refresh")
+	guard.RecordFailure(ctx, "10.0.0.2", "/api/v1/auth/validate")
+	_, blocked = guard.Blocked("10.0.0.1")
+	assert.False(t, blocked)
+
+	guard.RecordFailure(ctx, "10.0.0.1", "/api/v1/auth/validate")
+	until, blocked := guard.Blocked("10.0.0.1")
+	assert.True(t, blocked)
+	assert.Equal(t, now.Add(5*time.Minute), until)
+	_, blocked = guard.Blocked("10.0.0.2")
+	assert.False(t, blocked, "other IPs aren't blocked")
+
+	incidents, total, err := guard.Incidents(ctx, 20, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, incidents, 1)
+	assert.Equal(t, models.AuthIncidentInvalidTokens, incidents[0].Type)
+	assert.Equal(t, "10.0.0.1", incidents[0].IPAddress)
+	assert.Equal(t, "/api/v1/auth/validate", incidents[0].Path)
+	assert.Equal(t, 3, incidents[0].Failures)
+	require.NotNil(t, incidents[0].BlockedUntil)
+	assert.True(t, until.Equal(*incidents[0].BlockedUntil))
+
+	// The block lifts by itself
+	now = now.Add(5 * time.Minute)
+	_, blocked = guard.Blocked("10.0.0.1")
+	assert.False(t, blocked)
+}
+
+func TestTokenGuard_LogOnly(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1_700_000_000, 0).UTC()
+	guard := newTestTokenGuard(t, TokenGuardOptions{MaxFailures: 2, Window: time.Minute}, &now)
+
+	for range 4 {
+		guard.RecordFailure(ctx, "10.0.0.1", "/api/v1/auth/refresh")
+	}
+	_, blocked := guard.Blocked("10.0.0.1")
+	assert.False(t, blocked, "without a block duration incidents are only logged")
+
+	incidents, total, err := guard.Incidents(ctx, 20, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total, "the count starts over after an incident")
+	assert.Nil(t, incidents[0].BlockedUntil)
+
+	var disabled *TokenGuard
+	disabled.RecordFailure(ctx, "10.0.0.1", "/api/v1/auth/refresh")
+	_, blocked = disabled.Blocked("10.0.0.1")
+	assert.False(t, blocked, "a nil guard does nothing")
+}
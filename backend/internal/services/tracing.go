@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"web-crawler-backend/internal/models"
+	"web-crawler-backend/internal/telemetry"
+)
+
+// crawlerTransport traces the crawler's outbound requests and propagates the
+// trace context in their headers. It connects only to the destinations
+// allowed by RestrictCrawlerDestinations.
+var crawlerTransport http.RoundTripper = otelhttp.NewTransport(newCrawlerTransport())
+
+// crawlerClient fetches pages
+var crawlerClient = &http.Client{Transport: crawlerTransport, CheckRedirect: checkCrawlerRedirect}
+
+// linkCheckClient requests links, images and icons to check them, giving up
+// on slow hosts
+var linkCheckClient = &http.Client{Transport: crawlerTransport, Timeout: 10 * time.Second, CheckRedirect: checkCrawlerRedirect}
+
+// newCrawlerTransport returns the default transport dialing through the
+// destination check
+func newCrawlerTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialCrawlerDestination
+	return transport
+}
+
+// contextCrawlStarter is implemented by crawlers that link their crawl traces
+// to the request that started them
+type contextCrawlStarter interface {
+	StartCrawlContext(ctx context.Context, urlID uint)
+}
+
+// priorityCrawlStarter is implemented by crawlers that queue crawls by priority
+type priorityCrawlStarter interface {
+	StartCrawlWithPriority(ctx context.Context, urlID uint, priority models.CrawlPriority)
+}
+
+// StartCrawlContext starts a crawl a user is waiting for, with high priority
+func (s *CrawlerService) StartCrawlContext(ctx context.Context, urlID uint) {
+	s.StartCrawlWithPriority(ctx, urlID, models.CrawlPriorityHigh)
+}
+
+// StartCrawlWithPriority runs a crawl in its own trace, linked to the span in
+// ctx, or adds it to the crawl queue with the priority if the service has one.
+// Crawls outlive the request that started them, so ctx is only used for the
+// link and never to cancel the crawl.
+func (s *CrawlerService) StartCrawlWithPriority(ctx context.Context, urlID uint, priority models.CrawlPriority) {
+	if s.queue != nil {
+		_, err := s.queue.Enqueue(context.WithoutCancel(ctx), urlID, priority)
+		if err == nil {
+			return
+		}
+		log.Printf("Failed to queue crawl of URL %d, running it here: %v", urlID, err)
+	}
+	s.RunCrawl(ctx, urlID)
+}
+
+// RunCrawl runs a crawl in its own trace, linked to the span in ctx. It
+// implements CrawlRunner for the workers of the crawl queue.
+func (s *CrawlerService) RunCrawl(ctx context.Context, urlID uint) error {
+	spanCtx, span := telemetry.Tracer().Start(context.Background(), "crawl",
+		trace.WithNewRoot(),
+		trace.WithLinks(trace.LinkFromContext(ctx)),
+		trace.WithAttributes(attribute.Int("crawl.url_id", int(urlID))),
+	)
+	defer span.End()
+
+	traced := *s
+	traced.db = s.db.WithContext(spanCtx)
+	return traced.startCrawl(urlID)
+}
+
+// traceContext returns the context of the crawl span, for outbound requests
+func (s *CrawlerService) traceContext() context.Context {
+	if ctx := s.db.Statement.Context; ctx != nil {
+		return ctx
+	}
+	return context.Background()
+}
+
+// WithContext returns a copy of the service whose queries are traced as part of
+// ctx and cancelled at its deadline
+func (s *URLService) WithContext(ctx context.Context) *URLService {
+	copied := *s
+	copied.db = s.db.WithContext(ctx)
+	return &copied
+}
+
+// context returns the context the service was bound to with WithContext
+func (s *URLService) context() context.Context {
+	if ctx := s.db.Statement.Context; ctx != nil {
+		return ctx
+	}
+	return context.Background()
+}
+
+// startCrawl starts a crawl, linking its trace to the current request if the crawler supports it
+func (s *URLService) startCrawl(urlID uint) {
+	if starter, ok := s.crawlerService.(contextCrawlStarter); ok {
+		starter.StartCrawlContext(s.db.Statement.Context, urlID)
+		return
+	}
+	s.crawlerService.StartCrawl(urlID)
+}
@@ -0,0 +1,206 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+
+	"web-crawler-backend/internal/models"
+)
+
+// trashPurgeBatchSize caps how many URLs are purged per transaction
+const trashPurgeBatchSize = 100
+
+// TrashService manages soft-deleted URLs: listing and restoring them, and
+// purging them together with their crawl history
+type TrashService struct {
+	db        *gorm.DB
+	retention time.Duration
+	// organizationID limits the trash to one organization; nil shows all of it
+	organizationID *uint
+}
+
+// NewTrashService creates the service. Deleted URLs are purged automatically
+// once they have been in the trash for longer than retention; zero keeps them.
+func NewTrashService(db *gorm.DB, retention time.Duration) *TrashService {
+	return &TrashService{db: db, retention: retention}
+}
+
+// WithContext returns a copy of the service whose queries are traced as part of
+// ctx and cancelled at its deadline
+func (s *TrashService) WithContext(ctx context.Context) *TrashService {
+	copied := *s
+	copied.db = s.db.WithContext(ctx)
+	return &copied
+}
+
+// WithOrganization returns a copy of the service limited to the deleted URLs
+// of an organization; 0 limits it to the URLs outside any organization
+func (s *TrashService) WithOrganization(organizationID uint) *TrashService {
+	copied := *s
+	copied.organizationID = &organizationID
+	return &copied
+}
+
+// trashed selects the deleted URLs of the organization of the service
+func (s *TrashService) trashed() *gorm.DB {
+	query := s.db.Unscoped().Model(&models.URL{}).Where("deleted_at IS NOT NULL")
+	if s.organizationID != nil {
+		query = query.Where("organization_id = ?", *s.organizationID)
+	}
+	return query
+}
+
+// ListTrash returns the deleted URLs, most recently deleted first
+func (s *TrashService) ListTrash(limit, offset int) ([]*models.TrashedURL, int64, error) {
+	query := s.trashed()
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count deleted URLs: %w", err)
+	}
+
+	var urls []models.URL
+	if err := query.Order("deleted_at DESC, id DESC").Limit(limit).Offset(offset).Find(&urls).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch deleted URLs: %w", err)
+	}
+
+	trashed := make([]*models.TrashedURL, len(urls))
+	for i, url := range urls {
+		trashed[i] = &models.TrashedURL{URL: url, DeletedAt: url.DeletedAt.Time}
+		if s.retention > 0 {
+			purgeAt := url.DeletedAt.Time.Add(s.retention)
+			trashed[i].PurgeAt = &purgeAt
+		}
+	}
+	return trashed, total, nil
+}
+
+// RestoreURL takes a URL out of the trash, with its crawl history intact
+func (s *TrashService) RestoreURL(id uint) (*models.URL, error) {
+	url, err := s.findTrashed(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Unscoped().Model(url).Update("deleted_at", nil).Error; err != nil {
+		return nil, fmt.Errorf("failed to restore URL: %w", err)
+	}
+	url.DeletedAt = gorm.DeletedAt{}
+	return url, nil
+}
+
+// PurgeURL deletes a URL in the trash for good, together with its crawls and
+// everything they found. URLs must be deleted before they can be purged, and
+// a crawl that was running when the URL was deleted must finish first.
+func (s *TrashService) PurgeURL(id uint) error {
+	url, err := s.findTrashed(id)
+	if err != nil {
+		return err
+	}
+	if url.Status == "running" {
+		return ErrCrawlRunning
+	}
+	return s.purge([]uint{id})
+}
+
+// PurgeExpired purges the URLs deleted longer ago than the retention period
+// and returns how many were purged. URLs still being crawled wait for a later
+// run. It does nothing without a retention period.
+func (s *TrashService) PurgeExpired(now time.Time) (int, error) {
+	if s.retention <= 0 {
+		return 0, nil
+	}
+
+	purged := 0
+	for {
+		var ids []uint
+		if err := s.db.Unscoped().Model(&models.URL{}).
+			Where("deleted_at IS NOT NULL AND deleted_at < ? AND status <> ?", now.Add(-s.retention), "running").
+			Limit(trashPurgeBatchSize).Pluck("id", &ids).Error; err != nil {
+			return purged, fmt.Errorf("failed to find expired URLs: %w", err)
+		}
+		if len(ids) == 0 {
+			return purged, nil
+		}
+
+		if err := s.purge(ids); err != nil {
+			return purged, err
+		}
+		purged += len(ids)
+	}
+}
+
+// Start purges expired trash every tick until the returned stop function is called
+func (s *TrashService) Start(tick time.Duration) (stop func()) {
+	ticker := time.NewTicker(tick)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case now := <-ticker.C:
+				if purged, err := s.PurgeExpired(now); err != nil {
+					log.Printf("Trash purge failed: %v", err)
+				} else if purged > 0 {
+					log.Printf("Purged %d URLs from the trash", purged)
+				}
+			case <-done:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+
+	return func() { close(done) }
+}
+
+func (s *TrashService) findTrashed(id uint) (*models.URL, error) {
+	var url models.URL
+	if err := s.trashed().First(&url, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrURLNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch URL: %w", err)
+	}
+	return &url, nil
+}
+
+// purge hard deletes URLs and every row that belongs to them. The activity
+// feed keeps its entries, detached from the purged URLs.
+func (s *TrashService) purge(urlIDs []uint) error {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		crawlIDs := tx.Model(&models.Crawl{}).Select("id").Where("url_id IN ?", urlIDs)
+		for _, child := range crawlChildren {
+			if err := tx.Where("crawl_id IN (?)", crawlIDs).Delete(child).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Where("url_id IN ?", urlIDs).Delete(&models.Crawl{}).Error; err != nil {
+			return err
+		}
+
+		for _, child := range []interface{}{&models.CrawlSchedule{}, &models.FindingAnnotation{}, &models.ExtractionRule{},
+			&models.Monitor{}, &models.MonitorCheck{}, &models.SitemapEntry{}, &models.BrokenLinkThreshold{}, &models.ExternalIssue{}} {
+			if err := tx.Where("url_id IN ?", urlIDs).Delete(child).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Where("baseline_url_id IN ? OR candidate_url_id IN ?", urlIDs, urlIDs).Delete(&models.EnvironmentPair{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.ActivityEvent{}).Where("url_id IN ?", urlIDs).
+			Updates(map[string]interface{}{"url_id": nil, "crawl_id": nil}).Error; err != nil {
+			return err
+		}
+
+		return tx.Unscoped().Where("id IN ?", urlIDs).Delete(&models.URL{}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to purge URLs: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"web-crawler-backend/internal/models"
+)
+
+func setupTrashTest(t *testing.T) (*TrashService, *gorm.DB) {
+	db := setupURLTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.CrawlSchedule{}, &models.ExtractionRule{}, &models.Monitor{}, &models.MonitorCheck{}, &models.EnvironmentPair{}, &models.SitemapEntry{}, &models.BrokenLinkThreshold{}, &models.ExternalIssue{}))
+	return NewTrashService(db, 24*time.Hour), db
+}
+
+// createCrawledURL adds a URL with a crawl, a link, a schedule and an activity entry
+func createCrawledURL(t *testing.T, db *gorm.DB, address string) *models.URL {
+	url := &models.URL{URL: address, Status: "completed"}
+	require.NoError(t, db.Create(url).Error)
+	crawl := &models.Crawl{URLID: url.ID, Status: "completed"}
+	require.NoError(t, db.Create(crawl).Error)
+	require.NoError(t, db.Create(&models.Link{URLID: url.ID, CrawlID: crawl.ID, LinkURL: address + "/a"}).Error)
+	require.NoError(t, db.Create(&models.CrawlSchedule{URLID: url.ID, IntervalMinutes: 60}).Error)
+	require.NoError(t, db.Create(&models.ActivityEvent{UserID: 1, Type: models.ActivityURLAdded, URLID: &url.ID, CrawlID: &crawl.ID}).Error)
+	return url
+}
+
+func countRows(t *testing.T, db *gorm.DB, model interface{}, urlID uint) int64 {
+	var count int64
+	require.NoError(t, db.Model(model).Where("url_id = ?", urlID).Count(&count).Error)
+	return count
+}
+
+func TestTrashService_ListAndRestore(t *testing.T) {
+	service, db := setupTrashTest(t)
+	kept := createCrawledURL(t, db, "https://kept.com")
+	deleted := createCrawledURL(t, db, "https://deleted.com")
+	require.NoError(t, db.Delete(deleted).Error)
+
+	trash, total, err := service.ListTrash(20, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, trash, 1)
+	assert.Equal(t, deleted.ID, trash[0].ID)
+	require.NotNil(t, trash[0].PurgeAt)
+	assert.Equal(t, trash[0].DeletedAt.Add(24*time.Hour), *trash[0].PurgeAt)
+
+	_, err = service.RestoreURL(kept.ID)
+	assert.ErrorIs(t, err, ErrURLNotFound)
+
+	restored, err := service.RestoreURL(deleted.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "https://deleted.com", restored.URL)
+	require.NoError(t, db.First(&models.URL{}, deleted.ID).Error)
+	assert.Equal(t, int64(1), countRows(t, db, &models.Crawl{}, deleted.ID))
+}
+
+func TestTrashService_PurgeURL(t *testing.T) {
+	service, db := setupTrashTest(t)
+	url := createCrawledURL(t, db, "https://example.com")
+
+	assert.ErrorIs(t, service.PurgeURL(url.ID), ErrURLNotFound, "only deleted URLs can be purged")
+
+	require.NoError(t, db.Delete(url).Error)
+	require.NoError(t, service.PurgeURL(url.ID))
+
+	var remaining int64
+	require.NoError(t, db.Unscoped().Model(&models.URL{}).Count(&remaining).Error)
+	assert.Zero(t, remaining)
+	for _, model := range []interface{}{&models.Crawl{}, &models.Link{}, &models.CrawlSchedule{}, &models.ActivityEvent{}} {
+		assert.Zero(t, countRows(t, db, model, url.ID))
+	}
+
+	var activity models.ActivityEvent
+	require.NoError(t, db.First(&activity).Error)
+	assert.Nil(t, activity.URLID)
+	assert.Nil(t, activity.CrawlID)
+}
+
+func TestTrashService_PurgeExpired(t *testing.T) {
+	service, db := setupTrashTest(t)
+	now := time.Now()
+
+	old := createCrawledURL(t, db, "https://old.com")
+	recent := createCrawledURL(t, db, "https://recent.com")
+	require.NoError(t, db.Unscoped().Model(old).Update("deleted_at", now.Add(-25*time.Hour)).Error)
+	require.NoError(t, db.Unscoped().Model(recent).Update("deleted_at", now.Add(-time.Hour)).Error)
+
+	purged, err := service.PurgeExpired(now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, purged)
+
+	var ids []uint
+	require.NoError(t, db.Unscoped().Model(&models.URL{}).Pluck("id", &ids).Error)
+	assert.Equal(t, []uint{recent.ID}, ids)
+
+	purged, err = NewTrashService(db, 0).PurgeExpired(now.Add(48 * time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, purged, "trash is kept without a retention period")
+}
+
+func TestTrashService_PurgeWaitsForRunningCrawls(t *testing.T) {
+	service, db := setupTrashTest(t)
+	now := time.Now()
+
+	url := createCrawledURL(t, db, "https://example.com")
+	require.NoError(t, db.Model(url).Update("status", "running").Error)
+	require.NoError(t, db.Unscoped().Model(url).Update("deleted_at", now.Add(-25*time.Hour)).Error)
+
+	assert.ErrorIs(t, service.PurgeURL(url.ID), ErrCrawlRunning)
+	purged, err := service.PurgeExpired(now)
+	require.NoError(t, err)
+	assert.Zero(t, purged)
+
+	// Once the crawl finishes the URL goes
+	require.NoError(t, db.Unscoped().Model(url).Update("status", "completed").Error)
+	purged, err = service.PurgeExpired(now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, purged)
+}
@@ -0,0 +1,293 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrInvalidFilter is returned for filter expressions that don't parse
+var ErrInvalidFilter = errors.New("invalid filter")
+
+// maxFilterConditions bounds the conditions of one filter expression
+const maxFilterConditions = 20
+
+// filterOperators are tried longest first, so ">=" isn't read as ">"
+var filterOperators = []string{">=", "<=", "!=", ">", "<", "=", ":"}
+
+// filterKind is the type of value a filter field holds
+type filterKind int
+
+const (
+	filterText filterKind = iota
+	filterEnum
+	filterBool
+	filterNumber
+	filterTime
+)
+
+// filterField describes a field that URL lists can be filtered on
+type filterField struct {
+	kind filterKind
+	// column is the SQL expression the field compares
+	column string
+	// dialectColumns overrides column for databases that need their own SQL.
+	// Fields without a column only work on the databases listed here.
+	dialectColumns map[string]string
+	// values lists the allowed values of enum fields
+	values map[string]bool
+}
+
+// latestCrawlColumn selects a column of the latest crawl of a URL; URLs that
+// were never crawled have NULL, which matches no comparison
+func latestCrawlColumn(column string) string {
+	return fmt.Sprintf("(SELECT %s FROM crawls c WHERE c.url_id = urls.id ORDER BY c.created_at DESC, c.id DESC LIMIT 1)", column)
+}
+
+// crawlDurationField compares how long the latest crawl took in milliseconds;
+// every database has its own date arithmetic
+var crawlDurationField = filterField{
+	kind: filterNumber,
+	dialectColumns: map[string]string{
+		"mysql":    latestCrawlColumn("TIMESTAMPDIFF(MICROSECOND, c.started_at, c.completed_at) / 1000"),
+		"postgres": latestCrawlColumn("EXTRACT(EPOCH FROM (c.completed_at - c.started_at)) * 1000"),
+		"sqlite":   latestCrawlColumn("(julianday(c.completed_at) - julianday(c.started_at)) * 86400000"),
+	},
+}
+
+var filterFields = map[string]filterField{
+	"status": {kind: filterEnum, column: "urls.status", values: map[string]bool{
+		"pending": true, "running": true, "completed": true, "skipped": true, "error": true,
+	}},
+	"url":             {kind: filterText, column: "urls.url"},
+	"title":           {kind: filterText, column: "urls.title"},
+	"html_version":    {kind: filterText, column: "urls.html_version"},
+	"has_login_form":  {kind: filterBool, column: "urls.has_login_form"},
+	"needs_attention": {kind: filterBool, column: "urls.needs_attention"},
+	"broken_links":    {kind: filterNumber, column: latestCrawlColumn("c.broken_links")},
+	"internal_links":  {kind: filterNumber, column: latestCrawlColumn("c.internal_links")},
+	"external_links":  {kind: filterNumber, column: latestCrawlColumn("c.external_links")},
+	"pages_crawled":   {kind: filterNumber, column: latestCrawlColumn("c.pages_crawled")},
+	"duration_ms":     crawlDurationField,
+	"word_count":      {kind: filterNumber, column: latestCrawlColumn("c.word_count")},
+	"readability":     {kind: filterNumber, column: latestCrawlColumn("c.readability_score")},
+	"created_at":      {kind: filterTime, column: "urls.created_at"},
+	"updated_at":      {kind: filterTime, column: "urls.updated_at"},
+}
+
+// filterAliases are shorthands for time comparisons, e.g. created_after:2024-01-01
+var filterAliases = map[string]struct{ field, op string }{
+	"created_after":  {"created_at", ">="},
+	"created_before": {"created_at", "<"},
+	"updated_after":  {"updated_at", ">="},
+	"updated_before": {"updated_at", "<"},
+}
+
+// FilterCondition is one condition of a URL filter, e.g. broken_links>10
+type FilterCondition struct {
+	Field string
+	Op    string
+	Value string
+
+	field  filterField
+	values []interface{}
+}
+
+// URLFilter is a parsed filter expression; all of its conditions must match
+type URLFilter []FilterCondition
+
+// ParseURLFilter parses a comma separated list of conditions such as
+// "status:error,broken_links>10,created_after:2024-01-01".
+//
+// A condition is a field, an operator and a value. Text fields (url, title,
+// html_version) support ":" for contains, "=" and "!="; status and
+// has_login_form support ":", "=" and "!="; link and page counts and the
+// duration_ms, word_count and readability of the latest crawl and the
+// created_at and updated_at times also support ">", ">=", "<" and "<=".
+// Values of status, url, title and html_version may list alternatives
+// separated by "|". Times are dates (2024-01-01) or RFC 3339 timestamps,
+// and created_after, created_before, updated_after and updated_before are
+// shorthands for ">=" and "<" comparisons.
+func ParseURLFilter(expr string) (URLFilter, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return nil, nil
+	}
+
+	terms := strings.Split(expr, ",")
+	if len(terms) > maxFilterConditions {
+		return nil, fmt.Errorf("%w: at most %d conditions are allowed", ErrInvalidFilter, maxFilterConditions)
+	}
+
+	filter := make(URLFilter, 0, len(terms))
+	for _, term := range terms {
+		condition, err := parseFilterCondition(strings.TrimSpace(term))
+		if err != nil {
+			return nil, err
+		}
+		filter = append(filter, condition)
+	}
+	return filter, nil
+}
+
+// parseFilterCondition parses and validates a single condition
+func parseFilterCondition(term string) (FilterCondition, error) {
+	end := strings.IndexAny(term, "<>=!:")
+	if end <= 0 {
+		return FilterCondition{}, fmt.Errorf("%w: %q is not a condition like field:value", ErrInvalidFilter, term)
+	}
+	name := strings.ToLower(strings.TrimSpace(term[:end]))
+	rest := term[end:]
+
+	condition := FilterCondition{Field: name}
+	for _, op := range filterOperators {
+		if strings.HasPrefix(rest, op) {
+			condition.Op = op
+			condition.Value = strings.TrimSpace(rest[len(op):])
+			break
+		}
+	}
+	if condition.Op == "" {
+		return FilterCondition{}, fmt.Errorf("%w: unknown operator in %q", ErrInvalidFilter, term)
+	}
+	if condition.Value == "" {
+		return FilterCondition{}, fmt.Errorf("%w: %q has no value", ErrInvalidFilter, term)
+	}
+
+	if alias, ok := filterAliases[name]; ok {
+		if condition.Op != ":" && condition.Op != "=" {
+			return FilterCondition{}, fmt.Errorf("%w: %s only supports ':'", ErrInvalidFilter, name)
+		}
+		condition.Field, condition.Op = alias.field, alias.op
+	}
+
+	field, ok := filterFields[condition.Field]
+	if !ok {
+		return FilterCondition{}, fmt.Errorf("%w: unknown field %q", ErrInvalidFilter, name)
+	}
+	condition.field = field
+
+	ordered := condition.Op == ">" || condition.Op == ">=" || condition.Op == "<" || condition.Op == "<="
+	if ordered && field.kind != filterNumber && field.kind != filterTime {
+		return FilterCondition{}, fmt.Errorf("%w: %s can't be compared with %s", ErrInvalidFilter, condition.Field, condition.Op)
+	}
+
+	for _, raw := range strings.Split(condition.Value, "|") {
+		value, err := parseFilterValue(field, strings.TrimSpace(raw))
+		if err != nil {
+			return FilterCondition{}, fmt.Errorf("%w: %s: %v", ErrInvalidFilter, condition.Field, err)
+		}
+		condition.values = append(condition.values, value)
+	}
+	if len(condition.values) > 1 && field.kind != filterText && field.kind != filterEnum {
+		return FilterCondition{}, fmt.Errorf("%w: %s takes a single value", ErrInvalidFilter, condition.Field)
+	}
+	if len(condition.values) > 1 && ordered {
+		return FilterCondition{}, fmt.Errorf("%w: %s takes a single value with %s", ErrInvalidFilter, condition.Field, condition.Op)
+	}
+	return condition, nil
+}
+
+// parseFilterValue converts a value to the type of the field
+func parseFilterValue(field filterField, value string) (interface{}, error) {
+	if value == "" {
+		return nil, errors.New("empty value")
+	}
+	switch field.kind {
+	case filterEnum:
+		value = strings.ToLower(value)
+		if !field.values[value] {
+			return nil, fmt.Errorf("unknown value %q", value)
+		}
+		return value, nil
+	case filterBool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not true or false", value)
+		}
+		return b, nil
+	case filterNumber:
+		n, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a number", value)
+		}
+		return int64(n), nil
+	case filterTime:
+		if t, err := time.Parse("2006-01-02", value); err == nil {
+			return t, nil
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a date like 2024-01-01 or an RFC 3339 time", value)
+		}
+		return t, nil
+	default:
+		return value, nil
+	}
+}
+
+// String returns the filter in a canonical form, for cache keys
+func (f URLFilter) String() string {
+	conditions := make([]string, len(f))
+	for i, condition := range f {
+		values := make([]string, len(condition.values))
+		for j, value := range condition.values {
+			if t, ok := value.(time.Time); ok {
+				values[j] = t.UTC().Format(time.RFC3339)
+			} else {
+				values[j] = fmt.Sprint(value)
+			}
+		}
+		conditions[i] = condition.Field + condition.Op + strings.Join(values, "|")
+	}
+	return strings.Join(conditions, ",")
+}
+
+// apply adds the conditions of the filter to query
+func (f URLFilter) apply(query *gorm.DB) *gorm.DB {
+	for _, condition := range f {
+		query = condition.apply(query)
+	}
+	return query
+}
+
+// apply adds the condition to query; alternatives of a value are ORed. Fields
+// the database has no SQL for fail the query with ErrInvalidFilter.
+func (c FilterCondition) apply(query *gorm.DB) *gorm.DB {
+	column, ok := c.field.dialectColumns[query.Dialector.Name()]
+	if !ok {
+		column = c.field.column
+	}
+	if column == "" {
+		query.AddError(fmt.Errorf("%w: %s is not supported on %s", ErrInvalidFilter, c.Field, query.Dialector.Name()))
+		return query
+	}
+	if c.field.kind == filterText && c.Op == ":" {
+		clauses := make([]string, len(c.values))
+		args := make([]interface{}, len(c.values))
+		for i, value := range c.values {
+			clauses[i] = "LOWER(" + column + ") LIKE ?"
+			args[i] = "%" + strings.ToLower(value.(string)) + "%"
+		}
+		return query.Where("("+strings.Join(clauses, " OR ")+")", args...)
+	}
+
+	switch c.Op {
+	case ":", "=":
+		if len(c.values) > 1 {
+			return query.Where(column+" IN ?", c.values)
+		}
+		return query.Where(column+" = ?", c.values[0])
+	case "!=":
+		if len(c.values) > 1 {
+			return query.Where(column+" NOT IN ?", c.values)
+		}
+		return query.Where(column+" <> ?", c.values[0])
+	default:
+		return query.Where(column+" "+c.Op+" ?", c.values[0])
+	}
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"web-crawler-backend/internal/models"
+)
+
+func TestParseURLFilter(t *testing.T) {
+	filter, err := ParseURLFilter(" status:error|skipped, broken_links>10,created_after:2024-01-01,title:Shop ")
+	require.NoError(t, err)
+	assert.Equal(t, "status:error|skipped,broken_links>10,created_at>=2024-01-01T00:00:00Z,title:Shop", filter.String())
+
+	filter, err = ParseURLFilter("duration_ms>=500,duration_ms<2000")
+	require.NoError(t, err)
+	assert.Equal(t, "duration_ms>=500,duration_ms<2000", filter.String())
+
+	filter, err = ParseURLFilter("")
+	require.NoError(t, err)
+	assert.Empty(t, filter)
+
+	for _, expr := range []string{
+		"status",
+		"status:",
+		"colour:red",
+		"status:broken",
+		"status>error",
+		"broken_links>many",
+		"broken_links>1|2",
+		"has_login_form:maybe",
+		"created_after>2024-01-01",
+		"created_at<yesterday",
+		"title~shop",
+		"status:error,,broken_links>1",
+	} {
+		_, err := ParseURLFilter(expr)
+		assert.ErrorIs(t, err, ErrInvalidFilter, expr)
+	}
+}
+
+// renamedDialector is a database the filters have no dialect specific SQL for
+type renamedDialector struct{ gorm.Dialector }
+
+func (renamedDialector) Name() string { return "unknown" }
+
+func TestURLService_WithFilter(t *testing.T) {
+	db := setupURLTestDB(t)
+	service := NewURLService(db, &mockCrawlerService{})
+
+	old := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	recent := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	urls := []*models.URL{
+		{URL: "https://shop.example.com", Title: "Shop", Status: "error", CreatedAt: recent},
+		{URL: "https://blog.example.com", Title: "Blog", Status: "completed", CreatedAt: recent, HasLoginForm: true},
+		{URL: "https://old.example.com", Title: "Old shop", Status: "error", CreatedAt: old},
+		{URL: "https://new.example.com", Title: "New", Status: "pending", CreatedAt: recent},
+	}
+	for _, url := range urls {
+		require.NoError(t, db.Create(url).Error)
+	}
+	// Only the latest crawl counts
+	require.NoError(t, db.Create(&models.Crawl{URLID: urls[0].ID, Status: "completed", BrokenLinks: 2, CreatedAt: recent}).Error)
+	require.NoError(t, db.Create(&models.Crawl{URLID: urls[0].ID, Status: "error", BrokenLinks: 20, CreatedAt: recent.Add(time.Hour)}).Error)
+	require.NoError(t, db.Create(&models.Crawl{URLID: urls[1].ID, Status: "completed", BrokenLinks: 15, WordCount: 120, CreatedAt: recent}).Error)
+	require.NoError(t, db.Create(&models.Crawl{URLID: urls[2].ID, Status: "completed", BrokenLinks: 30, CreatedAt: old}).Error)
+	require.NoError(t, db.Create(&models.Crawl{URLID: urls[2].ID, Status: "completed", BrokenLinks: 5, CreatedAt: old.Add(time.Hour)}).Error)
+	started, completed := recent, recent.Add(1500*time.Millisecond)
+	require.NoError(t, db.Create(&models.Crawl{URLID: urls[3].ID, Status: "completed", StartedAt: &started, CompletedAt: &completed, CreatedAt: recent}).Error)
+
+	list := func(expr string) []uint {
+		filter, err := ParseURLFilter(expr)
+		require.NoError(t, err)
+		found, total, err := service.WithFilter(filter).GetURLs(10, 0, "", "", "url", "asc")
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(found)), total)
+		ids := []uint{}
+		for _, url := range found {
+			ids = append(ids, url.ID)
+		}
+		return ids
+	}
+
+	assert.Equal(t, []uint{urls[2].ID, urls[0].ID}, list("status:error"))
+	assert.Equal(t, []uint{urls[1].ID, urls[0].ID}, list("broken_links>10"))
+	assert.Equal(t, []uint{urls[0].ID}, list("status:error,broken_links>10,created_after:2024-01-01"))
+	assert.Equal(t, []uint{urls[2].ID, urls[0].ID}, list("title:shop"))
+	assert.Equal(t, []uint{urls[1].ID, urls[3].ID}, list("status!=error"))
+	assert.Equal(t, []uint{urls[1].ID, urls[3].ID}, list("status:completed|pending"))
+	assert.Equal(t, []uint{urls[1].ID}, list("has_login_form:true"))
+	assert.Equal(t, []uint{urls[2].ID}, list("created_before:2024-01-01,broken_links<=5"))
+	assert.Equal(t, []uint{urls[1].ID}, list("word_count>=100"))
+	assert.Equal(t, []uint{urls[3].ID}, list("duration_ms>1000"))
+
+	t.Run("fields without SQL for the database", func(t *testing.T) {
+		filter, err := ParseURLFilter("duration_ms>1000")
+		require.NoError(t, err)
+		query := db.Session(&gorm.Session{NewDB: true})
+		query.Config = &gorm.Config{Dialector: renamedDialector{db.Dialector}}
+		assert.ErrorIs(t, filter.apply(query.Model(&models.URL{})).Error, ErrInvalidFilter)
+	})
+
+	// Filtered lists are versioned on their own
+	filter, err := ParseURLFilter("status:pending")
+	require.NoError(t, err)
+	all, err := service.ListVersion("", "")
+	require.NoError(t, err)
+	pending, err := service.WithFilter(filter).ListVersion("", "")
+	require.NoError(t, err)
+	assert.NotEqual(t, all, pending)
+}
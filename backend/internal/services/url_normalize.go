@@ -0,0 +1,65 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// ErrInvalidURL is returned when a submitted URL cannot be crawled
+var ErrInvalidURL = errors.New("invalid URL")
+
+// defaultPorts are stripped from normalized URLs
+var defaultPorts = map[string]string{
+	"http":  "80",
+	"https": "443",
+}
+
+// NormalizeURL validates a submitted URL and returns its canonical form, so
+// that spellings of the same address map to one record:
+//   - the scheme and host are lowercased
+//   - the default port of the scheme is removed
+//   - the fragment is removed
+//   - a bare "/" path is dropped; deeper paths keep their trailing slash,
+//     since servers may treat /blog and /blog/ as different pages
+//
+// Only http and https URLs with a host are accepted.
+func NormalizeURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", fmt.Errorf("%w: URL is empty", ErrInvalidURL)
+	}
+
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidURL, err)
+	}
+
+	parsed.Scheme = strings.ToLower(parsed.Scheme)
+	if _, ok := defaultPorts[parsed.Scheme]; !ok {
+		return "", fmt.Errorf("%w: scheme must be http or https", ErrInvalidURL)
+	}
+
+	host := strings.ToLower(parsed.Hostname())
+	if host == "" {
+		return "", fmt.Errorf("%w: URL has no host", ErrInvalidURL)
+	}
+	if port := parsed.Port(); port != "" && port != defaultPorts[parsed.Scheme] {
+		host = net.JoinHostPort(host, port)
+	} else if strings.Contains(host, ":") {
+		// IPv6 literals keep their brackets without a port
+		host = "[" + host + "]"
+	}
+	parsed.Host = host
+
+	parsed.Fragment = ""
+	parsed.RawFragment = ""
+	if parsed.Path == "/" {
+		parsed.Path = ""
+		parsed.RawPath = ""
+	}
+
+	return parsed.String(), nil
+}
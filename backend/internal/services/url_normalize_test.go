@@ -0,0 +1,40 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeURL(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{"https://example.com", "https://example.com"},
+		{"https://example.com/", "https://example.com"},
+		{"HTTPS://EXAMPLE.COM", "https://example.com"},
+		{"  https://example.com/#top ", "https://example.com"},
+		{"http://example.com:80/about", "http://example.com/about"},
+		{"https://example.com:443/", "https://example.com"},
+		{"https://example.com:8443/", "https://example.com:8443"},
+		{"http://example.com:443/", "http://example.com:443"},
+		{"https://example.com/Blog/?page=2#comments", "https://example.com/Blog/?page=2"},
+		{"https://example.com/?q=1", "https://example.com?q=1"},
+		{"http://[::1]:80/", "http://[::1]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := NormalizeURL(tt.raw)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	for _, raw := range []string{"", "example.com", "ftp://example.com", "javascript:alert(1)", "mailto:hi@example.com", "https://", "http://%zz"} {
+		t.Run("rejects "+raw, func(t *testing.T) {
+			_, err := NormalizeURL(raw)
+			assert.ErrorIs(t, err, ErrInvalidURL)
+		})
+	}
+}
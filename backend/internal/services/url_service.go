@@ -1,11 +1,26 @@
 package services
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"slices"
 	"strings"
 
 	"gorm.io/gorm"
+	"web-crawler-backend/internal/apperror"
 	"web-crawler-backend/internal/models"
+	"web-crawler-backend/internal/repository"
+)
+
+var (
+	// ErrInvalidCrawlSettings is returned when crawl depth or page limits are out of range
+	ErrInvalidCrawlSettings = errors.New("invalid crawl settings")
+	// ErrInvalidSort is returned for URL list sort orders that can't be parsed
+	ErrInvalidSort = errors.New("invalid sort")
+	// ErrURLNotFound is returned for URLs that don't exist or are out of scope
+	ErrURLNotFound = apperror.New(http.StatusNotFound, "URL not found", "The requested URL does not exist").WithCode("url_not_found")
 )
 
 // CrawlerServiceInterface defines the interface for crawler service
@@ -18,35 +33,76 @@ type CrawlerServiceInterface interface {
 type URLService struct {
 	db             *gorm.DB
 	crawlerService CrawlerServiceInterface
+	validator      *URLValidator
+	cache          *CacheService
+	// organizationID limits the service to the URLs of one organization, 0
+	// being the URLs outside any; nil leaves it unscoped for internal callers
+	organizationID *uint
+	// filter limits URL lists further
+	filter URLFilter
+	// store replaces db for the queries moved to repositories; nil uses db
+	store repository.Store
 }
 
 func NewURLService(db *gorm.DB, crawlerService CrawlerServiceInterface) *URLService {
+	return NewURLServiceWithValidator(db, crawlerService, nil)
+}
+
+// NewURLServiceWithValidator creates a URL service that checks new URLs with
+// the validator before crawling them; a nil validator accepts any host
+func NewURLServiceWithValidator(db *gorm.DB, crawlerService CrawlerServiceInterface, validator *URLValidator) *URLService {
 	return &URLService{
 		db:             db,
 		crawlerService: crawlerService,
+		validator:      validator,
 	}
 }
 
 // CreateURL creates a new URL record and starts crawling
 func (s *URLService) CreateURL(url string) (*models.URL, error) {
+	return s.CreateURLForUser(url, 0)
+}
+
+// CreateURLForUser creates a new URL record on behalf of a user and starts crawling.
+// A zero userID leaves the URL without an owner. The URL is normalized first, so
+// different spellings of the same address share one record.
+func (s *URLService) CreateURLForUser(url string, userID uint) (*models.URL, error) {
+	url, err := NormalizeURL(url)
+	if err != nil {
+		return nil, err
+	}
+	if s.validator != nil {
+		if err := s.validator.ValidateContext(s.context(), url); err != nil {
+			return nil, err
+		}
+	}
+
 	// Try to create new URL first
 	urlRecord := &models.URL{
 		URL:    url,
 		Status: "pending",
 	}
+	if s.organizationID != nil {
+		urlRecord.OrganizationID = *s.organizationID
+	}
+	if userID != 0 {
+		urlRecord.UserID = &userID
+	}
 
-	err := s.db.Create(urlRecord).Error
+	err = s.db.Create(urlRecord).Error
 	if err == nil {
+		s.recordURLAdded(urlRecord, userID)
+
 		// Successfully created new URL, start crawling
-		go s.crawlerService.StartCrawl(urlRecord.ID)
+		go s.startCrawl(urlRecord.ID)
 		return urlRecord, nil
 	}
 
 	// Check if error is due to duplicate key constraint
-	if strings.Contains(err.Error(), "Duplicate entry") || strings.Contains(err.Error(), "UNIQUE constraint failed") {
+	if isDuplicateKeyError(err) {
 		// URL already exists (might be soft-deleted), try to fetch it including deleted records
 		var existingURL models.URL
-		if fetchErr := s.db.Unscoped().Where("url = ?", url).First(&existingURL).Error; fetchErr != nil {
+		if fetchErr := s.db.Unscoped().Where("organization_id = ? AND url = ?", urlRecord.OrganizationID, url).First(&existingURL).Error; fetchErr != nil {
 			return nil, fmt.Errorf("failed to fetch existing URL after duplicate error: %w", fetchErr)
 		}
 
@@ -55,14 +111,20 @@ func (s *URLService) CreateURL(url string) (*models.URL, error) {
 			existingURL.DeletedAt = gorm.DeletedAt{}
 		}
 
+		// Record the owner if nobody claimed the URL before
+		if existingURL.UserID == nil && userID != 0 {
+			existingURL.UserID = &userID
+		}
+
 		// Update status and restart crawling
 		existingURL.Status = "pending"
 		if updateErr := s.db.Unscoped().Save(&existingURL).Error; updateErr != nil {
 			return nil, fmt.Errorf("failed to update existing URL status: %w", updateErr)
 		}
+		s.recordURLAdded(&existingURL, userID)
 		
 		// Restart crawling process
-		go s.crawlerService.StartCrawl(existingURL.ID)
+		go s.startCrawl(existingURL.ID)
 		
 		return &existingURL, nil
 	}
@@ -71,74 +133,600 @@ func (s *URLService) CreateURL(url string) (*models.URL, error) {
 	return nil, fmt.Errorf("failed to create URL record: %w", err)
 }
 
-// GetURLs retrieves URLs with pagination, filtering, and sorting
-func (s *URLService) GetURLs(limit, offset int, search, status, sortBy, sortOrder string) ([]*models.URL, int64, error) {
-	var urls []*models.URL
-	var total int64
+// recordURLAdded adds the URL to the activity feed of the user who added it
+func (s *URLService) recordURLAdded(url *models.URL, userID uint) {
+	if userID == 0 {
+		return
+	}
 
-	// Build query
-	query := s.db.Model(&models.URL{})
+	recordActivity(s.db, models.ActivityEvent{
+		UserID:  userID,
+		ActorID: &userID,
+		Type:    models.ActivityURLAdded,
+		URLID:   &url.ID,
+		Message: fmt.Sprintf("Added %s", url.URL),
+	}, nil)
+}
 
-	// Apply search filter
-	if search != "" {
-		searchPattern := "%" + strings.ToLower(search) + "%"
-		query = query.Where("LOWER(url) LIKE ? OR LOWER(title) LIKE ?", searchPattern, searchPattern)
+// WithCache returns a copy of the service that caches URL lists; a nil
+// cache disables caching
+func (s *URLService) WithCache(cache *CacheService) *URLService {
+	copied := *s
+	copied.cache = cache
+	return &copied
+}
+
+// WithOrganization returns a copy of the service limited to the URLs of an
+// organization; 0 limits it to the URLs outside any organization
+func (s *URLService) WithOrganization(organizationID uint) *URLService {
+	copied := *s
+	copied.organizationID = &organizationID
+	return &copied
+}
+
+// WithFilter returns a copy of the service whose URL lists only contain URLs
+// matching filter
+func (s *URLService) WithFilter(filter URLFilter) *URLService {
+	copied := *s
+	copied.filter = filter
+	return &copied
+}
+
+// inOrganization limits query to the organization of the service, if any
+func (s *URLService) inOrganization(query *gorm.DB) *gorm.DB {
+	if s.organizationID == nil {
+		return query
 	}
+	return query.Where("urls.organization_id = ?", *s.organizationID)
+}
 
-	// Apply status filter
-	if status != "" {
-		query = query.Where("status = ?", status)
+// cachedURLList is the cache entry of a URL list page
+type cachedURLList struct {
+	URLs  []*models.URLListItem
+	Total int64
+}
+
+// GetURLs retrieves URLs with pagination, filtering, and sorting
+func (s *URLService) GetURLs(limit, offset int, search, status, sortBy, sortOrder string) ([]*models.URLListItem, int64, error) {
+	ctx := s.db.Statement.Context
+	var cached cachedURLList
+	organization := "all"
+	if s.organizationID != nil {
+		organization = fmt.Sprint(*s.organizationID)
+	}
+	cacheKey, ok := s.cache.get(ctx, fmt.Sprintf("urls:%s:%d:%d:%q:%q:%q:%q:%q", organization, limit, offset, search, status, sortBy, sortOrder, s.filter), &cached)
+	if ok {
+		return cached.URLs, cached.Total, nil
+	}
+
+	urls, total, err := s.getURLs(limit, offset, search, status, sortBy, sortOrder)
+	if err != nil {
+		return nil, 0, err
 	}
+	s.cache.set(ctx, cacheKey, cachedURLList{URLs: urls, Total: total})
+	return urls, total, nil
+}
+
+func (s *URLService) getURLs(limit, offset int, search, status, sortBy, sortOrder string) ([]*models.URLListItem, int64, error) {
+	var urls []*models.URLListItem
+	var total int64
+
+	// Build query
+	query := s.urlListQuery(search, status)
 
 	// Count total records (before pagination)
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, fmt.Errorf("failed to count URLs: %w", err)
 	}
 
-	// Apply sorting
-	orderClause := fmt.Sprintf("%s %s", sortBy, strings.ToUpper(sortOrder))
-	query = query.Order(orderClause)
+	// Apply sorting, with the ID breaking ties like keyset pages do
+	columns, err := parseURLSort(sortBy, sortOrder)
+	if err != nil {
+		return nil, 0, err
+	}
+	orders := make([]string, 0, len(columns)+1)
+	for _, column := range columns {
+		orders = append(orders, fmt.Sprintf("urls.%s %s", column.Name, strings.ToUpper(column.Order)))
+	}
+	orders = append(orders, "urls.id "+strings.ToUpper(columns[len(columns)-1].Order))
+	query = query.Order(strings.Join(orders, ", "))
 
 	// Apply pagination
-	query = query.Limit(limit).Offset(offset)
-
-	// Preload related data
-	query = query.Preload("Crawls", func(db *gorm.DB) *gorm.DB {
-		return db.Order("created_at DESC").Limit(1)
-	}).Preload("Links")
+	query = query.Select(urlListColumns).Limit(limit).Offset(offset)
 
 	// Execute query
 	if err := query.Find(&urls).Error; err != nil {
 		return nil, 0, fmt.Errorf("failed to fetch URLs: %w", err)
 	}
+	if err := s.attachLinkCounts(urls); err != nil {
+		return nil, 0, err
+	}
 
 	return urls, total, nil
 }
 
+// GetURLsAfter returns the URLs following cursor, using keyset pagination
+// instead of an offset so deep pages stay fast. It also returns the cursor of
+// the next page, which is empty on the last page.
+func (s *URLService) GetURLsAfter(limit int, cursor, search, status, sortBy, sortOrder string) ([]*models.URLListItem, int64, string, error) {
+	columns, err := parseURLSort(sortBy, sortOrder)
+	if err != nil {
+		return nil, 0, "", err
+	}
+	sortKeys := make([]string, len(columns))
+	for i, column := range columns {
+		sortKeys[i] = column.Name + ":" + column.Order
+	}
+	sortKey := strings.Join(sortKeys, ",")
+	after, err := decodeCursor(cursor, sortKey)
+	if err != nil {
+		return nil, 0, "", err
+	}
+
+	var total int64
+	if err := s.urlListQuery(search, status).Count(&total).Error; err != nil {
+		return nil, 0, "", fmt.Errorf("failed to count URLs: %w", err)
+	}
+
+	query, err := applyKeysetColumns(s.urlListQuery(search, status), "urls", columns, after)
+	if err != nil {
+		return nil, 0, "", err
+	}
+
+	// Fetch one extra row to learn whether another page follows
+	var urls []*models.URLListItem
+	if err := query.Select(urlListColumns).Limit(limit + 1).Find(&urls).Error; err != nil {
+		return nil, 0, "", fmt.Errorf("failed to fetch URLs: %w", err)
+	}
+
+	next := ""
+	if len(urls) > limit {
+		urls = urls[:limit]
+		last := urls[limit-1]
+		page := pageCursor{Sort: sortKey, ID: last.ID}
+		if len(columns) == 1 {
+			page.Value = urlSortValue(last, columns[0].Name)
+		} else {
+			for _, column := range columns {
+				page.Values = append(page.Values, urlSortValue(last, column.Name))
+			}
+		}
+		next = page.encode()
+	}
+	if err := s.attachLinkCounts(urls); err != nil {
+		return nil, 0, "", err
+	}
+	return urls, total, next, nil
+}
+
+// urlListColumns are the columns of a URL list item
+const urlListColumns = "urls.id, urls.url, urls.title, urls.html_version, urls.status, urls.has_login_form, urls.needs_attention, " +
+	"urls.user_id, urls.organization_id, urls.created_at, urls.updated_at"
+
+// attachLinkCounts fills in the link counts of the latest completed crawl of
+// a page of URLs with one grouped query. Links of earlier crawls are kept,
+// so they must not be counted. Joining an aggregate of the whole links table to the list
+// would count the links of every URL before the page is cut.
+func (s *URLService) attachLinkCounts(urls []*models.URLListItem) error {
+	if len(urls) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, len(urls))
+	for i, url := range urls {
+		ids[i] = url.ID
+	}
+
+	var counts []struct {
+		URLID         uint
+		InternalLinks int
+		ExternalLinks int
+		BrokenLinks   int
+	}
+	if err := s.db.Model(&models.Link{}).
+		Select("url_id, " +
+			"SUM(CASE WHEN link_type = 'internal' THEN occurrences ELSE 0 END) AS internal_links, " +
+			"SUM(CASE WHEN link_type = 'external' THEN occurrences ELSE 0 END) AS external_links, " +
+			"SUM(CASE WHEN is_accessible THEN 0 ELSE 1 END) AS broken_links").
+		Where("url_id IN ?", ids).
+		Where("crawl_id = (SELECT c.id FROM crawls c WHERE c.url_id = links.url_id AND c.status = ? "+
+			"ORDER BY c.created_at DESC, c.id DESC LIMIT 1)", "completed").
+		Group("url_id").
+		Scan(&counts).Error; err != nil {
+		return fmt.Errorf("failed to count links: %w", err)
+	}
+
+	byURL := make(map[uint]int, len(counts))
+	for i, count := range counts {
+		byURL[count.URLID] = i
+	}
+	for _, url := range urls {
+		if i, ok := byURL[url.ID]; ok {
+			url.InternalLinks = counts[i].InternalLinks
+			url.ExternalLinks = counts[i].ExternalLinks
+			url.BrokenLinks = counts[i].BrokenLinks
+		}
+	}
+	return nil
+}
+
+// urlListQuery selects the URLs matching the list filters
+func (s *URLService) urlListQuery(search, status string) *gorm.DB {
+	query := s.inOrganization(s.db.Model(&models.URL{}))
+
+	// Apply search filter
+	if search != "" {
+		searchPattern := "%" + strings.ToLower(search) + "%"
+		query = query.Where("LOWER(url) LIKE ? OR LOWER(title) LIKE ?", searchPattern, searchPattern)
+	}
+
+	// Apply status filter; several statuses are separated by commas
+	if status != "" {
+		if statuses := strings.Split(status, ","); len(statuses) > 1 {
+			query = query.Where("status IN ?", statuses)
+		} else {
+			query = query.Where("status = ?", status)
+		}
+	}
+
+	return s.filter.apply(query)
+}
+
+// urlSortColumns are the columns URL lists can be sorted by
+var urlSortColumns = map[string]bool{
+	"url":          true,
+	"title":        true,
+	"status":       true,
+	"html_version": true,
+	"created_at":   true,
+	"updated_at":   true,
+}
+
+// NormalizeURLSort validates a sort order like parseURLSort does and returns
+// it as "column direction,..." with defaultOrder filled in
+func NormalizeURLSort(sortBy, defaultOrder string) (string, error) {
+	columns, err := parseURLSort(sortBy, defaultOrder)
+	if err != nil {
+		return "", err
+	}
+	terms := make([]string, len(columns))
+	for i, column := range columns {
+		terms[i] = column.Name + " " + column.Order
+	}
+	return strings.Join(terms, ","), nil
+}
+
+// parseURLSort reads a sort order of one or more comma separated columns,
+// each optionally followed by a space or colon and asc or desc, e.g.
+// "status asc,updated_at desc". Columns without a direction use
+// defaultOrder and an empty sort order sorts by updated_at. The names end up
+// in ORDER BY, so only the columns of urlSortColumns are accepted.
+func parseURLSort(sortBy, defaultOrder string) ([]keysetColumn, error) {
+	if strings.TrimSpace(sortBy) == "" {
+		sortBy = "updated_at"
+	}
+
+	terms := strings.Split(sortBy, ",")
+	seen := make(map[string]bool, len(terms))
+	columns := make([]keysetColumn, len(terms))
+	for i, term := range terms {
+		name, order, found := strings.Cut(strings.TrimSpace(term), ":")
+		if !found {
+			name, order, _ = strings.Cut(strings.TrimSpace(term), " ")
+		}
+		name, order = strings.TrimSpace(name), strings.ToLower(strings.TrimSpace(order))
+		if !urlSortColumns[name] {
+			return nil, fmt.Errorf("%w: unknown sort column %q; use url, title, status, html_version, created_at or updated_at", ErrInvalidSort, name)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("%w: sort column %q is listed twice", ErrInvalidSort, name)
+		}
+		seen[name] = true
+		if order == "" {
+			order = strings.ToLower(defaultOrder)
+		}
+		if order != "asc" && order != "desc" {
+			return nil, fmt.Errorf("%w: sort direction of %s must be asc or desc", ErrInvalidSort, name)
+		}
+		columns[i] = keysetColumn{Name: name, Order: order, Time: name == "created_at" || name == "updated_at"}
+	}
+	return columns, nil
+}
+
+// urlSortValue returns the value of a URL list sort column
+func urlSortValue(url *models.URLListItem, column string) string {
+	switch column {
+	case "url":
+		return url.URL
+	case "title":
+		return url.Title
+	case "status":
+		return url.Status
+	case "html_version":
+		return url.HTMLVersion
+	case "created_at":
+		return cursorTimeValue(url.CreatedAt)
+	default:
+		return cursorTimeValue(url.UpdatedAt)
+	}
+}
+
 // GetURL retrieves a single URL by ID with full details
 func (s *URLService) GetURL(id uint) (*models.URL, error) {
+	url, err := s.repositories().URLs().FindWithDetails(id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrURLNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch URL: %w", err)
+	}
+
+	if err := s.attachLinkAnnotations(url.ID, toLinkPointers(url.Links)); err != nil {
+		return nil, err
+	}
+
+	return url, nil
+}
+
+// attachLinkAnnotations sets the annotation and filed issue of every broken
+// link that has one
+func (s *URLService) attachLinkAnnotations(urlID uint, links []*models.Link) error {
+	annotations, err := loadAnnotations(s.db, urlID, models.FindingBrokenLink)
+	if err != nil {
+		return err
+	}
+	issues, err := loadExternalIssues(s.db, urlID, models.FindingBrokenLink)
+	if err != nil {
+		return err
+	}
+
+	for _, link := range links {
+		if !link.IsAccessible {
+			link.Annotation = annotations[link.LinkURL]
+			link.ExternalIssue = issues[link.LinkURL]
+		}
+	}
+	return nil
+}
+
+func toLinkPointers(links []models.Link) []*models.Link {
+	pointers := make([]*models.Link, len(links))
+	for i := range links {
+		pointers[i] = &links[i]
+	}
+	return pointers
+}
+
+// SetLoginFormOverride manually classifies whether a URL has a login form.
+// Passing nil clears the override and restores the latest crawl's detection.
+func (s *URLService) SetLoginFormOverride(id uint, override *bool) (*models.URL, error) {
 	var url models.URL
+	if err := s.db.First(&url, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrURLNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch URL: %w", err)
+	}
+
+	hasLoginForm := url.HasLoginForm
+	if override != nil {
+		hasLoginForm = *override
+	} else {
+		var crawl models.Crawl
+		err := s.db.Where("url_id = ? AND status = ?", id, "completed").Order("created_at DESC").First(&crawl).Error
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("failed to fetch latest crawl: %w", err)
+		}
+		hasLoginForm = err == nil && crawl.LoginFormDetected
+	}
+
+	if err := s.db.Model(&url).Updates(map[string]interface{}{
+		"login_form_override": override,
+		"has_login_form":      hasLoginForm,
+	}).Error; err != nil {
+		return nil, fmt.Errorf("failed to update URL: %w", err)
+	}
+
+	url.LoginFormOverride = override
+	url.HasLoginForm = hasLoginForm
+	return &url, nil
+}
 
-	if err := s.db.
-		Preload("Crawls", func(db *gorm.DB) *gorm.DB {
-			return db.Order("created_at DESC")
-		}).
-		Preload("Links", func(db *gorm.DB) *gorm.DB {
-			return db.Where("is_accessible = ?", false)
-		}).
-		First(&url, id).Error; err != nil {
+// UpdateCrawlSettings changes how deep and how many pages of a URL are crawled
+func (s *URLService) UpdateCrawlSettings(id uint, req models.CrawlSettingsRequest) (*models.URL, error) {
+	updates := map[string]interface{}{}
+	if req.MaxDepth != nil {
+		if *req.MaxDepth < 0 || *req.MaxDepth > maxCrawlDepth {
+			return nil, fmt.Errorf("%w: max_depth must be between 0 and %d", ErrInvalidCrawlSettings, maxCrawlDepth)
+		}
+		updates["max_depth"] = *req.MaxDepth
+	}
+	if req.MaxPages != nil {
+		if *req.MaxPages < 0 || *req.MaxPages > maxCrawlPagesLimit {
+			return nil, fmt.Errorf("%w: max_pages must be between 0 and %d", ErrInvalidCrawlSettings, maxCrawlPagesLimit)
+		}
+		updates["max_pages"] = *req.MaxPages
+	}
+
+	var include, exclude []string
+	if req.IncludePatterns != nil {
+		patterns, err := validateScopePatterns("include_patterns", *req.IncludePatterns)
+		if err != nil {
+			return nil, err
+		}
+		include = patterns
+		updates["include_patterns"] = strings.Join(patterns, "\n")
+	}
+	if req.ExcludePatterns != nil {
+		patterns, err := validateScopePatterns("exclude_patterns", *req.ExcludePatterns)
+		if err != nil {
+			return nil, err
+		}
+		exclude = patterns
+		updates["exclude_patterns"] = strings.Join(patterns, "\n")
+	}
+	if req.AllowSubdomains != nil {
+		updates["allow_subdomains"] = *req.AllowSubdomains
+	}
+	if req.StripQuery != nil {
+		updates["strip_query"] = *req.StripQuery
+	}
+	if req.MaxQueryParams != nil {
+		if *req.MaxQueryParams < 0 || *req.MaxQueryParams > maxScopeQueryParams {
+			return nil, fmt.Errorf("%w: max_query_params must be between 0 and %d", ErrInvalidCrawlSettings, maxScopeQueryParams)
+		}
+		updates["max_query_params"] = *req.MaxQueryParams
+	}
+	if req.CheckInternalLinks != nil {
+		updates["check_internal_links"] = *req.CheckInternalLinks
+	}
+	var disabled []string
+	if req.DisabledExtractors != nil {
+		names, err := s.validateExtractors(*req.DisabledExtractors)
+		if err != nil {
+			return nil, err
+		}
+		disabled = names
+		updates["disabled_extractors"] = strings.Join(names, "\n")
+	}
+	var keywords []string
+	if req.Keywords != nil {
+		valid, err := validateKeywords(*req.Keywords)
+		if err != nil {
+			return nil, err
+		}
+		keywords = valid
+		updates["keywords"] = strings.Join(valid, "\n")
+	}
+
+	var url models.URL
+	if err := s.db.First(&url, id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("URL not found")
+			return nil, ErrURLNotFound
 		}
 		return nil, fmt.Errorf("failed to fetch URL: %w", err)
 	}
 
+	// Keep the lists in step with their columns so the save hook writes the same value
+	if req.IncludePatterns != nil {
+		url.IncludePatterns = include
+	}
+	if req.ExcludePatterns != nil {
+		url.ExcludePatterns = exclude
+	}
+	if req.DisabledExtractors != nil {
+		url.DisabledExtractors = disabled
+	}
+	if req.Keywords != nil {
+		url.Keywords = keywords
+	}
+
+	if len(updates) > 0 {
+		if err := s.db.Model(&url).Updates(updates).Error; err != nil {
+			return nil, fmt.Errorf("failed to update crawl settings: %w", err)
+		}
+	}
+
 	return &url, nil
 }
 
+// validateExtractors checks that the names refer to extractors of the crawler
+// and returns them without duplicates. Disabling "links" also stops deep
+// crawls from following links.
+func (s *URLService) validateExtractors(names []string) ([]string, error) {
+	var known []string
+	if lister, ok := s.crawlerService.(interface{ ExtractorNames() []string }); ok {
+		known = lister.ExtractorNames()
+	}
+
+	unique := []string{}
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if strings.ContainsRune(name, '\n') || (known != nil && !slices.Contains(known, name)) {
+			return nil, fmt.Errorf("%w: unknown extractor %q; use %s", ErrInvalidCrawlSettings, name, strings.Join(known, ", "))
+		}
+		if !slices.Contains(unique, name) {
+			unique = append(unique, name)
+		}
+	}
+	return unique, nil
+}
+
+// GetStructureReport aggregates the heading structure of every page in the latest completed crawl
+func (s *URLService) GetStructureReport(urlID uint) (*models.StructureReport, error) {
+	var url models.URL
+	if err := s.db.First(&url, urlID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrURLNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch URL: %w", err)
+	}
+
+	report := &models.StructureReport{URLID: urlID, Pages: []models.PageStructure{}}
+
+	var crawl models.Crawl
+	if err := s.db.Where("url_id = ? AND status = ?", urlID, "completed").Order("created_at DESC").First(&crawl).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return report, nil
+		}
+		return nil, fmt.Errorf("failed to fetch latest crawl: %w", err)
+	}
+	report.CrawlID = crawl.ID
+
+	var pages []models.Page
+	if err := s.db.Where("crawl_id = ? AND error_message = ?", crawl.ID, "").Order("depth ASC, id ASC").Find(&pages).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch pages: %w", err)
+	}
+
+	depthTotal := 0
+	for _, page := range pages {
+		var counts models.HeadingCounts
+		if page.HeadingCounts != "" {
+			json.Unmarshal([]byte(page.HeadingCounts), &counts)
+		}
+
+		entry := models.PageStructure{
+			PageURL:       page.PageURL,
+			Depth:         page.Depth,
+			Title:         page.Title,
+			HeadingCounts: counts,
+			HeadingDepth:  page.HeadingDepth,
+			MissingH1:     counts.H1 == 0,
+			MultipleH1:    counts.H1 > 1,
+		}
+		if entry.MissingH1 {
+			report.PagesMissingH1++
+		}
+		if entry.MultipleH1 {
+			report.PagesWithMultipleH1++
+		}
+
+		totals := &report.HeadingTotals
+		totals.H1 += counts.H1
+		totals.H2 += counts.H2
+		totals.H3 += counts.H3
+		totals.H4 += counts.H4
+		totals.H5 += counts.H5
+		totals.H6 += counts.H6
+
+		depthTotal += page.HeadingDepth
+		report.Pages = append(report.Pages, entry)
+	}
+
+	report.PagesCrawled = len(pages)
+	if len(pages) > 0 {
+		report.AverageHeadingDepth = float64(depthTotal) / float64(len(pages))
+	}
+
+	return report, nil
+}
+
 // DeleteURL soft deletes a URL by ID
 func (s *URLService) DeleteURL(id uint) error {
-	if err := s.db.Delete(&models.URL{}, id).Error; err != nil {
+	if err := s.repositories().URLs().Delete([]uint{id}, nil); err != nil {
 		return fmt.Errorf("failed to delete URL: %w", err)
 	}
 	return nil
@@ -146,42 +734,309 @@ func (s *URLService) DeleteURL(id uint) error {
 
 // BulkDeleteURLs soft deletes multiple URLs
 func (s *URLService) BulkDeleteURLs(ids []uint) error {
-	if err := s.db.Delete(&models.URL{}, ids).Error; err != nil {
+	if err := s.repositories().URLs().Delete(ids, s.organizationID); err != nil {
 		return fmt.Errorf("failed to bulk delete URLs: %w", err)
 	}
 	return nil
 }
 
-// GetURLLinks retrieves links for a specific URL with filtering
-func (s *URLService) GetURLLinks(urlID uint, linkType string, limit, offset int) ([]*models.Link, int64, error) {
-	var links []*models.Link
+// GetSEOReport returns the SEO checklist of the latest completed crawl of a URL
+func (s *URLService) GetSEOReport(urlID uint) (*models.SEOReport, error) {
+	var url models.URL
+	if err := s.db.First(&url, urlID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrURLNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch URL: %w", err)
+	}
+
+	report := &models.SEOReport{URLID: urlID, Checks: []models.SEOCheck{}}
+
+	var crawl models.Crawl
+	if err := s.db.Where("url_id = ? AND status = ?", urlID, "completed").Order("created_at DESC").First(&crawl).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return report, nil
+		}
+		return nil, fmt.Errorf("failed to fetch latest crawl: %w", err)
+	}
+
+	report.CrawlID = crawl.ID
+	report.Score = crawl.SEOScore
+	if crawl.SEOChecks != "" {
+		if err := json.Unmarshal([]byte(crawl.SEOChecks), &report.Checks); err != nil {
+			return nil, fmt.Errorf("invalid SEO checks: %w", err)
+		}
+	}
+
+	issues, err := loadExternalIssues(s.db, urlID, models.FindingSEOCheck)
+	if err != nil {
+		return nil, err
+	}
+	for i := range report.Checks {
+		if !report.Checks[i].Passed {
+			report.Checks[i].ExternalIssue = issues[report.Checks[i].Key]
+		}
+	}
+
+	return report, nil
+}
+
+// GetSecurityReport returns the security header audit of the latest completed crawl of a URL
+func (s *URLService) GetSecurityReport(urlID uint) (*models.SecurityReport, error) {
+	var url models.URL
+	if err := s.db.First(&url, urlID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrURLNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch URL: %w", err)
+	}
+
+	report := &models.SecurityReport{URLID: urlID, Headers: map[string]string{}, Checks: []models.SecurityCheck{}}
+
+	var crawl models.Crawl
+	if err := s.db.Where("url_id = ? AND status = ?", urlID, "completed").Order("created_at DESC").First(&crawl).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return report, nil
+		}
+		return nil, fmt.Errorf("failed to fetch latest crawl: %w", err)
+	}
+
+	report.CrawlID = crawl.ID
+	report.Score = crawl.SecurityScore
+	if crawl.SecurityHeaders != "" {
+		if err := json.Unmarshal([]byte(crawl.SecurityHeaders), &report.Headers); err != nil {
+			return nil, fmt.Errorf("invalid security headers: %w", err)
+		}
+	}
+	if crawl.SecurityChecks != "" {
+		if err := json.Unmarshal([]byte(crawl.SecurityChecks), &report.Checks); err != nil {
+			return nil, fmt.Errorf("invalid security checks: %w", err)
+		}
+	}
+
+	return report, nil
+}
+
+// GetAccessibilityReport lists the accessibility issues of a crawl of the URL.
+// A zero crawlID selects the latest completed crawl.
+func (s *URLService) GetAccessibilityReport(urlID, crawlID uint, rule string) (*models.AccessibilityReport, error) {
+	var url models.URL
+	if err := s.db.First(&url, urlID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrURLNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch URL: %w", err)
+	}
+
+	report := &models.AccessibilityReport{URLID: urlID, Counts: map[string]int{}, Issues: []models.AccessibilityIssue{}}
+
+	crawlQuery := s.db.Where("url_id = ?", urlID)
+	if crawlID != 0 {
+		crawlQuery = crawlQuery.Where("id = ?", crawlID)
+	} else {
+		crawlQuery = crawlQuery.Where("status = ?", "completed").Order("created_at DESC")
+	}
+
+	var crawl models.Crawl
+	if err := crawlQuery.First(&crawl).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			if crawlID != 0 {
+				return nil, ErrCrawlNotFound
+			}
+			return report, nil
+		}
+		return nil, fmt.Errorf("failed to fetch crawl: %w", err)
+	}
+	report.CrawlID = crawl.ID
+
+	var issues []models.AccessibilityIssue
+	if err := s.db.Where("crawl_id = ?", crawl.ID).Order("id ASC").Find(&issues).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch accessibility issues: %w", err)
+	}
+
+	annotations, err := loadAnnotations(s.db, urlID, models.FindingAccessibilityIssue)
+	if err != nil {
+		return nil, err
+	}
+
+	// Annotated issues stay listed with their note but no longer count
+	for _, issue := range issues {
+		issue.Annotation = annotations[issue.FindingKey()]
+		if issue.Annotation != nil {
+			report.Annotated++
+		} else {
+			report.Counts[issue.Rule]++
+		}
+		if rule == "" || issue.Rule == rule {
+			report.Issues = append(report.Issues, issue)
+		}
+	}
+
+	return report, nil
+}
+
+// GetMixedContentReport lists the mixed content issues of a crawl of the URL.
+// A zero crawlID selects the latest completed crawl.
+func (s *URLService) GetMixedContentReport(urlID, crawlID uint, resourceType string) (*models.MixedContentReport, error) {
+	var url models.URL
+	if err := s.db.First(&url, urlID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrURLNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch URL: %w", err)
+	}
+
+	report := &models.MixedContentReport{URLID: urlID, Counts: map[string]int{}, Issues: []models.MixedContentIssue{}}
+
+	crawlQuery := s.db.Where("url_id = ?", urlID)
+	if crawlID != 0 {
+		crawlQuery = crawlQuery.Where("id = ?", crawlID)
+	} else {
+		crawlQuery = crawlQuery.Where("status = ?", "completed").Order("created_at DESC")
+	}
+
+	var crawl models.Crawl
+	if err := crawlQuery.First(&crawl).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			if crawlID != 0 {
+				return nil, ErrCrawlNotFound
+			}
+			return report, nil
+		}
+		return nil, fmt.Errorf("failed to fetch crawl: %w", err)
+	}
+	report.CrawlID = crawl.ID
+
+	var issues []models.MixedContentIssue
+	if err := s.db.Where("crawl_id = ?", crawl.ID).Order("id ASC").Find(&issues).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch mixed content issues: %w", err)
+	}
+
+	annotations, err := loadAnnotations(s.db, urlID, models.FindingMixedContent)
+	if err != nil {
+		return nil, err
+	}
+
+	// Annotated issues stay listed with their note but no longer count
+	for _, issue := range issues {
+		issue.Annotation = annotations[issue.ResourceURL]
+		if issue.Annotation != nil {
+			report.Annotated++
+		} else {
+			report.Counts[issue.ResourceType]++
+		}
+		if resourceType == "" || issue.ResourceType == resourceType {
+			report.Issues = append(report.Issues, issue)
+		}
+	}
+
+	return report, nil
+}
+
+// GetURLImages retrieves the images found by the latest completed crawl of a URL
+func (s *URLService) GetURLImages(urlID uint, filter string, limit, offset int) ([]*models.Image, int64, error) {
+	var images []*models.Image
 	var total int64
 
 	// Verify URL exists
 	var url models.URL
 	if err := s.db.First(&url, urlID).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, 0, fmt.Errorf("URL not found")
+			return nil, 0, ErrURLNotFound
 		}
 		return nil, 0, fmt.Errorf("failed to verify URL: %w", err)
 	}
 
-	// Build query
-	query := s.db.Model(&models.Link{}).Where("url_id = ?", urlID)
+	var crawl models.Crawl
+	if err := s.db.Where("url_id = ? AND status = ?", urlID, "completed").Order("created_at DESC").First(&crawl).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return []*models.Image{}, 0, nil
+		}
+		return nil, 0, fmt.Errorf("failed to fetch latest crawl: %w", err)
+	}
 
-	// Apply link type filter
-	switch linkType {
-	case "internal":
-		query = query.Where("link_type = ?", "internal")
-	case "external":
-		query = query.Where("link_type = ?", "external")
+	query := s.db.Model(&models.Image{}).Where("crawl_id = ?", crawl.ID)
+
+	// Apply audit filter
+	switch filter {
+	case "missing_alt":
+		query = query.Where("missing_alt = ?", true)
+	case "decorative":
+		query = query.Where("missing_alt = ? AND alt = ?", false, "")
 	case "broken":
-		query = query.Where("is_accessible = ?", false)
-	case "accessible":
-		query = query.Where("is_accessible = ?", true)
+		query = query.Where("is_broken = ?", true)
+	case "missing_dimensions":
+		query = query.Where("width = ? OR height = ?", "", "")
 	// "all" or empty - no additional filter
 	}
 
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count images: %w", err)
+	}
+
+	if err := query.Order("id ASC").Limit(limit).Offset(offset).Find(&images).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch images: %w", err)
+	}
+
+	return images, total, nil
+}
+
+// GetURLForms retrieves the forms found by the latest completed crawl of a URL,
+// optionally only those of one type
+func (s *URLService) GetURLForms(urlID uint, formType string, limit, offset int) ([]*models.Form, int64, error) {
+	var forms []*models.Form
+	var total int64
+
+	// Verify URL exists
+	var url models.URL
+	if err := s.db.First(&url, urlID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, 0, ErrURLNotFound
+		}
+		return nil, 0, fmt.Errorf("failed to verify URL: %w", err)
+	}
+
+	var crawl models.Crawl
+	if err := s.db.Where("url_id = ? AND status = ?", urlID, "completed").Order("created_at DESC").First(&crawl).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return []*models.Form{}, 0, nil
+		}
+		return nil, 0, fmt.Errorf("failed to fetch latest crawl: %w", err)
+	}
+
+	query := s.db.Model(&models.Form{}).Where("crawl_id = ?", crawl.ID)
+	if formType != "" && formType != "all" {
+		query = query.Where("type = ?", formType)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count forms: %w", err)
+	}
+
+	if err := query.Order("id ASC").Limit(limit).Offset(offset).Find(&forms).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch forms: %w", err)
+	}
+
+	return forms, total, nil
+}
+
+// GetURLLinks retrieves links for a specific URL with filtering
+func (s *URLService) GetURLLinks(urlID uint, linkType string, limit, offset int) ([]*models.Link, int64, error) {
+	var links []*models.Link
+	var total int64
+
+	// Verify URL exists
+	var url models.URL
+	if err := s.db.First(&url, urlID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, 0, ErrURLNotFound
+		}
+		return nil, 0, fmt.Errorf("failed to verify URL: %w", err)
+	}
+
+	// Build query
+	query := s.linkListQuery(urlID, linkType)
+
 	// Count total records
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, fmt.Errorf("failed to count links: %w", err)
@@ -195,5 +1050,94 @@ func (s *URLService) GetURLLinks(urlID uint, linkType string, limit, offset int)
 		return nil, 0, fmt.Errorf("failed to fetch links: %w", err)
 	}
 
+	if err := s.attachLinkAnnotations(urlID, links); err != nil {
+		return nil, 0, err
+	}
+
 	return links, total, nil
-} 
\ No newline at end of file
+}
+
+// GetURLLinksAfter returns the links following cursor, newest first, using
+// keyset pagination. It also returns the cursor of the next page, which is
+// empty on the last page.
+func (s *URLService) GetURLLinksAfter(urlID uint, linkType string, limit int, cursor string) ([]*models.Link, int64, string, error) {
+	const sortKey = "created_at:desc"
+	after, err := decodeCursor(cursor, sortKey)
+	if err != nil {
+		return nil, 0, "", err
+	}
+
+	// Verify URL exists
+	var url models.URL
+	if err := s.db.First(&url, urlID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, 0, "", ErrURLNotFound
+		}
+		return nil, 0, "", fmt.Errorf("failed to verify URL: %w", err)
+	}
+
+	var total int64
+	if err := s.linkListQuery(urlID, linkType).Count(&total).Error; err != nil {
+		return nil, 0, "", fmt.Errorf("failed to count links: %w", err)
+	}
+
+	query, err := applyKeyset(s.linkListQuery(urlID, linkType), "links", "created_at", "desc", true, after)
+	if err != nil {
+		return nil, 0, "", err
+	}
+
+	// Fetch one extra row to learn whether another page follows
+	var links []*models.Link
+	if err := query.Limit(limit + 1).Find(&links).Error; err != nil {
+		return nil, 0, "", fmt.Errorf("failed to fetch links: %w", err)
+	}
+
+	next := ""
+	if len(links) > limit {
+		links = links[:limit]
+		last := links[limit-1]
+		next = pageCursor{Sort: sortKey, Value: cursorTimeValue(last.CreatedAt), ID: last.ID}.encode()
+	}
+
+	if err := s.attachLinkAnnotations(urlID, links); err != nil {
+		return nil, 0, "", err
+	}
+
+	return links, total, next, nil
+}
+
+// linkListQuery selects the links of a URL matching a link type filter
+func (s *URLService) linkListQuery(urlID uint, linkType string) *gorm.DB {
+	query := s.db.Model(&models.Link{}).Where("url_id = ?", urlID)
+
+	// Apply link type filter
+	switch linkType {
+	case "internal", "external", "mailto", "tel", "anchor", "other":
+		query = query.Where("link_type = ?", linkType)
+	case "broken":
+		query = query.Where("is_accessible = ?", false)
+	case "accessible":
+		query = query.Where("is_accessible = ?", true)
+	case "nofollow":
+		query = query.Where("nofollow = ?", true)
+	case "sponsored":
+		query = query.Where("sponsored = ?", true)
+	case "ugc":
+		query = query.Where("ugc = ?", true)
+	case "new_tab":
+		query = query.Where("target = ?", "_blank")
+	case models.LinkContextNav, models.LinkContextFooter, models.LinkContextContent:
+		query = query.Where("context = ?", linkType)
+	// "all" or empty - no additional filter
+	}
+
+	return query
+} 
+// isDuplicateKeyError reports whether err is a unique constraint violation
+// from MySQL, PostgreSQL or SQLite
+func isDuplicateKeyError(err error) bool {
+	message := err.Error()
+	return strings.Contains(message, "Duplicate entry") ||
+		strings.Contains(message, "duplicate key value") ||
+		strings.Contains(message, "UNIQUE constraint failed")
+}
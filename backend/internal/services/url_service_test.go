@@ -2,6 +2,7 @@ package services
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -21,7 +22,7 @@ func setupURLTestDB(t *testing.T) *gorm.DB {
 	require.NoError(t, err)
 
 	// Auto migrate all models
-	err = db.AutoMigrate(&models.URL{}, &models.Crawl{}, &models.Link{}, &models.User{})
+	err = db.AutoMigrate(&models.URL{}, &models.Crawl{}, &models.Link{}, &models.PageMeta{}, &models.Page{}, &models.PageLink{}, &models.Image{}, &models.Form{}, &models.CrawlEvent{}, &models.AccessibilityIssue{}, &models.MixedContentIssue{}, &models.ActivityEvent{}, &models.FindingAnnotation{}, &models.ExternalIssue{}, &models.User{})
 	require.NoError(t, err)
 
 	return db
@@ -31,6 +32,7 @@ func setupURLTestDB(t *testing.T) *gorm.DB {
 type mockCrawlerService struct {
 	startCrawlCalled bool
 	lastURLID        uint
+	rerunURLIDs      []uint
 }
 
 func (m *mockCrawlerService) StartCrawl(urlID uint) {
@@ -47,6 +49,7 @@ func (m *mockCrawlerService) GetCrawlStatus(urlID uint) (*models.CrawlStatusResp
 }
 
 func (m *mockCrawlerService) BulkRerunCrawls(urlIDs []uint) error {
+	m.rerunURLIDs = append(m.rerunURLIDs, urlIDs...)
 	return nil
 }
 
@@ -249,6 +252,55 @@ func TestURLService_GetURLs(t *testing.T) {
 		assert.Equal(t, "B Title", titles[1])
 		assert.Equal(t, "C Title", titles[2])
 	})
+
+	t.Run("link counts", func(t *testing.T) {
+		db := setupURLTestDB(t)
+		crawlerService := &mockCrawlerService{}
+		service := NewURLService(db, crawlerService)
+
+		url := &models.URL{URL: "https://example.com", Status: "completed"}
+		require.NoError(t, db.Create(url).Error)
+		require.NoError(t, db.Create(&models.URL{URL: "https://empty.com", Status: "pending"}).Error)
+		crawl := &models.Crawl{URLID: url.ID, Status: "completed"}
+		require.NoError(t, db.Create(crawl).Error)
+		links := []*models.Link{
+			{URLID: url.ID, CrawlID: crawl.ID, LinkURL: "https://example.com/a", LinkType: "internal", IsAccessible: true},
+			{URLID: url.ID, CrawlID: crawl.ID, LinkURL: "https://example.com/b", LinkType: "internal", IsAccessible: false},
+			{URLID: url.ID, CrawlID: crawl.ID, LinkURL: "https://other.com", LinkType: "external", IsAccessible: true},
+		}
+		for _, link := range links {
+			require.NoError(t, db.Create(link).Error)
+		}
+
+		result, _, err := service.GetURLs(10, 0, "", "", "url", "desc")
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+		assert.Equal(t, "https://example.com", result[0].URL)
+		assert.Equal(t, 2, result[0].InternalLinks)
+		assert.Equal(t, 1, result[0].ExternalLinks)
+		assert.Equal(t, 1, result[0].BrokenLinks)
+		assert.Zero(t, result[1].InternalLinks+result[1].ExternalLinks+result[1].BrokenLinks)
+
+		page, _, _, err := service.GetURLsAfter(1, "", "", "", "url", "desc")
+		require.NoError(t, err)
+		require.Len(t, page, 1)
+		assert.Equal(t, 2, page[0].InternalLinks)
+
+		t.Run("of the latest completed crawl only", func(t *testing.T) {
+			recrawl := &models.Crawl{URLID: url.ID, Status: "completed", CreatedAt: crawl.CreatedAt.Add(time.Minute)}
+			require.NoError(t, db.Create(recrawl).Error)
+			require.NoError(t, db.Create(&models.Link{URLID: url.ID, CrawlID: recrawl.ID, LinkURL: "https://example.com/a", LinkType: "internal", IsAccessible: true}).Error)
+			running := &models.Crawl{URLID: url.ID, Status: "running", CreatedAt: crawl.CreatedAt.Add(2 * time.Minute)}
+			require.NoError(t, db.Create(running).Error)
+			require.NoError(t, db.Create(&models.Link{URLID: url.ID, CrawlID: running.ID, LinkURL: "https://example.com/c", LinkType: "internal", IsAccessible: false}).Error)
+
+			result, _, err := service.GetURLs(10, 0, "", "", "url", "desc")
+			require.NoError(t, err)
+			assert.Equal(t, 1, result[0].InternalLinks)
+			assert.Zero(t, result[0].ExternalLinks)
+			assert.Zero(t, result[0].BrokenLinks)
+		})
+	})
 }
 
 func TestURLService_GetURL(t *testing.T) {
@@ -304,7 +356,7 @@ func TestURLService_GetURL(t *testing.T) {
 		result, err := service.GetURL(999)
 		assert.Error(t, err)
 		assert.Nil(t, result)
-		assert.Contains(t, err.Error(), "URL not found")
+		assert.ErrorIs(t, err, ErrURLNotFound)
 	})
 }
 
@@ -387,7 +439,176 @@ func TestURLService_BulkDeleteURLs(t *testing.T) {
 	})
 }
 
+func TestURLService_GetURLsAfter(t *testing.T) {
+	db := setupURLTestDB(t)
+	service := NewURLService(db, &mockCrawlerService{})
+
+	// Two URLs share a timestamp so the ID has to break the tie
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.Local)
+	for i, offset := range []time.Duration{0, time.Hour, time.Hour, 2 * time.Hour, 3 * time.Hour} {
+		url := &models.URL{URL: fmt.Sprintf("https://example%d.com", i), Title: fmt.Sprintf("Example %d", i), Status: "completed"}
+		require.NoError(t, db.Create(url).Error)
+		require.NoError(t, db.Model(url).UpdateColumn("created_at", base.Add(offset)).Error)
+	}
+
+	var seen []string
+	cursor := ""
+	for page := 0; page < 5; page++ {
+		result, total, next, err := service.GetURLsAfter(2, cursor, "", "", "created_at", "desc")
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), total)
+		for _, url := range result {
+			seen = append(seen, url.URL)
+		}
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+	assert.Equal(t, []string{
+		"https://example4.com", "https://example3.com", "https://example2.com", "https://example1.com", "https://example0.com",
+	}, seen)
+
+	t.Run("string sort columns", func(t *testing.T) {
+		result, _, next, err := service.GetURLsAfter(3, "", "", "", "title", "asc")
+		require.NoError(t, err)
+		require.Len(t, result, 3)
+		result, _, next, err = service.GetURLsAfter(3, next, "", "", "title", "asc")
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+		assert.Equal(t, "Example 3", result[0].Title)
+		assert.Empty(t, next)
+	})
+
+	t.Run("rejects foreign and malformed cursors", func(t *testing.T) {
+		_, _, next, err := service.GetURLsAfter(1, "", "", "", "title", "asc")
+		require.NoError(t, err)
+		_, _, _, err = service.GetURLsAfter(1, next, "", "", "title", "desc")
+		assert.ErrorIs(t, err, ErrInvalidCursor)
+		_, _, _, err = service.GetURLsAfter(1, "not a cursor", "", "", "title", "asc")
+		assert.ErrorIs(t, err, ErrInvalidCursor)
+	})
+}
+
+func TestURLService_MultiColumnSort(t *testing.T) {
+	db := setupURLTestDB(t)
+	service := NewURLService(db, &mockCrawlerService{})
+
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.Local)
+	for i, status := range []string{"error", "completed", "error", "running", "completed", "error"} {
+		url := &models.URL{URL: fmt.Sprintf("https://example%d.com", i), Status: status}
+		require.NoError(t, db.Create(url).Error)
+		require.NoError(t, db.Model(url).UpdateColumn("updated_at", base.Add(time.Duration(i%3)*time.Hour)).Error)
+	}
+	expected := []string{
+		"https://example4.com", "https://example1.com", // completed, newest first
+		"https://example5.com", "https://example2.com", "https://example0.com", // error
+		"https://example3.com", // running
+	}
+
+	urls, total, err := service.GetURLs(10, 0, "", "", "status asc,updated_at desc", "asc")
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), total)
+	var listed []string
+	for _, url := range urls {
+		listed = append(listed, url.URL)
+	}
+	assert.Equal(t, expected, listed)
+
+	// Keyset pages follow the same order across mixed directions
+	var seen []string
+	cursor := ""
+	for page := 0; page < 6; page++ {
+		result, _, next, err := service.GetURLsAfter(2, cursor, "", "", "status asc,updated_at desc", "asc")
+		require.NoError(t, err)
+		for _, url := range result {
+			seen = append(seen, url.URL)
+		}
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+	assert.Equal(t, expected, seen)
+
+	// Several statuses
+	_, total, err = service.GetURLs(10, 0, "", "running,completed", "url", "asc")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+
+	// Only known columns reach ORDER BY
+	for _, sortBy := range []string{"id; DROP TABLE urls", "url,url", "url sideways"} {
+		_, _, err = service.GetURLs(10, 0, "", "", sortBy, "asc")
+		assert.ErrorIs(t, err, ErrInvalidSort, sortBy)
+		_, _, _, err = service.GetURLsAfter(10, "", "", "", sortBy, "asc")
+		assert.ErrorIs(t, err, ErrInvalidSort, sortBy)
+	}
+	normalized, err := NormalizeURLSort("status, url:DESC", "asc")
+	require.NoError(t, err)
+	assert.Equal(t, "status asc,url desc", normalized)
+}
+
+func TestURLService_GetURLLinksAfter(t *testing.T) {
+	db := setupURLTestDB(t)
+	service := NewURLService(db, &mockCrawlerService{})
+
+	url := &models.URL{URL: "https://example.com", Status: "completed"}
+	require.NoError(t, db.Create(url).Error)
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.Local)
+	for i := 0; i < 5; i++ {
+		linkType := "internal"
+		if i%2 == 1 {
+			linkType = "external"
+		}
+		require.NoError(t, db.Create(&models.Link{URLID: url.ID, LinkURL: fmt.Sprintf("https://example.com/%d", i), LinkType: linkType, CreatedAt: created}).Error)
+	}
+
+	links, total, next, err := service.GetURLLinksAfter(url.ID, "internal", 2, "")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	require.Len(t, links, 2)
+	assert.Equal(t, "https://example.com/4", links[0].LinkURL)
+	assert.NotEmpty(t, next)
+
+	links, _, next, err = service.GetURLLinksAfter(url.ID, "internal", 2, next)
+	require.NoError(t, err)
+	require.Len(t, links, 1)
+	assert.Equal(t, "https://example.com/0", links[0].LinkURL)
+	assert.Empty(t, next)
+
+	_, _, _, err = service.GetURLLinksAfter(999, "", 2, "")
+	assert.ErrorIs(t, err, ErrURLNotFound)
+}
+
 func TestURLService_GetURLLinks(t *testing.T) {
+	t.Run("attribute and context filters", func(t *testing.T) {
+		db := setupURLTestDB(t)
+		service := NewURLService(db, &mockCrawlerService{})
+
+		url := &models.URL{URL: "https://example.com", Status: "completed"}
+		require.NoError(t, db.Create(url).Error)
+		require.NoError(t, db.Create(&[]models.Link{
+			{URLID: url.ID, LinkURL: "https://example.com/about", LinkType: "internal", IsAccessible: true, Context: models.LinkContextNav},
+			{URLID: url.ID, LinkURL: "https://partner.com", LinkType: "external", IsAccessible: true, Rel: "sponsored nofollow", Nofollow: true, Sponsored: true, Target: "_blank", Context: models.LinkContextContent},
+			{URLID: url.ID, LinkURL: "https://forum.example.org", LinkType: "external", IsAccessible: true, Rel: "ugc", UGC: true, Context: models.LinkContextContent},
+			{URLID: url.ID, LinkURL: "https://example.com/privacy", LinkType: "internal", IsAccessible: true, Context: models.LinkContextFooter},
+		}).Error)
+
+		for filter, expected := range map[string]int{
+			"nofollow":  1,
+			"sponsored": 1,
+			"ugc":       1,
+			"new_tab":   1,
+			"nav":       1,
+			"footer":    1,
+			"content":   2,
+		} {
+			_, total, err := service.GetURLLinks(url.ID, filter, 10, 0)
+			require.NoError(t, err)
+			assert.Equal(t, int64(expected), total, filter)
+		}
+	})
+
 	t.Run("successful link retrieval", func(t *testing.T) {
 		db := setupURLTestDB(t)
 		crawlerService := &mockCrawlerService{}
@@ -503,6 +724,405 @@ func TestURLService_GetURLLinks(t *testing.T) {
 		assert.Error(t, err)
 		assert.Nil(t, result)
 		assert.Equal(t, int64(0), total)
-		assert.Contains(t, err.Error(), "URL not found")
+		assert.ErrorIs(t, err, ErrURLNotFound)
+	})
+} 
+
+func TestURLService_CreateURL_Normalization(t *testing.T) {
+	db := setupURLTestDB(t)
+	service := NewURLService(db, &mockCrawlerService{})
+
+	first, err := service.CreateURL("https://example.com/")
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com", first.URL)
+
+	second, err := service.CreateURL("HTTPS://EXAMPLE.COM:443#about")
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, second.ID)
+
+	var count int64
+	db.Model(&models.URL{}).Count(&count)
+	assert.Equal(t, int64(1), count)
+
+	_, err = service.CreateURL("ftp://example.com")
+	assert.ErrorIs(t, err, ErrInvalidURL)
+}
+
+func TestURLService_CreateURL_Validation(t *testing.T) {
+	db := setupURLTestDB(t)
+	crawlerService := &mockCrawlerService{}
+	validator := newTestURLValidator(t, URLValidatorOptions{}, map[string][]string{"example.com": {"93.184.216.34"}})
+	service := NewURLServiceWithValidator(db, crawlerService, validator)
+
+	_, err := service.CreateURL("http://169.254.169.254/latest/meta-data")
+	assert.ErrorIs(t, err, ErrURLNotAllowed)
+
+	var count int64
+	db.Model(&models.URL{}).Count(&count)
+	assert.Zero(t, count)
+	assert.False(t, crawlerService.startCrawlCalled)
+
+	url, err := service.CreateURL("https://example.com")
+	require.NoError(t, err)
+	assert.NotZero(t, url.ID)
+}
+
+func TestURLService_CreateURLForUser(t *testing.T) {
+	t.Run("records the owner", func(t *testing.T) {
+		db := setupURLTestDB(t)
+		service := NewURLService(db, &mockCrawlerService{})
+
+		url, err := service.CreateURLForUser("https://example.com", 5)
+		require.NoError(t, err)
+		require.NotNil(t, url.UserID)
+		assert.Equal(t, uint(5), *url.UserID)
+	})
+
+	t.Run("keeps the first owner of an existing URL", func(t *testing.T) {
+		db := setupURLTestDB(t)
+		service := NewURLService(db, &mockCrawlerService{})
+
+		_, err := service.CreateURLForUser("https://example.com", 5)
+		require.NoError(t, err)
+
+		url, err := service.CreateURLForUser("https://example.com", 6)
+		require.NoError(t, err)
+		require.NotNil(t, url.UserID)
+		assert.Equal(t, uint(5), *url.UserID)
+	})
+}
+
+func TestURLService_SetLoginFormOverride(t *testing.T) {
+	t.Run("overrides and restores detection", func(t *testing.T) {
+		db := setupURLTestDB(t)
+		service := NewURLService(db, &mockCrawlerService{})
+
+		url := &models.URL{URL: "https://example.com", Status: "completed", HasLoginForm: true}
+		require.NoError(t, db.Create(url).Error)
+		require.NoError(t, db.Create(&models.Crawl{URLID: url.ID, Status: "completed", LoginFormDetected: true}).Error)
+
+		off := false
+		updated, err := service.SetLoginFormOverride(url.ID, &off)
+		require.NoError(t, err)
+		assert.False(t, updated.HasLoginForm)
+		require.NotNil(t, updated.LoginFormOverride)
+
+		var stored models.URL
+		require.NoError(t, db.First(&stored, url.ID).Error)
+		assert.False(t, stored.HasLoginForm)
+		require.NotNil(t, stored.LoginFormOverride)
+		assert.False(t, *stored.LoginFormOverride)
+
+		updated, err = service.SetLoginFormOverride(url.ID, nil)
+		require.NoError(t, err)
+		assert.True(t, updated.HasLoginForm)
+		assert.Nil(t, updated.LoginFormOverride)
+	})
+
+	t.Run("URL not found", func(t *testing.T) {
+		db := setupURLTestDB(t)
+		service := NewURLService(db, &mockCrawlerService{})
+
+		_, err := service.SetLoginFormOverride(999, nil)
+		assert.ErrorIs(t, err, ErrURLNotFound)
+	})
+}
+
+func TestURLService_UpdateCrawlSettings(t *testing.T) {
+	db := setupURLTestDB(t)
+	service := NewURLService(db, &mockCrawlerService{})
+
+	url := &models.URL{URL: "https://example.com"}
+	require.NoError(t, db.Create(url).Error)
+
+	t.Run("updates limits", func(t *testing.T) {
+		depth, pages := 3, 50
+		updated, err := service.UpdateCrawlSettings(url.ID, models.CrawlSettingsRequest{MaxDepth: &depth, MaxPages: &pages})
+		require.NoError(t, err)
+		assert.Equal(t, 3, updated.MaxDepth)
+		assert.Equal(t, 50, updated.MaxPages)
+	})
+
+	t.Run("rejects out of range depth", func(t *testing.T) {
+		depth := maxCrawlDepth + 1
+		_, err := service.UpdateCrawlSettings(url.ID, models.CrawlSettingsRequest{MaxDepth: &depth})
+		assert.ErrorIs(t, err, ErrInvalidCrawlSettings)
+	})
+
+	t.Run("updates crawl scope", func(t *testing.T) {
+		exclude := []string{" /admin/* ", "", "re:^/calendar/\\d{4}"}
+		allow, params := true, 2
+		updated, err := service.UpdateCrawlSettings(url.ID, models.CrawlSettingsRequest{ExcludePatterns: &exclude, AllowSubdomains: &allow, MaxQueryParams: &params})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"/admin/*", "re:^/calendar/\\d{4}"}, updated.ExcludePatterns)
+		assert.True(t, updated.AllowSubdomains)
+
+		var stored models.URL
+		require.NoError(t, db.First(&stored, url.ID).Error)
+		assert.Equal(t, []string{"/admin/*", "re:^/calendar/\\d{4}"}, stored.ExcludePatterns)
+		assert.Empty(t, stored.IncludePatterns)
+		assert.Equal(t, 2, stored.MaxQueryParams)
+		assert.Equal(t, 3, stored.MaxDepth)
 	})
-} 
\ No newline at end of file
+
+	t.Run("rejects invalid patterns", func(t *testing.T) {
+		include := []string{"re:("}
+		_, err := service.UpdateCrawlSettings(url.ID, models.CrawlSettingsRequest{IncludePatterns: &include})
+		assert.ErrorIs(t, err, ErrInvalidCrawlSettings)
+
+		params := maxScopeQueryParams + 1
+		_, err = service.UpdateCrawlSettings(url.ID, models.CrawlSettingsRequest{MaxQueryParams: &params})
+		assert.ErrorIs(t, err, ErrInvalidCrawlSettings)
+	})
+
+	t.Run("disables extractors", func(t *testing.T) {
+		service := NewURLService(db, NewCrawlerService(db))
+		disabled := []string{"images", " accessibility ", "images"}
+		updated, err := service.UpdateCrawlSettings(url.ID, models.CrawlSettingsRequest{DisabledExtractors: &disabled})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"images", "accessibility"}, updated.DisabledExtractors)
+
+		var stored models.URL
+		require.NoError(t, db.First(&stored, url.ID).Error)
+		assert.Equal(t, []string{"images", "accessibility"}, stored.DisabledExtractors)
+
+		unknown := []string{"screenshots"}
+		_, err = service.UpdateCrawlSettings(url.ID, models.CrawlSettingsRequest{DisabledExtractors: &unknown})
+		assert.ErrorIs(t, err, ErrInvalidCrawlSettings)
+	})
+
+	t.Run("watches keywords", func(t *testing.T) {
+		keywords := []string{" Free   shipping ", "free shipping", "", "Terms of service"}
+		updated, err := service.UpdateCrawlSettings(url.ID, models.CrawlSettingsRequest{Keywords: &keywords})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Free shipping", "Terms of service"}, updated.Keywords)
+
+		var stored models.URL
+		require.NoError(t, db.First(&stored, url.ID).Error)
+		assert.Equal(t, []string{"Free shipping", "Terms of service"}, stored.Keywords)
+
+		tooLong := []string{strings.Repeat("a", 256)}
+		_, err = service.UpdateCrawlSettings(url.ID, models.CrawlSettingsRequest{Keywords: &tooLong})
+		assert.ErrorIs(t, err, ErrInvalidCrawlSettings)
+	})
+}
+
+func TestURLService_GetStructureReport(t *testing.T) {
+	db := setupURLTestDB(t)
+	service := NewURLService(db, &mockCrawlerService{})
+
+	url := &models.URL{URL: "https://example.com", Status: "completed"}
+	require.NoError(t, db.Create(url).Error)
+	crawl := &models.Crawl{URLID: url.ID, Status: "completed"}
+	require.NoError(t, db.Create(crawl).Error)
+
+	require.NoError(t, db.Create(&[]models.Page{
+		{URLID: url.ID, CrawlID: crawl.ID, PageURL: "https://example.com/", HeadingCounts: `{"h1":1,"h2":2}`, HeadingDepth: 2},
+		{URLID: url.ID, CrawlID: crawl.ID, PageURL: "https://example.com/a", Depth: 1, HeadingCounts: `{"h2":1,"h3":1,"h4":1}`, HeadingDepth: 4},
+		{URLID: url.ID, CrawlID: crawl.ID, PageURL: "https://example.com/b", Depth: 1, HeadingCounts: `{"h1":2}`, HeadingDepth: 1},
+		{URLID: url.ID, CrawlID: crawl.ID, PageURL: "https://example.com/broken", Depth: 1, ErrorMessage: "HTTP 404: 404 Not Found"},
+	}).Error)
+
+	report, err := service.GetStructureReport(url.ID)
+	require.NoError(t, err)
+	assert.Equal(t, crawl.ID, report.CrawlID)
+	assert.Equal(t, 3, report.PagesCrawled)
+	assert.Equal(t, 1, report.PagesMissingH1)
+	assert.Equal(t, 1, report.PagesWithMultipleH1)
+	assert.InDelta(t, 7.0/3.0, report.AverageHeadingDepth, 0.001)
+	assert.Equal(t, 3, report.HeadingTotals.H1)
+	assert.True(t, report.Pages[1].MissingH1)
+
+	_, err = service.GetStructureReport(999)
+	assert.ErrorIs(t, err, ErrURLNotFound)
+}
+
+func TestURLService_GetURLImages(t *testing.T) {
+	db := setupURLTestDB(t)
+	service := NewURLService(db, &mockCrawlerService{})
+
+	url := &models.URL{URL: "https://example.com", Status: "completed"}
+	require.NoError(t, db.Create(url).Error)
+	oldCrawl := &models.Crawl{URLID: url.ID, Status: "completed", CreatedAt: time.Now().Add(-time.Hour)}
+	require.NoError(t, db.Create(oldCrawl).Error)
+	crawl := &models.Crawl{URLID: url.ID, Status: "completed"}
+	require.NoError(t, db.Create(crawl).Error)
+
+	require.NoError(t, db.Create(&[]models.Image{
+		{URLID: url.ID, CrawlID: oldCrawl.ID, Src: "https://example.com/old.png", MissingAlt: true},
+		{URLID: url.ID, CrawlID: crawl.ID, Src: "https://example.com/a.png", Alt: "A", Width: "10", Height: "10", StatusCode: 200},
+		{URLID: url.ID, CrawlID: crawl.ID, Src: "https://example.com/b.png", MissingAlt: true, StatusCode: 404, IsBroken: true},
+		{URLID: url.ID, CrawlID: crawl.ID, Src: "https://example.com/c.png", StatusCode: 200},
+	}).Error)
+
+	tests := []struct {
+		filter   string
+		expected int64
+	}{
+		{"", 3},
+		{"missing_alt", 1},
+		{"decorative", 1},
+		{"broken", 1},
+		{"missing_dimensions", 2},
+	}
+	for _, tt := range tests {
+		t.Run("filter "+tt.filter, func(t *testing.T) {
+			images, total, err := service.GetURLImages(url.ID, tt.filter, 50, 0)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, total)
+			assert.Len(t, images, int(tt.expected))
+		})
+	}
+
+	t.Run("URL not found", func(t *testing.T) {
+		_, _, err := service.GetURLImages(999, "", 50, 0)
+		assert.ErrorIs(t, err, ErrURLNotFound)
+	})
+}
+
+func TestURLService_GetSEOReport(t *testing.T) {
+	db := setupURLTestDB(t)
+	service := NewURLService(db, &mockCrawlerService{})
+
+	url := &models.URL{URL: "https://example.com", Status: "completed"}
+	require.NoError(t, db.Create(url).Error)
+
+	t.Run("no completed crawl", func(t *testing.T) {
+		report, err := service.GetSEOReport(url.ID)
+		require.NoError(t, err)
+		assert.Zero(t, report.CrawlID)
+		assert.Empty(t, report.Checks)
+	})
+
+	t.Run("returns the stored checklist", func(t *testing.T) {
+		crawl := &models.Crawl{URLID: url.ID, Status: "completed", SEOScore: 85, SEOChecks: `[{"key":"canonical","passed":false,"score":0,"max_score":15}]`}
+		require.NoError(t, db.Create(crawl).Error)
+
+		report, err := service.GetSEOReport(url.ID)
+		require.NoError(t, err)
+		assert.Equal(t, crawl.ID, report.CrawlID)
+		assert.Equal(t, 85, report.Score)
+		require.Len(t, report.Checks, 1)
+		assert.Equal(t, "canonical", report.Checks[0].Key)
+	})
+}
+
+func TestURLService_GetSecurityReport(t *testing.T) {
+	db := setupURLTestDB(t)
+	service := NewURLService(db, &mockCrawlerService{})
+
+	url := &models.URL{URL: "https://example.com", Status: "completed"}
+	require.NoError(t, db.Create(url).Error)
+
+	t.Run("no completed crawl", func(t *testing.T) {
+		report, err := service.GetSecurityReport(url.ID)
+		require.NoError(t, err)
+		assert.Zero(t, report.CrawlID)
+		assert.Empty(t, report.Headers)
+		assert.Empty(t, report.Checks)
+	})
+
+	t.Run("returns the stored audit", func(t *testing.T) {
+		crawl := &models.Crawl{
+			URLID:           url.ID,
+			Status:          "completed",
+			SecurityScore:   15,
+			SecurityHeaders: `{"X-Content-Type-Options":"nosniff"}`,
+			SecurityChecks:  `[{"header":"X-Content-Type-Options","present":true,"passed":true,"score":15,"max_score":15}]`,
+		}
+		require.NoError(t, db.Create(crawl).Error)
+
+		report, err := service.GetSecurityReport(url.ID)
+		require.NoError(t, err)
+		assert.Equal(t, crawl.ID, report.CrawlID)
+		assert.Equal(t, 15, report.Score)
+		assert.Equal(t, "nosniff", report.Headers["X-Content-Type-Options"])
+		require.Len(t, report.Checks, 1)
+		assert.True(t, report.Checks[0].Passed)
+	})
+
+	t.Run("URL not found", func(t *testing.T) {
+		_, err := service.GetSecurityReport(999)
+		assert.ErrorIs(t, err, ErrURLNotFound)
+	})
+}
+
+func TestURLService_GetMixedContentReport(t *testing.T) {
+	db := setupURLTestDB(t)
+	service := NewURLService(db, &mockCrawlerService{})
+
+	url := &models.URL{URL: "https://example.com", Status: "completed"}
+	require.NoError(t, db.Create(url).Error)
+	crawl := &models.Crawl{URLID: url.ID, Status: "completed"}
+	require.NoError(t, db.Create(crawl).Error)
+
+	require.NoError(t, db.Create(&[]models.MixedContentIssue{
+		{URLID: url.ID, CrawlID: crawl.ID, ResourceType: models.MixedContentScript, ResourceURL: "http://cdn.example.com/app.js", Blocked: true},
+		{URLID: url.ID, CrawlID: crawl.ID, ResourceType: models.MixedContentImage, ResourceURL: "http://example.com/a.png"},
+		{URLID: url.ID, CrawlID: crawl.ID, ResourceType: models.MixedContentImage, ResourceURL: "http://example.com/b.png"},
+	}).Error)
+
+	t.Run("latest crawl", func(t *testing.T) {
+		report, err := service.GetMixedContentReport(url.ID, 0, "")
+		require.NoError(t, err)
+		assert.Equal(t, crawl.ID, report.CrawlID)
+		assert.Len(t, report.Issues, 3)
+		assert.Equal(t, map[string]int{models.MixedContentScript: 1, models.MixedContentImage: 2}, report.Counts)
+	})
+
+	t.Run("filters by resource type", func(t *testing.T) {
+		report, err := service.GetMixedContentReport(url.ID, 0, models.MixedContentScript)
+		require.NoError(t, err)
+		require.Len(t, report.Issues, 1)
+		assert.True(t, report.Issues[0].Blocked)
+	})
+
+	t.Run("unknown crawl", func(t *testing.T) {
+		_, err := service.GetMixedContentReport(url.ID, 999, "")
+		assert.ErrorIs(t, err, ErrCrawlNotFound)
+	})
+}
+
+func TestURLService_GetAccessibilityReport(t *testing.T) {
+	db := setupURLTestDB(t)
+	service := NewURLService(db, &mockCrawlerService{})
+
+	url := &models.URL{URL: "https://example.com", Status: "completed"}
+	require.NoError(t, db.Create(url).Error)
+	oldCrawl := &models.Crawl{URLID: url.ID, Status: "completed", CreatedAt: time.Now().Add(-time.Hour)}
+	require.NoError(t, db.Create(oldCrawl).Error)
+	crawl := &models.Crawl{URLID: url.ID, Status: "completed"}
+	require.NoError(t, db.Create(crawl).Error)
+
+	require.NoError(t, db.Create(&[]models.AccessibilityIssue{
+		{URLID: url.ID, CrawlID: oldCrawl.ID, Rule: models.A11yRuleHTMLLang},
+		{URLID: url.ID, CrawlID: crawl.ID, Rule: models.A11yRuleImageAlt},
+		{URLID: url.ID, CrawlID: crawl.ID, Rule: models.A11yRuleImageAlt},
+		{URLID: url.ID, CrawlID: crawl.ID, Rule: models.A11yRuleFormLabel},
+	}).Error)
+
+	t.Run("latest crawl", func(t *testing.T) {
+		report, err := service.GetAccessibilityReport(url.ID, 0, "")
+		require.NoError(t, err)
+		assert.Equal(t, crawl.ID, report.CrawlID)
+		assert.Len(t, report.Issues, 3)
+		assert.Equal(t, map[string]int{models.A11yRuleImageAlt: 2, models.A11yRuleFormLabel: 1}, report.Counts)
+	})
+
+	t.Run("filters by rule", func(t *testing.T) {
+		report, err := service.GetAccessibilityReport(url.ID, 0, models.A11yRuleFormLabel)
+		require.NoError(t, err)
+		assert.Len(t, report.Issues, 1)
+		assert.Equal(t, 2, report.Counts[models.A11yRuleImageAlt])
+	})
+
+	t.Run("specific crawl", func(t *testing.T) {
+		report, err := service.GetAccessibilityReport(url.ID, oldCrawl.ID, "")
+		require.NoError(t, err)
+		require.Len(t, report.Issues, 1)
+		assert.Equal(t, models.A11yRuleHTMLLang, report.Issues[0].Rule)
+
+		_, err = service.GetAccessibilityReport(url.ID, 999, "")
+		assert.ErrorIs(t, err, ErrCrawlNotFound)
+	})
+}
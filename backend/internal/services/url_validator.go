@@ -0,0 +1,232 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// ErrURLNotAllowed is returned when a URL points at a host or port the crawler may not reach
+var ErrURLNotAllowed = errors.New("URL not allowed")
+
+// dnsLookupTimeout bounds how long validation waits for a host to resolve
+const dnsLookupTimeout = 5 * time.Second
+
+// blockedNetworks are address ranges the crawler must not reach unless allowlisted,
+// on top of the loopback, private, link-local and multicast ranges checked by net.IP
+var blockedNetworks = mustParseCIDRs(
+	"0.0.0.0/8",     // "this" network
+	"100.64.0.0/10", // carrier-grade NAT
+	"192.0.0.0/24",  // IETF protocol assignments
+	"198.18.0.0/15", // benchmarking
+	"240.0.0.0/4",   // reserved
+	"64:ff9b::/96",  // NAT64, can map to internal IPv4 addresses
+)
+
+// URLValidatorOptions configures which destinations the crawler may reach
+type URLValidatorOptions struct {
+	// AllowedHosts skip the address check, e.g. an internal staging site
+	AllowedHosts []string
+	// AllowedNetworks are CIDR ranges exempt from the address check
+	AllowedNetworks []string
+	// AllowedPorts lists the ports URLs may use; empty allows 80 and 443 only
+	AllowedPorts []int
+}
+
+// URLValidator rejects URLs that would make the crawler request internal
+// services, such as cloud metadata endpoints or hosts on the private network
+type URLValidator struct {
+	allowedHosts    map[string]bool
+	allowedNetworks []*net.IPNet
+	allowedPorts    map[int]bool
+	lookupIP        func(ctx context.Context, host string) ([]net.IP, error)
+}
+
+// NewURLValidator creates a validator; it fails if an allowed network is not valid CIDR
+func NewURLValidator(options URLValidatorOptions) (*URLValidator, error) {
+	v := &URLValidator{
+		allowedHosts: map[string]bool{},
+		allowedPorts: map[int]bool{},
+		lookupIP: func(ctx context.Context, host string) ([]net.IP, error) {
+			return net.DefaultResolver.LookupIP(ctx, "ip", host)
+		},
+	}
+
+	for _, host := range options.AllowedHosts {
+		v.allowedHosts[strings.ToLower(strings.TrimSpace(host))] = true
+	}
+	for _, cidr := range options.AllowedNetworks {
+		_, network, err := net.ParseCIDR(strings.TrimSpace(cidr))
+		if err != nil {
+			return nil, fmt.Errorf("invalid allowed network %q: %w", cidr, err)
+		}
+		v.allowedNetworks = append(v.allowedNetworks, network)
+	}
+
+	ports := options.AllowedPorts
+	if len(ports) == 0 {
+		ports = []int{80, 443}
+	}
+	for _, port := range ports {
+		v.allowedPorts[port] = true
+	}
+
+	return v, nil
+}
+
+// Validate checks the port of a URL and that every address its host resolves to is public
+func (v *URLValidator) Validate(rawURL string) error {
+	return v.ValidateContext(context.Background(), rawURL)
+}
+
+// ValidateContext is Validate with the host lookup cancelled with ctx
+func (v *URLValidator) ValidateContext(ctx context.Context, rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidURL, err)
+	}
+
+	if !v.portAllowed(parsed.Scheme, parsed.Port()) {
+		return fmt.Errorf("%w: port %s is not allowed", ErrURLNotAllowed, parsed.Port())
+	}
+
+	host := strings.ToLower(parsed.Hostname())
+	if v.allowedHosts[host] {
+		return nil
+	}
+
+	ips := []net.IP{net.ParseIP(host)}
+	if ips[0] == nil {
+		ctx, cancel := context.WithTimeout(ctx, dnsLookupTimeout)
+		defer cancel()
+
+		ips, err = v.lookupIP(ctx, host)
+		if err != nil {
+			return fmt.Errorf("%w: cannot resolve %s: %v", ErrURLNotAllowed, host, err)
+		}
+		if len(ips) == 0 {
+			return fmt.Errorf("%w: %s has no addresses", ErrURLNotAllowed, host)
+		}
+	}
+
+	// Every address must pass, since the crawler may connect to any of them
+	for _, ip := range ips {
+		if !v.ipAllowed(ip) {
+			return fmt.Errorf("%w: %s resolves to internal address %s", ErrURLNotAllowed, host, ip)
+		}
+	}
+	return nil
+}
+
+// portAllowed tells whether URLs of the scheme may use the port, empty for the scheme's default
+func (v *URLValidator) portAllowed(scheme, port string) bool {
+	if port == "" {
+		port = defaultPorts[strings.ToLower(scheme)]
+	}
+	number, err := strconv.Atoi(port)
+	return err == nil && v.allowedPorts[number]
+}
+
+func (v *URLValidator) ipAllowed(ip net.IP) bool {
+	for _, network := range v.allowedNetworks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsMulticast() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() {
+		return false
+	}
+	for _, network := range blockedNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// crawlerDestinations is the validator every connection of the crawler is
+// checked against; nil leaves connections unchecked
+var crawlerDestinations atomic.Pointer[URLValidator]
+
+// RestrictCrawlerDestinations checks every connection the crawler makes
+// against v: page fetches, redirects, link, image and icon checks, monitors
+// and sitemaps. Addresses are checked once resolved, right before connecting,
+// so hosts that resolve to another address after validation are refused too.
+func RestrictCrawlerDestinations(v *URLValidator) {
+	crawlerDestinations.Store(v)
+}
+
+// crawlerDialer connects the crawler's requests
+var crawlerDialer = &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+
+// dialCrawlerDestination connects to address unless the destination
+// validator refuses its port or the address its host resolves to
+func dialCrawlerDestination(ctx context.Context, network, address string) (net.Conn, error) {
+	v := crawlerDestinations.Load()
+	if v == nil {
+		return crawlerDialer.DialContext(ctx, network, address)
+	}
+
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+	if number, err := strconv.Atoi(port); err != nil || !v.allowedPorts[number] {
+		return nil, fmt.Errorf("%w: port %s is not allowed", ErrURLNotAllowed, port)
+	}
+	if v.allowedHosts[strings.ToLower(host)] {
+		return crawlerDialer.DialContext(ctx, network, address)
+	}
+
+	dialer := *crawlerDialer
+	dialer.Control = func(network, resolved string, _ syscall.RawConn) error {
+		ip, _, err := net.SplitHostPort(resolved)
+		if err != nil {
+			return err
+		}
+		if parsed := net.ParseIP(ip); parsed == nil || !v.ipAllowed(parsed) {
+			return fmt.Errorf("%w: %s resolves to internal address %s", ErrURLNotAllowed, host, ip)
+		}
+		return nil
+	}
+	return dialer.DialContext(ctx, network, address)
+}
+
+// maxCrawlerRedirects is how many redirects the crawler follows, as many as
+// net/http does by default
+const maxCrawlerRedirects = 10
+
+// checkCrawlerRedirect is the CheckRedirect of the crawler's clients: it
+// refuses redirects to ports the destination validator doesn't allow, before
+// anything is sent to them
+func checkCrawlerRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxCrawlerRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxCrawlerRedirects)
+	}
+	if v := crawlerDestinations.Load(); v != nil && !v.portAllowed(req.URL.Scheme, req.URL.Port()) {
+		return fmt.Errorf("%w: redirect to port %s is not allowed", ErrURLNotAllowed, req.URL.Port())
+	}
+	return nil
+}
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
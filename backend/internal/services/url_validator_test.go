@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestURLValidator resolves hosts from a fixed table instead of DNS
+func newTestURLValidator(t *testing.T, options URLValidatorOptions, hosts map[string][]string) *URLValidator {
+	v, err := NewURLValidator(options)
+	require.NoError(t, err)
+	v.lookupIP = func(ctx context.Context, host string) ([]net.IP, error) {
+		addresses, ok := hosts[host]
+		if !ok {
+			return nil, errors.New("no such host")
+		}
+		var ips []net.IP
+		for _, address := range addresses {
+			ips = append(ips, net.ParseIP(address))
+		}
+		return ips, nil
+	}
+	return v
+}
+
+func TestURLValidator_Validate(t *testing.T) {
+	hosts := map[string][]string{
+		"example.com":      {"93.184.216.34"},
+		"intranet.local":   {"10.0.0.5"},
+		"rebind.example":   {"93.184.216.34", "127.0.0.1"},
+		"staging.internal": {"192.168.1.20"},
+		"v6.example":       {"2606:2800:220:1::1"},
+	}
+	v := newTestURLValidator(t, URLValidatorOptions{
+		AllowedHosts:    []string{"staging.internal"},
+		AllowedNetworks: []string{"172.16.5.0/24"},
+		AllowedPorts:    []int{80, 443, 8080},
+	}, hosts)
+
+	allowed := []string{
+		"https://example.com",
+		"http://example.com:8080/path",
+		"https://v6.example",
+		"https://staging.internal",
+		"http://172.16.5.9",
+		"https://93.184.216.34",
+	}
+	for _, raw := range allowed {
+		t.Run("allows "+raw, func(t *testing.T) {
+			assert.NoError(t, v.Validate(raw))
+		})
+	}
+
+	blocked := []string{
+		"http://169.254.169.254/latest/meta-data",
+		"http://127.0.0.1",
+		"http://[::1]",
+		"http://[::ffff:127.0.0.1]",
+		"http://0.0.0.0",
+		"http://100.64.0.1",
+		"http://172.16.6.1",
+		"https://intranet.local",
+		"https://rebind.example",
+		"https://unknown.example",
+		"https://example.com:22",
+		"http://[fd00::1]",
+	}
+	for _, raw := range blocked {
+		t.Run("blocks "+raw, func(t *testing.T) {
+			assert.ErrorIs(t, v.Validate(raw), ErrURLNotAllowed)
+		})
+	}
+}
+
+func TestURLValidator_ValidateContext(t *testing.T) {
+	v := newTestURLValidator(t, URLValidatorOptions{}, nil)
+	v.lookupIP = func(ctx context.Context, host string) ([]net.IP, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := v.ValidateContext(ctx, "https://example.com")
+	assert.ErrorIs(t, err, ErrURLNotAllowed)
+	assert.ErrorContains(t, err, "context canceled")
+}
+
+func TestNewURLValidator(t *testing.T) {
+	_, err := NewURLValidator(URLValidatorOptions{AllowedNetworks: []string{"not-a-cidr"}})
+	assert.Error(t, err)
+
+	v := newTestURLValidator(t, URLValidatorOptions{}, nil)
+	assert.NoError(t, v.Validate("https://1.1.1.1"))
+	assert.ErrorIs(t, v.Validate("https://1.1.1.1:8443"), ErrURLNotAllowed)
+}
+
+func TestRestrictCrawlerDestinations(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, "http://127.0.0.1:6379/", http.StatusFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	port, err := strconv.Atoi(serverURL.Port())
+	require.NoError(t, err)
+	t.Cleanup(func() { RestrictCrawlerDestinations(nil) })
+
+	get := func(target string) error {
+		resp, err := crawlerClient.Get(target)
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+
+	RestrictCrawlerDestinations(newTestURLValidator(t, URLValidatorOptions{AllowedPorts: []int{port}}, nil))
+	assert.ErrorIs(t, get(server.URL), ErrURLNotAllowed, "loopback addresses are refused")
+	assert.ErrorIs(t, get("http://localhost:"+serverURL.Port()), ErrURLNotAllowed, "and so are hosts resolving to them")
+
+	RestrictCrawlerDestinations(newTestURLValidator(t, URLValidatorOptions{AllowedPorts: []int{port}, AllowedNetworks: []string{"127.0.0.0/8"}}, nil))
+	assert.NoError(t, get(server.URL), "allowed networks are reached")
+	err = get(server.URL + "/redirect")
+	assert.ErrorIs(t, err, ErrURLNotAllowed, "redirects to other ports are refused")
+
+	RestrictCrawlerDestinations(newTestURLValidator(t, URLValidatorOptions{AllowedHosts: []string{"localhost"}, AllowedPorts: []int{port}}, nil))
+	assert.NoError(t, get("http://localhost:"+serverURL.Port()), "allowed hosts skip the address check")
+}
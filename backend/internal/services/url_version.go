@@ -0,0 +1,82 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"web-crawler-backend/internal/models"
+)
+
+// The version queries fingerprint the rows behind the URL endpoints with a
+// few aggregates, so clients polling for changes can be answered without
+// loading the rows. Links are never updated in place, so their count and
+// highest ID are enough to notice changes.
+
+// ListVersion returns a fingerprint of the URL list matching the filters.
+// It changes whenever a listed URL, any crawl or any link changes.
+func (s *URLService) ListVersion(search, status string) (string, error) {
+	var urls struct {
+		Count   int64
+		Updated sql.NullString
+	}
+	if err := s.urlListQuery(search, status).Select("COUNT(*) AS count, MAX(updated_at) AS updated").Scan(&urls).Error; err != nil {
+		return "", fmt.Errorf("failed to fingerprint URLs: %w", err)
+	}
+
+	var related struct {
+		CrawlsUpdated sql.NullString
+		LastLink      sql.NullString
+	}
+	if err := s.db.Raw(`SELECT
+		(SELECT MAX(updated_at) FROM crawls) AS crawls_updated,
+		(SELECT MAX(id) FROM links) AS last_link`).Scan(&related).Error; err != nil {
+		return "", fmt.Errorf("failed to fingerprint URLs: %w", err)
+	}
+
+	return versionString(urls.Count, urls.Updated.String, related.CrawlsUpdated.String, related.LastLink.String), nil
+}
+
+// URLVersion returns a fingerprint of everything GetURL returns for a URL:
+// the URL itself, its crawls, links and finding annotations
+func (s *URLService) URLVersion(id uint) (string, error) {
+	var url models.URL
+	if err := s.db.Select("id", "updated_at").First(&url, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return "", ErrURLNotFound
+		}
+		return "", fmt.Errorf("failed to fetch URL: %w", err)
+	}
+
+	var related struct {
+		Crawls             int64
+		CrawlsUpdated      sql.NullString
+		Links              int64
+		LastLink           sql.NullString
+		Annotations        int64
+		AnnotationsUpdated sql.NullString
+	}
+	if err := s.db.Raw(`SELECT
+		(SELECT COUNT(*) FROM crawls WHERE url_id = @id) AS crawls,
+		(SELECT MAX(updated_at) FROM crawls WHERE url_id = @id) AS crawls_updated,
+		(SELECT COUNT(*) FROM links WHERE url_id = @id) AS links,
+		(SELECT MAX(id) FROM links WHERE url_id = @id) AS last_link,
+		(SELECT COUNT(*) FROM finding_annotations WHERE url_id = @id) AS annotations,
+		(SELECT MAX(updated_at) FROM finding_annotations WHERE url_id = @id) AS annotations_updated`,
+		sql.Named("id", id)).Scan(&related).Error; err != nil {
+		return "", fmt.Errorf("failed to fingerprint URL: %w", err)
+	}
+
+	return versionString(url.UpdatedAt.UnixNano(), related.Crawls, related.CrawlsUpdated.String,
+		related.Links, related.LastLink.String, related.Annotations, related.AnnotationsUpdated.String), nil
+}
+
+func versionString(parts ...interface{}) string {
+	values := make([]string, len(parts))
+	for i, part := range parts {
+		values[i] = fmt.Sprint(part)
+	}
+	return strings.Join(values, "|")
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+
+	"web-crawler-backend/internal/models"
+)
+
+// defaultMaxCrawlDuration is how long a crawl may run when no limit is configured
+const defaultMaxCrawlDuration = 30 * time.Minute
+
+// WatchdogService finishes crawls that can no longer complete: crawls running
+// for longer than the maximum duration, and crawls left running by a restart
+type WatchdogService struct {
+	db             *gorm.DB
+	crawlerService CrawlerServiceInterface
+	maxDuration    time.Duration
+	heartbeat      Heartbeat
+}
+
+// NewWatchdogService creates a watchdog; a zero maxDuration uses the default of 30 minutes
+func NewWatchdogService(db *gorm.DB, crawlerService CrawlerServiceInterface, maxDuration time.Duration) *WatchdogService {
+	if maxDuration <= 0 {
+		maxDuration = defaultMaxCrawlDuration
+	}
+	return &WatchdogService{db: db, crawlerService: crawlerService, maxDuration: maxDuration}
+}
+
+// Heartbeat reports when the worker last ticked, for readiness checks
+func (s *WatchdogService) Heartbeat() *Heartbeat {
+	return &s.heartbeat
+}
+
+// Start times out overdue crawls every tick until the returned stop function is called
+func (s *WatchdogService) Start(tick time.Duration) (stop func()) {
+	ticker := time.NewTicker(tick)
+	s.heartbeat.start(tick, time.Now())
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case now := <-ticker.C:
+				if _, err := s.TimeoutCrawls(now); err != nil {
+					log.Printf("Crawl watchdog failed: %v", err)
+				}
+				s.heartbeat.beat(now)
+			case <-done:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+
+	return func() { close(done) }
+}
+
+// TimeoutCrawls marks crawls running for longer than the maximum duration as
+// failed, together with their URLs. It returns the number of crawls timed out.
+// A crawl that later finishes after all overwrites this with its real result.
+func (s *WatchdogService) TimeoutCrawls(now time.Time) (int, error) {
+	var crawls []models.Crawl
+	if err := s.db.Where("status = ? AND started_at < ?", "running", now.Add(-s.maxDuration)).Find(&crawls).Error; err != nil {
+		return 0, fmt.Errorf("failed to find overdue crawls: %w", err)
+	}
+
+	for _, crawl := range crawls {
+		message := fmt.Sprintf("Timed out after %s", s.maxDuration)
+		if err := s.failCrawl(crawl, message, now); err != nil {
+			return 0, err
+		}
+		if err := s.db.Model(&models.URL{}).Where("id = ? AND status = ?", crawl.URLID, "running").Update("status", "error").Error; err != nil {
+			return 0, fmt.Errorf("failed to reset URL %d: %w", crawl.URLID, err)
+		}
+		log.Printf("Crawl %d of URL %d timed out", crawl.ID, crawl.URLID)
+	}
+
+	return len(crawls), nil
+}
+
+// RecoverOnStartup must run before any crawl starts. Every crawl still running
+// was interrupted by the restart, so it is marked as failed and its URL is
+// crawled again. It returns the IDs of the re-queued URLs.
+func (s *WatchdogService) RecoverOnStartup() ([]uint, error) {
+	now := time.Now()
+
+	var crawls []models.Crawl
+	if err := s.db.Where("status = ?", "running").Find(&crawls).Error; err != nil {
+		return nil, fmt.Errorf("failed to find interrupted crawls: %w", err)
+	}
+	for _, crawl := range crawls {
+		if err := s.failCrawl(crawl, "Interrupted by a restart", now); err != nil {
+			return nil, err
+		}
+	}
+
+	var urlIDs []uint
+	if err := s.db.Model(&models.URL{}).Where("status = ?", "running").Pluck("id", &urlIDs).Error; err != nil {
+		return nil, fmt.Errorf("failed to find running URLs: %w", err)
+	}
+	if len(urlIDs) == 0 {
+		return urlIDs, nil
+	}
+
+	if err := s.db.Model(&models.URL{}).Where("id IN ?", urlIDs).Update("status", "pending").Error; err != nil {
+		return nil, fmt.Errorf("failed to re-queue URLs: %w", err)
+	}
+	if err := s.crawlerService.BulkRerunCrawls(urlIDs); err != nil {
+		return nil, fmt.Errorf("failed to restart crawls: %w", err)
+	}
+
+	log.Printf("Re-queued %d URLs interrupted by a restart", len(urlIDs))
+	return urlIDs, nil
+}
+
+func (s *WatchdogService) failCrawl(crawl models.Crawl, message string, now time.Time) error {
+	if err := s.db.Model(&crawl).Updates(map[string]interface{}{
+		"status":        "error",
+		"error_message": message,
+		"completed_at":  now,
+	}).Error; err != nil {
+		return fmt.Errorf("failed to stop crawl %d: %w", crawl.ID, err)
+	}
+	return nil
+}
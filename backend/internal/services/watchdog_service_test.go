@@ -0,0 +1,84 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"web-crawler-backend/internal/models"
+)
+
+func TestWatchdogService_TimeoutCrawls(t *testing.T) {
+	db := setupURLTestDB(t)
+	service := NewWatchdogService(db, &mockCrawlerService{}, time.Hour)
+	now := time.Now()
+	longAgo, recently := now.Add(-2*time.Hour), now.Add(-10*time.Minute)
+
+	hung := &models.URL{URL: "https://hung.example.com", Status: "running"}
+	busy := &models.URL{URL: "https://busy.example.com", Status: "running"}
+	require.NoError(t, db.Create(hung).Error)
+	require.NoError(t, db.Create(busy).Error)
+	hungCrawl := &models.Crawl{URLID: hung.ID, Status: "running", StartedAt: &longAgo}
+	busyCrawl := &models.Crawl{URLID: busy.ID, Status: "running", StartedAt: &recently}
+	finished := &models.Crawl{URLID: busy.ID, Status: "completed", StartedAt: &longAgo}
+	require.NoError(t, db.Create(hungCrawl).Error)
+	require.NoError(t, db.Create(busyCrawl).Error)
+	require.NoError(t, db.Create(finished).Error)
+
+	count, err := service.TimeoutCrawls(now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	var crawl models.Crawl
+	require.NoError(t, db.First(&crawl, hungCrawl.ID).Error)
+	assert.Equal(t, "error", crawl.Status)
+	assert.Equal(t, "Timed out after 1h0m0s", crawl.ErrorMessage)
+	assert.NotNil(t, crawl.CompletedAt)
+
+	var url models.URL
+	require.NoError(t, db.First(&url, hung.ID).Error)
+	assert.Equal(t, "error", url.Status)
+
+	var stillRunning models.Crawl
+	require.NoError(t, db.First(&stillRunning, busyCrawl.ID).Error)
+	assert.Equal(t, "running", stillRunning.Status)
+
+	var untouched models.Crawl
+	require.NoError(t, db.First(&untouched, finished.ID).Error)
+	assert.Equal(t, "completed", untouched.Status)
+}
+
+func TestWatchdogService_RecoverOnStartup(t *testing.T) {
+	db := setupURLTestDB(t)
+	crawler := &mockCrawlerService{}
+	service := NewWatchdogService(db, crawler, 0)
+	assert.Equal(t, defaultMaxCrawlDuration, service.maxDuration)
+
+	now := time.Now()
+	running := &models.URL{URL: "https://running.example.com", Status: "running"}
+	done := &models.URL{URL: "https://done.example.com", Status: "completed"}
+	require.NoError(t, db.Create(running).Error)
+	require.NoError(t, db.Create(done).Error)
+	interrupted := &models.Crawl{URLID: running.ID, Status: "running", StartedAt: &now}
+	require.NoError(t, db.Create(interrupted).Error)
+
+	requeued, err := service.RecoverOnStartup()
+	require.NoError(t, err)
+	assert.Equal(t, []uint{running.ID}, requeued)
+	assert.Equal(t, []uint{running.ID}, crawler.rerunURLIDs)
+
+	var crawl models.Crawl
+	require.NoError(t, db.First(&crawl, interrupted.ID).Error)
+	assert.Equal(t, "error", crawl.Status)
+	assert.Equal(t, "Interrupted by a restart", crawl.ErrorMessage)
+
+	var url models.URL
+	require.NoError(t, db.First(&url, running.ID).Error)
+	assert.Equal(t, "pending", url.Status)
+
+	requeued, err = service.RecoverOnStartup()
+	require.NoError(t, err)
+	assert.Empty(t, requeued)
+}
@@ -0,0 +1,67 @@
+// Package storage keeps generated files such as report bundles and crawl
+// archives, either in a local directory or in an S3-compatible bucket (AWS
+// S3, MinIO, or Google Cloud Storage through its XML API and HMAC keys).
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// ErrNotFound is returned for keys that hold no object
+var ErrNotFound = errors.New("object not found")
+
+// ErrInvalidKey is returned for keys that are empty or leave their root
+var ErrInvalidKey = errors.New("invalid object key")
+
+// Storage stores objects under slash separated keys, e.g. "url-1/crawl-2.json.gz"
+type Storage interface {
+	// Put stores data under key, replacing any object already there
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	// Get opens the object stored under key and returns its size
+	Get(ctx context.Context, key string) (io.ReadCloser, int64, error)
+	// Delete removes the object under key; missing objects are not an error
+	Delete(ctx context.Context, key string) error
+}
+
+// Backends
+const (
+	BackendLocal = "local"
+	BackendS3    = "s3"
+)
+
+// Config selects and configures the storage backend
+type Config struct {
+	// Backend is "local" or "s3"
+	Backend string
+	S3      S3Options
+}
+
+// Open returns the storage for one kind of file. The local backend keeps
+// them in dir; the S3 backend uses dir, without leading dots and slashes,
+// as a key prefix in the bucket so every kind of file gets its own folder.
+func Open(cfg Config, dir string) (Storage, error) {
+	switch cfg.Backend {
+	case "", BackendLocal:
+		return NewLocal(dir), nil
+	case BackendS3:
+		opts := cfg.S3
+		opts.Prefix = strings.Trim(path.Clean("/"+strings.TrimLeft(dir, ".")), "/")
+		return NewS3(opts)
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q; use local or s3", cfg.Backend)
+	}
+}
+
+// cleanKey validates a key and returns it without redundant slashes and dots
+func cleanKey(key string) (string, error) {
+	cleaned := path.Clean("/" + key)[1:]
+	if key == "" || cleaned == "" || strings.Contains(key, "\\") || strings.Contains(key, "..") {
+		return "", fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+	return cleaned, nil
+}
@@ -0,0 +1,119 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestOTLPExporter_ExportSpans(t *testing.T) {
+	var received map[string]any
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/traces", r.URL.Path)
+		header = r.Header
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+	}))
+	defer server.Close()
+
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", server.URL+"/")
+	t.Setenv("OTEL_EXPORTER_OTLP_HEADERS", "x-api-key=secret%20key")
+	exporter, err := newOTLPExporterFromEnv()
+	require.NoError(t, err)
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSyncer(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", "test"))),
+	)
+	_, span := provider.Tracer("test-scope").Start(context.Background(), "request")
+	span.End()
+	require.NoError(t, provider.Shutdown(context.Background()))
+
+	assert.Equal(t, "application/json", header.Get("Content-Type"))
+	assert.Equal(t, "secret key", header.Get("X-Api-Key"))
+
+	resourceSpans := received["resourceSpans"].([]any)[0].(map[string]any)
+	assert.Equal(t, "service.name", resourceSpans["resource"].(map[string]any)["attributes"].([]any)[0].(map[string]any)["key"])
+	scopeSpans := resourceSpans["scopeSpans"].([]any)[0].(map[string]any)
+	assert.Equal(t, "test-scope", scopeSpans["scope"].(map[string]any)["name"])
+
+	encoded := scopeSpans["spans"].([]any)[0].(map[string]any)
+	assert.Equal(t, "request", encoded["name"])
+	assert.Len(t, encoded["traceId"], 32)
+	assert.Len(t, encoded["spanId"], 16)
+	assert.NotContains(t, encoded, "parentSpanId")
+}
+
+func TestEncodeSpan(t *testing.T) {
+	exporter := &capturingExporter{}
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	tracer := provider.Tracer("test-scope")
+	ctx, parent := tracer.Start(context.Background(), "parent")
+	_, child := tracer.Start(ctx, "child")
+	child.SetAttributes(attribute.Int64("rows", 3), attribute.StringSlice("tags", []string{"a", "b"}))
+	child.RecordError(errors.New("boom"))
+	child.SetStatus(codes.Error, "boom")
+	child.End()
+	parent.End()
+
+	require.Len(t, exporter.spans, 2)
+	encoded := encodeSpan(exporter.spans[0])
+	assert.Equal(t, "child", encoded.Name)
+	assert.Equal(t, exporter.spans[1].SpanContext().SpanID().String(), encoded.ParentSpanID)
+	assert.Equal(t, otlpStatusError, encoded.Status.Code)
+	assert.Equal(t, "boom", encoded.Status.Message)
+	require.Len(t, encoded.Events, 1)
+	assert.Equal(t, "exception", encoded.Events[0].Name)
+
+	body, err := json.Marshal(encoded.Attributes)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		{"key":"rows","value":{"intValue":"3"}},
+		{"key":"tags","value":{"arrayValue":{"values":[{"stringValue":"a"},{"stringValue":"b"}]}}}
+	]`, string(body))
+}
+
+func TestNewOTLPExporterFromEnv(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://collector:4318")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+	exporter, err := newOTLPExporterFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, "http://collector:4318/v1/traces", exporter.endpoint)
+
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "https://traces.example/custom")
+	exporter, err = newOTLPExporterFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, "https://traces.example/custom", exporter.endpoint)
+
+	t.Setenv("OTEL_EXPORTER_OTLP_HEADERS", "broken")
+	_, err = newOTLPExporterFromEnv()
+	assert.Error(t, err)
+
+	t.Setenv("OTEL_EXPORTER_OTLP_HEADERS", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_TIMEOUT", "soon")
+	_, err = newOTLPExporterFromEnv()
+	assert.Error(t, err)
+}
+
+type capturingExporter struct {
+	spans []sdktrace.ReadOnlySpan
+}
+
+func (e *capturingExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.spans = append(e.spans, spans...)
+	return nil
+}
+
+func (e *capturingExporter) Shutdown(ctx context.Context) error {
+	return nil
+}
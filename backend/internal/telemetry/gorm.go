@@ -0,0 +1,100 @@
+package telemetry
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// gormSpanKey stores the span of a running statement in its instance settings
+const gormSpanKey = "telemetry:span"
+
+// GormPlugin creates a span for every database statement. Statements run on a
+// *gorm.DB carrying a context (db.WithContext) become children of its span.
+type GormPlugin struct{}
+
+// Name implements gorm.Plugin
+func (GormPlugin) Name() string {
+	return "telemetry"
+}
+
+// Initialize implements gorm.Plugin
+func (p GormPlugin) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	if err := callbacks.Create().Before("gorm:create").Register("telemetry:before_create", startSpan("create")); err != nil {
+		return err
+	}
+	if err := callbacks.Create().After("gorm:create").Register("telemetry:after_create", endSpan); err != nil {
+		return err
+	}
+	if err := callbacks.Query().Before("gorm:query").Register("telemetry:before_query", startSpan("select")); err != nil {
+		return err
+	}
+	if err := callbacks.Query().After("gorm:query").Register("telemetry:after_query", endSpan); err != nil {
+		return err
+	}
+	if err := callbacks.Update().Before("gorm:update").Register("telemetry:before_update", startSpan("update")); err != nil {
+		return err
+	}
+	if err := callbacks.Update().After("gorm:update").Register("telemetry:after_update", endSpan); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().Before("gorm:delete").Register("telemetry:before_delete", startSpan("delete")); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().After("gorm:delete").Register("telemetry:after_delete", endSpan); err != nil {
+		return err
+	}
+	if err := callbacks.Row().Before("gorm:row").Register("telemetry:before_row", startSpan("row")); err != nil {
+		return err
+	}
+	if err := callbacks.Row().After("gorm:row").Register("telemetry:after_row", endSpan); err != nil {
+		return err
+	}
+	if err := callbacks.Raw().Before("gorm:raw").Register("telemetry:before_raw", startSpan("raw")); err != nil {
+		return err
+	}
+	return callbacks.Raw().After("gorm:raw").Register("telemetry:after_raw", endSpan)
+}
+
+func startSpan(operation string) func(*gorm.DB) {
+	return func(tx *gorm.DB) {
+		name := "db." + operation
+		if tx.Statement.Table != "" {
+			name += " " + tx.Statement.Table
+		}
+
+		_, span := Tracer().Start(tx.Statement.Context, name,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				semconv.DBSystemKey.String(tx.Dialector.Name()),
+				semconv.DBOperation(operation),
+				semconv.DBSQLTable(tx.Statement.Table),
+			),
+		)
+		tx.InstanceSet(gormSpanKey, span)
+	}
+}
+
+func endSpan(tx *gorm.DB) {
+	value, ok := tx.InstanceGet(gormSpanKey)
+	if !ok {
+		return
+	}
+	span, ok := value.(trace.Span)
+	if !ok {
+		return
+	}
+
+	span.SetAttributes(
+		semconv.DBStatement(tx.Statement.SQL.String()),
+		attribute.Int64("db.rows_affected", tx.Statement.RowsAffected),
+	)
+	if tx.Error != nil && tx.Error != gorm.ErrRecordNotFound {
+		span.RecordError(tx.Error)
+		span.SetStatus(codes.Error, tx.Error.Error())
+	}
+	span.End()
+}
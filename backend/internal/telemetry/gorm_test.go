@@ -0,0 +1,52 @@
+package telemetry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type tracedRecord struct {
+	ID   uint
+	Name string
+}
+
+func TestGormPlugin(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&tracedRecord{}))
+	require.NoError(t, db.Use(GormPlugin{}))
+
+	ctx, parent := Tracer().Start(context.Background(), "request")
+	require.NoError(t, db.WithContext(ctx).Create(&tracedRecord{Name: "a"}).Error)
+	var found tracedRecord
+	require.NoError(t, db.WithContext(ctx).First(&found).Error)
+	assert.Error(t, db.WithContext(ctx).Where("id = ?", 99).First(&found).Error)
+	parent.End()
+
+	var names []string
+	for _, span := range recorder.Ended() {
+		if span.Name() == "request" {
+			continue
+		}
+		names = append(names, span.Name())
+		assert.Equal(t, parent.SpanContext().TraceID(), span.Parent().TraceID(), span.Name())
+		assert.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID(), span.Name())
+		assert.Empty(t, span.Events(), "a missing record is not an error")
+	}
+	assert.Equal(t, []string{"db.create traced_records", "db.select traced_records", "db.select traced_records"}, names)
+}
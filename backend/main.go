@@ -1,20 +1,44 @@
 package main
 
 import (
+	"context"
 	"log"
+	"net"
 	"os"
+	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 
+	"web-crawler-backend/internal/authcookie"
 	"web-crawler-backend/internal/config"
 	"web-crawler-backend/internal/database"
+	"web-crawler-backend/internal/grpcapi"
 	"web-crawler-backend/internal/handlers"
+	"web-crawler-backend/internal/googlesheets"
+	"web-crawler-backend/internal/issuetracker"
 	"web-crawler-backend/internal/middleware"
+	"web-crawler-backend/internal/models"
+	"web-crawler-backend/internal/password"
+	"web-crawler-backend/internal/server"
 	"web-crawler-backend/internal/services"
+	"web-crawler-backend/internal/storage"
+	"web-crawler-backend/internal/telemetry"
 )
 
+//go:generate swag init --parseInternal --output docs
+
+// @title Web Crawler API
+// @version 1.0
+// @description Crawls websites and reports on their links, SEO, accessibility and security.
+// @BasePath /api/v1
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name Authorization
+// @description Bearer followed by a JWT from /auth/login, or by an API token from /tokens
 func main() {
 	// Load environment variables
 	if err := godotenv.Load(); err != nil {
@@ -22,37 +46,259 @@ func main() {
 	}
 
 	// Initialize configuration
-	cfg := config.Load()
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Invalid configuration:\n%v", err)
+	}
+
+	// Initialize tracing; it stays disabled without an OTLP endpoint
+	shutdownTracing, err := telemetry.Setup(context.Background(), "web-crawler-backend")
+	if err != nil {
+		log.Fatal("Failed to initialize tracing:", err)
+	}
+	defer shutdownTracing(context.Background())
 
 	// Initialize database
-	db, err := database.Initialize(cfg.DatabaseURL)
+	db, err := database.Initialize(cfg.DBDriver, cfg.DatabaseURL)
 	if err != nil {
 		log.Fatal("Failed to connect to database:", err)
 	}
+	if err := db.Use(telemetry.GormPlugin{}); err != nil {
+		log.Fatal("Failed to instrument database:", err)
+	}
 
 	// Run migrations (use GORM AutoMigrate for development, file-based for production)
 	if cfg.Environment == "production" {
-		if err := database.RunMigrationsWithFiles(cfg.DatabaseURL); err != nil {
-			log.Printf("File-based migrations failed, falling back to AutoMigrate: %v", err)
-			if err := database.RunMigrations(cfg.DatabaseURL); err != nil {
-				log.Fatal("Failed to run migrations:", err)
-			}
+		// A failed migration leaves the schema dirty; AutoMigrate on top of
+		// it would hide the failure, so it has to be fixed first
+		if err := database.RunMigrationsWithFiles(cfg.DBDriver, cfg.DatabaseURL); err != nil {
+			log.Fatal("Failed to run migrations:", err)
 		}
 	} else {
-		if err := database.RunMigrations(cfg.DatabaseURL); err != nil {
+		if err := database.RunMigrations(cfg.DBDriver, cfg.DatabaseURL); err != nil {
 			log.Fatal("Failed to run migrations:", err)
 		}
 	}
 
+	// Redis backs the cache and the crawl queue; both are optional
+	var redisClient *redis.Client
+	if cfg.RedisURL != "" {
+		redisClient, err = services.NewRedisClient(cfg.RedisURL)
+		if err != nil {
+			log.Printf("Caching disabled: %v", err)
+		} else {
+			defer redisClient.Close()
+		}
+	}
+
+	// Cache hot reads; writes through db invalidate the cache
+	var cache *services.CacheService
+	if redisClient != nil {
+		cache = services.NewCacheService(redisClient, cfg.CacheTTL)
+		if err := db.Use(cache.Plugin()); err != nil {
+			log.Fatal("Failed to set up cache invalidation:", err)
+		}
+	}
+
+	// Hand crawls to the queue so every replica and worker process shares them
+	var crawlQueue *services.CrawlQueue
+	if cfg.CrawlQueue {
+		if redisClient == nil {
+			log.Fatal("The crawl queue requires Redis, set REDIS_URL")
+		}
+		crawlQueue = services.NewCrawlQueue(redisClient, services.CrawlQueueOptions{MaxRetries: cfg.CrawlQueueMaxRetries})
+	}
+
 	// Initialize services
-	authService := services.NewAuthService(db)
-	crawlerService := services.NewCrawlerService(db)
-	urlService := services.NewURLService(db, crawlerService)
+	passwords, err := password.New(cfg.Passwords())
+	if err != nil {
+		log.Fatal("Invalid password hashing settings:", err)
+	}
+	apiTokenService := services.NewAPITokenService(db)
+	authService := services.NewAuthService(db).WithPasswords(passwords).WithAPITokens(apiTokenService).WithOptions(services.AuthOptions{
+		Secret:        cfg.JWTSecret,
+		TokenLifetime: cfg.JWTTokenLifetime,
+		Issuer:        cfg.JWTIssuer,
+		ClockSkew:     cfg.JWTClockSkew,
+	})
+	tokenGuard := services.NewTokenGuard(db, services.TokenGuardOptions{
+		MaxFailures: cfg.TokenGuardMaxFailures,
+		Window:      cfg.TokenGuardWindow,
+		BlockFor:    cfg.TokenGuardBlock,
+	})
+	quotaService := services.NewQuotaService(db, models.QuotaLimits{
+		MaxURLs:         cfg.QuotaMaxURLs,
+		MaxCrawlsPerDay: cfg.QuotaMaxCrawlsPerDay,
+		MaxPages:        cfg.QuotaMaxPages,
+	})
+	organizationService := services.NewOrganizationService(db)
+	featureFlagService := services.NewFeatureFlagService(db, cfg.FeatureFlags)
+	var crawlSnapshots storage.Storage
+	if cfg.CrawlSnapshotDir != "" {
+		if crawlSnapshots, err = storage.Open(cfg.Storage(), cfg.CrawlSnapshotDir); err != nil {
+			log.Fatal("Invalid storage settings:", err)
+		}
+	}
+	crawlerService := services.NewCrawlerServiceWithOptions(db, services.CrawlerOptions{
+		MaxConcurrency:   cfg.CrawlConcurrency,
+		MaxHostQPS:       cfg.CrawlHostQPS,
+		MaxPages:         cfg.CrawlMaxPages,
+		MaxResponseBytes: int64(cfg.CrawlMaxResponseBytes),
+		MaxRetries:       cfg.CrawlMaxRetries,
+		RetryBaseDelay:   cfg.CrawlRetryBaseDelay,
+		RetryMaxDelay:    cfg.CrawlRetryMaxDelay,
+		InsertBatchSize:  cfg.CrawlInsertBatchSize,
+	}).WithCache(cache).WithQueue(crawlQueue).WithQuota(quotaService).WithSnapshots(crawlSnapshots).
+		WithLinkCheckCache(services.NewLinkCheckCache(redisClient, cfg.LinkCheckCacheTTL)).WithFeatureFlags(featureFlagService)
+	urlValidator, err := services.NewURLValidator(services.URLValidatorOptions{
+		AllowedHosts:    cfg.CrawlAllowedHosts,
+		AllowedNetworks: cfg.CrawlAllowedNetworks,
+		AllowedPorts:    cfg.CrawlAllowedPorts,
+	})
+	if err != nil {
+		log.Fatal("Invalid crawl destination settings:", err)
+	}
+	services.RestrictCrawlerDestinations(urlValidator)
+	urlService := services.NewURLServiceWithValidator(db, crawlerService, urlValidator).WithCache(cache)
+	reportStorage, err := storage.Open(cfg.Storage(), cfg.ReportsDir)
+	if err != nil {
+		log.Fatal("Invalid storage settings:", err)
+	}
+	reportService := services.NewReportService(db, reportStorage)
+	onboardingService := services.NewOnboardingService(db, urlService, cfg.OnboardingSampleURL)
+	schedulerService := services.NewSchedulerService(db, crawlerService)
+	monitorService := services.NewMonitorService(db, cfg.MonitorHistory)
+	sitemapService := services.NewSitemapService(db, urlService, urlValidator).WithQuota(quotaService)
+	activityService := services.NewActivityService(db)
+	annotationService := services.NewAnnotationService(db)
+	issueTrackers, err := issuetracker.Open(cfg.IssueTrackers())
+	if err != nil {
+		log.Fatal("Invalid issue tracker settings:", err)
+	}
+	issueService := services.NewIssueService(db, issueTrackers)
+	var googleClient *googlesheets.Client
+	if cfg.GoogleClientID != "" {
+		if googleClient, err = googlesheets.New(cfg.GoogleSheets()); err != nil {
+			log.Fatal("Invalid Google settings:", err)
+		}
+	}
+	sheetExportService := services.NewSheetExportService(db, googleClient, cfg.JWTSecret)
+	extractionRuleService := services.NewExtractionRuleService(db)
+	watchdogService := services.NewWatchdogService(db, crawlerService, cfg.CrawlMaxDuration)
+	idempotencyService := services.NewIdempotencyService(db, cfg.IdempotencyKeyTTL)
+	trashService := services.NewTrashService(db, cfg.TrashRetention)
+	shareService := services.NewShareService(db, urlService, crawlerService, services.ShareOptions{
+		Secret: cfg.ShareSecret,
+		TTL:    cfg.ShareTTL,
+		MaxTTL: cfg.ShareMaxTTL,
+	})
+	var crawlArchive storage.Storage
+	if cfg.CrawlArchiveDir != "" {
+		if crawlArchive, err = storage.Open(cfg.Storage(), cfg.CrawlArchiveDir); err != nil {
+			log.Fatal("Invalid storage settings:", err)
+		}
+	}
+	retentionService := services.NewRetentionService(db, services.RetentionOptions{
+		KeepCrawls: cfg.CrawlRetentionKeep,
+		Archive:    crawlArchive,
+		Snapshots:  crawlSnapshots,
+	})
+	healthService := services.NewHealthService(db, database.MigrationsDir(cfg.DBDriver))
+	healthService.AddWorker("scheduler", schedulerService.Heartbeat())
+	healthService.AddWorker("watchdog", watchdogService.Heartbeat())
+	healthService.AddWorker("monitor", monitorService.Heartbeat())
+	healthService.AddWorker("sitemaps", sitemapService.Heartbeat())
+	healthService.AddWorker("sheet_exports", sheetExportService.Heartbeat())
+
+	// Recover crawls interrupted by the last shutdown, then watch for hung
+	// crawls. Queued crawls may be running on workers, and the queue reruns
+	// those of workers that died by itself.
+	if crawlQueue == nil {
+		if _, err := watchdogService.RecoverOnStartup(); err != nil {
+			log.Printf("Failed to recover interrupted crawls: %v", err)
+		}
+	}
+	stopWatchdog := watchdogService.Start(time.Minute)
+	defer stopWatchdog()
+
+	// Start scheduled crawls
+	stopScheduler := schedulerService.Start(time.Minute)
+	defer stopScheduler()
+
+	// Run uptime checks of monitored URLs
+	stopMonitor := monitorService.Start(time.Minute)
+	defer stopMonitor()
+
+	// Sync imported sitemaps, adding new pages and flagging removed ones
+	stopSitemapSync := sitemapService.Start(time.Minute)
+	defer stopSitemapSync()
+
+	// Write scheduled Google Sheets exports
+	stopSheetExports := sheetExportService.Start(time.Minute)
+	defer stopSheetExports()
+
+	// Purge expired idempotency keys
+	stopIdempotencyPurge := idempotencyService.Start(time.Hour)
+	defer stopIdempotencyPurge()
+
+	// Purge URLs that have been in the trash for longer than the retention period
+	stopTrashPurge := trashService.Start(time.Hour)
+	defer stopTrashPurge()
+
+	// Run queued crawls
+	if crawlQueue != nil && cfg.CrawlQueueWorkers > 0 {
+		stopCrawlQueue := crawlQueue.Start(crawlerService, cfg.CrawlQueueWorkers)
+		defer stopCrawlQueue()
+		healthService.AddWorker("crawl_queue", crawlQueue.Heartbeat())
+	}
+
+	// Archive and delete crawls beyond the retention limit
+	stopRetention := retentionService.Start(time.Hour)
+	defer stopRetention()
+
+	// Start the gRPC server for internal services
+	if cfg.GRPCPort != "" {
+		listener, err := net.Listen("tcp", ":"+cfg.GRPCPort)
+		if err != nil {
+			log.Fatal("Failed to listen for gRPC:", err)
+		}
+		grpcServer := grpcapi.NewGRPCServer(grpcapi.NewServer(urlService, crawlerService), authService)
+		go func() {
+			log.Printf("gRPC server starting on port %s", cfg.GRPCPort)
+			if err := grpcServer.Serve(listener); err != nil {
+				log.Printf("gRPC server stopped: %v", err)
+			}
+		}()
+		defer grpcServer.GracefulStop()
+	}
 
 	// Initialize handlers
-	authHandler := handlers.NewAuthHandler(authService)
-	urlHandler := handlers.NewURLHandler(urlService)
-	crawlHandler := handlers.NewCrawlHandler(crawlerService)
+	cookies := cfg.Cookies()
+	authHandler := handlers.NewAuthHandler(authService, onboardingService, cookies)
+	urlHandler := handlers.NewURLHandler(urlService, quotaService, featureFlagService)
+	crawlHandler := handlers.NewCrawlHandler(crawlerService, quotaService, organizationService)
+	reportHandler := handlers.NewReportHandler(reportService)
+	onboardingHandler := handlers.NewOnboardingHandler(onboardingService)
+	scheduleHandler := handlers.NewScheduleHandler(schedulerService)
+	monitorHandler := handlers.NewMonitorHandler(monitorService)
+	activityHandler := handlers.NewActivityHandler(activityService)
+	annotationHandler := handlers.NewAnnotationHandler(annotationService)
+	issueHandler := handlers.NewIssueHandler(issueService)
+	extractionRuleHandler := handlers.NewExtractionRuleHandler(extractionRuleService)
+	healthHandler := handlers.NewHealthHandler(healthService)
+	trashHandler := handlers.NewTrashHandler(trashService)
+	queueHandler := handlers.NewQueueHandler(crawlQueue)
+	featureFlagHandler := handlers.NewFeatureFlagHandler(featureFlagService)
+	requestMetrics := services.NewRequestMetrics()
+	adminHandler := handlers.NewAdminHandler(services.NewSystemStatusService(db, crawlerService, crawlQueue), requestMetrics, tokenGuard)
+	quotaHandler := handlers.NewQuotaHandler(quotaService)
+	organizationHandler := handlers.NewOrganizationHandler(organizationService)
+	apiTokenHandler := handlers.NewAPITokenHandler(apiTokenService)
+	sitemapHandler := handlers.NewSitemapHandler(sitemapService)
+	sheetExportHandler := handlers.NewSheetExportHandler(sheetExportService, cfg.AuthCookieSecure)
+	brokenLinkThresholdHandler := handlers.NewBrokenLinkThresholdHandler(services.NewBrokenLinkAlertService(db))
+	environmentHandler := handlers.NewEnvironmentHandler(services.NewEnvironmentService(db, crawlerService), quotaService)
+	shareHandler := handlers.NewShareHandler(shareService)
 
 	// Setup Gin router
 	if cfg.Environment == "production" {
@@ -60,20 +306,48 @@ func main() {
 	}
 
 	router := gin.Default()
+	// Client IPs key the per-IP rate limits and the token guard, so
+	// X-Forwarded-For is only believed from the configured proxies
+	if err := router.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		log.Fatal("Invalid trusted proxies:", err)
+	}
 
 	// Setup CORS
 	corsConfig := cors.DefaultConfig()
 	corsConfig.AllowOrigins = []string{"http://localhost:3000", "http://localhost:5173"}
-	corsConfig.AllowHeaders = []string{"Origin", "Content-Length", "Content-Type", "Authorization"}
+	corsConfig.AllowHeaders = []string{"Origin", "Content-Length", "Content-Type", "Authorization", "Idempotency-Key", "X-Organization-ID", "X-Request-ID", authcookie.CSRFHeader}
+	corsConfig.ExposeHeaders = []string{"X-Request-ID"}
+	// Browsers only send the auth cookie cross-origin with credentials allowed
+	corsConfig.AllowCredentials = cookies != nil
 	corsConfig.AllowMethods = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}
 	router.Use(cors.New(corsConfig))
 
 	// Setup middleware
+	router.Use(otelgin.Middleware("web-crawler-backend"))
+	router.Use(middleware.RequestID())
 	router.Use(middleware.Logger())
+	router.Use(middleware.RecordRequestMetrics(requestMetrics))
+	router.Use(middleware.Compress(cfg.CompressMinBytes))
 	router.Use(middleware.ErrorHandler())
+	router.Use(middleware.BodyLimit(int64(cfg.MaxRequestBodyBytes)))
+	router.Use(middleware.Timeout(cfg.RequestTimeout))
+
+	// Setup rate limits
+	limiters := rateLimiters{
+		public: middleware.NewRateLimiter(cfg.RateLimitPublic, cfg.RateLimitWindow),
+		user:   middleware.NewRateLimiter(cfg.RateLimitUser, cfg.RateLimitWindow),
+		crawl:  middleware.NewRateLimiter(cfg.RateLimitCrawl, cfg.RateLimitWindow),
+		token:  middleware.NewRateLimiter(cfg.RateLimitToken, cfg.RateLimitWindow),
+		guard:  tokenGuard,
+	}
 
 	// Setup routes
-	setupRoutes(router, authHandler, authService, urlHandler, crawlHandler)
+	router.GET("/healthz", healthHandler.Liveness)
+	router.GET("/readyz", healthHandler.Readiness)
+	if cfg.SwaggerUI {
+		router.GET("/swagger/*any", handlers.SwaggerUI("/api/v1"))
+	}
+	setupRoutes(router, limiters, cookies, authHandler, authService, idempotencyService, urlHandler, crawlHandler, reportHandler, onboardingHandler, scheduleHandler, monitorHandler, activityHandler, annotationHandler, issueHandler, extractionRuleHandler, trashHandler, queueHandler, adminHandler, featureFlagHandler, quotaHandler, organizationService, organizationHandler, shareHandler, apiTokenHandler, environmentHandler, sitemapHandler, brokenLinkThresholdHandler, sheetExportHandler)
 
 	// Start server
 	port := os.Getenv("PORT")
@@ -81,51 +355,273 @@ func main() {
 		port = "8080"
 	}
 
-	log.Printf("Server starting on port %s", port)
-	if err := router.Run(":" + port); err != nil {
+	tlsOptions := cfg.TLS()
+	if tlsOptions.Enabled() {
+		log.Printf("Server starting on port %s with TLS", port)
+	} else {
+		log.Printf("Server starting on port %s", port)
+	}
+	if err := server.ListenAndServe(":"+port, router, tlsOptions); err != nil {
 		log.Fatal("Failed to start server:", err)
 	}
 }
 
-func setupRoutes(router *gin.Engine, authHandler *handlers.AuthHandler, authService *services.AuthService, urlHandler *handlers.URLHandler, crawlHandler *handlers.CrawlHandler) {
+// rateLimiters holds the request budgets of the API: per IP on public
+// endpoints, per user on protected ones, a stricter one for crawls, and one
+// per IP on the token endpoints, whose invalid tokens guard also watches
+type rateLimiters struct {
+	public *middleware.RateLimiter
+	user   *middleware.RateLimiter
+	crawl  *middleware.RateLimiter
+	token  *middleware.RateLimiter
+	guard  *services.TokenGuard
+}
+
+func setupRoutes(router *gin.Engine, limiters rateLimiters, cookies *authcookie.Options, authHandler *handlers.AuthHandler, authService *services.AuthService, idempotencyService *services.IdempotencyService, urlHandler *handlers.URLHandler, crawlHandler *handlers.CrawlHandler, reportHandler *handlers.ReportHandler, onboardingHandler *handlers.OnboardingHandler, scheduleHandler *handlers.ScheduleHandler, monitorHandler *handlers.MonitorHandler, activityHandler *handlers.ActivityHandler, annotationHandler *handlers.AnnotationHandler, issueHandler *handlers.IssueHandler, extractionRuleHandler *handlers.ExtractionRuleHandler, trashHandler *handlers.TrashHandler, queueHandler *handlers.QueueHandler, adminHandler *handlers.AdminHandler, featureFlagHandler *handlers.FeatureFlagHandler, quotaHandler *handlers.QuotaHandler, organizationService *services.OrganizationService, organizationHandler *handlers.OrganizationHandler, shareHandler *handlers.ShareHandler, apiTokenHandler *handlers.APITokenHandler, environmentHandler *handlers.EnvironmentHandler, sitemapHandler *handlers.SitemapHandler, brokenLinkThresholdHandler *handlers.BrokenLinkThresholdHandler, sheetExportHandler *handlers.SheetExportHandler) {
+	userLimit := middleware.RateLimitByUser(limiters.user)
+	idempotent := middleware.Idempotency(idempotencyService)
+	orgScope := middleware.OrganizationScope(organizationService)
+	urlInScope := middleware.URLInScope(organizationService)
+	orgAdmin := middleware.OrgRoleRequired(models.OrgRoleAdmin)
+	// Scopes API tokens need on each route group, to read and to make changes
+	readOrCrawl := middleware.TokenScope(models.APIScopeRead, models.APIScopeCrawl)
+	readOrAdmin := middleware.TokenScope(models.APIScopeRead, models.APIScopeAdmin)
+	readOnly := middleware.TokenScope(models.APIScopeRead, models.APIScopeRead)
+	adminScope := middleware.TokenScope(models.APIScopeAdmin, models.APIScopeAdmin)
+	userTokens := middleware.UserTokensOnly()
+	tokenLimit := []gin.HandlerFunc{middleware.RateLimitByIP(limiters.token), middleware.GuardTokens(limiters.guard)}
+
 	api := router.Group("/api/v1")
+	// Clients with their own Authorization header, like API clients, can't
+	// be made to send requests by another site and need no CSRF token
+	api.Use(middleware.CookieAuth(cookies), middleware.CSRF(cookies, middleware.HeaderAuthenticated))
 	{
 		// Health check
 		api.GET("/health", func(c *gin.Context) {
 			c.JSON(200, gin.H{"status": "ok"})
 		})
 
+		// API documentation
+		api.GET("/openapi.json", handlers.OpenAPISpec)
+
 		// Auth endpoints (public)
 		auth := api.Group("/auth")
+		auth.Use(middleware.RateLimitByIP(limiters.public))
 		{
 			auth.POST("/register", authHandler.Register)
 			auth.POST("/login", authHandler.Login)
-			auth.POST("/refresh", authHandler.RefreshToken)
+			auth.POST("/refresh", append(tokenLimit, authHandler.RefreshToken)...)
+			auth.GET("/csrf", authHandler.GetCSRFToken)
 			// Protected auth endpoints
 			auth.GET("/profile", middleware.AuthRequired(authService), authHandler.GetProfile)
-			auth.POST("/logout", middleware.AuthRequired(authService), authHandler.Logout)
-			auth.GET("/validate", middleware.AuthRequired(authService), authHandler.ValidateToken)
+			auth.POST("/logout", middleware.AuthRequired(authService), adminScope, authHandler.Logout)
+			auth.GET("/validate", append(tokenLimit, middleware.AuthRequired(authService), authHandler.ValidateToken)...)
+			auth.GET("/sessions", middleware.AuthRequired(authService), adminScope, authHandler.GetSessions)
+			auth.DELETE("/sessions/:id", middleware.AuthRequired(authService), adminScope, authHandler.RevokeSession)
 		}
 
+		// Shared reports (public)
+		api.GET("/public/reports/:token", middleware.RateLimitByIP(limiters.public), shareHandler.GetSharedReport)
+
 		// URL endpoints (protected)
 		urls := api.Group("/urls")
-		urls.Use(middleware.AuthRequired(authService))
+		urls.Use(middleware.AuthRequired(authService), readOrAdmin, userLimit, orgScope, urlInScope)
 		{
 			urls.GET("", urlHandler.GetURLs)
-			urls.POST("", urlHandler.CreateURL)
+			urls.POST("", idempotent, urlHandler.CreateURL)
+			urls.GET("/trash", trashHandler.ListTrash)
 			urls.GET("/:id", urlHandler.GetURL)
 			urls.GET("/:id/links", urlHandler.GetURLLinks)
-			urls.DELETE("/:id", urlHandler.DeleteURL)
-			urls.POST("/bulk-delete", urlHandler.BulkDeleteURLs)
+			urls.GET("/:id/images", urlHandler.GetURLImages)
+			urls.GET("/:id/forms", urlHandler.GetURLForms)
+			urls.GET("/:id/structure", urlHandler.GetStructureReport)
+			urls.GET("/:id/graph", urlHandler.GetLinkGraph)
+			urls.GET("/:id/duplicates", urlHandler.GetDuplicates)
+			urls.GET("/:id/seo", urlHandler.GetSEOReport)
+			urls.GET("/:id/security", urlHandler.GetSecurityReport)
+			urls.GET("/:id/accessibility", urlHandler.GetAccessibilityReport)
+			urls.GET("/:id/mixed-content", urlHandler.GetMixedContentReport)
+			urls.GET("/:id/changes", urlHandler.GetContentChanges)
+			urls.GET("/:id/trends", urlHandler.GetCrawlTrends)
+			urls.GET("/:id/crawls/:crawl_a/diff/:crawl_b", urlHandler.GetCrawlDiff)
+			urls.PUT("/:id/crawl-settings", urlHandler.UpdateCrawlSettings)
+			urls.GET("/:id/schedule", scheduleHandler.GetSchedule)
+			urls.PUT("/:id/schedule", scheduleHandler.SetSchedule)
+			urls.DELETE("/:id/schedule", scheduleHandler.DeleteSchedule)
+			urls.GET("/:id/monitor", monitorHandler.GetMonitor)
+			urls.PUT("/:id/monitor", monitorHandler.SetMonitor)
+			urls.DELETE("/:id/monitor", monitorHandler.DeleteMonitor)
+			urls.GET("/:id/monitor/checks", monitorHandler.ListChecks)
+			urls.GET("/:id/availability", monitorHandler.GetAvailability)
+			urls.GET("/:id/broken-link-threshold", brokenLinkThresholdHandler.GetURLThreshold)
+			urls.PUT("/:id/broken-link-threshold", brokenLinkThresholdHandler.SetURLThreshold)
+			urls.DELETE("/:id/broken-link-threshold", brokenLinkThresholdHandler.DeleteURLThreshold)
+			urls.GET("/:id/annotations", annotationHandler.ListAnnotations)
+			urls.POST("/:id/annotations", annotationHandler.Annotate)
+			urls.DELETE("/:id/annotations/:annotation_id", annotationHandler.DeleteAnnotation)
+			urls.GET("/:id/issues", issueHandler.ListIssues)
+			urls.POST("/:id/issues", issueHandler.CreateIssue)
+			urls.GET("/:id/extraction-rules", extractionRuleHandler.ListRules)
+			urls.POST("/:id/extraction-rules", extractionRuleHandler.CreateRule)
+			urls.PUT("/:id/extraction-rules/:rule_id", extractionRuleHandler.UpdateRule)
+			urls.DELETE("/:id/extraction-rules/:rule_id", extractionRuleHandler.DeleteRule)
+			urls.PUT("/:id/login-form", urlHandler.SetLoginFormOverride)
+			urls.POST("/:id/recheck-links", middleware.RateLimitByUser(limiters.crawl), crawlHandler.RecheckBrokenLinks)
+			urls.POST("/:id/share", shareHandler.CreateShare)
+			urls.DELETE("/:id", orgAdmin, urlHandler.DeleteURL)
+			urls.POST("/:id/restore", orgAdmin, trashHandler.RestoreURL)
+			urls.DELETE("/:id/purge", orgAdmin, trashHandler.PurgeURL)
+			urls.POST("/bulk-delete", orgAdmin, idempotent, urlHandler.BulkDeleteURLs)
 		}
 
 		// Crawl endpoints (protected)
 		crawl := api.Group("/crawl")
-		crawl.Use(middleware.AuthRequired(authService))
+		crawl.Use(middleware.AuthRequired(authService), readOrCrawl, userLimit, middleware.RateLimitByUser(limiters.crawl), orgScope, urlInScope)
 		{
 			crawl.POST("/:id", crawlHandler.StartCrawl)
 			crawl.GET("/status/:id", crawlHandler.GetCrawlStatus)
-			crawl.POST("/bulk-rerun", crawlHandler.BulkRerunCrawls)
+			crawl.POST("/bulk-rerun", idempotent, crawlHandler.BulkRerunCrawls)
+		}
+
+		// Endpoints of single crawls (protected)
+		crawls := api.Group("/crawls")
+		crawls.Use(middleware.AuthRequired(authService), readOrCrawl, userLimit, middleware.RateLimitByUser(limiters.crawl), orgScope)
+		{
+			crawls.POST("/:id/reprocess", crawlHandler.ReprocessCrawl)
+			crawls.GET("/:id/events", crawlHandler.GetCrawlEvents)
+		}
+
+		// Report endpoints (protected)
+		reports := api.Group("/reports")
+		reports.Use(middleware.AuthRequired(authService), readOnly, userLimit, orgScope)
+		{
+			reports.POST("/bundle", reportHandler.CreateBundle)
+			reports.GET("/bundle/:id", reportHandler.GetBundle)
+			reports.GET("/bundle/:id/download", reportHandler.DownloadBundle)
+		}
+
+		// Default broken link threshold of the organization or the user's URLs (protected)
+		threshold := api.Group("/broken-link-threshold")
+		threshold.Use(middleware.AuthRequired(authService), readOrAdmin, userLimit, orgScope)
+		{
+			threshold.GET("", brokenLinkThresholdHandler.GetProjectThreshold)
+			threshold.PUT("", orgAdmin, brokenLinkThresholdHandler.SetProjectThreshold)
+			threshold.DELETE("", orgAdmin, brokenLinkThresholdHandler.DeleteProjectThreshold)
+		}
+
+		// Sitemaps whose pages were imported as URLs (protected)
+		sitemaps := api.Group("/sitemaps")
+		sitemaps.Use(middleware.AuthRequired(authService), readOrAdmin, userLimit, orgScope)
+		{
+			sitemaps.GET("", sitemapHandler.ListSitemaps)
+			sitemaps.POST("", sitemapHandler.ImportSitemap)
+			sitemaps.GET("/:id/entries", sitemapHandler.GetEntries)
+			sitemaps.POST("/:id/sync", sitemapHandler.SyncSitemap)
+			sitemaps.DELETE("/:id", sitemapHandler.DeleteSitemap)
+		}
+
+		// Google account whose spreadsheets exports write to (protected)
+		google := api.Group("/integrations/google")
+		{
+			google.GET("", middleware.AuthRequired(authService), userTokens, readOrAdmin, userLimit, sheetExportHandler.GetGoogleConnection)
+			google.GET("/authorize", middleware.AuthRequired(authService), userTokens, adminScope, userLimit, sheetExportHandler.AuthorizeGoogle)
+			google.DELETE("", middleware.AuthRequired(authService), userTokens, adminScope, userLimit, sheetExportHandler.DisconnectGoogle)
+			// Google redirects the browser here, so the signed state authenticates the request (public)
+			google.GET("/callback", middleware.RateLimitByIP(limiters.public), sheetExportHandler.GoogleCallback)
+		}
+
+		// URL lists and broken link reports exported to Google Sheets (protected)
+		sheetExports := api.Group("/sheet-exports")
+		sheetExports.Use(middleware.AuthRequired(authService), readOrAdmin, userLimit, orgScope)
+		{
+			sheetExports.GET("", sheetExportHandler.ListExports)
+			sheetExports.POST("", sheetExportHandler.CreateExport)
+			sheetExports.POST("/:id/run", sheetExportHandler.RunExport)
+			sheetExports.DELETE("/:id", sheetExportHandler.DeleteExport)
+		}
+
+		// Environment pairs, e.g. the production and staging URLs of a page (protected)
+		environments := api.Group("/environments")
+		environments.Use(middleware.AuthRequired(authService), readOrAdmin, userLimit, orgScope)
+		{
+			environments.GET("", environmentHandler.ListPairs)
+			environments.POST("", environmentHandler.CreatePair)
+			environments.DELETE("/:id", environmentHandler.DeletePair)
+			environments.GET("/:id/comparison", environmentHandler.GetComparison)
+		}
+
+		// Crawls both URLs of an environment pair and compares them (protected)
+		api.POST("/compare", middleware.AuthRequired(authService), readOrCrawl, userLimit, middleware.RateLimitByUser(limiters.crawl), orgScope, environmentHandler.Compare)
+
+		// Onboarding endpoints (protected)
+		onboarding := api.Group("/onboarding")
+		onboarding.Use(middleware.AuthRequired(authService), userTokens, readOrAdmin, userLimit)
+		{
+			onboarding.GET("", onboardingHandler.GetOnboarding)
+			onboarding.POST("/steps/:step", onboardingHandler.CompleteStep)
+			onboarding.POST("/demo", onboardingHandler.CreateDemo)
+			onboarding.DELETE("/demo", onboardingHandler.RemoveDemo)
+		}
+
+		// Activity feed (protected)
+		api.GET("/activity", middleware.AuthRequired(authService), userLimit, activityHandler.GetActivity)
+
+		// Features available to the current user (protected)
+		api.GET("/features", middleware.AuthRequired(authService), userLimit, featureFlagHandler.GetMyFeatures)
+
+		// Crawl queue inspection (admin)
+		queue := api.Group("/queue")
+		queue.Use(middleware.AuthRequired(authService), middleware.AdminRequired())
+		{
+			queue.GET("", queueHandler.GetQueue)
+			queue.POST("/dead/:task_id/retry", queueHandler.RetryDeadTask)
+			queue.DELETE("/dead", queueHandler.PurgeDeadTasks)
+			queue.POST("/scheduled/:task_id/requeue", queueHandler.RequeueTask)
+			queue.DELETE("/tasks/:task_id", queueHandler.CancelTask)
+		}
+
+		// System status and crawl control (admin)
+		admin := api.Group("/admin")
+		admin.Use(middleware.AuthRequired(authService), middleware.AdminRequired())
+		{
+			admin.GET("/status", adminHandler.GetSystemStatus)
+			admin.GET("/metrics", adminHandler.GetRequestMetrics)
+			admin.GET("/auth-incidents", adminHandler.GetAuthIncidents)
+			admin.GET("/flags", featureFlagHandler.ListFeatureFlags)
+			admin.PUT("/flags/:name", featureFlagHandler.SetFeatureFlag)
+			admin.DELETE("/flags/:name", featureFlagHandler.ResetFeatureFlag)
+			admin.POST("/crawls/:id/cancel", adminHandler.CancelCrawl)
+			admin.POST("/crawls/:id/requeue", adminHandler.RequeueCrawl)
+		}
+
+		// Organizations and their members
+		orgs := api.Group("/orgs")
+		orgs.Use(middleware.AuthRequired(authService), userTokens, readOrAdmin, userLimit)
+		{
+			orgs.POST("", organizationHandler.CreateOrganization)
+			orgs.GET("", organizationHandler.ListOrganizations)
+			orgs.GET("/:org_id/members", organizationHandler.ListMembers)
+			orgs.POST("/:org_id/members", organizationHandler.AddMember)
+			orgs.PUT("/:org_id/members/:user_id", organizationHandler.UpdateMember)
+			orgs.DELETE("/:org_id/members/:user_id", organizationHandler.RemoveMember)
+		}
+
+		// API tokens of the user and their organizations
+		tokens := api.Group("/tokens")
+		tokens.Use(middleware.AuthRequired(authService), userTokens, adminScope, userLimit)
+		{
+			tokens.GET("", apiTokenHandler.ListTokens)
+			tokens.POST("", apiTokenHandler.CreateToken)
+			tokens.DELETE("/:id", apiTokenHandler.RevokeToken)
+		}
+
+		// Quota usage, and plans of users (admin)
+		quota := api.Group("/quota")
+		quota.Use(middleware.AuthRequired(authService), readOrAdmin, userLimit)
+		{
+			quota.GET("", quotaHandler.GetQuota)
+			quota.PUT("/users/:user_id", middleware.AdminRequired(), quotaHandler.SetUserQuota)
+			quota.DELETE("/users/:user_id", middleware.AdminRequired(), quotaHandler.DeleteUserQuota)
 		}
 	}
 } 
\ No newline at end of file
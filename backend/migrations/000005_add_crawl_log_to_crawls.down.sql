@@ -0,0 +1 @@
+ALTER TABLE crawls DROP COLUMN crawl_log;
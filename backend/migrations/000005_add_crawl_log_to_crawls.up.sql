@@ -0,0 +1 @@
+ALTER TABLE crawls ADD COLUMN crawl_log TEXT NULL AFTER heading_counts;
@@ -0,0 +1,15 @@
+CREATE TABLE report_bundles (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT UNSIGNED NOT NULL,
+    status ENUM('queued', 'running', 'completed', 'error') DEFAULT 'queued',
+    url_ids TEXT,
+    url_count INT UNSIGNED DEFAULT 0,
+    file_path VARCHAR(1024) DEFAULT '',
+    error_message TEXT,
+    started_at TIMESTAMP NULL DEFAULT NULL,
+    completed_at TIMESTAMP NULL DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    INDEX idx_report_bundles_user_id (user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS onboarding_states;
+ALTER TABLE urls DROP INDEX idx_urls_user_id, DROP COLUMN user_id;
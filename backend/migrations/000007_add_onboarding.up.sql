@@ -0,0 +1,13 @@
+ALTER TABLE urls ADD COLUMN user_id BIGINT UNSIGNED NULL AFTER has_login_form,
+    ADD INDEX idx_urls_user_id (user_id);
+
+CREATE TABLE onboarding_states (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT UNSIGNED NOT NULL,
+    completed_steps TEXT,
+    demo_url_id BIGINT UNSIGNED NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    UNIQUE INDEX idx_onboarding_states_user_id (user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
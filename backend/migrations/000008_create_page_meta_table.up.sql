@@ -0,0 +1,16 @@
+CREATE TABLE page_meta (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    url_id BIGINT UNSIGNED NOT NULL,
+    crawl_id BIGINT UNSIGNED NOT NULL,
+    description TEXT,
+    robots VARCHAR(255) DEFAULT '',
+    canonical VARCHAR(2048) DEFAULT '',
+    open_graph TEXT,
+    twitter_card TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (url_id) REFERENCES urls(id) ON DELETE CASCADE,
+    FOREIGN KEY (crawl_id) REFERENCES crawls(id) ON DELETE CASCADE,
+    INDEX idx_page_meta_url_id (url_id),
+    UNIQUE INDEX idx_page_meta_crawl_id (crawl_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
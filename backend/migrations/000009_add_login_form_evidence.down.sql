@@ -0,0 +1,2 @@
+ALTER TABLE crawls DROP COLUMN login_form_evidence, DROP COLUMN login_form_detected;
+ALTER TABLE urls DROP COLUMN login_form_override;
@@ -0,0 +1,4 @@
+ALTER TABLE urls ADD COLUMN login_form_override BOOLEAN NULL AFTER has_login_form;
+
+ALTER TABLE crawls ADD COLUMN login_form_detected BOOLEAN DEFAULT FALSE AFTER heading_counts,
+    ADD COLUMN login_form_evidence TEXT AFTER login_form_detected;
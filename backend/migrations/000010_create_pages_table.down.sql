@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS pages;
+ALTER TABLE crawls DROP COLUMN pages_crawled;
+ALTER TABLE urls DROP COLUMN max_pages, DROP COLUMN max_depth;
@@ -0,0 +1,23 @@
+ALTER TABLE urls ADD COLUMN max_depth INT DEFAULT 0 AFTER login_form_override,
+    ADD COLUMN max_pages INT DEFAULT 0 AFTER max_depth;
+
+ALTER TABLE crawls ADD COLUMN pages_crawled INT DEFAULT 0 AFTER login_form_evidence;
+
+CREATE TABLE pages (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    url_id BIGINT UNSIGNED NOT NULL,
+    crawl_id BIGINT UNSIGNED NOT NULL,
+    page_url VARCHAR(2048) NOT NULL,
+    depth INT DEFAULT 0,
+    status_code INT DEFAULT 0,
+    title VARCHAR(255) DEFAULT '',
+    heading_counts TEXT,
+    heading_depth INT DEFAULT 0,
+    error_message TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (url_id) REFERENCES urls(id) ON DELETE CASCADE,
+    FOREIGN KEY (crawl_id) REFERENCES crawls(id) ON DELETE CASCADE,
+    INDEX idx_pages_url_id (url_id),
+    INDEX idx_pages_crawl_id (crawl_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
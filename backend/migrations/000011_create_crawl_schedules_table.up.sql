@@ -0,0 +1,18 @@
+CREATE TABLE crawl_schedules (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    url_id BIGINT UNSIGNED NOT NULL,
+    interval_minutes INT NOT NULL,
+    enabled BOOLEAN DEFAULT TRUE,
+    timezone VARCHAR(64) DEFAULT 'UTC',
+    window_start VARCHAR(5) DEFAULT '',
+    window_end VARCHAR(5) DEFAULT '',
+    days VARCHAR(32) DEFAULT '',
+    next_run_at TIMESTAMP NULL,
+    last_run_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (url_id) REFERENCES urls(id) ON DELETE CASCADE,
+    UNIQUE INDEX idx_crawl_schedules_url_id (url_id),
+    INDEX idx_crawl_schedules_next_run_at (next_run_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
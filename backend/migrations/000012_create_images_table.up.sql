@@ -0,0 +1,18 @@
+CREATE TABLE images (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    url_id BIGINT UNSIGNED NOT NULL,
+    crawl_id BIGINT UNSIGNED NOT NULL,
+    src VARCHAR(2048) NOT NULL,
+    alt TEXT,
+    missing_alt BOOLEAN DEFAULT FALSE,
+    width VARCHAR(32) DEFAULT '',
+    height VARCHAR(32) DEFAULT '',
+    status_code INT DEFAULT 0,
+    is_broken BOOLEAN DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (url_id) REFERENCES urls(id) ON DELETE CASCADE,
+    FOREIGN KEY (crawl_id) REFERENCES crawls(id) ON DELETE CASCADE,
+    INDEX idx_images_url_id (url_id),
+    INDEX idx_images_crawl_id (crawl_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1 @@
+ALTER TABLE crawls DROP COLUMN seo_checks, DROP COLUMN seo_score;
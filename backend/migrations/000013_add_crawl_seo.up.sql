@@ -0,0 +1,2 @@
+ALTER TABLE crawls ADD COLUMN seo_score INT DEFAULT 0 AFTER pages_crawled,
+    ADD COLUMN seo_checks TEXT AFTER seo_score;
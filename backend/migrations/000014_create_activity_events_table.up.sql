@@ -0,0 +1,17 @@
+CREATE TABLE activity_events (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT UNSIGNED NOT NULL,
+    actor_id BIGINT UNSIGNED NULL,
+    type VARCHAR(50) NOT NULL,
+    url_id BIGINT UNSIGNED NULL,
+    crawl_id BIGINT UNSIGNED NULL,
+    message VARCHAR(255) DEFAULT '',
+    metadata TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    INDEX idx_activity_events_user_id (user_id),
+    INDEX idx_activity_events_type (type),
+    INDEX idx_activity_events_url_id (url_id),
+    INDEX idx_activity_events_created_at (created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
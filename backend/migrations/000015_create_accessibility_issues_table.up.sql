@@ -0,0 +1,15 @@
+CREATE TABLE accessibility_issues (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    url_id BIGINT UNSIGNED NOT NULL,
+    crawl_id BIGINT UNSIGNED NOT NULL,
+    rule VARCHAR(50) NOT NULL,
+    wcag VARCHAR(16) DEFAULT '',
+    element TEXT,
+    message VARCHAR(255) DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (url_id) REFERENCES urls(id) ON DELETE CASCADE,
+    FOREIGN KEY (crawl_id) REFERENCES crawls(id) ON DELETE CASCADE,
+    INDEX idx_accessibility_issues_url_id (url_id),
+    INDEX idx_accessibility_issues_crawl_id (crawl_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,15 @@
+CREATE TABLE finding_annotations (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    url_id BIGINT UNSIGNED NOT NULL,
+    finding_type VARCHAR(50) NOT NULL,
+    finding_key VARCHAR(768) NOT NULL,
+    finding_key_hash CHAR(64) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    reason TEXT,
+    created_by BIGINT UNSIGNED NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (url_id) REFERENCES urls(id) ON DELETE CASCADE,
+    UNIQUE INDEX idx_finding_annotation (url_id, finding_type, finding_key_hash)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1 @@
+ALTER TABLE crawls DROP COLUMN security_checks, DROP COLUMN security_headers, DROP COLUMN security_score;
@@ -0,0 +1,3 @@
+ALTER TABLE crawls ADD COLUMN security_score INT DEFAULT 0 AFTER seo_checks,
+    ADD COLUMN security_headers TEXT AFTER security_score,
+    ADD COLUMN security_checks TEXT AFTER security_headers;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS mixed_content_issues;
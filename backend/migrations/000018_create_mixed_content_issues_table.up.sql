@@ -0,0 +1,15 @@
+CREATE TABLE mixed_content_issues (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    url_id BIGINT UNSIGNED NOT NULL,
+    crawl_id BIGINT UNSIGNED NOT NULL,
+    resource_type VARCHAR(20) NOT NULL,
+    resource_url TEXT NOT NULL,
+    blocked BOOLEAN DEFAULT FALSE,
+    element TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (url_id) REFERENCES urls(id) ON DELETE CASCADE,
+    FOREIGN KEY (crawl_id) REFERENCES crawls(id) ON DELETE CASCADE,
+    INDEX idx_mixed_content_issues_url_id (url_id),
+    INDEX idx_mixed_content_issues_crawl_id (crawl_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,2 @@
+ALTER TABLE pages DROP COLUMN protocol, DROP COLUMN content_encoding, DROP COLUMN response_bytes, DROP COLUMN download_ms, DROP COLUMN ttfb_ms;
+ALTER TABLE crawls DROP COLUMN protocol, DROP COLUMN content_encoding, DROP COLUMN response_bytes, DROP COLUMN download_ms, DROP COLUMN ttfb_ms;
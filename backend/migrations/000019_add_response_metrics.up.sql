@@ -0,0 +1,11 @@
+ALTER TABLE crawls ADD COLUMN ttfb_ms BIGINT DEFAULT 0 AFTER security_checks,
+    ADD COLUMN download_ms BIGINT DEFAULT 0 AFTER ttfb_ms,
+    ADD COLUMN response_bytes BIGINT DEFAULT 0 AFTER download_ms,
+    ADD COLUMN content_encoding VARCHAR(32) DEFAULT '' AFTER response_bytes,
+    ADD COLUMN protocol VARCHAR(16) DEFAULT '' AFTER content_encoding;
+
+ALTER TABLE pages ADD COLUMN ttfb_ms BIGINT DEFAULT 0 AFTER error_message,
+    ADD COLUMN download_ms BIGINT DEFAULT 0 AFTER ttfb_ms,
+    ADD COLUMN response_bytes BIGINT DEFAULT 0 AFTER download_ms,
+    ADD COLUMN content_encoding VARCHAR(32) DEFAULT '' AFTER response_bytes,
+    ADD COLUMN protocol VARCHAR(16) DEFAULT '' AFTER content_encoding;
@@ -0,0 +1 @@
+ALTER TABLE crawls DROP COLUMN content_hash, DROP COLUMN title;
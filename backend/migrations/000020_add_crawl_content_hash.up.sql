@@ -0,0 +1,2 @@
+ALTER TABLE crawls ADD COLUMN title VARCHAR(255) DEFAULT '' AFTER heading_counts,
+    ADD COLUMN content_hash CHAR(64) DEFAULT '' AFTER title;
@@ -0,0 +1 @@
+ALTER TABLE links DROP COLUMN context, DROP COLUMN ugc, DROP COLUMN sponsored, DROP COLUMN nofollow, DROP COLUMN target, DROP COLUMN rel;
@@ -0,0 +1,6 @@
+ALTER TABLE links ADD COLUMN rel VARCHAR(255) DEFAULT '' AFTER is_accessible,
+    ADD COLUMN target VARCHAR(50) DEFAULT '' AFTER rel,
+    ADD COLUMN nofollow BOOLEAN DEFAULT FALSE AFTER target,
+    ADD COLUMN sponsored BOOLEAN DEFAULT FALSE AFTER nofollow,
+    ADD COLUMN ugc BOOLEAN DEFAULT FALSE AFTER sponsored,
+    ADD COLUMN context VARCHAR(20) DEFAULT '' AFTER ugc;
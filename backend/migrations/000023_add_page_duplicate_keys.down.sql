@@ -0,0 +1 @@
+ALTER TABLE pages DROP INDEX idx_pages_content_hash, DROP COLUMN content_hash, DROP COLUMN canonical;
@@ -0,0 +1,3 @@
+ALTER TABLE pages ADD COLUMN canonical VARCHAR(2048) DEFAULT '' AFTER error_message,
+    ADD COLUMN content_hash CHAR(64) DEFAULT '' AFTER canonical,
+    ADD INDEX idx_pages_content_hash (content_hash);
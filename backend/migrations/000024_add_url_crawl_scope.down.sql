@@ -0,0 +1,2 @@
+ALTER TABLE urls DROP COLUMN max_query_params, DROP COLUMN strip_query, DROP COLUMN allow_subdomains,
+    DROP COLUMN exclude_patterns, DROP COLUMN include_patterns;
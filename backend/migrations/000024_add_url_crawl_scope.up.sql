@@ -0,0 +1,5 @@
+ALTER TABLE urls ADD COLUMN include_patterns TEXT AFTER max_pages,
+    ADD COLUMN exclude_patterns TEXT AFTER include_patterns,
+    ADD COLUMN allow_subdomains BOOLEAN DEFAULT FALSE AFTER exclude_patterns,
+    ADD COLUMN strip_query BOOLEAN DEFAULT FALSE AFTER allow_subdomains,
+    ADD COLUMN max_query_params INT DEFAULT 0 AFTER strip_query;
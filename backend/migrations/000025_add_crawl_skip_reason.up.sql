@@ -0,0 +1 @@
+ALTER TABLE crawls ADD COLUMN skip_reason VARCHAR(255) DEFAULT '' AFTER error_message;
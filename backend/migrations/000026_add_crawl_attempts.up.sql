@@ -0,0 +1 @@
+ALTER TABLE crawls ADD COLUMN attempts INT DEFAULT 0 AFTER skip_reason;
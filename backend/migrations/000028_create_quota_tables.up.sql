@@ -0,0 +1,21 @@
+CREATE TABLE user_quotas (
+    user_id BIGINT UNSIGNED NOT NULL PRIMARY KEY,
+    plan VARCHAR(64) NOT NULL,
+    max_urls INT NULL,
+    max_crawls_per_day INT NULL,
+    max_pages INT NULL,
+    created_at TIMESTAMP NULL,
+    updated_at TIMESTAMP NULL,
+
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE crawl_usages (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT UNSIGNED NOT NULL,
+    day CHAR(10) NOT NULL,
+    crawls INT NOT NULL DEFAULT 0,
+
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    UNIQUE INDEX idx_crawl_usage_user_day (user_id, day)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
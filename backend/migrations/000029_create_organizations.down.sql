@@ -0,0 +1,7 @@
+ALTER TABLE urls
+    DROP INDEX idx_urls_org_url,
+    DROP COLUMN organization_id,
+    ADD UNIQUE INDEX url (url);
+
+DROP TABLE IF EXISTS memberships;
+DROP TABLE IF EXISTS organizations;
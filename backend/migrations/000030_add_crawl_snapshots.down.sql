@@ -0,0 +1 @@
+ALTER TABLE crawls DROP COLUMN snapshot_key, DROP COLUMN reprocessed_at;
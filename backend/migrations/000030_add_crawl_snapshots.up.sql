@@ -0,0 +1,2 @@
+ALTER TABLE crawls ADD COLUMN snapshot_key VARCHAR(255) DEFAULT '' AFTER crawl_log,
+    ADD COLUMN reprocessed_at TIMESTAMP NULL AFTER completed_at;
@@ -0,0 +1,2 @@
+ALTER TABLE urls ADD COLUMN disabled_extractors TEXT AFTER max_query_params;
+ALTER TABLE crawls ADD COLUMN extracted_data TEXT AFTER security_checks;
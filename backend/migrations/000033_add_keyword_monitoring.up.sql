@@ -0,0 +1,2 @@
+ALTER TABLE urls ADD COLUMN keywords TEXT AFTER disabled_extractors;
+ALTER TABLE crawls ADD COLUMN keyword_checks TEXT AFTER extracted_data;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS monitor_checks;
+DROP TABLE IF EXISTS monitors;
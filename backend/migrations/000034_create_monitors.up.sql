@@ -0,0 +1,30 @@
+CREATE TABLE monitors (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    url_id BIGINT UNSIGNED NOT NULL,
+    interval_minutes INT NOT NULL,
+    enabled BOOLEAN DEFAULT TRUE,
+    next_check_at TIMESTAMP NULL,
+    last_check_at TIMESTAMP NULL,
+    last_up BOOLEAN NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (url_id) REFERENCES urls(id) ON DELETE CASCADE,
+    UNIQUE INDEX idx_monitors_url_id (url_id),
+    INDEX idx_monitors_next_check_at (next_check_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE monitor_checks (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    url_id BIGINT UNSIGNED NOT NULL,
+    checked_at TIMESTAMP NOT NULL,
+    up BOOLEAN NOT NULL DEFAULT FALSE,
+    status_code INT DEFAULT 0,
+    response_ms BIGINT DEFAULT 0,
+    tls_valid BOOLEAN NULL,
+    tls_expires_at TIMESTAMP NULL,
+    error VARCHAR(255) DEFAULT '',
+
+    FOREIGN KEY (url_id) REFERENCES urls(id) ON DELETE CASCADE,
+    INDEX idx_monitor_checks_url_checked (url_id, checked_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,2 @@
+ALTER TABLE crawls ADD COLUMN word_count INT DEFAULT 0 AFTER keyword_checks;
+ALTER TABLE crawls ADD COLUMN readability_score DOUBLE NULL AFTER word_count;
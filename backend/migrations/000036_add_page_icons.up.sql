@@ -0,0 +1 @@
+ALTER TABLE page_meta ADD COLUMN icons TEXT AFTER twitter_card;
@@ -0,0 +1,2 @@
+ALTER TABLE page_meta ADD COLUMN viewport VARCHAR(255) DEFAULT '' AFTER canonical,
+    ADD COLUMN amp_url VARCHAR(2048) DEFAULT '' AFTER viewport;
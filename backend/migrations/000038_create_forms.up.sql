@@ -0,0 +1,16 @@
+CREATE TABLE forms (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    url_id BIGINT UNSIGNED NOT NULL,
+    crawl_id BIGINT UNSIGNED NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    method VARCHAR(10) DEFAULT '',
+    action VARCHAR(2048) DEFAULT '',
+    input_count INT DEFAULT 0,
+    evidence TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (url_id) REFERENCES urls(id) ON DELETE CASCADE,
+    FOREIGN KEY (crawl_id) REFERENCES crawls(id) ON DELETE CASCADE,
+    INDEX idx_forms_url_id (url_id),
+    INDEX idx_forms_crawl_id (crawl_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1 @@
+ALTER TABLE crawls ADD COLUMN login_form_confidence DOUBLE DEFAULT 0 AFTER login_form_evidence;
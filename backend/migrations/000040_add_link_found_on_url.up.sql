@@ -0,0 +1 @@
+ALTER TABLE links ADD COLUMN found_on_url VARCHAR(2048) DEFAULT '' AFTER link_url;
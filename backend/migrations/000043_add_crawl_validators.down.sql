@@ -0,0 +1 @@
+ALTER TABLE crawls DROP COLUMN last_modified, DROP COLUMN etag;
@@ -0,0 +1,2 @@
+ALTER TABLE crawls ADD COLUMN etag VARCHAR(255) DEFAULT '' AFTER snapshot_key,
+    ADD COLUMN last_modified VARCHAR(64) DEFAULT '' AFTER etag;
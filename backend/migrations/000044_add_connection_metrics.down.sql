@@ -0,0 +1,2 @@
+ALTER TABLE pages DROP COLUMN connection_reused, DROP COLUMN keep_alive, DROP COLUMN http3_advertised;
+ALTER TABLE crawls DROP COLUMN connection_reused, DROP COLUMN keep_alive, DROP COLUMN http3_advertised;
@@ -0,0 +1,7 @@
+ALTER TABLE crawls ADD COLUMN http3_advertised BOOLEAN DEFAULT FALSE AFTER protocol,
+    ADD COLUMN keep_alive BOOLEAN DEFAULT FALSE AFTER http3_advertised,
+    ADD COLUMN connection_reused BOOLEAN DEFAULT FALSE AFTER keep_alive;
+
+ALTER TABLE pages ADD COLUMN http3_advertised BOOLEAN DEFAULT FALSE AFTER protocol,
+    ADD COLUMN keep_alive BOOLEAN DEFAULT FALSE AFTER http3_advertised,
+    ADD COLUMN connection_reused BOOLEAN DEFAULT FALSE AFTER keep_alive;
@@ -0,0 +1,11 @@
+CREATE TABLE crawl_events (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    crawl_id BIGINT UNSIGNED NOT NULL,
+    type VARCHAR(32) NOT NULL,
+    message TEXT,
+    elapsed_ms BIGINT DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (crawl_id) REFERENCES crawls(id) ON DELETE CASCADE,
+    INDEX idx_crawl_events_crawl_id (crawl_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
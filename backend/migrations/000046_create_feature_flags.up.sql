@@ -0,0 +1,7 @@
+CREATE TABLE feature_flags (
+    name VARCHAR(64) NOT NULL PRIMARY KEY,
+    enabled BOOLEAN DEFAULT FALSE,
+    user_ids TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
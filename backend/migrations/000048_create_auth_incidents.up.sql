@@ -0,0 +1,13 @@
+CREATE TABLE auth_incidents (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    type VARCHAR(50) NOT NULL,
+    ip_address VARCHAR(45) NOT NULL,
+    path VARCHAR(255),
+    failures INT NOT NULL DEFAULT 0,
+    blocked_until TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    INDEX idx_auth_incidents_type (type),
+    INDEX idx_auth_incidents_ip_address (ip_address),
+    INDEX idx_auth_incidents_created_at (created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS environment_pairs;
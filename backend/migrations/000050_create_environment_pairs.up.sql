@@ -0,0 +1,16 @@
+CREATE TABLE environment_pairs (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    organization_id BIGINT UNSIGNED NOT NULL DEFAULT 0,
+    name VARCHAR(100) NOT NULL,
+    baseline_url_id BIGINT UNSIGNED NOT NULL,
+    candidate_url_id BIGINT UNSIGNED NOT NULL,
+    created_by BIGINT UNSIGNED NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (baseline_url_id) REFERENCES urls(id) ON DELETE CASCADE,
+    FOREIGN KEY (candidate_url_id) REFERENCES urls(id) ON DELETE CASCADE,
+    INDEX idx_environment_pairs_organization_id (organization_id),
+    INDEX idx_environment_pairs_baseline_url_id (baseline_url_id),
+    INDEX idx_environment_pairs_candidate_url_id (candidate_url_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
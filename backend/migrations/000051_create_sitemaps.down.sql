@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS sitemap_entries;
+DROP TABLE IF EXISTS sitemaps;
@@ -0,0 +1,31 @@
+CREATE TABLE sitemaps (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT UNSIGNED NOT NULL,
+    organization_id BIGINT UNSIGNED NOT NULL DEFAULT 0,
+    url VARCHAR(2048) NOT NULL,
+    sync_interval_minutes INT NOT NULL DEFAULT 0,
+    next_sync_at TIMESTAMP NULL,
+    last_synced_at TIMESTAMP NULL,
+    last_error VARCHAR(255),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    INDEX idx_sitemaps_user_id (user_id),
+    INDEX idx_sitemaps_organization_id (organization_id),
+    INDEX idx_sitemaps_next_sync_at (next_sync_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE sitemap_entries (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    sitemap_id BIGINT UNSIGNED NOT NULL,
+    url_id BIGINT UNSIGNED NOT NULL,
+    loc VARCHAR(2048) NOT NULL,
+    removed_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (sitemap_id) REFERENCES sitemaps(id) ON DELETE CASCADE,
+    FOREIGN KEY (url_id) REFERENCES urls(id) ON DELETE CASCADE,
+    UNIQUE INDEX idx_sitemap_entries_sitemap_url (sitemap_id, url_id),
+    INDEX idx_sitemap_entries_url_id (url_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS broken_link_thresholds;
+ALTER TABLE urls DROP INDEX idx_urls_needs_attention, DROP COLUMN needs_attention;
@@ -0,0 +1,15 @@
+ALTER TABLE urls ADD COLUMN needs_attention BOOLEAN DEFAULT FALSE AFTER keywords;
+CREATE INDEX idx_urls_needs_attention ON urls (needs_attention);
+
+CREATE TABLE broken_link_thresholds (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    organization_id BIGINT UNSIGNED NOT NULL DEFAULT 0,
+    user_id BIGINT UNSIGNED NOT NULL DEFAULT 0,
+    url_id BIGINT UNSIGNED NOT NULL DEFAULT 0,
+    max_broken_links INT NULL,
+    max_broken_percent DOUBLE NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    UNIQUE INDEX idx_broken_link_thresholds_scope (organization_id, user_id, url_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS sheet_exports;
+DROP TABLE IF EXISTS google_connections;
@@ -0,0 +1,2 @@
+DELETE FROM links WHERE link_type NOT IN ('internal', 'external');
+ALTER TABLE links MODIFY link_type ENUM('internal', 'external') NOT NULL;
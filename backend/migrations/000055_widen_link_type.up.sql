@@ -0,0 +1 @@
+ALTER TABLE links MODIFY link_type VARCHAR(20) NOT NULL;
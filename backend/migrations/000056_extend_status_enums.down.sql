@@ -0,0 +1,5 @@
+UPDATE urls SET status = 'error' WHERE status = 'skipped';
+UPDATE crawls SET status = 'error' WHERE status = 'skipped';
+UPDATE crawls SET status = 'completed' WHERE status = 'unchanged';
+ALTER TABLE urls MODIFY status ENUM('pending', 'running', 'completed', 'error') DEFAULT 'pending';
+ALTER TABLE crawls MODIFY status ENUM('queued', 'running', 'completed', 'error') DEFAULT 'queued';
@@ -0,0 +1,2 @@
+ALTER TABLE urls MODIFY status ENUM('pending', 'running', 'completed', 'skipped', 'error') DEFAULT 'pending';
+ALTER TABLE crawls MODIFY status ENUM('queued', 'running', 'completed', 'unchanged', 'skipped', 'error') DEFAULT 'queued';
@@ -0,0 +1,16 @@
+DROP TABLE IF EXISTS idempotency_keys;
+DROP TABLE IF EXISTS page_links;
+DROP TABLE IF EXISTS mixed_content_issues;
+DROP TABLE IF EXISTS finding_annotations;
+DROP TABLE IF EXISTS accessibility_issues;
+DROP TABLE IF EXISTS activity_events;
+DROP TABLE IF EXISTS images;
+DROP TABLE IF EXISTS crawl_schedules;
+DROP TABLE IF EXISTS pages;
+DROP TABLE IF EXISTS page_meta;
+DROP TABLE IF EXISTS onboarding_states;
+DROP TABLE IF EXISTS report_bundles;
+DROP TABLE IF EXISTS links;
+DROP TABLE IF EXISTS crawls;
+DROP TABLE IF EXISTS urls;
+DROP TABLE IF EXISTS users;
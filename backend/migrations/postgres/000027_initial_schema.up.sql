@@ -0,0 +1,280 @@
+-- PostgreSQL starts from the schema of MySQL migration 000027, so both
+-- databases share migration versions from here on.
+
+CREATE TABLE users (
+    id BIGSERIAL PRIMARY KEY,
+    username VARCHAR(191) NOT NULL UNIQUE,
+    email VARCHAR(191) NOT NULL UNIQUE,
+    password VARCHAR(255) NOT NULL,
+    first_name VARCHAR(191) NOT NULL DEFAULT '',
+    last_name VARCHAR(191) NOT NULL DEFAULT '',
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    is_admin BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NULL,
+    updated_at TIMESTAMPTZ NULL,
+    deleted_at TIMESTAMPTZ NULL
+);
+CREATE INDEX idx_users_deleted_at ON users (deleted_at);
+
+CREATE TABLE urls (
+    id BIGSERIAL PRIMARY KEY,
+    url VARCHAR(2048) NOT NULL UNIQUE,
+    title VARCHAR(512) DEFAULT '',
+    html_version VARCHAR(50) DEFAULT '',
+    status VARCHAR(20) DEFAULT 'pending',
+    has_login_form BOOLEAN DEFAULT FALSE,
+    login_form_override BOOLEAN NULL,
+    max_depth INT DEFAULT 0,
+    max_pages INT DEFAULT 0,
+    include_patterns TEXT,
+    exclude_patterns TEXT,
+    allow_subdomains BOOLEAN DEFAULT FALSE,
+    strip_query BOOLEAN DEFAULT FALSE,
+    max_query_params INT DEFAULT 0,
+    user_id BIGINT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ NULL
+);
+CREATE INDEX idx_urls_status ON urls (status);
+CREATE INDEX idx_urls_created_at ON urls (created_at);
+CREATE INDEX idx_urls_deleted_at ON urls (deleted_at);
+CREATE INDEX idx_urls_user_id ON urls (user_id);
+
+CREATE TABLE crawls (
+    id BIGSERIAL PRIMARY KEY,
+    url_id BIGINT NOT NULL REFERENCES urls(id) ON DELETE CASCADE,
+    status VARCHAR(20) DEFAULT 'queued',
+    started_at TIMESTAMPTZ NULL,
+    completed_at TIMESTAMPTZ NULL,
+    error_message TEXT DEFAULT '',
+    skip_reason VARCHAR(255) DEFAULT '',
+    attempts INT DEFAULT 0,
+    internal_links INT DEFAULT 0,
+    external_links INT DEFAULT 0,
+    broken_links INT DEFAULT 0,
+    heading_counts TEXT,
+    title VARCHAR(255) DEFAULT '',
+    content_hash CHAR(64) DEFAULT '',
+    crawl_log TEXT NULL,
+    login_form_detected BOOLEAN DEFAULT FALSE,
+    login_form_evidence TEXT,
+    pages_crawled INT DEFAULT 0,
+    seo_score INT DEFAULT 0,
+    seo_checks TEXT,
+    security_score INT DEFAULT 0,
+    security_headers TEXT,
+    security_checks TEXT,
+    ttfb_ms BIGINT DEFAULT 0,
+    download_ms BIGINT DEFAULT 0,
+    response_bytes BIGINT DEFAULT 0,
+    content_encoding VARCHAR(32) DEFAULT '',
+    protocol VARCHAR(16) DEFAULT '',
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_crawls_url_id ON crawls (url_id);
+CREATE INDEX idx_crawls_status ON crawls (status);
+CREATE INDEX idx_crawls_created_at ON crawls (created_at);
+
+CREATE TABLE links (
+    id BIGSERIAL PRIMARY KEY,
+    url_id BIGINT NOT NULL REFERENCES urls(id) ON DELETE CASCADE,
+    crawl_id BIGINT NOT NULL REFERENCES crawls(id) ON DELETE CASCADE,
+    link_url VARCHAR(2048) NOT NULL,
+    link_text VARCHAR(512) DEFAULT '',
+    link_type VARCHAR(20) NOT NULL,
+    status_code INT DEFAULT 0,
+    is_accessible BOOLEAN DEFAULT TRUE,
+    rel VARCHAR(255) DEFAULT '',
+    target VARCHAR(50) DEFAULT '',
+    nofollow BOOLEAN DEFAULT FALSE,
+    sponsored BOOLEAN DEFAULT FALSE,
+    ugc BOOLEAN DEFAULT FALSE,
+    context VARCHAR(20) DEFAULT '',
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_links_url_id ON links (url_id);
+CREATE INDEX idx_links_crawl_id ON links (crawl_id);
+CREATE INDEX idx_links_type ON links (link_type);
+CREATE INDEX idx_links_accessible ON links (is_accessible);
+CREATE INDEX idx_links_status_code ON links (status_code);
+
+CREATE TABLE report_bundles (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    status VARCHAR(20) DEFAULT 'queued',
+    url_ids TEXT,
+    url_count INT DEFAULT 0,
+    file_path VARCHAR(1024) DEFAULT '',
+    error_message TEXT,
+    started_at TIMESTAMPTZ NULL,
+    completed_at TIMESTAMPTZ NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_report_bundles_user_id ON report_bundles (user_id);
+
+CREATE TABLE onboarding_states (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    completed_steps TEXT,
+    demo_url_id BIGINT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX idx_onboarding_states_user_id ON onboarding_states (user_id);
+
+CREATE TABLE page_meta (
+    id BIGSERIAL PRIMARY KEY,
+    url_id BIGINT NOT NULL REFERENCES urls(id) ON DELETE CASCADE,
+    crawl_id BIGINT NOT NULL REFERENCES crawls(id) ON DELETE CASCADE,
+    description TEXT,
+    robots VARCHAR(255) DEFAULT '',
+    canonical VARCHAR(2048) DEFAULT '',
+    open_graph TEXT,
+    twitter_card TEXT,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_page_meta_url_id ON page_meta (url_id);
+CREATE UNIQUE INDEX idx_page_meta_crawl_id ON page_meta (crawl_id);
+
+CREATE TABLE pages (
+    id BIGSERIAL PRIMARY KEY,
+    url_id BIGINT NOT NULL REFERENCES urls(id) ON DELETE CASCADE,
+    crawl_id BIGINT NOT NULL REFERENCES crawls(id) ON DELETE CASCADE,
+    page_url VARCHAR(2048) NOT NULL,
+    depth INT DEFAULT 0,
+    status_code INT DEFAULT 0,
+    title VARCHAR(255) DEFAULT '',
+    heading_counts TEXT,
+    heading_depth INT DEFAULT 0,
+    error_message TEXT,
+    canonical VARCHAR(2048) DEFAULT '',
+    content_hash CHAR(64) DEFAULT '',
+    ttfb_ms BIGINT DEFAULT 0,
+    download_ms BIGINT DEFAULT 0,
+    response_bytes BIGINT DEFAULT 0,
+    content_encoding VARCHAR(32) DEFAULT '',
+    protocol VARCHAR(16) DEFAULT '',
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_pages_url_id ON pages (url_id);
+CREATE INDEX idx_pages_crawl_id ON pages (crawl_id);
+CREATE INDEX idx_pages_content_hash ON pages (content_hash);
+
+CREATE TABLE crawl_schedules (
+    id BIGSERIAL PRIMARY KEY,
+    url_id BIGINT NOT NULL REFERENCES urls(id) ON DELETE CASCADE,
+    interval_minutes INT NOT NULL,
+    enabled BOOLEAN DEFAULT TRUE,
+    timezone VARCHAR(64) DEFAULT 'UTC',
+    window_start VARCHAR(5) DEFAULT '',
+    window_end VARCHAR(5) DEFAULT '',
+    days VARCHAR(32) DEFAULT '',
+    next_run_at TIMESTAMPTZ NULL,
+    last_run_at TIMESTAMPTZ NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX idx_crawl_schedules_url_id ON crawl_schedules (url_id);
+CREATE INDEX idx_crawl_schedules_next_run_at ON crawl_schedules (next_run_at);
+
+CREATE TABLE images (
+    id BIGSERIAL PRIMARY KEY,
+    url_id BIGINT NOT NULL REFERENCES urls(id) ON DELETE CASCADE,
+    crawl_id BIGINT NOT NULL REFERENCES crawls(id) ON DELETE CASCADE,
+    src VARCHAR(2048) NOT NULL,
+    alt TEXT,
+    missing_alt BOOLEAN DEFAULT FALSE,
+    width VARCHAR(32) DEFAULT '',
+    height VARCHAR(32) DEFAULT '',
+    status_code INT DEFAULT 0,
+    is_broken BOOLEAN DEFAULT FALSE,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_images_url_id ON images (url_id);
+CREATE INDEX idx_images_crawl_id ON images (crawl_id);
+
+CREATE TABLE activity_events (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    actor_id BIGINT NULL,
+    type VARCHAR(50) NOT NULL,
+    url_id BIGINT NULL,
+    crawl_id BIGINT NULL,
+    message VARCHAR(255) DEFAULT '',
+    metadata TEXT,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_activity_events_user_id ON activity_events (user_id);
+CREATE INDEX idx_activity_events_type ON activity_events (type);
+CREATE INDEX idx_activity_events_url_id ON activity_events (url_id);
+CREATE INDEX idx_activity_events_created_at ON activity_events (created_at);
+
+CREATE TABLE accessibility_issues (
+    id BIGSERIAL PRIMARY KEY,
+    url_id BIGINT NOT NULL REFERENCES urls(id) ON DELETE CASCADE,
+    crawl_id BIGINT NOT NULL REFERENCES crawls(id) ON DELETE CASCADE,
+    rule VARCHAR(50) NOT NULL,
+    wcag VARCHAR(16) DEFAULT '',
+    element TEXT,
+    message VARCHAR(255) DEFAULT '',
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_accessibility_issues_url_id ON accessibility_issues (url_id);
+CREATE INDEX idx_accessibility_issues_crawl_id ON accessibility_issues (crawl_id);
+
+CREATE TABLE finding_annotations (
+    id BIGSERIAL PRIMARY KEY,
+    url_id BIGINT NOT NULL REFERENCES urls(id) ON DELETE CASCADE,
+    finding_type VARCHAR(50) NOT NULL,
+    finding_key VARCHAR(768) NOT NULL,
+    finding_key_hash CHAR(64) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    reason TEXT,
+    created_by BIGINT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX idx_finding_annotation ON finding_annotations (url_id, finding_type, finding_key_hash);
+
+CREATE TABLE mixed_content_issues (
+    id BIGSERIAL PRIMARY KEY,
+    url_id BIGINT NOT NULL REFERENCES urls(id) ON DELETE CASCADE,
+    crawl_id BIGINT NOT NULL REFERENCES crawls(id) ON DELETE CASCADE,
+    resource_type VARCHAR(20) NOT NULL,
+    resource_url TEXT NOT NULL,
+    blocked BOOLEAN DEFAULT FALSE,
+    element TEXT,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_mixed_content_issues_url_id ON mixed_content_issues (url_id);
+CREATE INDEX idx_mixed_content_issues_crawl_id ON mixed_content_issues (crawl_id);
+
+CREATE TABLE page_links (
+    id BIGSERIAL PRIMARY KEY,
+    url_id BIGINT NOT NULL REFERENCES urls(id) ON DELETE CASCADE,
+    crawl_id BIGINT NOT NULL REFERENCES crawls(id) ON DELETE CASCADE,
+    source_url VARCHAR(2048) NOT NULL,
+    target_url VARCHAR(2048) NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_page_links_url_id ON page_links (url_id);
+CREATE INDEX idx_page_links_crawl_id ON page_links (crawl_id);
+
+CREATE TABLE idempotency_keys (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    key VARCHAR(255) NOT NULL,
+    method VARCHAR(10) NOT NULL,
+    path VARCHAR(255) NOT NULL,
+    request_hash CHAR(64) NOT NULL,
+    status_code INT NOT NULL DEFAULT 0,
+    content_type VARCHAR(255),
+    response_body BYTEA,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX idx_idempotency_key ON idempotency_keys (user_id, key, method, path);
+CREATE INDEX idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS crawl_usages;
+DROP TABLE IF EXISTS user_quotas;
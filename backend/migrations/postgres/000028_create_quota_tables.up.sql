@@ -0,0 +1,17 @@
+CREATE TABLE user_quotas (
+    user_id BIGINT NOT NULL PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    plan VARCHAR(64) NOT NULL,
+    max_urls INT NULL,
+    max_crawls_per_day INT NULL,
+    max_pages INT NULL,
+    created_at TIMESTAMPTZ NULL,
+    updated_at TIMESTAMPTZ NULL
+);
+
+CREATE TABLE crawl_usages (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    day CHAR(10) NOT NULL,
+    crawls INT NOT NULL DEFAULT 0
+);
+CREATE UNIQUE INDEX idx_crawl_usage_user_day ON crawl_usages (user_id, day);
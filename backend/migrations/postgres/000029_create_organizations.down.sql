@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_urls_org_url;
+ALTER TABLE urls DROP COLUMN organization_id;
+ALTER TABLE urls ADD CONSTRAINT urls_url_key UNIQUE (url);
+
+DROP TABLE IF EXISTS memberships;
+DROP TABLE IF EXISTS organizations;
@@ -0,0 +1,23 @@
+CREATE TABLE organizations (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(191) NOT NULL,
+    created_by BIGINT NOT NULL,
+    created_at TIMESTAMPTZ NULL,
+    updated_at TIMESTAMPTZ NULL
+);
+
+CREATE TABLE memberships (
+    id BIGSERIAL PRIMARY KEY,
+    organization_id BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL,
+    created_at TIMESTAMPTZ NULL,
+    updated_at TIMESTAMPTZ NULL
+);
+CREATE UNIQUE INDEX idx_membership ON memberships (organization_id, user_id);
+CREATE INDEX idx_memberships_user_id ON memberships (user_id);
+
+-- URLs are unique within an organization; 0 holds the URLs outside any organization
+ALTER TABLE urls ADD COLUMN organization_id BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE urls DROP CONSTRAINT urls_url_key;
+CREATE UNIQUE INDEX idx_urls_org_url ON urls (organization_id, url);
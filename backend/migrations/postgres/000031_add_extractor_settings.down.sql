@@ -0,0 +1,2 @@
+ALTER TABLE crawls DROP COLUMN extracted_data;
+ALTER TABLE urls DROP COLUMN disabled_extractors;
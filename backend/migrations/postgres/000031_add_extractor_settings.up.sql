@@ -0,0 +1,2 @@
+ALTER TABLE urls ADD COLUMN disabled_extractors TEXT;
+ALTER TABLE crawls ADD COLUMN extracted_data TEXT;
@@ -0,0 +1,26 @@
+CREATE TABLE monitors (
+    id BIGSERIAL PRIMARY KEY,
+    url_id BIGINT NOT NULL REFERENCES urls(id) ON DELETE CASCADE,
+    interval_minutes INT NOT NULL,
+    enabled BOOLEAN DEFAULT TRUE,
+    next_check_at TIMESTAMPTZ NULL,
+    last_check_at TIMESTAMPTZ NULL,
+    last_up BOOLEAN NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX idx_monitors_url_id ON monitors (url_id);
+CREATE INDEX idx_monitors_next_check_at ON monitors (next_check_at);
+
+CREATE TABLE monitor_checks (
+    id BIGSERIAL PRIMARY KEY,
+    url_id BIGINT NOT NULL REFERENCES urls(id) ON DELETE CASCADE,
+    checked_at TIMESTAMPTZ NOT NULL,
+    up BOOLEAN NOT NULL DEFAULT FALSE,
+    status_code INT DEFAULT 0,
+    response_ms BIGINT DEFAULT 0,
+    tls_valid BOOLEAN NULL,
+    tls_expires_at TIMESTAMPTZ NULL,
+    error VARCHAR(255) DEFAULT ''
+);
+CREATE INDEX idx_monitor_checks_url_checked ON monitor_checks (url_id, checked_at);
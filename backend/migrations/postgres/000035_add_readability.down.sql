@@ -0,0 +1,2 @@
+ALTER TABLE crawls DROP COLUMN readability_score;
+ALTER TABLE crawls DROP COLUMN word_count;
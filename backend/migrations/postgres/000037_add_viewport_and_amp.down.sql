@@ -0,0 +1,2 @@
+ALTER TABLE page_meta DROP COLUMN amp_url;
+ALTER TABLE page_meta DROP COLUMN viewport;
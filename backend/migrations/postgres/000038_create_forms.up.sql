@@ -0,0 +1,13 @@
+CREATE TABLE forms (
+    id BIGSERIAL PRIMARY KEY,
+    url_id BIGINT NOT NULL REFERENCES urls(id) ON DELETE CASCADE,
+    crawl_id BIGINT NOT NULL REFERENCES crawls(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL,
+    method VARCHAR(10) DEFAULT '',
+    action VARCHAR(2048) DEFAULT '',
+    input_count INT DEFAULT 0,
+    evidence TEXT,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_forms_url_id ON forms (url_id);
+CREATE INDEX idx_forms_crawl_id ON forms (crawl_id);
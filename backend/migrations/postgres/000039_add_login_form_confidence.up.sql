@@ -0,0 +1 @@
+ALTER TABLE crawls ADD COLUMN login_form_confidence DOUBLE PRECISION DEFAULT 0;
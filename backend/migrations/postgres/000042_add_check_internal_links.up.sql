@@ -0,0 +1 @@
+ALTER TABLE urls ADD COLUMN check_internal_links BOOLEAN DEFAULT FALSE;
@@ -0,0 +1,2 @@
+ALTER TABLE crawls DROP COLUMN last_modified;
+ALTER TABLE crawls DROP COLUMN etag;
@@ -0,0 +1,2 @@
+ALTER TABLE crawls ADD COLUMN etag VARCHAR(255) DEFAULT '';
+ALTER TABLE crawls ADD COLUMN last_modified VARCHAR(64) DEFAULT '';
@@ -0,0 +1,9 @@
+CREATE TABLE crawl_events (
+    id BIGSERIAL PRIMARY KEY,
+    crawl_id BIGINT NOT NULL REFERENCES crawls(id) ON DELETE CASCADE,
+    type VARCHAR(32) NOT NULL,
+    message TEXT,
+    elapsed_ms BIGINT DEFAULT 0,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_crawl_events_crawl_id ON crawl_events (crawl_id);
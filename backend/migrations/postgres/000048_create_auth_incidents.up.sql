@@ -0,0 +1,12 @@
+CREATE TABLE auth_incidents (
+    id BIGSERIAL PRIMARY KEY,
+    type VARCHAR(50) NOT NULL,
+    ip_address VARCHAR(45) NOT NULL,
+    path VARCHAR(255),
+    failures INTEGER NOT NULL DEFAULT 0,
+    blocked_until TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_auth_incidents_type ON auth_incidents (type);
+CREATE INDEX idx_auth_incidents_ip_address ON auth_incidents (ip_address);
+CREATE INDEX idx_auth_incidents_created_at ON auth_incidents (created_at);
@@ -0,0 +1,17 @@
+CREATE TABLE api_tokens (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    organization_id BIGINT NOT NULL DEFAULT 0,
+    name VARCHAR(100) NOT NULL,
+    scope VARCHAR(20) NOT NULL,
+    prefix VARCHAR(16) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL,
+    expires_at TIMESTAMPTZ,
+    last_used_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX idx_api_tokens_token_hash ON api_tokens (token_hash);
+CREATE INDEX idx_api_tokens_user_id ON api_tokens (user_id);
+CREATE INDEX idx_api_tokens_organization_id ON api_tokens (organization_id);
+CREATE INDEX idx_api_tokens_revoked_at ON api_tokens (revoked_at);
@@ -0,0 +1,26 @@
+CREATE TABLE sitemaps (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    organization_id BIGINT NOT NULL DEFAULT 0,
+    url VARCHAR(2048) NOT NULL,
+    sync_interval_minutes INTEGER NOT NULL DEFAULT 0,
+    next_sync_at TIMESTAMPTZ,
+    last_synced_at TIMESTAMPTZ,
+    last_error VARCHAR(255),
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_sitemaps_user_id ON sitemaps (user_id);
+CREATE INDEX idx_sitemaps_organization_id ON sitemaps (organization_id);
+CREATE INDEX idx_sitemaps_next_sync_at ON sitemaps (next_sync_at);
+
+CREATE TABLE sitemap_entries (
+    id BIGSERIAL PRIMARY KEY,
+    sitemap_id BIGINT NOT NULL REFERENCES sitemaps(id) ON DELETE CASCADE,
+    url_id BIGINT NOT NULL REFERENCES urls(id) ON DELETE CASCADE,
+    loc VARCHAR(2048) NOT NULL,
+    removed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX idx_sitemap_entries_sitemap_url ON sitemap_entries (sitemap_id, url_id);
+CREATE INDEX idx_sitemap_entries_url_id ON sitemap_entries (url_id);
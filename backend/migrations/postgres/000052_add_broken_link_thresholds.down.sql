@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS broken_link_thresholds;
+DROP INDEX IF EXISTS idx_urls_needs_attention;
+ALTER TABLE urls DROP COLUMN needs_attention;
@@ -0,0 +1,29 @@
+CREATE TABLE google_connections (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    refresh_token TEXT NOT NULL,
+    access_token TEXT,
+    expires_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX idx_google_connections_user_id ON google_connections (user_id);
+
+CREATE TABLE sheet_exports (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    organization_id BIGINT NOT NULL DEFAULT 0,
+    kind VARCHAR(20) NOT NULL,
+    spreadsheet_id VARCHAR(255) NOT NULL,
+    spreadsheet_url TEXT,
+    interval_minutes INTEGER NOT NULL DEFAULT 0,
+    next_run_at TIMESTAMPTZ,
+    last_run_at TIMESTAMPTZ,
+    last_rows INTEGER NOT NULL DEFAULT 0,
+    last_error VARCHAR(255),
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_sheet_exports_user_id ON sheet_exports (user_id);
+CREATE INDEX idx_sheet_exports_organization_id ON sheet_exports (organization_id);
+CREATE INDEX idx_sheet_exports_next_run_at ON sheet_exports (next_run_at);
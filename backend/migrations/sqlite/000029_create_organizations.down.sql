@@ -0,0 +1,31 @@
+CREATE TABLE urls_old (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    url VARCHAR(2048) NOT NULL UNIQUE,
+    title VARCHAR(512) DEFAULT '',
+    html_version VARCHAR(50) DEFAULT '',
+    status VARCHAR(20) DEFAULT 'pending',
+    has_login_form BOOLEAN DEFAULT FALSE,
+    login_form_override BOOLEAN NULL,
+    max_depth INT DEFAULT 0,
+    max_pages INT DEFAULT 0,
+    include_patterns TEXT,
+    exclude_patterns TEXT,
+    allow_subdomains BOOLEAN DEFAULT FALSE,
+    strip_query BOOLEAN DEFAULT FALSE,
+    max_query_params INT DEFAULT 0,
+    user_id BIGINT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    deleted_at DATETIME NULL
+);
+INSERT INTO urls_old (id, url, title, html_version, status, has_login_form, login_form_override, max_depth, max_pages, include_patterns, exclude_patterns, allow_subdomains, strip_query, max_query_params, user_id, created_at, updated_at, deleted_at)
+    SELECT id, url, title, html_version, status, has_login_form, login_form_override, max_depth, max_pages, include_patterns, exclude_patterns, allow_subdomains, strip_query, max_query_params, user_id, created_at, updated_at, deleted_at FROM urls WHERE organization_id = 0;
+DROP TABLE urls;
+ALTER TABLE urls_old RENAME TO urls;
+CREATE INDEX idx_urls_status ON urls (status);
+CREATE INDEX idx_urls_created_at ON urls (created_at);
+CREATE INDEX idx_urls_deleted_at ON urls (deleted_at);
+CREATE INDEX idx_urls_user_id ON urls (user_id);
+
+DROP TABLE IF EXISTS memberships;
+DROP TABLE IF EXISTS organizations;
@@ -0,0 +1,51 @@
+CREATE TABLE organizations (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(191) NOT NULL,
+    created_by BIGINT NOT NULL,
+    created_at DATETIME NULL,
+    updated_at DATETIME NULL
+);
+
+CREATE TABLE memberships (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    organization_id BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL,
+    created_at DATETIME NULL,
+    updated_at DATETIME NULL
+);
+CREATE UNIQUE INDEX idx_membership ON memberships (organization_id, user_id);
+CREATE INDEX idx_memberships_user_id ON memberships (user_id);
+
+-- URLs are unique within an organization; 0 holds the URLs outside any
+-- organization. SQLite can't drop the inline UNIQUE, so the table is rebuilt.
+CREATE TABLE urls_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    url VARCHAR(2048) NOT NULL,
+    title VARCHAR(512) DEFAULT '',
+    html_version VARCHAR(50) DEFAULT '',
+    status VARCHAR(20) DEFAULT 'pending',
+    has_login_form BOOLEAN DEFAULT FALSE,
+    login_form_override BOOLEAN NULL,
+    max_depth INT DEFAULT 0,
+    max_pages INT DEFAULT 0,
+    include_patterns TEXT,
+    exclude_patterns TEXT,
+    allow_subdomains BOOLEAN DEFAULT FALSE,
+    strip_query BOOLEAN DEFAULT FALSE,
+    max_query_params INT DEFAULT 0,
+    user_id BIGINT NULL,
+    organization_id BIGINT NOT NULL DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    deleted_at DATETIME NULL
+);
+INSERT INTO urls_new (id, url, title, html_version, status, has_login_form, login_form_override, max_depth, max_pages, include_patterns, exclude_patterns, allow_subdomains, strip_query, max_query_params, user_id, created_at, updated_at, deleted_at)
+    SELECT id, url, title, html_version, status, has_login_form, login_form_override, max_depth, max_pages, include_patterns, exclude_patterns, allow_subdomains, strip_query, max_query_params, user_id, created_at, updated_at, deleted_at FROM urls;
+DROP TABLE urls;
+ALTER TABLE urls_new RENAME TO urls;
+CREATE INDEX idx_urls_status ON urls (status);
+CREATE INDEX idx_urls_created_at ON urls (created_at);
+CREATE INDEX idx_urls_deleted_at ON urls (deleted_at);
+CREATE INDEX idx_urls_user_id ON urls (user_id);
+CREATE UNIQUE INDEX idx_urls_org_url ON urls (organization_id, url);
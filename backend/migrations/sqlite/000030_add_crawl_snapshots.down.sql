@@ -0,0 +1,2 @@
+ALTER TABLE crawls DROP COLUMN snapshot_key;
+ALTER TABLE crawls DROP COLUMN reprocessed_at;
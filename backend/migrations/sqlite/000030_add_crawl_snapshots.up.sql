@@ -0,0 +1,2 @@
+ALTER TABLE crawls ADD COLUMN snapshot_key VARCHAR(255) DEFAULT '';
+ALTER TABLE crawls ADD COLUMN reprocessed_at DATETIME NULL;
@@ -0,0 +1,12 @@
+CREATE TABLE extraction_rules (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    url_id BIGINT NOT NULL REFERENCES urls(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    type VARCHAR(10) NOT NULL,
+    selector TEXT NOT NULL,
+    attribute VARCHAR(100) DEFAULT '',
+    multiple BOOLEAN DEFAULT FALSE,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX idx_extraction_rule_name ON extraction_rules (url_id, name);
@@ -0,0 +1,2 @@
+ALTER TABLE crawls DROP COLUMN keyword_checks;
+ALTER TABLE urls DROP COLUMN keywords;
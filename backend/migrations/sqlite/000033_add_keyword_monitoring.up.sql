@@ -0,0 +1,2 @@
+ALTER TABLE urls ADD COLUMN keywords TEXT;
+ALTER TABLE crawls ADD COLUMN keyword_checks TEXT;
@@ -0,0 +1,26 @@
+CREATE TABLE monitors (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    url_id BIGINT NOT NULL REFERENCES urls(id) ON DELETE CASCADE,
+    interval_minutes INT NOT NULL,
+    enabled BOOLEAN DEFAULT TRUE,
+    next_check_at DATETIME NULL,
+    last_check_at DATETIME NULL,
+    last_up BOOLEAN NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX idx_monitors_url_id ON monitors (url_id);
+CREATE INDEX idx_monitors_next_check_at ON monitors (next_check_at);
+
+CREATE TABLE monitor_checks (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    url_id BIGINT NOT NULL REFERENCES urls(id) ON DELETE CASCADE,
+    checked_at DATETIME NOT NULL,
+    up BOOLEAN NOT NULL DEFAULT FALSE,
+    status_code INT DEFAULT 0,
+    response_ms BIGINT DEFAULT 0,
+    tls_valid BOOLEAN NULL,
+    tls_expires_at DATETIME NULL,
+    error VARCHAR(255) DEFAULT ''
+);
+CREATE INDEX idx_monitor_checks_url_checked ON monitor_checks (url_id, checked_at);
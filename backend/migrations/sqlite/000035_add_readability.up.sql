@@ -0,0 +1,2 @@
+ALTER TABLE crawls ADD COLUMN word_count INT DEFAULT 0;
+ALTER TABLE crawls ADD COLUMN readability_score REAL NULL;
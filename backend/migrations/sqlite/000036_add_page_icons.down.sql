@@ -0,0 +1 @@
+ALTER TABLE page_meta DROP COLUMN icons;
@@ -0,0 +1 @@
+ALTER TABLE crawls ADD COLUMN login_form_confidence REAL DEFAULT 0;
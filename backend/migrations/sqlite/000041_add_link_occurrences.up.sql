@@ -0,0 +1 @@
+ALTER TABLE links ADD COLUMN occurrences INT DEFAULT 1;
@@ -0,0 +1,6 @@
+ALTER TABLE pages DROP COLUMN connection_reused;
+ALTER TABLE pages DROP COLUMN keep_alive;
+ALTER TABLE pages DROP COLUMN http3_advertised;
+ALTER TABLE crawls DROP COLUMN connection_reused;
+ALTER TABLE crawls DROP COLUMN keep_alive;
+ALTER TABLE crawls DROP COLUMN http3_advertised;
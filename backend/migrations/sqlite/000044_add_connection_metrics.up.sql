@@ -0,0 +1,6 @@
+ALTER TABLE crawls ADD COLUMN http3_advertised BOOLEAN DEFAULT FALSE;
+ALTER TABLE crawls ADD COLUMN keep_alive BOOLEAN DEFAULT FALSE;
+ALTER TABLE crawls ADD COLUMN connection_reused BOOLEAN DEFAULT FALSE;
+ALTER TABLE pages ADD COLUMN http3_advertised BOOLEAN DEFAULT FALSE;
+ALTER TABLE pages ADD COLUMN keep_alive BOOLEAN DEFAULT FALSE;
+ALTER TABLE pages ADD COLUMN connection_reused BOOLEAN DEFAULT FALSE;
@@ -0,0 +1,7 @@
+CREATE TABLE feature_flags (
+    name VARCHAR(64) NOT NULL PRIMARY KEY,
+    enabled BOOLEAN DEFAULT FALSE,
+    user_ids TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
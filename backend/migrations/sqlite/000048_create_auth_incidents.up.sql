@@ -0,0 +1,12 @@
+CREATE TABLE auth_incidents (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    type VARCHAR(50) NOT NULL,
+    ip_address VARCHAR(45) NOT NULL,
+    path VARCHAR(255),
+    failures INTEGER NOT NULL DEFAULT 0,
+    blocked_until DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_auth_incidents_type ON auth_incidents (type);
+CREATE INDEX idx_auth_incidents_ip_address ON auth_incidents (ip_address);
+CREATE INDEX idx_auth_incidents_created_at ON auth_incidents (created_at);
@@ -0,0 +1,13 @@
+CREATE TABLE environment_pairs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    organization_id BIGINT NOT NULL DEFAULT 0,
+    name VARCHAR(100) NOT NULL,
+    baseline_url_id BIGINT NOT NULL REFERENCES urls(id) ON DELETE CASCADE,
+    candidate_url_id BIGINT NOT NULL REFERENCES urls(id) ON DELETE CASCADE,
+    created_by BIGINT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_environment_pairs_organization_id ON environment_pairs (organization_id);
+CREATE INDEX idx_environment_pairs_baseline_url_id ON environment_pairs (baseline_url_id);
+CREATE INDEX idx_environment_pairs_candidate_url_id ON environment_pairs (candidate_url_id);
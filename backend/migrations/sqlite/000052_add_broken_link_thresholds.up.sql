@@ -0,0 +1,14 @@
+ALTER TABLE urls ADD COLUMN needs_attention BOOLEAN DEFAULT FALSE;
+CREATE INDEX idx_urls_needs_attention ON urls (needs_attention);
+
+CREATE TABLE broken_link_thresholds (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    organization_id BIGINT NOT NULL DEFAULT 0,
+    user_id BIGINT NOT NULL DEFAULT 0,
+    url_id BIGINT NOT NULL DEFAULT 0,
+    max_broken_links INTEGER,
+    max_broken_percent REAL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX idx_broken_link_thresholds_scope ON broken_link_thresholds (organization_id, user_id, url_id);
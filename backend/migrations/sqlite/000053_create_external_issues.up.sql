@@ -0,0 +1,13 @@
+CREATE TABLE external_issues (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    url_id BIGINT NOT NULL REFERENCES urls(id) ON DELETE CASCADE,
+    finding_type VARCHAR(50) NOT NULL,
+    finding_key VARCHAR(768) NOT NULL,
+    finding_key_hash CHAR(64) NOT NULL,
+    tracker VARCHAR(20) NOT NULL,
+    issue_key VARCHAR(255) NOT NULL,
+    issue_url TEXT NOT NULL,
+    created_by BIGINT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX idx_external_issue ON external_issues (url_id, finding_type, finding_key_hash);
@@ -7,21 +7,28 @@ export interface URL {
   url: string
   title: string
   html_version: string
-  status: 'pending' | 'running' | 'completed' | 'error'
+  status: 'pending' | 'running' | 'completed' | 'skipped' | 'error'
   has_login_form: boolean
   created_at: string
   updated_at: string
   crawls?: Crawl[]
   links?: Link[]
+  // Set in URL lists, which carry link counts instead of crawls and links
+  internal_links?: number
+  external_links?: number
+  broken_links?: number
 }
 
 export interface Crawl {
   id: number
   url_id: number
-  status: 'queued' | 'running' | 'completed' | 'error'
+  status: 'queued' | 'running' | 'completed' | 'unchanged' | 'skipped' | 'error'
   started_at?: string
   completed_at?: string
+  reprocessed_at?: string
   error_message: string
+  skip_reason?: string
+  attempts?: number
   internal_links: number
   external_links: number
   broken_links: number
@@ -36,7 +43,7 @@ export interface Link {
   crawl_id: number
   link_url: string
   link_text: string
-  link_type: 'internal' | 'external'
+  link_type: 'internal' | 'external' | 'mailto' | 'tel' | 'anchor' | 'other'
   status_code: number
   is_accessible: boolean
   created_at: string
@@ -67,7 +74,9 @@ export interface PaginationResponse<T> {
   pagination: {
     total: number
     limit: number
-    offset: number
+    // offset is set for offset pagination, next_cursor for cursor pagination
+    offset?: number
+    next_cursor?: string
   }
 }
 
@@ -120,6 +129,8 @@ export const urlApi = {
   getUrls: async (params?: {
     limit?: number
     offset?: number
+    // Pass a cursor (empty for the first page) to use cursor pagination
+    cursor?: string
     search?: string
     status?: string
     sortBy?: string
@@ -129,6 +140,7 @@ export const urlApi = {
     
     if (params?.limit) searchParams.append('limit', params.limit.toString())
     if (params?.offset) searchParams.append('offset', params.offset.toString())
+    if (params?.cursor !== undefined) searchParams.append('cursor', params.cursor)
     if (params?.search) searchParams.append('search', params.search)
     if (params?.status) searchParams.append('status', params.status)
     if (params?.sortBy) searchParams.append('sortBy', params.sortBy)
@@ -204,12 +216,14 @@ export const linksApi = {
     type?: 'all' | 'internal' | 'external' | 'broken' | 'accessible'
     limit?: number
     offset?: number
+    cursor?: string
   }): Promise<PaginationResponse<Link>> => {
     const searchParams = new URLSearchParams()
     
     if (params?.type) searchParams.append('type', params.type)
     if (params?.limit) searchParams.append('limit', params.limit.toString())
     if (params?.offset) searchParams.append('offset', params.offset.toString())
+    if (params?.cursor !== undefined) searchParams.append('cursor', params.cursor)
 
     const query = searchParams.toString()
     return apiCall<PaginationResponse<Link>>(`/urls/${urlId}/links${query ? `?${query}` : ''}`)